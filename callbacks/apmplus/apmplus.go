@@ -181,6 +181,16 @@ func NewApmplusHandler(cfg *Config) (handler callbacks.Handler, shutdown func(ct
 		return nil, p.Shutdown, err
 	}
 
+	streamingOutputTokensPerSecond, err := meter.Float64Histogram(
+		"gen_ai.chat_completions.streaming_output_tokens_per_second",
+		metric.WithDescription("Output tokens per second between first token and completion in streaming chat completions"),
+		metric.WithUnit("token/s"),
+		metric.WithExplicitBucketBoundaries(1, 2, 5, 10, 20, 30, 50, 75, 100, 150, 200, 300, 500, 1000),
+	)
+	if err != nil {
+		return nil, p.Shutdown, err
+	}
+
 	return &apmplusHandler{
 		otelProvider: p,
 		serviceName:  cfg.ServiceName,
@@ -196,6 +206,8 @@ func NewApmplusHandler(cfg *Config) (handler callbacks.Handler, shutdown func(ct
 		streamingTimeToFirstToken:   streamingTimeToFirstToken,
 		streamingTimeToGenerate:     streamingTimeToGenerate,
 		streamingTimePerOutputToken: streamingTimePerOutputToken,
+
+		streamingOutputTokensPerSecond: streamingOutputTokensPerSecond,
 	}, p.Shutdown, nil
 }
 
@@ -214,6 +226,8 @@ type apmplusHandler struct {
 	streamingTimeToFirstToken   metric.Float64Histogram
 	streamingTimeToGenerate     metric.Float64Histogram
 	streamingTimePerOutputToken metric.Float64Histogram
+
+	streamingOutputTokensPerSecond metric.Float64Histogram
 }
 
 type requestInfo struct {
@@ -348,7 +362,8 @@ func (a *apmplusHandler) OnEnd(ctx context.Context, info *callbacks.RunInfo, out
 	case components.ComponentOfChatModel:
 		fallthrough
 	default:
-		usage, outMessages, _, config, err := extractModelOutput(convModelCallbackOutput([]callbacks.CallbackOutput{output}))
+		outs := convModelCallbackOutput([]callbacks.CallbackOutput{output})
+		usage, outMessages, _, config, err := extractModelOutput(outs)
 		if err == nil {
 			responseModel := ""
 			responseFinishReason := ""
@@ -377,9 +392,8 @@ func (a *apmplusHandler) OnEnd(ctx context.Context, info *callbacks.RunInfo, out
 				}
 			}
 
-			if config != nil {
-				span.SetAttributes(attribute.String("gen_ai.response.model", config.Model))
-				responseModel = config.Model
+			if responseModel = responseModelName(outs, config, state.requestInfo); responseModel != "" {
+				span.SetAttributes(attribute.String("gen_ai.response.model", responseModel))
 			}
 
 			if usage != nil {
@@ -589,7 +603,8 @@ func (a *apmplusHandler) OnEndWithStreamOutput(ctx context.Context, info *callba
 			span.End(trace.WithTimestamp(time.Now()))
 		}()
 		var outs []callbacks.CallbackOutput
-		timeOfFirstToken := time.Now()
+		// the first token is timed at the first chunk carrying generated tokens, not at the metadata chunks before it
+		var timeOfFirstToken time.Time
 		for {
 			chunk, err := output.Recv()
 			if err == io.EOF {
@@ -598,12 +613,20 @@ func (a *apmplusHandler) OnEndWithStreamOutput(ctx context.Context, info *callba
 			if err != nil {
 				log.Printf("read stream output error: %v, runinfo: %+v", err, info)
 			}
+			if timeOfFirstToken.IsZero() && hasGeneratedTokens(model.ConvCallbackOutput(chunk)) {
+				timeOfFirstToken = time.Now()
+			}
 			outs = append(outs, chunk)
 		}
 		endTime := time.Now()
+		// the request model is set while the stream input is read, so wait for it before reading the model
+		if stopCh, ok := ctx.Value(traceStreamInputAsyncKey{}).(streamInputAsyncVal); ok {
+			<-stopCh
+		}
 		contentReady := false
+		modelOuts := convModelCallbackOutput(outs)
 		// both work for ChatModel or not
-		usage, outMessages, _, config, err := extractModelOutput(convModelCallbackOutput(outs))
+		usage, outMessages, _, config, err := extractModelOutput(modelOuts)
 		if err == nil {
 			if state.isRootNode {
 				outMessagesStr, err := sonic.MarshalString(outMessages)
@@ -631,9 +654,8 @@ func (a *apmplusHandler) OnEndWithStreamOutput(ctx context.Context, info *callba
 				}
 			}
 
-			if config != nil {
-				span.SetAttributes(attribute.String("gen_ai.response.model", config.Model))
-				responseModel = config.Model
+			if responseModel = responseModelName(modelOuts, config, state.requestInfo); responseModel != "" {
+				span.SetAttributes(attribute.String("gen_ai.response.model", responseModel))
 			}
 
 			if usage != nil {
@@ -665,29 +687,16 @@ func (a *apmplusHandler) OnEndWithStreamOutput(ctx context.Context, info *callba
 			}
 			if usage != nil {
 				a.AddTokenUsage(ctx, usage, responseModel, true)
-				tpot := endTime.Sub(timeOfFirstToken).Seconds() / float64(usage.CompletionTokens)
-				a.streamingTimePerOutputToken.Record(ctx, tpot, metric.WithAttributes(
-					attribute.String("gen_ai_response_model", responseModel),
-					attribute.Bool("stream", true),
-				))
-				span.SetAttributes(attribute.Float64("gen_ai.chat_completions.streaming_time_per_output_token", tpot))
 			}
 			a.chatDurationHistogram.Record(ctx, endTime.Sub(startTime).Seconds(), metric.WithAttributes(
 				attribute.String("gen_ai_response_model", responseModel),
 				attribute.Bool("stream", true),
 			))
 
-			ttft := timeOfFirstToken.Sub(startTime).Seconds()
-			a.streamingTimeToFirstToken.Record(ctx, ttft, metric.WithAttributes(
-				attribute.String("gen_ai_response_model", responseModel),
-				attribute.Bool("stream", true),
-			))
-			span.SetAttributes(attribute.Float64("gen_ai.chat_completions.streaming_time_to_first_token", ttft))
-
-			a.streamingTimeToGenerate.Record(ctx, endTime.Sub(timeOfFirstToken).Seconds(), metric.WithAttributes(
-				attribute.String("gen_ai_response_model", responseModel),
-				attribute.Bool("stream", true),
-			))
+			// a stream without generated tokens, e.g. failed before the first token, has no latency of the tokens
+			if !timeOfFirstToken.IsZero() {
+				a.recordStreamingLatency(ctx, span, responseModel, usage, startTime, timeOfFirstToken, endTime)
+			}
 		}
 
 	}()
@@ -695,6 +704,35 @@ func (a *apmplusHandler) OnEndWithStreamOutput(ctx context.Context, info *callba
 	return ctx
 }
 
+// recordStreamingLatency records the time to first token, the generation time,
+// and the time per output token and output tokens per second if the usage is reported.
+func (a *apmplusHandler) recordStreamingLatency(ctx context.Context, span trace.Span, responseModel string, usage *model.TokenUsage,
+	startTime, timeOfFirstToken, endTime time.Time) {
+	attrs := metric.WithAttributes(
+		attribute.String("gen_ai_response_model", responseModel),
+		attribute.Bool("stream", true),
+	)
+
+	ttft := timeOfFirstToken.Sub(startTime).Seconds()
+	a.streamingTimeToFirstToken.Record(ctx, ttft, attrs)
+	span.SetAttributes(attribute.Float64("gen_ai.chat_completions.streaming_time_to_first_token", ttft))
+
+	generation := endTime.Sub(timeOfFirstToken).Seconds()
+	a.streamingTimeToGenerate.Record(ctx, generation, attrs)
+
+	if usage == nil || usage.CompletionTokens <= 0 {
+		return
+	}
+	tpot := generation / float64(usage.CompletionTokens)
+	a.streamingTimePerOutputToken.Record(ctx, tpot, attrs)
+	span.SetAttributes(attribute.Float64("gen_ai.chat_completions.streaming_time_per_output_token", tpot))
+	if generation > 0 {
+		tps := float64(usage.CompletionTokens) / generation
+		a.streamingOutputTokensPerSecond.Record(ctx, tps, attrs)
+		span.SetAttributes(attribute.Float64("gen_ai.chat_completions.streaming_output_tokens_per_second", tps))
+	}
+}
+
 func (a *apmplusHandler) AddTokenUsage(ctx context.Context, usage *model.TokenUsage, responseModel string, isStream bool) {
 	if usage != nil {
 		a.tokenUsage.Record(ctx, int64(usage.TotalTokens), metric.WithAttributes(
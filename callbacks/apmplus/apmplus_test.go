@@ -21,6 +21,7 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/bytedance/mockey"
 	"github.com/cloudwego/eino/callbacks"
@@ -28,6 +29,7 @@ import (
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestApmplusCallback(t *testing.T) {
@@ -171,4 +173,36 @@ func TestApmplusCallback(t *testing.T) {
 		}
 		cbh.OnEndWithStreamOutput(ctx2, &callbacks.RunInfo{Component: components.ComponentOfChatModel}, outsr)
 	})
+
+	mockey.PatchConvey("test generation stream without model name in extra", t, func() {
+		models := make(chan string, 1)
+		mockey.Mock((*apmplusHandler).recordStreamingLatency).To(func(a *apmplusHandler, ctx context.Context, span trace.Span,
+			responseModel string, usage *model.TokenUsage, startTime, timeOfFirstToken, endTime time.Time) {
+			models <- responseModel
+		}).Build()
+
+		ctx3 := cbh.OnStart(ctx, &callbacks.RunInfo{Component: components.ComponentOfChatModel}, &model.CallbackInput{
+			Messages: []*schema.Message{{Role: schema.User, Content: "user message"}},
+			Config:   &model.Config{Model: "deepseek-chat"},
+		})
+		outsr, outsw := schema.Pipe[callbacks.CallbackOutput](2)
+		outsw.Send(&model.CallbackOutput{
+			Message: &schema.Message{Role: schema.Assistant, Content: "assistant message"},
+		}, nil)
+		outsw.Send(&model.CallbackOutput{
+			Message:    &schema.Message{Role: schema.Assistant, ResponseMeta: &schema.ResponseMeta{FinishReason: "stop"}},
+			TokenUsage: &model.TokenUsage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3},
+		}, nil)
+		outsw.Close()
+		cbh.OnEndWithStreamOutput(ctx3, &callbacks.RunInfo{Component: components.ComponentOfChatModel}, outsr)
+
+		select {
+		case responseModel := <-models:
+			if responseModel != "deepseek-chat" {
+				t.Fatalf("expect deepseek-chat, but got %s", responseModel)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expect streaming latency recorded")
+		}
+	})
 }
@@ -26,6 +26,10 @@ import (
 	"github.com/cloudwego/eino/schema"
 )
 
+// callbackExtraModelName is the Extra key of the chat model callback output carrying the name of the model
+// serving the request, e.g. the model behind an ark endpoint ID.
+const callbackExtraModelName = "model_name"
+
 func getName(info *callbacks.RunInfo) string {
 	if len(info.Name) != 0 {
 		return info.Name
@@ -161,3 +165,35 @@ func convSchemaMessage(in []*schema.Message) []*model.CallbackInput {
 	}
 	return ret
 }
+
+// responseModelName returns the model name reported in the Extra of the outputs.
+// Most chat models do not report it, so it falls back to the model of config,
+// and then to the model of the callback input kept in requestInfo.
+func responseModelName(outs []*model.CallbackOutput, config *model.Config, requestInfo *requestInfo) string {
+	for _, out := range outs {
+		if out == nil {
+			continue
+		}
+		if name, ok := out.Extra[callbackExtraModelName].(string); ok && name != "" {
+			return name
+		}
+	}
+	if config != nil && config.Model != "" {
+		return config.Model
+	}
+	if requestInfo != nil {
+		return requestInfo.model
+	}
+	return ""
+}
+
+// hasGeneratedTokens reports whether the stream chunk carries generated tokens,
+// as opposed to chunks with metadata only, e.g. the usage or the response ID.
+func hasGeneratedTokens(out *model.CallbackOutput) bool {
+	if out == nil || out.Message == nil {
+		return false
+	}
+	msg := out.Message
+	return msg.Content != "" || msg.ReasoningContent != "" || len(msg.ToolCalls) > 0 ||
+		len(msg.AssistantGenMultiContent) > 0 || len(msg.MultiContent) > 0
+}
@@ -272,3 +272,38 @@ func Test_convSchemaMessage(t *testing.T) {
 		convey.So(actual, convey.ShouldResemble, expected)
 	})
 }
+
+func Test_responseModelName(t *testing.T) {
+	mockey.PatchConvey("Test responseModelName with model name in extra", t, func() {
+		outs := []*model.CallbackOutput{
+			nil,
+			{Extra: map[string]interface{}{callbackExtraModelName: ""}},
+			{Extra: map[string]interface{}{callbackExtraModelName: "doubao-seed-1-6"}},
+		}
+		convey.So(responseModelName(outs, &model.Config{Model: "ep-123"}, &requestInfo{model: "ep-123"}), convey.ShouldEqual, "doubao-seed-1-6")
+	})
+	mockey.PatchConvey("Test responseModelName falls back to config", t, func() {
+		outs := []*model.CallbackOutput{{Extra: map[string]interface{}{"key": "value"}}}
+		convey.So(responseModelName(outs, &model.Config{Model: "ep-123"}, &requestInfo{model: "ep-456"}), convey.ShouldEqual, "ep-123")
+		convey.So(responseModelName(outs, nil, nil), convey.ShouldEqual, "")
+	})
+	mockey.PatchConvey("Test responseModelName falls back to the request model", t, func() {
+		// e.g. deepseek sets neither the model name in the Extra nor the config of the outputs
+		outs := []*model.CallbackOutput{{Message: &schema.Message{Role: schema.Assistant, Content: "hello"}}}
+		convey.So(responseModelName(outs, nil, &requestInfo{model: "deepseek-chat"}), convey.ShouldEqual, "deepseek-chat")
+		convey.So(responseModelName(outs, &model.Config{}, &requestInfo{model: "deepseek-chat"}), convey.ShouldEqual, "deepseek-chat")
+	})
+}
+
+func Test_hasGeneratedTokens(t *testing.T) {
+	mockey.PatchConvey("Test hasGeneratedTokens", t, func() {
+		convey.So(hasGeneratedTokens(nil), convey.ShouldBeFalse)
+		convey.So(hasGeneratedTokens(&model.CallbackOutput{TokenUsage: &model.TokenUsage{}}), convey.ShouldBeFalse)
+		convey.So(hasGeneratedTokens(&model.CallbackOutput{Message: &schema.Message{Role: schema.Assistant}}), convey.ShouldBeFalse)
+		convey.So(hasGeneratedTokens(&model.CallbackOutput{Message: &schema.Message{Content: "a"}}), convey.ShouldBeTrue)
+		convey.So(hasGeneratedTokens(&model.CallbackOutput{Message: &schema.Message{ReasoningContent: "a"}}), convey.ShouldBeTrue)
+		convey.So(hasGeneratedTokens(&model.CallbackOutput{Message: &schema.Message{
+			ToolCalls: []schema.ToolCall{{Function: schema.FunctionCall{Name: "f"}}},
+		}}), convey.ShouldBeTrue)
+	})
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tokenbudget
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Handler returns the callback handler accumulating the token usage of the ChatModel calls
+// into the sessions of their contexts.
+// The usage of streams is charged once the stream output is fully received,
+// or up to the last usage received when the stream fails, so that failed streams do not bypass the budget.
+func (t *Tracker) Handler() callbacks.Handler {
+	return &handler{tracker: t}
+}
+
+type handler struct {
+	tracker *Tracker
+}
+
+func (h *handler) OnStart(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackInput) context.Context {
+	return ctx
+}
+
+func (h *handler) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	if !isChatModel(info) {
+		return ctx
+	}
+	if out := model.ConvCallbackOutput(output); out != nil {
+		h.tracker.charge(ctx, out.TokenUsage)
+	}
+	return ctx
+}
+
+func (h *handler) OnError(ctx context.Context, _ *callbacks.RunInfo, _ error) context.Context {
+	return ctx
+}
+
+func (h *handler) OnStartWithStreamInput(ctx context.Context, _ *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+	input.Close()
+	return ctx
+}
+
+func (h *handler) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+	if !isChatModel(info) {
+		output.Close()
+		return ctx
+	}
+
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				log.Printf("recover token budget panic: %v, runinfo: %+v, stack: %s", e, info, string(debug.Stack()))
+			}
+			output.Close()
+		}()
+
+		// the usage is either reported once or accumulated over the chunks, the last one is taken
+		var usage *model.TokenUsage
+		for {
+			chunk, err := output.Recv()
+			if err != nil {
+				// io.EOF or a failed stream, which is charged the usage received so far
+				break
+			}
+			if out := model.ConvCallbackOutput(chunk); out != nil && out.TokenUsage != nil {
+				usage = out.TokenUsage
+			}
+		}
+		h.tracker.charge(ctx, usage)
+	}()
+	return ctx
+}
+
+func isChatModel(info *callbacks.RunInfo) bool {
+	return info != nil && info.Component == components.ComponentOfChatModel
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tokenbudget
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestHandler(t *testing.T) {
+	convey.Convey("test Tracker.Handler", t, func() {
+		tracker, err := NewTracker(&Config{Budget: 100})
+		convey.So(err, convey.ShouldBeNil)
+		h := tracker.Handler()
+		ctx := WithSessionKey(context.Background(), "s1")
+		modelInfo := &callbacks.RunInfo{Component: components.ComponentOfChatModel}
+
+		convey.Convey("test OnEnd", func() {
+			h.OnEnd(ctx, modelInfo, &model.CallbackOutput{TokenUsage: &model.TokenUsage{TotalTokens: 30}})
+			h.OnEnd(ctx, modelInfo, &model.CallbackOutput{})
+			h.OnEnd(ctx, &callbacks.RunInfo{Component: components.ComponentOfRetriever},
+				&model.CallbackOutput{TokenUsage: &model.TokenUsage{TotalTokens: 30}})
+			h.OnEnd(ctx, nil, &model.CallbackOutput{TokenUsage: &model.TokenUsage{TotalTokens: 30}})
+			convey.So(tracker.Used("s1"), convey.ShouldEqual, 30)
+		})
+
+		convey.Convey("test OnEndWithStreamOutput", func() {
+			sr, sw := schema.Pipe[callbacks.CallbackOutput](3)
+			sw.Send(&model.CallbackOutput{Message: schema.AssistantMessage("a", nil)}, nil)
+			sw.Send(&model.CallbackOutput{TokenUsage: &model.TokenUsage{TotalTokens: 10}}, nil)
+			sw.Send(&model.CallbackOutput{TokenUsage: &model.TokenUsage{TotalTokens: 25}}, nil)
+			sw.Close()
+
+			h.OnEndWithStreamOutput(ctx, modelInfo, sr)
+			deadline := time.Now().Add(5 * time.Second)
+			for tracker.Used("s1") == 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			convey.So(tracker.Used("s1"), convey.ShouldEqual, 25)
+		})
+
+		convey.Convey("test OnEndWithStreamOutput with a failed stream", func() {
+			sr, sw := schema.Pipe[callbacks.CallbackOutput](3)
+			sw.Send(&model.CallbackOutput{TokenUsage: &model.TokenUsage{TotalTokens: 15}}, nil)
+			sw.Send(nil, errors.New("connection reset"))
+			sw.Close()
+
+			h.OnEndWithStreamOutput(ctx, modelInfo, sr)
+			deadline := time.Now().Add(5 * time.Second)
+			for tracker.Used("s1") == 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			convey.So(tracker.Used("s1"), convey.ShouldEqual, 15)
+		})
+	})
+}
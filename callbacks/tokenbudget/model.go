@@ -0,0 +1,80 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tokenbudget
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// WrapChatModel returns a model which fails with a *BudgetExceededError
+// instead of calling cm once the budget of the session of the context is used up.
+func WrapChatModel(cm model.BaseChatModel, t *Tracker) model.BaseChatModel {
+	return &budgetChatModel{cm: cm, tracker: t}
+}
+
+// WrapToolCallingChatModel is WrapChatModel for tool calling models, the models returned by WithTools are wrapped too.
+func WrapToolCallingChatModel(cm model.ToolCallingChatModel, t *Tracker) model.ToolCallingChatModel {
+	return &budgetToolCallingChatModel{budgetChatModel: budgetChatModel{cm: cm, tracker: t}, tcm: cm}
+}
+
+type budgetChatModel struct {
+	cm      model.BaseChatModel
+	tracker *Tracker
+}
+
+func (m *budgetChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if err := m.tracker.Check(ctx); err != nil {
+		return nil, err
+	}
+	return m.cm.Generate(ctx, input, opts...)
+}
+
+func (m *budgetChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if err := m.tracker.Check(ctx); err != nil {
+		return nil, err
+	}
+	return m.cm.Stream(ctx, input, opts...)
+}
+
+// GetType returns the type of the wrapped model, so the callbacks are reported as the wrapped model.
+func (m *budgetChatModel) GetType() string {
+	typ, _ := components.GetType(m.cm)
+	return typ
+}
+
+// IsCallbacksEnabled reports whether the wrapped model reports callbacks itself,
+// so the usage is charged only once.
+func (m *budgetChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(m.cm)
+}
+
+type budgetToolCallingChatModel struct {
+	budgetChatModel
+	tcm model.ToolCallingChatModel
+}
+
+func (m *budgetToolCallingChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	tcm, err := m.tcm.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return WrapToolCallingChatModel(tcm, m.tracker), nil
+}
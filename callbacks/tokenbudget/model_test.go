@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tokenbudget
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+type mockChatModel struct {
+	calls int
+	tools []*schema.ToolInfo
+}
+
+func (m *mockChatModel) Generate(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	m.calls++
+	return schema.AssistantMessage("ok", nil), nil
+}
+
+func (m *mockChatModel) Stream(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	m.calls++
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("ok", nil)}), nil
+}
+
+func (m *mockChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &mockChatModel{tools: tools}, nil
+}
+
+func (m *mockChatModel) GetType() string {
+	return "Mock"
+}
+
+func (m *mockChatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+func TestWrapChatModel(t *testing.T) {
+	convey.Convey("test WrapChatModel", t, func() {
+		tracker, err := NewTracker(&Config{Budget: 100})
+		convey.So(err, convey.ShouldBeNil)
+		ctx := WithSessionKey(context.Background(), "s1")
+		inner := &mockChatModel{}
+		cm := WrapChatModel(inner, tracker)
+
+		_, err = cm.Generate(ctx, nil)
+		convey.So(err, convey.ShouldBeNil)
+		_, err = cm.Stream(ctx, nil)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(inner.calls, convey.ShouldEqual, 2)
+
+		tracker.Add("s1", 100)
+		_, err = cm.Generate(ctx, nil)
+		convey.So(errors.Is(err, ErrBudgetExceeded), convey.ShouldBeTrue)
+		_, err = cm.Stream(ctx, nil)
+		convey.So(errors.Is(err, ErrBudgetExceeded), convey.ShouldBeTrue)
+		convey.So(inner.calls, convey.ShouldEqual, 2)
+
+		checker := cm.(interface {
+			GetType() string
+			IsCallbacksEnabled() bool
+		})
+		convey.So(checker.GetType(), convey.ShouldEqual, "Mock")
+		convey.So(checker.IsCallbacksEnabled(), convey.ShouldBeTrue)
+	})
+}
+
+func TestWrapToolCallingChatModel(t *testing.T) {
+	convey.Convey("test WrapToolCallingChatModel", t, func() {
+		tracker, err := NewTracker(&Config{Budget: 100})
+		convey.So(err, convey.ShouldBeNil)
+		ctx := WithSessionKey(context.Background(), "s1")
+		cm := WrapToolCallingChatModel(&mockChatModel{}, tracker)
+
+		tools := []*schema.ToolInfo{{Name: "search"}}
+		withTools, err := cm.WithTools(tools)
+		convey.So(err, convey.ShouldBeNil)
+		wrapped, ok := withTools.(*budgetToolCallingChatModel)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(wrapped.tcm.(*mockChatModel).tools, convey.ShouldResemble, tools)
+
+		tracker.Add("s1", 100)
+		_, err = withTools.Generate(ctx, nil)
+		convey.So(errors.Is(err, ErrBudgetExceeded), convey.ShouldBeTrue)
+	})
+}
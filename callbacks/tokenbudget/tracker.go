@@ -0,0 +1,173 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tokenbudget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino/components/model"
+)
+
+// ErrBudgetExceeded is matched by errors.Is for every *BudgetExceededError.
+var ErrBudgetExceeded = errors.New("token budget exceeded")
+
+// BudgetExceededError is returned by the wrapped models once the token budget of the session is used up.
+type BudgetExceededError struct {
+	// SessionKey is the key of the session, see WithSessionKey.
+	SessionKey string
+	// Used is the number of tokens used by the session.
+	Used int64
+	// Budget is the token budget of the session.
+	Budget int64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("token budget exceeded for session %q: used %d of %d tokens", e.SessionKey, e.Used, e.Budget)
+}
+
+// Is reports whether target is ErrBudgetExceeded.
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+type sessionKeyCtxKey struct{}
+
+// WithSessionKey returns a context whose model calls are accounted to the session key,
+// e.g. a conversation ID, or a request ID to budget a single request.
+// Model calls without a session key are neither accounted nor limited.
+func WithSessionKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, sessionKeyCtxKey{}, key)
+}
+
+// GetSessionKey returns the session key set by WithSessionKey.
+func GetSessionKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(sessionKeyCtxKey{}).(string)
+	return key, ok
+}
+
+// Config contains configuration for the token budget Tracker.
+type Config struct {
+	// Budget is the max number of tokens of a session.
+	// Required unless BudgetOf is set.
+	Budget int64
+
+	// BudgetOf returns the budget of the session, overriding Budget, e.g. per user tier.
+	// A budget <= 0 disables the limit of the session.
+	// Optional.
+	BudgetOf func(sessionKey string) int64
+
+	// Counter returns the number of tokens charged for a model call.
+	// Optional. Default: TotalTokens, or PromptTokens + CompletionTokens if TotalTokens is not reported
+	Counter func(usage *model.TokenUsage) int64
+}
+
+// Tracker accumulates the token usage of the model calls per session
+// and aborts the model calls of the sessions whose budget is used up.
+type Tracker struct {
+	config *Config
+
+	mu   sync.Mutex
+	used map[string]int64
+}
+
+// NewTracker creates a new token budget tracker with the provided configuration.
+// Register Tracker.Handler to accumulate the usage, and wrap the models with WrapChatModel
+// or WrapToolCallingChatModel to enforce the budget.
+func NewTracker(conf *Config) (*Tracker, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("[NewTracker] config is required")
+	}
+	if conf.Budget <= 0 && conf.BudgetOf == nil {
+		return nil, fmt.Errorf("[NewTracker] budget must be positive")
+	}
+	// the default is filled in on a copy, so that the caller's config is not modified
+	config := *conf
+	if config.Counter == nil {
+		config.Counter = defaultCounter
+	}
+	return &Tracker{
+		config: &config,
+		used:   make(map[string]int64),
+	}, nil
+}
+
+func defaultCounter(usage *model.TokenUsage) int64 {
+	if usage.TotalTokens > 0 {
+		return int64(usage.TotalTokens)
+	}
+	return int64(usage.PromptTokens + usage.CompletionTokens)
+}
+
+// Add charges tokens to the session.
+func (t *Tracker) Add(sessionKey string, tokens int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.used[sessionKey] += tokens
+}
+
+// Used returns the number of tokens used by the session.
+func (t *Tracker) Used(sessionKey string) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.used[sessionKey]
+}
+
+// Reset forgets the usage of the session, e.g. when the session ends.
+// The usage of the sessions is kept in memory until they are reset.
+func (t *Tracker) Reset(sessionKey string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.used, sessionKey)
+}
+
+// Check returns a *BudgetExceededError if the budget of the session of ctx is used up.
+func (t *Tracker) Check(ctx context.Context) error {
+	key, ok := GetSessionKey(ctx)
+	if !ok {
+		return nil
+	}
+	budget := t.budgetOf(key)
+	if budget <= 0 {
+		return nil
+	}
+	if used := t.Used(key); used >= budget {
+		return &BudgetExceededError{SessionKey: key, Used: used, Budget: budget}
+	}
+	return nil
+}
+
+func (t *Tracker) budgetOf(sessionKey string) int64 {
+	if t.config.BudgetOf != nil {
+		return t.config.BudgetOf(sessionKey)
+	}
+	return t.config.Budget
+}
+
+// charge charges the usage of a model call to the session of ctx.
+func (t *Tracker) charge(ctx context.Context, usage *model.TokenUsage) {
+	if usage == nil {
+		return
+	}
+	key, ok := GetSessionKey(ctx)
+	if !ok {
+		return
+	}
+	t.Add(key, t.config.Counter(usage))
+}
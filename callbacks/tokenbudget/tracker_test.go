@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tokenbudget
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewTracker(t *testing.T) {
+	convey.Convey("test NewTracker", t, func() {
+		_, err := NewTracker(nil)
+		convey.So(err, convey.ShouldNotBeNil)
+
+		_, err = NewTracker(&Config{})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "budget must be positive")
+
+		conf := &Config{Budget: 100}
+		tracker, err := NewTracker(conf)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(tracker.config.Counter(&model.TokenUsage{TotalTokens: 10, PromptTokens: 1}), convey.ShouldEqual, 10)
+		convey.So(tracker.config.Counter(&model.TokenUsage{PromptTokens: 3, CompletionTokens: 4}), convey.ShouldEqual, 7)
+		// the caller's config is not modified
+		convey.So(conf.Counter, convey.ShouldBeNil)
+
+		_, err = NewTracker(&Config{BudgetOf: func(string) int64 { return 0 }})
+		convey.So(err, convey.ShouldBeNil)
+	})
+}
+
+func TestTracker_Check(t *testing.T) {
+	convey.Convey("test Tracker.Check", t, func() {
+		tracker, err := NewTracker(&Config{Budget: 100})
+		convey.So(err, convey.ShouldBeNil)
+		ctx := WithSessionKey(context.Background(), "s1")
+
+		convey.Convey("test without session key", func() {
+			tracker.Add("", 1000)
+			convey.So(tracker.Check(context.Background()), convey.ShouldBeNil)
+		})
+
+		convey.Convey("test within budget", func() {
+			tracker.charge(ctx, &model.TokenUsage{TotalTokens: 99})
+			convey.So(tracker.Used("s1"), convey.ShouldEqual, 99)
+			convey.So(tracker.Check(ctx), convey.ShouldBeNil)
+		})
+
+		convey.Convey("test budget exceeded", func() {
+			tracker.charge(ctx, &model.TokenUsage{TotalTokens: 60})
+			tracker.charge(ctx, &model.TokenUsage{TotalTokens: 40})
+			tracker.charge(ctx, nil)
+			err := tracker.Check(ctx)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(errors.Is(err, ErrBudgetExceeded), convey.ShouldBeTrue)
+
+			var budgetErr *BudgetExceededError
+			convey.So(errors.As(err, &budgetErr), convey.ShouldBeTrue)
+			convey.So(*budgetErr, convey.ShouldResemble, BudgetExceededError{SessionKey: "s1", Used: 100, Budget: 100})
+
+			convey.So(tracker.Check(WithSessionKey(context.Background(), "s2")), convey.ShouldBeNil)
+
+			tracker.Reset("s1")
+			convey.So(tracker.Used("s1"), convey.ShouldEqual, 0)
+			convey.So(tracker.Check(ctx), convey.ShouldBeNil)
+		})
+
+		convey.Convey("test budget of session", func() {
+			tracker.config.BudgetOf = func(key string) int64 {
+				if key == "vip" {
+					return 0
+				}
+				return 10
+			}
+			tracker.Add("s1", 10)
+			tracker.Add("vip", 1000)
+			convey.So(tracker.Check(ctx), convey.ShouldNotBeNil)
+			convey.So(tracker.Check(WithSessionKey(context.Background(), "vip")), convey.ShouldBeNil)
+		})
+	})
+}
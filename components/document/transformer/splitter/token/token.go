@@ -0,0 +1,148 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package token
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+// IDGenerator generates new IDs for split chunks
+type IDGenerator func(ctx context.Context, originalID string, splitIndex int) string
+
+// defaultIDGenerator keeps the original ID
+func defaultIDGenerator(ctx context.Context, originalID string, _ int) string {
+	return originalID
+}
+
+type Config struct {
+	// Tokenizer is the tokenizer of the target model, e.g. NewTiktokenTokenizer(enc).
+	// Required.
+	Tokenizer Tokenizer
+	// ChunkSize is the max number of tokens of each chunk.
+	ChunkSize int
+	// OverlapSize is the number of tokens shared by adjacent chunks. Must be smaller than ChunkSize.
+	// Overlapping can mitigate loss of information when context is divided.
+	OverlapSize int
+	// IDGenerator is an optional function to generate new IDs for split chunks.
+	// If nil, the original document ID will be used for all splits.
+	IDGenerator IDGenerator
+}
+
+// NewSplitter creates a splitter which splits documents by token count.
+func NewSplitter(ctx context.Context, config *Config) (document.Transformer, error) {
+	if config.Tokenizer == nil {
+		return nil, fmt.Errorf("tokenizer is required")
+	}
+	if config.ChunkSize <= 0 {
+		return nil, fmt.Errorf("chunk size must be greater than zero")
+	}
+	if config.OverlapSize < 0 || config.OverlapSize >= config.ChunkSize {
+		return nil, fmt.Errorf("overlap must be greater than or equal to zero and less than chunk size")
+	}
+	idGenerator := config.IDGenerator
+	if idGenerator == nil {
+		idGenerator = defaultIDGenerator
+	}
+	return &splitter{
+		tokenizer:   config.Tokenizer,
+		chunkSize:   config.ChunkSize,
+		overlap:     config.OverlapSize,
+		idGenerator: idGenerator,
+	}, nil
+}
+
+type splitter struct {
+	tokenizer   Tokenizer
+	chunkSize   int
+	overlap     int
+	idGenerator IDGenerator
+}
+
+func (s *splitter) Transform(ctx context.Context, docs []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	ret := make([]*schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		splits, err := s.splitText(ctx, doc.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to split document %s: %w", doc.ID, err)
+		}
+		for i, split := range splits {
+			ret = append(ret, &schema.Document{
+				ID:       s.idGenerator(ctx, doc.ID, i),
+				Content:  split,
+				MetaData: deepCopyMap(doc.MetaData),
+			})
+		}
+	}
+	return ret, nil
+}
+
+func (s *splitter) splitText(ctx context.Context, text string) ([]string, error) {
+	spans, err := s.tokenizer.Tokenize(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	if len(spans) == 0 {
+		return nil, nil
+	}
+
+	step := s.chunkSize - s.overlap
+	chunks := make([]string, 0, len(spans)/step+1)
+	for i := 0; i < len(spans); i += step {
+		j := i + s.chunkSize
+		if j > len(spans) {
+			j = len(spans)
+		}
+
+		start, end := spans[i].Start, spans[j-1].End
+		if start < 0 || end > len(text) || start > end {
+			return nil, fmt.Errorf("invalid token span [%d, %d) for text of length %d", start, end, len(text))
+		}
+		// a token may hold part of a multi-byte character, extend the chunk to whole characters.
+		for start > 0 && !utf8.RuneStart(text[start]) {
+			start--
+		}
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+		chunks = append(chunks, text[start:end])
+
+		if j == len(spans) {
+			break
+		}
+	}
+	return chunks, nil
+}
+
+func (s *splitter) GetType() string {
+	return "TokenSplitter"
+}
+
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	ret := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
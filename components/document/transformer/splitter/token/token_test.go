@@ -0,0 +1,168 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// wordTokenizer treats every space separated word, with its leading space, as a token.
+var wordTokenizer = TokenizerFunc(func(ctx context.Context, text string) ([]Span, error) {
+	var spans []Span
+	start := 0
+	for i := 1; i <= len(text); i++ {
+		if i == len(text) || text[i] == ' ' {
+			spans = append(spans, Span{Start: start, End: i})
+			start = i
+		}
+	}
+	return spans, nil
+})
+
+// byteEncoder encodes every byte as a token, like a byte level BPE without merges.
+type byteEncoder struct{}
+
+func (byteEncoder) Encode(text string, _ []string, _ []string) []int {
+	ids := make([]int, 0, len(text))
+	for i := 0; i < len(text); i++ {
+		ids = append(ids, int(text[i]))
+	}
+	return ids
+}
+
+func (byteEncoder) Decode(tokens []int) string {
+	b := make([]byte, 0, len(tokens))
+	for _, t := range tokens {
+		b = append(b, byte(t))
+	}
+	return string(b)
+}
+
+func TestNewSplitter(t *testing.T) {
+	ctx := context.Background()
+	for _, conf := range []*Config{
+		{ChunkSize: 10},
+		{Tokenizer: wordTokenizer},
+		{Tokenizer: wordTokenizer, ChunkSize: 10, OverlapSize: -1},
+		{Tokenizer: wordTokenizer, ChunkSize: 10, OverlapSize: 10},
+	} {
+		if _, err := NewSplitter(ctx, conf); err == nil {
+			t.Fatalf("expected error for config %+v", conf)
+		}
+	}
+}
+
+func TestTokenSplitter(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		config *Config
+		input  []*schema.Document
+		want   []*schema.Document
+	}{
+		{
+			name: "overlap",
+			config: &Config{
+				Tokenizer:   wordTokenizer,
+				ChunkSize:   3,
+				OverlapSize: 1,
+				IDGenerator: func(ctx context.Context, originalID string, splitIndex int) string {
+					return fmt.Sprintf("%s_part%d", originalID, splitIndex)
+				},
+			},
+			input: []*schema.Document{
+				{ID: "doc", Content: "a b c d e f g", MetaData: map[string]any{"k": "v"}},
+			},
+			want: []*schema.Document{
+				{ID: "doc_part0", Content: "a b c", MetaData: map[string]any{"k": "v"}},
+				{ID: "doc_part1", Content: " c d e", MetaData: map[string]any{"k": "v"}},
+				{ID: "doc_part2", Content: " e f g", MetaData: map[string]any{"k": "v"}},
+			},
+		},
+		{
+			name:   "short document",
+			config: &Config{Tokenizer: wordTokenizer, ChunkSize: 10},
+			input:  []*schema.Document{{ID: "doc", Content: "a b"}, {ID: "empty"}},
+			want:   []*schema.Document{{ID: "doc", Content: "a b"}},
+		},
+		{
+			name:   "tiktoken keeps whole characters",
+			config: &Config{Tokenizer: NewTiktokenTokenizer(byteEncoder{}), ChunkSize: 4},
+			input:  []*schema.Document{{Content: "ab你好"}},
+			want: []*schema.Document{
+				{Content: "ab你"},
+				{Content: "你好"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSplitter(ctx, tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.Transform(ctx, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenSplitterError(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := NewSplitter(ctx, &Config{
+		Tokenizer: TokenizerFunc(func(ctx context.Context, text string) ([]Span, error) {
+			return nil, errors.New("tokenize failed")
+		}),
+		ChunkSize: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Transform(ctx, []*schema.Document{{ID: "doc", Content: "abc"}})
+	if err == nil || !strings.Contains(err.Error(), "tokenize failed") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	s, err = NewSplitter(ctx, &Config{
+		Tokenizer: TokenizerFunc(func(ctx context.Context, text string) ([]Span, error) {
+			return []Span{{Start: 0, End: len(text) + 1}}, nil
+		}),
+		ChunkSize: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Transform(ctx, []*schema.Document{{ID: "doc", Content: "abc"}})
+	if err == nil {
+		t.Fatal("expected error for invalid span")
+	}
+}
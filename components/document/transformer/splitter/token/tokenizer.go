@@ -0,0 +1,72 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package token
+
+import "context"
+
+// Span is the byte range [Start, End) of a token in the tokenized text.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Tokenizer splits text into tokens of a specific model.
+type Tokenizer interface {
+	// Tokenize returns the spans of the tokens in text, in order.
+	Tokenize(ctx context.Context, text string) ([]Span, error)
+}
+
+// TokenizerFunc adapts a function to Tokenizer, e.g. to wrap a remote tokenization API
+// which returns the offset mapping of tokens, such as the Doubao tokenization API.
+type TokenizerFunc func(ctx context.Context, text string) ([]Span, error)
+
+func (f TokenizerFunc) Tokenize(ctx context.Context, text string) ([]Span, error) {
+	return f(ctx, text)
+}
+
+// TiktokenEncoder is the encoder interface of tiktoken compatible BPE libraries,
+// satisfied by *tiktoken.Tiktoken of github.com/pkoukk/tiktoken-go.
+type TiktokenEncoder interface {
+	Encode(text string, allowedSpecial []string, disallowedSpecial []string) []int
+	Decode(tokens []int) string
+}
+
+// NewTiktokenTokenizer returns a Tokenizer based on a tiktoken compatible encoder, e.g.
+//
+//	enc, err := tiktoken.GetEncoding("cl100k_base")
+//	tokenizer := token.NewTiktokenTokenizer(enc)
+func NewTiktokenTokenizer(enc TiktokenEncoder) Tokenizer {
+	return &tiktokenTokenizer{enc: enc}
+}
+
+type tiktokenTokenizer struct {
+	enc TiktokenEncoder
+}
+
+func (t *tiktokenTokenizer) Tokenize(_ context.Context, text string) ([]Span, error) {
+	ids := t.enc.Encode(text, nil, nil)
+	spans := make([]Span, 0, len(ids))
+	offset := 0
+	for _, id := range ids {
+		// BPE tokens are byte sequences, so the decoded length of a single token is its byte length,
+		// even when the token holds part of a multi-byte character.
+		n := len(t.enc.Decode([]int{id}))
+		spans = append(spans, Span{Start: offset, End: offset + n})
+		offset += n
+	}
+	return spans, nil
+}
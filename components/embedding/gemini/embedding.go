@@ -18,6 +18,7 @@ package gemini
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
@@ -35,7 +36,8 @@ type EmbeddingConfig struct {
 	// Examples: "gemini-embedding-001", "gemini-embedding-exp-03-07"
 	Model string
 
-	// Type of task for which the embedding will be used.
+	// Type of task for which the embedding will be used, e.g. TaskTypeRetrievalDocument.
+	// Can be overridden per call by WithTaskType.
 	TaskType string
 
 	// Title for the text. Only applicable when TaskType is
@@ -46,6 +48,7 @@ type EmbeddingConfig struct {
 	// excessive values in the output embedding are truncated from the end.
 	// Supported by newer models since 2024 only. You cannot set this value if
 	// using the earlier model (`models/embedding-001`).
+	// Can be overridden per call by WithOutputDimensionality.
 	OutputDimensionality *int32
 
 	// Vertex API only. The MIME type of the input.
@@ -55,8 +58,15 @@ type EmbeddingConfig struct {
 	// the max sequence length. If this option is set to false, oversized inputs
 	// will lead to an INVALID_ARGUMENT error, similar to other text APIs.
 	AutoTruncate bool `json:"autoTruncate,omitempty"`
+
+	// BatchSize is the max number of texts sent in one request.
+	// Texts exceeding it are split into multiple requests.
+	// Default: 100
+	BatchSize int
 }
 
+const defaultBatchSize = 100
+
 type Embedder struct {
 	cli *genai.Client
 
@@ -64,9 +74,17 @@ type Embedder struct {
 }
 
 func NewEmbedder(ctx context.Context, cfg *EmbeddingConfig) (*Embedder, error) {
+	if cfg == nil || cfg.Client == nil {
+		return nil, fmt.Errorf("gemini client is required")
+	}
+	// fill in the defaults on a copy, keep the caller's config untouched
+	conf := *cfg
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = defaultBatchSize
+	}
 	return &Embedder{
-		cli:  cfg.Client,
-		conf: cfg,
+		cli:  conf.Client,
+		conf: &conf,
 	}, nil
 }
 
@@ -74,6 +92,10 @@ func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...emb
 	options := embedding.GetCommonOptions(&embedding.Options{
 		Model: &e.conf.Model,
 	}, opts...)
+	io := embedding.GetImplSpecificOptions(&implOptions{
+		taskType:             e.conf.TaskType,
+		outputDimensionality: e.conf.OutputDimensionality,
+	}, opts...)
 
 	conf := &embedding.Config{
 		Model: *options.Model,
@@ -91,43 +113,45 @@ func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...emb
 		}
 	}()
 
-	contents := make([]*genai.Content, 0, len(texts))
-	for _, text := range texts {
-		contents = append(contents, genai.NewContentFromText(text, genai.RoleUser))
-	}
-
 	embedContentConfig := &genai.EmbedContentConfig{
-		TaskType:             e.conf.TaskType,
+		TaskType:             io.taskType,
 		Title:                e.conf.Title,
-		OutputDimensionality: e.conf.OutputDimensionality,
+		OutputDimensionality: io.outputDimensionality,
 		MIMEType:             e.conf.MIMEType,
 		AutoTruncate:         e.conf.AutoTruncate,
 	}
 
-	resp, err := e.cli.Models.EmbedContent(ctx,
-		e.conf.Model,
-		contents,
-		embedContentConfig,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	// Convert [][]float32 to [][]float64
-	embeddings = make([][]float64, len(resp.Embeddings))
+	embeddings = make([][]float64, 0, len(texts))
 	var tokenUsage *embedding.TokenUsage
-	for i, emb := range resp.Embeddings {
-		embeddings[i] = make([]float64, len(emb.Values))
-		for j, v := range emb.Values {
-			embeddings[i][j] = float64(v)
+	for l := 0; l < len(texts); l += e.conf.BatchSize {
+		r := min(l+e.conf.BatchSize, len(texts))
+
+		contents := make([]*genai.Content, 0, r-l)
+		for _, text := range texts[l:r] {
+			contents = append(contents, genai.NewContentFromText(text, genai.RoleUser))
+		}
+
+		resp, err := e.cli.Models.EmbedContent(ctx, conf.Model, contents, embedContentConfig)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) != len(contents) {
+			return nil, fmt.Errorf("embedding result length mismatch: need %d, got %d", len(contents), len(resp.Embeddings))
 		}
-		if emb.Statistics != nil {
-			if tokenUsage == nil {
-				tokenUsage = &embedding.TokenUsage{
-					PromptTokens: int(emb.Statistics.TokenCount),
+
+		// Convert [][]float32 to [][]float64
+		for _, emb := range resp.Embeddings {
+			vec := make([]float64, len(emb.Values))
+			for j, v := range emb.Values {
+				vec[j] = float64(v)
+			}
+			embeddings = append(embeddings, vec)
+			if emb.Statistics != nil {
+				if tokenUsage == nil {
+					tokenUsage = &embedding.TokenUsage{}
 				}
-			} else {
 				tokenUsage.PromptTokens += int(emb.Statistics.TokenCount)
+				tokenUsage.TotalTokens += int(emb.Statistics.TokenCount)
 			}
 		}
 	}
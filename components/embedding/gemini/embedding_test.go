@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/embedding"
 	"github.com/smartystreets/goconvey/convey"
 	"google.golang.org/genai"
 )
@@ -69,5 +70,62 @@ func Test_EmbedStrings(t *testing.T) {
 				convey.So(len(result[i]), convey.ShouldEqual, len(expectedResult[i]))
 			}
 		})
+
+		PatchConvey("test batching and per-call options", func() {
+			embedder, err := NewEmbedder(ctx, &EmbeddingConfig{
+				Client:    mockCli,
+				Model:     "gemini-embedding-001",
+				TaskType:  TaskTypeRetrievalDocument,
+				BatchSize: 2,
+			})
+			convey.So(err, convey.ShouldBeNil)
+
+			var batches []int
+			var gotModel, gotTaskType string
+			var gotDim *int32
+			Mock(GetMethod(mockCli.Models, "EmbedContent")).To(func(_ genai.Models, ctx context.Context, model string,
+				contents []*genai.Content, config *genai.EmbedContentConfig) (*genai.EmbedContentResponse, error) {
+				batches = append(batches, len(contents))
+				gotModel, gotTaskType, gotDim = model, config.TaskType, config.OutputDimensionality
+				resp := &genai.EmbedContentResponse{}
+				for range contents {
+					resp.Embeddings = append(resp.Embeddings, &genai.ContentEmbedding{
+						Values:     []float32{float32(len(batches))},
+						Statistics: &genai.ContentEmbeddingStatistics{TokenCount: 1},
+					})
+				}
+				return resp, nil
+			}).Build()
+
+			result, err := embedder.EmbedStrings(ctx, []string{"a", "b", "c"},
+				embedding.WithModel("text-embedding-004"),
+				WithTaskType(TaskTypeRetrievalQuery),
+				WithOutputDimensionality(256))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(batches, convey.ShouldResemble, []int{2, 1})
+			convey.So(result, convey.ShouldResemble, [][]float64{{1}, {1}, {2}})
+			convey.So(gotModel, convey.ShouldEqual, "text-embedding-004")
+			convey.So(gotTaskType, convey.ShouldEqual, TaskTypeRetrievalQuery)
+			convey.So(*gotDim, convey.ShouldEqual, 256)
+		})
+
+		PatchConvey("test embedding length mismatch", func() {
+			Mock(GetMethod(mockCli.Models, "EmbedContent")).Return(mockResponse, nil).Build()
+			_, err := embedder.EmbedStrings(ctx, []string{"hello world"})
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+func Test_NewEmbedder(t *testing.T) {
+	convey.Convey("test NewEmbedder", t, func() {
+		_, err := NewEmbedder(context.Background(), &EmbeddingConfig{Model: "gemini-embedding-001"})
+		convey.So(err, convey.ShouldNotBeNil)
+
+		cfg := &EmbeddingConfig{Client: &genai.Client{}}
+		emb, err := NewEmbedder(context.Background(), cfg)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(emb.conf.BatchSize, convey.ShouldEqual, defaultBatchSize)
+		convey.So(cfg.BatchSize, convey.ShouldEqual, 0)
 	})
 }
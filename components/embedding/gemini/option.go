@@ -0,0 +1,53 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import "github.com/cloudwego/eino/components/embedding"
+
+// Task types supported by Gemini embedding models.
+// See https://ai.google.dev/gemini-api/docs/embeddings#task-types
+const (
+	TaskTypeRetrievalQuery     = "RETRIEVAL_QUERY"
+	TaskTypeRetrievalDocument  = "RETRIEVAL_DOCUMENT"
+	TaskTypeSemanticSimilarity = "SEMANTIC_SIMILARITY"
+	TaskTypeClassification     = "CLASSIFICATION"
+	TaskTypeClustering         = "CLUSTERING"
+	TaskTypeQuestionAnswering  = "QUESTION_ANSWERING"
+	TaskTypeFactVerification   = "FACT_VERIFICATION"
+	TaskTypeCodeRetrievalQuery = "CODE_RETRIEVAL_QUERY"
+)
+
+type implOptions struct {
+	taskType             string
+	outputDimensionality *int32
+}
+
+// WithTaskType overrides EmbeddingConfig.TaskType for a single call,
+// e.g. TaskTypeRetrievalDocument when indexing and TaskTypeRetrievalQuery when searching
+// with the same embedder.
+func WithTaskType(taskType string) embedding.Option {
+	return embedding.WrapImplSpecificOptFn(func(o *implOptions) {
+		o.taskType = taskType
+	})
+}
+
+// WithOutputDimensionality overrides EmbeddingConfig.OutputDimensionality for a single call.
+func WithOutputDimensionality(dim int32) embedding.Option {
+	return embedding.WrapImplSpecificOptFn(func(o *implOptions) {
+		o.outputDimensionality = &dim
+	})
+}
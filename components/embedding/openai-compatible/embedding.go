@@ -0,0 +1,303 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package openaicompatible provides an embedder for servers exposing the OpenAI
+// embeddings API, e.g. vLLM, TEI, Xinference or other self-hosted embedding servers.
+package openaicompatible
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+const (
+	typ = "OpenAICompatible"
+
+	defaultBatchSize    = 64
+	defaultRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 10 * time.Second
+)
+
+type EmbeddingConfig struct {
+	// BaseURL is the base URL of the OpenAI compatible API, the request is sent to BaseURL + "/embeddings".
+	// Example: "http://localhost:8000/v1"
+	// Required
+	BaseURL string `json:"base_url"`
+
+	// APIKey is sent as "Authorization: Bearer <APIKey>" if set.
+	// Optional
+	APIKey string `json:"api_key"`
+
+	// Model specifies the ID of the model to use for embedding generation
+	// Required
+	Model string `json:"model"`
+
+	// Dimensions specifies the number of dimensions the resulting output embeddings should have
+	// Optional. Only sent when set, as many servers do not support it
+	Dimensions *int `json:"dimensions,omitempty"`
+
+	// User is a unique identifier representing your end-user
+	// Optional
+	User *string `json:"user,omitempty"`
+
+	// Headers are extra HTTP headers sent with every request
+	// Optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// BatchSize is the max number of texts sent in one request.
+	// Texts exceeding it are split into multiple requests.
+	// Optional. Default: 64
+	BatchSize int `json:"batch_size"`
+
+	// MaxRetries is the max number of retries of a request failed with a network error,
+	// HTTP 429 or HTTP 5xx.
+	// Optional. Default: 0, no retry
+	MaxRetries int `json:"max_retries"`
+
+	// RetryBackoff is the wait before the first retry, doubled on each following retry and capped at 10s.
+	// Optional. Default: 500ms
+	RetryBackoff time.Duration `json:"retry_backoff"`
+
+	// Timeout specifies the maximum duration to wait for API responses
+	// If HTTPClient is set, Timeout will not be used.
+	// Optional. Default: no timeout
+	Timeout time.Duration `json:"timeout"`
+
+	// HTTPClient specifies the client to send HTTP requests.
+	// If HTTPClient is set, Timeout will not be used.
+	// Optional. Default &http.Client{Timeout: Timeout}
+	HTTPClient *http.Client `json:"http_client"`
+}
+
+// APIError is returned when the server responds with a non-2xx status code.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("embedding request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+var _ embedding.Embedder = (*Embedder)(nil)
+
+type Embedder struct {
+	cli  *http.Client
+	conf *EmbeddingConfig
+	url  string
+}
+
+func NewEmbedder(ctx context.Context, config *EmbeddingConfig) (*Embedder, error) {
+	if config == nil {
+		return nil, errors.New("config is required")
+	}
+	if config.BaseURL == "" {
+		return nil, errors.New("base url is required")
+	}
+	if config.Model == "" {
+		return nil, errors.New("model is required")
+	}
+
+	conf := *config
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = defaultBatchSize
+	}
+	if conf.RetryBackoff <= 0 {
+		conf.RetryBackoff = defaultRetryBackoff
+	}
+
+	cli := conf.HTTPClient
+	if cli == nil {
+		cli = &http.Client{Timeout: conf.Timeout}
+	}
+
+	return &Embedder{
+		cli:  cli,
+		conf: &conf,
+		url:  strings.TrimSuffix(conf.BaseURL, "/") + "/embeddings",
+	}, nil
+}
+
+type embeddingRequest struct {
+	Input          []string `json:"input"`
+	Model          string   `json:"model"`
+	EncodingFormat string   `json:"encoding_format"`
+	Dimensions     *int     `json:"dimensions,omitempty"`
+	User           *string  `json:"user,omitempty"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+	Usage *struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) (
+	embeddings [][]float64, err error) {
+	options := embedding.GetCommonOptions(&embedding.Options{
+		Model: &e.conf.Model,
+	}, opts...)
+
+	conf := &embedding.Config{
+		Model:          *options.Model,
+		EncodingFormat: "float",
+	}
+
+	ctx = callbacks.EnsureRunInfo(ctx, e.GetType(), components.ComponentOfEmbedding)
+	ctx = callbacks.OnStart(ctx, &embedding.CallbackInput{
+		Texts:  texts,
+		Config: conf,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	embeddings = make([][]float64, 0, len(texts))
+	var usage *embedding.TokenUsage
+	for l := 0; l < len(texts); l += e.conf.BatchSize {
+		r := l + e.conf.BatchSize
+		if r > len(texts) {
+			r = len(texts)
+		}
+
+		resp, err := e.embedWithRetry(ctx, &embeddingRequest{
+			Input:          texts[l:r],
+			Model:          conf.Model,
+			EncodingFormat: conf.EncodingFormat,
+			Dimensions:     e.conf.Dimensions,
+			User:           e.conf.User,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data) != r-l {
+			return nil, fmt.Errorf("embedding result length mismatch: need %d, got %d", r-l, len(resp.Data))
+		}
+
+		sort.SliceStable(resp.Data, func(i, j int) bool {
+			return resp.Data[i].Index < resp.Data[j].Index
+		})
+		for _, d := range resp.Data {
+			embeddings = append(embeddings, d.Embedding)
+		}
+
+		if resp.Usage != nil {
+			if usage == nil {
+				usage = &embedding.TokenUsage{}
+			}
+			usage.PromptTokens += resp.Usage.PromptTokens
+			usage.TotalTokens += resp.Usage.TotalTokens
+		}
+	}
+
+	callbacks.OnEnd(ctx, &embedding.CallbackOutput{
+		Embeddings: embeddings,
+		Config:     conf,
+		TokenUsage: usage,
+	})
+
+	return embeddings, nil
+}
+
+func (e *Embedder) embedWithRetry(ctx context.Context, req *embeddingRequest) (*embeddingResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
+	}
+
+	backoff := e.conf.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, retryable, err := e.embed(ctx, body)
+		if err == nil || !retryable || attempt >= e.conf.MaxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// embed sends one embedding request, the returned bool reports whether the request can be retried.
+func (e *Embedder) embed(ctx context.Context, body []byte) (*embeddingResponse, bool, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create embedding request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if e.conf.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+e.conf.APIKey)
+	}
+	for k, v := range e.conf.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	httpResp, err := e.cli.Do(httpReq)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to send embedding request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read embedding response: %w", err)
+	}
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		retryable := httpResp.StatusCode == http.StatusTooManyRequests || httpResp.StatusCode >= 500
+		return nil, retryable, &APIError{StatusCode: httpResp.StatusCode, Body: string(respBody)}
+	}
+
+	resp := &embeddingResponse{}
+	if err = json.Unmarshal(respBody, resp); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal embedding response: %w", err)
+	}
+	return resp, false, nil
+}
+
+func (e *Embedder) GetType() string {
+	return typ
+}
+
+func (e *Embedder) IsCallbacksEnabled() bool {
+	return true
+}
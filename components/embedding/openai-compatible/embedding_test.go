@@ -0,0 +1,195 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openaicompatible
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/embedding"
+	callbacksHelper "github.com/cloudwego/eino/utils/callbacks"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T, handler func(w http.ResponseWriter, req *embeddingRequest)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		req := &embeddingRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		handler(w, req)
+	}))
+}
+
+func writeEmbeddings(w http.ResponseWriter, req *embeddingRequest) {
+	type item struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	}
+	// return items in reverse order to check sorting by index
+	data := make([]item, 0, len(req.Input))
+	for i := len(req.Input) - 1; i >= 0; i-- {
+		data = append(data, item{Index: i, Embedding: []float64{float64(len(req.Input[i]))}})
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"data":  data,
+		"usage": map[string]int{"prompt_tokens": len(req.Input), "total_tokens": len(req.Input)},
+	})
+}
+
+func TestNewEmbedder(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewEmbedder(ctx, nil)
+	assert.Error(t, err)
+	_, err = NewEmbedder(ctx, &EmbeddingConfig{Model: "bge-m3"})
+	assert.Error(t, err)
+	_, err = NewEmbedder(ctx, &EmbeddingConfig{BaseURL: "http://localhost:8000/v1"})
+	assert.Error(t, err)
+
+	emb, err := NewEmbedder(ctx, &EmbeddingConfig{BaseURL: "http://localhost:8000/v1/", Model: "bge-m3"})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:8000/v1/embeddings", emb.url)
+	assert.Equal(t, defaultBatchSize, emb.conf.BatchSize)
+	assert.Equal(t, typ, emb.GetType())
+	assert.True(t, emb.IsCallbacksEnabled())
+}
+
+func TestEmbedStrings(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("batching and usage", func(t *testing.T) {
+		var batches []int
+		srv := newTestServer(t, func(w http.ResponseWriter, req *embeddingRequest) {
+			batches = append(batches, len(req.Input))
+			assert.Equal(t, "bge-large", req.Model)
+			assert.Equal(t, "float", req.EncodingFormat)
+			writeEmbeddings(w, req)
+		})
+		defer srv.Close()
+
+		emb, err := NewEmbedder(ctx, &EmbeddingConfig{
+			BaseURL:   srv.URL + "/v1",
+			Model:     "bge-m3",
+			BatchSize: 2,
+		})
+		assert.NoError(t, err)
+
+		var usage *embedding.TokenUsage
+		handler := callbacksHelper.NewHandlerHelper().Embedding(&callbacksHelper.EmbeddingCallbackHandler{
+			OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *embedding.CallbackOutput) context.Context {
+				usage = output.TokenUsage
+				return ctx
+			},
+		}).Handler()
+		ctx := callbacks.InitCallbacks(ctx, nil, handler)
+
+		result, err := emb.EmbedStrings(ctx, []string{"a", "bb", "ccc"}, embedding.WithModel("bge-large"))
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 1}, batches)
+		assert.Equal(t, [][]float64{{1}, {2}, {3}}, result)
+		assert.Equal(t, &embedding.TokenUsage{PromptTokens: 3, TotalTokens: 3}, usage)
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+			assert.Equal(t, "tenant-a", r.Header.Get("X-Tenant"))
+			req := &embeddingRequest{}
+			_ = json.NewDecoder(r.Body).Decode(req)
+			writeEmbeddings(w, req)
+		}))
+		defer srv.Close()
+
+		emb, err := NewEmbedder(ctx, &EmbeddingConfig{
+			BaseURL: srv.URL,
+			APIKey:  "sk-test",
+			Model:   "bge-m3",
+			Headers: map[string]string{"X-Tenant": "tenant-a"},
+		})
+		assert.NoError(t, err)
+		_, err = emb.EmbedStrings(ctx, []string{"a"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("retry on 503", func(t *testing.T) {
+		var calls int32
+		srv := newTestServer(t, func(w http.ResponseWriter, req *embeddingRequest) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			writeEmbeddings(w, req)
+		})
+		defer srv.Close()
+
+		emb, err := NewEmbedder(ctx, &EmbeddingConfig{
+			BaseURL:      srv.URL + "/v1",
+			Model:        "bge-m3",
+			MaxRetries:   2,
+			RetryBackoff: time.Millisecond,
+		})
+		assert.NoError(t, err)
+		result, err := emb.EmbedStrings(ctx, []string{"a"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1}}, result)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("no retry on 400", func(t *testing.T) {
+		var calls int32
+		srv := newTestServer(t, func(w http.ResponseWriter, req *embeddingRequest) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, "bad input")
+		})
+		defer srv.Close()
+
+		emb, err := NewEmbedder(ctx, &EmbeddingConfig{
+			BaseURL:      srv.URL + "/v1",
+			Model:        "bge-m3",
+			MaxRetries:   2,
+			RetryBackoff: time.Millisecond,
+		})
+		assert.NoError(t, err)
+		_, err = emb.EmbedStrings(ctx, []string{"a"})
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+		assert.Equal(t, "bad input", apiErr.Body)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("length mismatch", func(t *testing.T) {
+		srv := newTestServer(t, func(w http.ResponseWriter, req *embeddingRequest) {
+			_, _ = fmt.Fprint(w, `{"data":[]}`)
+		})
+		defer srv.Close()
+
+		emb, err := NewEmbedder(ctx, &EmbeddingConfig{BaseURL: srv.URL + "/v1", Model: "bge-m3"})
+		assert.NoError(t, err)
+		_, err = emb.EmbedStrings(ctx, []string{"a"})
+		assert.Error(t, err)
+	})
+}
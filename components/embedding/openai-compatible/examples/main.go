@@ -0,0 +1,58 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	openaicompatible "github.com/cloudwego/eino-ext/components/embedding/openai-compatible"
+)
+
+func main() {
+	ctx := context.Background()
+
+	baseURL := os.Getenv("EMBEDDING_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8000/v1" // e.g. vLLM or TEI
+	}
+	model := os.Getenv("EMBEDDING_MODEL")
+	if model == "" {
+		model = "BAAI/bge-m3"
+	}
+
+	embedder, err := openaicompatible.NewEmbedder(ctx, &openaicompatible.EmbeddingConfig{
+		BaseURL:    baseURL,
+		APIKey:     os.Getenv("EMBEDDING_API_KEY"),
+		Model:      model,
+		BatchSize:  32,
+		MaxRetries: 3,
+		Timeout:    30 * time.Second,
+	})
+	if err != nil {
+		log.Fatalf("NewEmbedder failed, err=%v", err)
+	}
+
+	vectors, err := embedder.EmbedStrings(ctx, []string{"hello", "how are you"})
+	if err != nil {
+		log.Fatalf("EmbedStrings failed, err=%v", err)
+	}
+
+	log.Printf("vectors: %d, dimensions: %d", len(vectors), len(vectors[0]))
+}
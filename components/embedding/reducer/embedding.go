@@ -0,0 +1,155 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reducer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+var (
+	ErrEmbedderRequired = errors.New("embedding/reducer: embedder is required")
+	ErrInvalidDimension = errors.New("embedding/reducer: dimension must be positive")
+)
+
+// Embedder reduces the vectors of the wrapped embedder to a target dimension,
+// by truncation for Matryoshka (MRL) embeddings, or by a PCA projection for the other embeddings.
+type Embedder struct {
+	embedder   embedding.Embedder
+	dimension  int
+	projection *Projection
+	normalize  bool
+}
+
+type Option interface {
+	apply(*Embedder)
+}
+
+type optionFunc func(*Embedder)
+
+func (f optionFunc) apply(e *Embedder) {
+	f(e)
+}
+
+// WithProjection returns an [Option] that projects the vectors with the [Projection] instead of truncating them,
+// e.g. a projection fitted by [FitPCA] on a sample of the vectors of the wrapped embedder.
+func WithProjection(projection *Projection) Option {
+	return optionFunc(func(e *Embedder) {
+		e.projection = projection
+	})
+}
+
+// WithNormalize returns an [Option] that scales the reduced vectors to unit L2 norm,
+// which is required to compare truncated Matryoshka embeddings by inner product.
+func WithNormalize(normalize bool) Option {
+	return optionFunc(func(e *Embedder) {
+		e.normalize = normalize
+	})
+}
+
+var _ embedding.Embedder = (*Embedder)(nil)
+
+// NewEmbedder creates a new [Embedder] instance reducing the vectors of the embedder to dimension.
+func NewEmbedder(embedder embedding.Embedder, dimension int, opts ...Option) (*Embedder, error) {
+	if embedder == nil {
+		return nil, ErrEmbedderRequired
+	}
+	if dimension <= 0 {
+		return nil, ErrInvalidDimension
+	}
+
+	e := &Embedder{
+		embedder:  embedder,
+		dimension: dimension,
+	}
+	for _, opt := range opts {
+		opt.apply(e)
+	}
+
+	if e.projection != nil {
+		if err := e.projection.validate(); err != nil {
+			return nil, err
+		}
+		if len(e.projection.Components) != dimension {
+			return nil, fmt.Errorf("embedding/reducer: projection has %d components, need %d", len(e.projection.Components), dimension)
+		}
+	}
+
+	return e, nil
+}
+
+// Dimension returns the dimension of the reduced vectors.
+func (e *Embedder) Dimension() int {
+	return e.dimension
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	vectors, err := e.embedder.EmbedStrings(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	reduced := make([][]float64, len(vectors))
+	for i, vector := range vectors {
+		reduced[i], err = e.reduce(vector)
+		if err != nil {
+			return nil, fmt.Errorf("embedding/reducer: vector %d: %w", i, err)
+		}
+	}
+	return reduced, nil
+}
+
+func (e *Embedder) reduce(vector []float64) ([]float64, error) {
+	var reduced []float64
+	if e.projection != nil {
+		projected, err := e.projection.Project(vector)
+		if err != nil {
+			return nil, err
+		}
+		reduced = projected
+	} else {
+		if len(vector) < e.dimension {
+			return nil, fmt.Errorf("dimension %d is smaller than the target dimension %d", len(vector), e.dimension)
+		}
+		reduced = make([]float64, e.dimension)
+		copy(reduced, vector)
+	}
+
+	if e.normalize {
+		normalize(reduced)
+	}
+	return reduced, nil
+}
+
+// normalize scales the vector to unit L2 norm in place, zero vectors are left as is.
+func normalize(vector []float64) {
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm == 0 {
+		return
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] /= norm
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reducer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticEmbedder struct {
+	vectors [][]float64
+	err     error
+}
+
+func (s *staticEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	return s.vectors, s.err
+}
+
+func TestNewEmbedder(t *testing.T) {
+	_, err := NewEmbedder(nil, 2)
+	assert.ErrorIs(t, err, ErrEmbedderRequired)
+
+	_, err = NewEmbedder(&staticEmbedder{}, 0)
+	assert.ErrorIs(t, err, ErrInvalidDimension)
+
+	_, err = NewEmbedder(&staticEmbedder{}, 2, WithProjection(&Projection{}))
+	assert.ErrorContains(t, err, "no components")
+
+	_, err = NewEmbedder(&staticEmbedder{}, 2, WithProjection(&Projection{
+		Mean:       []float64{0, 0},
+		Components: [][]float64{{1, 0}},
+	}))
+	assert.ErrorContains(t, err, "projection has 1 components, need 2")
+
+	_, err = NewEmbedder(&staticEmbedder{}, 1, WithProjection(&Projection{
+		Mean:       []float64{0, 0},
+		Components: [][]float64{{1, 0, 0}},
+	}))
+	assert.ErrorContains(t, err, "component 0 has dimension 3")
+
+	e, err := NewEmbedder(&staticEmbedder{}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, e.Dimension())
+}
+
+func TestEmbedder_EmbedStrings(t *testing.T) {
+	ctx := context.Background()
+	inner := &staticEmbedder{vectors: [][]float64{{3, 4, 5, 6}, {0, 0, 1, 1}}}
+
+	t.Run("truncate", func(t *testing.T) {
+		e, err := NewEmbedder(inner, 2)
+		require.NoError(t, err)
+		vectors, err := e.EmbedStrings(ctx, []string{"a", "b"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]float64{{3, 4}, {0, 0}}, vectors)
+		// the vectors of the wrapped embedder are not modified
+		assert.Equal(t, []float64{3, 4, 5, 6}, inner.vectors[0])
+	})
+
+	t.Run("truncate and normalize", func(t *testing.T) {
+		e, err := NewEmbedder(inner, 2, WithNormalize(true))
+		require.NoError(t, err)
+		vectors, err := e.EmbedStrings(ctx, []string{"a", "b"})
+		require.NoError(t, err)
+		assert.InDeltaSlice(t, []float64{0.6, 0.8}, vectors[0], 1e-12)
+		assert.Equal(t, []float64{0, 0}, vectors[1])
+	})
+
+	t.Run("projection", func(t *testing.T) {
+		e, err := NewEmbedder(inner, 1, WithProjection(&Projection{
+			Mean:       []float64{1, 1, 1, 1},
+			Components: [][]float64{{0, 0, 1, 0}},
+		}))
+		require.NoError(t, err)
+		vectors, err := e.EmbedStrings(ctx, []string{"a", "b"})
+		require.NoError(t, err)
+		assert.Equal(t, [][]float64{{4}, {0}}, vectors)
+	})
+
+	t.Run("dimension too small", func(t *testing.T) {
+		e, err := NewEmbedder(inner, 8)
+		require.NoError(t, err)
+		_, err = e.EmbedStrings(ctx, []string{"a", "b"})
+		assert.ErrorContains(t, err, "vector 0: dimension 4 is smaller than the target dimension 8")
+	})
+
+	t.Run("projection dimension mismatch", func(t *testing.T) {
+		e, err := NewEmbedder(inner, 1, WithProjection(&Projection{
+			Mean:       []float64{0, 0},
+			Components: [][]float64{{1, 0}},
+		}))
+		require.NoError(t, err)
+		_, err = e.EmbedStrings(ctx, []string{"a", "b"})
+		assert.ErrorContains(t, err, "mismatches the projection dimension")
+	})
+
+	t.Run("embedder error", func(t *testing.T) {
+		embedErr := errors.New("embed error")
+		e, err := NewEmbedder(&staticEmbedder{err: embedErr}, 2)
+		require.NoError(t, err)
+		_, err = e.EmbedStrings(ctx, []string{"a"})
+		assert.ErrorIs(t, err, embedErr)
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	v := []float64{1, 1}
+	normalize(v)
+	assert.InDeltaSlice(t, []float64{1 / math.Sqrt2, 1 / math.Sqrt2}, v, 1e-12)
+
+	zero := []float64{0, 0}
+	normalize(zero)
+	assert.Equal(t, []float64{0, 0}, zero)
+}
@@ -0,0 +1,205 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reducer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+const (
+	pcaMaxIterations = 200
+	pcaTolerance     = 1e-9
+	// pcaRankTolerance is the share of the total variance below which the sample is considered exhausted
+	pcaRankTolerance = 1e-9
+)
+
+// Projection is a linear projection of the vectors onto the principal components of a sample,
+// the projected vector is Components * (vector - Mean).
+// It can be marshaled as JSON to reuse a fitted projection across processes,
+// the same projection must be used for the stored vectors and the query vectors.
+type Projection struct {
+	// Mean is the mean vector of the sample, subtracted before projecting.
+	Mean []float64 `json:"mean"`
+	// Components are the unit principal components, ordered by decreasing variance,
+	// each of them has the dimension of the original vectors.
+	Components [][]float64 `json:"components"`
+}
+
+func (p *Projection) validate() error {
+	if len(p.Components) == 0 {
+		return errors.New("embedding/reducer: projection has no components")
+	}
+	for i, c := range p.Components {
+		if len(c) != len(p.Mean) {
+			return fmt.Errorf("embedding/reducer: projection component %d has dimension %d, need %d", i, len(c), len(p.Mean))
+		}
+	}
+	return nil
+}
+
+// Project projects the vector onto the components.
+func (p *Projection) Project(vector []float64) ([]float64, error) {
+	if len(vector) != len(p.Mean) {
+		return nil, fmt.Errorf("dimension %d mismatches the projection dimension %d", len(vector), len(p.Mean))
+	}
+	projected := make([]float64, len(p.Components))
+	for i, c := range p.Components {
+		var dot float64
+		for j, v := range vector {
+			dot += (v - p.Mean[j]) * c[j]
+		}
+		projected[i] = dot
+	}
+	return projected, nil
+}
+
+// FitPCA fits a PCA projection of the sample vectors to dimension,
+// computing the principal components by power iteration without building the covariance matrix.
+// The sample should be representative of the embedded texts, and it must vary in at least dimension directions,
+// i.e. contain more than dimension linearly independent vectors, otherwise an error is returned.
+func FitPCA(vectors [][]float64, dimension int) (*Projection, error) {
+	if dimension <= 0 {
+		return nil, ErrInvalidDimension
+	}
+	if len(vectors) == 0 {
+		return nil, errors.New("embedding/reducer: no vectors to fit")
+	}
+	dim := len(vectors[0])
+	if dimension > dim {
+		return nil, fmt.Errorf("embedding/reducer: target dimension %d exceeds the vector dimension %d", dimension, dim)
+	}
+
+	mean := make([]float64, dim)
+	for i, v := range vectors {
+		if len(v) != dim {
+			return nil, fmt.Errorf("embedding/reducer: vector %d has dimension %d, need %d", i, len(v), dim)
+		}
+		for j, x := range v {
+			mean[j] += x
+		}
+	}
+	for j := range mean {
+		mean[j] /= float64(len(vectors))
+	}
+
+	centered := make([][]float64, len(vectors))
+	var totalVariance float64
+	for i, v := range vectors {
+		centered[i] = make([]float64, dim)
+		for j, x := range v {
+			centered[i][j] = x - mean[j]
+			totalVariance += centered[i][j] * centered[i][j]
+		}
+	}
+
+	// a fixed seed keeps the fitted projection reproducible
+	rng := rand.New(rand.NewSource(1))
+	components := make([][]float64, 0, dimension)
+	var explainedVariance float64
+	for len(components) < dimension {
+		if totalVariance-explainedVariance <= pcaRankTolerance*totalVariance {
+			return nil, fmt.Errorf("embedding/reducer: the sample has rank %d, below the target dimension %d",
+				len(components), dimension)
+		}
+
+		c := make([]float64, dim)
+		for j := range c {
+			c[j] = rng.NormFloat64()
+		}
+		orthonormalize(c, components)
+
+		for iter := 0; iter < pcaMaxIterations; iter++ {
+			next := covarianceProduct(centered, c)
+			orthonormalize(next, components)
+			diff := 0.0
+			for j := range next {
+				diff = math.Max(diff, math.Abs(next[j]-c[j]))
+			}
+			c = next
+			if diff < pcaTolerance {
+				break
+			}
+		}
+		alignSign(c)
+		components = append(components, c)
+		explainedVariance += variance(centered, c)
+	}
+
+	return &Projection{Mean: mean, Components: components}, nil
+}
+
+// covarianceProduct returns X^T X v, which is proportional to the product of the covariance matrix and v.
+func covarianceProduct(centered [][]float64, v []float64) []float64 {
+	res := make([]float64, len(v))
+	for _, row := range centered {
+		var dot float64
+		for j, x := range row {
+			dot += x * v[j]
+		}
+		for j, x := range row {
+			res[j] += dot * x
+		}
+	}
+	return res
+}
+
+// variance returns the variance of the centered sample along the unit vector v, up to the sample size.
+func variance(centered [][]float64, v []float64) float64 {
+	var res float64
+	for _, row := range centered {
+		var dot float64
+		for j, x := range row {
+			dot += x * v[j]
+		}
+		res += dot * dot
+	}
+	return res
+}
+
+// orthonormalize removes the projections onto the orthonormal basis from v and scales it to unit norm,
+// v is left as the zero vector if it is in the span of the basis, e.g. the sample has no more variance.
+func orthonormalize(v []float64, basis [][]float64) {
+	for _, b := range basis {
+		var dot float64
+		for j := range v {
+			dot += v[j] * b[j]
+		}
+		for j := range v {
+			v[j] -= dot * b[j]
+		}
+	}
+	normalize(v)
+}
+
+// alignSign flips the component so that its largest coordinate is positive,
+// since the sign of a principal component is arbitrary.
+func alignSign(v []float64) {
+	maxIdx := 0
+	for j := range v {
+		if math.Abs(v[j]) > math.Abs(v[maxIdx]) {
+			maxIdx = j
+		}
+	}
+	if v[maxIdx] < 0 {
+		for j := range v {
+			v[j] = -v[j]
+		}
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reducer
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFitPCA(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := FitPCA([][]float64{{1, 2}}, 0)
+		assert.ErrorIs(t, err, ErrInvalidDimension)
+
+		_, err = FitPCA(nil, 1)
+		assert.ErrorContains(t, err, "no vectors to fit")
+
+		_, err = FitPCA([][]float64{{1, 2}}, 3)
+		assert.ErrorContains(t, err, "exceeds the vector dimension")
+
+		_, err = FitPCA([][]float64{{1, 2}, {1}}, 1)
+		assert.ErrorContains(t, err, "vector 1 has dimension 1, need 2")
+	})
+
+	t.Run("rank below dimension", func(t *testing.T) {
+		// two vectors only vary along a single direction once centered
+		_, err := FitPCA([][]float64{{1, 2, 3}, {2, 4, 6}}, 2)
+		assert.ErrorContains(t, err, "the sample has rank 1, below the target dimension 2")
+
+		_, err = FitPCA([][]float64{{1, 2}, {1, 2}}, 1)
+		assert.ErrorContains(t, err, "the sample has rank 0, below the target dimension 1")
+	})
+
+	t.Run("principal components", func(t *testing.T) {
+		// the sample varies the most along (1, 2, 0), then along (0, 0, 1)
+		var vectors [][]float64
+		for i := -5; i <= 5; i++ {
+			for _, z := range []float64{-1, 1} {
+				x := float64(i)
+				vectors = append(vectors, []float64{x + 10, 2*x + 10, z + 10})
+			}
+		}
+
+		p, err := FitPCA(vectors, 2)
+		require.NoError(t, err)
+		assert.InDeltaSlice(t, []float64{10, 10, 10}, p.Mean, 1e-9)
+		require.Len(t, p.Components, 2)
+		assert.InDeltaSlice(t, []float64{1 / math.Sqrt(5), 2 / math.Sqrt(5), 0}, p.Components[0], 1e-6)
+		assert.InDeltaSlice(t, []float64{0, 0, 1}, p.Components[1], 1e-6)
+
+		projected, err := p.Project([]float64{11, 12, 9})
+		require.NoError(t, err)
+		assert.InDeltaSlice(t, []float64{math.Sqrt(5), -1}, projected, 1e-6)
+
+		// a fitted projection can be persisted as JSON
+		data, err := json.Marshal(p)
+		require.NoError(t, err)
+		var restored Projection
+		require.NoError(t, json.Unmarshal(data, &restored))
+		assert.Equal(t, p, &restored)
+	})
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+const (
+	defaultAttachmentPipeline    = "eino_attachment"
+	defaultAttachmentField       = "data"
+	defaultAttachmentTargetField = "attachment"
+
+	attachmentKey = "_es9_attachment"
+)
+
+// AttachmentConfig indexes binary documents, e.g. PDF or office files, with the attachment ingest processor,
+// which extracts their text and metadata with Apache Tika on the Elasticsearch side.
+// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/attachment.html
+type AttachmentConfig struct {
+	// Pipeline is the name of the ingest pipeline running the attachment processor, used by all the documents.
+	// Default is "eino_attachment".
+	Pipeline string `json:"pipeline"`
+	// CreatePipeline creates the pipeline in NewIndexer if it does not exist.
+	// Otherwise, the pipeline must be created beforehand.
+	CreatePipeline bool `json:"create_pipeline"`
+	// Field is the field holding the base64 binary data, set from the data attached by SetAttachment.
+	// Default is "data".
+	Field string `json:"field"`
+	// TargetField is the field holding the extracted attachment, e.g. "attachment.content" and "attachment.content_type".
+	// Default is "attachment".
+	TargetField string `json:"target_field"`
+	// Properties are the extracted properties, e.g. "content", "title", "author", "content_type" and "language".
+	// Default is all the properties.
+	Properties []string `json:"properties,omitempty"`
+	// IndexedChars limits the number of the extracted characters, -1 for no limit.
+	// Default is 0, the limit of Elasticsearch, 100000 characters.
+	IndexedChars int `json:"indexed_chars,omitempty"`
+	// ContentField, if set, receives a copy of the extracted content, e.g. the content field searched by the retriever.
+	ContentField string `json:"content_field,omitempty"`
+	// KeepBinary keeps the base64 binary data in the stored document.
+	// Default is false, the binary data is removed after the extraction to save space.
+	KeepBinary bool `json:"keep_binary,omitempty"`
+}
+
+// SetAttachment attaches the binary data of a file to the document, e.g. the bytes of a PDF,
+// sent base64 encoded in AttachmentConfig.Field to the attachment pipeline.
+func SetAttachment(doc *schema.Document, data []byte) *schema.Document {
+	if doc.MetaData == nil {
+		doc.MetaData = make(map[string]any)
+	}
+	doc.MetaData[attachmentKey] = data
+	return doc
+}
+
+// GetAttachment returns the binary data attached to the document by SetAttachment.
+func GetAttachment(doc *schema.Document) ([]byte, bool) {
+	if doc == nil || doc.MetaData == nil {
+		return nil, false
+	}
+	data, ok := doc.MetaData[attachmentKey].([]byte)
+	return data, ok
+}
+
+func (c *AttachmentConfig) setDefaults() {
+	if c.Pipeline == "" {
+		c.Pipeline = defaultAttachmentPipeline
+	}
+	if c.Field == "" {
+		c.Field = defaultAttachmentField
+	}
+	if c.TargetField == "" {
+		c.TargetField = defaultAttachmentTargetField
+	}
+}
+
+// pipelineBody returns the definition of the attachment pipeline.
+// Documents without binary data pass the pipeline unchanged.
+func (c *AttachmentConfig) pipelineBody() map[string]any {
+	attachment := map[string]any{
+		"field":          c.Field,
+		"target_field":   c.TargetField,
+		"ignore_missing": true,
+		"remove_binary":  !c.KeepBinary,
+	}
+	if len(c.Properties) > 0 {
+		attachment["properties"] = c.Properties
+	}
+	if c.IndexedChars != 0 {
+		attachment["indexed_chars"] = c.IndexedChars
+	}
+
+	processors := []map[string]any{{"attachment": attachment}}
+	if c.ContentField != "" {
+		processors = append(processors, map[string]any{
+			"set": map[string]any{
+				"field":              c.ContentField,
+				"copy_from":          c.TargetField + ".content",
+				"ignore_empty_value": true,
+				"if":                 fmt.Sprintf("ctx.%s?.content != null", c.TargetField),
+			},
+		})
+	}
+
+	return map[string]any{
+		"description": "extracts the content of binary documents, created by the eino es9 indexer",
+		"processors":  processors,
+	}
+}
+
+// ensureAttachmentPipeline creates the attachment pipeline if it does not exist.
+func ensureAttachmentPipeline(ctx context.Context, client *elasticsearch.Client, conf *AttachmentConfig) error {
+	getRes, err := esapi.IngestGetPipelineRequest{PipelineID: conf.Pipeline}.Do(ctx, client)
+	if err != nil {
+		return fmt.Errorf("[NewIndexer] check pipeline existence failed, %w", err)
+	}
+	if getRes.Body != nil {
+		_ = getRes.Body.Close()
+	}
+	if getRes.StatusCode != 404 {
+		if getRes.IsError() {
+			return fmt.Errorf("[NewIndexer] check pipeline existence failed, response: %s", getRes.String())
+		}
+		return nil
+	}
+
+	body, err := json.Marshal(conf.pipelineBody())
+	if err != nil {
+		return fmt.Errorf("[NewIndexer] marshal pipeline failed, %w", err)
+	}
+	putRes, err := esapi.IngestPutPipelineRequest{PipelineID: conf.Pipeline, Body: bytes.NewReader(body)}.Do(ctx, client)
+	if err != nil {
+		return fmt.Errorf("[NewIndexer] create pipeline failed, %w", err)
+	}
+	if putRes.Body != nil {
+		_ = putRes.Body.Close()
+	}
+	if putRes.IsError() {
+		return fmt.Errorf("[NewIndexer] create pipeline failed, response: %s", putRes.String())
+	}
+	return nil
+}
+
+// setAttachmentField sets the base64 binary data attached to the document to the source field of the pipeline,
+// unless DocumentToFields already set the field.
+func (c *AttachmentConfig) setAttachmentField(doc *schema.Document, fields map[string]any) {
+	if _, found := fields[c.Field]; found {
+		return
+	}
+	if data, ok := GetAttachment(doc); ok && len(data) > 0 {
+		fields[c.Field] = base64.StdEncoding.EncodeToString(data)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/elastic/go-elasticsearch/v9/esutil"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestAttachment(t *testing.T) {
+	PatchConvey("test attachment", t, func() {
+		ctx := context.Background()
+		docToFields := func(ctx context.Context, doc *schema.Document) (map[string]FieldValue, error) {
+			return map[string]FieldValue{"title": {Value: doc.ID}}, nil
+		}
+
+		PatchConvey("test set and get attachment", func() {
+			doc := SetAttachment(&schema.Document{ID: "1"}, []byte("pdf"))
+			data, ok := GetAttachment(doc)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(string(data), convey.ShouldEqual, "pdf")
+
+			_, ok = GetAttachment(&schema.Document{ID: "2"})
+			convey.So(ok, convey.ShouldBeFalse)
+		})
+
+		PatchConvey("test pipeline body", func() {
+			conf := &AttachmentConfig{Properties: []string{"content"}, IndexedChars: -1, ContentField: "content"}
+			conf.setDefaults()
+			convey.So(conf.Pipeline, convey.ShouldEqual, defaultAttachmentPipeline)
+
+			b, err := json.Marshal(conf.pipelineBody())
+			convey.So(err, convey.ShouldBeNil)
+			var body struct {
+				Processors []map[string]map[string]any `json:"processors"`
+			}
+			convey.So(json.Unmarshal(b, &body), convey.ShouldBeNil)
+			convey.So(len(body.Processors), convey.ShouldEqual, 2)
+			attachment := body.Processors[0]["attachment"]
+			convey.So(attachment["field"], convey.ShouldEqual, "data")
+			convey.So(attachment["target_field"], convey.ShouldEqual, "attachment")
+			convey.So(attachment["remove_binary"], convey.ShouldEqual, true)
+			convey.So(attachment["indexed_chars"], convey.ShouldEqual, -1)
+			convey.So(body.Processors[1]["set"]["copy_from"], convey.ShouldEqual, "attachment.content")
+		})
+
+		PatchConvey("test create pipeline", func() {
+			mockT := &mockTransportCreation{}
+			client, _ := elasticsearch.NewClient(elasticsearch.Config{Transport: mockT})
+			idx, err := NewIndexer(ctx, &IndexerConfig{
+				Client:           client,
+				Index:            "test-index",
+				DocumentToFields: docToFields,
+				Attachment:       &AttachmentConfig{Pipeline: "pdf", CreatePipeline: true},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(idx.config.Attachment.Field, convey.ShouldEqual, defaultAttachmentField)
+			convey.So(mockT.pipelineChecked, convey.ShouldBeTrue)
+			convey.So(mockT.createCalled, convey.ShouldBeTrue)
+			convey.So(string(mockT.createBody), convey.ShouldContainSubstring, `"attachment"`)
+		})
+
+		PatchConvey("test pipeline exists", func() {
+			mockT := &mockTransportCreation{
+				pipelineResponse: &http.Response{
+					StatusCode: 200,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"pdf":{}}`))),
+					Header:     http.Header{"X-Elastic-Product": []string{"Elasticsearch"}},
+				},
+			}
+			client, _ := elasticsearch.NewClient(elasticsearch.Config{Transport: mockT})
+			_, err := NewIndexer(ctx, &IndexerConfig{
+				Client:           client,
+				Index:            "test-index",
+				DocumentToFields: docToFields,
+				Attachment:       &AttachmentConfig{Pipeline: "pdf", CreatePipeline: true},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(mockT.pipelineChecked, convey.ShouldBeTrue)
+			convey.So(mockT.createCalled, convey.ShouldBeFalse)
+		})
+
+		PatchConvey("test create pipeline fails", func() {
+			mockT := &mockTransportCreation{
+				createResponse: &http.Response{
+					StatusCode: 400,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"error": "failed"}`))),
+					Header:     http.Header{"X-Elastic-Product": []string{"Elasticsearch"}},
+				},
+			}
+			client, _ := elasticsearch.NewClient(elasticsearch.Config{Transport: mockT})
+			_, err := NewIndexer(ctx, &IndexerConfig{
+				Client:           client,
+				Index:            "test-index",
+				DocumentToFields: docToFields,
+				Attachment:       &AttachmentConfig{CreatePipeline: true},
+			})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "create pipeline failed")
+		})
+
+		PatchConvey("test bulkAdd with attachment", func() {
+			bi, err := esutil.NewBulkIndexer(esutil.BulkIndexerConfig{})
+			convey.So(err, convey.ShouldBeNil)
+			var biConfig esutil.BulkIndexerConfig
+			Mock(esutil.NewBulkIndexer).To(func(cfg esutil.BulkIndexerConfig) (esutil.BulkIndexer, error) {
+				biConfig = cfg
+				return bi, nil
+			}).Build()
+			var items []esutil.BulkIndexerItem
+			Mock(GetMethod(bi, "Add")).To(func(ctx context.Context, item esutil.BulkIndexerItem) error {
+				items = append(items, item)
+				return nil
+			}).Build()
+			Mock(GetMethod(bi, "Close")).Return(nil).Build()
+
+			conf := &AttachmentConfig{}
+			conf.setDefaults()
+			i := &Indexer{config: &IndexerConfig{
+				Index:            "test-index",
+				BatchSize:        defaultBatchSize,
+				DocumentToFields: docToFields,
+				Attachment:       conf,
+			}}
+			docs := []*schema.Document{
+				SetAttachment(&schema.Document{ID: "1"}, []byte("%PDF-1.7")),
+				{ID: "2", Content: "plain text"},
+			}
+			convey.So(i.bulkAdd(ctx, docs, &indexer.Options{}), convey.ShouldBeNil)
+			convey.So(biConfig.Pipeline, convey.ShouldEqual, defaultAttachmentPipeline)
+			convey.So(len(items), convey.ShouldEqual, 2)
+
+			var mp map[string]any
+			b, _ := io.ReadAll(items[0].Body)
+			convey.So(json.Unmarshal(b, &mp), convey.ShouldBeNil)
+			convey.So(mp["data"], convey.ShouldEqual, base64.StdEncoding.EncodeToString([]byte("%PDF-1.7")))
+
+			mp = nil
+			b, _ = io.ReadAll(items[1].Body)
+			convey.So(json.Unmarshal(b, &mp), convey.ShouldBeNil)
+			_, found := mp["data"]
+			convey.So(found, convey.ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v9/esapi"
+)
+
+// Script is a painless script used by UpdateByQuery.
+type Script struct {
+	// Source is the script source, e.g. "ctx._source.tags.add(params.tag)".
+	Source string `json:"source"`
+	// Lang is the script language. Default is painless.
+	Lang string `json:"lang,omitempty"`
+	// Params are the parameters of the script.
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// ByQueryResult is the result of DeleteByQuery and UpdateByQuery.
+// When conflict retries are enabled, the counters are accumulated over all attempts,
+// except VersionConflicts, which is the number of conflicts left by the last attempt.
+type ByQueryResult struct {
+	Took             int64             `json:"took"`
+	TimedOut         bool              `json:"timed_out"`
+	Total            int64             `json:"total"`
+	Deleted          int64             `json:"deleted"`
+	Updated          int64             `json:"updated"`
+	Batches          int64             `json:"batches"`
+	VersionConflicts int64             `json:"version_conflicts"`
+	Noops            int64             `json:"noops"`
+	Failures         []json.RawMessage `json:"failures"`
+	// Attempts is the number of requests sent.
+	Attempts int `json:"-"`
+}
+
+// ByQueryOption configures DeleteByQuery and UpdateByQuery.
+type ByQueryOption func(o *byQueryOptions)
+
+type byQueryOptions struct {
+	slices            string
+	proceedOnConflict bool
+	conflictRetries   int
+	retryBackoff      time.Duration
+	refresh           *bool
+	maxDocs           *int
+	requestsPerSecond *int
+	routing           []string
+}
+
+// WithSlices parallelizes the operation with the given number of slices.
+// Use WithAutoSlices to let Elasticsearch choose.
+func WithSlices(n int) ByQueryOption {
+	return func(o *byQueryOptions) {
+		o.slices = strconv.Itoa(n)
+	}
+}
+
+// WithAutoSlices lets Elasticsearch choose the number of slices, usually one per shard.
+func WithAutoSlices() ByQueryOption {
+	return func(o *byQueryOptions) {
+		o.slices = "auto"
+	}
+}
+
+// WithProceedOnConflict counts version conflicts instead of aborting the operation on the first one.
+func WithProceedOnConflict() ByQueryOption {
+	return func(o *byQueryOptions) {
+		o.proceedOnConflict = true
+	}
+}
+
+// WithConflictRetries re-runs the operation up to maxRetries times while it reports version conflicts,
+// waiting backoff between attempts. It implies WithProceedOnConflict.
+// Re-running UpdateByQuery updates every matching document again, so the script should be idempotent,
+// or the query should exclude documents which are already updated.
+func WithConflictRetries(maxRetries int, backoff time.Duration) ByQueryOption {
+	return func(o *byQueryOptions) {
+		o.proceedOnConflict = true
+		o.conflictRetries = maxRetries
+		o.retryBackoff = backoff
+	}
+}
+
+// WithRefresh refreshes the affected shards after the operation completes.
+func WithRefresh(refresh bool) ByQueryOption {
+	return func(o *byQueryOptions) {
+		o.refresh = &refresh
+	}
+}
+
+// WithMaxDocs limits the number of documents processed by a single attempt.
+func WithMaxDocs(n int) ByQueryOption {
+	return func(o *byQueryOptions) {
+		o.maxDocs = &n
+	}
+}
+
+// WithRequestsPerSecond throttles the operation. -1 disables throttling.
+func WithRequestsPerSecond(n int) ByQueryOption {
+	return func(o *byQueryOptions) {
+		o.requestsPerSecond = &n
+	}
+}
+
+// WithRouting routes the operation to the shards of the given routing values.
+func WithRouting(routing ...string) ByQueryOption {
+	return func(o *byQueryOptions) {
+		o.routing = routing
+	}
+}
+
+// DeleteByQuery deletes the documents matching the query from the index, e.g. to offboard a tenant:
+//
+//	res, err := idx.DeleteByQuery(ctx, map[string]any{"term": map[string]any{"tenant_id": "t1"}},
+//		es9.WithAutoSlices(), es9.WithConflictRetries(3, time.Second))
+func (i *Indexer) DeleteByQuery(ctx context.Context, query map[string]any, opts ...ByQueryOption) (*ByQueryResult, error) {
+	if len(query) == 0 {
+		return nil, fmt.Errorf("[DeleteByQuery] query not provided")
+	}
+
+	body, err := json.Marshal(map[string]any{"query": query})
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteByQuery] marshal request body failed, %w", err)
+	}
+
+	o := getByQueryOptions(opts)
+	res, err := doByQuery(ctx, o, func(ctx context.Context) (*esapi.Response, error) {
+		req := esapi.DeleteByQueryRequest{
+			Index:             []string{i.config.Index},
+			Body:              bytes.NewReader(body),
+			Conflicts:         o.conflicts(),
+			Refresh:           o.refresh,
+			MaxDocs:           o.maxDocs,
+			RequestsPerSecond: o.requestsPerSecond,
+			Routing:           o.routing,
+		}
+		if o.slices != "" {
+			req.Slices = o.slices
+		}
+		return req.Do(ctx, i.client)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[DeleteByQuery] %w", err)
+	}
+
+	return res, nil
+}
+
+// UpdateByQuery runs the script on the documents matching the query, e.g. to re-tag documents:
+//
+//	res, err := idx.UpdateByQuery(ctx, map[string]any{"term": map[string]any{"tag": "old"}},
+//		&es9.Script{Source: "ctx._source.tag = params.tag", Params: map[string]any{"tag": "new"}},
+//		es9.WithAutoSlices(), es9.WithConflictRetries(3, time.Second))
+//
+// A nil script only reindexes the documents in place, which picks up mapping changes.
+func (i *Indexer) UpdateByQuery(ctx context.Context, query map[string]any, script *Script, opts ...ByQueryOption) (*ByQueryResult, error) {
+	if len(query) == 0 {
+		return nil, fmt.Errorf("[UpdateByQuery] query not provided")
+	}
+
+	reqBody := map[string]any{"query": query}
+	if script != nil {
+		reqBody["script"] = script
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateByQuery] marshal request body failed, %w", err)
+	}
+
+	o := getByQueryOptions(opts)
+	res, err := doByQuery(ctx, o, func(ctx context.Context) (*esapi.Response, error) {
+		req := esapi.UpdateByQueryRequest{
+			Index:             []string{i.config.Index},
+			Body:              bytes.NewReader(body),
+			Conflicts:         o.conflicts(),
+			Refresh:           o.refresh,
+			MaxDocs:           o.maxDocs,
+			RequestsPerSecond: o.requestsPerSecond,
+			Routing:           o.routing,
+		}
+		if o.slices != "" {
+			req.Slices = o.slices
+		}
+		return req.Do(ctx, i.client)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[UpdateByQuery] %w", err)
+	}
+
+	return res, nil
+}
+
+func getByQueryOptions(opts []ByQueryOption) *byQueryOptions {
+	o := &byQueryOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *byQueryOptions) conflicts() string {
+	if o.proceedOnConflict {
+		return "proceed"
+	}
+	return ""
+}
+
+func doByQuery(ctx context.Context, o *byQueryOptions, do func(ctx context.Context) (*esapi.Response, error)) (*ByQueryResult, error) {
+	total := &ByQueryResult{}
+	for {
+		res, err := doByQueryOnce(ctx, do)
+		if err != nil {
+			return nil, err
+		}
+
+		total.Attempts++
+		total.Took += res.Took
+		total.TimedOut = total.TimedOut || res.TimedOut
+		total.Total += res.Total
+		total.Deleted += res.Deleted
+		total.Updated += res.Updated
+		total.Batches += res.Batches
+		total.Noops += res.Noops
+		total.Failures = append(total.Failures, res.Failures...)
+		total.VersionConflicts = res.VersionConflicts
+
+		if res.VersionConflicts == 0 || total.Attempts > o.conflictRetries {
+			return total, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(o.retryBackoff):
+		}
+	}
+}
+
+func doByQueryOnce(ctx context.Context, do func(ctx context.Context) (*esapi.Response, error)) (*ByQueryResult, error) {
+	res, err := do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("request failed, %w", err)
+	}
+	defer func() {
+		if res.Body != nil {
+			_ = res.Body.Close()
+		}
+	}()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("request failed, response: %s", res.String())
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body failed, %w", err)
+	}
+
+	result := &ByQueryResult{}
+	if err = json.Unmarshal(b, result); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed, %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/bytedance/mockey"
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestDeleteByQuery(t *testing.T) {
+	PatchConvey("test DeleteByQuery", t, func() {
+		ctx := context.Background()
+		query := map[string]any{"term": map[string]any{"tenant_id": "t1"}}
+
+		PatchConvey("test query not provided", func() {
+			i := &Indexer{config: &IndexerConfig{Index: "mock_index"}}
+			res, err := i.DeleteByQuery(ctx, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(res, convey.ShouldBeNil)
+		})
+
+		PatchConvey("test success", func() {
+			mockT := &mockTransportByQuery{responses: []string{`{"took":10,"total":3,"deleted":3,"batches":1}`}}
+			client, _ := elasticsearch.NewClient(elasticsearch.Config{Transport: mockT})
+			i := &Indexer{client: client, config: &IndexerConfig{Index: "mock_index"}}
+
+			res, err := i.DeleteByQuery(ctx, query, WithAutoSlices(), WithRefresh(true))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(res.Deleted, convey.ShouldEqual, 3)
+			convey.So(res.Attempts, convey.ShouldEqual, 1)
+			convey.So(len(mockT.requests), convey.ShouldEqual, 1)
+
+			req := mockT.requests[0]
+			convey.So(req.URL.Path, convey.ShouldEqual, "/mock_index/_delete_by_query")
+			convey.So(req.URL.Query().Get("slices"), convey.ShouldEqual, "auto")
+			convey.So(req.URL.Query().Get("refresh"), convey.ShouldEqual, "true")
+			convey.So(req.URL.Query().Get("conflicts"), convey.ShouldEqual, "")
+
+			var body map[string]any
+			convey.So(json.Unmarshal(mockT.bodies[0], &body), convey.ShouldBeNil)
+			convey.So(body["query"], convey.ShouldResemble, map[string]any{"term": map[string]any{"tenant_id": "t1"}})
+		})
+
+		PatchConvey("test conflict retries", func() {
+			mockT := &mockTransportByQuery{responses: []string{
+				`{"total":5,"deleted":3,"version_conflicts":2}`,
+				`{"total":2,"deleted":1,"version_conflicts":1}`,
+				`{"total":1,"deleted":1,"version_conflicts":0}`,
+			}}
+			client, _ := elasticsearch.NewClient(elasticsearch.Config{Transport: mockT})
+			i := &Indexer{client: client, config: &IndexerConfig{Index: "mock_index"}}
+
+			res, err := i.DeleteByQuery(ctx, query, WithSlices(4), WithConflictRetries(3, time.Millisecond))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(res.Attempts, convey.ShouldEqual, 3)
+			convey.So(res.Deleted, convey.ShouldEqual, 5)
+			convey.So(res.VersionConflicts, convey.ShouldEqual, 0)
+			convey.So(mockT.requests[0].URL.Query().Get("conflicts"), convey.ShouldEqual, "proceed")
+			convey.So(mockT.requests[0].URL.Query().Get("slices"), convey.ShouldEqual, "4")
+		})
+
+		PatchConvey("test conflict retries exhausted", func() {
+			mockT := &mockTransportByQuery{responses: []string{
+				`{"total":5,"deleted":3,"version_conflicts":2}`,
+				`{"total":2,"deleted":1,"version_conflicts":1}`,
+			}}
+			client, _ := elasticsearch.NewClient(elasticsearch.Config{Transport: mockT})
+			i := &Indexer{client: client, config: &IndexerConfig{Index: "mock_index"}}
+
+			res, err := i.DeleteByQuery(ctx, query, WithConflictRetries(1, time.Millisecond))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(res.Attempts, convey.ShouldEqual, 2)
+			convey.So(res.Deleted, convey.ShouldEqual, 4)
+			convey.So(res.VersionConflicts, convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test error response", func() {
+			mockT := &mockTransportByQuery{statusCode: 400, responses: []string{`{"error":"bad query"}`}}
+			client, _ := elasticsearch.NewClient(elasticsearch.Config{Transport: mockT})
+			i := &Indexer{client: client, config: &IndexerConfig{Index: "mock_index"}}
+
+			res, err := i.DeleteByQuery(ctx, query)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "[DeleteByQuery]")
+			convey.So(res, convey.ShouldBeNil)
+		})
+	})
+}
+
+func TestUpdateByQuery(t *testing.T) {
+	PatchConvey("test UpdateByQuery", t, func() {
+		ctx := context.Background()
+		query := map[string]any{"term": map[string]any{"tag": "old"}}
+
+		PatchConvey("test query not provided", func() {
+			i := &Indexer{config: &IndexerConfig{Index: "mock_index"}}
+			res, err := i.UpdateByQuery(ctx, nil, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(res, convey.ShouldBeNil)
+		})
+
+		PatchConvey("test success with script", func() {
+			mockT := &mockTransportByQuery{responses: []string{`{"total":2,"updated":2}`}}
+			client, _ := elasticsearch.NewClient(elasticsearch.Config{Transport: mockT})
+			i := &Indexer{client: client, config: &IndexerConfig{Index: "mock_index"}}
+
+			script := &Script{Source: "ctx._source.tag = params.tag", Params: map[string]any{"tag": "new"}}
+			res, err := i.UpdateByQuery(ctx, query, script, WithProceedOnConflict(), WithMaxDocs(100))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(res.Updated, convey.ShouldEqual, 2)
+
+			req := mockT.requests[0]
+			convey.So(req.URL.Path, convey.ShouldEqual, "/mock_index/_update_by_query")
+			convey.So(req.URL.Query().Get("conflicts"), convey.ShouldEqual, "proceed")
+			convey.So(req.URL.Query().Get("max_docs"), convey.ShouldEqual, "100")
+
+			var body map[string]any
+			convey.So(json.Unmarshal(mockT.bodies[0], &body), convey.ShouldBeNil)
+			convey.So(body["script"], convey.ShouldResemble, map[string]any{
+				"source": "ctx._source.tag = params.tag",
+				"params": map[string]any{"tag": "new"},
+			})
+		})
+	})
+}
+
+// mockTransportByQuery replies to by-query requests with the given responses in order.
+type mockTransportByQuery struct {
+	statusCode int
+	responses  []string
+	requests   []*http.Request
+	bodies     [][]byte
+}
+
+func (m *mockTransportByQuery) RoundTrip(req *http.Request) (*http.Response, error) {
+	header := http.Header{"X-Elastic-Product": []string{"Elasticsearch"}}
+	if req.Method == "GET" && req.URL.Path == "/" {
+		return &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"version":{"number":"9.0.0"}}`))),
+			Header:     header,
+		}, nil
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+	m.requests = append(m.requests, req)
+	m.bodies = append(m.bodies, body)
+
+	statusCode := m.statusCode
+	if statusCode == 0 {
+		statusCode = 200
+	}
+	resp := m.responses[len(m.requests)-1]
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(bytes.NewReader([]byte(resp))),
+		Header:     header,
+	}, nil
+}
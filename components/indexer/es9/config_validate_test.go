@@ -0,0 +1,57 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	elasticsearch "github.com/elastic/go-elasticsearch/v9"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+func TestIndexerConfig_Validate(t *testing.T) {
+	convey.Convey("test IndexerConfig.Validate", t, func() {
+		convey.Convey("valid", func() {
+			conf := &IndexerConfig{
+				Client: &elasticsearch.Client{},
+				DocumentToFields: func(ctx context.Context, doc *schema.Document) (map[string]FieldValue, error) {
+					return nil, nil
+				},
+			}
+			convey.So(conf.Validate(), convey.ShouldBeNil)
+			// the defaults are not filled in
+			convey.So(conf.BatchSize, convey.ShouldEqual, 0)
+		})
+
+		convey.Convey("invalid", func() {
+			err := (&IndexerConfig{BatchSize: -1, Attachment: &AttachmentConfig{IndexedChars: -2}}).Validate()
+			report, ok := err.(*configcheck.Report)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(report.Config, convey.ShouldEqual, "es9.IndexerConfig")
+			convey.So(report.Issues, convey.ShouldResemble, []configcheck.Issue{
+				{Field: "Client", Message: "es client not provided"},
+				{Field: "BatchSize", Message: "must not be negative, got -1"},
+				{Field: "DocumentToFields", Message: "DocumentToFields method not provided"},
+				{Field: "Attachment.IndexedChars", Message: "must not be less than -1, got -2"},
+			})
+		})
+	})
+}
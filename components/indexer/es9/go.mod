@@ -5,6 +5,7 @@ go 1.23.0
 require (
 	github.com/bytedance/mockey v1.2.13
 	github.com/cloudwego/eino v0.6.0
+	github.com/cloudwego/eino-ext/libs/configcheck v0.1.0
 	github.com/elastic/go-elasticsearch/v9 v9.0.0
 	github.com/smartystreets/goconvey v1.8.1
 )
@@ -56,6 +56,13 @@ type IndexerConfig struct {
 	// 1. The document content itself needs to be vectorized and does not have a pre-computed vector (see [schema.Document.Vector]).
 	// 2. Additional fields (other than content) need to be vectorized.
 	Embedding embedding.Embedder
+	// EnableCallbacks is false to let the graph running the indexer report its callbacks.
+	// Default is true.
+	EnableCallbacks *bool `json:"enable_callbacks,omitempty"`
+	// Attachment, if provided, indexes all the documents through the attachment ingest pipeline,
+	// extracting the text of the binary data attached to the documents by SetAttachment, e.g. PDF or office files.
+	// Optional. Default is nil, documents are indexed without a pipeline.
+	Attachment *AttachmentConfig `json:"attachment,omitempty"`
 }
 
 // IndexSpec allows defining detailed index settings for auto-creation.
@@ -142,6 +149,15 @@ func NewIndexer(ctx context.Context, conf *IndexerConfig) (*Indexer, error) {
 		}
 	}
 
+	if conf.Attachment != nil {
+		conf.Attachment.setDefaults()
+		if conf.Attachment.CreatePipeline {
+			if err := ensureAttachmentPipeline(ctx, conf.Client, conf.Attachment); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return &Indexer{
 		client: conf.Client,
 		config: conf,
@@ -152,9 +168,12 @@ func NewIndexer(ctx context.Context, conf *IndexerConfig) (*Indexer, error) {
 // It returns the list of IDs for the stored documents or an error.
 func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
 	ctx = callbacks.EnsureRunInfo(ctx, i.GetType(), components.ComponentOfIndexer)
-	ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{Docs: docs})
+	cbEnabled := i.IsCallbacksEnabled()
+	if cbEnabled {
+		ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{Docs: docs})
+	}
 	defer func() {
-		if err != nil {
+		if err != nil && cbEnabled {
 			callbacks.OnError(ctx, err)
 		}
 	}()
@@ -169,17 +188,23 @@ func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...in
 
 	ids = iter(docs, func(t *schema.Document) string { return t.ID })
 
-	callbacks.OnEnd(ctx, &indexer.CallbackOutput{IDs: ids})
+	if cbEnabled {
+		callbacks.OnEnd(ctx, &indexer.CallbackOutput{IDs: ids})
+	}
 
 	return ids, nil
 }
 
 func (i *Indexer) bulkAdd(ctx context.Context, docs []*schema.Document, options *indexer.Options) error {
 	emb := options.Embedding
-	bi, err := esutil.NewBulkIndexer(esutil.BulkIndexerConfig{
+	biConfig := esutil.BulkIndexerConfig{
 		Index:  i.config.Index,
 		Client: i.client,
-	})
+	}
+	if i.config.Attachment != nil {
+		biConfig.Pipeline = i.config.Attachment.Pipeline
+	}
+	bi, err := esutil.NewBulkIndexer(biConfig)
 	if err != nil {
 		return err
 	}
@@ -256,6 +281,9 @@ func (i *Indexer) bulkAdd(ctx context.Context, docs []*schema.Document, options
 				embSize++
 			}
 		}
+		if i.config.Attachment != nil {
+			i.config.Attachment.setAttachmentField(doc, rawFields)
+		}
 
 		if embSize > i.config.BatchSize {
 			return fmt.Errorf("[bulkAdd] needEmbeddingFields length over batch size, batch size=%d, got size=%d",
@@ -333,9 +361,9 @@ func (i *Indexer) GetType() string {
 	return typ
 }
 
-// IsCallbacksEnabled checks if callbacks are enabled for this indexer.
+// IsCallbacksEnabled reports whether the indexer reports its callbacks itself, see IndexerConfig.EnableCallbacks.
 func (i *Indexer) IsCallbacksEnabled() bool {
-	return true
+	return i.config.EnableCallbacks == nil || *i.config.EnableCallbacks
 }
 
 type tuple struct {
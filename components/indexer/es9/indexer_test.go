@@ -23,9 +23,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/schema"
@@ -397,6 +399,35 @@ func TestStore(t *testing.T) {
 			convey.So(ids[0], convey.ShouldEqual, "1")
 		})
 
+		PatchConvey("test callbacks disabled", func() {
+			var started, ended int
+			handler := callbacks.NewHandlerBuilder().
+				OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+					started++
+					return ctx
+				}).
+				OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+					ended++
+					return ctx
+				}).Build()
+			cbCtx := callbacks.InitCallbacks(ctx, nil, handler)
+
+			enableCallbacks := false
+			idx.config.EnableCallbacks = &enableCallbacks
+			ids, err := idx.Store(cbCtx, []*schema.Document{{ID: "1", Content: "test"}})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids, convey.ShouldResemble, []string{"1"})
+			convey.So(started, convey.ShouldEqual, 0)
+			convey.So(ended, convey.ShouldEqual, 0)
+			convey.So(idx.IsCallbacksEnabled(), convey.ShouldBeFalse)
+
+			idx.config.EnableCallbacks = nil
+			_, err = idx.Store(cbCtx, []*schema.Document{{ID: "1", Content: "test"}})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(started, convey.ShouldEqual, 1)
+			convey.So(ended, convey.ShouldEqual, 1)
+		})
+
 		PatchConvey("test validation error in bulkAdd", func() {
 			// Trigger error in bulkAdd by providing embedding but no embedding implementation
 			// To do this, we need to return a field with EmbedKey
@@ -472,6 +503,9 @@ type mockTransportCreation struct {
 	existsCalled   bool
 	createCalled   bool
 	createBody     []byte
+
+	pipelineResponse *http.Response
+	pipelineChecked  bool
 }
 
 func (m *mockTransportCreation) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -482,6 +516,17 @@ func (m *mockTransportCreation) RoundTrip(req *http.Request) (*http.Response, er
 			Header:     http.Header{"X-Elastic-Product": []string{"Elasticsearch"}},
 		}, nil
 	}
+	if req.Method == "GET" && strings.HasPrefix(req.URL.Path, "/_ingest/pipeline/") {
+		m.pipelineChecked = true
+		if m.pipelineResponse != nil {
+			return m.pipelineResponse, nil
+		}
+		return &http.Response{
+			StatusCode: 404,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{}`))),
+			Header:     http.Header{"X-Elastic-Product": []string{"Elasticsearch"}},
+		}, nil
+	}
 	if req.Method == "HEAD" {
 		m.existsCalled = true
 		if m.existsResponse != nil {
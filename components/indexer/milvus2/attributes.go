@@ -0,0 +1,43 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/cloudwego/eino-ext/libs/milvus2/spanattr"
+)
+
+// spanAttributeKeys maps the callback extra keys to the OpenTelemetry attribute keys, in the order of SpanAttributes.
+var spanAttributeKeys = []spanattr.Key{
+	{Extra: CallbackExtraKeyCollection, Attr: "db.collection.name"},
+	{Extra: CallbackExtraKeyPartition, Attr: "milvus.partition"},
+	{Extra: CallbackExtraKeyIndexType, Attr: "milvus.index_type"},
+	{Extra: CallbackExtraKeyMetricType, Attr: "milvus.metric_type"},
+	{Extra: CallbackExtraKeyUpsertCount, Attr: "milvus.upsert_count"},
+	{Extra: CallbackExtraKeyCollectionRowCount, Attr: "milvus.collection_row_count"},
+	{Extra: CallbackExtraKeyWALPending, Attr: "milvus.wal_pending"},
+	{Extra: CallbackExtraKeyFailedCount, Attr: "milvus.failed_count"},
+	{Extra: CallbackExtraKeyLatency, Attr: "milvus.latency_ms"},
+}
+
+// SpanAttributes converts the Extra of indexer.CallbackInput or indexer.CallbackOutput
+// into OpenTelemetry span attributes with the "db.system" attribute set to "milvus",
+// e.g. for the OnStart and OnEnd of a tracing callbacks handler. Unknown keys are ignored.
+func SpanAttributes(extra map[string]any) []attribute.KeyValue {
+	return spanattr.SpanAttributes(extra, spanAttributeKeys)
+}
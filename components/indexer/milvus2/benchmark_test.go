@@ -0,0 +1,138 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	benchmarkBatchSize = 10000
+	benchmarkDim       = 1024
+	benchmarkSparseNNZ = 128
+)
+
+func benchmarkDocs() []*schema.Document {
+	docs := make([]*schema.Document, benchmarkBatchSize)
+	for i := range docs {
+		docs[i] = &schema.Document{
+			ID:       fmt.Sprintf("doc-%d", i),
+			Content:  fmt.Sprintf("content of document %d", i),
+			MetaData: map[string]any{"source": "benchmark", "index": i},
+		}
+	}
+	return docs
+}
+
+func benchmarkVectors(rng *rand.Rand) [][]float64 {
+	vectors := make([][]float64, benchmarkBatchSize)
+	for i := range vectors {
+		vectors[i] = make([]float64, benchmarkDim)
+		for j := range vectors[i] {
+			vectors[i][j] = rng.Float64()
+		}
+	}
+	return vectors
+}
+
+func benchmarkSparseVector(rng *rand.Rand) map[int]float64 {
+	sv := make(map[int]float64, benchmarkSparseNNZ)
+	for len(sv) < benchmarkSparseNNZ {
+		sv[rng.Intn(250000)] = rng.Float64()
+	}
+	return sv
+}
+
+func BenchmarkDefaultDocumentConverter(b *testing.B) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+	docs := benchmarkDocs()
+	vectors := benchmarkVectors(rng)
+
+	b.Run("dense", func(b *testing.B) {
+		converter := defaultDocumentConverter(defaultScalarFields, &VectorConfig{VectorField: defaultVectorField}, nil, nil)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := converter(ctx, docs, vectors); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("float32", func(b *testing.B) {
+		f32Docs := make([]*schema.Document, len(docs))
+		for i, doc := range docs {
+			vec := make([]float32, benchmarkDim)
+			for j, v := range vectors[i] {
+				vec[j] = float32(v)
+			}
+			cp := *doc
+			cp.MetaData = map[string]any{"source": "benchmark", "index": i}
+			f32Docs[i] = WithFloat32Vector(&cp, vec)
+		}
+		converter := defaultDocumentConverter(defaultScalarFields, &VectorConfig{VectorField: defaultVectorField}, nil, nil)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := converter(ctx, f32Docs, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("dense and sparse", func(b *testing.B) {
+		sparseDocs := make([]*schema.Document, len(docs))
+		for i, doc := range docs {
+			cp := *doc
+			cp.MetaData = map[string]any{"source": "benchmark", "index": i}
+			sparseDocs[i] = cp.WithSparseVector(benchmarkSparseVector(rng))
+		}
+		converter := defaultDocumentConverter(defaultScalarFields, &VectorConfig{VectorField: defaultVectorField},
+			&SparseVectorConfig{VectorField: defaultSparseVectorField, Method: SparseMethodPrecomputed}, nil)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := converter(ctx, sparseDocs, vectors); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkToMilvusSparseEmbedding(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	svs := make([]map[int]float64, benchmarkBatchSize)
+	for i := range svs {
+		svs[i] = benchmarkSparseVector(rng)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, sv := range svs {
+			if _, err := toMilvusSparseEmbedding(sv); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+// Validate checks the config without connecting to Milvus, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid. Unlike NewIndexer, it does not fill in the defaults of the config.
+func (c *IndexerConfig) Validate() error {
+	r := configcheck.New("milvus2.IndexerConfig")
+	r.Checkf(c.Client != nil || c.ClientConfig != nil, "Client", "milvus client or client config not provided")
+	r.Checkf(c.Vector != nil || c.Sparse != nil || len(c.ExtraVectors) > 0, "Vector",
+		"at least one vector field (dense or sparse) is required")
+
+	fields := map[string]string{}
+	addField := func(field, name string) {
+		if other, ok := fields[name]; ok {
+			r.Addf(field, "field name %q is already used by %s", name, other)
+			return
+		}
+		fields[name] = field
+	}
+	addField("IDField", orDefault(c.IDField, defaultIDField))
+	addField("ContentField", orDefault(c.ContentField, defaultContentField))
+	addField("MetadataField", orDefault(c.MetadataField, defaultMetadataField))
+
+	if c.Vector != nil {
+		addField("Vector.VectorField", orDefault(c.Vector.VectorField, defaultVectorField))
+		configcheck.NonNegative(r, "Vector.Dimension", &c.Vector.Dimension)
+	}
+	if c.Sparse != nil {
+		addField("Sparse.VectorField", orDefault(c.Sparse.VectorField, defaultSparseVectorField))
+		method := c.Sparse.Method
+		if method == "" && c.Sparse.MetricType != "" && c.Sparse.MetricType != BM25 {
+			method = SparseMethodPrecomputed
+		}
+		switch method {
+		case "", SparseMethodAuto, SparseMethodPrecomputed:
+		default:
+			r.Addf("Sparse.Method", "unsupported sparse method %q", method)
+		}
+		r.Checkf(c.Sparse.Encoder == nil || method == SparseMethodPrecomputed, "Sparse.Encoder",
+			"requires method %s, got %s", SparseMethodPrecomputed, orDefault(method, SparseMethodAuto))
+	}
+	for idx, vc := range c.ExtraVectors {
+		field := fmt.Sprintf("ExtraVectors[%d]", idx)
+		if vc == nil {
+			r.Addf(field, "is nil")
+			continue
+		}
+		if vc.VectorField == "" {
+			r.Addf(field+".VectorField", "is required")
+		} else {
+			addField(field+".VectorField", vc.VectorField)
+		}
+		configcheck.NonNegative(r, field+".Dimension", &vc.Dimension)
+		r.Checkf(vc.Embedding != nil || vc.VectorProvider != nil, field, "either Embedding or VectorProvider is required")
+	}
+
+	switch c.ContentOverflowPolicy {
+	case "", ContentOverflowError, ContentOverflowTruncate, ContentOverflowSplit:
+	case ContentOverflowExternal:
+		r.Checkf(c.ExternalContentStore != nil, "ExternalContentStore",
+			"is required for overflow policy %q", c.ContentOverflowPolicy)
+	default:
+		r.Addf("ContentOverflowPolicy", "unsupported content overflow policy %q", c.ContentOverflowPolicy)
+	}
+	configcheck.NonNegative(r, "CollectionStatsInterval", &c.CollectionStatsInterval)
+	if c.WAL != nil {
+		r.Checkf(c.WAL.Dir != "", "WAL.Dir", "is required")
+	}
+	if c.RetryPolicy != nil {
+		configcheck.NonNegative(r, "RetryPolicy.MaxAttempts", &c.RetryPolicy.MaxAttempts)
+		configcheck.NonNegative(r, "RetryPolicy.InitialBackoff", &c.RetryPolicy.InitialBackoff)
+		configcheck.NonNegative(r, "RetryPolicy.MaxBackoff", &c.RetryPolicy.MaxBackoff)
+	}
+	return r.Err()
+}
+
+func orDefault[T comparable](v, def T) T {
+	var zero T
+	if v == zero {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestIndexerConfig_Validate(t *testing.T) {
+	convey.Convey("test IndexerConfig.Validate", t, func() {
+		mockEmb := &mockEmbedding{}
+
+		convey.Convey("test valid config is not modified", func() {
+			config := &IndexerConfig{
+				ClientConfig: &milvusclient.ClientConfig{Address: "localhost:19530"},
+				Vector:       &VectorConfig{Dimension: 128},
+			}
+			convey.So(config.Validate(), convey.ShouldBeNil)
+			convey.So(config.Collection, convey.ShouldEqual, "")
+			convey.So(config.Vector.MetricType, convey.ShouldEqual, MetricType(""))
+			convey.So(config.DocumentConverter, convey.ShouldBeNil)
+		})
+
+		convey.Convey("test all issues are reported", func() {
+			config := &IndexerConfig{
+				ContentField: "id",
+				Vector:       &VectorConfig{Dimension: 128, Embedding: mockEmb},
+				Sparse:       &SparseVectorConfig{Encoder: SparseEncoderFunc(nil)},
+				ExtraVectors: []*VectorConfig{nil, {VectorField: "vector", Embedding: mockEmb}},
+				WAL:          &WALConfig{},
+				RetryPolicy:  &RetryPolicy{MaxAttempts: -1},
+
+				ContentOverflowPolicy: ContentOverflowExternal,
+			}
+			err := config.Validate()
+			report, ok := err.(*configcheck.Report)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(report.Config, convey.ShouldEqual, "milvus2.IndexerConfig")
+			convey.So(report.Issues, convey.ShouldResemble, []configcheck.Issue{
+				{Field: "Client", Message: "milvus client or client config not provided"},
+				{Field: "ContentField", Message: `field name "id" is already used by IDField`},
+				{Field: "Sparse.Encoder", Message: "requires method Precomputed, got Auto"},
+				{Field: "ExtraVectors[0]", Message: "is nil"},
+				{Field: "ExtraVectors[1].VectorField", Message: `field name "vector" is already used by Vector.VectorField`},
+				{Field: "ExternalContentStore", Message: `is required for overflow policy "external"`},
+				{Field: "WAL.Dir", Message: "is required"},
+				{Field: "RetryPolicy.MaxAttempts", Message: "must not be negative, got -1"},
+			})
+		})
+	})
+}
@@ -28,4 +28,6 @@ const (
 	defaultIDField           = "id"
 	defaultMaxContentLen     = 65535
 	defaultMaxIDLen          = 512
+
+	defaultMetadataCompressionThreshold = 4096
 )
@@ -6,8 +6,13 @@ require (
 	github.com/bytedance/mockey v1.4.0
 	github.com/bytedance/sonic v1.14.1
 	github.com/cloudwego/eino v0.6.0
+	github.com/cloudwego/eino-ext/libs/configcheck v0.1.0
+	github.com/cloudwego/eino-ext/libs/milvus2 v0.2.0
 	github.com/milvus-io/milvus/client/v2 v2.6.1
+	github.com/milvus-io/milvus/pkg/v2 v2.6.3
 	github.com/smartystreets/goconvey v1.8.1
+	go.opentelemetry.io/otel v1.34.0
+	google.golang.org/grpc v1.71.0
 )
 
 require (
@@ -59,7 +64,6 @@ require (
 	github.com/lufia/plan9stats v0.0.0-20211012122336-39d0f177ccd0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/milvus-io/milvus-proto/go-api/v2 v2.6.3 // indirect
-	github.com/milvus-io/milvus/pkg/v2 v2.6.3 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
@@ -109,7 +113,6 @@ require (
 	go.etcd.io/etcd/server/v3 v3.5.10 // indirect
 	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc v0.59.0 // indirect
-	go.opentelemetry.io/otel v1.34.0 // indirect
 	go.opentelemetry.io/otel/exporters/otlp/otlptrace v1.20.0 // indirect
 	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc v1.20.0 // indirect
 	go.opentelemetry.io/otel/metric v1.34.0 // indirect
@@ -131,7 +134,6 @@ require (
 	google.golang.org/genproto v0.0.0-20250303144028-a0af3efb3deb // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20250303144028-a0af3efb3deb // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250227231956-55c901821b1e // indirect
-	google.golang.org/grpc v1.71.0 // indirect
 	google.golang.org/protobuf v1.36.5 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
@@ -18,9 +18,11 @@ package milvus2
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"sort"
+	"slices"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/cloudwego/eino/callbacks"
@@ -32,6 +34,8 @@ import (
 	"github.com/milvus-io/milvus/client/v2/entity"
 	"github.com/milvus-io/milvus/client/v2/index"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
+
+	"github.com/cloudwego/eino-ext/libs/milvus2/retry"
 )
 
 // IndexerConfig contains configuration for the Milvus2 indexer.
@@ -59,14 +63,39 @@ type IndexerConfig struct {
 	// Default: ConsistencyLevelBounded
 	ConsistencyLevel ConsistencyLevel
 
+	// IDField is the name of the primary key field storing the document ID.
+	// Default: "id"
+	IDField string
+
+	// ContentField is the name of the field storing the document content.
+	// Default: "content"
+	ContentField string
+
+	// MetadataField is the name of the JSON field storing the document metadata.
+	// Default: "metadata"
+	MetadataField string
+
 	// EnableDynamicSchema enables dynamic field support for flexible metadata.
 	// Default: false
 	EnableDynamicSchema bool
 
+	// CollectionProperties are the collection properties applied when the collection is created,
+	// e.g. PropertyMmapEnabled or PropertyCollectionTTLSeconds.
+	// They are not applied to existing collections, use Indexer.UpdateProperties instead.
+	// Optional.
+	CollectionProperties map[string]string
+
 	// Vector defines the configuration for dense vector index.
 	// Optional.
 	Vector *VectorConfig
 
+	// ExtraVectors defines additional dense vector fields stored in the same row,
+	// e.g. an image embedding next to the text embedding of Vector.
+	// Every field must set VectorField and either Embedding or VectorProvider.
+	// Their columns are appended after DocumentConverter unless it already returns a column of the same name.
+	// Optional.
+	ExtraVectors []*VectorConfig
+
 	// Sparse defines the configuration for sparse vector index.
 	// Optional.
 	Sparse *SparseVectorConfig
@@ -76,7 +105,10 @@ type IndexerConfig struct {
 	DocumentConverter func(ctx context.Context, docs []*schema.Document, vectors [][]float64) ([]column.Column, error)
 
 	// Embedding is the embedder for vectorization.
-	// Required.
+	// Without Embedding and Vector.VectorProvider, the documents must carry precomputed dense vectors,
+	// attached with schema.Document.WithDenseVector or WithFloat32Vector, which the default DocumentConverter checks
+	// for every document before the batch is stored.
+	// Optional.
 	Embedding embedding.Embedder
 
 	// Functions defines the Milvus built-in functions (e.g. BM25) to be added to the schema.
@@ -87,6 +119,59 @@ type IndexerConfig struct {
 	// Key is field name, value is a map of parameter key-value pairs.
 	// Optional.
 	FieldParams map[string]map[string]string
+
+	// MaxContentLength is the max length in bytes of the content field.
+	// Default: 65535
+	MaxContentLength int
+
+	// ContentOverflowPolicy defines how documents with content longer than MaxContentLength are handled.
+	// Default: ContentOverflowError
+	ContentOverflowPolicy ContentOverflowPolicy
+
+	// ExternalContentStore stores the full content of overflowing documents.
+	// Required when ContentOverflowPolicy is ContentOverflowExternal.
+	ExternalContentStore ExternalContentStore
+
+	// MetadataCompression enables gzip+base64 compression of large metadata JSON.
+	// Only takes effect with the default DocumentConverter.
+	// Optional.
+	MetadataCompression *MetadataCompressionConfig
+
+	// CollectionStatsInterval enables reporting the collection row count in the callback output,
+	// queried from Milvus at most once per interval.
+	// Optional. Default: 0, disabled
+	CollectionStatsInterval time.Duration
+
+	// EnableCallbacks is false to let the graph running the indexer report its callbacks,
+	// without the partial failures and collection stats of the indexer.
+	// Optional. Default: true
+	EnableCallbacks *bool
+
+	// WAL enables a file-backed write-ahead buffer, protecting ingestion against transient Milvus outages.
+	// Store returns once the documents are persisted locally, failed upserts are retried in the background.
+	// Call Close to stop the background flusher.
+	// Optional. Default: disabled
+	WAL *WALConfig
+
+	// ContinueOnError rejects the invalid documents of a batch individually, e.g. documents missing vector data
+	// or exceeding MaxContentLength with ContentOverflowError, and stores the rest instead of failing the whole batch.
+	// The rejected documents are reported by a *StoreError. Can be overridden per request by WithContinueOnError.
+	// Errors of the whole batch, e.g. embedding or upsert failures, still fail Store.
+	// Optional. Default: false
+	ContinueOnError bool
+
+	// WriteBackVectors attaches the dense vectors of Vector computed by Store to the stored input documents
+	// with schema.Document.WithDenseVector, and reports them in the callback output with CallbackExtraKeyDenseVectors,
+	// so that downstream nodes, e.g. a secondary store, reuse the embeddings without computing them again.
+	// Only the vectors computed by the embedder or VectorProvider are written back, not the ones read from the documents.
+	// Can be overridden per request by WithWriteBackVectors.
+	// Optional. Default: false
+	WriteBackVectors bool
+
+	// RetryPolicy retries the batches failed to upsert with a transient error, e.g. the rate limit is exceeded
+	// or Milvus is unavailable, with exponential backoff. Permanent errors are returned immediately.
+	// Optional. Default: no retry
+	RetryPolicy *RetryPolicy
 }
 
 // VectorConfig contains configuration for dense vector index.
@@ -106,7 +191,19 @@ type VectorConfig struct {
 
 	// VectorField is the name of the vector field in the collection.
 	// Default: "vector"
+	// Required for the fields of IndexerConfig.ExtraVectors.
 	VectorField string
+
+	// Embedding embeds the document content into this field.
+	// For Vector, it takes precedence over IndexerConfig.Embedding, the WithEmbedding option still overrides it.
+	// Optional.
+	Embedding embedding.Embedder
+
+	// VectorProvider returns the vectors of this field for docs, one per document,
+	// e.g. image embeddings of the image referenced by the document metadata, or vectors computed by another pipeline.
+	// It takes precedence over Embedding, and for Vector over IndexerConfig.Embedding and the WithEmbedding option.
+	// Optional.
+	VectorProvider func(ctx context.Context, docs []*schema.Document) ([][]float64, error)
 }
 
 // SparseMethod defines the method for sparse vector generation.
@@ -138,17 +235,41 @@ type SparseVectorConfig struct {
 	// Method specifies the method for sparse vector generation.
 	// Optional. Default: SparseMethodAuto if MetricType is BM25, otherwise SparseMethodPrecomputed.
 	Method SparseMethod
+
+	// Encoder encodes the document content into sparse vectors on the client side,
+	// overriding the sparse vectors carried by the documents.
+	// Only applicable when Method is SparseMethodPrecomputed.
+	// Optional.
+	Encoder SparseEncoder
+
+	// Normalize scales the precomputed sparse vectors to unit L2 norm before they are stored.
+	// Only takes effect with the default DocumentConverter.
+	// Optional. Default: false
+	Normalize bool
 }
 
 // Indexer implements the indexer.Indexer interface for Milvus 2.x using the V2 SDK.
 type Indexer struct {
 	client *milvusclient.Client
 	config *IndexerConfig
+	stats  *collectionStats
+	wal    *writeAheadLog
+
+	// inputExtra is the Extra of indexer.CallbackInput shared by all the Store calls, see newInputExtra.
+	inputExtra map[string]any
+
+	// requireDocVectors is set if the default DocumentConverter reads the dense vectors of Vector from the documents
+	// when no embedder is given, so that a batch with a document missing one fails before any embedding call.
+	requireDocVectors bool
+	// requireDocIDs is set if the default DocumentConverter is used, which rejects documents without an ID,
+	// so that such a batch fails before any embedding call.
+	requireDocIDs bool
 }
 
 // NewIndexer creates a new Milvus2 indexer with the provided configuration.
 // It returns an error if the configuration is invalid.
 func NewIndexer(ctx context.Context, conf *IndexerConfig) (*Indexer, error) {
+	defaultConverter := conf.DocumentConverter == nil
 	if err := conf.validate(); err != nil {
 		return nil, err
 	}
@@ -162,10 +283,21 @@ func NewIndexer(ctx context.Context, conf *IndexerConfig) (*Indexer, error) {
 		return nil, err
 	}
 
-	return &Indexer{
-		client: cli,
-		config: conf,
-	}, nil
+	i := &Indexer{
+		client:            cli,
+		config:            conf,
+		stats:             newCollectionStats(conf.CollectionStatsInterval),
+		inputExtra:        newInputExtra(conf),
+		requireDocVectors: defaultConverter && conf.Vector != nil && conf.Vector.VectorProvider == nil,
+		requireDocIDs:     defaultConverter,
+	}
+	if conf.WAL != nil {
+		i.wal, err = newWriteAheadLog(conf.WAL, i.upsertBatch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
 }
 
 func initClient(ctx context.Context, conf *IndexerConfig) (*milvusclient.Client, error) {
@@ -199,6 +331,11 @@ func initCollection(ctx context.Context, cli *milvusclient.Client, conf *Indexer
 		if conf.Vector != nil && conf.Vector.Dimension <= 0 {
 			return fmt.Errorf("[NewIndexer] vector dimension is required when collection does not exist")
 		}
+		for _, vc := range conf.ExtraVectors {
+			if vc.Dimension <= 0 {
+				return fmt.Errorf("[NewIndexer] vector dimension of field %s is required when collection does not exist", vc.VectorField)
+			}
+		}
 		if err := createCollection(ctx, cli, conf); err != nil {
 			return err
 		}
@@ -228,45 +365,244 @@ func initCollection(ctx context.Context, cli *milvusclient.Client, conf *Indexer
 
 // Store adds the provided documents to the Milvus collection.
 // It returns the list of IDs for the stored documents or an error.
+// With ContinueOnError, invalid documents are rejected individually and the rest are stored,
+// the rejected documents are reported by a *StoreError returned with the IDs of the stored rows.
 func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
+	emb := i.config.Embedding
+	if i.config.Vector != nil && i.config.Vector.Embedding != nil {
+		emb = i.config.Vector.Embedding
+	}
 	co := indexer.GetCommonOptions(&indexer.Options{
-		Embedding: i.config.Embedding,
+		Embedding: emb,
 	}, opts...)
 	io := indexer.GetImplSpecificOptions(&ImplOptions{
-		Partition: i.config.PartitionName,
+		Partition:        i.config.PartitionName,
+		ContinueOnError:  i.config.ContinueOnError,
+		WriteBackVectors: i.config.WriteBackVectors,
 	}, opts...)
 
 	ctx = callbacks.EnsureRunInfo(ctx, i.GetType(), components.ComponentOfIndexer)
-	ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{
-		Docs: docs,
-	})
+	cbEnabled := i.IsCallbacksEnabled()
+	if cbEnabled {
+		ctx = callbacks.OnStart(ctx, &indexer.CallbackInput{
+			Docs:  docs,
+			Extra: i.callbackInputExtra(io.Partition),
+		})
+	}
 	defer func() {
-		if err != nil {
+		// a partial failure is reported by OnEnd with the stored rows
+		var storeErr *StoreError
+		if err != nil && cbEnabled && !errors.As(err, &storeErr) {
 			callbacks.OnError(ctx, err)
 		}
 	}()
 
-	vectors, err := i.embedDocuments(ctx, co.Embedding, docs)
+	var (
+		input  = docs
+		kept   []int
+		failed []FailedDocument
+	)
+	if io.ContinueOnError {
+		docs, kept, failed = screenDocuments(i.config, docs, i.requireDocIDs)
+	}
+
+	if i.requireDocIDs && !io.ContinueOnError {
+		if err = checkDocIDs(docs); err != nil {
+			return nil, err
+		}
+	}
+	if i.requireDocVectors && co.Embedding == nil && !io.ContinueOnError {
+		if err = checkDocVectors(docs); err != nil {
+			return nil, err
+		}
+	}
+
+	// The split rows are embedded one by one, so that every row gets the vector of its own content.
+	// The other policies do not call ExternalContentStore before the embedding succeeds.
+	var (
+		rows    []*schema.Document
+		origins []int
+	)
+	split := i.config.ContentOverflowPolicy == ContentOverflowSplit
+	if split {
+		if rows, origins, err = applyContentOverflowRows(ctx, i.config, docs); err != nil {
+			return nil, err
+		}
+	}
+	embedded := docs
+	perRow := split && origins != nil
+	if perRow {
+		embedded = rows
+	}
+
+	vectors, err := i.denseVectors(ctx, co.Embedding, embedded)
+	if err != nil {
+		return nil, err
+	}
+
+	extraVectors, err := i.embedExtraVectors(ctx, embedded)
+	if err != nil {
+		return nil, err
+	}
+
+	if !split {
+		if rows, origins, err = applyContentOverflowRows(ctx, i.config, docs); err != nil {
+			return nil, err
+		}
+	}
+	rows, err = i.encodeSparseVectors(ctx, rows)
 	if err != nil {
 		return nil, err
 	}
+	docVectors := vectors
+	if perRow {
+		docVectors = headVectors(vectors, len(docs), origins)
+	} else {
+		vectors = expandVectors(vectors, len(docs), origins)
+		for idx := range extraVectors {
+			extraVectors[idx] = expandVectors(extraVectors[idx], len(docs), origins)
+		}
+	}
+	// the rows are screened one by one only if the batch conversion fails,
+	// the columns of a successful conversion are upserted as is
+	var columns []column.Column
+	if io.ContinueOnError && len(rows) > 0 {
+		var convErr error
+		if columns, convErr = i.convertDocuments(ctx, rows, vectors, extraVectors); convErr != nil {
+			var rowFailed []FailedDocument
+			rows, vectors, extraVectors, rowFailed = i.screenRows(ctx, rows, vectors, extraVectors, mapRowDocs(len(rows), origins, kept))
+			failed = append(failed, rowFailed...)
+		}
+		sortFailedDocuments(failed)
+	}
 
-	upsertResult, err := i.upsertDocuments(ctx, docs, vectors, io.Partition)
+	start := time.Now()
+	var upsertResult []string
+	switch {
+	case len(rows) == 0:
+		upsertResult = []string{}
+	case i.wal != nil:
+		upsertResult, err = i.storeWithWAL(ctx, rows, vectors, extraVectors, io.Partition)
+	case columns != nil:
+		upsertResult, err = i.upsertColumns(ctx, rows, columns, io.Partition)
+	default:
+		upsertResult, err = i.upsertDocuments(ctx, rows, vectors, extraVectors, io.Partition)
+	}
 	if err != nil {
 		return nil, err
 	}
+	var storedVectors [][]float64
+	if io.WriteBackVectors && docVectors != nil {
+		storedVectors = writeBackVectors(input, docs, docVectors, kept, failed)
+	}
+	if len(failed) > 0 {
+		err = &StoreError{Result: &StoreResult{SucceededIDs: upsertResult, Failed: failed}}
+	}
+	if !cbEnabled {
+		return upsertResult, err
+	}
+
+	extra := map[string]any{
+		CallbackExtraKeyLatency:     time.Since(start),
+		CallbackExtraKeyUpsertCount: len(upsertResult),
+	}
+	if len(failed) > 0 {
+		extra[CallbackExtraKeyFailedCount] = len(failed)
+	}
+	if rowCount, ok := i.stats.getRowCount(ctx, i.client, i.config.Collection); ok {
+		extra[CallbackExtraKeyCollectionRowCount] = rowCount
+	}
+	if i.wal != nil {
+		extra[CallbackExtraKeyWALPending] = i.wal.size()
+	}
+	if storedVectors != nil {
+		extra[CallbackExtraKeyDenseVectors] = storedVectors
+	}
 
 	callbacks.OnEnd(ctx, &indexer.CallbackOutput{
-		IDs: upsertResult,
+		IDs:   upsertResult,
+		Extra: extra,
 	})
 
-	return upsertResult, nil
+	return upsertResult, err
+}
+
+// writeBackVectors attaches the vectors computed for docs, the input documents kept by the screening, to the documents
+// which are stored. It returns the vectors in the order of the input documents, nil for the rejected ones.
+func writeBackVectors(input, docs []*schema.Document, vectors [][]float64, kept []int, failed []FailedDocument) [][]float64 {
+	rejected := make(map[int]bool, len(failed))
+	for _, f := range failed {
+		rejected[f.Index] = true
+	}
+	stored := make([][]float64, len(input))
+	for idx, doc := range docs {
+		inputIdx := idx
+		if kept != nil {
+			inputIdx = kept[idx]
+		}
+		if rejected[inputIdx] || idx >= len(vectors) {
+			continue
+		}
+		doc.WithDenseVector(vectors[idx])
+		stored[inputIdx] = vectors[idx]
+	}
+	return stored
+}
+
+// denseVectors computes the vectors of Vector with its VectorProvider or emb,
+// nil if neither is set, in which case the vectors are read from the documents.
+func (i *Indexer) denseVectors(ctx context.Context, emb embedding.Embedder, docs []*schema.Document) ([][]float64, error) {
+	if i.config.Vector != nil && i.config.Vector.VectorProvider != nil {
+		return provideVectors(ctx, i.config.Vector, docs)
+	}
+	return i.embedDocuments(ctx, emb, docs)
+}
+
+// checkDocIDs checks that every document has an ID, as the primary key is not generated by Milvus.
+func checkDocIDs(docs []*schema.Document) error {
+	for idx, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("[Indexer.Store] id of document %d is empty", idx)
+		}
+	}
+	return nil
+}
+
+// checkDocVectors checks that every document carries a precomputed dense vector.
+func checkDocVectors(docs []*schema.Document) error {
+	for idx, doc := range docs {
+		if len(doc.DenseVector()) == 0 && len(Float32Vector(doc)) == 0 {
+			return fmt.Errorf("[Indexer.Store] vector data missing for document %d (id: %s): "+
+				"set Embedding or Vector.VectorProvider, or attach the vector with WithDenseVector or WithFloat32Vector", idx, doc.ID)
+		}
+	}
+	return nil
+}
+
+// provideVectors returns the vectors of the field of vc given by its VectorProvider.
+func provideVectors(ctx context.Context, vc *VectorConfig, docs []*schema.Document) ([][]float64, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	vectors, err := vc.VectorProvider(ctx, docs)
+	if err != nil {
+		return nil, fmt.Errorf("[Indexer.Store] failed to get vectors of field %s: %w", vc.VectorField, err)
+	}
+	if len(vectors) != len(docs) {
+		return nil, fmt.Errorf("[Indexer.Store] vector provider result length mismatch for field %s: need %d, got %d",
+			vc.VectorField, len(docs), len(vectors))
+	}
+	return vectors, nil
 }
 
 func (i *Indexer) embedDocuments(ctx context.Context, emb embedding.Embedder, docs []*schema.Document) ([][]float64, error) {
 	if emb == nil {
 		return nil, nil // Return nil vectors if no embedder
 	}
+	if len(docs) == 0 {
+		// e.g. every document is rejected with ContinueOnError
+		return nil, nil
+	}
 
 	texts := make([]string, 0, len(docs))
 	for _, doc := range docs {
@@ -283,12 +619,126 @@ func (i *Indexer) embedDocuments(ctx context.Context, emb embedding.Embedder, do
 	return vectors, nil
 }
 
-func (i *Indexer) upsertDocuments(ctx context.Context, docs []*schema.Document, vectors [][]float64, partition string) ([]string, error) {
-	columns, err := i.config.DocumentConverter(ctx, docs, vectors)
+// embedExtraVectors computes the vectors of the ExtraVectors fields, in the order of the config.
+func (i *Indexer) embedExtraVectors(ctx context.Context, docs []*schema.Document) ([][][]float64, error) {
+	if len(i.config.ExtraVectors) == 0 {
+		return nil, nil
+	}
+
+	extraVectors := make([][][]float64, 0, len(i.config.ExtraVectors))
+	for _, vc := range i.config.ExtraVectors {
+		var (
+			vectors [][]float64
+			err     error
+		)
+		if vc.VectorProvider != nil {
+			vectors, err = provideVectors(ctx, vc, docs)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			vectors, err = i.embedDocuments(ctx, vc.Embedding, docs)
+			if err != nil {
+				return nil, fmt.Errorf("[Indexer.Store] field %s: %w", vc.VectorField, err)
+			}
+		}
+		extraVectors = append(extraVectors, vectors)
+	}
+	return extraVectors, nil
+}
+
+// storeWithWAL persists the rows to the write-ahead buffer and flushes it, unless a flush is already in progress.
+// Batches failed to upsert stay in the buffer and are retried by the background flusher,
+// the error is passed to WALConfig.OnError.
+func (i *Indexer) storeWithWAL(ctx context.Context, docs []*schema.Document, vectors [][]float64,
+	extraVectors [][][]float64, partition string) ([]string, error) {
+	if err := i.wal.append(newWALBatch(docs, vectors, extraVectors, partition)); err != nil {
+		return nil, fmt.Errorf("[Indexer.Store] failed to write WAL: %w", err)
+	}
+	if err := i.wal.tryFlush(ctx); err != nil {
+		i.wal.report(err)
+	}
+
+	ids := make([]string, len(docs))
+	for idx, doc := range docs {
+		ids[idx] = doc.ID
+	}
+	return ids, nil
+}
+
+func (i *Indexer) upsertBatch(ctx context.Context, batch *walBatch) error {
+	_, err := i.upsertDocuments(ctx, batch.documents(), batch.Vectors, batch.ExtraVectors, batch.Partition)
+	return err
+}
+
+// Pending returns the number of batches in the write-ahead buffer waiting to be upserted.
+// It is always 0 if IndexerConfig.WAL is not set.
+func (i *Indexer) Pending() int {
+	if i.wal == nil {
+		return 0
+	}
+	return i.wal.size()
+}
+
+// Flush upserts the batches in the write-ahead buffer in order, stopping at the first failure.
+// It is a no-op if IndexerConfig.WAL is not set.
+func (i *Indexer) Flush(ctx context.Context) error {
+	if i.wal == nil {
+		return nil
+	}
+	if err := i.wal.flush(ctx); err != nil {
+		return fmt.Errorf("[Indexer.Flush] %w", err)
+	}
+	return nil
+}
+
+// Close stops the background flusher of the write-ahead buffer.
+// Pending batches are kept on disk and flushed by the next indexer using the same WALConfig.Dir.
+func (i *Indexer) Close() error {
+	if i.wal != nil {
+		i.wal.close()
+	}
+	return nil
+}
+
+// UpdateProperties alters the properties of the collection, e.g. PropertyMmapEnabled or PropertyCollectionTTLSeconds.
+// Some properties such as PropertyMmapEnabled only take effect after the collection is released and loaded again.
+func (i *Indexer) UpdateProperties(ctx context.Context, props map[string]string) error {
+	if len(props) == 0 {
+		return nil
+	}
+
+	opt := milvusclient.NewAlterCollectionPropertiesOption(i.config.Collection)
+	for k, v := range props {
+		opt = opt.WithProperty(k, v)
+	}
+	if err := i.client.AlterCollectionProperties(ctx, opt); err != nil {
+		return fmt.Errorf("[Indexer.UpdateProperties] failed to alter collection properties: %w", err)
+	}
+	return nil
+}
+
+func (i *Indexer) upsertDocuments(ctx context.Context, docs []*schema.Document, vectors [][]float64,
+	extraVectors [][][]float64, partition string) ([]string, error) {
+	columns, err := i.convertDocuments(ctx, docs, vectors, extraVectors)
 	if err != nil {
 		return nil, fmt.Errorf("[Indexer.Store] failed to convert documents: %w", err)
 	}
+	return i.upsertColumns(ctx, docs, columns, partition)
+}
+
+// convertDocuments converts docs to the columns of the collection with DocumentConverter and the ExtraVectors fields.
+func (i *Indexer) convertDocuments(ctx context.Context, docs []*schema.Document, vectors [][]float64,
+	extraVectors [][][]float64) ([]column.Column, error) {
+	columns, err := i.config.DocumentConverter(ctx, docs, vectors)
+	if err != nil {
+		return nil, err
+	}
+	return appendExtraVectorColumns(columns, i.config.ExtraVectors, extraVectors, len(docs))
+}
 
+// upsertColumns upserts the columns converted from docs.
+func (i *Indexer) upsertColumns(ctx context.Context, docs []*schema.Document, columns []column.Column, partition string) ([]string, error) {
 	insertOpt := milvusclient.NewColumnBasedInsertOption(i.config.Collection)
 	if partition != "" {
 		insertOpt = insertOpt.WithPartition(partition)
@@ -297,11 +747,27 @@ func (i *Indexer) upsertDocuments(ctx context.Context, docs []*schema.Document,
 		insertOpt = insertOpt.WithColumns(col)
 	}
 
-	result, err := i.client.Upsert(ctx, insertOpt)
+	var policy *RetryPolicy
+	if i.config.RetryPolicy != nil && retryable(docs) {
+		policy = i.config.RetryPolicy
+	}
+	result, err := retry.Do(ctx, policy, func() (milvusclient.UpsertResult, error) {
+		return i.client.Upsert(ctx, insertOpt)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("[Indexer.Store] failed to upsert documents: %w", err)
 	}
 
+	if i.config.ContentOverflowPolicy == ContentOverflowSplit {
+		deleteOpt := milvusclient.NewDeleteOption(i.config.Collection).WithExpr(staleContinuationFilter(i.config, docs))
+		if partition != "" {
+			deleteOpt = deleteOpt.WithPartition(partition)
+		}
+		if _, err = i.client.Delete(ctx, deleteOpt); err != nil {
+			return nil, fmt.Errorf("[Indexer.Store] failed to delete stale continuation rows: %w", err)
+		}
+	}
+
 	ids := make([]string, 0, result.IDs.Len())
 	for idx := 0; idx < result.IDs.Len(); idx++ {
 		idStr, err := result.IDs.GetAsString(idx)
@@ -319,9 +785,9 @@ func (i *Indexer) GetType() string {
 	return typ
 }
 
-// IsCallbacksEnabled checks if callbacks are enabled for this indexer.
+// IsCallbacksEnabled returns IndexerConfig.EnableCallbacks.
 func (i *Indexer) IsCallbacksEnabled() bool {
-	return true
+	return i.config.EnableCallbacks == nil || *i.config.EnableCallbacks
 }
 
 // validate checks the configuration and sets default values.
@@ -331,7 +797,7 @@ func (c *IndexerConfig) validate() error {
 	}
 
 	// Ensure at least one vector config is present
-	if c.Vector == nil && c.Sparse == nil {
+	if c.Vector == nil && c.Sparse == nil && len(c.ExtraVectors) == 0 {
 		return fmt.Errorf("[NewIndexer] at least one vector field (dense or sparse) is required")
 	}
 
@@ -341,6 +807,9 @@ func (c *IndexerConfig) validate() error {
 	if c.Description == "" {
 		c.Description = defaultDescription
 	}
+	if err := c.validateScalarFields(); err != nil {
+		return err
+	}
 
 	// Dense vector defaults
 	if c.Vector != nil {
@@ -352,6 +821,10 @@ func (c *IndexerConfig) validate() error {
 		}
 	}
 
+	if err := c.validateExtraVectors(); err != nil {
+		return err
+	}
+
 	// Sparse vector defaults
 	if c.Sparse != nil {
 		if c.Sparse.VectorField == "" {
@@ -373,15 +846,122 @@ func (c *IndexerConfig) validate() error {
 			}
 		}
 
+		if c.Sparse.Encoder != nil && c.Sparse.Method != SparseMethodPrecomputed {
+			return fmt.Errorf("[NewIndexer] sparse encoder requires method %s, got %s", SparseMethodPrecomputed, c.Sparse.Method)
+		}
+
 		c.addDefaultBM25Function()
 	}
 
+	if c.MaxContentLength <= 0 {
+		c.MaxContentLength = defaultMaxContentLen
+	}
+	switch c.ContentOverflowPolicy {
+	case "":
+		c.ContentOverflowPolicy = ContentOverflowError
+	case ContentOverflowError, ContentOverflowTruncate, ContentOverflowSplit:
+	case ContentOverflowExternal:
+		if c.ExternalContentStore == nil {
+			return fmt.Errorf("[NewIndexer] external content store is required for overflow policy %q", c.ContentOverflowPolicy)
+		}
+	default:
+		return fmt.Errorf("[NewIndexer] unsupported content overflow policy: %s", c.ContentOverflowPolicy)
+	}
+	if c.MetadataCompression != nil && c.MetadataCompression.Threshold <= 0 {
+		c.MetadataCompression.Threshold = defaultMetadataCompressionThreshold
+	}
+
 	if c.DocumentConverter == nil {
-		c.DocumentConverter = defaultDocumentConverter(c.Vector, c.Sparse)
+		c.DocumentConverter = defaultDocumentConverter(c.scalarFields(), c.Vector, c.Sparse, c.MetadataCompression)
+	}
+	if c.WAL != nil {
+		if err := c.WAL.validate(); err != nil {
+			return err
+		}
+	}
+	if c.RetryPolicy != nil {
+		if err := c.RetryPolicy.Validate(); err != nil {
+			return fmt.Errorf("[NewIndexer] %w", err)
+		}
+	}
+	return nil
+}
+
+// validateScalarFields sets the default names of the id, content and metadata fields and checks they are distinct.
+func (c *IndexerConfig) validateScalarFields() error {
+	if c.IDField == "" {
+		c.IDField = defaultIDField
+	}
+	if c.ContentField == "" {
+		c.ContentField = defaultContentField
+	}
+	if c.MetadataField == "" {
+		c.MetadataField = defaultMetadataField
+	}
+	if c.IDField == c.ContentField || c.IDField == c.MetadataField || c.ContentField == c.MetadataField {
+		return fmt.Errorf("[NewIndexer] id, content and metadata fields must be distinct, got %q, %q and %q",
+			c.IDField, c.ContentField, c.MetadataField)
 	}
 	return nil
 }
 
+// scalarFields returns the names of the id, content and metadata fields.
+func (c *IndexerConfig) scalarFields() scalarFields {
+	return scalarFields{id: c.IDField, content: c.ContentField, metadata: c.MetadataField}
+}
+
+func (c *IndexerConfig) validateExtraVectors() error {
+	fields := map[string]bool{
+		c.IDField:       true,
+		c.ContentField:  true,
+		c.MetadataField: true,
+	}
+	if c.Vector != nil {
+		if fields[c.Vector.VectorField] {
+			return fmt.Errorf("[NewIndexer] duplicate vector field: %s", c.Vector.VectorField)
+		}
+		fields[c.Vector.VectorField] = true
+	}
+	if c.Sparse != nil {
+		vectorField := c.Sparse.VectorField
+		if vectorField == "" {
+			vectorField = defaultSparseVectorField
+		}
+		if fields[vectorField] {
+			return fmt.Errorf("[NewIndexer] duplicate vector field: %s", vectorField)
+		}
+		fields[vectorField] = true
+	}
+
+	for idx, vc := range c.ExtraVectors {
+		if vc == nil {
+			return fmt.Errorf("[NewIndexer] extra vector config %d is nil", idx)
+		}
+		if vc.VectorField == "" {
+			return fmt.Errorf("[NewIndexer] vector field of extra vector config %d is required", idx)
+		}
+		if fields[vc.VectorField] {
+			return fmt.Errorf("[NewIndexer] duplicate vector field: %s", vc.VectorField)
+		}
+		fields[vc.VectorField] = true
+		if vc.Embedding == nil && vc.VectorProvider == nil {
+			return fmt.Errorf("[NewIndexer] either Embedding or VectorProvider is required for vector field %s", vc.VectorField)
+		}
+	}
+
+	// the defaults are set on copies, keep the caller's configs untouched
+	extraVectors := make([]*VectorConfig, len(c.ExtraVectors))
+	for idx, vc := range c.ExtraVectors {
+		cp := *vc
+		if cp.MetricType == "" {
+			cp.MetricType = L2
+		}
+		extraVectors[idx] = &cp
+	}
+	c.ExtraVectors = extraVectors
+	return nil
+}
+
 func (c *IndexerConfig) addDefaultBM25Function() {
 	if c.Sparse != nil && c.Sparse.Method == SparseMethodAuto {
 		hasSparseFunc := false
@@ -401,7 +981,7 @@ func (c *IndexerConfig) addDefaultBM25Function() {
 			bm25Fn := entity.NewFunction().
 				WithName("bm25_auto").
 				WithType(entity.FunctionTypeBM25).
-				WithInputFields(defaultContentField).
+				WithInputFields(c.ContentField).
 				WithOutputFields(c.Sparse.VectorField)
 			c.Functions = append(c.Functions, bm25Fn)
 		}
@@ -419,6 +999,9 @@ func createCollection(ctx context.Context, cli *milvusclient.Client, conf *Index
 	if conf.ConsistencyLevel != ConsistencyLevelDefault {
 		createOpt = createOpt.WithConsistencyLevel(conf.ConsistencyLevel.ToEntity())
 	}
+	for k, v := range conf.CollectionProperties {
+		createOpt = createOpt.WithProperty(k, v)
+	}
 
 	if err := cli.CreateCollection(ctx, createOpt); err != nil {
 		return fmt.Errorf("[NewIndexer] failed to create collection: %w", err)
@@ -438,22 +1021,22 @@ func buildSchema(conf *IndexerConfig) (*entity.Schema, error) {
 	}
 
 	idField := entity.NewField().
-		WithName(defaultIDField).
+		WithName(conf.IDField).
 		WithDataType(entity.FieldTypeVarChar).
 		WithMaxLength(defaultMaxIDLen).
 		WithIsPrimaryKey(true)
-	applyParams(idField, defaultIDField)
+	applyParams(idField, conf.IDField)
 
 	contentField := entity.NewField().
-		WithName(defaultContentField).
+		WithName(conf.ContentField).
 		WithDataType(entity.FieldTypeVarChar).
-		WithMaxLength(defaultMaxContentLen)
-	applyParams(contentField, defaultContentField)
+		WithMaxLength(int64(conf.MaxContentLength))
+	applyParams(contentField, conf.ContentField)
 
 	metadataField := entity.NewField().
-		WithName(defaultMetadataField).
+		WithName(conf.MetadataField).
 		WithDataType(entity.FieldTypeJSON)
-	applyParams(metadataField, defaultMetadataField)
+	applyParams(metadataField, conf.MetadataField)
 
 	sch := entity.NewSchema().
 		WithField(idField).
@@ -468,11 +1051,20 @@ func buildSchema(conf *IndexerConfig) (*entity.Schema, error) {
 			WithDim(conf.Vector.Dimension)
 		applyParams(vecField, conf.Vector.VectorField)
 		sch.WithField(vecField)
-	} else if conf.Sparse == nil {
+	} else if conf.Sparse == nil && len(conf.ExtraVectors) == 0 {
 		// Should not happen if validation passed, but safety check: at least one vector field required
 		return nil, fmt.Errorf("[NewIndexer] at least one vector field (dense or sparse) is required")
 	}
 
+	for _, vc := range conf.ExtraVectors {
+		vecField := entity.NewField().
+			WithName(vc.VectorField).
+			WithDataType(entity.FieldTypeFloatVector).
+			WithDim(vc.Dimension)
+		applyParams(vecField, vc.VectorField)
+		sch.WithField(vecField)
+	}
+
 	if conf.Sparse != nil {
 		sparseField := entity.NewField().
 			WithName(conf.Sparse.VectorField).
@@ -497,6 +1089,12 @@ func createIndex(ctx context.Context, cli *milvusclient.Client, conf *IndexerCon
 		}
 	}
 
+	for _, vc := range conf.ExtraVectors {
+		if err := createVectorIndex(ctx, cli, vc.VectorField, vc, conf.Collection); err != nil {
+			return err
+		}
+	}
+
 	if conf.Sparse != nil {
 		if err := createSparseIndex(ctx, cli, conf.Sparse, conf.Collection); err != nil {
 			return err
@@ -562,52 +1160,59 @@ func createSparseIndex(ctx context.Context, cli *milvusclient.Client, sparseConf
 	return nil
 }
 
-// defaultDocumentConverter returns the default document to column converter.
-func defaultDocumentConverter(vector *VectorConfig, sparse *SparseVectorConfig) func(ctx context.Context, docs []*schema.Document, vectors [][]float64) ([]column.Column, error) {
+// scalarFields are the names of the id, content and metadata fields written by the default converter.
+type scalarFields struct {
+	id       string
+	content  string
+	metadata string
+}
+
+// defaultDocumentConverter returns the default document to column converter, writing the scalar columns of fields.
+// Metadata larger than the threshold of compression is stored compressed if compression is not nil.
+func defaultDocumentConverter(fields scalarFields, vector *VectorConfig, sparse *SparseVectorConfig, compression *MetadataCompressionConfig) func(ctx context.Context, docs []*schema.Document, vectors [][]float64) ([]column.Column, error) {
 	return func(ctx context.Context, docs []*schema.Document, vectors [][]float64) ([]column.Column, error) {
 		ids := make([]string, 0, len(docs))
 		contents := make([]string, 0, len(docs))
-		vecs := make([][]float32, 0, len(docs))
 		metadatas := make([][]byte, 0, len(docs))
-		sparseVecs := make([]entity.SparseEmbedding, 0, len(docs))
 
 		// Determine if we need to handle sparse vectors
 		sparseVectorField := ""
+		var sparseVecs []entity.SparseEmbedding
 		if sparse != nil && sparse.Method == SparseMethodPrecomputed {
 			sparseVectorField = sparse.VectorField
+			sparseVecs = make([]entity.SparseEmbedding, 0, len(docs))
 		}
 
 		// Determine if we need to handle dense vectors
 		denseVectorField := ""
+		var vecs [][]float32
 		if vector != nil {
 			denseVectorField = vector.VectorField
+			vecs = make([][]float32, 0, len(docs))
 		}
 
+		var vecBuf float32VectorBuffer
 		for idx, doc := range docs {
+			if doc.ID == "" {
+				return nil, fmt.Errorf("id of document %d is empty", idx)
+			}
 			ids = append(ids, doc.ID)
 			contents = append(contents, doc.Content)
 
-			var sourceVec []float64
-			if len(vectors) == len(docs) {
-				sourceVec = vectors[idx]
-			} else {
-				sourceVec = doc.DenseVector()
-			}
-
 			// Dense vector is required when vectorField is set (dense-only or hybrid mode).
 			if denseVectorField != "" {
-				if len(sourceVec) == 0 {
-					return nil, fmt.Errorf("vector data missing for document %d (id: %s)", idx, doc.ID)
-				}
-				vec := make([]float32, len(sourceVec))
-				for i, v := range sourceVec {
-					vec[i] = float32(v)
+				vec, err := denseVectorOf(&vecBuf, doc, vectors, idx, len(docs))
+				if err != nil {
+					return nil, err
 				}
 				vecs = append(vecs, vec)
 			}
 
 			if sparseVectorField != "" {
 				sv := doc.SparseVector()
+				if sparse.Normalize {
+					sv = NormalizeSparseVector(sv)
+				}
 				se, err := toMilvusSparseEmbedding(sv)
 				if err != nil {
 					return nil, fmt.Errorf("failed to convert sparse vector for document %d: %w", idx, err)
@@ -615,25 +1220,27 @@ func defaultDocumentConverter(vector *VectorConfig, sparse *SparseVectorConfig)
 				sparseVecs = append(sparseVecs, se)
 			}
 
-			metadata, err := sonic.Marshal(doc.MetaData)
+			metadata, err := sonic.Marshal(storedMetaData(doc))
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal metadata: %w", err)
 			}
+			if compression != nil {
+				metadata, err = compressMetadata(metadata, compression.Threshold)
+				if err != nil {
+					return nil, err
+				}
+			}
 			metadatas = append(metadatas, metadata)
 		}
 
 		columns := []column.Column{
-			column.NewColumnVarChar(defaultIDField, ids),
-			column.NewColumnVarChar(defaultContentField, contents),
-			column.NewColumnJSONBytes(defaultMetadataField, metadatas),
+			column.NewColumnVarChar(fields.id, ids),
+			column.NewColumnVarChar(fields.content, contents),
+			column.NewColumnJSONBytes(fields.metadata, metadatas),
 		}
 
 		if denseVectorField != "" {
-			dim := 0
-			if len(vecs) > 0 {
-				dim = len(vecs[0])
-			}
-			columns = append(columns, column.NewColumnFloatVector(denseVectorField, dim, vecs))
+			columns = append(columns, column.NewColumnFloatVector(denseVectorField, vecBuf.dim, vecs))
 		}
 
 		if sparseVectorField != "" {
@@ -645,30 +1252,113 @@ func defaultDocumentConverter(vector *VectorConfig, sparse *SparseVectorConfig)
 	}
 }
 
+// denseVectorOf returns the float32 dense vector of the document at idx,
+// from the embedder vectors, WithFloat32Vector or schema.Document.DenseVector in this order.
+func denseVectorOf(buf *float32VectorBuffer, doc *schema.Document, vectors [][]float64, idx, batchSize int) ([]float32, error) {
+	sourceVec := doc.DenseVector()
+	if len(vectors) == batchSize {
+		sourceVec = vectors[idx]
+	} else if vec := Float32Vector(doc); len(vec) > 0 {
+		if err := buf.checkDim(len(vec)); err != nil {
+			return nil, fmt.Errorf("document %d (id: %s): %w", idx, doc.ID, err)
+		}
+		return vec, nil
+	}
+	if len(sourceVec) == 0 {
+		return nil, fmt.Errorf("vector data missing for document %d (id: %s)", idx, doc.ID)
+	}
+	vec, err := buf.add(sourceVec, batchSize-idx)
+	if err != nil {
+		return nil, fmt.Errorf("document %d (id: %s): %w", idx, doc.ID, err)
+	}
+	return vec, nil
+}
+
+// storedMetaData returns the metadata stored in the metadata field, without the WithFloat32Vector vector.
+func storedMetaData(doc *schema.Document) map[string]any {
+	if _, ok := doc.MetaData[float32VectorKey]; !ok {
+		return doc.MetaData
+	}
+	metadata := make(map[string]any, len(doc.MetaData)-1)
+	for k, v := range doc.MetaData {
+		if k != float32VectorKey {
+			metadata[k] = v
+		}
+	}
+	return metadata
+}
+
+// appendExtraVectorColumns appends the float vector columns of the ExtraVectors fields for rowCount rows,
+// the fields already returned by the document converter are skipped.
+func appendExtraVectorColumns(columns []column.Column, confs []*VectorConfig, extraVectors [][][]float64, rowCount int) ([]column.Column, error) {
+	if len(confs) == 0 {
+		return columns, nil
+	}
+
+	converted := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		converted[col.Name()] = true
+	}
+
+	for idx, vc := range confs {
+		if converted[vc.VectorField] {
+			continue
+		}
+		if idx >= len(extraVectors) || len(extraVectors[idx]) != rowCount {
+			got := 0
+			if idx < len(extraVectors) {
+				got = len(extraVectors[idx])
+			}
+			return nil, fmt.Errorf("vectors of field %s missing: need %d, got %d", vc.VectorField, rowCount, got)
+		}
+
+		vecs := make([][]float32, 0, len(extraVectors[idx]))
+		dim := int(vc.Dimension)
+		var vecBuf float32VectorBuffer
+		for n, sourceVec := range extraVectors[idx] {
+			if len(sourceVec) == 0 {
+				return nil, fmt.Errorf("vector data of field %s missing for document %d", vc.VectorField, n)
+			}
+			if dim <= 0 {
+				dim = len(sourceVec)
+			}
+			if len(sourceVec) != dim {
+				return nil, fmt.Errorf("vector dimension of field %s mismatch for document %d: need %d, got %d",
+					vc.VectorField, n, dim, len(sourceVec))
+			}
+			vec, err := vecBuf.add(sourceVec, len(extraVectors[idx])-n)
+			if err != nil {
+				return nil, fmt.Errorf("vector of field %s for document %d: %w", vc.VectorField, n, err)
+			}
+			vecs = append(vecs, vec)
+		}
+		columns = append(columns, column.NewColumnFloatVector(vc.VectorField, dim, vecs))
+	}
+
+	return columns, nil
+}
+
 func toMilvusSparseEmbedding(sv map[int]float64) (entity.SparseEmbedding, error) {
 	if len(sv) == 0 {
 		return entity.NewSliceSparseEmbedding([]uint32{}, []float32{})
 	}
 
-	indices := make([]int, 0, len(sv))
-	for k := range sv {
-		indices = append(indices, k)
+	// validate while collecting the indices, instead of iterating the map twice
+	indices := make([]uint32, 0, len(sv))
+	for idx, v := range sv {
+		if err := validateSparseEntry(idx, v); err != nil {
+			return nil, err
+		}
+		indices = append(indices, uint32(idx))
 	}
+	slices.Sort(indices)
 
-	sort.Ints(indices)
-
-	uint32Indices := make([]uint32, len(indices))
 	values := make([]float32, len(indices))
-
 	for i, idx := range indices {
-		if idx < 0 {
-			return nil, fmt.Errorf("negative sparse index: %d", idx)
-		}
-		uint32Indices[i] = uint32(idx)
-		values[i] = float32(sv[idx])
+		values[i] = float32(sv[int(idx)])
 	}
 
-	return entity.NewSliceSparseEmbedding(uint32Indices, values)
+	return entity.NewSliceSparseEmbedding(indices, values)
 }
 
 // makeEmbeddingCtx creates a context with embedding callback information.
@@ -18,11 +18,15 @@ package milvus2
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components/embedding"
+	einoindexer "github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/schema"
 	"github.com/milvus-io/milvus/client/v2/column"
 	"github.com/milvus-io/milvus/client/v2/entity"
@@ -30,6 +34,9 @@ import (
 	"github.com/smartystreets/goconvey/convey"
 )
 
+// defaultScalarFields are the field names of the collections created with the default configuration.
+var defaultScalarFields = scalarFields{id: defaultIDField, content: defaultContentField, metadata: defaultMetadataField}
+
 // mockEmbedding implements embedding.Embedder for testing
 type mockEmbedding struct {
 	err  error
@@ -83,6 +90,14 @@ func TestIndexerConfig_validate(t *testing.T) {
 			convey.So(err, convey.ShouldBeNil)
 		})
 
+		PatchConvey("test vector embedding", func() {
+			config := &IndexerConfig{
+				ClientConfig: &milvusclient.ClientConfig{Address: "localhost:19530"},
+				Vector:       &VectorConfig{Dimension: 128, Embedding: mockEmb},
+			}
+			convey.So(config.validate(), convey.ShouldBeNil)
+		})
+
 		PatchConvey("test valid config sets defaults", func() {
 			config := &IndexerConfig{
 				ClientConfig: &milvusclient.ClientConfig{Address: "localhost:19530"},
@@ -302,6 +317,75 @@ func TestIndexerConfig_validate(t *testing.T) {
 			convey.So(config.Functions[0].Name, convey.ShouldEqual, "other_fn")
 			convey.So(config.Functions[1].Name, convey.ShouldEqual, "bm25_auto")
 		})
+
+		PatchConvey("test extra vectors", func() {
+			newConfig := func(extra ...*VectorConfig) *IndexerConfig {
+				return &IndexerConfig{
+					ClientConfig: &milvusclient.ClientConfig{Address: "localhost:19530"},
+					Embedding:    mockEmb,
+					Vector:       &VectorConfig{Dimension: 128},
+					ExtraVectors: extra,
+				}
+			}
+
+			image := &VectorConfig{VectorField: "image_vector", Dimension: 512, Embedding: mockEmb}
+			config := newConfig(image)
+			convey.So(config.validate(), convey.ShouldBeNil)
+			convey.So(config.ExtraVectors[0].MetricType, convey.ShouldEqual, L2)
+			// the default is set on a copy
+			convey.So(image.MetricType, convey.ShouldEqual, MetricType(""))
+
+			err := newConfig(&VectorConfig{Dimension: 512, Embedding: mockEmb}).validate()
+			convey.So(err.Error(), convey.ShouldContainSubstring, "vector field of extra vector config 0 is required")
+
+			err = newConfig(&VectorConfig{VectorField: defaultVectorField, Embedding: mockEmb}).validate()
+			convey.So(err.Error(), convey.ShouldContainSubstring, "duplicate vector field: vector")
+
+			err = newConfig(&VectorConfig{VectorField: "image_vector"}).validate()
+			convey.So(err.Error(), convey.ShouldContainSubstring, "either Embedding or VectorProvider is required")
+		})
+
+		PatchConvey("test custom scalar fields", func() {
+			config := &IndexerConfig{
+				ClientConfig:  &milvusclient.ClientConfig{Address: "localhost:19530"},
+				Embedding:     mockEmb,
+				Vector:        &VectorConfig{Dimension: 128},
+				Sparse:        &SparseVectorConfig{},
+				IDField:       "pk",
+				ContentField:  "text",
+				MetadataField: "attrs",
+			}
+			convey.So(config.validate(), convey.ShouldBeNil)
+			convey.So(config.Functions[0].InputFieldNames, convey.ShouldResemble, []string{"text"})
+
+			sch, err := buildSchema(config)
+			convey.So(err, convey.ShouldBeNil)
+			names := make([]string, 0, len(sch.Fields))
+			for _, f := range sch.Fields {
+				names = append(names, f.Name)
+			}
+			convey.So(names[:3], convey.ShouldResemble, []string{"pk", "text", "attrs"})
+
+			columns, err := config.DocumentConverter(context.Background(),
+				[]*schema.Document{{ID: "doc1", Content: "content1"}}, [][]float64{{0.1, 0.2}})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(columns[0].Name(), convey.ShouldEqual, "pk")
+			convey.So(columns[1].Name(), convey.ShouldEqual, "text")
+			convey.So(columns[2].Name(), convey.ShouldEqual, "attrs")
+
+			config = &IndexerConfig{
+				ClientConfig: &milvusclient.ClientConfig{Address: "localhost:19530"},
+				Vector:       &VectorConfig{Dimension: 128},
+				ContentField: defaultMetadataField,
+			}
+			convey.So(config.validate().Error(), convey.ShouldContainSubstring, "must be distinct")
+
+			config = &IndexerConfig{
+				ClientConfig: &milvusclient.ClientConfig{Address: "localhost:19530"},
+				Vector:       &VectorConfig{Dimension: 128, VectorField: defaultContentField},
+			}
+			convey.So(config.validate().Error(), convey.ShouldContainSubstring, "duplicate vector field: content")
+		})
 	})
 }
 
@@ -376,7 +460,7 @@ func TestNewIndexer(t *testing.T) {
 
 		PatchConvey("test collection does not exist, needs creation", func() {
 			Mock(GetMethod(mockClient, "HasCollection")).Return(false, nil).Build()
-			Mock(GetMethod(mockClient, "CreateCollection")).Return(nil).Build()
+			createMocker := Mock(GetMethod(mockClient, "CreateCollection")).Return(nil).Build()
 			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateNotLoad}, nil).Build()
 			Mock(GetMethod(mockClient, "ListIndexes")).Return([]string{}, nil).Build()
 			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{}, fmt.Errorf("index not found")).Build()
@@ -398,9 +482,11 @@ func TestNewIndexer(t *testing.T) {
 				Vector: &VectorConfig{
 					Dimension: 128,
 				},
+				CollectionProperties: map[string]string{PropertyMmapEnabled: "true"},
 			})
 			convey.So(err, convey.ShouldBeNil)
 			convey.So(indexer, convey.ShouldNotBeNil)
+			convey.So(createMocker.Times(), convey.ShouldEqual, 1)
 		})
 
 		PatchConvey("test collection does not exist but dimension not provided", func() {
@@ -435,6 +521,44 @@ func TestIndexer_IsCallbacksEnabled(t *testing.T) {
 			config: &IndexerConfig{},
 		}
 		convey.So(indexer.IsCallbacksEnabled(), convey.ShouldBeTrue)
+
+		enableCallbacks := false
+		indexer.config.EnableCallbacks = &enableCallbacks
+		convey.So(indexer.IsCallbacksEnabled(), convey.ShouldBeFalse)
+	})
+}
+
+func TestIndexer_UpdateProperties(t *testing.T) {
+	PatchConvey("test Indexer.UpdateProperties", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		indexer := &Indexer{
+			client: mockClient,
+			config: &IndexerConfig{Collection: "test_collection"},
+		}
+
+		PatchConvey("test empty properties", func() {
+			mocker := Mock(GetMethod(mockClient, "AlterCollectionProperties")).Return(nil).Build()
+			convey.So(indexer.UpdateProperties(ctx, nil), convey.ShouldBeNil)
+			convey.So(mocker.Times(), convey.ShouldEqual, 0)
+		})
+
+		PatchConvey("test success", func() {
+			mocker := Mock(GetMethod(mockClient, "AlterCollectionProperties")).Return(nil).Build()
+			err := indexer.UpdateProperties(ctx, map[string]string{
+				PropertyMmapEnabled:          "true",
+				PropertyCollectionTTLSeconds: "86400",
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(mocker.Times(), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test alter error", func() {
+			Mock(GetMethod(mockClient, "AlterCollectionProperties")).Return(fmt.Errorf("alter error")).Build()
+			err := indexer.UpdateProperties(ctx, map[string]string{PropertyMmapEnabled: "true"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "alter error")
+		})
 	})
 }
 
@@ -452,9 +576,9 @@ func TestIndexer_Store(t *testing.T) {
 					Dimension: 128,
 				},
 				Embedding: mockEmb,
-				DocumentConverter: defaultDocumentConverter(&VectorConfig{
+				DocumentConverter: defaultDocumentConverter(defaultScalarFields, &VectorConfig{
 					VectorField: defaultVectorField,
-				}, nil),
+				}, nil, nil),
 			},
 		}
 
@@ -503,15 +627,285 @@ func TestIndexer_Store(t *testing.T) {
 			convey.So(ids, convey.ShouldNotBeNil)
 			convey.So(len(ids), convey.ShouldEqual, 2)
 		})
+
+		PatchConvey("test store with extra vectors", func() {
+			indexer.config.Embedding = mockEmb
+			var providedDocs []*schema.Document
+			indexer.config.ExtraVectors = []*VectorConfig{
+				{
+					VectorField: "image_vector",
+					Dimension:   2,
+					VectorProvider: func(ctx context.Context, docs []*schema.Document) ([][]float64, error) {
+						providedDocs = docs
+						return [][]float64{{0.1, 0.2}, {0.3, 0.4}}, nil
+					},
+				},
+			}
+
+			mockResult := milvusclient.UpsertResult{
+				IDs: column.NewColumnVarChar("id", []string{"doc1", "doc2"}),
+			}
+			Mock(GetMethod(mockClient, "Upsert")).Return(mockResult, nil).Build()
+
+			ids, err := indexer.Store(ctx, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(ids), convey.ShouldEqual, 2)
+			convey.So(providedDocs, convey.ShouldResemble, docs)
+
+			indexer.config.ExtraVectors[0].VectorProvider = func(ctx context.Context, docs []*schema.Document) ([][]float64, error) {
+				return [][]float64{{0.1, 0.2}}, nil
+			}
+			_, err = indexer.Store(ctx, docs)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "result length mismatch for field image_vector")
+		})
+
+		PatchConvey("test store reports metrics in callback extra", func() {
+			indexer.config.Embedding = mockEmb
+			indexer.stats = newCollectionStats(time.Minute)
+
+			mockResult := milvusclient.UpsertResult{
+				IDs: column.NewColumnVarChar("id", []string{"doc1", "doc2"}),
+			}
+			Mock(GetMethod(mockClient, "Upsert")).Return(mockResult, nil).Build()
+			Mock(GetMethod(mockClient, "GetCollectionStats")).Return(map[string]string{"row_count": "42"}, nil).Build()
+
+			indexer.config.Vector.MetricType = COSINE
+			indexer.config.Vector.IndexBuilder = NewHNSWIndexBuilder()
+
+			var inputExtra, extra map[string]any
+			handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				inputExtra = einoindexer.ConvCallbackInput(input).Extra
+				return ctx
+			}).OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				extra = einoindexer.ConvCallbackOutput(output).Extra
+				return ctx
+			}).Build()
+
+			_, err := indexer.Store(callbacks.InitCallbacks(ctx, nil, handler), docs, WithPartition("p1"))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(extra[CallbackExtraKeyUpsertCount], convey.ShouldEqual, 2)
+			convey.So(extra[CallbackExtraKeyCollectionRowCount], convey.ShouldEqual, int64(42))
+			_, ok := extra[CallbackExtraKeyLatency].(time.Duration)
+			convey.So(ok, convey.ShouldBeTrue)
+
+			convey.So(inputExtra[CallbackExtraKeyCollection], convey.ShouldEqual, "test_collection")
+			convey.So(inputExtra[CallbackExtraKeyPartition], convey.ShouldEqual, "p1")
+			convey.So(inputExtra[CallbackExtraKeyIndexType], convey.ShouldEqual, "HNSW")
+			convey.So(inputExtra[CallbackExtraKeyMetricType], convey.ShouldEqual, "COSINE")
+		})
+
+		PatchConvey("test store with continue on error", func() {
+			indexer.config.Embedding = nil
+			indexer.config.MaxContentLength = 10
+			badDocs := []*schema.Document{
+				(&schema.Document{ID: "doc1", Content: "ok"}).WithDenseVector([]float64{0.1, 0.2}),
+				{ID: "doc2", Content: "no vector"},
+				(&schema.Document{ID: "doc3", Content: "content too long"}).WithDenseVector([]float64{0.1, 0.2}),
+				(&schema.Document{ID: "doc4", Content: "ok"}).WithDenseVector([]float64{0.3, 0.4}),
+			}
+
+			mocker := Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{
+				IDs: column.NewColumnVarChar("id", []string{"doc1", "doc4"}),
+			}, nil).Build()
+
+			_, err := indexer.Store(ctx, badDocs)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(mocker.Times(), convey.ShouldEqual, 0)
+
+			var extra map[string]any
+			handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				extra = einoindexer.ConvCallbackOutput(output).Extra
+				return ctx
+			}).Build()
+
+			ids, err := indexer.Store(callbacks.InitCallbacks(ctx, nil, handler), badDocs, WithContinueOnError(true))
+			convey.So(ids, convey.ShouldResemble, []string{"doc1", "doc4"})
+			var storeErr *StoreError
+			convey.So(errors.As(err, &storeErr), convey.ShouldBeTrue)
+			convey.So(storeErr.Result.SucceededIDs, convey.ShouldResemble, []string{"doc1", "doc4"})
+			convey.So(len(storeErr.Result.Failed), convey.ShouldEqual, 2)
+			convey.So(storeErr.Result.Failed[0].Index, convey.ShouldEqual, 1)
+			convey.So(storeErr.Result.Failed[0].Err.Error(), convey.ShouldContainSubstring, "vector data missing")
+			convey.So(storeErr.Result.Failed[1].Index, convey.ShouldEqual, 2)
+			convey.So(storeErr.Result.Failed[1].Err.Error(), convey.ShouldContainSubstring, "exceeds max length")
+			convey.So(mocker.Times(), convey.ShouldEqual, 1)
+			convey.So(extra[CallbackExtraKeyFailedCount], convey.ShouldEqual, 2)
+
+			ids, err = indexer.Store(ctx, badDocs[1:3], WithContinueOnError(true))
+			convey.So(errors.As(err, &storeErr), convey.ShouldBeTrue)
+			convey.So(ids, convey.ShouldBeEmpty)
+			convey.So(len(storeErr.Result.Failed), convey.ShouldEqual, 2)
+			convey.So(mocker.Times(), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test store with precomputed vectors", func() {
+			indexer.config.Embedding = nil
+			indexer.requireDocVectors = true
+			mocker := Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{
+				IDs: column.NewColumnVarChar("id", []string{"doc1", "doc2"}),
+			}, nil).Build()
+
+			_, err := indexer.Store(ctx, []*schema.Document{
+				(&schema.Document{ID: "doc1"}).WithDenseVector([]float64{0.1, 0.2}),
+				{ID: "doc2"},
+			})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "vector data missing for document 1 (id: doc2)")
+			convey.So(mocker.Times(), convey.ShouldEqual, 0)
+
+			ids, err := indexer.Store(ctx, []*schema.Document{
+				(&schema.Document{ID: "doc1"}).WithDenseVector([]float64{0.1, 0.2}),
+				WithFloat32Vector(&schema.Document{ID: "doc2"}, []float32{0.3, 0.4}),
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids, convey.ShouldResemble, []string{"doc1", "doc2"})
+			convey.So(mocker.Times(), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test store with vector provider", func() {
+			var provided []string
+			indexer.config.Vector.VectorProvider = func(ctx context.Context, docs []*schema.Document) ([][]float64, error) {
+				vectors := make([][]float64, 0, len(docs))
+				for _, doc := range docs {
+					provided = append(provided, doc.ID)
+					vectors = append(vectors, []float64{0.1, 0.2})
+				}
+				return vectors, nil
+			}
+			indexer.config.Embedding = &mockEmbedding{err: fmt.Errorf("embedding must not be called")}
+			Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{
+				IDs: column.NewColumnVarChar("id", []string{"doc1", "doc2"}),
+			}, nil).Build()
+
+			ids, err := indexer.Store(ctx, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids, convey.ShouldResemble, []string{"doc1", "doc2"})
+			convey.So(provided, convey.ShouldResemble, []string{"doc1", "doc2"})
+
+			indexer.config.Vector.VectorProvider = func(ctx context.Context, docs []*schema.Document) ([][]float64, error) {
+				return [][]float64{{0.1, 0.2}}, nil
+			}
+			_, err = indexer.Store(ctx, docs)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "vector provider result length mismatch")
+		})
+
+		PatchConvey("test store rejects empty ids", func() {
+			indexer.requireDocIDs = true
+			mocker := Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{
+				IDs: column.NewColumnVarChar("id", []string{"doc1"}),
+			}, nil).Build()
+			input := []*schema.Document{{ID: "doc1", Content: "one"}, {Content: "two"}}
+
+			_, err := indexer.Store(ctx, input)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "id of document 1 is empty")
+			convey.So(mocker.Times(), convey.ShouldEqual, 0)
+
+			ids, err := indexer.Store(ctx, input, WithContinueOnError(true))
+			var storeErr *StoreError
+			convey.So(errors.As(err, &storeErr), convey.ShouldBeTrue)
+			convey.So(ids, convey.ShouldResemble, []string{"doc1"})
+			convey.So(storeErr.Result.Failed[0].Index, convey.ShouldEqual, 1)
+			convey.So(storeErr.Result.Failed[0].Err.Error(), convey.ShouldContainSubstring, "id of document 1 is empty")
+		})
+
+		PatchConvey("test store error without failures", func() {
+			convey.So((&StoreError{}).Error(), convey.ShouldContainSubstring, "no document rejected")
+			convey.So((&StoreError{Result: &StoreResult{}}).Error(), convey.ShouldContainSubstring, "no document rejected")
+		})
+
+		PatchConvey("test store with vector embedding", func() {
+			indexer.config.Vector.Embedding = &mockEmbedding{dims: 2}
+			indexer.config.Embedding = &mockEmbedding{err: fmt.Errorf("embedding must not be called")}
+			Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{
+				IDs: column.NewColumnVarChar("id", []string{"doc1", "doc2"}),
+			}, nil).Build()
+
+			ids, err := indexer.Store(ctx, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids, convey.ShouldResemble, []string{"doc1", "doc2"})
+		})
+
+		PatchConvey("test store writes back vectors", func() {
+			indexer.config.MaxContentLength = 10
+			indexer.config.Vector.VectorProvider = func(ctx context.Context, docs []*schema.Document) ([][]float64, error) {
+				vectors := make([][]float64, 0, len(docs))
+				for _, doc := range docs {
+					vectors = append(vectors, []float64{float64(len(doc.Content)), 0.5})
+				}
+				return vectors, nil
+			}
+			Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{
+				IDs: column.NewColumnVarChar("id", []string{"doc1", "doc3"}),
+			}, nil).Build()
+
+			input := []*schema.Document{
+				{ID: "doc1", Content: "one"},
+				{ID: "doc2", Content: "content too long"},
+				{ID: "doc3", Content: "three"},
+			}
+			_, err := indexer.Store(ctx, input[:1])
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(input[0].DenseVector(), convey.ShouldBeNil)
+
+			var extra map[string]any
+			handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				extra = einoindexer.ConvCallbackOutput(output).Extra
+				return ctx
+			}).Build()
+
+			indexer.config.WriteBackVectors = true
+			ids, err := indexer.Store(callbacks.InitCallbacks(ctx, nil, handler), input, WithContinueOnError(true))
+			convey.So(ids, convey.ShouldResemble, []string{"doc1", "doc3"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(input[0].DenseVector(), convey.ShouldResemble, []float64{3, 0.5})
+			convey.So(input[1].DenseVector(), convey.ShouldBeNil)
+			convey.So(input[2].DenseVector(), convey.ShouldResemble, []float64{5, 0.5})
+			convey.So(extra[CallbackExtraKeyDenseVectors], convey.ShouldResemble, [][]float64{{3, 0.5}, nil, {5, 0.5}})
+
+			extra = nil
+			input = []*schema.Document{{ID: "doc1", Content: "one"}}
+			_, err = indexer.Store(callbacks.InitCallbacks(ctx, nil, handler), input, WithWriteBackVectors(false))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(input[0].DenseVector(), convey.ShouldBeNil)
+			convey.So(extra, convey.ShouldNotContainKey, CallbackExtraKeyDenseVectors)
+		})
+
+		PatchConvey("test store with callbacks disabled", func() {
+			mockResult := milvusclient.UpsertResult{
+				IDs: column.NewColumnVarChar("id", []string{"doc1", "doc2"}),
+			}
+			Mock(GetMethod(mockClient, "Upsert")).Return(mockResult, nil).Build()
+
+			var started, ended int
+			handler := callbacks.NewHandlerBuilder().
+				OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+					started++
+					return ctx
+				}).
+				OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+					ended++
+					return ctx
+				}).Build()
+
+			enableCallbacks := false
+			indexer.config.EnableCallbacks = &enableCallbacks
+			ids, err := indexer.Store(callbacks.InitCallbacks(ctx, nil, handler), docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ids, convey.ShouldResemble, []string{"doc1", "doc2"})
+			convey.So(started, convey.ShouldEqual, 0)
+			convey.So(ended, convey.ShouldEqual, 0)
+		})
 	})
 }
 
 func TestDefaultDocumentConverter(t *testing.T) {
 	convey.Convey("test defaultDocumentConverter", t, func() {
 		convey.Convey("test conversion (dense only)", func() {
-			converter := defaultDocumentConverter(&VectorConfig{
+			converter := defaultDocumentConverter(defaultScalarFields, &VectorConfig{
 				VectorField: defaultVectorField,
-			}, nil)
+			}, nil, nil)
 
 			ctx := context.Background()
 			docs := []*schema.Document{
@@ -531,12 +925,12 @@ func TestDefaultDocumentConverter(t *testing.T) {
 		})
 
 		convey.Convey("test conversion with sparse vector", func() {
-			converter := defaultDocumentConverter(&VectorConfig{
+			converter := defaultDocumentConverter(defaultScalarFields, &VectorConfig{
 				VectorField: defaultVectorField,
 			}, &SparseVectorConfig{
 				VectorField: "sparse_vector",
 				Method:      SparseMethodPrecomputed,
-			})
+			}, nil)
 
 			ctx := context.Background()
 			docs := []*schema.Document{
@@ -558,3 +952,52 @@ func TestDefaultDocumentConverter(t *testing.T) {
 
 	})
 }
+
+func TestAppendExtraVectorColumns(t *testing.T) {
+	convey.Convey("test appendExtraVectorColumns", t, func() {
+		base := []column.Column{
+			column.NewColumnVarChar(defaultIDField, []string{"doc1"}),
+		}
+		confs := []*VectorConfig{
+			{VectorField: "image_vector", Dimension: 2},
+			{VectorField: "audio_vector"},
+		}
+
+		convey.Convey("test append columns", func() {
+			columns, err := appendExtraVectorColumns(base, confs, [][][]float64{{{0.1, 0.2}}, {{0.3, 0.4, 0.5}}}, 1)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(columns), convey.ShouldEqual, 3)
+			convey.So(columns[1].Name(), convey.ShouldEqual, "image_vector")
+			convey.So(columns[2].Name(), convey.ShouldEqual, "audio_vector")
+			convey.So(columns[2].Len(), convey.ShouldEqual, 1)
+		})
+
+		convey.Convey("test skip columns returned by converter", func() {
+			converted := append(base, column.NewColumnFloatVector("image_vector", 2, [][]float32{{1, 2}}))
+			columns, err := appendExtraVectorColumns(converted, confs[:1], [][][]float64{{{0.1, 0.2}}}, 1)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(columns), convey.ShouldEqual, 2)
+		})
+
+		convey.Convey("test dimension mismatch", func() {
+			_, err := appendExtraVectorColumns(base, confs[:1], [][][]float64{{{0.1, 0.2, 0.3}}}, 1)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "need 2, got 3")
+		})
+
+		convey.Convey("test missing vectors of a field", func() {
+			_, err := appendExtraVectorColumns(base, confs, [][][]float64{{{0.1, 0.2}}}, 1)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "vectors of field audio_vector missing: need 1, got 0")
+
+			_, err = appendExtraVectorColumns(base, confs[:1], [][][]float64{nil}, 1)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test missing vector", func() {
+			_, err := appendExtraVectorColumns(base, confs[:1], [][][]float64{{nil}}, 1)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "vector data of field image_vector missing")
+		})
+	})
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// Keys of the Extra of indexer.CallbackOutput.
+const (
+	// CallbackExtraKeyLatency is the time.Duration spent on converting and upserting the rows, excluding embedding.
+	CallbackExtraKeyLatency = "milvus2_latency"
+	// CallbackExtraKeyUpsertCount is the number of rows upserted, which may be larger than the number of
+	// input documents when ContentOverflowSplit is used.
+	CallbackExtraKeyUpsertCount = "milvus2_upsert_count"
+	// CallbackExtraKeyCollectionRowCount is the row count of the collection reported by Milvus,
+	// only set when IndexerConfig.CollectionStatsInterval is enabled.
+	CallbackExtraKeyCollectionRowCount = "milvus2_collection_row_count"
+	// CallbackExtraKeyWALPending is the number of batches in the write-ahead buffer waiting to be upserted
+	// after the Store call, only set when IndexerConfig.WAL is enabled.
+	CallbackExtraKeyWALPending = "milvus2_wal_pending"
+	// CallbackExtraKeyFailedCount is the number of documents rejected by a Store with ContinueOnError, only set when positive.
+	CallbackExtraKeyFailedCount = "milvus2_failed_count"
+	// CallbackExtraKeyDenseVectors is the [][]float64 of the dense vectors computed for the input documents,
+	// in their order and nil for the rejected documents, only set when IndexerConfig.WriteBackVectors is enabled.
+	CallbackExtraKeyDenseVectors = "milvus2_dense_vectors"
+)
+
+// Keys of the Extra of indexer.CallbackInput, describing the target collection
+// so that APM dashboards can slice the writes. See SpanAttributes.
+const (
+	// CallbackExtraKeyCollection is the name of the target collection.
+	CallbackExtraKeyCollection = "milvus2_collection"
+	// CallbackExtraKeyPartition is the target partition, only set when a partition is configured or given by WithPartition.
+	CallbackExtraKeyPartition = "milvus2_partition"
+	// CallbackExtraKeyIndexType is the configured index type of the vector field,
+	// or of the sparse vector field when IndexerConfig.Vector is not set.
+	// It reflects the configuration, not an index which already existed before NewIndexer.
+	CallbackExtraKeyIndexType = "milvus2_index_type"
+	// CallbackExtraKeyMetricType is the metric type of the field of CallbackExtraKeyIndexType.
+	CallbackExtraKeyMetricType = "milvus2_metric_type"
+)
+
+// collectionStats caches the collection row count reported by Milvus,
+// refreshing it at most once per interval. A nil *collectionStats is disabled.
+type collectionStats struct {
+	interval time.Duration
+
+	mu        sync.Mutex
+	updatedAt time.Time
+	rowCount  int64
+	valid     bool
+}
+
+func newCollectionStats(interval time.Duration) *collectionStats {
+	if interval <= 0 {
+		return nil
+	}
+	return &collectionStats{interval: interval}
+}
+
+// getRowCount returns the cached row count, refreshing it if it is stale.
+// Refresh failures are ignored and the last known value, if any, is returned.
+func (s *collectionStats) getRowCount(ctx context.Context, cli *milvusclient.Client, collection string) (int64, bool) {
+	if s == nil || cli == nil {
+		return 0, false
+	}
+
+	s.mu.Lock()
+	if time.Since(s.updatedAt) < s.interval {
+		defer s.mu.Unlock()
+		return s.rowCount, s.valid
+	}
+	// claim the refresh so that the concurrent calls return the cached value instead of waiting for the RPC
+	s.updatedAt = time.Now()
+	s.mu.Unlock()
+
+	stats, err := cli.GetCollectionStats(ctx, milvusclient.NewGetCollectionStatsOption(collection))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		return s.rowCount, s.valid
+	}
+	n, err := strconv.ParseInt(stats["row_count"], 10, 64)
+	if err != nil {
+		return s.rowCount, s.valid
+	}
+	s.rowCount, s.valid = n, true
+	return s.rowCount, s.valid
+}
+
+// newInputExtra returns the Extra of indexer.CallbackInput which does not depend on the Store call,
+// built once by NewIndexer since building the index of the config to read its type is not free.
+func newInputExtra(conf *IndexerConfig) map[string]any {
+	extra := map[string]any{
+		CallbackExtraKeyCollection: conf.Collection,
+	}
+
+	var idx index.Index
+	var metricType MetricType
+	switch {
+	case conf.Vector != nil:
+		metricType = conf.Vector.MetricType
+		if conf.Vector.IndexBuilder != nil {
+			idx = conf.Vector.IndexBuilder.Build(metricType)
+		} else {
+			idx = index.NewAutoIndex(metricType.toEntity())
+		}
+	case conf.Sparse != nil:
+		metricType = conf.Sparse.MetricType
+		if conf.Sparse.IndexBuilder != nil {
+			idx = conf.Sparse.IndexBuilder.Build(metricType)
+		} else {
+			idx = NewSparseInvertedIndexBuilder().Build(metricType)
+		}
+	}
+	if idx != nil {
+		extra[CallbackExtraKeyIndexType] = string(idx.IndexType())
+	}
+	if metricType != "" {
+		extra[CallbackExtraKeyMetricType] = string(metricType)
+	}
+	return extra
+}
+
+// callbackInputExtra returns the Extra of indexer.CallbackInput describing the write.
+func (i *Indexer) callbackInputExtra(partition string) map[string]any {
+	base := i.inputExtra
+	if base == nil {
+		// the Indexer was not created by NewIndexer
+		base = newInputExtra(i.config)
+	}
+	extra := make(map[string]any, len(base)+1)
+	for k, v := range base {
+		extra[k] = v
+	}
+	if partition != "" {
+		extra[CallbackExtraKeyPartition] = partition
+	}
+	return extra
+}
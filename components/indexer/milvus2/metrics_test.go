@@ -0,0 +1,97 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestCallbackInputExtra(t *testing.T) {
+	convey.Convey("test callbackInputExtra", t, func() {
+		convey.Convey("test vector with auto index", func() {
+			extra := (&Indexer{config: &IndexerConfig{
+				Collection: "c",
+				Vector:     &VectorConfig{MetricType: L2},
+			}}).callbackInputExtra("")
+			convey.So(extra, convey.ShouldResemble, map[string]any{
+				CallbackExtraKeyCollection: "c",
+				CallbackExtraKeyIndexType:  "AUTOINDEX",
+				CallbackExtraKeyMetricType: "L2",
+			})
+		})
+
+		convey.Convey("test sparse only", func() {
+			extra := (&Indexer{config: &IndexerConfig{
+				Collection: "c",
+				Sparse:     &SparseVectorConfig{MetricType: BM25},
+			}}).callbackInputExtra("p1")
+			convey.So(extra, convey.ShouldResemble, map[string]any{
+				CallbackExtraKeyCollection: "c",
+				CallbackExtraKeyPartition:  "p1",
+				CallbackExtraKeyIndexType:  "SPARSE_INVERTED_INDEX",
+				CallbackExtraKeyMetricType: "BM25",
+			})
+		})
+
+		convey.Convey("test index is built once", func() {
+			builder := &countingIndexBuilder{IndexBuilder: NewHNSWIndexBuilder()}
+			i := &Indexer{config: &IndexerConfig{
+				Collection: "c",
+				Vector:     &VectorConfig{MetricType: COSINE, IndexBuilder: builder},
+			}}
+			i.inputExtra = newInputExtra(i.config)
+			convey.So(i.callbackInputExtra("p1")[CallbackExtraKeyIndexType], convey.ShouldEqual, "HNSW")
+			convey.So(i.callbackInputExtra("")[CallbackExtraKeyPartition], convey.ShouldBeNil)
+			convey.So(builder.builds, convey.ShouldEqual, 1)
+		})
+	})
+}
+
+func TestSpanAttributes(t *testing.T) {
+	convey.Convey("test SpanAttributes", t, func() {
+		attrs := SpanAttributes(map[string]any{
+			CallbackExtraKeyCollection:  "c",
+			CallbackExtraKeyIndexType:   "HNSW",
+			CallbackExtraKeyUpsertCount: 2,
+			CallbackExtraKeyLatency:     20 * time.Millisecond,
+			"unknown":                   "ignored",
+		})
+		convey.So(attrs, convey.ShouldResemble, []attribute.KeyValue{
+			attribute.String("db.system", "milvus"),
+			attribute.String("db.collection.name", "c"),
+			attribute.String("milvus.index_type", "HNSW"),
+			attribute.Int("milvus.upsert_count", 2),
+			attribute.Int64("milvus.latency_ms", 20),
+		})
+	})
+}
+
+// countingIndexBuilder counts the indexes built by the wrapped IndexBuilder.
+type countingIndexBuilder struct {
+	IndexBuilder
+	builds int
+}
+
+func (b *countingIndexBuilder) Build(metricType MetricType) index.Index {
+	b.builds++
+	return b.IndexBuilder.Build(metricType)
+}
@@ -0,0 +1,287 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+const defaultMigrationBatchSize = 1000
+
+// MigrationConfig contains configuration for Migrate.
+type MigrationConfig struct {
+	// Client is the Milvus client connected to the server holding both collections.
+	// Required.
+	Client *milvusclient.Client
+
+	// SourceCollection is the collection created by the milvus component (V1 SDK).
+	// Default: "eino_collection"
+	SourceCollection string
+
+	// TargetCollection is the collection to create and copy the rows to.
+	// If it already exists, it is not recreated and the rows are upserted into it,
+	// so an interrupted migration can simply be run again.
+	// Required, must differ from SourceCollection.
+	TargetCollection string
+
+	// Description is the description of the target collection.
+	// Default: the description of the source collection
+	Description string
+
+	// VectorField is the vector field to index in the target collection.
+	// Default: "vector"
+	VectorField string
+
+	// MetricType is the metric type of the vector index.
+	// Default: HAMMING, the metric of the binary vectors written by the milvus component
+	MetricType MetricType
+
+	// IndexBuilder specifies how to build the vector index.
+	// If nil, uses AutoIndex.
+	IndexBuilder IndexBuilder
+
+	// ConsistencyLevel of the target collection.
+	// Default: the consistency level of the source collection
+	ConsistencyLevel ConsistencyLevel
+
+	// BatchSize is the number of rows read from the source and written to the target at once.
+	// Default: 1000
+	BatchSize int
+
+	// OnProgress is called after each copied batch.
+	// Optional.
+	OnProgress func(progress MigrationProgress)
+}
+
+// MigrationProgress reports the progress of Migrate.
+type MigrationProgress struct {
+	// Copied is the number of rows copied so far.
+	Copied int64
+	// Total is the row count of the source collection reported by Milvus when the copy started,
+	// 0 if it is unknown.
+	Total int64
+}
+
+// Migrate copies a collection created by the milvus component (V1 SDK) into a new collection for this component.
+// The target collection gets the same fields as the source, including the binary vector field,
+// which the milvus2 retriever searches with the binary metrics, e.g. HAMMING.
+// Auto ID primary keys are turned into plain primary keys so that the document IDs are kept.
+// The rows are read in pages of BatchSize ordered by primary key and upserted into the target collection.
+func Migrate(ctx context.Context, config *MigrationConfig) (*MigrationProgress, error) {
+	if config == nil {
+		return nil, fmt.Errorf("[Migrate] config not provided")
+	}
+	// validate fills in the defaults, keep the caller's config untouched
+	c := *config
+	conf := &c
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	cli := conf.Client
+
+	source, err := cli.DescribeCollection(ctx, milvusclient.NewDescribeCollectionOption(conf.SourceCollection))
+	if err != nil {
+		return nil, fmt.Errorf("[Migrate] failed to describe source collection: %w", err)
+	}
+	if source.Schema == nil || len(source.Schema.Fields) == 0 {
+		return nil, fmt.Errorf("[Migrate] source collection %q has no fields", conf.SourceCollection)
+	}
+
+	if err = createMigrationTarget(ctx, conf, source); err != nil {
+		return nil, err
+	}
+	if err = loadCollection(ctx, cli, conf.SourceCollection); err != nil {
+		return nil, err
+	}
+
+	progress := &MigrationProgress{}
+	if stats, err := cli.GetCollectionStats(ctx, milvusclient.NewGetCollectionStatsOption(conf.SourceCollection)); err == nil {
+		progress.Total, _ = strconv.ParseInt(stats["row_count"], 10, 64)
+	}
+
+	outputFields := make([]string, 0, len(source.Schema.Fields))
+	pkField := ""
+	for _, f := range source.Schema.Fields {
+		outputFields = append(outputFields, f.Name)
+		if f.PrimaryKey {
+			pkField = f.Name
+		}
+	}
+	if pkField == "" {
+		return progress, fmt.Errorf("[Migrate] source collection %q has no primary key field", conf.SourceCollection)
+	}
+
+	// Milvus returns limited query results ordered by primary key,
+	// so the rows are paged with a primary key cursor instead of an offset
+	var lastPK any
+	for {
+		queryOpt := milvusclient.NewQueryOption(conf.SourceCollection).
+			WithOutputFields(outputFields...).
+			WithLimit(conf.BatchSize)
+		if lastPK != nil {
+			queryOpt = queryOpt.WithFilter(pkField+" > {last_pk}").WithTemplateParam("last_pk", lastPK)
+		}
+		res, err := cli.Query(ctx, queryOpt)
+		if err != nil {
+			return progress, fmt.Errorf("[Migrate] failed to read source rows: %w", err)
+		}
+		if res.ResultCount == 0 {
+			break
+		}
+
+		upsertOpt := milvusclient.NewColumnBasedInsertOption(conf.TargetCollection, res.Fields...)
+		if _, err = cli.Upsert(ctx, upsertOpt); err != nil {
+			return progress, fmt.Errorf("[Migrate] failed to write %d rows after %d copied: %w", res.ResultCount, progress.Copied, err)
+		}
+
+		progress.Copied += int64(res.ResultCount)
+		if conf.OnProgress != nil {
+			conf.OnProgress(*progress)
+		}
+		if res.ResultCount < conf.BatchSize {
+			break
+		}
+
+		pk := res.GetColumn(pkField)
+		if pk == nil {
+			return progress, fmt.Errorf("[Migrate] source rows miss the primary key field %q", pkField)
+		}
+		if lastPK, err = pk.Get(res.ResultCount - 1); err != nil {
+			return progress, fmt.Errorf("[Migrate] failed to read the primary key: %w", err)
+		}
+	}
+
+	return progress, nil
+}
+
+func (c *MigrationConfig) validate() error {
+	if c.Client == nil {
+		return fmt.Errorf("[Migrate] milvus client not provided")
+	}
+	if c.SourceCollection == "" {
+		c.SourceCollection = defaultCollection
+	}
+	if c.TargetCollection == "" {
+		return fmt.Errorf("[Migrate] target collection not provided")
+	}
+	if c.TargetCollection == c.SourceCollection {
+		return fmt.Errorf("[Migrate] target collection must differ from source collection %q", c.SourceCollection)
+	}
+	if c.VectorField == "" {
+		c.VectorField = defaultVectorField
+	}
+	if c.MetricType == "" {
+		c.MetricType = HAMMING
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = defaultMigrationBatchSize
+	}
+	return nil
+}
+
+// createMigrationTarget creates and indexes the target collection with the fields of source,
+// unless it already exists.
+func createMigrationTarget(ctx context.Context, conf *MigrationConfig, source *entity.Collection) error {
+	cli := conf.Client
+	exists, err := cli.HasCollection(ctx, milvusclient.NewHasCollectionOption(conf.TargetCollection))
+	if err != nil {
+		return fmt.Errorf("[Migrate] failed to check target collection: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	sch, err := migrationSchema(conf, source)
+	if err != nil {
+		return err
+	}
+
+	createOpt := milvusclient.NewCreateCollectionOption(conf.TargetCollection, sch)
+	if conf.ConsistencyLevel != ConsistencyLevelDefault {
+		createOpt = createOpt.WithConsistencyLevel(conf.ConsistencyLevel.ToEntity())
+	} else {
+		createOpt = createOpt.WithConsistencyLevel(source.ConsistencyLevel)
+	}
+	if err = cli.CreateCollection(ctx, createOpt); err != nil {
+		return fmt.Errorf("[Migrate] failed to create target collection: %w", err)
+	}
+
+	var idx index.Index
+	if conf.IndexBuilder != nil {
+		idx = conf.IndexBuilder.Build(conf.MetricType)
+	} else {
+		idx = index.NewAutoIndex(conf.MetricType.toEntity())
+	}
+	createTask, err := cli.CreateIndex(ctx, milvusclient.NewCreateIndexOption(conf.TargetCollection, conf.VectorField, idx))
+	if err != nil {
+		return fmt.Errorf("[Migrate] failed to create index: %w", err)
+	}
+	if err = createTask.Await(ctx); err != nil {
+		return fmt.Errorf("[Migrate] failed to await index creation: %w", err)
+	}
+
+	return loadCollection(ctx, cli, conf.TargetCollection)
+}
+
+// migrationSchema returns the schema of the target collection, with the fields of source.
+func migrationSchema(conf *MigrationConfig, source *entity.Collection) (*entity.Schema, error) {
+	description := conf.Description
+	if description == "" {
+		description = source.Schema.Description
+	}
+	sch := entity.NewSchema().
+		WithName(conf.TargetCollection).
+		WithDescription(description).
+		WithDynamicFieldEnabled(source.Schema.EnableDynamicField)
+	hasVectorField := false
+	for _, f := range source.Schema.Fields {
+		field := *f
+		// keep the document IDs of the source rows
+		field.AutoID = false
+		sch.WithField(&field)
+		hasVectorField = hasVectorField || f.Name == conf.VectorField
+	}
+	if !hasVectorField {
+		return nil, fmt.Errorf("[Migrate] vector field %q not found in source collection %q", conf.VectorField, conf.SourceCollection)
+	}
+	return sch, nil
+}
+
+// loadCollection loads the collection unless it is already loaded.
+func loadCollection(ctx context.Context, cli *milvusclient.Client, collection string) error {
+	loadState, err := cli.GetLoadState(ctx, milvusclient.NewGetLoadStateOption(collection))
+	if err != nil {
+		return fmt.Errorf("[Migrate] failed to get load state of collection %q: %w", collection, err)
+	}
+	if loadState.State == entity.LoadStateLoaded {
+		return nil
+	}
+	loadTask, err := cli.LoadCollection(ctx, milvusclient.NewLoadCollectionOption(collection))
+	if err != nil {
+		return fmt.Errorf("[Migrate] failed to load collection %q: %w", collection, err)
+	}
+	if err = loadTask.Await(ctx); err != nil {
+		return fmt.Errorf("[Migrate] failed to await load of collection %q: %w", collection, err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestMigrationConfig_validate(t *testing.T) {
+	convey.Convey("test MigrationConfig.validate", t, func() {
+		mockClient := &milvusclient.Client{}
+
+		convey.Convey("test missing client", func() {
+			err := (&MigrationConfig{TargetCollection: "target"}).validate()
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test missing target collection", func() {
+			err := (&MigrationConfig{Client: mockClient}).validate()
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test same source and target", func() {
+			err := (&MigrationConfig{Client: mockClient, TargetCollection: defaultCollection}).validate()
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test defaults", func() {
+			conf := &MigrationConfig{Client: mockClient, TargetCollection: "target"}
+			convey.So(conf.validate(), convey.ShouldBeNil)
+			convey.So(conf.SourceCollection, convey.ShouldEqual, defaultCollection)
+			convey.So(conf.VectorField, convey.ShouldEqual, defaultVectorField)
+			convey.So(conf.MetricType, convey.ShouldEqual, HAMMING)
+			convey.So(conf.BatchSize, convey.ShouldEqual, defaultMigrationBatchSize)
+		})
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	PatchConvey("test Migrate", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+
+		source := &entity.Collection{
+			Name: defaultCollection,
+			Schema: entity.NewSchema().
+				WithName(defaultCollection).
+				WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeVarChar).WithMaxLength(255).WithIsPrimaryKey(true).WithIsAutoID(true)).
+				WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeBinaryVector).WithDim(16)).
+				WithField(entity.NewField().WithName("content").WithDataType(entity.FieldTypeVarChar).WithMaxLength(1024)).
+				WithField(entity.NewField().WithName("metadata").WithDataType(entity.FieldTypeJSON)),
+		}
+		batch := func(ids ...string) milvusclient.ResultSet {
+			return milvusclient.ResultSet{
+				ResultCount: len(ids),
+				Fields:      []column.Column{column.NewColumnVarChar("id", ids)},
+			}
+		}
+
+		Mock(GetMethod(mockClient, "DescribeCollection")).Return(source, nil).Build()
+		Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateLoaded}, nil).Build()
+		Mock(GetMethod(mockClient, "GetCollectionStats")).Return(map[string]string{"row_count": "3"}, nil).Build()
+		queryMocker := Mock(GetMethod(mockClient, "Query")).Return(Sequence(batch("1", "2"), nil).Then(batch("3"), nil)).Build()
+
+		PatchConvey("test create target and copy", func() {
+			Mock(GetMethod(mockClient, "HasCollection")).Return(false, nil).Build()
+			createMocker := Mock(GetMethod(mockClient, "CreateCollection")).Return(nil).Build()
+			mockTask := &milvusclient.CreateIndexTask{}
+			indexMocker := Mock(GetMethod(mockClient, "CreateIndex")).Return(mockTask, nil).Build()
+			Mock(GetMethod(mockTask, "Await")).Return(nil).Build()
+			upsertMocker := Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{}, nil).Build()
+
+			var reported []MigrationProgress
+			conf := &MigrationConfig{
+				Client:           mockClient,
+				TargetCollection: "eino_collection_v2",
+				BatchSize:        2,
+				OnProgress:       func(p MigrationProgress) { reported = append(reported, p) },
+			}
+			progress, err := Migrate(ctx, conf)
+			convey.So(err, convey.ShouldBeNil)
+			// the last page is shorter than BatchSize, no more query is needed
+			convey.So(queryMocker.Times(), convey.ShouldEqual, 2)
+			// the defaults are not written into the caller's config
+			convey.So(conf.SourceCollection, convey.ShouldEqual, "")
+			convey.So(*progress, convey.ShouldResemble, MigrationProgress{Copied: 3, Total: 3})
+			convey.So(reported, convey.ShouldResemble, []MigrationProgress{{Copied: 2, Total: 3}, {Copied: 3, Total: 3}})
+			convey.So(indexMocker.Times(), convey.ShouldEqual, 1)
+			convey.So(upsertMocker.Times(), convey.ShouldEqual, 2)
+			convey.So(createMocker.Times(), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test target schema", func() {
+			conf := &MigrationConfig{Client: mockClient, TargetCollection: "eino_collection_v2"}
+			convey.So(conf.validate(), convey.ShouldBeNil)
+			sch, err := migrationSchema(conf, source)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(sch.CollectionName, convey.ShouldEqual, "eino_collection_v2")
+			convey.So(len(sch.Fields), convey.ShouldEqual, 4)
+			convey.So(sch.Fields[0].AutoID, convey.ShouldBeFalse)
+			convey.So(sch.Fields[1].DataType, convey.ShouldEqual, entity.FieldTypeBinaryVector)
+			convey.So(source.Schema.Fields[0].AutoID, convey.ShouldBeTrue)
+		})
+
+		PatchConvey("test existing target is resumed", func() {
+			Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
+			createMocker := Mock(GetMethod(mockClient, "CreateCollection")).Return(nil).Build()
+			Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{}, nil).Build()
+
+			progress, err := Migrate(ctx, &MigrationConfig{Client: mockClient, TargetCollection: "eino_collection_v2", BatchSize: 2})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(progress.Copied, convey.ShouldEqual, 3)
+			convey.So(createMocker.Times(), convey.ShouldEqual, 0)
+		})
+
+		PatchConvey("test missing vector field", func() {
+			Mock(GetMethod(mockClient, "HasCollection")).Return(false, nil).Build()
+
+			_, err := Migrate(ctx, &MigrationConfig{Client: mockClient, TargetCollection: "eino_collection_v2", VectorField: "embedding"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "embedding")
+		})
+
+		PatchConvey("test upsert error", func() {
+			Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
+			Mock(GetMethod(mockClient, "Upsert")).Return(milvusclient.UpsertResult{}, fmt.Errorf("upsert error")).Build()
+
+			progress, err := Migrate(ctx, &MigrationConfig{Client: mockClient, TargetCollection: "eino_collection_v2"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(progress.Copied, convey.ShouldEqual, 0)
+		})
+	})
+}
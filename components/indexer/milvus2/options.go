@@ -23,6 +23,14 @@ type ImplOptions struct {
 	// Partition specifies the target partition for document insertion.
 	// If empty, documents are inserted into the default partition.
 	Partition string
+
+	// ContinueOnError rejects the invalid documents individually instead of failing the whole batch.
+	// Default: IndexerConfig.ContinueOnError
+	ContinueOnError bool
+
+	// WriteBackVectors attaches the computed dense vectors to the stored documents.
+	// Default: IndexerConfig.WriteBackVectors
+	WriteBackVectors bool
 }
 
 // WithPartition returns an option that sets the target partition for insertion.
@@ -31,3 +39,17 @@ func WithPartition(partition string) indexer.Option {
 		o.Partition = partition
 	})
 }
+
+// WithContinueOnError returns an option that overrides IndexerConfig.ContinueOnError.
+func WithContinueOnError(continueOnError bool) indexer.Option {
+	return indexer.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.ContinueOnError = continueOnError
+	})
+}
+
+// WithWriteBackVectors returns an option that overrides IndexerConfig.WriteBackVectors.
+func WithWriteBackVectors(writeBack bool) indexer.Option {
+	return indexer.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.WriteBackVectors = writeBack
+	})
+}
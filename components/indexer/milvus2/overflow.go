@@ -0,0 +1,294 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ContentOverflowPolicy defines how documents whose content exceeds MaxContentLength are handled.
+type ContentOverflowPolicy string
+
+const (
+	// ContentOverflowError rejects the whole batch if any document content is too long.
+	ContentOverflowError ContentOverflowPolicy = "error"
+	// ContentOverflowTruncate truncates the content to MaxContentLength bytes at a UTF-8 boundary.
+	ContentOverflowTruncate ContentOverflowPolicy = "truncate"
+	// ContentOverflowSplit splits the content into continuation rows.
+	// The first row keeps the document ID, following rows use "<id>#~<n>" as ID and
+	// carry MetaKeyContinuationOf and MetaKeyContinuationIndex in metadata.
+	// Every row is embedded with its own content, precomputed vectors of the document are shared by its rows.
+	// Document IDs containing ContinuationIDSeparator are rejected, so that they never collide with continuation rows.
+	// The continuation rows left by a previous version of a document are deleted after its rows are upserted,
+	// which relies on the filter on MetaKeyContinuationOf, so it does not work with MetadataCompression.
+	ContentOverflowSplit ContentOverflowPolicy = "split"
+	// ContentOverflowExternal stores the full content with ExternalContentStore,
+	// keeps a truncated preview in the content field and the pointer in MetaKeyContentRef.
+	ContentOverflowExternal ContentOverflowPolicy = "external"
+)
+
+const (
+	// MetaKeyContinuationOf is the metadata key holding the original document ID of a continuation row.
+	MetaKeyContinuationOf = "_continuation_of"
+	// MetaKeyContinuationIndex is the metadata key holding the index of a row in a split document, starting from 0.
+	MetaKeyContinuationIndex = "_continuation_index"
+	// MetaKeyContinuationCount is the metadata key holding the number of rows of a split document.
+	MetaKeyContinuationCount = "_continuation_count"
+	// MetaKeyContentRef is the metadata key holding the pointer returned by ExternalContentStore.
+	MetaKeyContentRef = "_content_ref"
+
+	// ContinuationIDSeparator separates the document ID and the index of a continuation row in its ID.
+	ContinuationIDSeparator = "#~"
+
+	// metadataCodecKey and metadataDataKey wrap compressed metadata into a valid JSON object.
+	// They must be kept in sync with the milvus2 retriever.
+	metadataCodecKey     = "_eino_metadata_codec"
+	metadataDataKey      = "_eino_metadata_data"
+	metadataCodecGzipB64 = "gzip+base64"
+)
+
+// ExternalContentStore stores document content which does not fit in the content field,
+// e.g. in an object storage.
+type ExternalContentStore interface {
+	// Put stores the content of the document and returns a pointer to locate it, e.g. "s3://bucket/key".
+	Put(ctx context.Context, docID string, content string) (ref string, err error)
+}
+
+// MetadataCompressionConfig configures compression of the metadata JSON.
+// Compressed metadata is stored as {"_eino_metadata_codec":"gzip+base64","_eino_metadata_data":"..."}
+// and is decompressed transparently by the default document converter of the milvus2 retriever.
+// Note that filter expressions on metadata keys do not work on compressed rows.
+type MetadataCompressionConfig struct {
+	// Threshold is the size in bytes of the metadata JSON above which it is compressed.
+	// Default: 4096
+	Threshold int
+}
+
+// applyContentOverflowRows applies the overflow policy of the config to docs.
+// Documents are copied before being modified, the input slice is left untouched.
+// origins holds the index in docs of the original document of every returned row,
+// it is nil if no document overflows and docs are returned as is.
+func applyContentOverflowRows(ctx context.Context, conf *IndexerConfig, docs []*schema.Document) (
+	newDocs []*schema.Document, origins []int, err error) {
+
+	if conf.ContentOverflowPolicy == ContentOverflowSplit {
+		for _, doc := range docs {
+			if strings.Contains(doc.ID, ContinuationIDSeparator) {
+				return nil, nil, fmt.Errorf("[Indexer.Store] id of document %s contains the reserved separator %q",
+					doc.ID, ContinuationIDSeparator)
+			}
+		}
+	}
+
+	maxLen := conf.MaxContentLength
+	if maxLen <= 0 {
+		maxLen = defaultMaxContentLen
+	}
+	overflow := false
+	for _, doc := range docs {
+		if len(doc.Content) > maxLen {
+			overflow = true
+			break
+		}
+	}
+	if !overflow {
+		return docs, nil, nil
+	}
+
+	newDocs = make([]*schema.Document, 0, len(docs))
+	origins = make([]int, 0, len(docs))
+
+	for idx, doc := range docs {
+		if len(doc.Content) <= maxLen {
+			newDocs = append(newDocs, doc)
+			origins = append(origins, idx)
+			continue
+		}
+
+		switch conf.ContentOverflowPolicy {
+		case ContentOverflowTruncate:
+			nd := copyDocument(doc)
+			nd.Content = truncateContent(doc.Content, maxLen)
+			newDocs = append(newDocs, nd)
+			origins = append(origins, idx)
+		case ContentOverflowSplit:
+			parts := splitContent(doc.Content, maxLen)
+			for n, part := range parts {
+				nd := copyDocument(doc)
+				nd.Content = part
+				if n > 0 {
+					nd.ID = doc.ID + ContinuationIDSeparator + strconv.Itoa(n)
+					nd.MetaData[MetaKeyContinuationOf] = doc.ID
+				} else {
+					nd.MetaData[MetaKeyContinuationCount] = len(parts)
+				}
+				nd.MetaData[MetaKeyContinuationIndex] = n
+				newDocs = append(newDocs, nd)
+				origins = append(origins, idx)
+			}
+		case ContentOverflowExternal:
+			ref, err := conf.ExternalContentStore.Put(ctx, doc.ID, doc.Content)
+			if err != nil {
+				return nil, nil, fmt.Errorf("[Indexer.Store] failed to store content of document %s externally: %w", doc.ID, err)
+			}
+			nd := copyDocument(doc)
+			nd.Content = truncateContent(doc.Content, maxLen)
+			nd.MetaData[MetaKeyContentRef] = ref
+			newDocs = append(newDocs, nd)
+			origins = append(origins, idx)
+		default:
+			return nil, nil, fmt.Errorf("[Indexer.Store] content of document %s exceeds max length: %d > %d",
+				doc.ID, len(doc.Content), maxLen)
+		}
+	}
+
+	return newDocs, origins, nil
+}
+
+// expandVectors maps the vectors of the original documents to the rows produced by applyContentOverflowRows.
+// Vectors whose length does not match the document count are left nil, as the default converter
+// falls back to the vectors carried by documents in this case.
+func expandVectors(vectors [][]float64, docCount int, origins []int) [][]float64 {
+	if origins == nil {
+		return vectors
+	}
+	if len(vectors) != docCount {
+		return nil
+	}
+	newVectors := make([][]float64, 0, len(origins))
+	for _, origin := range origins {
+		newVectors = append(newVectors, vectors[origin])
+	}
+	return newVectors
+}
+
+// staleContinuationFilter returns the filter matching the continuation rows of the documents of rows
+// which are not part of rows, i.e. left by a previous version of a document split into more rows.
+func staleContinuationFilter(conf *IndexerConfig, rows []*schema.Document) string {
+	var heads, continuations []string
+	for _, row := range rows {
+		if _, ok := row.MetaData[MetaKeyContinuationOf]; ok {
+			continuations = append(continuations, row.ID)
+		} else {
+			heads = append(heads, row.ID)
+		}
+	}
+	filter := fmt.Sprintf(`%s[%s] in %s`, conf.MetadataField, quoteString(MetaKeyContinuationOf), stringList(heads))
+	if len(continuations) > 0 {
+		filter += fmt.Sprintf(" and %s not in %s", conf.IDField, stringList(continuations))
+	}
+	return filter
+}
+
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func quoteString(s string) string {
+	return `"` + stringEscaper.Replace(s) + `"`
+}
+
+func stringList(values []string) string {
+	quoted := make([]string, len(values))
+	for idx, v := range values {
+		quoted[idx] = quoteString(v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// headVectors returns the vector of the first row of every document, for vectors computed per row.
+func headVectors(vectors [][]float64, docCount int, origins []int) [][]float64 {
+	if len(vectors) != len(origins) {
+		return nil
+	}
+	heads := make([][]float64, docCount)
+	for r, origin := range origins {
+		if heads[origin] == nil {
+			heads[origin] = vectors[r]
+		}
+	}
+	return heads
+}
+
+func copyDocument(doc *schema.Document) *schema.Document {
+	nd := &schema.Document{
+		ID:       doc.ID,
+		Content:  doc.Content,
+		MetaData: make(map[string]any, len(doc.MetaData)+2),
+	}
+	for k, v := range doc.MetaData {
+		nd.MetaData[k] = v
+	}
+	return nd
+}
+
+// truncateContent truncates s to at most maxLen bytes without splitting a UTF-8 character.
+func truncateContent(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	end := maxLen
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end]
+}
+
+// splitContent splits s into parts of at most maxLen bytes without splitting a UTF-8 character.
+func splitContent(s string, maxLen int) []string {
+	var parts []string
+	for len(s) > 0 {
+		part := truncateContent(s, maxLen)
+		if part == "" {
+			// maxLen is smaller than a single character, take the character as is.
+			_, size := utf8.DecodeRuneInString(s)
+			part = s[:size]
+		}
+		parts = append(parts, part)
+		s = s[len(part):]
+	}
+	return parts
+}
+
+// compressMetadata wraps metadata JSON larger than threshold into a gzip+base64 envelope.
+func compressMetadata(metadata []byte, threshold int) ([]byte, error) {
+	if len(metadata) <= threshold {
+		return metadata, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(metadata); err != nil {
+		return nil, fmt.Errorf("failed to compress metadata: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress metadata: %w", err)
+	}
+
+	return sonic.Marshal(map[string]string{
+		metadataCodecKey: metadataCodecGzipB64,
+		metadataDataKey:  base64.StdEncoding.EncodeToString(buf.Bytes()),
+	})
+}
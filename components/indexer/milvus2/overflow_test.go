@@ -0,0 +1,239 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+)
+
+type mockContentStore struct {
+	contents map[string]string
+	err      error
+}
+
+func (m *mockContentStore) Put(_ context.Context, docID string, content string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.contents[docID] = content
+	return "mock://" + docID, nil
+}
+
+func TestApplyContentOverflowRows(t *testing.T) {
+	convey.Convey("test applyContentOverflowRows", t, func() {
+		ctx := context.Background()
+		docs := []*schema.Document{
+			{ID: "short", Content: "abc", MetaData: map[string]any{}},
+			{ID: "long", Content: "你好世界", MetaData: map[string]any{"k": "v"}},
+		}
+		vectors := [][]float64{{0.1}, {0.2}}
+
+		convey.Convey("test no overflow", func() {
+			conf := &IndexerConfig{MaxContentLength: 100}
+			newDocs, origins, err := applyContentOverflowRows(ctx, conf, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(origins, convey.ShouldBeNil)
+			newVectors := expandVectors(vectors, len(docs), origins)
+			convey.So(newDocs, convey.ShouldResemble, docs)
+			convey.So(newVectors, convey.ShouldResemble, vectors)
+		})
+
+		convey.Convey("test error policy", func() {
+			conf := &IndexerConfig{MaxContentLength: 5, ContentOverflowPolicy: ContentOverflowError}
+			_, _, err := applyContentOverflowRows(ctx, conf, docs)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "exceeds max length")
+		})
+
+		convey.Convey("test truncate policy", func() {
+			conf := &IndexerConfig{MaxContentLength: 7, ContentOverflowPolicy: ContentOverflowTruncate}
+			newDocs, origins, err := applyContentOverflowRows(ctx, conf, docs)
+			convey.So(err, convey.ShouldBeNil)
+			newVectors := expandVectors(vectors, len(docs), origins)
+			convey.So(len(newDocs), convey.ShouldEqual, 2)
+			convey.So(newDocs[1].Content, convey.ShouldEqual, "你好")
+			convey.So(docs[1].Content, convey.ShouldEqual, "你好世界")
+			convey.So(newVectors, convey.ShouldResemble, vectors)
+		})
+
+		convey.Convey("test split policy", func() {
+			conf := &IndexerConfig{MaxContentLength: 7, ContentOverflowPolicy: ContentOverflowSplit}
+			newDocs, origins, err := applyContentOverflowRows(ctx, conf, docs)
+			convey.So(err, convey.ShouldBeNil)
+			newVectors := expandVectors(vectors, len(docs), origins)
+			convey.So(len(newDocs), convey.ShouldEqual, 3)
+			convey.So(newDocs[1].ID, convey.ShouldEqual, "long")
+			convey.So(newDocs[1].Content, convey.ShouldEqual, "你好")
+			convey.So(newDocs[1].MetaData[MetaKeyContinuationCount], convey.ShouldEqual, 2)
+			convey.So(newDocs[2].ID, convey.ShouldEqual, "long#~1")
+			convey.So(newDocs[2].Content, convey.ShouldEqual, "世界")
+			convey.So(newDocs[2].MetaData[MetaKeyContinuationOf], convey.ShouldEqual, "long")
+			convey.So(newDocs[2].MetaData[MetaKeyContinuationIndex], convey.ShouldEqual, 1)
+			convey.So(newDocs[2].MetaData["k"], convey.ShouldEqual, "v")
+			convey.So(newVectors, convey.ShouldResemble, [][]float64{{0.1}, {0.2}, {0.2}})
+			_, ok := docs[1].MetaData[MetaKeyContinuationCount]
+			convey.So(ok, convey.ShouldBeFalse)
+		})
+
+		convey.Convey("test split policy rejects reserved ids", func() {
+			conf := &IndexerConfig{MaxContentLength: 100, ContentOverflowPolicy: ContentOverflowSplit}
+			_, _, err := applyContentOverflowRows(ctx, conf, []*schema.Document{{ID: "long#~1", Content: "abc"}})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "reserved separator")
+		})
+
+		convey.Convey("test external policy", func() {
+			store := &mockContentStore{contents: map[string]string{}}
+			conf := &IndexerConfig{MaxContentLength: 3, ContentOverflowPolicy: ContentOverflowExternal, ExternalContentStore: store}
+			newDocs, _, err := applyContentOverflowRows(ctx, conf, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(newDocs[1].Content, convey.ShouldEqual, "你")
+			convey.So(newDocs[1].MetaData[MetaKeyContentRef], convey.ShouldEqual, "mock://long")
+			convey.So(store.contents["long"], convey.ShouldEqual, "你好世界")
+
+			store.err = fmt.Errorf("put error")
+			_, _, err = applyContentOverflowRows(ctx, conf, docs)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+func TestStaleContinuationFilter(t *testing.T) {
+	convey.Convey("test staleContinuationFilter", t, func() {
+		conf := &IndexerConfig{IDField: defaultIDField, MetadataField: defaultMetadataField}
+		rows := []*schema.Document{
+			{ID: "a", MetaData: map[string]any{}},
+			{ID: `b"`, MetaData: map[string]any{}},
+			{ID: "b\"#~1", MetaData: map[string]any{MetaKeyContinuationOf: `b"`}},
+		}
+		convey.So(staleContinuationFilter(conf, rows), convey.ShouldEqual,
+			`metadata["_continuation_of"] in ["a", "b\""] and id not in ["b\"#~1"]`)
+		convey.So(staleContinuationFilter(conf, rows[:1]), convey.ShouldEqual, `metadata["_continuation_of"] in ["a"]`)
+	})
+}
+
+func TestHeadVectors(t *testing.T) {
+	convey.Convey("test headVectors", t, func() {
+		vectors := [][]float64{{0.1}, {0.2}, {0.3}}
+		convey.So(headVectors(vectors, 2, []int{0, 1, 1}), convey.ShouldResemble, [][]float64{{0.1}, {0.2}})
+		convey.So(headVectors(nil, 2, []int{0, 1, 1}), convey.ShouldBeNil)
+	})
+}
+
+// recordingEmbedding embeds every text into a vector holding its length.
+type recordingEmbedding struct {
+	texts []string
+}
+
+func (m *recordingEmbedding) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	m.texts = append(m.texts, texts...)
+	vectors := make([][]float64, len(texts))
+	for idx, text := range texts {
+		vectors[idx] = []float64{float64(len(text)), 0}
+	}
+	return vectors, nil
+}
+
+func TestIndexer_StoreSplit(t *testing.T) {
+	PatchConvey("test Indexer.Store with the split policy", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		emb := &recordingEmbedding{}
+		vector := &VectorConfig{VectorField: defaultVectorField, Dimension: 2}
+		indexer := &Indexer{
+			client: mockClient,
+			config: &IndexerConfig{
+				Collection:            "test_collection",
+				IDField:               defaultIDField,
+				MetadataField:         defaultMetadataField,
+				Vector:                vector,
+				Embedding:             emb,
+				MaxContentLength:      7,
+				ContentOverflowPolicy: ContentOverflowSplit,
+				DocumentConverter:     defaultDocumentConverter(defaultScalarFields, vector, nil, nil),
+			},
+			stats: newCollectionStats(0),
+		}
+
+		Mock(GetMethod(mockClient, "Upsert")).To(func(_ *milvusclient.Client, ctx context.Context, option milvusclient.UpsertOption, callOptions ...grpc.CallOption) (milvusclient.UpsertResult, error) {
+			return milvusclient.UpsertResult{IDs: column.NewColumnVarChar("id", []string{"short", "long", "long#~1"})}, nil
+		}).Build()
+		var deleteExpr string
+		Mock(GetMethod(mockClient, "Delete")).To(func(_ *milvusclient.Client, ctx context.Context, option milvusclient.DeleteOption, callOptions ...grpc.CallOption) (milvusclient.DeleteResult, error) {
+			deleteExpr = option.Request().GetExpr()
+			return milvusclient.DeleteResult{}, nil
+		}).Build()
+
+		docs := []*schema.Document{
+			{ID: "short", Content: "abc"},
+			{ID: "long", Content: "你好世界"},
+		}
+		ids, err := indexer.Store(ctx, docs, WithWriteBackVectors(true))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(ids, convey.ShouldResemble, []string{"short", "long", "long#~1"})
+		// every row is embedded with its own content, in the order of the rows
+		convey.So(emb.texts, convey.ShouldResemble, []string{"abc", "你好", "世界"})
+		convey.So(docs[1].DenseVector(), convey.ShouldResemble, []float64{6, 0})
+		convey.So(deleteExpr, convey.ShouldEqual, `metadata["_continuation_of"] in ["short", "long"] and id not in ["long#~1"]`)
+	})
+}
+
+func TestCompressMetadata(t *testing.T) {
+	convey.Convey("test compressMetadata", t, func() {
+		convey.Convey("test below threshold", func() {
+			data := []byte(`{"key":"value"}`)
+			out, err := compressMetadata(data, 100)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(out, convey.ShouldResemble, data)
+		})
+
+		convey.Convey("test above threshold", func() {
+			data, _ := sonic.Marshal(map[string]any{"key": strings.Repeat("x", 1000)})
+			out, err := compressMetadata(data, 100)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(out), convey.ShouldBeLessThan, len(data))
+
+			var envelope map[string]string
+			convey.So(sonic.Unmarshal(out, &envelope), convey.ShouldBeNil)
+			convey.So(envelope[metadataCodecKey], convey.ShouldEqual, metadataCodecGzipB64)
+
+			raw, err := base64.StdEncoding.DecodeString(envelope[metadataDataKey])
+			convey.So(err, convey.ShouldBeNil)
+			zr, err := gzip.NewReader(bytes.NewReader(raw))
+			convey.So(err, convey.ShouldBeNil)
+			decoded, err := io.ReadAll(zr)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(decoded, convey.ShouldResemble, data)
+		})
+	})
+}
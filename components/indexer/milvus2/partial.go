@@ -0,0 +1,180 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// StoreResult is the outcome of a Store with ContinueOnError, see StoreError.
+type StoreResult struct {
+	// SucceededIDs are the IDs of the stored rows, as returned by Store.
+	SucceededIDs []string
+	// Failed are the documents rejected before the upsert, ordered by Index.
+	Failed []FailedDocument
+}
+
+// FailedDocument is a document rejected by a Store with ContinueOnError.
+type FailedDocument struct {
+	// Index is the index of the document in the docs passed to Store.
+	Index int
+	// Err is the reason the document was rejected, e.g. missing vector data.
+	Err error
+}
+
+// StoreError is returned by Store with ContinueOnError when some documents are rejected,
+// together with the IDs of the stored rows. The rest of the batch is stored as usual.
+//
+//	ids, err := idx.Store(ctx, docs, milvus2.WithContinueOnError(true))
+//	var storeErr *milvus2.StoreError
+//	if errors.As(err, &storeErr) {
+//		for _, f := range storeErr.Result.Failed {
+//			log.Printf("document %s rejected: %v", docs[f.Index].ID, f.Err)
+//		}
+//	}
+type StoreError struct {
+	Result *StoreResult
+}
+
+func (e *StoreError) Error() string {
+	if e.Result == nil || len(e.Result.Failed) == 0 {
+		return "[Indexer.Store] no document rejected"
+	}
+	first := e.Result.Failed[0]
+	return fmt.Sprintf("[Indexer.Store] %d documents rejected, %d rows stored, first rejected document %d: %v",
+		len(e.Result.Failed), len(e.Result.SucceededIDs), first.Index, first.Err)
+}
+
+// screenDocuments rejects the documents without an ID if requireIDs is set, and the documents whose content
+// exceeds MaxContentLength if ContentOverflowPolicy is ContentOverflowError, instead of rejecting the whole batch.
+// kept holds the index in docs of every returned document.
+func screenDocuments(conf *IndexerConfig, docs []*schema.Document, requireIDs bool) (
+	valid []*schema.Document, kept []int, failed []FailedDocument) {
+
+	maxLen := conf.MaxContentLength
+	if maxLen <= 0 {
+		maxLen = defaultMaxContentLen
+	}
+	policy := conf.ContentOverflowPolicy
+	valid = make([]*schema.Document, 0, len(docs))
+	kept = make([]int, 0, len(docs))
+	for idx, doc := range docs {
+		if requireIDs && doc.ID == "" {
+			failed = append(failed, FailedDocument{Index: idx, Err: fmt.Errorf("id of document %d is empty", idx)})
+			continue
+		}
+		if len(doc.Content) > maxLen && (policy == "" || policy == ContentOverflowError) {
+			failed = append(failed, FailedDocument{
+				Index: idx,
+				Err:   fmt.Errorf("content of document %s exceeds max length: %d > %d", doc.ID, len(doc.Content), maxLen),
+			})
+			continue
+		}
+		valid = append(valid, doc)
+		kept = append(kept, idx)
+	}
+	return valid, kept, failed
+}
+
+// screenRows converts every row on its own to find the documents the batch conversion would fail on,
+// e.g. missing vector data, and drops all the rows of these documents.
+// rowDocs holds the index of the original document of every row.
+func (i *Indexer) screenRows(ctx context.Context, rows []*schema.Document, vectors [][]float64,
+	extraVectors [][][]float64, rowDocs []int) ([]*schema.Document, [][]float64, [][][]float64, []FailedDocument) {
+
+	var failed []FailedDocument
+	rejected := make(map[int]bool)
+	for r, row := range rows {
+		if rejected[rowDocs[r]] {
+			continue
+		}
+		var rowVectors [][]float64
+		if len(vectors) == len(rows) {
+			rowVectors = vectors[r : r+1]
+		}
+		rowExtraVectors := make([][][]float64, len(extraVectors))
+		for f := range extraVectors {
+			if len(extraVectors[f]) == len(rows) {
+				rowExtraVectors[f] = extraVectors[f][r : r+1]
+			}
+		}
+
+		if _, err := i.convertDocuments(ctx, []*schema.Document{row}, rowVectors, rowExtraVectors); err != nil {
+			rejected[rowDocs[r]] = true
+			failed = append(failed, FailedDocument{Index: rowDocs[r], Err: err})
+		}
+	}
+	if len(rejected) == 0 {
+		return rows, vectors, extraVectors, nil
+	}
+
+	keep := func(r int) bool { return !rejected[rowDocs[r]] }
+	validRows := make([]*schema.Document, 0, len(rows))
+	for r, row := range rows {
+		if keep(r) {
+			validRows = append(validRows, row)
+		}
+	}
+	return validRows, filterRows(vectors, len(rows), keep), filterExtraVectors(extraVectors, len(rows), keep), failed
+}
+
+// filterRows keeps the vectors of the kept rows, vectors not matching the row count are returned as is.
+func filterRows(vectors [][]float64, rowCount int, keep func(r int) bool) [][]float64 {
+	if len(vectors) != rowCount {
+		return vectors
+	}
+	filtered := make([][]float64, 0, len(vectors))
+	for r, vec := range vectors {
+		if keep(r) {
+			filtered = append(filtered, vec)
+		}
+	}
+	return filtered
+}
+
+func filterExtraVectors(extraVectors [][][]float64, rowCount int, keep func(r int) bool) [][][]float64 {
+	if len(extraVectors) == 0 {
+		return extraVectors
+	}
+	filtered := make([][][]float64, len(extraVectors))
+	for f := range extraVectors {
+		filtered[f] = filterRows(extraVectors[f], rowCount, keep)
+	}
+	return filtered
+}
+
+// mapRowDocs composes the row origins of applyContentOverflowRows with the kept documents of screenDocuments,
+// returning the index in the docs passed to Store of every row.
+func mapRowDocs(rowCount int, origins, kept []int) []int {
+	rowDocs := make([]int, rowCount)
+	for r := range rowDocs {
+		idx := r
+		if origins != nil {
+			idx = origins[r]
+		}
+		rowDocs[r] = kept[idx]
+	}
+	return rowDocs
+}
+
+func sortFailedDocuments(failed []FailedDocument) {
+	sort.SliceStable(failed, func(a, b int) bool { return failed[a].Index < failed[b].Index })
+}
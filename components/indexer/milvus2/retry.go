@@ -0,0 +1,40 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/libs/milvus2/retry"
+)
+
+// RetryPolicy configures the retries of the batches failed to upsert with a transient error,
+// e.g. the rate limit of Milvus is exceeded or a node is unavailable. It is shared with the milvus2 retriever,
+// errors are classified by retry.IsTransientError unless IsRetryable is set.
+// A batch is retried as a whole with the same primary keys, the upsert overwrites the rows already written
+// by a failed attempt instead of duplicating them. So only the batches whose documents all have an ID are retried.
+type RetryPolicy = retry.Policy
+
+// retryable reports whether the batch can be retried without creating duplicates, i.e. every document has an ID.
+func retryable(docs []*schema.Document) bool {
+	for _, doc := range docs {
+		if doc == nil || doc.ID == "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestRetry(t *testing.T) {
+	convey.Convey("test retry of transient upsert errors", t, func() {
+		convey.Convey("test only batches with document IDs are retried", func() {
+			convey.So(retryable([]*schema.Document{{ID: "1"}, {ID: "2"}}), convey.ShouldBeTrue)
+			convey.So(retryable([]*schema.Document{{ID: "1"}, {}}), convey.ShouldBeFalse)
+		})
+
+		convey.Convey("test the policy of the config is not modified", func() {
+			policy := &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+			conf := &IndexerConfig{
+				Client:      &milvusclient.Client{},
+				Vector:      &VectorConfig{Dimension: 4},
+				RetryPolicy: policy,
+			}
+			convey.So(conf.validate(), convey.ShouldBeNil)
+			convey.So(policy.MaxBackoff, convey.ShouldEqual, 0)
+			convey.So(policy.IsRetryable, convey.ShouldBeNil)
+
+			conf.RetryPolicy = &RetryPolicy{MaxAttempts: -1}
+			convey.So(conf.validate(), convey.ShouldNotBeNil)
+		})
+	})
+}
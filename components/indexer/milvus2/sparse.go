@@ -0,0 +1,121 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// maxSparseIndex is the largest sparse vector index accepted by Milvus, the max uint32 is reserved.
+const maxSparseIndex = math.MaxUint32 - 1
+
+// SparseEncoder encodes texts into sparse vectors on the client side,
+// e.g. a BM25 encoder with a local vocabulary or a SPLADE model served over HTTP.
+type SparseEncoder interface {
+	// EncodeSparse returns the sparse vectors of texts, one per text, as maps of index -> weight.
+	EncodeSparse(ctx context.Context, texts []string) ([]map[int]float64, error)
+}
+
+// SparseEncoderFunc adapts a function to the SparseEncoder interface.
+type SparseEncoderFunc func(ctx context.Context, texts []string) ([]map[int]float64, error)
+
+// EncodeSparse calls f(ctx, texts).
+func (f SparseEncoderFunc) EncodeSparse(ctx context.Context, texts []string) ([]map[int]float64, error) {
+	return f(ctx, texts)
+}
+
+// ValidateSparseVector checks that the sparse vector can be stored in Milvus:
+// indices must be in [0, 2^32-2] and values must be finite.
+func ValidateSparseVector(sv map[int]float64) error {
+	for idx, v := range sv {
+		if err := validateSparseEntry(idx, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateSparseEntry(idx int, v float64) error {
+	if idx < 0 {
+		return fmt.Errorf("negative sparse index: %d", idx)
+	}
+	if int64(idx) > maxSparseIndex {
+		return fmt.Errorf("sparse index out of range: %d", idx)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return fmt.Errorf("non-finite sparse value at index %d: %v", idx, v)
+	}
+	return nil
+}
+
+// NormalizeSparseVector returns a copy of the sparse vector scaled to unit L2 norm, zero values are dropped.
+// A vector without non-zero values is returned as an empty map.
+// Normalized vectors make IP scores equal to cosine similarity.
+func NormalizeSparseVector(sv map[int]float64) map[int]float64 {
+	var norm float64
+	for _, v := range sv {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	normalized := make(map[int]float64, len(sv))
+	if norm == 0 {
+		return normalized
+	}
+	for idx, v := range sv {
+		if v != 0 {
+			normalized[idx] = v / norm
+		}
+	}
+	return normalized
+}
+
+// encodeSparseVectors returns copies of docs carrying the sparse vectors of the SparseVectorConfig.Encoder.
+// The docs are returned as is if no encoder is configured.
+func (i *Indexer) encodeSparseVectors(ctx context.Context, docs []*schema.Document) ([]*schema.Document, error) {
+	if i.config.Sparse == nil || i.config.Sparse.Encoder == nil || len(docs) == 0 {
+		return docs, nil
+	}
+
+	texts := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		texts = append(texts, doc.Content)
+	}
+	sparseVectors, err := i.config.Sparse.Encoder.EncodeSparse(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("[Indexer.Store] failed to encode sparse vectors: %w", err)
+	}
+	if len(sparseVectors) != len(docs) {
+		return nil, fmt.Errorf("[Indexer.Store] sparse encoder result length mismatch: need %d, got %d", len(docs), len(sparseVectors))
+	}
+
+	encoded := make([]*schema.Document, len(docs))
+	for idx, doc := range docs {
+		// copy the document to keep the sparse vector out of the caller's metadata
+		cp := *doc
+		cp.MetaData = make(map[string]any, len(doc.MetaData)+1)
+		for k, v := range doc.MetaData {
+			cp.MetaData[k] = v
+		}
+		encoded[idx] = cp.WithSparseVector(sparseVectors[idx])
+	}
+	return encoded, nil
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateSparseVector(t *testing.T) {
+	convey.Convey("test ValidateSparseVector", t, func() {
+		convey.So(ValidateSparseVector(nil), convey.ShouldBeNil)
+		convey.So(ValidateSparseVector(map[int]float64{0: 0.5, maxSparseIndex: -1}), convey.ShouldBeNil)
+
+		err := ValidateSparseVector(map[int]float64{-1: 0.5})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "negative sparse index")
+
+		err = ValidateSparseVector(map[int]float64{maxSparseIndex + 1: 0.5})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "out of range")
+
+		err = ValidateSparseVector(map[int]float64{1: math.NaN()})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "non-finite")
+
+		err = ValidateSparseVector(map[int]float64{1: math.Inf(-1)})
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "non-finite")
+	})
+}
+
+func TestNormalizeSparseVector(t *testing.T) {
+	convey.Convey("test NormalizeSparseVector", t, func() {
+		sv := map[int]float64{1: 3, 2: 0, 5: -4}
+		normalized := NormalizeSparseVector(sv)
+		convey.So(normalized, convey.ShouldResemble, map[int]float64{1: 0.6, 5: -0.8})
+		convey.So(sv, convey.ShouldResemble, map[int]float64{1: 3, 2: 0, 5: -4})
+
+		convey.So(NormalizeSparseVector(map[int]float64{1: 0}), convey.ShouldBeEmpty)
+		convey.So(NormalizeSparseVector(nil), convey.ShouldBeEmpty)
+	})
+}
+
+func TestIndexer_encodeSparseVectors(t *testing.T) {
+	convey.Convey("test Indexer.encodeSparseVectors", t, func() {
+		ctx := context.Background()
+		docs := []*schema.Document{
+			{ID: "1", Content: "a b", MetaData: map[string]any{"k": "v"}},
+			{ID: "2", Content: "c"},
+		}
+		encoder := SparseEncoderFunc(func(ctx context.Context, texts []string) ([]map[int]float64, error) {
+			res := make([]map[int]float64, 0, len(texts))
+			for _, text := range texts {
+				res = append(res, map[int]float64{len(strings.Fields(text)): 1})
+			}
+			return res, nil
+		})
+
+		convey.Convey("test without encoder", func() {
+			i := &Indexer{config: &IndexerConfig{Sparse: &SparseVectorConfig{}}}
+			encoded, err := i.encodeSparseVectors(ctx, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(encoded, convey.ShouldResemble, docs)
+		})
+
+		convey.Convey("test encode", func() {
+			i := &Indexer{config: &IndexerConfig{Sparse: &SparseVectorConfig{Encoder: encoder}}}
+			encoded, err := i.encodeSparseVectors(ctx, docs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(encoded), convey.ShouldEqual, 2)
+			convey.So(encoded[0].SparseVector(), convey.ShouldResemble, map[int]float64{2: 1})
+			convey.So(encoded[0].MetaData["k"], convey.ShouldEqual, "v")
+			convey.So(encoded[1].SparseVector(), convey.ShouldResemble, map[int]float64{1: 1})
+			// the caller's documents are not modified
+			convey.So(docs[0].SparseVector(), convey.ShouldBeNil)
+			convey.So(docs[1].MetaData, convey.ShouldBeNil)
+		})
+
+		convey.Convey("test encoder error", func() {
+			i := &Indexer{config: &IndexerConfig{Sparse: &SparseVectorConfig{
+				Encoder: SparseEncoderFunc(func(ctx context.Context, texts []string) ([]map[int]float64, error) {
+					return nil, fmt.Errorf("encode error")
+				}),
+			}}}
+			_, err := i.encodeSparseVectors(ctx, docs)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "encode error")
+		})
+
+		convey.Convey("test encoder result length mismatch", func() {
+			i := &Indexer{config: &IndexerConfig{Sparse: &SparseVectorConfig{
+				Encoder: SparseEncoderFunc(func(ctx context.Context, texts []string) ([]map[int]float64, error) {
+					return []map[int]float64{{1: 1}}, nil
+				}),
+			}}}
+			_, err := i.encodeSparseVectors(ctx, docs)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "length mismatch")
+		})
+	})
+}
+
+func TestSparseVectorConfig_Encoder(t *testing.T) {
+	convey.Convey("test SparseVectorConfig.Encoder", t, func() {
+		encoder := SparseEncoderFunc(func(ctx context.Context, texts []string) ([]map[int]float64, error) {
+			return nil, nil
+		})
+
+		convey.Convey("test encoder requires precomputed method", func() {
+			conf := &IndexerConfig{
+				Client: &milvusclient.Client{},
+				Sparse: &SparseVectorConfig{Encoder: encoder},
+			}
+			err := conf.validate()
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "sparse encoder requires method")
+		})
+
+		convey.Convey("test encoder with precomputed method", func() {
+			conf := &IndexerConfig{
+				Client: &milvusclient.Client{},
+				Sparse: &SparseVectorConfig{MetricType: IP, Encoder: encoder},
+			}
+			convey.So(conf.validate(), convey.ShouldBeNil)
+			convey.So(conf.Sparse.Method, convey.ShouldEqual, SparseMethodPrecomputed)
+		})
+	})
+}
+
+func TestDefaultDocumentConverter_Sparse(t *testing.T) {
+	convey.Convey("test defaultDocumentConverter with sparse vectors", t, func() {
+		ctx := context.Background()
+		sparseConf := &SparseVectorConfig{VectorField: "sparse", Method: SparseMethodPrecomputed}
+		sparseColumn := func(cols []column.Column) entity.SparseEmbedding {
+			for _, col := range cols {
+				if col.Name() == "sparse" {
+					v, err := col.Get(0)
+					convey.So(err, convey.ShouldBeNil)
+					return v.(entity.SparseEmbedding)
+				}
+			}
+			return nil
+		}
+
+		convey.Convey("test normalize", func() {
+			sparseConf.Normalize = true
+			doc := (&schema.Document{ID: "1"}).WithSparseVector(map[int]float64{1: 3, 2: 4})
+			cols, err := defaultDocumentConverter(defaultScalarFields, nil, sparseConf, nil)(ctx, []*schema.Document{doc}, nil)
+			convey.So(err, convey.ShouldBeNil)
+			se := sparseColumn(cols)
+			convey.So(se.Len(), convey.ShouldEqual, 2)
+			_, value, ok := se.Get(0)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(value, convey.ShouldAlmostEqual, float32(0.6), 1e-6)
+		})
+
+		convey.Convey("test invalid sparse vector", func() {
+			doc := (&schema.Document{ID: "1"}).WithSparseVector(map[int]float64{1: math.NaN()})
+			_, err := defaultDocumentConverter(defaultScalarFields, nil, sparseConf, nil)(ctx, []*schema.Document{doc}, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "non-finite")
+		})
+	})
+}
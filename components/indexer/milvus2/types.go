@@ -106,3 +106,16 @@ const (
 func (m MetricType) toEntity() entity.MetricType {
 	return entity.MetricType(m)
 }
+
+// Collection property keys commonly set through IndexerConfig.CollectionProperties and Indexer.UpdateProperties.
+const (
+	// PropertyMmapEnabled enables memory-mapped storage of the collection data, e.g. "true".
+	// Altering it requires the collection to be released.
+	PropertyMmapEnabled = "mmap.enabled"
+	// PropertyCollectionTTLSeconds is the time to live of the collection data in seconds, e.g. "86400".
+	PropertyCollectionTTLSeconds = "collection.ttl.seconds"
+	// PropertyReplicaNumber is the default number of replicas to load the collection with, e.g. "2".
+	PropertyReplicaNumber = "collection.replica.number"
+	// PropertyResourceGroups is the comma-separated default resource groups to load the collection in.
+	PropertyResourceGroups = "collection.resource_groups"
+)
@@ -0,0 +1,255 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+)
+
+// IndexUpdateStage is a stage of Indexer.UpdateIndex.
+type IndexUpdateStage string
+
+const (
+	// IndexUpdateStageReleased is reported after the collection is released to drop its index.
+	IndexUpdateStageReleased IndexUpdateStage = "released"
+	// IndexUpdateStageDropped is reported after the old index is dropped.
+	IndexUpdateStageDropped IndexUpdateStage = "dropped"
+	// IndexUpdateStageBuilding is reported periodically while the new index is being built.
+	IndexUpdateStageBuilding IndexUpdateStage = "building"
+	// IndexUpdateStageBuilt is reported once the new index is built.
+	IndexUpdateStageBuilt IndexUpdateStage = "built"
+	// IndexUpdateStageLoaded is reported after the collection released by UpdateIndex is loaded again.
+	IndexUpdateStageLoaded IndexUpdateStage = "loaded"
+)
+
+// IndexUpdateProgress reports the progress of Indexer.UpdateIndex.
+type IndexUpdateProgress struct {
+	// Field is the vector field whose index is updated.
+	Field string
+	// Stage is the stage reached.
+	Stage IndexUpdateStage
+	// IndexedRows and TotalRows are the rows indexed so far and the rows to index,
+	// as last described by Milvus while the index is being built.
+	IndexedRows int64
+	TotalRows   int64
+}
+
+// UpdateIndexOption configures Indexer.UpdateIndex.
+type UpdateIndexOption func(o *updateIndexOptions)
+
+type updateIndexOptions struct {
+	onProgress   func(progress IndexUpdateProgress)
+	pollInterval time.Duration
+}
+
+// WithIndexUpdateProgress sets the callback receiving the progress of Indexer.UpdateIndex.
+func WithIndexUpdateProgress(fn func(progress IndexUpdateProgress)) UpdateIndexOption {
+	return func(o *updateIndexOptions) {
+		o.onProgress = fn
+	}
+}
+
+// WithIndexUpdatePollInterval sets how often the build progress of the new index is described.
+// Default: 1s
+func WithIndexUpdatePollInterval(interval time.Duration) UpdateIndexOption {
+	return func(o *updateIndexOptions) {
+		o.pollInterval = interval
+	}
+}
+
+const defaultIndexUpdatePollInterval = time.Second
+
+// UpdateIndex rebuilds the index of the vector field with builder, e.g. to tune ef_construction or nlist
+// after the initial ingestion. field must be the VectorField of Vector, ExtraVectors or Sparse,
+// whose MetricType the new index is built with.
+//
+// Milvus cannot alter the build parameters of an index, so the collection is released,
+// the old index is dropped and the new one is created under the same name, then the collection is loaded
+// again if it was loaded before, also when the update fails. The field cannot be searched until the collection
+// is loaded again. A field without an index gets one created; any other DescribeIndex error aborts the update.
+// Use UpdateIndexProperties for the index properties that can be altered in place, e.g. mmap.
+func (i *Indexer) UpdateIndex(ctx context.Context, field string, builder IndexBuilder, opts ...UpdateIndexOption) (retErr error) {
+	if builder == nil {
+		return fmt.Errorf("[Indexer.UpdateIndex] index builder not provided")
+	}
+	metricType, ok := i.config.metricTypeOf(field)
+	if !ok {
+		return fmt.Errorf("[Indexer.UpdateIndex] field %q is not a vector field of the indexer", field)
+	}
+
+	o := &updateIndexOptions{pollInterval: defaultIndexUpdatePollInterval}
+	for _, opt := range opts {
+		opt(o)
+	}
+	report := func(progress IndexUpdateProgress) {
+		if o.onProgress != nil {
+			progress.Field = field
+			o.onProgress(progress)
+		}
+	}
+
+	collection := i.config.Collection
+	indexName := field
+	hasIndex := true
+	desc, err := i.client.DescribeIndex(ctx, milvusclient.NewDescribeIndexOption(collection, field))
+	switch {
+	case errors.Is(err, merr.ErrIndexNotFound):
+		hasIndex = false
+	case err != nil:
+		return fmt.Errorf("[Indexer.UpdateIndex] failed to describe index of field %q: %w", field, err)
+	case desc.Index != nil && desc.Name() != "":
+		indexName = desc.Name()
+	}
+
+	loadState, err := i.client.GetLoadState(ctx, milvusclient.NewGetLoadStateOption(collection))
+	if err != nil {
+		return fmt.Errorf("[Indexer.UpdateIndex] failed to get load state of collection %q: %w", collection, err)
+	}
+	loaded := loadState.State == entity.LoadStateLoaded || loadState.State == entity.LoadStateLoading
+	if loaded {
+		if err = i.client.ReleaseCollection(ctx, milvusclient.NewReleaseCollectionOption(collection)); err != nil {
+			return fmt.Errorf("[Indexer.UpdateIndex] failed to release collection %q: %w", collection, err)
+		}
+		report(IndexUpdateProgress{Stage: IndexUpdateStageReleased})
+		defer func() {
+			// the collection was released by UpdateIndex, load it back even if the update failed
+			// so that it stays searchable, with the old index if it was not dropped yet
+			if loadErr := i.loadCollection(context.WithoutCancel(ctx)); loadErr != nil {
+				retErr = errors.Join(retErr, fmt.Errorf("[Indexer.UpdateIndex] %w", loadErr))
+				return
+			}
+			if retErr == nil {
+				report(IndexUpdateProgress{Stage: IndexUpdateStageLoaded})
+			}
+		}()
+	}
+
+	if hasIndex {
+		if err = i.client.DropIndex(ctx, milvusclient.NewDropIndexOption(collection, indexName)); err != nil {
+			return fmt.Errorf("[Indexer.UpdateIndex] failed to drop index %q: %w", indexName, err)
+		}
+		report(IndexUpdateProgress{Stage: IndexUpdateStageDropped})
+	}
+
+	createOpt := milvusclient.NewCreateIndexOption(collection, field, builder.Build(metricType)).WithIndexName(indexName)
+	createTask, err := i.client.CreateIndex(ctx, createOpt)
+	if err != nil {
+		return fmt.Errorf("[Indexer.UpdateIndex] failed to create index: %w", err)
+	}
+	if err = i.awaitIndex(ctx, createTask, field, o.pollInterval, report); err != nil {
+		return fmt.Errorf("[Indexer.UpdateIndex] failed to await index creation: %w", err)
+	}
+	return nil
+}
+
+// loadCollection loads the collection of the indexer and waits until it is loaded.
+func (i *Indexer) loadCollection(ctx context.Context) error {
+	collection := i.config.Collection
+	loadTask, err := i.client.LoadCollection(ctx, milvusclient.NewLoadCollectionOption(collection))
+	if err != nil {
+		return fmt.Errorf("failed to load collection %q: %w", collection, err)
+	}
+	if err = loadTask.Await(ctx); err != nil {
+		return fmt.Errorf("failed to await load of collection %q: %w", collection, err)
+	}
+	return nil
+}
+
+// awaitIndex waits for the index creation, describing the index every interval to report the build progress.
+func (i *Indexer) awaitIndex(ctx context.Context, task *milvusclient.CreateIndexTask, field string,
+	interval time.Duration, report func(progress IndexUpdateProgress)) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- task.Await(ctx)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last IndexUpdateProgress
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return err
+			}
+			if last.TotalRows > 0 {
+				last.IndexedRows = last.TotalRows
+			}
+			last.Stage = IndexUpdateStageBuilt
+			report(last)
+			return nil
+		case <-ticker.C:
+			desc, err := i.client.DescribeIndex(ctx, milvusclient.NewDescribeIndexOption(i.config.Collection, field))
+			if err != nil {
+				// the progress is best effort, the result of the task decides
+				continue
+			}
+			last = IndexUpdateProgress{Stage: IndexUpdateStageBuilding, IndexedRows: desc.IndexedRows, TotalRows: desc.TotalRows}
+			report(last)
+		}
+	}
+}
+
+// UpdateIndexProperties alters the properties of the index of the vector field in place, e.g. PropertyMmapEnabled.
+// Like UpdateProperties, mmap changes only take effect after the collection is released and loaded again.
+// Build parameters such as M or nlist cannot be altered, use UpdateIndex to rebuild the index instead.
+func (i *Indexer) UpdateIndexProperties(ctx context.Context, field string, props map[string]string) error {
+	if len(props) == 0 {
+		return nil
+	}
+
+	indexName := field
+	desc, err := i.client.DescribeIndex(ctx, milvusclient.NewDescribeIndexOption(i.config.Collection, field))
+	if err != nil {
+		return fmt.Errorf("[Indexer.UpdateIndexProperties] failed to describe index of field %q: %w", field, err)
+	}
+	if desc.Index != nil && desc.Name() != "" {
+		indexName = desc.Name()
+	}
+
+	opt := milvusclient.NewAlterIndexPropertiesOption(i.config.Collection, indexName)
+	for k, v := range props {
+		opt = opt.WithProperty(k, v)
+	}
+	if err = i.client.AlterIndexProperties(ctx, opt); err != nil {
+		return fmt.Errorf("[Indexer.UpdateIndexProperties] failed to alter index properties: %w", err)
+	}
+	return nil
+}
+
+// metricTypeOf returns the metric type of the dense or sparse vector field configured as field.
+func (c *IndexerConfig) metricTypeOf(field string) (MetricType, bool) {
+	if c.Vector != nil && c.Vector.VectorField == field {
+		return c.Vector.MetricType, true
+	}
+	for _, vc := range c.ExtraVectors {
+		if vc.VectorField == field {
+			return vc.MetricType, true
+		}
+	}
+	if c.Sparse != nil && c.Sparse.VectorField == field {
+		return c.Sparse.MetricType, true
+	}
+	return "", false
+}
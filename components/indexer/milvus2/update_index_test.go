@@ -0,0 +1,220 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/bytedance/mockey"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+)
+
+func TestIndexer_UpdateIndex(t *testing.T) {
+	PatchConvey("test Indexer.UpdateIndex", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		indexer := &Indexer{
+			client: mockClient,
+			config: &IndexerConfig{
+				Collection: "test_collection",
+				Vector:     &VectorConfig{VectorField: "vector", MetricType: COSINE},
+				Sparse:     &SparseVectorConfig{VectorField: "sparse_vector", MetricType: BM25},
+			},
+		}
+
+		PatchConvey("test invalid arguments", func() {
+			err := indexer.UpdateIndex(ctx, "vector", nil)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "index builder not provided")
+
+			err = indexer.UpdateIndex(ctx, "content", NewHNSWIndexBuilder())
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "not a vector field")
+		})
+
+		PatchConvey("test rebuild loaded collection", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{
+				Index:       index.NewGenericIndex("vector_idx", nil),
+				IndexedRows: 50,
+				TotalRows:   100,
+			}, nil).Build()
+			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateLoaded}, nil).Build()
+			releaseMocker := Mock(GetMethod(mockClient, "ReleaseCollection")).Return(nil).Build()
+			var dropped milvusclient.DropIndexOption
+			Mock(GetMethod(mockClient, "DropIndex")).To(func(_ context.Context, opt milvusclient.DropIndexOption, _ ...grpc.CallOption) error {
+				dropped = opt
+				return nil
+			}).Build()
+
+			mockTask := &milvusclient.CreateIndexTask{}
+			var created milvusclient.CreateIndexOption
+			Mock(GetMethod(mockClient, "CreateIndex")).To(func(_ context.Context, opt milvusclient.CreateIndexOption, _ ...grpc.CallOption) (*milvusclient.CreateIndexTask, error) {
+				created = opt
+				return mockTask, nil
+			}).Build()
+			Mock(GetMethod(mockTask, "Await")).To(func(_ context.Context) error {
+				time.Sleep(20 * time.Millisecond)
+				return nil
+			}).Build()
+
+			mockLoadTask := milvusclient.LoadTask{}
+			loadMocker := Mock(GetMethod(mockClient, "LoadCollection")).Return(mockLoadTask, nil).Build()
+			Mock(GetMethod(&mockLoadTask, "Await")).Return(nil).Build()
+
+			var stages []IndexUpdateStage
+			var built IndexUpdateProgress
+			err := indexer.UpdateIndex(ctx, "vector", NewHNSWIndexBuilder().WithEfConstruction(500),
+				WithIndexUpdatePollInterval(time.Millisecond),
+				WithIndexUpdateProgress(func(progress IndexUpdateProgress) {
+					convey.So(progress.Field, convey.ShouldEqual, "vector")
+					if len(stages) == 0 || stages[len(stages)-1] != progress.Stage {
+						stages = append(stages, progress.Stage)
+					}
+					if progress.Stage == IndexUpdateStageBuilt {
+						built = progress
+					}
+				}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(releaseMocker.Times(), convey.ShouldEqual, 1)
+			convey.So(loadMocker.Times(), convey.ShouldEqual, 1)
+			convey.So(dropped.Request().GetIndexName(), convey.ShouldEqual, "vector_idx")
+			req := created.Request()
+			convey.So(req.GetIndexName(), convey.ShouldEqual, "vector_idx")
+			convey.So(req.GetFieldName(), convey.ShouldEqual, "vector")
+			convey.So(stages, convey.ShouldResemble, []IndexUpdateStage{
+				IndexUpdateStageReleased, IndexUpdateStageDropped, IndexUpdateStageBuilding,
+				IndexUpdateStageBuilt, IndexUpdateStageLoaded,
+			})
+			convey.So(built.IndexedRows, convey.ShouldEqual, 100)
+			convey.So(built.TotalRows, convey.ShouldEqual, 100)
+		})
+
+		PatchConvey("test create index without previous index on released collection", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{}, merr.WrapErrIndexNotFound("sparse_vector")).Build()
+			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateNotLoad}, nil).Build()
+			releaseMocker := Mock(GetMethod(mockClient, "ReleaseCollection")).Return(nil).Build()
+			dropMocker := Mock(GetMethod(mockClient, "DropIndex")).Return(nil).Build()
+			mockTask := &milvusclient.CreateIndexTask{}
+			Mock(GetMethod(mockClient, "CreateIndex")).Return(mockTask, nil).Build()
+			Mock(GetMethod(mockTask, "Await")).Return(nil).Build()
+			loadMocker := Mock(GetMethod(mockClient, "LoadCollection")).Return(milvusclient.LoadTask{}, nil).Build()
+
+			err := indexer.UpdateIndex(ctx, "sparse_vector", NewSparseInvertedIndexBuilder())
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(releaseMocker.Times(), convey.ShouldEqual, 0)
+			convey.So(dropMocker.Times(), convey.ShouldEqual, 0)
+			convey.So(loadMocker.Times(), convey.ShouldEqual, 0)
+		})
+
+		PatchConvey("test build failure", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{}, merr.WrapErrIndexNotFound("vector")).Build()
+			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateNotLoad}, nil).Build()
+			mockTask := &milvusclient.CreateIndexTask{}
+			Mock(GetMethod(mockClient, "CreateIndex")).Return(mockTask, nil).Build()
+			Mock(GetMethod(mockTask, "Await")).Return(fmt.Errorf("build error")).Build()
+
+			err := indexer.UpdateIndex(ctx, "vector", NewHNSWIndexBuilder())
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "build error")
+		})
+
+		PatchConvey("test build failure reloads released collection", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{
+				Index: index.NewGenericIndex("vector_idx", nil),
+			}, nil).Build()
+			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateLoaded}, nil).Build()
+			Mock(GetMethod(mockClient, "ReleaseCollection")).Return(nil).Build()
+			Mock(GetMethod(mockClient, "DropIndex")).Return(nil).Build()
+			Mock(GetMethod(mockClient, "CreateIndex")).Return(nil, fmt.Errorf("create error")).Build()
+			mockLoadTask := milvusclient.LoadTask{}
+			loadMocker := Mock(GetMethod(mockClient, "LoadCollection")).Return(mockLoadTask, nil).Build()
+			Mock(GetMethod(&mockLoadTask, "Await")).Return(nil).Build()
+
+			var stages []IndexUpdateStage
+			err := indexer.UpdateIndex(ctx, "vector", NewHNSWIndexBuilder(),
+				WithIndexUpdateProgress(func(progress IndexUpdateProgress) {
+					stages = append(stages, progress.Stage)
+				}))
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "create error")
+			convey.So(loadMocker.Times(), convey.ShouldEqual, 1)
+			convey.So(stages, convey.ShouldResemble, []IndexUpdateStage{IndexUpdateStageReleased, IndexUpdateStageDropped})
+
+			PatchConvey("reload failure is joined", func() {
+				Mock(GetMethod(mockClient, "LoadCollection")).Return(milvusclient.LoadTask{}, fmt.Errorf("load error")).Build()
+				err := indexer.UpdateIndex(ctx, "vector", NewHNSWIndexBuilder())
+				convey.So(err, convey.ShouldNotBeNil)
+				convey.So(err.Error(), convey.ShouldContainSubstring, "create error")
+				convey.So(err.Error(), convey.ShouldContainSubstring, "load error")
+			})
+		})
+
+		PatchConvey("test describe index failure", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{}, fmt.Errorf("connection refused")).Build()
+			releaseMocker := Mock(GetMethod(mockClient, "ReleaseCollection")).Return(nil).Build()
+			createMocker := Mock(GetMethod(mockClient, "CreateIndex")).Return(nil, nil).Build()
+
+			err := indexer.UpdateIndex(ctx, "vector", NewHNSWIndexBuilder())
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "failed to describe index")
+			convey.So(releaseMocker.Times(), convey.ShouldEqual, 0)
+			convey.So(createMocker.Times(), convey.ShouldEqual, 0)
+		})
+	})
+}
+
+func TestIndexer_UpdateIndexProperties(t *testing.T) {
+	PatchConvey("test Indexer.UpdateIndexProperties", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		indexer := &Indexer{
+			client: mockClient,
+			config: &IndexerConfig{Collection: "test_collection"},
+		}
+
+		PatchConvey("test empty properties", func() {
+			mocker := Mock(GetMethod(mockClient, "AlterIndexProperties")).Return(nil).Build()
+			convey.So(indexer.UpdateIndexProperties(ctx, "vector", nil), convey.ShouldBeNil)
+			convey.So(mocker.Times(), convey.ShouldEqual, 0)
+		})
+
+		PatchConvey("test success", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{
+				Index: index.NewGenericIndex("vector_idx", nil),
+			}, nil).Build()
+			mocker := Mock(GetMethod(mockClient, "AlterIndexProperties")).Return(nil).Build()
+			err := indexer.UpdateIndexProperties(ctx, "vector", map[string]string{PropertyMmapEnabled: "true"})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(mocker.Times(), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test index not found", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{}, fmt.Errorf("index not found")).Build()
+			err := indexer.UpdateIndexProperties(ctx, "vector", map[string]string{PropertyMmapEnabled: "true"})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "index not found")
+		})
+	})
+}
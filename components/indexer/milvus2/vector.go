@@ -0,0 +1,82 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// float32VectorKey is the metadata key of the precomputed float32 dense vector,
+// it is not stored in the metadata field.
+const float32VectorKey = "_milvus2_float32_vector"
+
+// WithFloat32Vector sets a precomputed float32 dense vector on the document.
+// The default DocumentConverter stores it as is when no embedder is configured,
+// saving the float64 to float32 copy of schema.Document.WithDenseVector, e.g. for vectors produced by a local model.
+func WithFloat32Vector(doc *schema.Document, vector []float32) *schema.Document {
+	if doc.MetaData == nil {
+		doc.MetaData = make(map[string]any)
+	}
+	doc.MetaData[float32VectorKey] = vector
+	return doc
+}
+
+// Float32Vector returns the float32 dense vector set by WithFloat32Vector.
+func Float32Vector(doc *schema.Document) []float32 {
+	if doc == nil || doc.MetaData == nil {
+		return nil
+	}
+	vector, _ := doc.MetaData[float32VectorKey].([]float32)
+	return vector
+}
+
+// float32VectorBuffer converts the dense vectors of a batch to float32 in a single allocation,
+// instead of one allocation per vector. The zero value is ready to use.
+type float32VectorBuffer struct {
+	buf []float32
+	dim int
+}
+
+// add converts the vector, remaining is the number of vectors left in the batch, including this one.
+func (b *float32VectorBuffer) add(vector []float64, remaining int) ([]float32, error) {
+	if err := b.checkDim(len(vector)); err != nil {
+		return nil, err
+	}
+	if cap(b.buf)-len(b.buf) < b.dim {
+		// the converted vectors keep referencing the previous buffer
+		b.buf = make([]float32, 0, b.dim*remaining)
+	}
+	start := len(b.buf)
+	for _, v := range vector {
+		b.buf = append(b.buf, float32(v))
+	}
+	return b.buf[start:len(b.buf):len(b.buf)], nil
+}
+
+// checkDim records the dimension of the first vector of the batch and checks the following ones against it.
+func (b *float32VectorBuffer) checkDim(dim int) error {
+	if b.dim == 0 {
+		b.dim = dim
+		return nil
+	}
+	if dim != b.dim {
+		return fmt.Errorf("vector dimension mismatch: need %d, got %d", b.dim, dim)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestFloat32Vector(t *testing.T) {
+	convey.Convey("test WithFloat32Vector", t, func() {
+		convey.So(Float32Vector(nil), convey.ShouldBeNil)
+		convey.So(Float32Vector(&schema.Document{}), convey.ShouldBeNil)
+
+		doc := WithFloat32Vector(&schema.Document{ID: "1"}, []float32{1, 2})
+		convey.So(Float32Vector(doc), convey.ShouldResemble, []float32{1, 2})
+	})
+}
+
+func TestFloat32VectorBuffer(t *testing.T) {
+	convey.Convey("test float32VectorBuffer", t, func() {
+		var buf float32VectorBuffer
+
+		a, err := buf.add([]float64{1, 2}, 3)
+		convey.So(err, convey.ShouldBeNil)
+		b, err := buf.add([]float64{3, 4}, 2)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(a, convey.ShouldResemble, []float32{1, 2})
+		convey.So(b, convey.ShouldResemble, []float32{3, 4})
+		// the vectors share the buffer of the batch
+		convey.So(cap(buf.buf), convey.ShouldEqual, 6)
+		convey.So(&buf.buf[2], convey.ShouldEqual, &b[0])
+
+		// appending to a converted vector does not overwrite the next one
+		_ = append(a, 10)
+		convey.So(b, convey.ShouldResemble, []float32{3, 4})
+
+		_, err = buf.add([]float64{1, 2, 3}, 1)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "need 2, got 3")
+	})
+}
+
+func TestDefaultDocumentConverter_Float32(t *testing.T) {
+	convey.Convey("test defaultDocumentConverter with float32 vectors", t, func() {
+		ctx := context.Background()
+		converter := defaultDocumentConverter(defaultScalarFields, &VectorConfig{VectorField: defaultVectorField}, nil, nil)
+		findColumn := func(cols []column.Column, name string) column.Column {
+			for _, col := range cols {
+				if col.Name() == name {
+					return col
+				}
+			}
+			return nil
+		}
+
+		convey.Convey("test float32 vector stored as is", func() {
+			vec := []float32{0.5, 0.25}
+			docs := []*schema.Document{
+				WithFloat32Vector(&schema.Document{ID: "1", MetaData: map[string]any{"k": "v"}}, vec),
+				(&schema.Document{ID: "2"}).WithDenseVector([]float64{1, 2}),
+			}
+			cols, err := converter(ctx, docs, nil)
+			convey.So(err, convey.ShouldBeNil)
+
+			vectorCol := findColumn(cols, defaultVectorField)
+			convey.So(vectorCol.(interface{ Dim() int }).Dim(), convey.ShouldEqual, 2)
+			first, err := vectorCol.Get(0)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(first, convey.ShouldResemble, entity.FloatVector{0.5, 0.25})
+			convey.So(&first.(entity.FloatVector)[0], convey.ShouldEqual, &vec[0])
+			second, err := vectorCol.Get(1)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(second, convey.ShouldResemble, entity.FloatVector{1, 2})
+
+			metadata, err := findColumn(cols, defaultMetadataField).Get(0)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(string(metadata.([]byte)), convey.ShouldEqual, `{"k":"v"}`)
+			// the caller's metadata is not modified
+			convey.So(Float32Vector(docs[0]), convey.ShouldResemble, vec)
+		})
+
+		convey.Convey("test embedder vectors take precedence", func() {
+			docs := []*schema.Document{WithFloat32Vector(&schema.Document{ID: "1"}, []float32{0.5, 0.25})}
+			cols, err := converter(ctx, docs, [][]float64{{1, 2, 3}})
+			convey.So(err, convey.ShouldBeNil)
+			first, err := findColumn(cols, defaultVectorField).Get(0)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(first, convey.ShouldResemble, entity.FloatVector{1, 2, 3})
+		})
+
+		convey.Convey("test dimension mismatch", func() {
+			docs := []*schema.Document{
+				WithFloat32Vector(&schema.Document{ID: "1"}, []float32{0.5, 0.25}),
+				(&schema.Document{ID: "2"}).WithDenseVector([]float64{1, 2, 3}),
+			}
+			_, err := converter(ctx, docs, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "document 1 (id: 2): vector dimension mismatch: need 2, got 3")
+		})
+	})
+}
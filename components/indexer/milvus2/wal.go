@@ -0,0 +1,392 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	walFileSuffix = ".wal"
+	walTempSuffix = ".tmp"
+	walDeadDir    = "dead"
+
+	defaultWALRetryInterval    = time.Second
+	defaultWALMaxRetryInterval = time.Minute
+	defaultWALMaxAttempts      = 10
+)
+
+// WALConfig configures the write-ahead buffer of the indexer.
+// When enabled, Store persists the embedded documents to a local directory before upserting them.
+// Batches failed to upsert, e.g. during a transient Milvus outage, are kept on disk and retried
+// in order by a background flusher with exponential backoff, so Store does not fail because of them.
+// A batch still failing after MaxAttempts upserts is moved to the dead-letter directory "<Dir>/dead",
+// so that it does not block the batches written after it.
+type WALConfig struct {
+	// Dir is the directory storing the pending batches, one file per Store call.
+	// Batches left by a previous process are flushed by the next indexer using the same Dir.
+	// Required.
+	Dir string
+
+	// RetryInterval is the interval between retries of the pending batches, doubled after each failure.
+	// Optional. Default: 1s
+	RetryInterval time.Duration
+
+	// MaxRetryInterval caps the interval between retries.
+	// Optional. Default: 1m
+	MaxRetryInterval time.Duration
+
+	// MaxAttempts is the number of failed upserts after which a batch is moved to the dead-letter directory.
+	// A batch which cannot be read back is moved there at once.
+	// Optional. Default: 10
+	MaxAttempts int
+
+	// OnError is called with the errors of the flushes started by Store and by the background flusher,
+	// which do not fail any call, and with the batches moved to the dead-letter directory.
+	// Optional.
+	OnError func(err error)
+}
+
+func (c *WALConfig) validate() error {
+	if c.Dir == "" {
+		return fmt.Errorf("[NewIndexer] WAL dir is required")
+	}
+	if c.RetryInterval <= 0 {
+		c.RetryInterval = defaultWALRetryInterval
+	}
+	if c.MaxRetryInterval <= 0 {
+		c.MaxRetryInterval = defaultWALMaxRetryInterval
+	}
+	if c.MaxRetryInterval < c.RetryInterval {
+		c.MaxRetryInterval = c.RetryInterval
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultWALMaxAttempts
+	}
+	return nil
+}
+
+// walBatch is the content of a Store call persisted in the write-ahead buffer.
+type walBatch struct {
+	Partition    string         `json:"partition,omitempty"`
+	Docs         []*walDocument `json:"docs"`
+	Vectors      [][]float64    `json:"vectors,omitempty"`
+	ExtraVectors [][][]float64  `json:"extra_vectors,omitempty"`
+}
+
+// walDocument keeps the precomputed vectors of a document apart from its metadata,
+// as their types are not preserved by the JSON encoding of the metadata.
+type walDocument struct {
+	Document      *schema.Document `json:"document"`
+	DenseVector   []float64        `json:"dense_vector,omitempty"`
+	Float32Vector []float32        `json:"float32_vector,omitempty"`
+	SparseVector  map[int]float64  `json:"sparse_vector,omitempty"`
+}
+
+func newWALBatch(docs []*schema.Document, vectors [][]float64, extraVectors [][][]float64, partition string) *walBatch {
+	batch := &walBatch{
+		Partition:    partition,
+		Docs:         make([]*walDocument, len(docs)),
+		Vectors:      vectors,
+		ExtraVectors: extraVectors,
+	}
+	for idx, doc := range docs {
+		batch.Docs[idx] = &walDocument{
+			Document:      doc,
+			DenseVector:   doc.DenseVector(),
+			Float32Vector: Float32Vector(doc),
+			SparseVector:  doc.SparseVector(),
+		}
+	}
+	return batch
+}
+
+func (b *walBatch) documents() []*schema.Document {
+	docs := make([]*schema.Document, len(b.Docs))
+	for idx, wd := range b.Docs {
+		doc := wd.Document
+		if doc == nil {
+			doc = &schema.Document{}
+		}
+		if wd.DenseVector != nil {
+			doc.WithDenseVector(wd.DenseVector)
+		}
+		if wd.Float32Vector != nil {
+			WithFloat32Vector(doc, wd.Float32Vector)
+		}
+		if wd.SparseVector != nil {
+			doc.WithSparseVector(wd.SparseVector)
+		}
+		docs[idx] = doc
+	}
+	return docs
+}
+
+// writeAheadLog is a file-backed queue of batches waiting to be upserted, flushed in the order they were written.
+type writeAheadLog struct {
+	conf   *WALConfig
+	upsert func(ctx context.Context, batch *walBatch) error
+
+	mu      sync.Mutex
+	pending []string
+	lastSeq int64
+	// attempts counts the failed upserts of the pending batches
+	attempts map[string]int
+
+	// flushMu serializes flushes, so that batches are upserted in order.
+	flushMu sync.Mutex
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newWriteAheadLog loads the batches left in conf.Dir and starts the background flusher.
+func newWriteAheadLog(conf *WALConfig, upsert func(ctx context.Context, batch *walBatch) error) (*writeAheadLog, error) {
+	if err := os.MkdirAll(conf.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("[NewIndexer] failed to create WAL dir: %w", err)
+	}
+	entries, err := os.ReadDir(conf.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("[NewIndexer] failed to read WAL dir: %w", err)
+	}
+
+	w := &writeAheadLog{
+		conf:     conf,
+		upsert:   upsert,
+		attempts: make(map[string]int),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+		case strings.HasSuffix(name, walTempSuffix):
+			// an interrupted write, the Store call did not succeed
+			_ = os.Remove(filepath.Join(conf.Dir, name))
+		case strings.HasSuffix(name, walFileSuffix):
+			w.pending = append(w.pending, name)
+		}
+	}
+	sort.Strings(w.pending)
+	if n := len(w.pending); n > 0 {
+		w.lastSeq, _ = strconv.ParseInt(strings.TrimSuffix(w.pending[n-1], walFileSuffix), 10, 64)
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// append durably writes the batch to the buffer.
+func (w *writeAheadLog) append(batch *walBatch) error {
+	data, err := sonic.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seq := time.Now().UnixNano()
+	if seq <= w.lastSeq {
+		seq = w.lastSeq + 1
+	}
+	// zero padded, so that the file names sort in write order
+	name := fmt.Sprintf("%020d%s", seq, walFileSuffix)
+	if err := writeFileSync(w.conf.Dir, name, data); err != nil {
+		return err
+	}
+	w.lastSeq = seq
+	w.pending = append(w.pending, name)
+	return nil
+}
+
+// flush upserts the pending batches in order, stopping at the first failure.
+// It waits for the flush in progress, if any.
+func (w *writeAheadLog) flush(ctx context.Context) error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+	return w.flushLocked(ctx)
+}
+
+// tryFlush is like flush, but returns at once if a flush is in progress,
+// which upserts the batches appended meanwhile as well.
+func (w *writeAheadLog) tryFlush(ctx context.Context) error {
+	if !w.flushMu.TryLock() {
+		return nil
+	}
+	defer w.flushMu.Unlock()
+	return w.flushLocked(ctx)
+}
+
+func (w *writeAheadLog) flushLocked(ctx context.Context) error {
+	for {
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.mu.Unlock()
+			return nil
+		}
+		name := w.pending[0]
+		w.mu.Unlock()
+
+		path := filepath.Join(w.conf.Dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read batch %s: %w", name, err)
+		}
+		batch := &walBatch{}
+		if err := sonic.Unmarshal(data, batch); err != nil {
+			// retrying cannot fix a corrupted batch
+			if err := w.deadLetter(name, fmt.Errorf("failed to unmarshal batch %s: %w", name, err)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.upsert(ctx, batch); err != nil {
+			err = fmt.Errorf("failed to upsert batch %s: %w", name, err)
+			w.mu.Lock()
+			w.attempts[name]++
+			attempts := w.attempts[name]
+			w.mu.Unlock()
+			if attempts < w.conf.MaxAttempts {
+				return err
+			}
+			if err := w.deadLetter(name, fmt.Errorf("giving up after %d attempts: %w", attempts, err)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove batch %s: %w", name, err)
+		}
+		w.dequeue()
+	}
+}
+
+// deadLetter moves the first pending batch to the dead-letter directory and reports cause to OnError.
+func (w *writeAheadLog) deadLetter(name string, cause error) error {
+	dir := filepath.Join(w.conf.Dir, walDeadDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create dead-letter dir: %w", err)
+	}
+	if err := os.Rename(filepath.Join(w.conf.Dir, name), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("failed to move batch %s to dead-letter dir: %w", name, err)
+	}
+	w.dequeue()
+	w.report(fmt.Errorf("batch %s moved to %s: %w", name, dir, cause))
+	return nil
+}
+
+func (w *writeAheadLog) dequeue() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.attempts, w.pending[0])
+	w.pending = w.pending[1:]
+}
+
+// report passes an error which does not fail any call to OnError.
+func (w *writeAheadLog) report(err error) {
+	if w.conf.OnError != nil {
+		w.conf.OnError(err)
+	}
+}
+
+func (w *writeAheadLog) size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.pending)
+}
+
+// run retries the pending batches until close is called,
+// doubling the interval after each failure up to MaxRetryInterval.
+func (w *writeAheadLog) run() {
+	defer close(w.done)
+
+	interval := w.conf.RetryInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-timer.C:
+		}
+
+		if w.size() > 0 {
+			if err := w.flush(context.Background()); err != nil {
+				w.report(err)
+				interval *= 2
+				if interval > w.conf.MaxRetryInterval {
+					interval = w.conf.MaxRetryInterval
+				}
+			} else {
+				interval = w.conf.RetryInterval
+			}
+		}
+		timer.Reset(interval)
+	}
+}
+
+// close stops the background flusher, the pending batches are kept on disk.
+func (w *writeAheadLog) close() {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		<-w.done
+	})
+}
+
+// writeFileSync writes the file atomically and syncs it to disk before returning.
+func writeFileSync(dir, name string, data []byte) error {
+	tmp := filepath.Join(dir, name+walTempSuffix)
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create batch file: %w", err)
+	}
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write batch file: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to rename batch file: %w", err)
+	}
+	// Persist the rename. Syncing a directory is not supported on every platform, so errors are ignored.
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
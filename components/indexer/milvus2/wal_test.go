@@ -0,0 +1,305 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+// recordingUpsert records the upserted batches, failing while err is set.
+type recordingUpsert struct {
+	mu      sync.Mutex
+	err     error
+	batches []*walBatch
+}
+
+func (r *recordingUpsert) upsert(_ context.Context, batch *walBatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.batches = append(r.batches, batch)
+	return nil
+}
+
+func (r *recordingUpsert) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+func TestWALConfig_validate(t *testing.T) {
+	convey.Convey("test WALConfig.validate", t, func() {
+		convey.Convey("test missing dir", func() {
+			err := (&WALConfig{}).validate()
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "WAL dir is required")
+		})
+
+		convey.Convey("test defaults", func() {
+			conf := &WALConfig{Dir: t.TempDir()}
+			convey.So(conf.validate(), convey.ShouldBeNil)
+			convey.So(conf.RetryInterval, convey.ShouldEqual, defaultWALRetryInterval)
+			convey.So(conf.MaxRetryInterval, convey.ShouldEqual, defaultWALMaxRetryInterval)
+			convey.So(conf.MaxAttempts, convey.ShouldEqual, defaultWALMaxAttempts)
+		})
+
+		convey.Convey("test max retry interval below retry interval", func() {
+			conf := &WALConfig{Dir: t.TempDir(), RetryInterval: time.Minute, MaxRetryInterval: time.Second}
+			convey.So(conf.validate(), convey.ShouldBeNil)
+			convey.So(conf.MaxRetryInterval, convey.ShouldEqual, time.Minute)
+		})
+	})
+}
+
+func TestWriteAheadLog(t *testing.T) {
+	convey.Convey("test writeAheadLog", t, func() {
+		ctx := context.Background()
+		conf := &WALConfig{Dir: t.TempDir(), RetryInterval: time.Hour}
+		convey.So(conf.validate(), convey.ShouldBeNil)
+		rec := &recordingUpsert{}
+
+		docA := (&schema.Document{ID: "a", MetaData: map[string]any{"key": "value"}}).WithDenseVector([]float64{0.1, 0.2})
+		docB := WithFloat32Vector((&schema.Document{ID: "b"}).WithSparseVector(map[int]float64{3: 0.5}), []float32{0.5, 0.25})
+
+		convey.Convey("test flush in order", func() {
+			w, err := newWriteAheadLog(conf, rec.upsert)
+			convey.So(err, convey.ShouldBeNil)
+			defer w.close()
+
+			convey.So(w.append(newWALBatch([]*schema.Document{docA}, [][]float64{{1, 2}}, nil, "")), convey.ShouldBeNil)
+			convey.So(w.append(newWALBatch([]*schema.Document{docB}, nil, [][][]float64{{{3}}}, "p1")), convey.ShouldBeNil)
+			convey.So(w.size(), convey.ShouldEqual, 2)
+
+			convey.So(w.flush(ctx), convey.ShouldBeNil)
+			convey.So(w.size(), convey.ShouldEqual, 0)
+			convey.So(len(rec.batches), convey.ShouldEqual, 2)
+
+			first := rec.batches[0]
+			convey.So(first.Partition, convey.ShouldEqual, "")
+			convey.So(first.Vectors, convey.ShouldResemble, [][]float64{{1, 2}})
+			docs := first.documents()
+			convey.So(docs[0].ID, convey.ShouldEqual, "a")
+			convey.So(docs[0].MetaData["key"], convey.ShouldEqual, "value")
+			convey.So(docs[0].DenseVector(), convey.ShouldResemble, []float64{0.1, 0.2})
+
+			second := rec.batches[1]
+			convey.So(second.Partition, convey.ShouldEqual, "p1")
+			convey.So(second.ExtraVectors, convey.ShouldResemble, [][][]float64{{{3}}})
+			convey.So(second.documents()[0].SparseVector(), convey.ShouldResemble, map[int]float64{3: 0.5})
+			convey.So(Float32Vector(second.documents()[0]), convey.ShouldResemble, []float32{0.5, 0.25})
+
+			entries, err := os.ReadDir(conf.Dir)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(entries), convey.ShouldEqual, 0)
+		})
+
+		convey.Convey("test flush stops at first failure", func() {
+			w, err := newWriteAheadLog(conf, rec.upsert)
+			convey.So(err, convey.ShouldBeNil)
+			defer w.close()
+
+			convey.So(w.append(newWALBatch([]*schema.Document{docA}, nil, nil, "")), convey.ShouldBeNil)
+			convey.So(w.append(newWALBatch([]*schema.Document{docB}, nil, nil, "")), convey.ShouldBeNil)
+
+			rec.setErr(fmt.Errorf("unavailable"))
+			err = w.flush(ctx)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "unavailable")
+			convey.So(w.size(), convey.ShouldEqual, 2)
+
+			rec.setErr(nil)
+			convey.So(w.flush(ctx), convey.ShouldBeNil)
+			convey.So(len(rec.batches), convey.ShouldEqual, 2)
+			convey.So(rec.batches[0].documents()[0].ID, convey.ShouldEqual, "a")
+			convey.So(rec.batches[1].documents()[0].ID, convey.ShouldEqual, "b")
+		})
+
+		convey.Convey("test dead letter after max attempts", func() {
+			conf.MaxAttempts = 2
+			var reported []error
+			conf.OnError = func(err error) { reported = append(reported, err) }
+			w, err := newWriteAheadLog(conf, func(ctx context.Context, batch *walBatch) error {
+				if batch.documents()[0].ID == "a" {
+					return fmt.Errorf("schema mismatch")
+				}
+				return rec.upsert(ctx, batch)
+			})
+			convey.So(err, convey.ShouldBeNil)
+			defer w.close()
+
+			convey.So(w.append(newWALBatch([]*schema.Document{docA}, nil, nil, "")), convey.ShouldBeNil)
+			convey.So(w.append(newWALBatch([]*schema.Document{docB}, nil, nil, "")), convey.ShouldBeNil)
+			poison := w.pending[0]
+
+			convey.So(w.flush(ctx), convey.ShouldNotBeNil)
+			convey.So(w.size(), convey.ShouldEqual, 2)
+			convey.So(reported, convey.ShouldBeEmpty)
+
+			// the second failure moves the poison batch away, the next batch is no longer blocked
+			convey.So(w.flush(ctx), convey.ShouldBeNil)
+			convey.So(w.size(), convey.ShouldEqual, 0)
+			convey.So(len(rec.batches), convey.ShouldEqual, 1)
+			convey.So(rec.batches[0].documents()[0].ID, convey.ShouldEqual, "b")
+			convey.So(len(reported), convey.ShouldEqual, 1)
+			convey.So(reported[0].Error(), convey.ShouldContainSubstring, "schema mismatch")
+			_, err = os.Stat(filepath.Join(conf.Dir, walDeadDir, poison))
+			convey.So(err, convey.ShouldBeNil)
+
+			// dead letters are not loaded again
+			recovered, err := newWriteAheadLog(conf, rec.upsert)
+			convey.So(err, convey.ShouldBeNil)
+			defer recovered.close()
+			convey.So(recovered.size(), convey.ShouldEqual, 0)
+		})
+
+		convey.Convey("test corrupted batch is dead-lettered", func() {
+			convey.So(os.WriteFile(filepath.Join(conf.Dir, "00000000000000000001.wal"), []byte("{"), 0o644), convey.ShouldBeNil)
+			w, err := newWriteAheadLog(conf, rec.upsert)
+			convey.So(err, convey.ShouldBeNil)
+			defer w.close()
+
+			convey.So(w.flush(ctx), convey.ShouldBeNil)
+			convey.So(w.size(), convey.ShouldEqual, 0)
+			_, err = os.Stat(filepath.Join(conf.Dir, walDeadDir, "00000000000000000001.wal"))
+			convey.So(err, convey.ShouldBeNil)
+		})
+
+		convey.Convey("test try flush does not wait for a flush in progress", func() {
+			w, err := newWriteAheadLog(conf, rec.upsert)
+			convey.So(err, convey.ShouldBeNil)
+			defer w.close()
+			convey.So(w.append(newWALBatch([]*schema.Document{docA}, nil, nil, "")), convey.ShouldBeNil)
+
+			w.flushMu.Lock()
+			convey.So(w.tryFlush(ctx), convey.ShouldBeNil)
+			convey.So(w.size(), convey.ShouldEqual, 1)
+			w.flushMu.Unlock()
+
+			convey.So(w.tryFlush(ctx), convey.ShouldBeNil)
+			convey.So(w.size(), convey.ShouldEqual, 0)
+		})
+
+		convey.Convey("test recover pending batches", func() {
+			w, err := newWriteAheadLog(conf, rec.upsert)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(w.append(newWALBatch([]*schema.Document{docA}, nil, nil, "")), convey.ShouldBeNil)
+			convey.So(w.append(newWALBatch([]*schema.Document{docB}, nil, nil, "")), convey.ShouldBeNil)
+			w.close()
+
+			tmp := filepath.Join(conf.Dir, "00000000000000000001.wal.tmp")
+			convey.So(os.WriteFile(tmp, []byte("{"), 0o644), convey.ShouldBeNil)
+
+			recovered, err := newWriteAheadLog(conf, rec.upsert)
+			convey.So(err, convey.ShouldBeNil)
+			defer recovered.close()
+			convey.So(recovered.size(), convey.ShouldEqual, 2)
+			convey.So(recovered.lastSeq, convey.ShouldEqual, w.lastSeq)
+			_, err = os.Stat(tmp)
+			convey.So(os.IsNotExist(err), convey.ShouldBeTrue)
+
+			convey.So(recovered.flush(ctx), convey.ShouldBeNil)
+			convey.So(len(rec.batches), convey.ShouldEqual, 2)
+			convey.So(rec.batches[0].documents()[0].ID, convey.ShouldEqual, "a")
+		})
+
+		convey.Convey("test background retry", func() {
+			conf.RetryInterval = 10 * time.Millisecond
+			rec.setErr(fmt.Errorf("unavailable"))
+			w, err := newWriteAheadLog(conf, rec.upsert)
+			convey.So(err, convey.ShouldBeNil)
+			defer w.close()
+
+			convey.So(w.append(newWALBatch([]*schema.Document{docA}, nil, nil, "")), convey.ShouldBeNil)
+			time.Sleep(50 * time.Millisecond)
+			convey.So(w.size(), convey.ShouldEqual, 1)
+
+			rec.setErr(nil)
+			deadline := time.Now().Add(5 * time.Second)
+			for w.size() > 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			convey.So(w.size(), convey.ShouldEqual, 0)
+		})
+	})
+}
+
+func TestIndexer_StoreWithWAL(t *testing.T) {
+	PatchConvey("test Indexer.Store with WAL", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+
+		indexer := &Indexer{
+			client: mockClient,
+			config: &IndexerConfig{
+				Collection: "test_collection",
+				Vector: &VectorConfig{
+					Dimension: 128,
+				},
+				Embedding: &mockEmbedding{dims: 128},
+				DocumentConverter: defaultDocumentConverter(defaultScalarFields, &VectorConfig{
+					VectorField: defaultVectorField,
+				}, nil, nil),
+			},
+		}
+		var reported []error
+		conf := &WALConfig{Dir: t.TempDir(), RetryInterval: time.Hour, OnError: func(err error) { reported = append(reported, err) }}
+		convey.So(conf.validate(), convey.ShouldBeNil)
+		wal, err := newWriteAheadLog(conf, indexer.upsertBatch)
+		convey.So(err, convey.ShouldBeNil)
+		indexer.wal = wal
+		defer indexer.Close()
+
+		docs := []*schema.Document{
+			{ID: "doc1", Content: "Test document 1"},
+			{ID: "doc2", Content: "Test document 2"},
+		}
+
+		upsertErr := fmt.Errorf("upsert error")
+		upserted := milvusclient.UpsertResult{IDs: column.NewColumnVarChar("id", []string{"doc1", "doc2"})}
+		Mock(GetMethod(mockClient, "Upsert")).Return(Sequence(milvusclient.UpsertResult{}, upsertErr).
+			Then(milvusclient.UpsertResult{}, upsertErr).
+			Then(upserted, nil)).Build()
+
+		ids, err := indexer.Store(ctx, docs)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(ids, convey.ShouldResemble, []string{"doc1", "doc2"})
+		convey.So(indexer.Pending(), convey.ShouldEqual, 1)
+		convey.So(len(reported), convey.ShouldEqual, 1)
+		convey.So(reported[0].Error(), convey.ShouldContainSubstring, "upsert error")
+
+		err = indexer.Flush(ctx)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "upsert error")
+
+		convey.So(indexer.Flush(ctx), convey.ShouldBeNil)
+		convey.So(indexer.Pending(), convey.ShouldEqual, 0)
+	})
+}
@@ -0,0 +1,31 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+// Validate checks the config without calling Redis, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid. Unlike NewIndexer, it does not fill in the defaults of the config.
+func (c *IndexerConfig) Validate() error {
+	r := configcheck.New("redis.IndexerConfig")
+	r.Checkf(c.Client != nil, "Client", "redis client not provided")
+	configcheck.NonNegative(r, "BatchSize", &c.BatchSize)
+	r.Checkf(c.Embedding != nil, "Embedding", "embedding not provided")
+	return r.Err()
+}
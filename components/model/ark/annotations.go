@@ -0,0 +1,175 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+const keyOfAnnotations = "ark-annotations"
+
+// AnnotationType is the type of an Annotation.
+type AnnotationType string
+
+const (
+	// AnnotationTypeURLCitation is a citation of a web page, e.g. found by the web search tool.
+	AnnotationTypeURLCitation AnnotationType = "url_citation"
+	// AnnotationTypeDocCitation is a citation of a chunk of a document, e.g. found by the knowledge base search tool.
+	AnnotationTypeDocCitation AnnotationType = "doc_citation"
+)
+
+// Annotation is a citation of the output text, added by the built-in tools of the Responses API.
+type Annotation struct {
+	// Type is the type of the citation.
+	Type AnnotationType `json:"type,omitempty"`
+	// Title is the title of the cited web page or document.
+	Title string `json:"title,omitempty"`
+	// URL is the URL of the cited web page or document.
+	URL string `json:"url,omitempty"`
+
+	// The following fields are only set for the url citations.
+	SiteName      string           `json:"site_name,omitempty"`
+	LogoURL       string           `json:"logo_url,omitempty"`
+	MobileURL     string           `json:"mobile_url,omitempty"`
+	PublishTime   string           `json:"publish_time,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	FreshnessInfo string           `json:"freshness_info,omitempty"`
+	CoverImage    *AnnotationImage `json:"cover_image,omitempty"`
+
+	// The following fields are only set for the doc citations.
+	DocID            string           `json:"doc_id,omitempty"`
+	DocName          string           `json:"doc_name,omitempty"`
+	ChunkID          *int32           `json:"chunk_id,omitempty"`
+	ChunkAttachments []map[string]any `json:"chunk_attachments,omitempty"`
+}
+
+// AnnotationImage is the cover image of a cited web page.
+type AnnotationImage struct {
+	URL    string `json:"url,omitempty"`
+	Width  int64  `json:"width,omitempty"`
+	Height int64  `json:"height,omitempty"`
+}
+
+// ToDocument converts the citation to a document, e.g. to render it with the documents of a retriever.
+// The ID is the doc ID or the URL, the content is the summary, and the other fields are kept in the metadata.
+func (a *Annotation) ToDocument() *schema.Document {
+	doc := &schema.Document{
+		ID:       a.DocID,
+		Content:  a.Summary,
+		MetaData: map[string]any{"type": string(a.Type)},
+	}
+	if doc.ID == "" {
+		doc.ID = a.URL
+	}
+	meta := map[string]string{
+		"title":          a.Title,
+		"url":            a.URL,
+		"site_name":      a.SiteName,
+		"logo_url":       a.LogoURL,
+		"mobile_url":     a.MobileURL,
+		"publish_time":   a.PublishTime,
+		"freshness_info": a.FreshnessInfo,
+		"doc_name":       a.DocName,
+	}
+	for k, v := range meta {
+		if v != "" {
+			doc.MetaData[k] = v
+		}
+	}
+	if a.CoverImage != nil {
+		doc.MetaData["cover_image"] = a.CoverImage
+	}
+	if a.ChunkID != nil {
+		doc.MetaData["chunk_id"] = *a.ChunkID
+	}
+	if len(a.ChunkAttachments) > 0 {
+		doc.MetaData["chunk_attachments"] = a.ChunkAttachments
+	}
+	return doc
+}
+
+type arkAnnotations []*Annotation
+
+func init() {
+	compose.RegisterStreamChunkConcatFunc(func(chunks []arkAnnotations) (final arkAnnotations, err error) {
+		for _, chunk := range chunks {
+			final = append(final, chunk...)
+		}
+		return final, nil
+	})
+	schema.RegisterName[arkAnnotations]("_eino_ext_ark_annotations")
+}
+
+// GetAnnotations returns the citations of the output text, e.g. the web pages found by the web search tool,
+// in the order they are added. When streaming, each chunk carries the citations added with it.
+// Only available for ResponsesAPI responses.
+func GetAnnotations(msg *schema.Message) []*Annotation {
+	annotations, _ := getMsgExtraValue[arkAnnotations](msg, keyOfAnnotations)
+	return annotations
+}
+
+func addAnnotations(msg *schema.Message, annotations []*responses.Annotation) {
+	if len(annotations) == 0 {
+		return
+	}
+	list, _ := getMsgExtraValue[arkAnnotations](msg, keyOfAnnotations)
+	for _, a := range annotations {
+		if a != nil {
+			list = append(list, toAnnotation(a))
+		}
+	}
+	if len(list) > 0 {
+		setMsgExtra(msg, keyOfAnnotations, list)
+	}
+}
+
+func toAnnotation(a *responses.Annotation) *Annotation {
+	annotation := &Annotation{
+		Title:         a.Title,
+		URL:           a.Url,
+		SiteName:      ptrFromOrZero(a.SiteName),
+		LogoURL:       ptrFromOrZero(a.LogoUrl),
+		MobileURL:     ptrFromOrZero(a.MobileUrl),
+		PublishTime:   ptrFromOrZero(a.PublishTime),
+		Summary:       ptrFromOrZero(a.Summary),
+		FreshnessInfo: ptrFromOrZero(a.FreshnessInfo),
+		DocID:         ptrFromOrZero(a.DocId),
+		DocName:       ptrFromOrZero(a.DocName),
+		ChunkID:       a.ChunkId,
+	}
+	switch a.Type {
+	case responses.AnnotationType_url_citation:
+		annotation.Type = AnnotationTypeURLCitation
+	case responses.AnnotationType_doc_citation:
+		annotation.Type = AnnotationTypeDocCitation
+	}
+	if a.CoverImage != nil {
+		annotation.CoverImage = &AnnotationImage{
+			URL:    ptrFromOrZero(a.CoverImage.Url),
+			Width:  ptrFromOrZero(a.CoverImage.Width),
+			Height: ptrFromOrZero(a.CoverImage.Height),
+		}
+	}
+	for _, attachment := range a.ChunkAttachment {
+		if attachment != nil {
+			annotation.ChunkAttachments = append(annotation.ChunkAttachments, attachment.AsMap())
+		}
+	}
+	return annotation
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestAnnotations(t *testing.T) {
+	urlCitation := &responses.Annotation{
+		Type:       responses.AnnotationType_url_citation,
+		Title:      "Eino",
+		Url:        "https://www.cloudwego.io/docs/eino/",
+		SiteName:   ptrOf("CloudWeGo"),
+		Summary:    ptrOf("the LLM application framework"),
+		CoverImage: &responses.CoverImage{Url: ptrOf("https://www.cloudwego.io/cover.png"), Width: ptrOf(int64(640)), Height: ptrOf(int64(480))},
+	}
+	attachment, err := structpb.NewStruct(map[string]any{"page": 3})
+	assert.NoError(t, err)
+	docCitation := &responses.Annotation{
+		Type:            responses.AnnotationType_doc_citation,
+		Title:           "manual",
+		DocId:           ptrOf("doc_1"),
+		DocName:         ptrOf("manual.pdf"),
+		ChunkId:         ptrOf(int32(7)),
+		ChunkAttachment: []*structpb.Struct{attachment},
+	}
+	expectedURL := &Annotation{
+		Type:       AnnotationTypeURLCitation,
+		Title:      "Eino",
+		URL:        "https://www.cloudwego.io/docs/eino/",
+		SiteName:   "CloudWeGo",
+		Summary:    "the LLM application framework",
+		CoverImage: &AnnotationImage{URL: "https://www.cloudwego.io/cover.png", Width: 640, Height: 480},
+	}
+	expectedDoc := &Annotation{
+		Type:             AnnotationTypeDocCitation,
+		Title:            "manual",
+		DocID:            "doc_1",
+		DocName:          "manual.pdf",
+		ChunkID:          ptrOf(int32(7)),
+		ChunkAttachments: []map[string]any{{"page": float64(3)}},
+	}
+
+	t.Run("generate", func(t *testing.T) {
+		cm := &ResponsesAPIChatModel{}
+		msg, err := cm.toOutputMessage(&responses.ResponseObject{
+			Status: responses.ResponseStatus_completed,
+			Output: []*responses.OutputItem{{Union: &responses.OutputItem_OutputMessage{OutputMessage: &responses.ItemOutputMessage{
+				Content: []*responses.OutputContentItem{{Union: &responses.OutputContentItem_Text{Text: &responses.OutputContentItemText{
+					Text:        "Eino is a framework.",
+					Annotations: []*responses.Annotation{urlCitation, nil, docCitation},
+				}}}},
+			}}}},
+			Usage: &responses.Usage{},
+		}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Eino is a framework.", msg.Content)
+		assert.Equal(t, []*Annotation{expectedURL, expectedDoc}, GetAnnotations(msg))
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		ctx := context.Background()
+		cm, err := NewResponsesAPIChatModel(ctx, &ResponsesAPIConfig{
+			Model: "test-model",
+			Client: &fakeResponsesClient{events: []*responses.Event{
+				{Event: &responses.Event_Text{Text: &responses.OutputTextEvent{Delta: ptrOf("Eino")}}},
+				{Event: &responses.Event_ResponseAnnotationAdded{ResponseAnnotationAdded: &responses.ResponseAnnotationAddedEvent{Annotation: urlCitation}}},
+				{Event: &responses.Event_Text{Text: &responses.OutputTextEvent{Delta: ptrOf(" is a framework.")}}},
+				{Event: &responses.Event_ResponseAnnotationAdded{ResponseAnnotationAdded: &responses.ResponseAnnotationAddedEvent{Annotation: docCitation}}},
+			}},
+		})
+		assert.NoError(t, err)
+		sr, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("what is eino")})
+		assert.NoError(t, err)
+		var chunks []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+		msg, err := schema.ConcatMessages(chunks)
+		assert.NoError(t, err)
+		assert.Equal(t, "Eino is a framework.", msg.Content)
+		assert.Equal(t, []*Annotation{expectedURL, expectedDoc}, GetAnnotations(msg))
+	})
+
+	t.Run("to document", func(t *testing.T) {
+		assert.Equal(t, &schema.Document{
+			ID:      "https://www.cloudwego.io/docs/eino/",
+			Content: "the LLM application framework",
+			MetaData: map[string]any{
+				"type":        "url_citation",
+				"title":       "Eino",
+				"url":         "https://www.cloudwego.io/docs/eino/",
+				"site_name":   "CloudWeGo",
+				"cover_image": expectedURL.CoverImage,
+			},
+		}, expectedURL.ToDocument())
+		assert.Equal(t, &schema.Document{
+			ID: "doc_1",
+			MetaData: map[string]any{
+				"type":              "doc_citation",
+				"title":             "manual",
+				"doc_name":          "manual.pdf",
+				"chunk_id":          int32(7),
+				"chunk_attachments": expectedDoc.ChunkAttachments,
+			},
+		}, expectedDoc.ToDocument())
+	})
+
+	assert.Nil(t, GetAnnotations(&schema.Message{}))
+	assert.Nil(t, GetAnnotations(nil))
+}
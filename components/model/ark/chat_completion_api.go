@@ -23,12 +23,16 @@ import (
 	"io"
 	"log"
 	"runtime/debug"
+	"time"
 
 	"github.com/eino-contrib/jsonschema"
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 	autils "github.com/volcengine/volcengine-go-sdk/service/arkruntime/utils"
 
+	"github.com/cloudwego/eino-ext/libs/multimodal"
+	"github.com/cloudwego/eino-ext/libs/pii"
+	"github.com/cloudwego/eino-ext/libs/stall"
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	fmodel "github.com/cloudwego/eino/components/model"
@@ -59,6 +63,11 @@ type completionAPIChatModel struct {
 	reasoningEffort     *model.ReasoningEffort
 	batchChat           *BatchChatConfig
 	maxCompletionTokens *int
+	stallTimeout        time.Duration
+	mediaLimits         *multimodal.Limits
+	unsupportedFields   map[string][]RequestField
+	interceptor         pii.Interceptor
+	disableCallbacks    bool
 }
 
 type tool struct {
@@ -77,6 +86,16 @@ func (cm *completionAPIChatModel) Generate(ctx context.Context, in []*schema.Mes
 
 	ctx = callbacks.EnsureRunInfo(ctx, getType(), components.ComponentOfChatModel)
 
+	in, restorer, err := pii.Apply(ctx, cm.interceptor, in)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			outMsg = restorer.Restore(outMsg)
+		}
+	}()
+
 	options := fmodel.GetCommonOptions(&fmodel.Options{
 		Temperature: cm.temperature,
 		MaxTokens:   cm.maxTokens,
@@ -98,6 +117,7 @@ func (cm *completionAPIChatModel) Generate(ctx context.Context, in []*schema.Mes
 	if err != nil {
 		return nil, err
 	}
+	droppedFields := dropUnsupportedChatFields(cm.unsupportedFields, req, specOptions)
 
 	reqConf := &fmodel.Config{
 		Model:       req.Model,
@@ -112,16 +132,21 @@ func (cm *completionAPIChatModel) Generate(ctx context.Context, in []*schema.Mes
 		tools = options.Tools
 	}
 
-	ctx = callbacks.OnStart(ctx, &fmodel.CallbackInput{
-		Messages:   in,
-		Tools:      tools, // join tool info from call options
-		ToolChoice: options.ToolChoice,
-		Config:     reqConf,
-		Extra:      map[string]any{callbackExtraKeyThinking: specOptions.thinking},
-	})
+	if !cm.disableCallbacks {
+		ctx = callbacks.OnStart(ctx, &fmodel.CallbackInput{
+			Messages:   in,
+			Tools:      tools, // join tool info from call options
+			ToolChoice: options.ToolChoice,
+			Config:     reqConf,
+			Extra: droppedFieldsExtra(map[string]any{
+				callbackExtraKeyThinking:       specOptions.thinking,
+				callbackExtraKeyThinkingConfig: thinkingExtra(specOptions.thinking),
+			}, droppedFields),
+		})
+	}
 
 	defer func() {
-		if err != nil {
+		if err != nil && !cm.disableCallbacks {
 			callbacks.OnError(ctx, err)
 		}
 	}()
@@ -147,16 +172,23 @@ func (cm *completionAPIChatModel) Generate(ctx context.Context, in []*schema.Mes
 	if err != nil {
 		return nil, err
 	}
-
-	callbacks.OnEnd(ctx, &fmodel.CallbackOutput{
-		Message:    outMsg,
-		Config:     reqConf,
-		TokenUsage: cm.toModelCallbackUsage(outMsg.ResponseMeta),
-		Extra: map[string]any{
-			callbackExtraKeyThinking: specOptions.thinking,
-			callbackExtraModelName:   resp.Model,
-		},
-	})
+	if specOptions.cache != nil && specOptions.cache.ContextID != nil {
+		// share the cache metadata accessors with the ResponsesAPI, see GetContextID
+		setContextID(outMsg, *specOptions.cache.ContextID)
+	}
+
+	if !cm.disableCallbacks {
+		callbacks.OnEnd(ctx, &fmodel.CallbackOutput{
+			Message:    outMsg,
+			Config:     reqConf,
+			TokenUsage: cm.toModelCallbackUsage(outMsg.ResponseMeta),
+			Extra: droppedFieldsExtra(map[string]any{
+				callbackExtraKeyThinking:       specOptions.thinking,
+				callbackExtraKeyThinkingConfig: thinkingExtra(specOptions.thinking),
+				callbackExtraModelName:         resp.Model,
+			}, droppedFields),
+		})
+	}
 
 	return outMsg, nil
 }
@@ -166,6 +198,16 @@ func (cm *completionAPIChatModel) Stream(ctx context.Context, in []*schema.Messa
 
 	ctx = callbacks.EnsureRunInfo(ctx, getType(), components.ComponentOfChatModel)
 
+	in, restorer, err := pii.Apply(ctx, cm.interceptor, in)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			outStream = restorer.RestoreStream(outStream)
+		}
+	}()
+
 	options := fmodel.GetCommonOptions(&fmodel.Options{
 		Temperature: cm.temperature,
 		MaxTokens:   cm.maxTokens,
@@ -187,6 +229,7 @@ func (cm *completionAPIChatModel) Stream(ctx context.Context, in []*schema.Messa
 	if err != nil {
 		return nil, err
 	}
+	droppedFields := dropUnsupportedChatFields(cm.unsupportedFields, req, arkOpts)
 
 	req.Stream = ptrOf(true)
 	req.StreamOptions = &model.StreamOptions{IncludeUsage: true}
@@ -204,30 +247,40 @@ func (cm *completionAPIChatModel) Stream(ctx context.Context, in []*schema.Messa
 		tools = options.Tools
 	}
 
-	ctx = callbacks.OnStart(ctx, &fmodel.CallbackInput{
-		Messages:   in,
-		Tools:      tools,
-		ToolChoice: options.ToolChoice,
-		Config:     reqConf,
-		Extra:      map[string]any{callbackExtraKeyThinking: arkOpts.thinking},
-	})
+	if !cm.disableCallbacks {
+		ctx = callbacks.OnStart(ctx, &fmodel.CallbackInput{
+			Messages:   in,
+			Tools:      tools,
+			ToolChoice: options.ToolChoice,
+			Config:     reqConf,
+			Extra: droppedFieldsExtra(map[string]any{
+				callbackExtraKeyThinking:       arkOpts.thinking,
+				callbackExtraKeyThinkingConfig: thinkingExtra(arkOpts.thinking),
+			}, droppedFields),
+		})
+	}
 	defer func() {
-		if err != nil {
+		if err != nil && !cm.disableCallbacks {
 			callbacks.OnError(ctx, err)
 		}
 	}()
 
+	if cm.batchChat != nil && cm.batchChat.EnableBatchChat &&
+		(arkOpts.cache == nil || arkOpts.cache.ContextID == nil) {
+		return nil, fmt.Errorf("batch chat not support stream")
+	}
+
+	streamCtx, watcher, stopWatcher := stall.NewWatcher(ctx, "ark", cm.stallTimeout)
 	var stream *autils.ChatCompletionStreamReader
 	if arkOpts.cache != nil && arkOpts.cache.ContextID != nil {
-		stream, err = cm.client.CreateContextChatCompletionStream(ctx, *cm.convCompletionRequest(req, *arkOpts.cache.ContextID),
+		stream, err = cm.client.CreateContextChatCompletionStream(streamCtx, *cm.convCompletionRequest(req, *arkOpts.cache.ContextID),
 			arkruntime.WithCustomHeaders(arkOpts.customHeaders))
-	} else if cm.batchChat != nil && cm.batchChat.EnableBatchChat {
-		return nil, fmt.Errorf("batch chat not support stream")
 	} else {
-		stream, err = cm.client.CreateChatCompletionStream(ctx, *req, arkruntime.WithCustomHeaders(arkOpts.customHeaders))
+		stream, err = cm.client.CreateChatCompletionStream(streamCtx, *req, arkruntime.WithCustomHeaders(arkOpts.customHeaders))
 	}
 	if err != nil {
-		return nil, err
+		stopWatcher()
+		return nil, watcher.WrapErr(err)
 	}
 
 	sr, sw := schema.Pipe[*fmodel.CallbackOutput](1)
@@ -240,17 +293,19 @@ func (cm *completionAPIChatModel) Stream(ctx context.Context, in []*schema.Messa
 
 			sw.Close()
 			_ = cm.closeArkStreamReader(stream)
-
+			stopWatcher()
 		}()
 
 		for {
+			watcher.Arm()
 			resp, err := stream.Recv()
+			watcher.Disarm()
 			if errors.Is(err, io.EOF) {
 				return
 			}
 
 			if err != nil {
-				_ = sw.Send(nil, err)
+				_ = sw.Send(nil, watcher.WrapErr(err))
 				return
 			}
 
@@ -263,15 +318,19 @@ func (cm *completionAPIChatModel) Stream(ctx context.Context, in []*schema.Messa
 			if !msgFound {
 				continue
 			}
+			if arkOpts.cache != nil && arkOpts.cache.ContextID != nil {
+				setContextID(msg, *arkOpts.cache.ContextID)
+			}
 
 			closed := sw.Send(&fmodel.CallbackOutput{
 				Message:    msg,
 				Config:     reqConf,
 				TokenUsage: cm.toModelCallbackUsage(msg.ResponseMeta),
-				Extra: map[string]any{
-					callbackExtraKeyThinking: arkOpts.thinking,
-					callbackExtraModelName:   resp.Model,
-				},
+				Extra: droppedFieldsExtra(map[string]any{
+					callbackExtraKeyThinking:       arkOpts.thinking,
+					callbackExtraKeyThinkingConfig: thinkingExtra(arkOpts.thinking),
+					callbackExtraModelName:         resp.Model,
+				}, droppedFields),
 			}, nil)
 			if closed {
 				return
@@ -279,10 +338,13 @@ func (cm *completionAPIChatModel) Stream(ctx context.Context, in []*schema.Messa
 		}
 	}()
 
-	ctx, nsr := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderWithConvert(sr,
+	nsr := schema.StreamReaderWithConvert(sr,
 		func(src *fmodel.CallbackOutput) (callbacks.CallbackOutput, error) {
 			return src, nil
-		}))
+		})
+	if !cm.disableCallbacks {
+		_, nsr = callbacks.OnEndWithStreamOutput(ctx, nsr)
+	}
 
 	outStream = schema.StreamReaderWithConvert(nsr,
 		func(src callbacks.CallbackOutput) (*schema.Message, error) {
@@ -311,6 +373,9 @@ func populateChatMsgReasoningContent(in *schema.Message, msg *model.ChatCompleti
 }
 
 func (cm *completionAPIChatModel) genRequest(in []*schema.Message, options *fmodel.Options, arkOpts *arkOptions) (req *model.CreateChatCompletionRequest, err error) {
+	if err = cm.mediaLimits.ValidateMessages(in); err != nil {
+		return nil, err
+	}
 
 	req = &model.CreateChatCompletionRequest{
 		MaxTokens:           options.MaxTokens,
@@ -606,7 +671,7 @@ func (cm *completionAPIChatModel) toArkContent(msg *schema.Message) (*model.Chat
 					if part.Image.MIMEType == "" {
 						return nil, fmt.Errorf("image part must have MIMEType when using Base64Data")
 					}
-					imageURL, err = ensureDataURL(*part.Image.Base64Data, part.Image.MIMEType)
+					imageURL, err = multimodal.DataURL(*part.Image.Base64Data, part.Image.MIMEType)
 					if err != nil {
 						return nil, err
 					}
@@ -631,13 +696,16 @@ func (cm *completionAPIChatModel) toArkContent(msg *schema.Message) (*model.Chat
 					if part.Video.MIMEType == "" {
 						return nil, fmt.Errorf("video part must have MIMEType when using Base64Data")
 					}
-					videoURL, err = ensureDataURL(*part.Video.Base64Data, part.Video.MIMEType)
+					videoURL, err = multimodal.DataURL(*part.Video.Base64Data, part.Video.MIMEType)
 					if err != nil {
 						return nil, err
 					}
 				} else {
 					return nil, fmt.Errorf("video part for user input must contain either a URL or Base64Data, but got: %+v", part.Video)
 				}
+				if err = checkVideoMetadata(part.Video.Extra); err != nil {
+					return nil, err
+				}
 				parts = append(parts, &model.ChatCompletionMessageContentPart{
 					Type: model.ChatCompletionMessageContentPartTypeVideoURL,
 					VideoURL: &model.ChatMessageVideoURL{
@@ -673,7 +741,7 @@ func (cm *completionAPIChatModel) toArkContent(msg *schema.Message) (*model.Chat
 					if part.Image.MIMEType == "" {
 						return nil, fmt.Errorf("image part must have MIMEType when using Base64Data")
 					}
-					imageURL, err = ensureDataURL(*part.Image.Base64Data, part.Image.MIMEType)
+					imageURL, err = multimodal.DataURL(*part.Image.Base64Data, part.Image.MIMEType)
 					if err != nil {
 						return nil, err
 					}
@@ -697,7 +765,7 @@ func (cm *completionAPIChatModel) toArkContent(msg *schema.Message) (*model.Chat
 					if part.Video.MIMEType == "" {
 						return nil, fmt.Errorf("video part must have MIMEType when using Base64Data")
 					}
-					videoURL, err = ensureDataURL(*part.Video.Base64Data, part.Video.MIMEType)
+					videoURL, err = multimodal.DataURL(*part.Video.Base64Data, part.Video.MIMEType)
 					if err != nil {
 						return nil, err
 					}
@@ -740,6 +808,9 @@ func (cm *completionAPIChatModel) toArkContent(msg *schema.Message) (*model.Chat
 				if part.VideoURL == nil {
 					return nil, fmt.Errorf("VideoURL field must not be nil when Type is ChatMessagePartTypeVideoURL")
 				}
+				if err := checkVideoMetadata(part.VideoURL.Extra); err != nil {
+					return nil, err
+				}
 				parts = append(parts, &model.ChatCompletionMessageContentPart{
 					Type: model.ChatCompletionMessageContentPartTypeVideoURL,
 					VideoURL: &model.ChatMessageVideoURL{
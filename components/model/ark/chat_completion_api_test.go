@@ -269,6 +269,26 @@ func TestChatCompletionAPIGenerate(t *testing.T) {
 			convey.So(len(outMsg.ToolCalls), convey.ShouldEqual, 1)
 		})
 
+		PatchConvey("test context cache success", func() {
+			Mock(GetMethod(cli, "CreateContextChatCompletion")).Return(
+				model.ChatCompletionResponse{
+					Choices: []*model.ChatCompletionChoice{
+						{
+							Message: model.ChatCompletionMessage{
+								Content: &model.ChatCompletionMessageContent{StringValue: ptrOf("test_content")},
+								Role:    model.ChatMessageRoleAssistant,
+							},
+						},
+					},
+				}, nil).Build()
+
+			outMsg, err := m.Generate(ctx, msgs, WithCache(&CacheOption{ContextID: ptrOf("ctx-123")}))
+			convey.So(err, convey.ShouldBeNil)
+			contextID, ok := GetContextID(outMsg)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(contextID, convey.ShouldEqual, "ctx-123")
+		})
+
 		PatchConvey("test use batch success", func() {
 			Mock(GetMethod(cli, "CreateBatchChatCompletion")).Return(
 				model.ChatCompletionResponse{
@@ -29,6 +29,9 @@ import (
 
 	fmodel "github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/libs/multimodal"
+	"github.com/cloudwego/eino-ext/libs/pii"
 )
 
 var _ fmodel.ToolCallingChatModel = (*ChatModel)(nil)
@@ -40,6 +43,8 @@ var (
 	defaultRetryTimes       = 2
 	defaultTimeout          = 10 * time.Minute
 	defaultBatchMaxParallel = 3000
+	// defaultContextTTL is the TTL in seconds of the ContextAPI caches created without a ttl.
+	defaultContextTTL = 86400
 )
 
 var (
@@ -155,6 +160,32 @@ type ChatModelConfig struct {
 	BatchChat *BatchChatConfig `json:"batch_chat,omitempty"`
 
 	Cache *CacheConfig `json:"cache,omitempty"`
+
+	// StallTimeout specifies the maximum duration to wait for the next chunk in Stream.
+	// If no chunk arrives within the window, the upstream request is canceled
+	// and a *stall.Error is sent on the StreamReader.
+	// Optional. Default: no stall detection
+	StallTimeout time.Duration `json:"stall_timeout,omitempty"`
+
+	// MediaLimits, if set, fails the input messages violating it before the request is sent, see multimodal.Limits.
+	// Optional. Default: no limits
+	MediaLimits *multimodal.Limits `json:"media_limits,omitempty"`
+
+	// UnsupportedFields maps the models, i.e. the model names or the endpoint IDs set by Model or model.WithModel,
+	// to the optional request fields they do not support, see ResponsesAPIConfig.UnsupportedFields.
+	// Optional. Default: all the fields are sent
+	UnsupportedFields map[string][]RequestField `json:"unsupported_fields,omitempty"`
+
+	// Interceptor processes the input messages before they are sent and the output messages before they are returned,
+	// e.g. a *pii.Redactor replacing the personally identifiable information with placeholders restored in the response.
+	// The callbacks see the intercepted input and output messages.
+	// Optional. Default: none
+	Interceptor pii.Interceptor `json:"-"`
+
+	// EnableCallbacks makes the model report OnStart, OnEnd and OnError with the Ark specific extras.
+	// When false, IsCallbacksEnabled returns false and a graph running the model reports the generic callbacks instead.
+	// Optional. Default: true
+	EnableCallbacks *bool `json:"enable_callbacks,omitempty"`
 }
 
 type BatchChatConfig struct {
@@ -279,6 +310,11 @@ func buildChatCompletionAPIChatModel(config *ChatModelConfig) (*completionAPICha
 		serviceTier:         config.ServiceTier,
 		reasoningEffort:     config.ReasoningEffort,
 		batchChat:           config.BatchChat,
+		stallTimeout:        config.StallTimeout,
+		mediaLimits:         config.MediaLimits,
+		unsupportedFields:   config.UnsupportedFields,
+		interceptor:         config.Interceptor,
+		disableCallbacks:    !callbacksEnabled(config.EnableCallbacks),
 	}
 
 	return cm, nil
@@ -298,14 +334,8 @@ func buildResponsesAPIChatModel(config *ChatModelConfig) (*ResponsesAPIChatModel
 		opts = append(opts, arkruntime.WithRegion(config.Region))
 	}
 
-	if config.Timeout != nil {
-		opts = append(opts, arkruntime.WithTimeout(*config.Timeout))
-	} else {
-		opts = append(opts, arkruntime.WithTimeout(defaultTimeout))
-	}
-	if config.HTTPClient != nil {
-		opts = append(opts, arkruntime.WithHTTPClient(config.HTTPClient))
-	}
+	// the request fields not supported by the ark sdk, e.g. the reasoning summary, are merged into the body by the transport
+	opts = append(opts, arkruntime.WithHTTPClient(newExtraBodyHTTPClient(config.HTTPClient, config.Timeout)))
 	if config.BaseURL != "" {
 		opts = append(opts, arkruntime.WithBaseUrl(config.BaseURL))
 	} else {
@@ -327,16 +357,21 @@ func buildResponsesAPIChatModel(config *ChatModelConfig) (*ResponsesAPIChatModel
 	}
 
 	cm := &ResponsesAPIChatModel{
-		client:         client,
-		model:          config.Model,
-		maxTokens:      config.MaxTokens,
-		temperature:    config.Temperature,
-		topP:           config.TopP,
-		customHeader:   config.CustomHeader,
-		responseFormat: config.ResponseFormat,
-		thinking:       config.Thinking,
-		cache:          config.Cache,
-		serviceTier:    config.ServiceTier,
+		client:            client,
+		model:             config.Model,
+		maxTokens:         config.MaxTokens,
+		temperature:       config.Temperature,
+		topP:              config.TopP,
+		customHeader:      config.CustomHeader,
+		responseFormat:    config.ResponseFormat,
+		thinking:          config.Thinking,
+		cache:             config.Cache,
+		serviceTier:       config.ServiceTier,
+		stallTimeout:      config.StallTimeout,
+		mediaLimits:       config.MediaLimits,
+		unsupportedFields: config.UnsupportedFields,
+		interceptor:       config.Interceptor,
+		disableCallbacks:  !callbacksEnabled(config.EnableCallbacks),
 	}
 	return cm, nil
 }
@@ -383,6 +418,8 @@ type CacheInfo struct {
 	ResponseID string
 	// Usage specifies the token usage of prefix
 	Usage schema.TokenUsage
+	// ExpireAt is the unix timestamp in seconds when the cache expires, 0 if unknown.
+	ExpireAt int64
 }
 
 func (cm *ChatModel) Generate(ctx context.Context, in []*schema.Message, opts ...fmodel.Option) (
@@ -517,7 +554,7 @@ func (cm *ChatModel) GetType() string {
 }
 
 func (cm *ChatModel) IsCallbacksEnabled() bool {
-	return true
+	return !cm.chatModel.disableCallbacks
 }
 
 // CreatePrefixCache creates a prefix context on the server side.
@@ -529,9 +566,10 @@ func (cm *ChatModel) IsCallbacksEnabled() bool {
 //   - ctx: The context for the request
 //   - prefix: Initial messages to be cached as prefix context
 //   - ttl: Time-to-live in seconds for the cached prefix, default: 86400
+//   - opts: Options of the request, fmodel.WithModel and WithCustomHeader are applied by ContextAPI
 //
 // Returns:
-//   - info: Information about the created prefix cache, including the context ID and token usage
+//   - info: Information about the created prefix cache, including the context ID, token usage and expiration time
 //   - err: Any error encountered during the operation
 //
 // ref: https://www.volcengine.com/docs/82379/1396490#_1-%E5%88%9B%E5%BB%BA%E5%89%8D%E7%BC%80%E7%BC%93%E5%AD%98
@@ -542,7 +580,7 @@ func (cm *ChatModel) CreatePrefixCache(ctx context.Context, prefix []*schema.Mes
 	if cm.respChatModel.cache != nil && ptrFromOrZero(cm.respChatModel.cache.APIType) == ResponsesAPI {
 		return cm.respChatModel.CreatePrefixCache(ctx, prefix, ttl, opts...)
 	}
-	return cm.createContextByContextAPI(ctx, prefix, ttl, model.ContextModeCommonPrefix, nil)
+	return cm.createContextByContextAPI(ctx, prefix, ttl, model.ContextModeCommonPrefix, nil, opts...)
 }
 
 // CreateSessionCache creates an initial session context on the server side.
@@ -576,10 +614,17 @@ func (cm *ChatModel) CreateSessionCache(ctx context.Context, prefix []*schema.Me
 }
 
 func (cm *ChatModel) createContextByContextAPI(ctx context.Context, prefix []*schema.Message, ttl int, mode model.ContextMode,
-	truncation *model.TruncationStrategy) (info *CacheInfo, err error) {
+	truncation *model.TruncationStrategy, opts ...fmodel.Option) (info *CacheInfo, err error) {
+
+	options := fmodel.GetCommonOptions(&fmodel.Options{
+		Model: &cm.chatModel.model,
+	}, opts...)
+	specOptions := fmodel.GetImplSpecificOptions(&arkOptions{
+		customHeaders: cm.chatModel.customHeader,
+	}, opts...)
 
 	req := model.CreateContextRequest{
-		Model:              cm.chatModel.model,
+		Model:              dereferenceOrZero(options.Model),
 		Mode:               mode,
 		Messages:           make([]*model.ChatCompletionMessage, 0, len(prefix)),
 		TTL:                nil,
@@ -602,11 +647,18 @@ func (cm *ChatModel) createContextByContextAPI(ctx context.Context, prefix []*sc
 		req.TTL = &ttl
 	}
 
-	resp, err := cm.chatModel.client.CreateContext(ctx, req)
+	resp, err := cm.chatModel.client.CreateContext(ctx, req, arkruntime.WithCustomHeaders(specOptions.customHeaders))
 	if err != nil {
 		return nil, fmt.Errorf("CreateContext fail: %w", err)
 	}
 
+	expireTTL := defaultContextTTL
+	if resp.TTL != nil {
+		expireTTL = *resp.TTL
+	} else if ttl > 0 {
+		expireTTL = ttl
+	}
+
 	return &CacheInfo{
 		ContextID: resp.ID,
 		Usage: schema.TokenUsage{
@@ -617,5 +669,6 @@ func (cm *ChatModel) createContextByContextAPI(ctx context.Context, prefix []*sc
 			CompletionTokens: resp.Usage.CompletionTokens,
 			TotalTokens:      resp.Usage.TotalTokens,
 		},
+		ExpireAt: time.Now().Unix() + int64(expireTTL),
 	}, nil
 }
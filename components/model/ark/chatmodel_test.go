@@ -19,9 +19,12 @@ package ark
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/bytedance/mockey"
 	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 
 	"github.com/cloudwego/eino/schema"
 )
@@ -191,6 +194,42 @@ func TestCallByResponsesAPI(t *testing.T) {
 	})
 }
 
+func TestChatModel_CreatePrefixCache(t *testing.T) {
+	mockey.PatchConvey("context api", t, func() {
+		ctx := context.Background()
+		cm, err := NewChatModel(ctx, &ChatModelConfig{Model: "test model", APIKey: "test"})
+		assert.NoError(t, err)
+		prefix := []*schema.Message{schema.SystemMessage("you are a helpful assistant")}
+
+		mockey.PatchConvey("ttl returned by server", func() {
+			mockey.Mock((*arkruntime.Client).CreateContext).Return(model.CreateContextResponse{
+				ID:    "ctx-1",
+				TTL:   ptrOf(60),
+				Usage: model.Usage{PromptTokens: 10, TotalTokens: 10},
+			}, nil).Build()
+
+			now := time.Now().Unix()
+			info, err := cm.CreatePrefixCache(ctx, prefix, 3600)
+			assert.NoError(t, err)
+			assert.Equal(t, "ctx-1", info.ContextID)
+			assert.Equal(t, 10, info.Usage.PromptTokens)
+			assert.GreaterOrEqual(t, info.ExpireAt, now+60)
+			assert.LessOrEqual(t, info.ExpireAt, time.Now().Unix()+60)
+		})
+
+		mockey.PatchConvey("default ttl", func() {
+			mockey.Mock((*arkruntime.Client).CreateContext).Return(model.CreateContextResponse{ID: "ctx-2"}, nil).Build()
+
+			now := time.Now().Unix()
+			info, err := cm.CreatePrefixCache(ctx, prefix, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "ctx-2", info.ContextID)
+			assert.GreaterOrEqual(t, info.ExpireAt, now+int64(defaultContextTTL))
+			assert.LessOrEqual(t, info.ExpireAt, time.Now().Unix()+int64(defaultContextTTL))
+		})
+	})
+}
+
 func TestBuildResponsesAPIChatModel(t *testing.T) {
 	mockey.PatchConvey("invalid config", t, func() {
 		_, err := buildResponsesAPIChatModel(&ChatModelConfig{
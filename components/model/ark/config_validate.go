@@ -0,0 +1,127 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+)
+
+// maxSessionCacheTTL is the max TTL in seconds of the session cache, 3 days.
+const maxSessionCacheTTL = 3 * 86400
+
+// Validate checks the config without creating a client, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid.
+func (c *ChatModelConfig) Validate() error {
+	r := configcheck.New("ark.ChatModelConfig")
+	checkCredentials(r, c.APIKey, c.AccessKey, c.SecretKey)
+	configcheck.Positive(r, "Timeout", c.Timeout)
+	configcheck.NonNegative(r, "RetryTimes", c.RetryTimes)
+	configcheck.Positive(r, "MaxTokens", c.MaxTokens)
+	configcheck.Positive(r, "MaxCompletionTokens", c.MaxCompletionTokens)
+	r.Checkf(c.MaxTokens == nil || c.MaxCompletionTokens == nil, "MaxCompletionTokens",
+		"cannot be set together with MaxTokens")
+	configcheck.InRange(r, "Temperature", c.Temperature, 0, 2)
+	configcheck.InRange(r, "TopP", c.TopP, 0, 1)
+	configcheck.InRange(r, "FrequencyPenalty", c.FrequencyPenalty, -2, 2)
+	configcheck.InRange(r, "PresencePenalty", c.PresencePenalty, -2, 2)
+	configcheck.InRange(r, "TopLogProbs", &c.TopLogProbs, 0, 20)
+	r.Checkf(c.TopLogProbs == 0 || c.LogProbs, "TopLogProbs", "requires LogProbs")
+	configcheck.NonNegative(r, "StallTimeout", &c.StallTimeout)
+	if c.ResponseFormat != nil && c.ResponseFormat.Type == arkModel.ResponseFormatJSONSchema && c.ResponseFormat.JSONSchema == nil {
+		r.Addf("ResponseFormat.JSONSchema", "is required when ResponseFormat.Type is %q", c.ResponseFormat.Type)
+	}
+	if c.BatchChat != nil && c.BatchChat.EnableBatchChat {
+		r.Checkf(c.BatchChat.BatchChatAsyncRetryTimeout > 0, "BatchChat.BatchChatAsyncRetryTimeout",
+			"must be set when EnableBatchChat is true")
+		configcheck.Positive(r, "BatchChat.BatchMaxParallel", c.BatchChat.BatchMaxParallel)
+	}
+	if c.Cache != nil {
+		checkSessionCache(r, "Cache.SessionCache", c.Cache.SessionCache)
+	}
+	return r.Err()
+}
+
+// Validate checks the config without creating a client, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid.
+func (c *ResponsesAPIConfig) Validate() error {
+	r := configcheck.New("ark.ResponsesAPIConfig")
+	if c.Client == nil {
+		checkCredentials(r, c.APIKey, c.AccessKey, c.SecretKey)
+	}
+	configcheck.Positive(r, "Timeout", c.Timeout)
+	configcheck.NonNegative(r, "RetryTimes", c.RetryTimes)
+	configcheck.Positive(r, "MaxOutputTokens", c.MaxOutputTokens)
+	configcheck.InRange(r, "Temperature", c.Temperature, 0, 2)
+	configcheck.InRange(r, "TopP", c.TopP, 0, 1)
+	if _, err := sanitizeResponseFormat(c.ResponseFormat); err != nil {
+		r.AddError("ResponseFormat", err)
+	}
+	if c.ReasoningSummary != nil {
+		switch *c.ReasoningSummary {
+		case ReasoningSummaryAuto, ReasoningSummaryConcise, ReasoningSummaryDetailed, ReasoningSummaryNone:
+		default:
+			r.Addf("ReasoningSummary", "unsupported value %q", *c.ReasoningSummary)
+		}
+	}
+	if c.TextVerbosity != nil {
+		switch *c.TextVerbosity {
+		case TextVerbosityLow, TextVerbosityMedium, TextVerbosityHigh:
+		default:
+			r.Addf("TextVerbosity", "unsupported value %q", *c.TextVerbosity)
+		}
+	}
+	checkSessionCache(r, "SessionCache", c.SessionCache)
+	if c.StoreResponses != nil && !*c.StoreResponses && c.SessionCache != nil && c.SessionCache.EnableCache {
+		r.AddError("StoreResponses", errStoreDisabledCache)
+	}
+	configcheck.Positive(r, "MaxToolCalls", c.MaxToolCalls)
+	configcheck.NonNegative(r, "StallTimeout", &c.StallTimeout)
+	configcheck.Positive(r, "MaxInputTokens", c.MaxInputTokens)
+	for model, fields := range c.UnsupportedFields {
+		for _, f := range fields {
+			switch f {
+			case RequestFieldThinking, RequestFieldReasoningEffort, RequestFieldReasoningSummary,
+				RequestFieldTextVerbosity, RequestFieldServiceTier:
+			default:
+				r.Addf("UnsupportedFields["+model+"]", "unknown request field %q", f)
+			}
+		}
+	}
+	if c.Failover != nil {
+		configcheck.NonNegative(r, "Failover.FailureThreshold", &c.Failover.FailureThreshold)
+		configcheck.NonNegative(r, "Failover.RecoveryInterval", &c.Failover.RecoveryInterval)
+		configcheck.NonNegative(r, "Failover.ConnectTimeout", &c.Failover.ConnectTimeout)
+	}
+	return r.Err()
+}
+
+func checkCredentials(r *configcheck.Report, apiKey, accessKey, secretKey string) {
+	r.Checkf(apiKey != "" || (accessKey != "" && secretKey != ""), "APIKey",
+		"missing credentials: set 'APIKey' or both 'AccessKey' and 'SecretKey'")
+}
+
+func checkSessionCache(r *configcheck.Report, field string, sc *SessionCacheConfig) {
+	if sc == nil {
+		return
+	}
+	configcheck.InRange(r, field+".TTL", &sc.TTL, 0, maxSessionCacheTTL)
+	if sc.AutoSummary != nil {
+		configcheck.NonNegative(r, field+".AutoSummary.ExpiryThreshold", &sc.AutoSummary.ExpiryThreshold)
+		configcheck.InRange(r, field+".AutoSummary.TTL", &sc.AutoSummary.TTL, 0, maxSessionCacheTTL)
+	}
+}
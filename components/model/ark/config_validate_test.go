@@ -0,0 +1,87 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+	"github.com/stretchr/testify/assert"
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+)
+
+func TestChatModelConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&ChatModelConfig{APIKey: "key", Model: "model"}).Validate())
+	assert.NoError(t, (&ChatModelConfig{AccessKey: "ak", SecretKey: "sk"}).Validate())
+
+	err := (&ChatModelConfig{
+		AccessKey:           "ak",
+		MaxTokens:           ptrOf(100),
+		MaxCompletionTokens: ptrOf(100),
+		Temperature:         ptrOf(float32(3)),
+		TopLogProbs:         5,
+		ResponseFormat:      &ResponseFormat{Type: arkModel.ResponseFormatJSONSchema},
+		BatchChat:           &BatchChatConfig{EnableBatchChat: true},
+	}).Validate()
+	var report *configcheck.Report
+	assert.True(t, errors.As(err, &report))
+	assert.Equal(t, "ark.ChatModelConfig", report.Config)
+	var fields []string
+	for _, issue := range report.Issues {
+		fields = append(fields, issue.Field)
+	}
+	assert.Equal(t, []string{"APIKey", "MaxCompletionTokens", "Temperature", "TopLogProbs",
+		"ResponseFormat.JSONSchema", "BatchChat.BatchChatAsyncRetryTimeout"}, fields)
+}
+
+func TestResponsesAPIConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&ResponsesAPIConfig{APIKey: "key"}).Validate())
+	assert.NoError(t, (&ResponsesAPIConfig{Client: &mutatingResponsesClient{}}).Validate())
+
+	cfg := &ResponsesAPIConfig{
+		APIKey:           "key",
+		Timeout:          ptrOf(time.Duration(0)),
+		TopP:             ptrOf(float32(1.5)),
+		ResponseFormat:   &ResponseFormat{Type: "xml"},
+		ReasoningSummary: ptrOf(ReasoningSummary("brief")),
+		SessionCache:     &SessionCacheConfig{EnableCache: true, TTL: 4 * 86400},
+		StoreResponses:   ptrOf(false),
+		UnsupportedFields: map[string][]RequestField{
+			"model": {RequestFieldThinking, "temperature"},
+		},
+		Failover: &FailoverConfig{FailureThreshold: -1, ConnectTimeout: -time.Second},
+	}
+	err := cfg.Validate()
+	var report *configcheck.Report
+	assert.True(t, errors.As(err, &report))
+	assert.Equal(t, []configcheck.Issue{
+		{Field: "Timeout", Message: "must be positive, got 0s"},
+		{Field: "TopP", Message: "must be in [0, 1], got 1.5"},
+		{Field: "ResponseFormat", Message: `unsupported 'ResponseFormat.Type': "xml"`},
+		{Field: "ReasoningSummary", Message: `unsupported value "brief"`},
+		{Field: "SessionCache.TTL", Message: "must be in [0, 259200], got 345600"},
+		{Field: "StoreResponses", Message: errStoreDisabledCache.Error()},
+		{Field: "UnsupportedFields[model]", Message: `unknown request field "temperature"`},
+		{Field: "Failover.FailureThreshold", Message: "must not be negative, got -1"},
+		{Field: "Failover.ConnectTimeout", Message: "must not be negative, got -1s"},
+	}, report.Issues)
+
+	// validating does not touch the config
+	assert.Equal(t, 4*86400, cfg.SessionCache.TTL)
+}
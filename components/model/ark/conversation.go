@@ -0,0 +1,289 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+// listInputItemsLimit is the max page size of the input items API.
+const listInputItemsLimit = 100
+
+// FetchConversation restores the conversation ending with the response of responseID from the responses stored by Ark,
+// the session history can be reconstructed without local storage.
+// The response chain is followed through the previous response IDs, the input items and the output of every response
+// are converted back into messages, ordered from the oldest to the newest.
+// The restored assistant messages carry the response ID, the response ID chain and the cache expiration,
+// so the conversation can be continued with the session cache like the messages returned by Generate.
+//
+// Only responses created with store enabled (e.g. with the session cache or a prefix cache) can be fetched,
+// and only before they expire. Input items without a message counterpart, e.g. built-in tool calls, are skipped.
+func (cm *ResponsesAPIChatModel) FetchConversation(ctx context.Context, responseID string, opts ...model.Option) ([]*schema.Message, error) {
+	if responseID == "" {
+		return nil, errors.New("response id cannot be empty")
+	}
+
+	storeClient, ok := cm.responsesStoreClient()
+	if !ok {
+		return nil, errors.New("the responses client does not implement ResponsesStoreClient")
+	}
+
+	_, specOptions, err := cm.getOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	headers := buildRequestHeaders(ctx, specOptions.customHeaders, specOptions.requestHeaders)
+
+	// walk the response chain backwards, then restore it from the oldest response
+	var chain []*responses.ResponseObject
+	seen := make(map[string]bool)
+	for id := responseID; id != ""; {
+		if seen[id] {
+			return nil, fmt.Errorf("found circular response chain at response %s", id)
+		}
+		seen[id] = true
+
+		respObject, err := storeClient.GetResponses(ctx, id, headers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get response %s: %w", id, err)
+		}
+		chain = append(chain, respObject)
+		id = ptrFromOrZero(respObject.PreviousResponseId)
+	}
+
+	var (
+		msgs    []*schema.Message
+		idChain []string
+	)
+	for i := len(chain) - 1; i >= 0; i-- {
+		respObject := chain[i]
+
+		items, err := listInputItems(ctx, storeClient, respObject.Id, headers)
+		if err != nil {
+			return nil, err
+		}
+		inputMsgs, err := toInputItemMessages(items)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert input items of response %s: %w", respObject.Id, err)
+		}
+		msgs = append(msgs, inputMsgs...)
+
+		cacheCfg := &cacheConfig{
+			PrevResponseIDChain: idChain,
+		}
+		if respObject.Caching != nil && respObject.Caching.Type != nil {
+			cacheCfg.Enabled = *respObject.Caching.Type == responses.CacheType_enabled
+			cacheCfg.ExpireAt = respObject.ExpireAt
+		}
+		outMsg, err := cm.toOutputMessage(respObject, cacheCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert output of response %s: %w", respObject.Id, err)
+		}
+		msgs = append(msgs, outMsg)
+
+		idChain = append(idChain, respObject.Id)
+	}
+
+	return msgs, nil
+}
+
+// listInputItems returns all the input items of the response in ascending order.
+func listInputItems(ctx context.Context, storeClient ResponsesStoreClient, responseID string, headers map[string]string) ([]*responses.InputItem, error) {
+	var (
+		items []*responses.InputItem
+		after *string
+	)
+	for {
+		resp, err := storeClient.ListResponseInputItems(ctx, &responses.ListInputItemsRequest{
+			ResponseId: responseID,
+			After:      after,
+			Limit:      ptrOf(int32(listInputItemsLimit)),
+			Order:      ptrOf("asc"),
+		}, headers)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list input items of response %s: %w", responseID, err)
+		}
+		items = append(items, resp.Data...)
+
+		if !ptrFromOrZero(resp.HasMore) || resp.LastId == "" || len(resp.Data) == 0 {
+			return items, nil
+		}
+		after = ptrOf(resp.LastId)
+	}
+}
+
+// toInputItemMessages converts the input items back into messages,
+// consecutive function calls are merged into one assistant message as they are split by populateInput.
+func toInputItemMessages(items []*responses.InputItem) ([]*schema.Message, error) {
+	msgs := make([]*schema.Message, 0, len(items))
+	for _, item := range items {
+		switch asItem := item.GetUnion().(type) {
+		case *responses.InputItem_InputMessage:
+			if asItem.InputMessage == nil {
+				continue
+			}
+			msg, err := toInputItemMessage(asItem.InputMessage.Role, asItem.InputMessage.Content)
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, msg)
+
+		case *responses.InputItem_EasyMessage:
+			if asItem.EasyMessage == nil {
+				continue
+			}
+			content := asItem.EasyMessage.GetContent()
+			var contentItems []*responses.ContentItem
+			if content.GetListValue() != nil {
+				contentItems = content.GetListValue().GetListValue()
+			} else {
+				contentItems = []*responses.ContentItem{{Union: &responses.ContentItem_Text{
+					Text: &responses.ContentItemText{Type: responses.ContentItemType_input_text, Text: content.GetStringValue()},
+				}}}
+			}
+			msg, err := toInputItemMessage(asItem.EasyMessage.Role, contentItems)
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, msg)
+
+		case *responses.InputItem_OutputMessage:
+			if asItem.OutputMessage == nil {
+				continue
+			}
+			var texts []string
+			for _, content := range asItem.OutputMessage.Content {
+				if content.GetText() != nil {
+					texts = append(texts, content.GetText().GetText())
+				}
+			}
+			msgs = append(msgs, schema.AssistantMessage(strings.Join(texts, ""), nil))
+
+		case *responses.InputItem_FunctionToolCall:
+			if asItem.FunctionToolCall == nil {
+				continue
+			}
+			toolCall := schema.ToolCall{
+				ID:   asItem.FunctionToolCall.CallId,
+				Type: asItem.FunctionToolCall.Type.String(),
+				Function: schema.FunctionCall{
+					Name:      asItem.FunctionToolCall.Name,
+					Arguments: asItem.FunctionToolCall.Arguments,
+				},
+			}
+			// the function calls follow the content of the assistant message they belong to
+			if n := len(msgs); n > 0 && msgs[n-1].Role == schema.Assistant {
+				msgs[n-1].ToolCalls = append(msgs[n-1].ToolCalls, toolCall)
+				continue
+			}
+			msgs = append(msgs, schema.AssistantMessage("", []schema.ToolCall{toolCall}))
+
+		case *responses.InputItem_FunctionToolCallOutput:
+			if asItem.FunctionToolCallOutput == nil {
+				continue
+			}
+			msgs = append(msgs, schema.ToolMessage(asItem.FunctionToolCallOutput.Output, asItem.FunctionToolCallOutput.CallId))
+		}
+	}
+	return msgs, nil
+}
+
+func toInputItemMessage(role responses.MessageRole_Enum, contentItems []*responses.ContentItem) (*schema.Message, error) {
+	msg := &schema.Message{}
+	switch role {
+	case responses.MessageRole_user:
+		msg.Role = schema.User
+	case responses.MessageRole_system, responses.MessageRole_developer:
+		msg.Role = schema.System
+	case responses.MessageRole_assistant:
+		msg.Role = schema.Assistant
+	default:
+		return nil, fmt.Errorf("unknown message role: %s", role.String())
+	}
+
+	var (
+		texts    []string
+		parts    []schema.MessageInputPart
+		hasMedia bool
+	)
+	for _, contentItem := range contentItems {
+		switch asContent := contentItem.GetUnion().(type) {
+		case *responses.ContentItem_Text:
+			texts = append(texts, asContent.Text.GetText())
+			parts = append(parts, schema.MessageInputPart{
+				Type: schema.ChatMessagePartTypeText,
+				Text: asContent.Text.GetText(),
+			})
+		case *responses.ContentItem_Image:
+			// the getters are nil-safe, items without a URL are skipped
+			imageURL := asContent.Image.GetImageUrl()
+			if imageURL == "" {
+				continue
+			}
+			hasMedia = true
+			parts = append(parts, schema.MessageInputPart{
+				Type: schema.ChatMessagePartTypeImageURL,
+				Image: &schema.MessageInputImage{
+					MessagePartCommon: schema.MessagePartCommon{URL: ptrOf(imageURL)},
+				},
+			})
+		case *responses.ContentItem_Video:
+			videoURL := asContent.Video.GetVideoUrl()
+			if videoURL == "" {
+				continue
+			}
+			hasMedia = true
+			parts = append(parts, schema.MessageInputPart{
+				Type: schema.ChatMessagePartTypeVideoURL,
+				Video: &schema.MessageInputVideo{
+					MessagePartCommon: schema.MessagePartCommon{URL: ptrOf(videoURL)},
+				},
+			})
+		case *responses.ContentItem_File:
+			fileURL := asContent.File.GetFileUrl()
+			if fileURL == "" {
+				fileURL = asContent.File.GetFileData()
+			}
+			if fileURL == "" {
+				continue
+			}
+			hasMedia = true
+			parts = append(parts, schema.MessageInputPart{
+				Type: schema.ChatMessagePartTypeFileURL,
+				File: &schema.MessageInputFile{
+					MessagePartCommon: schema.MessagePartCommon{URL: ptrOf(fileURL)},
+					Name:              asContent.File.GetFilename(),
+				},
+			})
+		}
+	}
+
+	// only user messages can carry hasMedia input, the others keep the text content
+	if hasMedia && msg.Role == schema.User {
+		msg.UserInputMultiContent = parts
+		return msg, nil
+	}
+	msg.Content = strings.Join(texts, "")
+	return msg, nil
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+func inputTextMessage(role responses.MessageRole_Enum, text string) *responses.InputItem {
+	return &responses.InputItem{Union: &responses.InputItem_InputMessage{InputMessage: &responses.ItemInputMessage{
+		Role: role,
+		Content: []*responses.ContentItem{{Union: &responses.ContentItem_Text{
+			Text: &responses.ContentItemText{Type: responses.ContentItemType_input_text, Text: text},
+		}}},
+	}}}
+}
+
+func TestResponsesAPIChatModel_FetchConversation(t *testing.T) {
+	PatchConvey("test FetchConversation", t, func() {
+		ctx := context.Background()
+		cm := &ResponsesAPIChatModel{}
+
+		PatchConvey("restore the response chain", func() {
+			expireAt := time.Now().Unix() + 3600
+			Mock((*arkruntime.Client).GetResponses).Return(Sequence(&responses.ResponseObject{
+				Id:                 "resp-2",
+				PreviousResponseId: ptrOf("resp-1"),
+				Status:             responses.ResponseStatus_completed,
+				Caching:            &responses.ResponsesCaching{Type: responses.CacheType_enabled.Enum()},
+				ExpireAt:           &expireAt,
+				Output: []*responses.OutputItem{{Union: &responses.OutputItem_OutputMessage{OutputMessage: &responses.ItemOutputMessage{
+					Content: []*responses.OutputContentItem{{Union: &responses.OutputContentItem_Text{
+						Text: &responses.OutputContentItemText{Text: "sunny"},
+					}}},
+				}}}},
+			}, nil).Then(&responses.ResponseObject{
+				Id:     "resp-1",
+				Status: responses.ResponseStatus_completed,
+				Output: []*responses.OutputItem{{Union: &responses.OutputItem_FunctionToolCall{FunctionToolCall: &responses.ItemFunctionToolCall{
+					Type:      responses.ItemType_function_call,
+					CallId:    "call-1",
+					Name:      "get_weather",
+					Arguments: `{"city":"Beijing"}`,
+				}}}},
+			}, nil)).Build()
+
+			imageURL := "https://example.com/a.png"
+			listMocker := Mock((*arkruntime.Client).ListResponseInputItems).Return(Sequence(&responses.ListInputItemsResponse{
+				Data:    []*responses.InputItem{inputTextMessage(responses.MessageRole_system, "you are a helper")},
+				LastId:  "item-1",
+				HasMore: ptrOf(true),
+			}, nil).Then(&responses.ListInputItemsResponse{
+				Data: []*responses.InputItem{{Union: &responses.InputItem_InputMessage{InputMessage: &responses.ItemInputMessage{
+					Role: responses.MessageRole_user,
+					Content: []*responses.ContentItem{
+						{Union: &responses.ContentItem_Text{Text: &responses.ContentItemText{Text: "what is the weather here?"}}},
+						{Union: &responses.ContentItem_Image{Image: &responses.ContentItemImage{ImageUrl: &imageURL}}},
+						// items without a URL are skipped
+						{Union: &responses.ContentItem_Image{}},
+						{Union: &responses.ContentItem_File{File: &responses.ContentItemFile{}}},
+					},
+				}}}},
+				LastId:  "item-2",
+				HasMore: ptrOf(false),
+			}, nil).Then(&responses.ListInputItemsResponse{
+				Data: []*responses.InputItem{{Union: &responses.InputItem_FunctionToolCallOutput{FunctionToolCallOutput: &responses.ItemFunctionToolCallOutput{
+					CallId: "call-1",
+					Output: "sunny",
+				}}}},
+			}, nil)).Build()
+
+			msgs, err := cm.FetchConversation(ctx, "resp-2")
+			assert.NoError(t, err)
+			assert.Equal(t, 3, listMocker.Times())
+			assert.Len(t, msgs, 5)
+
+			assert.Equal(t, schema.System, msgs[0].Role)
+			assert.Equal(t, "you are a helper", msgs[0].Content)
+
+			assert.Equal(t, schema.User, msgs[1].Role)
+			assert.Len(t, msgs[1].UserInputMultiContent, 2)
+			assert.Equal(t, "what is the weather here?", msgs[1].UserInputMultiContent[0].Text)
+			assert.Equal(t, imageURL, *msgs[1].UserInputMultiContent[1].Image.URL)
+
+			assert.Equal(t, schema.Assistant, msgs[2].Role)
+			assert.Len(t, msgs[2].ToolCalls, 1)
+			assert.Equal(t, "get_weather", msgs[2].ToolCalls[0].Function.Name)
+			assert.Equal(t, []string{"resp-1"}, GetResponseIDChain(msgs[2]))
+
+			assert.Equal(t, schema.Tool, msgs[3].Role)
+			assert.Equal(t, "call-1", msgs[3].ToolCallID)
+
+			assert.Equal(t, "sunny", msgs[4].Content)
+			respID, ok := GetResponseID(msgs[4])
+			assert.True(t, ok)
+			assert.Equal(t, "resp-2", respID)
+			assert.Equal(t, []string{"resp-1", "resp-2"}, GetResponseIDChain(msgs[4]))
+			exp, ok := GetCacheExpiration(msgs[4])
+			assert.True(t, ok)
+			assert.Equal(t, expireAt, exp)
+		})
+
+		PatchConvey("empty response id", func() {
+			_, err := cm.FetchConversation(ctx, "")
+			assert.Error(t, err)
+		})
+
+		PatchConvey("circular response chain", func() {
+			Mock((*arkruntime.Client).GetResponses).Return(&responses.ResponseObject{
+				Id:                 "resp-1",
+				PreviousResponseId: ptrOf("resp-1"),
+			}, nil).Build()
+
+			_, err := cm.FetchConversation(ctx, "resp-1")
+			assert.ErrorContains(t, err, "circular response chain")
+		})
+
+		PatchConvey("get response error", func() {
+			Mock((*arkruntime.Client).GetResponses).Return(nil, errors.New("not found")).Build()
+
+			_, err := cm.FetchConversation(ctx, "resp-1")
+			assert.ErrorContains(t, err, "not found")
+		})
+	})
+}
+
+func TestToInputItemMessages(t *testing.T) {
+	PatchConvey("test toInputItemMessages", t, func() {
+		msgs, err := toInputItemMessages([]*responses.InputItem{
+			inputTextMessage(responses.MessageRole_assistant, "let me check"),
+			{Union: &responses.InputItem_FunctionToolCall{FunctionToolCall: &responses.ItemFunctionToolCall{CallId: "call-1", Name: "a"}}},
+			{Union: &responses.InputItem_FunctionToolCall{FunctionToolCall: &responses.ItemFunctionToolCall{CallId: "call-2", Name: "b"}}},
+			{Union: &responses.InputItem_EasyMessage{EasyMessage: &responses.ItemEasyMessage{
+				Role:    responses.MessageRole_developer,
+				Content: &responses.MessageContent{Union: &responses.MessageContent_StringValue{StringValue: "be brief"}},
+			}}},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, msgs, 2)
+		assert.Equal(t, "let me check", msgs[0].Content)
+		assert.Len(t, msgs[0].ToolCalls, 2)
+		assert.Equal(t, schema.System, msgs[1].Role)
+		assert.Equal(t, "be brief", msgs[1].Content)
+
+		_, err = toInputItemMessages([]*responses.InputItem{inputTextMessage(responses.MessageRole_unspecified, "x")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+// FinishReasonContentFilter is the FinishReason set on the output message when Ark
+// stops generating because the content was flagged by content moderation.
+// Details of the termination can be obtained by GetContentFilterDetail.
+const FinishReasonContentFilter = "content_filter"
+
+// FinishReasonFailed is the FinishReason set on the output message when the Ark response failed.
+// The error reported by Ark can be obtained by GetResponseError.
+const FinishReasonFailed = "failed"
+
+// FinishReasonIncomplete is the FinishReason set on the output message when the Ark response
+// is incomplete for another reason than the output length or content moderation, or without a reason reported.
+// The reason reported by Ark can be obtained by GetIncompleteDetails.
+const FinishReasonIncomplete = "incomplete"
+
+// FinishReasonLength is the FinishReason set on the output message when the Ark response
+// is incomplete because it reached the max output tokens.
+const FinishReasonLength = "length"
+
+// sensitiveContentCodeSuffix is shared by all Ark moderation error codes,
+// e.g. InputTextSensitiveContentDetected, OutputImageSensitiveContentDetected.
+const sensitiveContentCodeSuffix = "SensitiveContentDetected"
+
+// ContentFilterDetail describes why Ark terminated a response for content policy reasons.
+type ContentFilterDetail struct {
+	// Type is the category of the content filter reported by Ark.
+	Type string `json:"type,omitempty"`
+	// Details is the explanation reported by Ark.
+	Details string `json:"details,omitempty"`
+}
+
+// IncompleteDetails describes why an Ark response is incomplete.
+type IncompleteDetails struct {
+	// Reason is the reason reported by Ark, e.g. "length", "content_filter".
+	Reason string `json:"reason,omitempty"`
+	// ContentFilter is set when the response was terminated by content moderation.
+	ContentFilter *ContentFilterDetail `json:"content_filter,omitempty"`
+}
+
+// ResponseError is the error object of a failed Ark response.
+type ResponseError struct {
+	// Code is the error code returned by Ark.
+	Code string `json:"code,omitempty"`
+	// Message is the error message returned by Ark.
+	Message string `json:"message,omitempty"`
+}
+
+// ContentFilteredError is returned when Ark rejects the input or aborts the output
+// because it was flagged by content moderation.
+// Retrying the same request will usually produce the same result,
+// so callers should use errors.As to detect it and handle it explicitly.
+type ContentFilteredError struct {
+	// Code is the error code returned by Ark, e.g. "OutputTextSensitiveContentDetected".
+	Code string
+	// Message is the error message returned by Ark.
+	Message string
+	// RequestID is the ID of the filtered request, if available.
+	RequestID string
+
+	err error
+}
+
+func (e *ContentFilteredError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("content filtered by ark, code=%s, request_id=%s: %s", e.Code, e.RequestID, e.Message)
+	}
+	return fmt.Sprintf("content filtered by ark, code=%s: %s", e.Code, e.Message)
+}
+
+func (e *ContentFilteredError) Unwrap() error {
+	return e.err
+}
+
+func isContentFilterCode(code string) bool {
+	return strings.HasSuffix(code, sensitiveContentCodeSuffix)
+}
+
+// convContentFilterError converts the error returned by the ark client to a ContentFilteredError
+// if it is caused by content moderation, otherwise the original error is returned.
+func convContentFilterError(err error) error {
+	var apiErr *arkModel.APIError
+	if !errors.As(err, &apiErr) || !isContentFilterCode(apiErr.Code) {
+		return err
+	}
+	return &ContentFilteredError{
+		Code:      apiErr.Code,
+		Message:   apiErr.Message,
+		RequestID: apiErr.RequestId,
+		err:       err,
+	}
+}
+
+func newContentFilteredErrorFromResponse(respErr *responses.Error) error {
+	if respErr == nil || !isContentFilterCode(respErr.Code) {
+		return nil
+	}
+	return &ContentFilteredError{
+		Code:    respErr.Code,
+		Message: respErr.Message,
+	}
+}
+
+// getIncompleteFinishReason returns the FinishReason of an incomplete response.
+// Content filter terminations are normalized to FinishReasonContentFilter with the detail returned,
+// and the reasons other than the output length to FinishReasonIncomplete, so that no unstable backend string
+// leaks into the FinishReason. The raw reason is kept in IncompleteDetails.Reason.
+func getIncompleteFinishReason(details *responses.IncompleteDetails) (string, *ContentFilterDetail) {
+	if details == nil {
+		return "", nil
+	}
+	if cf := details.GetContentFilter(); cf != nil {
+		return FinishReasonContentFilter, &ContentFilterDetail{
+			Type:    cf.GetType(),
+			Details: cf.GetDetails(),
+		}
+	}
+	if details.Reason == FinishReasonContentFilter {
+		return FinishReasonContentFilter, &ContentFilterDetail{}
+	}
+	switch details.Reason {
+	case FinishReasonLength, "max_output_tokens":
+		return FinishReasonLength, nil
+	default:
+		return FinishReasonIncomplete, nil
+	}
+}
+
+func toIncompleteDetails(details *responses.IncompleteDetails) *IncompleteDetails {
+	if details == nil {
+		return nil
+	}
+	ret := &IncompleteDetails{Reason: details.Reason}
+	if cf := details.GetContentFilter(); cf != nil {
+		ret.ContentFilter = &ContentFilterDetail{
+			Type:    cf.GetType(),
+			Details: cf.GetDetails(),
+		}
+	}
+	return ret
+}
+
+func toResponseError(respErr *responses.Error) *ResponseError {
+	if respErr == nil {
+		return nil
+	}
+	return &ResponseError{
+		Code:    respErr.Code,
+		Message: respErr.Message,
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+func TestConvContentFilterError(t *testing.T) {
+	t.Run("content filter api error", func(t *testing.T) {
+		apiErr := &arkModel.APIError{
+			Code:      "InputTextSensitiveContentDetected",
+			Message:   "sensitive",
+			RequestId: "req-1",
+		}
+		err := convContentFilterError(fmt.Errorf("wrapped: %w", apiErr))
+
+		var cfErr *ContentFilteredError
+		assert.True(t, errors.As(err, &cfErr))
+		assert.Equal(t, "InputTextSensitiveContentDetected", cfErr.Code)
+		assert.Equal(t, "req-1", cfErr.RequestID)
+
+		var gotAPIErr *arkModel.APIError
+		assert.True(t, errors.As(err, &gotAPIErr))
+	})
+
+	t.Run("other api error", func(t *testing.T) {
+		apiErr := &arkModel.APIError{Code: "RateLimitExceeded"}
+		err := convContentFilterError(apiErr)
+		var cfErr *ContentFilteredError
+		assert.False(t, errors.As(err, &cfErr))
+		assert.Equal(t, apiErr, err)
+	})
+}
+
+func TestGetIncompleteFinishReason(t *testing.T) {
+	reason, detail := getIncompleteFinishReason(nil)
+	assert.Equal(t, "", reason)
+	assert.Nil(t, detail)
+
+	reason, detail = getIncompleteFinishReason(&responses.IncompleteDetails{Reason: "max_output_tokens"})
+	assert.Equal(t, FinishReasonLength, reason)
+	assert.Nil(t, detail)
+
+	// the unknown reasons do not leak into the FinishReason
+	reason, detail = getIncompleteFinishReason(&responses.IncompleteDetails{Reason: "backend_interrupted"})
+	assert.Equal(t, FinishReasonIncomplete, reason)
+	assert.Nil(t, detail)
+
+	reason, detail = getIncompleteFinishReason(&responses.IncompleteDetails{})
+	assert.Equal(t, FinishReasonIncomplete, reason)
+	assert.Nil(t, detail)
+
+	reason, detail = getIncompleteFinishReason(&responses.IncompleteDetails{
+		Reason: "content_filter",
+		ContentFilter: &responses.ContentFilter{
+			Type:    "output",
+			Details: "violence",
+		},
+	})
+	assert.Equal(t, FinishReasonContentFilter, reason)
+	assert.Equal(t, &ContentFilterDetail{Type: "output", Details: "violence"}, detail)
+}
+
+func TestToOutputMessageContentFilter(t *testing.T) {
+	cm := &ResponsesAPIChatModel{}
+
+	t.Run("incomplete", func(t *testing.T) {
+		msg, err := cm.toOutputMessage(&responses.ResponseObject{
+			Status: responses.ResponseStatus_incomplete,
+			IncompleteDetails: &responses.IncompleteDetails{
+				ContentFilter: &responses.ContentFilter{Type: "output", Details: "violence"},
+			},
+			Usage: &responses.Usage{},
+		}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, FinishReasonContentFilter, msg.ResponseMeta.FinishReason)
+		detail, ok := GetContentFilterDetail(msg)
+		assert.True(t, ok)
+		assert.Equal(t, "violence", detail.Details)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		_, err := cm.toOutputMessage(&responses.ResponseObject{
+			Status: responses.ResponseStatus_failed,
+			Error: &responses.Error{
+				Code:    "OutputTextSensitiveContentDetected",
+				Message: "sensitive",
+			},
+			Usage: &responses.Usage{},
+		}, nil)
+		var cfErr *ContentFilteredError
+		assert.True(t, errors.As(err, &cfErr))
+		assert.Equal(t, "OutputTextSensitiveContentDetected", cfErr.Code)
+	})
+
+	t.Run("not filtered", func(t *testing.T) {
+		msg := &schema.Message{}
+		_, ok := GetContentFilterDetail(msg)
+		assert.False(t, ok)
+	})
+}
+
+func TestToOutputMessageResponseDetails(t *testing.T) {
+	cm := &ResponsesAPIChatModel{}
+
+	t.Run("failed", func(t *testing.T) {
+		msg, err := cm.toOutputMessage(&responses.ResponseObject{
+			Status: responses.ResponseStatus_failed,
+			Error: &responses.Error{
+				Code:    "InternalServiceError",
+				Message: "the service encountered an unexpected internal error",
+			},
+			Usage: &responses.Usage{},
+		}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, FinishReasonFailed, msg.ResponseMeta.FinishReason)
+		respErr, ok := GetResponseError(msg)
+		assert.True(t, ok)
+		assert.Equal(t, &ResponseError{
+			Code:    "InternalServiceError",
+			Message: "the service encountered an unexpected internal error",
+		}, respErr)
+		_, ok = GetIncompleteDetails(msg)
+		assert.False(t, ok)
+	})
+
+	t.Run("failed without error", func(t *testing.T) {
+		msg, err := cm.toOutputMessage(&responses.ResponseObject{
+			Status: responses.ResponseStatus_failed,
+			Usage:  &responses.Usage{},
+		}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, FinishReasonFailed, msg.ResponseMeta.FinishReason)
+		_, ok := GetResponseError(msg)
+		assert.False(t, ok)
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		msg, err := cm.toOutputMessage(&responses.ResponseObject{
+			Status:            responses.ResponseStatus_incomplete,
+			IncompleteDetails: &responses.IncompleteDetails{Reason: "length"},
+			Usage:             &responses.Usage{},
+		}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "length", msg.ResponseMeta.FinishReason)
+		details, ok := GetIncompleteDetails(msg)
+		assert.True(t, ok)
+		assert.Equal(t, &IncompleteDetails{Reason: "length"}, details)
+		_, ok = GetResponseError(msg)
+		assert.False(t, ok)
+	})
+
+	t.Run("incomplete content filter", func(t *testing.T) {
+		msg, err := cm.toOutputMessage(&responses.ResponseObject{
+			Status: responses.ResponseStatus_incomplete,
+			IncompleteDetails: &responses.IncompleteDetails{
+				Reason:        "content_filter",
+				ContentFilter: &responses.ContentFilter{Type: "output", Details: "violence"},
+			},
+			Usage: &responses.Usage{},
+		}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, FinishReasonContentFilter, msg.ResponseMeta.FinishReason)
+		details, ok := GetIncompleteDetails(msg)
+		assert.True(t, ok)
+		assert.Equal(t, &IncompleteDetails{
+			Reason:        "content_filter",
+			ContentFilter: &ContentFilterDetail{Type: "output", Details: "violence"},
+		}, details)
+	})
+
+	t.Run("incomplete without details", func(t *testing.T) {
+		msg, err := cm.toOutputMessage(&responses.ResponseObject{
+			Status: responses.ResponseStatus_incomplete,
+			Usage:  &responses.Usage{},
+		}, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, FinishReasonIncomplete, msg.ResponseMeta.FinishReason)
+		_, ok := GetIncompleteDetails(msg)
+		assert.False(t, ok)
+	})
+}
@@ -6,11 +6,17 @@ require (
 	github.com/bytedance/mockey v1.2.14
 	github.com/bytedance/sonic v1.14.1
 	github.com/cloudwego/eino v0.7.13
+	github.com/cloudwego/eino-ext/libs/callbackextra v0.2.0
+	github.com/cloudwego/eino-ext/libs/configcheck v0.1.0
+	github.com/cloudwego/eino-ext/libs/multimodal v0.2.0
+	github.com/cloudwego/eino-ext/libs/pii v0.1.1
+	github.com/cloudwego/eino-ext/libs/stall v0.1.0
+	github.com/cloudwego/eino-ext/libs/tokenestimate v0.1.0
 	github.com/eino-contrib/jsonschema v1.0.3
 	github.com/smartystreets/goconvey v1.8.1
 	github.com/stretchr/testify v1.11.1
 	github.com/volcengine/volcengine-go-sdk v1.2.9
-
+	google.golang.org/protobuf v1.31.0
 )
 
 require (
@@ -46,7 +52,6 @@ require (
 	golang.org/x/crypto v0.32.0 // indirect
 	golang.org/x/exp v0.0.0-20230713183714-613f0c0eb8a1 // indirect
 	golang.org/x/sys v0.29.0 // indirect
-	google.golang.org/protobuf v1.31.0 // indirect
 	gopkg.in/yaml.v2 v2.2.8 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,35 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino-ext/libs/multimodal"
+	fmodel "github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestMediaLimits(t *testing.T) {
+	cm := &completionAPIChatModel{mediaLimits: &multimodal.Limits{AllowedMIMETypes: []string{"image/*"}}}
+	_, err := cm.genRequest([]*schema.Message{{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+		{Type: schema.ChatMessagePartTypeVideoURL, VideoURL: &schema.ChatMessageVideoURL{URL: "https://example.com/a.mp4", MIMEType: "video/mp4"}},
+	}}}, &fmodel.Options{}, &arkOptions{})
+	assert.ErrorContains(t, err, "messages[0].multi_content[0].video_url")
+}
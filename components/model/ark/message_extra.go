@@ -17,8 +17,12 @@
 package ark
 
 import (
+	"fmt"
+
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/libs/multimodal"
 )
 
 const (
@@ -31,6 +35,10 @@ const (
 	keyOfResponseCacheExpireAt = "ark-response-cache-expire-at"
 	keyOfServiceTier           = "ark-service-tier"
 	keyOfPartial               = "ark-partial"
+	keyOfContentFilter         = "ark-content-filter"
+	keyOfIncompleteDetails     = "ark-incomplete-details"
+	keyOfResponseError         = "ark-response-error"
+	keyOfResponseIDChain       = "ark-response-id-chain"
 	ImageSizeKey               = "seedream-image-size"
 )
 
@@ -40,6 +48,7 @@ type arkServiceTier string
 type arkResponseID string
 type arkContextID string
 type arkResponseCacheExpireAt int64
+type arkResponseIDChain []string
 
 func init() {
 	compose.RegisterStreamChunkConcatFunc(func(chunks []arkRequestID) (final arkRequestID, err error) {
@@ -101,6 +110,21 @@ func init() {
 		return chunks[len(chunks)-1], nil
 	})
 	schema.RegisterName[arkResponseCacheExpireAt]("_eino_ext_ark_response_cache_expire_at")
+
+	compose.RegisterStreamChunkConcatFunc(func(chunks []arkResponseIDChain) (final arkResponseIDChain, err error) {
+		// Every chunk carrying a chain describes the same response, so take the first non-empty chain.
+		for _, chunk := range chunks {
+			if len(chunk) > 0 {
+				return chunk, nil
+			}
+		}
+		return nil, nil
+	})
+	schema.RegisterName[arkResponseIDChain]("_eino_ext_ark_response_id_chain")
+
+	schema.RegisterName[*ContentFilterDetail]("_eino_ext_ark_content_filter_detail")
+	schema.RegisterName[*IncompleteDetails]("_eino_ext_ark_incomplete_details")
+	schema.RegisterName[*ResponseError]("_eino_ext_ark_response_error")
 }
 
 func GetArkRequestID(msg *schema.Message) string {
@@ -132,9 +156,10 @@ func setModelName(msg *schema.Message, name string) {
 	setMsgExtra(msg, keyOfModelName, arkModelName(name))
 }
 
-// Deprecated: Use GetResponseID instead.
+// Deprecated: Use GetResponseID instead for ResponsesAPI responses.
 // GetContextID returns the conversation context ID from the message.
-// Available only for ResponsesAPI responses.
+// For ResponsesAPI responses, it is the response ID.
+// For ChatCompletion responses of requests using a ContextAPI cache, it is the ContextID passed to [WithCache].
 func GetContextID(msg *schema.Message) (string, bool) {
 	contextID_, ok := getMsgExtraValue[arkContextID](msg, keyOfContextID)
 	if ok {
@@ -188,6 +213,44 @@ func setResponseID(msg *schema.Message, responseID string) {
 	setMsgExtra(msg, keyOfResponseID, arkResponseID(responseID))
 }
 
+// GetResponseIDChain returns the IDs of the responses that the message is built upon, in chronological order.
+// The last element is the response ID of the message itself.
+// The chain can be used to audit or resume server-side conversations,
+// e.g. by passing any of its elements to WithPreviousResponseID.
+// Available only for ResponsesAPI responses.
+func GetResponseIDChain(msg *schema.Message) []string {
+	chain, ok := getMsgExtraValue[arkResponseIDChain](msg, keyOfResponseIDChain)
+	if ok && len(chain) > 0 {
+		return append([]string(nil), chain...)
+	}
+	// When the user serializes and deserializes the message, the type may be lost.
+	if anyChain, ok := getMsgExtraValue[[]any](msg, keyOfResponseIDChain); ok {
+		ret := make([]string, 0, len(anyChain))
+		for _, v := range anyChain {
+			if id, ok := v.(string); ok {
+				ret = append(ret, id)
+			}
+		}
+		if len(ret) > 0 {
+			return ret
+		}
+	}
+	if id, ok := GetResponseID(msg); ok && id != "" {
+		return []string{id}
+	}
+	return nil
+}
+
+func setResponseIDChain(msg *schema.Message, prevChain []string, responseID string) {
+	if responseID == "" {
+		return
+	}
+	chain := make(arkResponseIDChain, 0, len(prevChain)+1)
+	chain = append(chain, prevChain...)
+	chain = append(chain, responseID)
+	setMsgExtra(msg, keyOfResponseIDChain, chain)
+}
+
 // GetCacheExpiration returns the cache expiration time in seconds.
 // Only available for ResponsesAPI responses.
 func GetCacheExpiration(msg *schema.Message) (expireAtSec int64, ok bool) {
@@ -280,17 +343,35 @@ func setFPS(extra map[string]any, fps float64) {
 	extra[videoURLFPS] = fps
 }
 
+// getFPS returns the fps set by SetFPS or SetInputVideoFPS, or else the FPS of the multimodal.VideoMetadata.
 func getFPS(extra map[string]any) *float64 {
 	if extra == nil {
 		return nil
 	}
 	fps, ok := extra[videoURLFPS].(float64)
 	if !ok {
+		if meta := multimodal.GetVideoMetadata(extra); meta != nil && meta.FPS > 0 {
+			return &meta.FPS
+		}
 		return nil
 	}
 	return &fps
 }
 
+// checkVideoMetadata checks the multimodal.VideoMetadata of a video part.
+// Ark samples the whole video, so a clip of the video is rejected instead of being sent in full.
+func checkVideoMetadata(extra map[string]any) error {
+	meta := multimodal.GetVideoMetadata(extra)
+	if err := meta.Validate(); err != nil {
+		return err
+	}
+	if meta.Clipped() {
+		return fmt.Errorf("video clipping is not supported by ark, got start offset %s and end offset %s",
+			meta.StartOffset, meta.EndOffset)
+	}
+	return nil
+}
+
 func GetServiceTier(msg *schema.Message) (string, bool) {
 	t, ok := getMsgExtraValue[arkServiceTier](msg, keyOfServiceTier)
 	if !ok {
@@ -392,3 +473,45 @@ func getPartial(msg *schema.Message) bool {
 	}
 	return v
 }
+
+// GetContentFilterDetail returns the content filter detail of the message,
+// which is set when the FinishReason is FinishReasonContentFilter.
+// Only available for ResponsesAPI responses.
+func GetContentFilterDetail(msg *schema.Message) (*ContentFilterDetail, bool) {
+	return getMsgExtraValue[*ContentFilterDetail](msg, keyOfContentFilter)
+}
+
+func setContentFilterDetail(msg *schema.Message, detail *ContentFilterDetail) {
+	if detail == nil {
+		return
+	}
+	setMsgExtra(msg, keyOfContentFilter, detail)
+}
+
+// GetIncompleteDetails returns the details of an incomplete response,
+// which is set when the response status is incomplete.
+// Only available for ResponsesAPI responses.
+func GetIncompleteDetails(msg *schema.Message) (*IncompleteDetails, bool) {
+	return getMsgExtraValue[*IncompleteDetails](msg, keyOfIncompleteDetails)
+}
+
+func setIncompleteDetails(msg *schema.Message, details *IncompleteDetails) {
+	if details == nil {
+		return
+	}
+	setMsgExtra(msg, keyOfIncompleteDetails, details)
+}
+
+// GetResponseError returns the error object of a failed response,
+// which is set when the FinishReason is FinishReasonFailed.
+// Only available for ResponsesAPI responses.
+func GetResponseError(msg *schema.Message) (*ResponseError, bool) {
+	return getMsgExtraValue[*ResponseError](msg, keyOfResponseError)
+}
+
+func setResponseError(msg *schema.Message, respErr *ResponseError) {
+	if respErr == nil {
+		return
+	}
+	setMsgExtra(msg, keyOfResponseError, respErr)
+}
@@ -18,10 +18,13 @@ package ark
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/libs/multimodal"
 )
 
 func TestConcatMessages(t *testing.T) {
@@ -228,3 +231,55 @@ func TestInvalidateMessageCaches(t *testing.T) {
 		assert.Nil(t, msg.Extra[keyOfResponseCacheExpireAt])
 	}
 }
+
+func TestGetResponseIDChain(t *testing.T) {
+	assert.Nil(t, GetResponseIDChain(&schema.Message{}))
+
+	msg := &schema.Message{Extra: map[string]any{keyOfResponseID: "1"}}
+	assert.Equal(t, []string{"1"}, GetResponseIDChain(msg))
+
+	msg = &schema.Message{Extra: map[string]any{keyOfResponseIDChain: []any{"1", "2"}}}
+	assert.Equal(t, []string{"1", "2"}, GetResponseIDChain(msg))
+
+	chunks := []*schema.Message{{Role: schema.Assistant}, {Role: schema.Assistant}}
+	setResponseIDChain(chunks[0], []string{"1"}, "2")
+	setResponseIDChain(chunks[1], []string{"1"}, "2")
+	concated, err := schema.ConcatMessages(chunks)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, GetResponseIDChain(concated))
+}
+
+func TestPortableVideoMetadata(t *testing.T) {
+	url := "https://example.com/video.mp4"
+	video := &schema.MessageInputVideo{MessagePartCommon: schema.MessagePartCommon{URL: &url}}
+	video.Extra = multimodal.SetVideoMetadata(video.Extra, &multimodal.VideoMetadata{FPS: 2})
+	assert.Equal(t, 2.0, *GetInputVideoFPS(video))
+
+	item, err := convMsgInputVideoToResponseContentItem(video)
+	assert.NoError(t, err)
+	assert.Equal(t, float32(2), item.GetVideo().GetFps())
+
+	cm := &completionAPIChatModel{}
+	content, err := cm.toArkContent(&schema.Message{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+		{Type: schema.ChatMessagePartTypeVideoURL, Video: video},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, *content.ListValue[0].VideoURL.FPS)
+
+	// the ark fps takes precedence
+	SetInputVideoFPS(video, 1)
+	assert.Equal(t, 1.0, *GetInputVideoFPS(video))
+
+	// a clip is rejected instead of sending the whole video
+	video.Extra = multimodal.SetVideoMetadata(nil, &multimodal.VideoMetadata{StartOffset: 10 * time.Second})
+	_, err = convMsgInputVideoToResponseContentItem(video)
+	assert.ErrorContains(t, err, "video clipping is not supported by ark")
+	_, err = cm.toArkContent(&schema.Message{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+		{Type: schema.ChatMessagePartTypeVideoURL, Video: video},
+	}})
+	assert.ErrorContains(t, err, "video clipping is not supported by ark")
+
+	video.Extra = multimodal.SetVideoMetadata(nil, &multimodal.VideoMetadata{FPS: -1})
+	_, err = convMsgInputVideoToResponseContentItem(video)
+	assert.ErrorContains(t, err, "invalid video metadata")
+}
@@ -23,6 +23,7 @@ import (
 
 type arkOptions struct {
 	customHeaders       map[string]string
+	requestHeaders      map[string]string
 	reasoningEffort     *arkModel.ReasoningEffort
 	maxCompletionTokens *int
 
@@ -33,6 +34,13 @@ type arkOptions struct {
 	enableWebSearch *ToolWebSearch
 
 	maxToolCalls *int64
+
+	previousResponseID *string
+
+	maxInputTokens *int
+
+	reasoningSummary *ReasoningSummary
+	textVerbosity    *TextVerbosity
 }
 
 // WithCustomHeader sets custom headers for a single request
@@ -43,6 +51,16 @@ func WithCustomHeader(m map[string]string) model.Option {
 	})
 }
 
+// WithRequestHeaders sets headers for a single request, e.g. a user ID or a trace ID.
+// Unlike WithCustomHeader, the headers are merged over the custom headers instead of replacing them,
+// and take precedence over the X-Request-ID header set by ContextWithRequestID.
+// This option is only supported for the ResponsesAPIChatModel.
+func WithRequestHeaders(headers map[string]string) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *arkOptions) {
+		o.requestHeaders = headers
+	})
+}
+
 // WithThinking sets the thinking process configuration for the ark.
 func WithThinking(thinking *arkModel.Thinking) model.Option {
 	return model.WrapImplSpecificOptFn(func(o *arkOptions) {
@@ -141,3 +159,40 @@ func WithMaxToolCalls(n int64) model.Option {
 	})
 
 }
+
+// WithPreviousResponseID sets the previous response ID for a single request,
+// which resumes the server-side conversation from the specified response.
+// Input messages are sent as is, the session cache scanning for response IDs in messages is skipped.
+// It takes precedence over CacheOption.HeadPreviousResponseID and the response IDs carried by messages.
+// This option is only supported for the ResponsesAPIChatModel.
+func WithPreviousResponseID(responseID string) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *arkOptions) {
+		o.previousResponseID = &responseID
+	})
+}
+
+// WithMaxInputTokens sets the max estimated input tokens for a single request.
+// Requests whose estimated input exceeds the limit fail with an *InputTooLargeError before being sent.
+// This option is only supported for the ResponsesAPIChatModel.
+func WithMaxInputTokens(n int) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *arkOptions) {
+		o.maxInputTokens = &n
+	})
+}
+
+// WithReasoningSummary sets the granularity of the reasoning summary for a single request,
+// which controls how much thinking text is paid for and can be displayed.
+// This option is only supported for the ResponsesAPIChatModel.
+func WithReasoningSummary(summary ReasoningSummary) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *arkOptions) {
+		o.reasoningSummary = &summary
+	})
+}
+
+// WithTextVerbosity sets the verbosity of the text output for a single request.
+// This option is only supported for the ResponsesAPIChatModel.
+func WithTextVerbosity(verbosity TextVerbosity) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *arkOptions) {
+		o.textVerbosity = &verbosity
+	})
+}
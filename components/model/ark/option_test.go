@@ -40,9 +40,13 @@ func TestOptions(t *testing.T) {
 		WithCache(&cacheOpt),
 		WithThinking(&arkModel.Thinking{
 			Type: arkModel.ThinkingTypeEnabled,
-		}))
+		}),
+		WithPreviousResponseID("resp-1"),
+		WithRequestHeaders(map[string]string{"X-User-ID": "u1"}))
 
 	assert.Equal(t, map[string]string{"k1": "v1"}, opt.customHeaders)
 	assert.Equal(t, cacheOpt, *opt.cache)
 	assert.Equal(t, arkModel.ThinkingTypeEnabled, opt.thinking.Type)
+	assert.Equal(t, "resp-1", *opt.previousResponseID)
+	assert.Equal(t, map[string]string{"X-User-ID": "u1"}, opt.requestHeaders)
 }
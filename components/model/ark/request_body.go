@@ -0,0 +1,111 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// defaultHTTPTimeout is the timeout of the http client created by the ark sdk by default.
+const defaultHTTPTimeout = 10 * time.Minute
+
+type extraBodyFieldsKey struct{}
+
+// withExtraBodyFields returns a context carrying the fields to merge into the JSON body of the request,
+// for the request fields which are not supported by the ark sdk yet.
+func withExtraBodyFields(ctx context.Context, fields map[string]any) context.Context {
+	if len(fields) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, extraBodyFieldsKey{}, fields)
+}
+
+// extraBodyTransport merges the fields of withExtraBodyFields into the JSON body of the requests.
+type extraBodyTransport struct {
+	base http.RoundTripper
+}
+
+func (t *extraBodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fields, ok := req.Context().Value(extraBodyFieldsKey{}).(map[string]any)
+	if !ok || req.Body == nil || req.Body == http.NoBody {
+		return t.base.RoundTrip(req)
+	}
+
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	// the numbers are kept as json.Number, so that the int64 fields, e.g. the seed, are not rounded to float64
+	body := make(map[string]any)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request body: %w", err)
+	}
+	mergeBodyFields(body, fields)
+	if data, err = json.Marshal(body); err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	// RoundTrip must not modify the request
+	nReq := req.Clone(req.Context())
+	nReq.Body = io.NopCloser(bytes.NewReader(data))
+	nReq.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	nReq.ContentLength = int64(len(data))
+	return t.base.RoundTrip(nReq)
+}
+
+// mergeBodyFields merges the fields into the body, objects are merged recursively.
+func mergeBodyFields(body, fields map[string]any) {
+	for k, v := range fields {
+		src, srcOK := v.(map[string]any)
+		dst, dstOK := body[k].(map[string]any)
+		if srcOK && dstOK {
+			mergeBodyFields(dst, src)
+			continue
+		}
+		body[k] = v
+	}
+}
+
+// newExtraBodyHTTPClient returns a copy of the client whose transport supports withExtraBodyFields.
+func newExtraBodyHTTPClient(client *http.Client, timeout *time.Duration) *http.Client {
+	var nClient http.Client
+	if client != nil {
+		nClient = *client
+	} else {
+		nClient.Timeout = defaultHTTPTimeout
+		if timeout != nil {
+			nClient.Timeout = *timeout
+		}
+	}
+	base := nClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	nClient.Transport = &extraBodyTransport{base: base}
+	return &nClient
+}
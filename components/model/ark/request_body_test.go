@@ -0,0 +1,124 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtraBodyTransport(t *testing.T) {
+	var (
+		received map[string]any
+		raw      string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		raw = string(data)
+		received = nil
+		_ = json.Unmarshal(data, &received)
+		assert.Equal(t, int64(len(data)), r.ContentLength)
+	}))
+	defer server.Close()
+
+	client := newExtraBodyHTTPClient(nil, nil)
+	post := func(ctx context.Context, body string) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, bytes.NewBufferString(body))
+		assert.NoError(t, err)
+		resp, err := client.Do(req)
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	t.Run("without fields", func(t *testing.T) {
+		post(context.Background(), `{"model":"m","reasoning":{"effort":"low"}}`)
+		assert.Equal(t, map[string]any{"model": "m", "reasoning": map[string]any{"effort": "low"}}, received)
+	})
+
+	t.Run("merge fields", func(t *testing.T) {
+		ctx := withExtraBodyFields(context.Background(), extraResponsesBodyFields(&arkOptions{
+			reasoningSummary: ptrOf(ReasoningSummaryConcise),
+			textVerbosity:    ptrOf(TextVerbosityLow),
+		}))
+		post(ctx, `{"model":"m","reasoning":{"effort":"low"},"text":{"format":{"type":"text"}}}`)
+		assert.Equal(t, map[string]any{
+			"model":     "m",
+			"reasoning": map[string]any{"effort": "low", "summary": "concise"},
+			"text":      map[string]any{"format": map[string]any{"type": "text"}, "verbosity": "low"},
+		}, received)
+
+		post(ctx, `{"model":"m"}`)
+		assert.Equal(t, map[string]any{
+			"model":     "m",
+			"reasoning": map[string]any{"summary": "concise"},
+			"text":      map[string]any{"verbosity": "low"},
+		}, received)
+	})
+
+	t.Run("keep int64 precision", func(t *testing.T) {
+		ctx := withExtraBodyFields(context.Background(), extraResponsesBodyFields(&arkOptions{
+			reasoningSummary: ptrOf(ReasoningSummaryConcise),
+		}))
+		post(ctx, `{"model":"m","seed":9007199254740993,"temperature":0.7}`)
+		assert.Contains(t, raw, `"seed":9007199254740993`)
+		assert.Contains(t, raw, `"temperature":0.7`)
+	})
+}
+
+func TestNewExtraBodyHTTPClient(t *testing.T) {
+	client := newExtraBodyHTTPClient(nil, nil)
+	assert.Equal(t, defaultHTTPTimeout, client.Timeout)
+	assert.Equal(t, http.DefaultTransport, client.Transport.(*extraBodyTransport).base)
+
+	client = newExtraBodyHTTPClient(nil, ptrOf(time.Second))
+	assert.Equal(t, time.Second, client.Timeout)
+
+	base := &http.Transport{}
+	custom := &http.Client{Transport: base, Timeout: time.Minute}
+	client = newExtraBodyHTTPClient(custom, ptrOf(time.Second))
+	assert.Equal(t, time.Minute, client.Timeout)
+	assert.Equal(t, base, client.Transport.(*extraBodyTransport).base)
+	assert.Equal(t, base, custom.Transport)
+}
+
+func TestExtraResponsesBodyFields(t *testing.T) {
+	assert.Empty(t, extraResponsesBodyFields(&arkOptions{}))
+
+	ctx := context.Background()
+	assert.Equal(t, ctx, withExtraBodyFields(ctx, extraResponsesBodyFields(&arkOptions{})))
+
+	cm := &ResponsesAPIChatModel{reasoningSummary: ptrOf(ReasoningSummaryAuto)}
+	_, specOptions, err := cm.getOptions(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"reasoning": map[string]any{"summary": "auto"}}, extraResponsesBodyFields(specOptions))
+
+	_, specOptions, err = cm.getOptions([]model.Option{WithReasoningSummary(ReasoningSummaryNone), WithTextVerbosity(TextVerbosityHigh)})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"reasoning": map[string]any{"summary": "none"},
+		"text":      map[string]any{"verbosity": "high"},
+	}, extraResponsesBodyFields(specOptions))
+}
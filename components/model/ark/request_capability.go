@@ -0,0 +1,105 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+// RequestField is an optional request field of the ChatCompletion and Responses APIs that a model may not support.
+type RequestField string
+
+const (
+	RequestFieldThinking         RequestField = "thinking"
+	RequestFieldReasoningEffort  RequestField = "reasoning_effort"
+	RequestFieldReasoningSummary RequestField = "reasoning_summary"
+	RequestFieldTextVerbosity    RequestField = "text_verbosity"
+	RequestFieldServiceTier      RequestField = "service_tier"
+)
+
+// CallbackExtraKeyDroppedFields is the key of the callback input and output Extra listing the []RequestField
+// dropped from the request as the model does not support them, see ChatModelConfig.UnsupportedFields
+// and ResponsesAPIConfig.UnsupportedFields.
+const CallbackExtraKeyDroppedFields = "ark-dropped-request-fields"
+
+// dropUnsupportedFields calls drop for each field the model does not support, which reports whether the field was set,
+// returning the fields actually dropped, in the order of unsupported.
+func dropUnsupportedFields(unsupported map[string][]RequestField, model string, drop func(field RequestField) bool) []RequestField {
+	var dropped []RequestField
+	for _, field := range unsupported[model] {
+		if drop(field) {
+			dropped = append(dropped, field)
+		}
+	}
+	return dropped
+}
+
+// dropUnsupportedResponsesFields removes the fields the model of the request does not support
+// from the Responses API request and the options.
+func dropUnsupportedResponsesFields(unsupported map[string][]RequestField, req *responses.ResponsesRequest,
+	specOptions *arkOptions) []RequestField {
+	return dropUnsupportedFields(unsupported, req.Model, func(field RequestField) (set bool) {
+		switch field {
+		case RequestFieldThinking:
+			set = req.Thinking != nil
+			req.Thinking, specOptions.thinking = nil, nil
+		case RequestFieldReasoningEffort:
+			set = req.Reasoning != nil
+			req.Reasoning, specOptions.reasoningEffort = nil, nil
+		case RequestFieldReasoningSummary:
+			set = specOptions.reasoningSummary != nil
+			specOptions.reasoningSummary = nil
+		case RequestFieldTextVerbosity:
+			set = specOptions.textVerbosity != nil
+			specOptions.textVerbosity = nil
+		case RequestFieldServiceTier:
+			set = req.ServiceTier != nil
+			req.ServiceTier = nil
+		}
+		return set
+	})
+}
+
+// dropUnsupportedChatFields removes the fields the model of the request does not support
+// from the ChatCompletion API request and the options.
+// The reasoning summary and the text verbosity are not sent by the ChatCompletion API, so there is nothing to drop.
+func dropUnsupportedChatFields(unsupported map[string][]RequestField, req *model.CreateChatCompletionRequest,
+	specOptions *arkOptions) []RequestField {
+	return dropUnsupportedFields(unsupported, req.Model, func(field RequestField) (set bool) {
+		switch field {
+		case RequestFieldThinking:
+			set = req.Thinking != nil
+			req.Thinking, specOptions.thinking = nil, nil
+		case RequestFieldReasoningEffort:
+			set = req.ReasoningEffort != nil
+			req.ReasoningEffort, specOptions.reasoningEffort = nil, nil
+		case RequestFieldServiceTier:
+			set = req.ServiceTier != nil
+			req.ServiceTier = nil
+		}
+		return set
+	})
+}
+
+// droppedFieldsExtra adds the dropped fields to the callback extra, if any.
+func droppedFieldsExtra(extra map[string]any, dropped []RequestField) map[string]any {
+	if len(dropped) > 0 {
+		extra[CallbackExtraKeyDroppedFields] = dropped
+	}
+	return extra
+}
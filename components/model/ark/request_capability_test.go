@@ -0,0 +1,138 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+func TestResponsesAPIChatModel_UnsupportedFields(t *testing.T) {
+	client := &fakeResponsesClient{resp: &responses.ResponseObject{
+		Id:     "resp-1",
+		Status: responses.ResponseStatus_completed,
+		Output: []*responses.OutputItem{{Union: &responses.OutputItem_OutputMessage{OutputMessage: &responses.ItemOutputMessage{
+			Content: []*responses.OutputContentItem{{Union: &responses.OutputContentItem_Text{
+				Text: &responses.OutputContentItemText{Text: "hello"},
+			}}},
+		}}}},
+	}}
+	serviceTier := "auto"
+	effort := arkModel.ReasoningEffortHigh
+	cm, err := NewResponsesAPIChatModel(context.Background(), &ResponsesAPIConfig{
+		Model:           "model-a",
+		Thinking:        &arkModel.Thinking{Type: arkModel.ThinkingTypeEnabled},
+		ReasoningEffort: &effort,
+		ServiceTier:     &serviceTier,
+		UnsupportedFields: map[string][]RequestField{
+			"model-b": {RequestFieldThinking, RequestFieldReasoningEffort, RequestFieldReasoningSummary, RequestFieldServiceTier},
+		},
+		Client: client,
+	})
+	assert.NoError(t, err)
+
+	var dropped any
+	handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, _ *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+		dropped = model.ConvCallbackInput(input).Extra[CallbackExtraKeyDroppedFields]
+		return ctx
+	}).Build()
+	ctx := callbacks.InitCallbacks(context.Background(), &callbacks.RunInfo{}, handler)
+	in := []*schema.Message{schema.UserMessage("hi")}
+
+	_, err = cm.Generate(ctx, in)
+	assert.NoError(t, err)
+	assert.NotNil(t, client.req.Thinking)
+	assert.NotNil(t, client.req.Reasoning)
+	assert.NotNil(t, client.req.ServiceTier)
+	assert.Nil(t, dropped)
+
+	_, err = cm.Generate(ctx, in, model.WithModel("model-b"))
+	assert.NoError(t, err)
+	assert.Equal(t, "model-b", client.req.Model)
+	assert.Nil(t, client.req.Thinking)
+	assert.Nil(t, client.req.Reasoning)
+	assert.Nil(t, client.req.ServiceTier)
+	// the reasoning summary is not set, so it is not reported as dropped
+	assert.Equal(t, []RequestField{RequestFieldThinking, RequestFieldReasoningEffort, RequestFieldServiceTier}, dropped)
+
+	specOptions := &arkOptions{reasoningSummary: ptrOf(ReasoningSummaryAuto)}
+	fields := dropUnsupportedResponsesFields(map[string][]RequestField{"m": {RequestFieldReasoningSummary}},
+		&responses.ResponsesRequest{Model: "m"}, specOptions)
+	assert.Equal(t, []RequestField{RequestFieldReasoningSummary}, fields)
+	assert.Empty(t, extraResponsesBodyFields(specOptions))
+}
+
+func TestChatModel_UnsupportedFields(t *testing.T) {
+	PatchConvey("test ChatModelConfig.UnsupportedFields", t, func() {
+		serviceTier := "auto"
+		effort := arkModel.ReasoningEffortHigh
+		cm, err := NewChatModel(context.Background(), &ChatModelConfig{
+			APIKey:          "key",
+			Model:           "model-a",
+			Thinking:        &arkModel.Thinking{Type: arkModel.ThinkingTypeEnabled},
+			ReasoningEffort: &effort,
+			ServiceTier:     &serviceTier,
+			UnsupportedFields: map[string][]RequestField{
+				"model-b": {RequestFieldThinking, RequestFieldReasoningEffort, RequestFieldTextVerbosity},
+			},
+		})
+		convey.So(err, convey.ShouldBeNil)
+
+		Mock(GetMethod(cm.chatModel.client, "CreateChatCompletion")).Return(arkModel.ChatCompletionResponse{
+			Choices: []*arkModel.ChatCompletionChoice{{Message: arkModel.ChatCompletionMessage{
+				Role:    arkModel.ChatMessageRoleAssistant,
+				Content: &arkModel.ChatCompletionMessageContent{StringValue: ptrOf("hello")},
+			}}},
+		}, nil).Build()
+
+		var dropped any
+		handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, _ *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			dropped = model.ConvCallbackInput(input).Extra[CallbackExtraKeyDroppedFields]
+			return ctx
+		}).Build()
+		ctx := callbacks.InitCallbacks(context.Background(), &callbacks.RunInfo{}, handler)
+		in := []*schema.Message{schema.UserMessage("hi")}
+
+		_, err = cm.Generate(ctx, in)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(dropped, convey.ShouldBeNil)
+
+		_, err = cm.Generate(ctx, in, model.WithModel("model-b"))
+		convey.So(err, convey.ShouldBeNil)
+		// the text verbosity is not sent by the ChatCompletion API, so it is not reported as dropped
+		convey.So(dropped, convey.ShouldResemble, []RequestField{RequestFieldThinking, RequestFieldReasoningEffort})
+
+		specOptions := &arkOptions{thinking: cm.chatModel.thinking, reasoningEffort: &effort}
+		req := &arkModel.CreateChatCompletionRequest{Model: "model-b", Thinking: specOptions.thinking,
+			ReasoningEffort: &effort, ServiceTier: &serviceTier}
+		fields := dropUnsupportedChatFields(cm.chatModel.unsupportedFields, req, specOptions)
+		convey.So(fields, convey.ShouldResemble, []RequestField{RequestFieldThinking, RequestFieldReasoningEffort})
+		convey.So(req.Thinking, convey.ShouldBeNil)
+		convey.So(req.ReasoningEffort, convey.ShouldBeNil)
+		convey.So(req.ServiceTier, convey.ShouldNotBeNil)
+		convey.So(specOptions.thinking, convey.ShouldBeNil)
+	})
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import "context"
+
+// RequestIDHeader is the header carrying the request ID of ContextWithRequestID,
+// which helps to correlate the requests on the Ark side for tracing and abuse attribution.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a context carrying the request ID,
+// which is sent in the X-Request-ID header by the ResponsesAPIChatModel.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID set by ContextWithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(requestIDKey{}).(string)
+	return requestID, ok && requestID != ""
+}
+
+// buildRequestHeaders merges the headers of a request, from lowest to highest priority:
+// the custom headers, the request ID of the context, and the headers of WithRequestHeaders.
+// The result is a new map, so the client may modify it without affecting the concurrent calls.
+func buildRequestHeaders(ctx context.Context, customHeaders, requestHeaders map[string]string) map[string]string {
+	requestID, hasRequestID := RequestIDFromContext(ctx)
+	if customHeaders == nil && !hasRequestID && len(requestHeaders) == 0 {
+		return nil
+	}
+
+	headers := make(map[string]string, len(customHeaders)+len(requestHeaders)+1)
+	for k, v := range customHeaders {
+		headers[k] = v
+	}
+	if hasRequestID {
+		headers[RequestIDHeader] = requestID
+	}
+	for k, v := range requestHeaders {
+		headers[k] = v
+	}
+	return headers
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildRequestHeaders(t *testing.T) {
+	ctx := context.Background()
+	custom := map[string]string{"k1": "v1", RequestIDHeader: "static"}
+
+	t.Run("custom headers only", func(t *testing.T) {
+		assert.Equal(t, custom, buildRequestHeaders(ctx, custom, nil))
+		assert.Nil(t, buildRequestHeaders(ctx, nil, nil))
+	})
+
+	t.Run("request id from context", func(t *testing.T) {
+		_, ok := RequestIDFromContext(ctx)
+		assert.False(t, ok)
+
+		rctx := ContextWithRequestID(ctx, "req-1")
+		id, ok := RequestIDFromContext(rctx)
+		assert.True(t, ok)
+		assert.Equal(t, "req-1", id)
+
+		headers := buildRequestHeaders(rctx, custom, nil)
+		assert.Equal(t, map[string]string{"k1": "v1", RequestIDHeader: "req-1"}, headers)
+		// the custom headers of the config are not modified
+		assert.Equal(t, "static", custom[RequestIDHeader])
+
+		_, ok = RequestIDFromContext(ContextWithRequestID(ctx, ""))
+		assert.False(t, ok)
+	})
+
+	t.Run("request headers take precedence", func(t *testing.T) {
+		rctx := ContextWithRequestID(ctx, "req-1")
+		headers := buildRequestHeaders(rctx, custom, map[string]string{"k1": "v2", RequestIDHeader: "req-2", "X-User-ID": "u1"})
+		assert.Equal(t, map[string]string{"k1": "v2", RequestIDHeader: "req-2", "X-User-ID": "u1"}, headers)
+	})
+}
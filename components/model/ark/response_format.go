@@ -0,0 +1,323 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+)
+
+var responseFormatNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+var jsonSchemaTypes = map[string]bool{
+	"object":  true,
+	"array":   true,
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"boolean": true,
+	"null":    true,
+}
+
+// sanitizeResponseFormat validates the response format of the Responses API and returns a copy of it,
+// whose JSONSchema.Schema is normalized to a JSON object without the "$schema" and "$id" annotations,
+// which are removed from the nested schemas too, e.g. the ones of "$defs" and "items".
+// Invalid schemas are rejected here, instead of failing every request with an opaque 400 error.
+func sanitizeResponseFormat(rf *ResponseFormat) (*ResponseFormat, error) {
+	if rf == nil {
+		return nil, nil
+	}
+
+	switch rf.Type {
+	case "", arkModel.ResponseFormatText, arkModel.ResponseFormatJsonObject:
+		if rf.JSONSchema != nil {
+			return nil, fmt.Errorf("'ResponseFormat.JSONSchema' requires 'ResponseFormat.Type' to be %q, got %q",
+				arkModel.ResponseFormatJSONSchema, rf.Type)
+		}
+		return rf, nil
+	case arkModel.ResponseFormatJSONSchema:
+	default:
+		return nil, fmt.Errorf("unsupported 'ResponseFormat.Type': %q", rf.Type)
+	}
+
+	js := rf.JSONSchema
+	if js == nil {
+		return nil, fmt.Errorf("'ResponseFormat.JSONSchema' is required when 'ResponseFormat.Type' is %q", rf.Type)
+	}
+	if !responseFormatNamePattern.MatchString(js.Name) {
+		return nil, fmt.Errorf("invalid 'ResponseFormat.JSONSchema.Name' %q: must be 1 to 64 characters of a-z, A-Z, 0-9, '_' or '-'", js.Name)
+	}
+	if js.Schema == nil {
+		return nil, fmt.Errorf("'ResponseFormat.JSONSchema.Schema' is required")
+	}
+
+	root, err := normalizeJSONSchema(js.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid 'ResponseFormat.JSONSchema.Schema': %w", err)
+	}
+
+	v := &schemaValidator{root: root, strict: js.Strict}
+	if err = v.validate("#", root); err != nil {
+		return nil, fmt.Errorf("invalid 'ResponseFormat.JSONSchema.Schema': %w", err)
+	}
+
+	nJS := *js
+	nJS.Schema = root
+	return &ResponseFormat{
+		Type:       rf.Type,
+		JSONSchema: &nJS,
+	}, nil
+}
+
+// normalizeJSONSchema converts a schema given as a struct (e.g. *jsonschema.Schema), a map,
+// or raw JSON bytes or string into a generic JSON object.
+func normalizeJSONSchema(s interface{}) (map[string]interface{}, error) {
+	var b []byte
+	switch v := s.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		var err error
+		b, err = sonic.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("marshal schema fail: %w", err)
+		}
+	}
+
+	var root map[string]interface{}
+	if err := sonic.Unmarshal(b, &root); err != nil {
+		return nil, fmt.Errorf("schema must be a JSON object: %w", err)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("schema must be a JSON object")
+	}
+	return root, nil
+}
+
+type schemaValidator struct {
+	root   map[string]interface{}
+	strict bool
+}
+
+// validate checks the schema and its nested schemas, removing the annotations the endpoint rejects from each of them.
+func (v *schemaValidator) validate(path string, node map[string]interface{}) error {
+	delete(node, "$schema")
+	delete(node, "$id")
+
+	types, err := schemaTypes(path, node["type"])
+	if err != nil {
+		return err
+	}
+
+	if ref, ok := node["$ref"]; ok {
+		s, ok := ref.(string)
+		if !ok {
+			return fmt.Errorf("%s: '$ref' must be a string", path)
+		}
+		if _, err = v.resolveRef(s); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if enum, ok := node["enum"]; ok {
+		if list, ok := enum.([]interface{}); !ok || len(list) == 0 {
+			return fmt.Errorf("%s: 'enum' must be a non-empty array", path)
+		}
+	}
+
+	var properties map[string]interface{}
+	if p, ok := node["properties"]; ok {
+		if properties, ok = p.(map[string]interface{}); !ok {
+			return fmt.Errorf("%s: 'properties' must be an object", path)
+		}
+		for _, name := range sortedKeys(properties) {
+			if err = v.validateChild(path+"/properties/"+name, properties[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	required := map[string]bool{}
+	if r, ok := node["required"]; ok {
+		list, ok := r.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: 'required' must be an array of property names", path)
+		}
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("%s: 'required' must be an array of property names", path)
+			}
+			if _, found := properties[name]; !found {
+				return fmt.Errorf("%s: required property %q is not defined in 'properties'", path, name)
+			}
+			required[name] = true
+		}
+	}
+
+	if ap, ok := node["additionalProperties"]; ok {
+		switch a := ap.(type) {
+		case bool:
+		case map[string]interface{}:
+			if err = v.validate(path+"/additionalProperties", a); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: 'additionalProperties' must be a boolean or a schema", path)
+		}
+	}
+
+	if items, ok := node["items"]; ok {
+		switch it := items.(type) {
+		case map[string]interface{}:
+			if err = v.validate(path+"/items", it); err != nil {
+				return err
+			}
+		case []interface{}:
+			for i, item := range it {
+				if err = v.validateChild(path+"/items/"+strconv.Itoa(i), item); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("%s: 'items' must be a schema", path)
+		}
+	}
+
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		sub, ok := node[key]
+		if !ok {
+			continue
+		}
+		list, ok := sub.([]interface{})
+		if !ok || len(list) == 0 {
+			return fmt.Errorf("%s: '%s' must be a non-empty array of schemas", path, key)
+		}
+		for i, item := range list {
+			if err = v.validateChild(path+"/"+key+"/"+strconv.Itoa(i), item); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range []string{"$defs", "definitions"} {
+		sub, ok := node[key]
+		if !ok {
+			continue
+		}
+		defs, ok := sub.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: '%s' must be an object", path, key)
+		}
+		for _, name := range sortedKeys(defs) {
+			if err = v.validateChild(path+"/"+key+"/"+name, defs[name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if v.strict && (types["object"] || properties != nil) {
+		if ap, ok := node["additionalProperties"].(bool); !ok || ap {
+			return fmt.Errorf("%s: strict mode requires 'additionalProperties' to be false", path)
+		}
+		for _, name := range sortedKeys(properties) {
+			if !required[name] {
+				return fmt.Errorf("%s: strict mode requires every property to be required, %q is not; "+
+					"use a union type with \"null\" for optional fields", path, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *schemaValidator) validateChild(path string, child interface{}) error {
+	node, ok := child.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: must be a schema object", path)
+	}
+	return v.validate(path, node)
+}
+
+// resolveRef resolves a local reference such as "#/$defs/item" against the root schema.
+func (v *schemaValidator) resolveRef(ref string) (map[string]interface{}, error) {
+	if ref == "#" {
+		return v.root, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("only local '$ref' is supported, got %q", ref)
+	}
+
+	var cur interface{} = v.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable '$ref' %q", ref)
+		}
+		if cur, ok = m[token]; !ok {
+			return nil, fmt.Errorf("unresolvable '$ref' %q", ref)
+		}
+	}
+	node, ok := cur.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("'$ref' %q does not point to a schema", ref)
+	}
+	return node, nil
+}
+
+func schemaTypes(path string, t interface{}) (map[string]bool, error) {
+	types := map[string]bool{}
+	switch tv := t.(type) {
+	case nil:
+	case string:
+		types[tv] = true
+	case []interface{}:
+		for _, item := range tv {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: 'type' must be a string or an array of strings", path)
+			}
+			types[s] = true
+		}
+	default:
+		return nil, fmt.Errorf("%s: 'type' must be a string or an array of strings", path)
+	}
+	for typ := range types {
+		if !jsonSchemaTypes[typ] {
+			return nil, fmt.Errorf("%s: unknown type %q", path, typ)
+		}
+	}
+	return types, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"testing"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/stretchr/testify/assert"
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+)
+
+func TestSanitizeResponseFormat(t *testing.T) {
+	jsonSchemaFormat := func(name string, strict bool, s interface{}) *ResponseFormat {
+		return &ResponseFormat{
+			Type: arkModel.ResponseFormatJSONSchema,
+			JSONSchema: &arkModel.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:   name,
+				Schema: s,
+				Strict: strict,
+			},
+		}
+	}
+
+	t.Run("nil and plain formats", func(t *testing.T) {
+		rf, err := sanitizeResponseFormat(nil)
+		assert.NoError(t, err)
+		assert.Nil(t, rf)
+
+		in := &ResponseFormat{Type: arkModel.ResponseFormatJsonObject}
+		rf, err = sanitizeResponseFormat(in)
+		assert.NoError(t, err)
+		assert.Equal(t, in, rf)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := sanitizeResponseFormat(&ResponseFormat{Type: "xml"})
+		assert.ErrorContains(t, err, "unsupported 'ResponseFormat.Type'")
+
+		_, err = sanitizeResponseFormat(&ResponseFormat{
+			Type:       arkModel.ResponseFormatJsonObject,
+			JSONSchema: &arkModel.ResponseFormatJSONSchemaJSONSchemaParam{Name: "a"},
+		})
+		assert.ErrorContains(t, err, "requires 'ResponseFormat.Type'")
+
+		_, err = sanitizeResponseFormat(&ResponseFormat{Type: arkModel.ResponseFormatJSONSchema})
+		assert.ErrorContains(t, err, "'ResponseFormat.JSONSchema' is required")
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		s := map[string]interface{}{"type": "object"}
+		for _, name := range []string{"", "has space", "中文", string(make([]byte, 65))} {
+			_, err := sanitizeResponseFormat(jsonSchemaFormat(name, false, s))
+			assert.ErrorContains(t, err, "invalid 'ResponseFormat.JSONSchema.Name'")
+		}
+	})
+
+	t.Run("missing schema", func(t *testing.T) {
+		_, err := sanitizeResponseFormat(jsonSchemaFormat("answer", false, nil))
+		assert.ErrorContains(t, err, "'ResponseFormat.JSONSchema.Schema' is required")
+
+		_, err = sanitizeResponseFormat(jsonSchemaFormat("answer", false, "[1, 2]"))
+		assert.ErrorContains(t, err, "schema must be a JSON object")
+	})
+
+	t.Run("sanitize", func(t *testing.T) {
+		in := jsonSchemaFormat("answer", true, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"$id": "answer",
+			"type": "object",
+			"properties": {
+				"text": {"type": "string"},
+				"tags": {"type": "array", "items": {"$id": "tags", "$ref": "#/$defs/tag"}}
+			},
+			"required": ["text", "tags"],
+			"additionalProperties": false,
+			"$defs": {"tag": {"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "string", "enum": ["a", "b"]}}
+		}`)
+		rf, err := sanitizeResponseFormat(in)
+		assert.NoError(t, err)
+
+		root, ok := rf.JSONSchema.Schema.(map[string]interface{})
+		assert.True(t, ok)
+		assert.NotContains(t, root, "$schema")
+		assert.NotContains(t, root, "$id")
+		assert.Equal(t, "object", root["type"])
+		assert.Equal(t, map[string]interface{}{"$ref": "#/$defs/tag"},
+			root["properties"].(map[string]interface{})["tags"].(map[string]interface{})["items"])
+		assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"a", "b"}},
+			root["$defs"].(map[string]interface{})["tag"])
+		assert.Equal(t, "answer", rf.JSONSchema.Name)
+		assert.True(t, rf.JSONSchema.Strict)
+		// the config given by the user is not modified
+		assert.IsType(t, "", in.JSONSchema.Schema)
+	})
+
+	t.Run("jsonschema struct", func(t *testing.T) {
+		s := &jsonschema.Schema{
+			Type:     "object",
+			Required: []string{"text"},
+		}
+		s.Properties = jsonschema.NewProperties()
+		s.Properties.Set("text", &jsonschema.Schema{Type: "string"})
+
+		_, err := sanitizeResponseFormat(jsonSchemaFormat("answer", false, s))
+		assert.NoError(t, err)
+	})
+
+	t.Run("structural errors", func(t *testing.T) {
+		cases := []struct {
+			schema string
+			err    string
+		}{
+			{`{"type": "map"}`, `#: unknown type "map"`},
+			{`{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["b"]}`,
+				`#: required property "b" is not defined in 'properties'`},
+			{`{"type": "object", "properties": {"a": {"type": "array", "items": {"type": 1}}}}`,
+				`#/properties/a/items: 'type' must be a string`},
+			{`{"type": "object", "properties": {"a": {"$ref": "#/$defs/missing"}}}`,
+				`#/properties/a: unresolvable '$ref' "#/$defs/missing"`},
+			{`{"type": "object", "properties": {"a": {"$ref": "https://example.com/a.json"}}}`,
+				`only local '$ref' is supported`},
+			{`{"type": "string", "enum": []}`, `#: 'enum' must be a non-empty array`},
+			{`{"anyOf": [{"type": "string"}, true]}`, `#/anyOf/1: must be a schema object`},
+		}
+		for _, c := range cases {
+			_, err := sanitizeResponseFormat(jsonSchemaFormat("answer", false, c.schema))
+			assert.ErrorContains(t, err, c.err, c.schema)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		optional := `{"type": "object", "properties": {"a": {"type": "string"}, "b": {"type": "string"}},
+			"required": ["a"], "additionalProperties": false}`
+		_, err := sanitizeResponseFormat(jsonSchemaFormat("answer", false, optional))
+		assert.NoError(t, err)
+		_, err = sanitizeResponseFormat(jsonSchemaFormat("answer", true, optional))
+		assert.ErrorContains(t, err, `#: strict mode requires every property to be required, "b" is not`)
+
+		open := `{"type": "object", "properties": {"a": {"type": "object", "properties": {}}},
+			"required": ["a"], "additionalProperties": false}`
+		_, err = sanitizeResponseFormat(jsonSchemaFormat("answer", true, open))
+		assert.ErrorContains(t, err, `#/properties/a: strict mode requires 'additionalProperties' to be false`)
+	})
+}
@@ -27,6 +27,9 @@ import (
 	"time"
 
 	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino-ext/libs/multimodal"
+	"github.com/cloudwego/eino-ext/libs/pii"
+	"github.com/cloudwego/eino-ext/libs/stall"
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/model"
@@ -34,7 +37,7 @@ import (
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
 	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
-	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/utils"
+	"google.golang.org/protobuf/proto"
 )
 
 type Thinking = arkModel.Thinking
@@ -97,6 +100,9 @@ type ResponsesAPIConfig struct {
 	CustomHeader map[string]string `json:"custom_header"`
 
 	// ResponseFormat specifies the format that the model must output.
+	// JSONSchema is validated by NewResponsesAPIChatModel, its Schema can be a *jsonschema.Schema,
+	// a map or raw JSON. When Strict is true, every object must set "additionalProperties" to false
+	// and list all of its properties in "required".
 	// Optional.
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
 
@@ -113,11 +119,29 @@ type ResponsesAPIConfig struct {
 	// Optional.
 	ReasoningEffort *ReasoningEffort `json:"reasoning_effort,omitempty"`
 
+	// ReasoningSummary specifies how detailed the returned reasoning summary is, one of auto, concise, detailed and none.
+	// It controls how much thinking text is paid for and can be displayed.
+	// Can be overridden per request by WithReasoningSummary.
+	// Optional. Default: decided by the model
+	ReasoningSummary *ReasoningSummary `json:"reasoning_summary,omitempty"`
+
+	// TextVerbosity specifies how verbose the text output is, one of low, medium and high.
+	// Can be overridden per request by WithTextVerbosity.
+	// Optional. Default: decided by the model
+	TextVerbosity *TextVerbosity `json:"text_verbosity,omitempty"`
+
 	// SessionCache is the configuration of ResponsesAPI session cache.
 	// It can be overridden by [WithCache].
 	// Optional.
 	SessionCache *SessionCacheConfig `json:"session_cache,omitempty"`
 
+	// StoreResponses specifies whether Ark stores the inputs and responses server side, i.e. the store field of the requests,
+	// regardless of the session cache. Set it to false to guarantee that nothing is retained server side, e.g. for compliance.
+	// The session cache and CreatePrefixCache need the stored responses, so enabling them with it set to false fails with an error,
+	// while the previous response IDs given by the options can still be referenced.
+	// Optional. Default: stored only if the session cache is enabled
+	StoreResponses *bool `json:"store_responses,omitempty"`
+
 	// EnableToolWebSearch enables the web search tool.
 	// Web Search is a basic internet search tool that can obtain real-time public network information
 	// (such as news, products, weather, etc.) for your large model through the Responses API.
@@ -136,6 +160,62 @@ type ResponsesAPIConfig struct {
 	// For more details, see https://www.volcengine.com/docs/82379/1569618?lang=zh
 	// Optional.
 	MaxToolCalls *int64 `json:"max_tool_calls,omitempty"`
+
+	// StallTimeout specifies the maximum duration to wait for the next event in Stream.
+	// If no event arrives within the window, the upstream request is canceled
+	// and a *stall.Error is sent on the StreamReader.
+	// Optional. Default: no stall detection
+	StallTimeout time.Duration `json:"stall_timeout,omitempty"`
+
+	// MaxInputTokens enables a guard which estimates the input tokens before sending the request.
+	// If the estimate exceeds the limit, an *InputTooLargeError with a per-message breakdown is returned
+	// instead of sending an oversized request. Can be overridden per request by WithMaxInputTokens.
+	// Optional. Default: no guard
+	MaxInputTokens *int `json:"max_input_tokens,omitempty"`
+
+	// TokenEstimator estimates the input tokens of a message for MaxInputTokens.
+	// Optional. Default: tokenestimate.Message, a heuristic
+	TokenEstimator TokenEstimator `json:"-"`
+
+	// MediaLimits, if set, fails the input messages violating it before the request is sent, see multimodal.Limits.
+	// Optional. Default: no limits
+	MediaLimits *multimodal.Limits `json:"media_limits,omitempty"`
+
+	// Interceptor processes the input messages before they are sent and the output messages before they are returned,
+	// e.g. a *pii.Redactor replacing the personally identifiable information with placeholders restored in the response.
+	// The callbacks see the intercepted input and output messages.
+	// Optional. Default: none
+	Interceptor pii.Interceptor `json:"-"`
+
+	// EnableCallbacks, see ChatModelConfig.EnableCallbacks.
+	// Optional. Default: true
+	EnableCallbacks *bool `json:"enable_callbacks,omitempty"`
+
+	// CancelOnContextDone cancels the in-flight response of a stream with the cancel endpoint of the Responses API
+	// when the context of Stream is done, instead of only closing the connection,
+	// so that Ark stops the generation and its billing sooner. The cancellation is best effort, its errors are ignored.
+	// Optional. Default: true
+	CancelOnContextDone *bool `json:"cancel_on_context_done,omitempty"`
+
+	// Client sends the requests to the Responses API instead of the Ark SDK client,
+	// e.g. a mock in tests or a wrapper injecting faults. The credentials are not required if it is set,
+	// and the connection fields (Timeout, HTTPClient, RetryTimes, BaseURL and Region) are ignored.
+	// The request fields not supported by the Ark SDK, e.g. the reasoning summary, are only sent by the Ark SDK client.
+	// Implement ResponsesStoreClient to support FetchConversation, and ResponsesCancelClient to support Cancel.
+	// Optional.
+	Client ResponsesClient `json:"-"`
+
+	// UnsupportedFields maps the models, i.e. the model names or the endpoint IDs set by Model or model.WithModel,
+	// to the optional request fields they do not support. The fields are dropped from the requests to these models
+	// instead of failing with a 400 error, e.g. when switching the model for an A/B test,
+	// and reported in the callback Extra with CallbackExtraKeyDroppedFields.
+	// Optional. Default: all the fields are sent
+	UnsupportedFields map[string][]RequestField `json:"unsupported_fields,omitempty"`
+
+	// Failover fails the requests over to a backup endpoint on the connection errors
+	// of the primary endpoint given by BaseURL and Region, using the same credentials. Ignored if Client is set.
+	// Optional. Default: no failover
+	Failover *FailoverConfig `json:"failover,omitempty"`
 }
 
 func NewResponsesAPIChatModel(_ context.Context, config *ResponsesAPIConfig) (*ResponsesAPIChatModel, error) {
@@ -143,62 +223,115 @@ func NewResponsesAPIChatModel(_ context.Context, config *ResponsesAPIConfig) (*R
 		return nil, fmt.Errorf("config cannot be nil")
 	}
 
-	var opts []arkruntime.ConfigOption
-
-	if config.Region == "" {
-		opts = append(opts, arkruntime.WithRegion(defaultRegion))
-	} else {
-		opts = append(opts, arkruntime.WithRegion(config.Region))
-	}
-	if config.Timeout != nil {
-		opts = append(opts, arkruntime.WithTimeout(*config.Timeout))
+	responseFormat, err := sanitizeResponseFormat(config.ResponseFormat)
+	if err != nil {
+		return nil, err
 	}
-	if config.HTTPClient != nil {
-		opts = append(opts, arkruntime.WithHTTPClient(config.HTTPClient))
+
+	if config.APIKey == "" && (config.AccessKey == "" || config.SecretKey == "") && config.Client == nil {
+		return nil, fmt.Errorf("new client fail, missing credentials: set 'APIKey' or both 'AccessKey' and 'SecretKey'")
 	}
-	if config.BaseURL != "" {
-		opts = append(opts, arkruntime.WithBaseUrl(config.BaseURL))
-	} else {
-		opts = append(opts, arkruntime.WithBaseUrl(defaultBaseURL))
+
+	if config.StoreResponses != nil && !*config.StoreResponses && config.SessionCache != nil && config.SessionCache.EnableCache {
+		return nil, errStoreDisabledCache
 	}
-	if config.RetryTimes != nil {
-		opts = append(opts, arkruntime.WithRetryTimes(*config.RetryTimes))
-	} else {
-		opts = append(opts, arkruntime.WithRetryTimes(defaultRetryTimes))
+
+	var connectTimeout time.Duration
+	if config.Client == nil && config.Failover != nil {
+		connectTimeout = config.Failover.ConnectTimeout
+		if connectTimeout <= 0 {
+			connectTimeout = defaultFailoverConnectTimeout
+		}
 	}
+	client := newResponsesArkClient(config, config.Region, config.BaseURL, connectTimeout)
 
-	var client *arkruntime.Client
-	if len(config.APIKey) > 0 {
-		client = arkruntime.NewClientWithApiKey(config.APIKey, opts...)
-	} else if config.AccessKey != "" && config.SecretKey != "" {
-		client = arkruntime.NewClientWithAkSk(config.AccessKey, config.SecretKey, opts...)
-	} else {
-		return nil, fmt.Errorf("new client fail, missing credentials: set 'APIKey' or both 'AccessKey' and 'SecretKey'")
+	injectedClient := config.Client
+	if injectedClient == nil && client != nil && config.Failover != nil {
+		region, baseURL := config.Failover.Region, config.Failover.BaseURL
+		if region == "" {
+			region = config.Region
+		}
+		if baseURL == "" {
+			baseURL = config.BaseURL
+		}
+		backup := newResponsesArkClient(config, region, baseURL, 0)
+		injectedClient = newFailoverResponsesClient(arkResponsesClient{client: client, baseURL: responsesBaseURL(config.BaseURL)},
+			arkResponsesClient{client: backup, baseURL: responsesBaseURL(baseURL)}, config.Failover)
 	}
 
 	return &ResponsesAPIChatModel{
 		client:          client,
+		injectedClient:  injectedClient,
 		model:           config.Model,
 		maxTokens:       config.MaxOutputTokens,
 		temperature:     config.Temperature,
 		topP:            config.TopP,
 		customHeader:    config.CustomHeader,
-		responseFormat:  config.ResponseFormat,
+		responseFormat:  responseFormat,
 		thinking:        config.Thinking,
 		cache:           &CacheConfig{SessionCache: config.SessionCache},
 		serviceTier:     config.ServiceTier,
 		reasoningEffort: config.ReasoningEffort,
 
+		reasoningSummary: config.ReasoningSummary,
+		textVerbosity:    config.TextVerbosity,
+
 		enableToolWebSearch: config.EnableToolWebSearch,
 		maxToolCalls:        config.MaxToolCalls,
+		stallTimeout:        config.StallTimeout,
+		maxInputTokens:      config.MaxInputTokens,
+		tokenEstimator:      config.TokenEstimator,
+		mediaLimits:         config.MediaLimits,
+		interceptor:         config.Interceptor,
+		unsupportedFields:   config.UnsupportedFields,
+		disableCallbacks:    !callbacksEnabled(config.EnableCallbacks),
+		baseURL:             responsesBaseURL(config.BaseURL),
+		cancelOnCtxDone:     config.CancelOnContextDone == nil || *config.CancelOnContextDone,
+		storeResponses:      config.StoreResponses,
 	}, nil
 }
 
+// newResponsesArkClient builds the Ark SDK client of the endpoint given by region and baseURL,
+// nil if the config has no credentials. A positive connectTimeout bounds the time to connect to the endpoint.
+func newResponsesArkClient(config *ResponsesAPIConfig, region, baseURL string, connectTimeout time.Duration) *arkruntime.Client {
+	var opts []arkruntime.ConfigOption
+
+	if region == "" {
+		opts = append(opts, arkruntime.WithRegion(defaultRegion))
+	} else {
+		opts = append(opts, arkruntime.WithRegion(region))
+	}
+	// the request fields not supported by the ark sdk, e.g. the reasoning summary, are merged into the body by the transport
+	httpClient := newExtraBodyHTTPClient(config.HTTPClient, config.Timeout)
+	if connectTimeout > 0 {
+		setConnectTimeout(httpClient, connectTimeout)
+	}
+	opts = append(opts, arkruntime.WithHTTPClient(httpClient))
+	opts = append(opts, arkruntime.WithBaseUrl(responsesBaseURL(baseURL)))
+	if config.RetryTimes != nil {
+		opts = append(opts, arkruntime.WithRetryTimes(*config.RetryTimes))
+	} else {
+		opts = append(opts, arkruntime.WithRetryTimes(defaultRetryTimes))
+	}
+
+	if len(config.APIKey) > 0 {
+		return arkruntime.NewClientWithApiKey(config.APIKey, opts...)
+	}
+	if config.AccessKey != "" && config.SecretKey != "" {
+		return arkruntime.NewClientWithAkSk(config.AccessKey, config.SecretKey, opts...)
+	}
+	return nil
+}
+
+// ResponsesAPIChatModel is a chat model of the Ark Responses API.
+// It is safe for concurrent use: the model is not modified after creation, WithTools returns a new model,
+// and every Generate, Stream and BuildRequestOnly call builds its request from its own copies of the config.
 type ResponsesAPIChatModel struct {
-	client     *arkruntime.Client
-	tools      []*responses.ResponsesTool
-	rawTools   []*schema.ToolInfo
-	toolChoice *schema.ToolChoice
+	client         *arkruntime.Client
+	injectedClient ResponsesClient
+	tools          []*responses.ResponsesTool
+	rawTools       []*schema.ToolInfo
+	toolChoice     *schema.ToolChoice
 
 	model           string
 	maxTokens       *int
@@ -211,13 +344,38 @@ type ResponsesAPIChatModel struct {
 	serviceTier     *string
 	reasoningEffort *arkModel.ReasoningEffort
 
+	reasoningSummary *ReasoningSummary
+	textVerbosity    *TextVerbosity
+
 	enableToolWebSearch *ToolWebSearch
 
 	maxToolCalls *int64
+
+	stallTimeout time.Duration
+
+	maxInputTokens *int
+	tokenEstimator TokenEstimator
+
+	mediaLimits *multimodal.Limits
+	interceptor pii.Interceptor
+
+	unsupportedFields map[string][]RequestField
+
+	disableCallbacks bool
+
+	baseURL         string
+	cancelOnCtxDone bool
+
+	storeResponses *bool
 }
+
+var errStoreDisabledCache = errors.New("the session cache requires the responses stored by Ark, but StoreResponses is false")
+
 type cacheConfig struct {
 	Enabled  bool
 	ExpireAt *int64
+	// PrevResponseIDChain is the response ID chain ending with the PreviousResponseId of the request.
+	PrevResponseIDChain []string
 }
 
 func (cm *ResponsesAPIChatModel) GetType() string {
@@ -228,49 +386,45 @@ func (cm *ResponsesAPIChatModel) Generate(ctx context.Context, input []*schema.M
 	opts ...model.Option) (outMsg *schema.Message, err error) {
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
 
-	options, specOptions, err := cm.getOptions(opts)
+	call, err := cm.buildRequest(ctx, input, opts, true)
 	if err != nil {
 		return nil, err
 	}
-
-	responseReq, err := cm.genRequestAndOptions(input, options, specOptions)
-	if err != nil {
-		return nil, fmt.Errorf("genRequestAndOptions failed: %w", err)
-	}
+	defer func() {
+		if err == nil {
+			outMsg = call.restorer.Restore(outMsg)
+		}
+	}()
+	input, options, specOptions, responseReq := call.input, call.options, call.specOptions, call.req
 	config := cm.toCallbackConfig(responseReq)
-
-	tools := cm.rawTools
-	if options.Tools != nil {
-		tools = options.Tools
-	}
-
-	callbackExtra := map[string]any{
-		callbackExtraKeyThinking: specOptions.thinking,
-	}
-	if responseReq.PreviousResponseId != nil {
-		callbackExtra[callbackExtraKeyPreResponseID] = *responseReq.PreviousResponseId
+	callbackExtra := call.callbackExtra()
+
+	if !cm.disableCallbacks {
+		ctx = callbacks.OnStart(ctx, &model.CallbackInput{
+			Messages:   input,
+			Tools:      call.tools(cm.rawTools),
+			ToolChoice: options.ToolChoice,
+			Config:     config,
+			Extra:      callbackExtra,
+		})
 	}
 
-	ctx = callbacks.OnStart(ctx, &model.CallbackInput{
-		Messages:   input,
-		Tools:      tools,
-		ToolChoice: options.ToolChoice,
-		Config:     config,
-		Extra:      callbackExtra,
-	})
-
 	defer func() {
-		if err != nil {
+		if err != nil && !cm.disableCallbacks {
 			callbacks.OnError(ctx, err)
 		}
 	}()
 
-	responseObject, err := cm.client.CreateResponses(ctx, responseReq, arkruntime.WithCustomHeaders(specOptions.customHeaders))
+	headers := buildRequestHeaders(ctx, specOptions.customHeaders, specOptions.requestHeaders)
+	reqCtx := withExtraBodyFields(ctx, extraResponsesBodyFields(specOptions))
+	responseObject, err := cm.responsesClient().CreateResponses(reqCtx, responseReq, headers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create responses: %w", err)
+		return nil, fmt.Errorf("failed to create responses: %w", convContentFilterError(err))
 	}
 
-	cacheCfg := &cacheConfig{}
+	cacheCfg := &cacheConfig{
+		PrevResponseIDChain: getPrevResponseIDChain(input, responseReq.PreviousResponseId),
+	}
 	if responseReq.Caching != nil && responseReq.Caching.Type != nil {
 		cacheCfg.Enabled = *responseReq.Caching.Type == responses.CacheType_enabled
 		cacheCfg.ExpireAt = responseReq.ExpireAt
@@ -283,12 +437,14 @@ func (cm *ResponsesAPIChatModel) Generate(ctx context.Context, input []*schema.M
 
 	callbackExtra[callbackExtraModelName] = responseObject.Model
 
-	callbacks.OnEnd(ctx, &model.CallbackOutput{
-		Message:    outMsg,
-		Config:     config,
-		TokenUsage: cm.toModelTokenUsage(responseObject.Usage),
-		Extra:      callbackExtra,
-	})
+	if !cm.disableCallbacks {
+		callbacks.OnEnd(ctx, &model.CallbackOutput{
+			Message:    outMsg,
+			Config:     config,
+			TokenUsage: cm.toModelTokenUsage(responseObject.Usage),
+			Extra:      callbackExtra,
+		})
+	}
 	return outMsg, nil
 
 }
@@ -296,48 +452,46 @@ func (cm *ResponsesAPIChatModel) Generate(ctx context.Context, input []*schema.M
 func (cm *ResponsesAPIChatModel) Stream(ctx context.Context, input []*schema.Message,
 	opts ...model.Option) (outStream *schema.StreamReader[*schema.Message], err error) {
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
-	options, specOptions, err := cm.getOptions(opts)
-	if err != nil {
-		return nil, err
-	}
 
-	responseReq, err := cm.genRequestAndOptions(input, options, specOptions)
+	call, err := cm.buildRequest(ctx, input, opts, true)
 	if err != nil {
-		return nil, fmt.Errorf("genRequestAndOptions failed: %w", err)
+		return nil, err
 	}
+	defer func() {
+		if err == nil {
+			outStream = call.restorer.RestoreStream(outStream)
+		}
+	}()
+	input, options, specOptions, responseReq := call.input, call.options, call.specOptions, call.req
 	config := cm.toCallbackConfig(responseReq)
-	tools := cm.rawTools
-	if options.Tools != nil {
-		tools = options.Tools
-	}
-
-	callbackExtra := map[string]any{
-		callbackExtraKeyThinking: specOptions.thinking,
-	}
-	if responseReq.PreviousResponseId != nil {
-		callbackExtra[callbackExtraKeyPreResponseID] = *responseReq.PreviousResponseId
+	callbackExtra := call.callbackExtra()
+
+	if !cm.disableCallbacks {
+		ctx = callbacks.OnStart(ctx, &model.CallbackInput{
+			Messages:   input,
+			Tools:      call.tools(cm.rawTools),
+			ToolChoice: options.ToolChoice,
+			Config:     config,
+			Extra:      callbackExtra,
+		})
 	}
 
-	ctx = callbacks.OnStart(ctx, &model.CallbackInput{
-		Messages:   input,
-		Tools:      tools,
-		ToolChoice: options.ToolChoice,
-		Config:     config,
-		Extra:      callbackExtra,
-	})
-
 	defer func() {
-		if err != nil {
+		if err != nil && !cm.disableCallbacks {
 			callbacks.OnError(ctx, err)
 		}
 	}()
 
-	responseStreamReader, err := cm.client.CreateResponsesStream(ctx, responseReq, arkruntime.WithCustomHeaders(specOptions.customHeaders))
+	streamCtx, watcher, stopWatcher := stall.NewWatcher(withExtraBodyFields(ctx, extraResponsesBodyFields(specOptions)), "ark", cm.stallTimeout)
+	headers := buildRequestHeaders(ctx, specOptions.customHeaders, specOptions.requestHeaders)
+	responseStreamReader, err := cm.responsesClient().CreateResponsesStream(streamCtx, responseReq, headers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create responses: %w", err)
+		stopWatcher()
+		return nil, fmt.Errorf("failed to create responses: %w", convContentFilterError(watcher.WrapErr(err)))
 	}
 
 	sr, sw := schema.Pipe[*model.CallbackOutput](1)
+	inFlight := &inFlightStream{ResponsesStreamReader: responseStreamReader}
 
 	go func() {
 		defer func() {
@@ -347,27 +501,41 @@ func (cm *ResponsesAPIChatModel) Stream(ctx context.Context, input []*schema.Mes
 			}
 
 			_ = responseStreamReader.Close()
+			stopWatcher()
 			sw.Close()
+
+			if cm.cancelOnCtxDone && ctx.Err() != nil {
+				cm.cancelInFlight(inFlight, headers)
+			}
 		}()
 
-		var cacheCfg = &cacheConfig{}
+		var cacheCfg = &cacheConfig{
+			PrevResponseIDChain: getPrevResponseIDChain(input, responseReq.PreviousResponseId),
+		}
 		if responseReq.Caching != nil && responseReq.Caching.Type != nil {
 			cacheCfg.Enabled = *responseReq.Caching.Type == responses.CacheType_enabled
 			cacheCfg.ExpireAt = responseReq.ExpireAt
 		}
 
-		cm.receivedStreamResponse(responseStreamReader, config, cacheCfg, sw)
+		cm.receivedStreamResponse(inFlight, watcher, config, cacheCfg, sw)
 
 	}()
 
-	ctx, nsr := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderWithConvert(sr,
+	nsr := schema.StreamReaderWithConvert(sr,
 		func(src *model.CallbackOutput) (callbacks.CallbackOutput, error) {
 			if src.Extra == nil {
 				src.Extra = make(map[string]any)
 			}
 			src.Extra[callbackExtraKeyThinking] = specOptions.thinking
+			src.Extra[callbackExtraKeyThinkingConfig] = thinkingExtra(specOptions.thinking)
+			if len(call.droppedFields) > 0 {
+				src.Extra[CallbackExtraKeyDroppedFields] = call.droppedFields
+			}
 			return src, nil
-		}))
+		})
+	if !cm.disableCallbacks {
+		_, nsr = callbacks.OnEndWithStreamOutput(ctx, nsr)
+	}
 
 	outStream = schema.StreamReaderWithConvert(nsr,
 		func(src callbacks.CallbackOutput) (*schema.Message, error) {
@@ -382,8 +550,91 @@ func (cm *ResponsesAPIChatModel) Stream(ctx context.Context, input []*schema.Mes
 	return outStream, err
 }
 
+// BuildRequestOnly builds the request Generate and Stream would send for the input and options, without sending it.
+// It is a debugging aid, e.g. to snapshot the requests in tests and catch conversion changes across upgrades.
+// The request is built as for a real call, the Interceptor is applied and the unsupported fields are dropped,
+// except that it makes no network call: an expiring session cache is not renewed by AutoSummary,
+// so the request keeps referring to the cached response.
+// The extra body fields such as text verbosity, which are not part of responses.ResponsesRequest, are not included.
+func (cm *ResponsesAPIChatModel) BuildRequestOnly(ctx context.Context, input []*schema.Message,
+	opts ...model.Option) (*responses.ResponsesRequest, error) {
+	call, err := cm.buildRequest(ctx, input, opts, false)
+	if err != nil {
+		return nil, err
+	}
+	return call.req, nil
+}
+
+// responsesCall is the request of a Generate, Stream or BuildRequestOnly call with the input and options it is built from.
+type responsesCall struct {
+	// input is the input after the Interceptor, reported by the callbacks.
+	input         []*schema.Message
+	restorer      pii.Restorer
+	options       *model.Options
+	specOptions   *arkOptions
+	req           *responses.ResponsesRequest
+	droppedFields []RequestField
+}
+
+// buildRequest applies the Interceptor to the input, renews the expiring session cache if renewCache,
+// and builds the request without the fields unsupported by the model.
+func (cm *ResponsesAPIChatModel) buildRequest(ctx context.Context, input []*schema.Message,
+	opts []model.Option, renewCache bool) (*responsesCall, error) {
+	input, restorer, err := pii.Apply(ctx, cm.interceptor, input)
+	if err != nil {
+		return nil, err
+	}
+
+	options, specOptions, err := cm.getOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reqInput := input
+	if renewCache {
+		if reqInput, specOptions, err = cm.renewSessionCache(ctx, input, specOptions); err != nil {
+			return nil, err
+		}
+	}
+	responseReq, err := cm.genRequestAndOptions(reqInput, options, specOptions)
+	if err != nil {
+		return nil, fmt.Errorf("genRequestAndOptions failed: %w", err)
+	}
+	return &responsesCall{
+		input:         input,
+		restorer:      restorer,
+		options:       options,
+		specOptions:   specOptions,
+		req:           responseReq,
+		droppedFields: dropUnsupportedResponsesFields(cm.unsupportedFields, responseReq, specOptions),
+	}, nil
+}
+
+// tools returns the tools of the call, defaulting to the tools bound to the model.
+func (c *responsesCall) tools(bound []*schema.ToolInfo) []*schema.ToolInfo {
+	if c.options.Tools != nil {
+		return c.options.Tools
+	}
+	return bound
+}
+
+// callbackExtra returns the extra of the callback input of the call.
+func (c *responsesCall) callbackExtra() map[string]any {
+	extra := map[string]any{
+		callbackExtraKeyThinking:       c.specOptions.thinking,
+		callbackExtraKeyThinkingConfig: thinkingExtra(c.specOptions.thinking),
+	}
+	if c.req.PreviousResponseId != nil {
+		extra[callbackExtraKeyPreResponseID] = *c.req.PreviousResponseId
+	}
+	if len(c.droppedFields) > 0 {
+		extra[CallbackExtraKeyDroppedFields] = c.droppedFields
+	}
+	return extra
+}
+
 func (cm *ResponsesAPIChatModel) IsCallbacksEnabled() bool {
-	return true
+	return !cm.disableCallbacks
 }
 
 func (cm *ResponsesAPIChatModel) prePopulateConfig(responseReq *responses.ResponsesRequest, options *model.Options,
@@ -404,8 +655,8 @@ func (cm *ResponsesAPIChatModel) prePopulateConfig(responseReq *responses.Respon
 			}
 			textFormat.Format.Schema = &responses.Bytes{Value: b}
 			textFormat.Format.Name = cm.responseFormat.JSONSchema.Name
-			textFormat.Format.Description = &cm.responseFormat.JSONSchema.Description
-			textFormat.Format.Strict = &cm.responseFormat.JSONSchema.Strict
+			textFormat.Format.Description = ptrOf(cm.responseFormat.JSONSchema.Description)
+			textFormat.Format.Strict = ptrOf(cm.responseFormat.JSONSchema.Strict)
 		default:
 			return fmt.Errorf("unsupported response format type: %s", cm.responseFormat.Type)
 		}
@@ -478,8 +729,25 @@ func (cm *ResponsesAPIChatModel) prePopulateConfig(responseReq *responses.Respon
 	return nil
 }
 
+// extraResponsesBodyFields returns the request fields not supported by the ark sdk,
+// which are merged into the request body by the transport of the client.
+func extraResponsesBodyFields(specOptions *arkOptions) map[string]any {
+	fields := make(map[string]any)
+	if specOptions.reasoningSummary != nil {
+		fields["reasoning"] = map[string]any{"summary": string(*specOptions.reasoningSummary)}
+	}
+	if specOptions.textVerbosity != nil {
+		fields["text"] = map[string]any{"verbosity": string(*specOptions.textVerbosity)}
+	}
+	return fields
+}
+
 func (cm *ResponsesAPIChatModel) genRequestAndOptions(in []*schema.Message, options *model.Options,
 	specOptions *arkOptions) (responseReq *responses.ResponsesRequest, err error) {
+	if err = cm.mediaLimits.ValidateMessages(in); err != nil {
+		return nil, err
+	}
+
 	responseReq = &responses.ResponsesRequest{}
 
 	err = cm.prePopulateConfig(responseReq, options, specOptions)
@@ -502,6 +770,13 @@ func (cm *ResponsesAPIChatModel) genRequestAndOptions(in []*schema.Message, opti
 		return nil, err
 	}
 
+	if specOptions.maxInputTokens != nil {
+		err = checkInputTokens(in, responseReq.Tools, *specOptions.maxInputTokens, cm.tokenEstimator)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return responseReq, nil
 
 }
@@ -556,7 +831,7 @@ func (cm *ResponsesAPIChatModel) populateCache(in []*schema.Message, responseReq
 	// ContextID and ResponseID will exist at the same time.
 	// Using ContextID is prioritized to maintain compatibility with the old logic.
 	// In this usage scenario, ResponseID cannot be used.
-	if cacheStatus == cachingEnabled && contextID == nil {
+	if cacheStatus == cachingEnabled && contextID == nil && arkOpts.previousResponseID == nil {
 		for i := len(in) - 1; i >= 0; i-- {
 			msg := in[i]
 			inputIdx = i
@@ -585,6 +860,17 @@ func (cm *ResponsesAPIChatModel) populateCache(in []*schema.Message, responseReq
 		}
 	}
 
+	if arkOpts.previousResponseID != nil {
+		preRespID = arkOpts.previousResponseID
+	}
+
+	if cm.storeResponses != nil {
+		if !*cm.storeResponses && cacheStatus == cachingEnabled {
+			return in, errStoreDisabledCache
+		}
+		store = *cm.storeResponses
+	}
+
 	responseReq.PreviousResponseId = preRespID
 	responseReq.Store = &store
 
@@ -606,6 +892,20 @@ func (cm *ResponsesAPIChatModel) populateCache(in []*schema.Message, responseReq
 	return in, nil
 }
 
+// getPrevResponseIDChain returns the response ID chain ending with preRespID.
+// The chain is inherited from the input message carrying preRespID if there is one.
+func getPrevResponseIDChain(in []*schema.Message, preRespID *string) []string {
+	if preRespID == nil || *preRespID == "" {
+		return nil
+	}
+	for i := len(in) - 1; i >= 0; i-- {
+		if id, ok := GetResponseID(in[i]); ok && id == *preRespID {
+			return GetResponseIDChain(in[i])
+		}
+	}
+	return []string{*preRespID}
+}
+
 func (cm *ResponsesAPIChatModel) populateInput(in []*schema.Message, responseReq *responses.ResponsesRequest) error {
 	itemList := make([]*responses.InputItem, 0, len(in))
 	if len(in) == 0 {
@@ -676,7 +976,8 @@ func (cm *ResponsesAPIChatModel) populateTools(responseReq *responses.ResponsesR
 	if responseReq.PreviousResponseId != nil {
 		return nil
 	}
-	tools := cm.tools
+	// the tools bound to the model are shared by the concurrent calls, so the request gets its own copies
+	tools := cloneResponsesTools(cm.tools)
 	if options.Tools != nil {
 		var err error
 		if tools, err = cm.toTools(options.Tools); err != nil {
@@ -711,6 +1012,18 @@ func (cm *ResponsesAPIChatModel) populateTools(responseReq *responses.ResponsesR
 	return nil
 }
 
+// cloneResponsesTools deep copies the tools, so that the request can be modified without affecting the model.
+func cloneResponsesTools(tools []*responses.ResponsesTool) []*responses.ResponsesTool {
+	if tools == nil {
+		return nil
+	}
+	cloned := make([]*responses.ResponsesTool, len(tools))
+	for i, t := range tools {
+		cloned[i] = proto.Clone(t).(*responses.ResponsesTool)
+	}
+	return cloned
+}
+
 func convToolWebSearch(enableToolWebSearch *ToolWebSearch) (*responses.ToolWebSearch, error) {
 	tl := &responses.ToolWebSearch{
 		Type:       responses.ToolType_web_search,
@@ -893,7 +1206,7 @@ func (cm *ResponsesAPIChatModel) getOptions(opts []model.Option) (*model.Options
 	options := model.GetCommonOptions(&model.Options{
 		Temperature: cm.temperature,
 		MaxTokens:   cm.maxTokens,
-		Model:       &cm.model,
+		Model:       ptrOf(cm.model),
 		TopP:        cm.topP,
 		ToolChoice:  cm.toolChoice,
 	}, opts...)
@@ -904,6 +1217,10 @@ func (cm *ResponsesAPIChatModel) getOptions(opts []model.Option) (*model.Options
 		reasoningEffort: cm.reasoningEffort,
 		enableWebSearch: cm.enableToolWebSearch,
 		maxToolCalls:    cm.maxToolCalls,
+		maxInputTokens:  cm.maxInputTokens,
+
+		reasoningSummary: cm.reasoningSummary,
+		textVerbosity:    cm.textVerbosity,
 	}, opts...)
 
 	if err := cm.checkOptions(options, arkOpts); err != nil {
@@ -961,18 +1278,31 @@ func (cm *ResponsesAPIChatModel) toOutputMessage(resp *responses.ResponseObject,
 	}
 	setContextID(msg, resp.Id)
 	setResponseID(msg, resp.Id)
+	if cache != nil {
+		setResponseIDChain(msg, cache.PrevResponseIDChain, resp.Id)
+	}
 
 	if resp.ServiceTier != nil {
 		setServiceTier(msg, resp.ServiceTier.String())
 	}
 
 	if resp.Status == responses.ResponseStatus_failed {
-		msg.ResponseMeta.FinishReason = resp.Error.Message
+		if cfErr := newContentFilteredErrorFromResponse(resp.Error); cfErr != nil {
+			return nil, cfErr
+		}
+		msg.ResponseMeta.FinishReason = FinishReasonFailed
+		setResponseError(msg, toResponseError(resp.Error))
 		return msg, nil
 	}
 
 	if resp.Status == responses.ResponseStatus_incomplete {
-		msg.ResponseMeta.FinishReason = resp.IncompleteDetails.Reason
+		finishReason, cfDetail := getIncompleteFinishReason(resp.IncompleteDetails)
+		if finishReason == "" {
+			finishReason = FinishReasonIncomplete
+		}
+		msg.ResponseMeta.FinishReason = finishReason
+		setContentFilterDetail(msg, cfDetail)
+		setIncompleteDetails(msg, toIncompleteDetails(resp.IncompleteDetails))
 		return msg, nil
 	}
 
@@ -991,6 +1321,7 @@ func (cm *ResponsesAPIChatModel) toOutputMessage(resp *responses.ResponseObject,
 				if content.GetText() == nil {
 					continue
 				}
+				addAnnotations(msg, content.GetText().GetAnnotations())
 				if !isMultiContent {
 					msg.Content = content.GetText().GetText()
 				} else {
@@ -1035,6 +1366,9 @@ func (cm *ResponsesAPIChatModel) toOutputMessage(resp *responses.ResponseObject,
 }
 
 func (cm *ResponsesAPIChatModel) toEinoTokenUsage(usage *responses.Usage) *schema.TokenUsage {
+	if usage == nil {
+		return nil
+	}
 	tokenUsage := &schema.TokenUsage{
 		PromptTokens:     int(usage.InputTokens),
 		CompletionTokens: int(usage.OutputTokens),
@@ -1051,6 +1385,9 @@ func (cm *ResponsesAPIChatModel) toEinoTokenUsage(usage *responses.Usage) *schem
 }
 
 func (cm *ResponsesAPIChatModel) toModelTokenUsage(usage *responses.Usage) *model.TokenUsage {
+	if usage == nil {
+		return nil
+	}
 	tokenUsage := &model.TokenUsage{
 		PromptTokens:     int(usage.InputTokens),
 		CompletionTokens: int(usage.OutputTokens),
@@ -1083,17 +1420,20 @@ func (cm *ResponsesAPIChatModel) toCallbackConfig(req *responses.ResponsesReques
 	}
 }
 
-func (cm *ResponsesAPIChatModel) receivedStreamResponse(streamReader *utils.ResponsesStreamReader,
+func (cm *ResponsesAPIChatModel) receivedStreamResponse(streamReader ResponsesStreamReader, watcher *stall.Watcher,
 	config *model.Config, cacheConfig *cacheConfig, sw *schema.StreamWriter[*model.CallbackOutput]) {
-	var itemFunctionToolCall *responses.ItemFunctionToolCall
+	// parallel function calls may stream their arguments interleaved, so the calls are tracked by item id
+	toolCalls := make(map[string]*streamToolCall)
 
 	for {
+		watcher.Arm()
 		event, err := streamReader.Recv()
+		watcher.Disarm()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
 				return
 			}
-			_ = sw.Send(nil, fmt.Errorf("failed to read stream: %w", err))
+			_ = sw.Send(nil, fmt.Errorf("failed to read stream: %w", watcher.WrapErr(err)))
 			return
 		}
 
@@ -1115,20 +1455,26 @@ func (cm *ResponsesAPIChatModel) receivedStreamResponse(streamReader *utils.Resp
 			cm.sendCallbackOutput(sw, config, ev.ResponseCompleted.Response.Model, msg)
 
 		case *responses.Event_Error:
+			if ev.Error != nil && isContentFilterCode(ev.Error.GetCode()) {
+				sw.Send(nil, &ContentFilteredError{Code: ev.Error.GetCode(), Message: ev.Error.Message})
+				continue
+			}
 			sw.Send(nil, fmt.Errorf("received error: %s", ev.Error.Message))
 
 		case *responses.Event_ResponseIncomplete:
 			if ev.ResponseIncomplete == nil || ev.ResponseIncomplete.Response == nil || ev.ResponseIncomplete.Response.IncompleteDetails == nil {
 				continue
 			}
-			detail := ev.ResponseIncomplete.Response.IncompleteDetails.Reason
+			finishReason, cfDetail := getIncompleteFinishReason(ev.ResponseIncomplete.Response.IncompleteDetails)
 			msg := &schema.Message{
 				Role: schema.Assistant,
 				ResponseMeta: &schema.ResponseMeta{
-					FinishReason: detail,
+					FinishReason: finishReason,
 					Usage:        cm.toEinoTokenUsage(ev.ResponseIncomplete.Response.Usage),
 				},
 			}
+			setContentFilterDetail(msg, cfDetail)
+			setIncompleteDetails(msg, toIncompleteDetails(ev.ResponseIncomplete.Response.IncompleteDetails))
 			cm.setStreamChunkDefaultExtra(msg, ev.ResponseIncomplete.Response, cacheConfig)
 			cm.sendCallbackOutput(sw, config, ev.ResponseIncomplete.Response.Model, msg)
 
@@ -1136,17 +1482,18 @@ func (cm *ResponsesAPIChatModel) receivedStreamResponse(streamReader *utils.Resp
 			if ev.ResponseFailed == nil || ev.ResponseFailed.Response == nil {
 				continue
 			}
-			var errorMessage string
-			if ev.ResponseFailed.Response.Error != nil {
-				errorMessage = ev.ResponseFailed.Response.Error.Message
+			if cfErr := newContentFilteredErrorFromResponse(ev.ResponseFailed.Response.Error); cfErr != nil {
+				sw.Send(nil, cfErr)
+				continue
 			}
 			msg := &schema.Message{
 				Role: schema.Assistant,
 				ResponseMeta: &schema.ResponseMeta{
-					FinishReason: errorMessage,
+					FinishReason: FinishReasonFailed,
 					Usage:        cm.toEinoTokenUsage(ev.ResponseFailed.Response.Usage),
 				},
 			}
+			setResponseError(msg, toResponseError(ev.ResponseFailed.Response.Error))
 			cm.setStreamChunkDefaultExtra(msg, ev.ResponseFailed.Response, cacheConfig)
 			cm.sendCallbackOutput(sw, config, ev.ResponseFailed.Response.Model, msg)
 
@@ -1154,35 +1501,37 @@ func (cm *ResponsesAPIChatModel) receivedStreamResponse(streamReader *utils.Resp
 			if ev.Item == nil || ev.Item.GetItem() == nil || ev.Item.GetItem().GetUnion() == nil {
 				continue
 			}
-			if outputItemFuncCall, ok := ev.Item.GetItem().GetUnion().(*responses.OutputItem_FunctionToolCall); ok {
-				itemFunctionToolCall = outputItemFuncCall.FunctionToolCall
+			outputItemFuncCall, ok := ev.Item.GetItem().GetUnion().(*responses.OutputItem_FunctionToolCall)
+			if ok && outputItemFuncCall.FunctionToolCall != nil && outputItemFuncCall.FunctionToolCall.Id != nil {
+				toolCalls[*outputItemFuncCall.FunctionToolCall.Id] = &streamToolCall{
+					call:  outputItemFuncCall.FunctionToolCall,
+					index: int(ev.Item.OutputIndex),
+				}
 			}
 
 		case *responses.Event_FunctionCallArguments:
-			if ev.FunctionCallArguments == nil {
+			if ev.FunctionCallArguments == nil || ev.FunctionCallArguments.Delta == nil {
 				continue
 			}
+			if tc, ok := toolCalls[ev.FunctionCallArguments.ItemId]; ok {
+				tc.emitted = true
+				cm.sendCallbackOutput(sw, config, "", tc.deltaMessage(*ev.FunctionCallArguments.Delta))
+			}
 
-			delta := *ev.FunctionCallArguments.Delta
-			outputIndex := ev.FunctionCallArguments.OutputIndex
-
-			if itemFunctionToolCall != nil && itemFunctionToolCall.Id != nil && *itemFunctionToolCall.Id == ev.FunctionCallArguments.ItemId {
-				msg := &schema.Message{
-					Role: schema.Assistant,
-					ToolCalls: []schema.ToolCall{
-						{
-							Index: ptrOf(int(outputIndex)),
-							ID:    itemFunctionToolCall.CallId,
-							Type:  itemFunctionToolCall.Type.String(),
-							Function: schema.FunctionCall{
-								Name:      itemFunctionToolCall.Name,
-								Arguments: delta,
-							},
-						},
-					},
-				}
-				cm.sendCallbackOutput(sw, config, "", msg)
+		case *responses.Event_ItemDone:
+			if ev.ItemDone == nil || ev.ItemDone.GetItem() == nil {
+				continue
+			}
+			outputItemFuncCall, ok := ev.ItemDone.GetItem().GetUnion().(*responses.OutputItem_FunctionToolCall)
+			if !ok || outputItemFuncCall.FunctionToolCall == nil || outputItemFuncCall.FunctionToolCall.Id == nil {
+				continue
 			}
+			itemID := *outputItemFuncCall.FunctionToolCall.Id
+			// a call without argument deltas is emitted with the arguments of the done item
+			if tc, ok := toolCalls[itemID]; ok && !tc.emitted {
+				cm.sendCallbackOutput(sw, config, "", tc.deltaMessage(outputItemFuncCall.FunctionToolCall.Arguments))
+			}
+			delete(toolCalls, itemID)
 
 		case *responses.Event_ReasoningText:
 			if ev.ReasoningText == nil || ev.ReasoningText.Delta == nil {
@@ -1206,12 +1555,45 @@ func (cm *ResponsesAPIChatModel) receivedStreamResponse(streamReader *utils.Resp
 			}
 			cm.sendCallbackOutput(sw, config, "", msg)
 
+		case *responses.Event_ResponseAnnotationAdded:
+			if ev.ResponseAnnotationAdded == nil || ev.ResponseAnnotationAdded.Annotation == nil {
+				continue
+			}
+			msg := &schema.Message{Role: schema.Assistant}
+			addAnnotations(msg, []*responses.Annotation{ev.ResponseAnnotationAdded.Annotation})
+			cm.sendCallbackOutput(sw, config, "", msg)
+
 		}
 
 	}
 
 }
 
+// streamToolCall is a function tool call being streamed.
+type streamToolCall struct {
+	call *responses.ItemFunctionToolCall
+	// index is the output index of the call item, which keeps the chunks of each call together in ConcatMessages.
+	index   int
+	emitted bool
+}
+
+func (tc *streamToolCall) deltaMessage(arguments string) *schema.Message {
+	return &schema.Message{
+		Role: schema.Assistant,
+		ToolCalls: []schema.ToolCall{
+			{
+				Index: ptrOf(tc.index),
+				ID:    tc.call.CallId,
+				Type:  tc.call.Type.String(),
+				Function: schema.FunctionCall{
+					Name:      tc.call.Name,
+					Arguments: arguments,
+				},
+			},
+		},
+	}
+}
+
 func (cm *ResponsesAPIChatModel) setStreamChunkDefaultExtra(msg *schema.Message, object *responses.ResponseObject,
 	cacheConfig *cacheConfig) {
 
@@ -1220,6 +1602,7 @@ func (cm *ResponsesAPIChatModel) setStreamChunkDefaultExtra(msg *schema.Message,
 	}
 	setContextID(msg, object.Id)
 	setResponseID(msg, object.Id)
+	setResponseIDChain(msg, cacheConfig.PrevResponseIDChain, object.Id)
 	if object.ServiceTier != nil {
 		setServiceTier(msg, object.ServiceTier.String())
 	}
@@ -1425,6 +1808,9 @@ func convMsgInputVideoToResponseContentItem(video *schema.MessageInputVideo) (*r
 	if err != nil {
 		return nil, fmt.Errorf("convert message input video failed err: %w", err)
 	}
+	if err = checkVideoMetadata(video.Extra); err != nil {
+		return nil, err
+	}
 
 	contentItemVideo := &responses.ContentItemVideo{
 		Type:     responses.ContentItemType_input_video,
@@ -1495,7 +1881,7 @@ func convMessagePartCommonToURL(common schema.MessagePartCommon) (url string, er
 	if common.MIMEType == "" {
 		return "", fmt.Errorf("message part must have MIMEType when use Base64Data")
 	}
-	url, err = ensureDataURL(*common.Base64Data, common.MIMEType)
+	url, err = multimodal.DataURL(*common.Base64Data, common.MIMEType)
 	if err != nil {
 		return "", err
 	}
@@ -1503,16 +1889,6 @@ func convMessagePartCommonToURL(common schema.MessagePartCommon) (url string, er
 	return url, nil
 }
 
-func ensureDataURL(dataOfBase64, mimeType string) (string, error) {
-	if strings.HasPrefix(dataOfBase64, "data:") {
-		return "", fmt.Errorf("base64Data field must be a raw base64 string, but got a string with prefix 'data:'")
-	}
-	if mimeType == "" {
-		return "", fmt.Errorf("mimeType field is required")
-	}
-	return fmt.Sprintf("data:%s;base64,%s", mimeType, dataOfBase64), nil
-}
-
 func toContentItemImageDetail(cImage *responses.ContentItemImage, detail schema.ImageURLDetail) error {
 	if len(detail) == 0 {
 		return nil
@@ -1637,7 +2013,7 @@ func (cm *ResponsesAPIChatModel) WithTools(tools []*schema.ToolInfo) (model.Tool
 //   - ttl: Time-to-live in seconds for the cached prefix, default: 86400.
 //
 // Returns:
-//   - info: Information about the created prefix cache, including the response id and token usage.
+//   - info: Information about the created prefix cache, including the response id, token usage and expiration time.
 //   - err: Any error encountered during the operation.
 //
 // ref: https://www.volcengine.com/docs/82379/1602228?lang=zh
@@ -1645,6 +2021,9 @@ func (cm *ResponsesAPIChatModel) CreatePrefixCache(ctx context.Context, prefix [
 	if len(prefix) == 0 {
 		return nil, errors.New("prefix messages cannot be empty")
 	}
+	if cm.storeResponses != nil && !*cm.storeResponses {
+		return nil, errors.New("the prefix cache requires the responses stored by Ark, but StoreResponses is false")
+	}
 	responseReq := &responses.ResponsesRequest{
 		Model: cm.model,
 		Store: ptrOf(true),
@@ -1676,14 +2055,17 @@ func (cm *ResponsesAPIChatModel) CreatePrefixCache(ctx context.Context, prefix [
 		return nil, err
 	}
 
-	responseObject, err := cm.client.CreateResponses(ctx, responseReq)
+	responseObject, err := cm.responsesClient().CreateResponses(ctx, responseReq, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	info = &CacheInfo{
 		ResponseID: responseObject.Id,
-		Usage:      *cm.toEinoTokenUsage(responseObject.Usage),
+		ExpireAt:   ptrFromOrZero(responseObject.ExpireAt),
+	}
+	if usage := cm.toEinoTokenUsage(responseObject.Usage); usage != nil {
+		info.Usage = *usage
 	}
 
 	return info, nil
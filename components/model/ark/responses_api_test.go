@@ -23,6 +23,7 @@ import (
 	"time"
 
 	. "github.com/bytedance/mockey"
+	"github.com/bytedance/sonic"
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
@@ -394,6 +395,54 @@ func TestResponsesAPIChatModelInjectCache(t *testing.T) {
 		assert.Len(t, in_, 2)
 		assert.NotNil(t, reqParams.ExpireAt)
 	})
+
+	PatchConvey("previous response id option", t, func() {
+		cm := &ResponsesAPIChatModel{
+			cache: &CacheConfig{
+				SessionCache: &SessionCacheConfig{
+					EnableCache: true,
+				},
+			},
+		}
+		arkOpts := &arkOptions{
+			previousResponseID: ptrOf("external-response-id"),
+		}
+		msgs := []*schema.Message{
+			{
+				Role:    schema.User,
+				Content: "Hello",
+				Extra: map[string]any{
+					keyOfResponseID:            "test-response-id",
+					keyOfResponseCacheExpireAt: time.Now().Unix() + 259200,
+				},
+			},
+			{
+				Role:    schema.User,
+				Content: "World",
+			},
+		}
+
+		reqParams := &responses.ResponsesRequest{}
+		in_, err := cm.populateCache(msgs, reqParams, arkOpts)
+		assert.Nil(t, err)
+		assert.Equal(t, "external-response-id", *reqParams.PreviousResponseId)
+		assert.Len(t, in_, 2)
+	})
+}
+
+func TestGetPrevResponseIDChain(t *testing.T) {
+	assert.Nil(t, getPrevResponseIDChain(nil, nil))
+	assert.Equal(t, []string{"resp-1"}, getPrevResponseIDChain(nil, ptrOf("resp-1")))
+
+	msg := &schema.Message{Role: schema.Assistant}
+	setResponseID(msg, "resp-2")
+	setResponseIDChain(msg, []string{"resp-1"}, "resp-2")
+	in := []*schema.Message{msg, {Role: schema.User, Content: "hi"}}
+	assert.Equal(t, []string{"resp-1", "resp-2"}, getPrevResponseIDChain(in, ptrOf("resp-2")))
+
+	out := &schema.Message{Role: schema.Assistant}
+	setResponseIDChain(out, getPrevResponseIDChain(in, ptrOf("resp-2")), "resp-3")
+	assert.Equal(t, []string{"resp-1", "resp-2", "resp-3"}, GetResponseIDChain(out))
 }
 
 func TestResponsesAPIChatModelReceivedStreamResponse_ResponseCreatedEvent(t *testing.T) {
@@ -409,7 +458,7 @@ func TestResponsesAPIChatModelReceivedStreamResponse_ResponseCreatedEvent(t *tes
 		}, nil).Then(nil, io.EOF)).Build()
 		mocker := Mock((*ResponsesAPIChatModel).sendCallbackOutput).Return().Build()
 		streamReader := &utils.ResponsesStreamReader{}
-		cm.receivedStreamResponse(streamReader, nil, &cacheConfig{Enabled: true}, nil)
+		cm.receivedStreamResponse(streamReader, nil, nil, &cacheConfig{Enabled: true}, nil)
 		assert.Equal(t, 1, mocker.Times())
 	})
 }
@@ -428,7 +477,7 @@ func TestResponsesAPIChatModelReceivedStreamResponse_ResponseCompletedEvent(t *t
 		}, nil).Then(nil, io.EOF)).Build()
 		mocker := Mock((*ResponsesAPIChatModel).sendCallbackOutput).Return().Build()
 		streamReader := &utils.ResponsesStreamReader{}
-		cm.receivedStreamResponse(streamReader, nil, &cacheConfig{Enabled: true}, nil)
+		cm.receivedStreamResponse(streamReader, nil, nil, &cacheConfig{Enabled: true}, nil)
 		assert.Equal(t, 1, mocker.Times())
 	})
 }
@@ -445,7 +494,7 @@ func TestResponsesAPIChatModelReceivedStreamResponse_ResponseErrorEvent(t *testi
 		}, nil).Then(nil, io.EOF)).Build()
 		sr, sw := schema.Pipe[*model.CallbackOutput](1)
 		streamReader := &utils.ResponsesStreamReader{}
-		cm.receivedStreamResponse(streamReader, nil, &cacheConfig{Enabled: true}, sw)
+		cm.receivedStreamResponse(streamReader, nil, nil, &cacheConfig{Enabled: true}, sw)
 
 		_, err := sr.Recv()
 		assert.NotNil(t, err)
@@ -470,7 +519,7 @@ func TestResponsesAPIChatModelReceivedStreamResponse_ResponseIncompleteEvent(t *
 		streamReader := &utils.ResponsesStreamReader{}
 		mocker := Mock((*ResponsesAPIChatModel).sendCallbackOutput).Return().Build()
 
-		cm.receivedStreamResponse(streamReader, nil, &cacheConfig{Enabled: true}, nil)
+		cm.receivedStreamResponse(streamReader, nil, nil, &cacheConfig{Enabled: true}, nil)
 
 		assert.Equal(t, 1, mocker.Times())
 	})
@@ -494,7 +543,7 @@ func TestResponsesAPIChatModelReceivedStreamResponse_ResponseFailedEvent(t *test
 		streamReader := &utils.ResponsesStreamReader{}
 		mocker := Mock((*ResponsesAPIChatModel).sendCallbackOutput).Return().Build()
 
-		cm.receivedStreamResponse(streamReader, nil, &cacheConfig{Enabled: true}, nil)
+		cm.receivedStreamResponse(streamReader, nil, nil, &cacheConfig{Enabled: true}, nil)
 
 		assert.Equal(t, 1, mocker.Times())
 	})
@@ -513,7 +562,7 @@ func TestResponsesAPIChatModelReceivedStreamResponse_Default(t *testing.T) {
 		streamReader := &utils.ResponsesStreamReader{}
 		mocker := Mock((*ResponsesAPIChatModel).sendCallbackOutput).Return().Build()
 
-		cm.receivedStreamResponse(streamReader, nil, &cacheConfig{Enabled: true}, nil)
+		cm.receivedStreamResponse(streamReader, nil, nil, &cacheConfig{Enabled: true}, nil)
 
 		assert.Equal(t, 1, mocker.Times())
 
@@ -559,13 +608,93 @@ func TestResponsesAPIChatModelReceivedStreamResponse_ToolCallMetaMsg(t *testing.
 
 		cache := &cacheConfig{Enabled: true}
 
-		cm.receivedStreamResponse(streamReader, nil, cache, nil)
+		cm.receivedStreamResponse(streamReader, nil, nil, cache, nil)
 
 		assert.Equal(t, 1, mocker.Times())
 
 	})
 }
 
+func TestResponsesAPIChatModelReceivedStreamResponse_ParallelToolCalls(t *testing.T) {
+	cm := &ResponsesAPIChatModel{}
+	funcCall := func(id, name, arguments string) *responses.OutputItem {
+		return &responses.OutputItem{
+			Union: &responses.OutputItem_FunctionToolCall{
+				FunctionToolCall: &responses.ItemFunctionToolCall{
+					Id:        ptrOf(id),
+					CallId:    "call_" + id,
+					Name:      name,
+					Arguments: arguments,
+					Type:      responses.ItemType_function_call,
+				},
+			},
+		}
+	}
+	added := func(outputIndex int64, item *responses.OutputItem) *responses.Event {
+		return &responses.Event{Event: &responses.Event_Item{
+			Item: &responses.ItemEvent{OutputIndex: outputIndex, Item: item},
+		}}
+	}
+	delta := func(outputIndex int64, itemID, delta string) *responses.Event {
+		return &responses.Event{Event: &responses.Event_FunctionCallArguments{
+			FunctionCallArguments: &responses.FunctionCallArgumentsEvent{OutputIndex: outputIndex, ItemId: itemID, Delta: ptrOf(delta)},
+		}}
+	}
+	done := func(outputIndex int64, item *responses.OutputItem) *responses.Event {
+		return &responses.Event{Event: &responses.Event_ItemDone{
+			ItemDone: &responses.ItemDoneEvent{OutputIndex: outputIndex, Item: item},
+		}}
+	}
+
+	PatchConvey("ParallelToolCalls", t, func() {
+		var msgs []*schema.Message
+		Mock((*ResponsesAPIChatModel).sendCallbackOutput).To(
+			func(sw *schema.StreamWriter[*model.CallbackOutput], reqConf *model.Config, modelName string,
+				msg *schema.Message) {
+				msgs = append(msgs, msg)
+			}).Build()
+
+		PatchConvey("interleaved arguments", func() {
+			Mock((*utils.ResponsesStreamReader).Recv).Return(Sequence(added(0, funcCall("a", "get_weather", "")), nil).
+				Then(added(1, funcCall("b", "get_time", "")), nil).
+				Then(delta(0, "a", `{"city":`), nil).
+				Then(delta(1, "b", `{"zone":`), nil).
+				Then(delta(0, "a", `"Paris"}`), nil).
+				Then(delta(1, "b", `"UTC"}`), nil).
+				Then(done(0, funcCall("a", "get_weather", `{"city":"Paris"}`)), nil).
+				Then(done(1, funcCall("b", "get_time", `{"zone":"UTC"}`)), nil).
+				Then(nil, io.EOF)).Build()
+
+			cm.receivedStreamResponse(&utils.ResponsesStreamReader{}, nil, nil, &cacheConfig{}, nil)
+			assert.Len(t, msgs, 4)
+
+			msg, err := schema.ConcatMessages(msgs)
+			assert.NoError(t, err)
+			assert.Len(t, msg.ToolCalls, 2)
+			assert.Equal(t, 0, *msg.ToolCalls[0].Index)
+			assert.Equal(t, "call_a", msg.ToolCalls[0].ID)
+			assert.Equal(t, "get_weather", msg.ToolCalls[0].Function.Name)
+			assert.Equal(t, `{"city":"Paris"}`, msg.ToolCalls[0].Function.Arguments)
+			assert.Equal(t, 1, *msg.ToolCalls[1].Index)
+			assert.Equal(t, "call_b", msg.ToolCalls[1].ID)
+			assert.Equal(t, "get_time", msg.ToolCalls[1].Function.Name)
+			assert.Equal(t, `{"zone":"UTC"}`, msg.ToolCalls[1].Function.Arguments)
+		})
+
+		PatchConvey("arguments without deltas", func() {
+			Mock((*utils.ResponsesStreamReader).Recv).Return(Sequence(added(1, funcCall("a", "get_weather", "")), nil).
+				Then(done(1, funcCall("a", "get_weather", `{"city":"Paris"}`)), nil).
+				Then(delta(1, "a", `ignored`), nil).
+				Then(nil, io.EOF)).Build()
+
+			cm.receivedStreamResponse(&utils.ResponsesStreamReader{}, nil, nil, &cacheConfig{}, nil)
+			assert.Len(t, msgs, 1)
+			assert.Equal(t, 1, *msgs[0].ToolCalls[0].Index)
+			assert.Equal(t, `{"city":"Paris"}`, msgs[0].ToolCalls[0].Function.Arguments)
+		})
+	})
+}
+
 func TestResponsesAPIChatModelHandleGenRequestAndOptions(t *testing.T) {
 	cm := &ResponsesAPIChatModel{
 		temperature: ptrOf(float32(1.0)),
@@ -650,6 +779,9 @@ func TestResponsesAPIChatModelHandleGenRequestAndOptions(t *testing.T) {
 		assert.Equal(t, "test tool", reqParams.Tools[0].GetToolFunction().Name)
 
 		assert.Equal(t, "json_schema", reqParams.Text.Format.GetName())
+		jsonSchema, err := sonic.Marshal(cm.responseFormat.JSONSchema)
+		assert.NoError(t, err)
+		assert.Equal(t, jsonSchema, reqParams.Text.Format.Schema.Value)
 	})
 }
 
@@ -1058,6 +1190,7 @@ func TestResponsesAPIChatModel_CreatePrefixCache(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotNil(t, info)
 			assert.Equal(t, "test-cache-id", info.ResponseID)
+			assert.Equal(t, *exAt, info.ExpireAt)
 
 		})
 		PatchConvey("Error: Nil Prefix", func() {
@@ -1069,3 +1202,43 @@ func TestResponsesAPIChatModel_CreatePrefixCache(t *testing.T) {
 
 	})
 }
+
+func TestResponsesAPIChatModelDisableCallbacks(t *testing.T) {
+	PatchConvey("test disable callbacks", t, func() {
+		Mock((*ResponsesAPIChatModel).genRequestAndOptions).
+			Return(&responses.ResponsesRequest{}, nil).Build()
+		Mock((*ResponsesAPIChatModel).toCallbackConfig).
+			Return(&model.Config{}).Build()
+		Mock((*arkruntime.Client).CreateResponses).
+			Return(&responses.ResponseObject{Usage: &responses.Usage{}}, nil).Build()
+		Mock((*ResponsesAPIChatModel).toOutputMessage).
+			Return(&schema.Message{Role: schema.Assistant, Content: "assistant"}, nil).Build()
+
+		var started, ended int
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackInput) context.Context {
+				started++
+				return ctx
+			}).
+			OnEndFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackOutput) context.Context {
+				ended++
+				return ctx
+			}).Build()
+		ctx := callbacks.InitCallbacks(context.Background(), &callbacks.RunInfo{}, handler)
+		input := []*schema.Message{schema.UserMessage("user")}
+
+		cm := &ResponsesAPIChatModel{disableCallbacks: true}
+		msg, err := cm.Generate(ctx, input)
+		assert.Nil(t, err)
+		assert.Equal(t, "assistant", msg.Content)
+		assert.Equal(t, 0, started)
+		assert.Equal(t, 0, ended)
+		assert.False(t, cm.IsCallbacksEnabled())
+
+		cm.disableCallbacks = false
+		_, err = cm.Generate(ctx, input)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, started)
+		assert.Equal(t, 1, ended)
+	})
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+// cancelInFlightTimeout bounds the cancellation of the in-flight response of a stream whose context is done.
+const cancelInFlightTimeout = 5 * time.Second
+
+// Cancel cancels the in-flight generation of the response of responseID, e.g. a stream abandoned by another process,
+// freeing the server resources and stopping the billing. The response ID of a stream is carried by its chunks,
+// see GetResponseID. Responses already finished cannot be canceled.
+func (cm *ResponsesAPIChatModel) Cancel(ctx context.Context, responseID string, opts ...model.Option) error {
+	if responseID == "" {
+		return errors.New("response id cannot be empty")
+	}
+
+	cancelClient, ok := cm.responsesCancelClient()
+	if !ok {
+		return errors.New("the responses client does not implement ResponsesCancelClient")
+	}
+
+	_, specOptions, err := cm.getOptions(opts)
+	if err != nil {
+		return err
+	}
+	headers := buildRequestHeaders(ctx, specOptions.customHeaders, specOptions.requestHeaders)
+
+	if _, err = cancelClient.CancelResponses(ctx, responseID, headers); err != nil {
+		return fmt.Errorf("failed to cancel response %s: %w", responseID, err)
+	}
+	return nil
+}
+
+// cancelInFlight cancels the response of the stream unless it has finished.
+// It runs once the context of the stream is done, so the cancellation gets a fresh context.
+func (cm *ResponsesAPIChatModel) cancelInFlight(stream *inFlightStream, headers map[string]string) {
+	if stream.responseID == "" || stream.finished {
+		return
+	}
+	cancelClient, ok := cm.responsesCancelClient()
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cancelInFlightTimeout)
+	defer cancel()
+	// the stream has already failed with the context error, so the cancellation error is ignored
+	_, _ = cancelClient.CancelResponses(ctx, stream.responseID, headers)
+}
+
+// inFlightStream tracks the ID of the streamed response and whether it has finished,
+// so that the response can be canceled when the context of the stream is done.
+type inFlightStream struct {
+	ResponsesStreamReader
+	responseID string
+	finished   bool
+}
+
+func (s *inFlightStream) Recv() (*responses.Event, error) {
+	event, err := s.ResponsesStreamReader.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			s.finished = true
+		}
+		return event, err
+	}
+
+	switch ev := event.GetEvent().(type) {
+	case *responses.Event_Response:
+		if ev.Response != nil && ev.Response.Response != nil && ev.Response.Response.Id != "" {
+			s.responseID = ev.Response.Response.Id
+		}
+	case *responses.Event_ResponseCompleted, *responses.Event_ResponseIncomplete, *responses.Event_ResponseFailed:
+		s.finished = true
+	}
+	return event, nil
+}
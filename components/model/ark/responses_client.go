@@ -0,0 +1,134 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+// ResponsesClient sends the requests of ResponsesAPIChatModel to the Ark Responses API.
+// By default, it is implemented by the Ark SDK client built from the ResponsesAPIConfig,
+// inject another implementation with ResponsesAPIConfig.Client, e.g. a gomock mock in tests or a fault injecting wrapper.
+type ResponsesClient interface {
+	// CreateResponses creates a response, sending headers as the HTTP request headers.
+	CreateResponses(ctx context.Context, req *responses.ResponsesRequest, headers map[string]string) (*responses.ResponseObject, error)
+	// CreateResponsesStream creates a streaming response, sending headers as the HTTP request headers.
+	CreateResponsesStream(ctx context.Context, req *responses.ResponsesRequest, headers map[string]string) (ResponsesStreamReader, error)
+}
+
+// ResponsesStreamReader reads the events of a streaming response.
+type ResponsesStreamReader interface {
+	// Recv returns the next event, or io.EOF when the stream is finished.
+	Recv() (*responses.Event, error)
+	Close() error
+}
+
+// ResponsesStoreClient reads the responses stored by Ark, required by ResponsesAPIChatModel.FetchConversation.
+// An injected ResponsesClient can implement it optionally.
+type ResponsesStoreClient interface {
+	// GetResponses returns the response of responseID.
+	GetResponses(ctx context.Context, responseID string, headers map[string]string) (*responses.ResponseObject, error)
+	// ListResponseInputItems returns a page of the input items of the response of responseID.
+	ListResponseInputItems(ctx context.Context, req *responses.ListInputItemsRequest, headers map[string]string) (*responses.ListInputItemsResponse, error)
+}
+
+// ResponsesCancelClient cancels the in-flight responses, required by ResponsesAPIChatModel.Cancel
+// and by the cancellation of the streams on context done.
+// An injected ResponsesClient can implement it optionally.
+type ResponsesCancelClient interface {
+	// CancelResponses cancels the generation of the response of responseID.
+	CancelResponses(ctx context.Context, responseID string, headers map[string]string) (*responses.ResponseObject, error)
+}
+
+// arkResponsesClient implements ResponsesClient, ResponsesStoreClient and ResponsesCancelClient with the Ark SDK client.
+type arkResponsesClient struct {
+	client  *arkruntime.Client
+	baseURL string
+}
+
+func (c arkResponsesClient) CreateResponses(ctx context.Context, req *responses.ResponsesRequest,
+	headers map[string]string) (*responses.ResponseObject, error) {
+	return c.client.CreateResponses(ctx, req, arkruntime.WithCustomHeaders(headers))
+}
+
+func (c arkResponsesClient) CreateResponsesStream(ctx context.Context, req *responses.ResponsesRequest,
+	headers map[string]string) (ResponsesStreamReader, error) {
+	stream, err := c.client.CreateResponsesStream(ctx, req, arkruntime.WithCustomHeaders(headers))
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (c arkResponsesClient) GetResponses(ctx context.Context, responseID string,
+	headers map[string]string) (*responses.ResponseObject, error) {
+	return c.client.GetResponses(ctx, responseID, &responses.GetResponseRequest{ResponseId: responseID},
+		arkruntime.WithCustomHeaders(headers))
+}
+
+func (c arkResponsesClient) ListResponseInputItems(ctx context.Context, req *responses.ListInputItemsRequest,
+	headers map[string]string) (*responses.ListInputItemsResponse, error) {
+	return c.client.ListResponseInputItems(ctx, req.ResponseId, req, arkruntime.WithCustomHeaders(headers))
+}
+
+// CancelResponses calls the cancel endpoint, which the Ark SDK does not wrap.
+func (c arkResponsesClient) CancelResponses(ctx context.Context, responseID string,
+	headers map[string]string) (*responses.ResponseObject, error) {
+	res := &responses.ResponseObject{}
+	err := c.client.Do(ctx, http.MethodPost, fmt.Sprintf("%s/responses/%s/cancel", c.baseURL, url.PathEscape(responseID)),
+		"", "", res, arkruntime.WithCustomHeaders(headers))
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// responsesClient returns the injected ResponsesClient, or the Ark SDK client if none is injected.
+func (cm *ResponsesAPIChatModel) responsesClient() ResponsesClient {
+	if cm.injectedClient != nil {
+		return cm.injectedClient
+	}
+	return arkResponsesClient{client: cm.client, baseURL: cm.baseURL}
+}
+
+// responsesStoreClient returns the ResponsesStoreClient of the model,
+// false if the injected ResponsesClient does not implement it.
+func (cm *ResponsesAPIChatModel) responsesStoreClient() (ResponsesStoreClient, bool) {
+	storeClient, ok := cm.responsesClient().(ResponsesStoreClient)
+	return storeClient, ok
+}
+
+// responsesCancelClient returns the ResponsesCancelClient of the model,
+// false if the injected ResponsesClient does not implement it.
+func (cm *ResponsesAPIChatModel) responsesCancelClient() (ResponsesCancelClient, bool) {
+	cancelClient, ok := cm.responsesClient().(ResponsesCancelClient)
+	return cancelClient, ok
+}
+
+// responsesBaseURL returns baseURL, or the default base URL if it is empty.
+func responsesBaseURL(baseURL string) string {
+	if baseURL == "" {
+		return defaultBaseURL
+	}
+	return baseURL
+}
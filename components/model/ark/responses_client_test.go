@@ -0,0 +1,325 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+
+	"github.com/cloudwego/eino-ext/libs/pii"
+)
+
+type fakeResponsesClient struct {
+	resp      *responses.ResponseObject
+	events    []*responses.Event
+	streamErr error
+	err       error
+	req       *responses.ResponsesRequest
+	headers   map[string]string
+	canceled  chan string
+}
+
+func (c *fakeResponsesClient) CreateResponses(_ context.Context, req *responses.ResponsesRequest,
+	headers map[string]string) (*responses.ResponseObject, error) {
+	c.req, c.headers = req, headers
+	return c.resp, c.err
+}
+
+func (c *fakeResponsesClient) CreateResponsesStream(_ context.Context, req *responses.ResponsesRequest,
+	headers map[string]string) (ResponsesStreamReader, error) {
+	c.req, c.headers = req, headers
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &fakeResponsesStream{events: c.events, err: c.streamErr}, nil
+}
+
+func (c *fakeResponsesClient) CancelResponses(_ context.Context, responseID string,
+	_ map[string]string) (*responses.ResponseObject, error) {
+	c.canceled <- responseID
+	return &responses.ResponseObject{Id: responseID}, nil
+}
+
+type fakeResponsesStream struct {
+	events []*responses.Event
+	// err is returned once the events are read, io.EOF if nil
+	err error
+}
+
+func (s *fakeResponsesStream) Recv() (*responses.Event, error) {
+	if len(s.events) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	event := s.events[0]
+	s.events = s.events[1:]
+	return event, nil
+}
+
+func (s *fakeResponsesStream) Close() error {
+	return nil
+}
+
+func TestResponsesAPIChatModel_InjectedClient(t *testing.T) {
+	ctx := context.Background()
+	newModel := func(client ResponsesClient) *ResponsesAPIChatModel {
+		cm, err := NewResponsesAPIChatModel(ctx, &ResponsesAPIConfig{
+			Model:        "test-model",
+			CustomHeader: map[string]string{"X-Test": "1"},
+			Client:       client,
+		})
+		assert.NoError(t, err)
+		return cm
+	}
+
+	t.Run("generate", func(t *testing.T) {
+		client := &fakeResponsesClient{resp: &responses.ResponseObject{
+			Id:     "resp-1",
+			Status: responses.ResponseStatus_completed,
+			Output: []*responses.OutputItem{{Union: &responses.OutputItem_OutputMessage{OutputMessage: &responses.ItemOutputMessage{
+				Content: []*responses.OutputContentItem{{Union: &responses.OutputContentItem_Text{
+					Text: &responses.OutputContentItemText{Text: "hello"},
+				}}},
+			}}}},
+		}}
+		cm := newModel(client)
+
+		msg, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", msg.Content)
+		// the response has no usage
+		assert.Nil(t, msg.ResponseMeta.Usage)
+		assert.Equal(t, "test-model", client.req.Model)
+		assert.Equal(t, "1", client.headers["X-Test"])
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		client := &fakeResponsesClient{events: []*responses.Event{
+			{Event: &responses.Event_Text{Text: &responses.OutputTextEvent{Delta: ptrOf("hel")}}},
+			{Event: &responses.Event_Text{Text: &responses.OutputTextEvent{Delta: ptrOf("lo")}}},
+			{Event: &responses.Event_ResponseCompleted{ResponseCompleted: &responses.ResponseCompletedEvent{
+				Response: &responses.ResponseObject{Id: "resp-1", Status: responses.ResponseStatus_completed},
+			}}},
+		}}
+		cm := newModel(client)
+
+		sr, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		var chunks []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+		msg, err := schema.ConcatMessages(chunks)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", msg.Content)
+		assert.Equal(t, responses.ResponseStatus_completed.String(), msg.ResponseMeta.FinishReason)
+		assert.Nil(t, msg.ResponseMeta.Usage)
+	})
+
+	t.Run("fault injection", func(t *testing.T) {
+		cm := newModel(&fakeResponsesClient{err: errors.New("injected fault")})
+
+		_, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.ErrorContains(t, err, "injected fault")
+		_, err = cm.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.ErrorContains(t, err, "injected fault")
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		client := &fakeResponsesClient{canceled: make(chan string, 1)}
+		cm := newModel(client)
+
+		assert.NoError(t, cm.Cancel(ctx, "resp-1"))
+		assert.Equal(t, "resp-1", <-client.canceled)
+		assert.ErrorContains(t, cm.Cancel(ctx, ""), "empty")
+	})
+
+	t.Run("cancel stream on context done", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		client := &fakeResponsesClient{
+			events: []*responses.Event{
+				{Event: &responses.Event_Response{Response: &responses.ResponseEvent{Response: &responses.ResponseObject{Id: "resp-2"}}}},
+				{Event: &responses.Event_Text{Text: &responses.OutputTextEvent{Delta: ptrOf("hel")}}},
+			},
+			streamErr: context.Canceled,
+			canceled:  make(chan string, 1),
+		}
+		cm := newModel(client)
+
+		sr, err := cm.Stream(cctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		cancel()
+		for {
+			if _, err = sr.Recv(); err != nil {
+				break
+			}
+		}
+		assert.ErrorIs(t, err, context.Canceled)
+
+		select {
+		case id := <-client.canceled:
+			assert.Equal(t, "resp-2", id)
+		case <-time.After(time.Second):
+			t.Fatal("the in-flight response was not canceled")
+		}
+	})
+
+	t.Run("finished stream is not canceled", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		client := &fakeResponsesClient{
+			events: []*responses.Event{
+				{Event: &responses.Event_Response{Response: &responses.ResponseEvent{Response: &responses.ResponseObject{Id: "resp-3"}}}},
+			},
+			canceled: make(chan string, 1),
+		}
+		cm := newModel(client)
+
+		sr, err := cm.Stream(cctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		for {
+			if _, err = sr.Recv(); err != nil {
+				break
+			}
+		}
+		assert.ErrorIs(t, err, io.EOF)
+		cancel()
+		assert.Len(t, client.canceled, 0)
+	})
+
+	t.Run("store responses", func(t *testing.T) {
+		client := &fakeResponsesClient{resp: &responses.ResponseObject{
+			Status: responses.ResponseStatus_completed,
+			Output: []*responses.OutputItem{{Union: &responses.OutputItem_OutputMessage{OutputMessage: &responses.ItemOutputMessage{
+				Content: []*responses.OutputContentItem{{Union: &responses.OutputContentItem_Text{
+					Text: &responses.OutputContentItemText{Text: "hello"},
+				}}},
+			}}}},
+			Usage: &responses.Usage{},
+		}}
+		cm, err := NewResponsesAPIChatModel(ctx, &ResponsesAPIConfig{Model: "test-model", Client: client, StoreResponses: ptrOf(false)})
+		assert.NoError(t, err)
+
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.False(t, *client.req.Store)
+
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")},
+			WithCache(&CacheOption{SessionCache: &SessionCacheConfig{EnableCache: true}}))
+		assert.ErrorIs(t, err, errStoreDisabledCache)
+		_, err = cm.CreatePrefixCache(ctx, []*schema.Message{schema.SystemMessage("you are a helpful assistant")}, 0)
+		assert.ErrorContains(t, err, "StoreResponses is false")
+
+		_, err = NewResponsesAPIChatModel(ctx, &ResponsesAPIConfig{Model: "test-model", Client: client, StoreResponses: ptrOf(false),
+			SessionCache: &SessionCacheConfig{EnableCache: true}})
+		assert.ErrorIs(t, err, errStoreDisabledCache)
+
+		cm, err = NewResponsesAPIChatModel(ctx, &ResponsesAPIConfig{Model: "test-model", Client: client, StoreResponses: ptrOf(true)})
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.True(t, *client.req.Store)
+		assert.Equal(t, responses.CacheType_disabled, *client.req.Caching.Type)
+	})
+
+	t.Run("build request only", func(t *testing.T) {
+		client := &fakeResponsesClient{resp: &responses.ResponseObject{
+			Status: responses.ResponseStatus_completed,
+			Output: []*responses.OutputItem{{Union: &responses.OutputItem_OutputMessage{OutputMessage: &responses.ItemOutputMessage{
+				Content: []*responses.OutputContentItem{{Union: &responses.OutputContentItem_Text{
+					Text: &responses.OutputContentItemText{Text: "hello"},
+				}}},
+			}}}},
+			Usage: &responses.Usage{},
+		}}
+		cm := newModel(client)
+		input := []*schema.Message{schema.SystemMessage("be brief"), schema.UserMessage("hi")}
+		tools := []*schema.ToolInfo{{Name: "search", Desc: "search the web"}}
+
+		req, err := cm.BuildRequestOnly(ctx, input, model.WithTools(tools), model.WithTemperature(0.5))
+		assert.NoError(t, err)
+		assert.Nil(t, client.req)
+		assert.Equal(t, "test-model", req.Model)
+		assert.Len(t, req.Tools, 1)
+
+		_, err = cm.Generate(ctx, input, model.WithTools(tools), model.WithTemperature(0.5))
+		assert.NoError(t, err)
+		built, err := json.Marshal(req)
+		assert.NoError(t, err)
+		sent, err := json.Marshal(client.req)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(sent), string(built))
+	})
+
+	t.Run("fetch conversation without store client", func(t *testing.T) {
+		cm := newModel(&fakeResponsesClient{})
+
+		_, err := cm.FetchConversation(ctx, "resp-1")
+		assert.ErrorContains(t, err, "ResponsesStoreClient")
+	})
+
+	t.Run("interceptor", func(t *testing.T) {
+		redactor, err := pii.NewRedactor(nil)
+		assert.NoError(t, err)
+		// the placeholders are stable across the requests of the redactor
+		redacted, _, err := redactor.Intercept(ctx, []*schema.Message{schema.UserMessage("a@example.com")})
+		assert.NoError(t, err)
+		placeholder := redacted[0].Content
+		client := &fakeResponsesClient{resp: &responses.ResponseObject{
+			Id:     "resp-1",
+			Status: responses.ResponseStatus_completed,
+			Output: []*responses.OutputItem{{Union: &responses.OutputItem_OutputMessage{OutputMessage: &responses.ItemOutputMessage{
+				Content: []*responses.OutputContentItem{{Union: &responses.OutputContentItem_Text{
+					Text: &responses.OutputContentItemText{Text: "mailed " + placeholder},
+				}}},
+			}}}},
+			Usage: &responses.Usage{},
+		}}
+		cm, err := NewResponsesAPIChatModel(ctx, &ResponsesAPIConfig{Model: "test-model", Client: client, Interceptor: redactor})
+		assert.NoError(t, err)
+
+		msg, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("mail a@example.com")})
+		assert.NoError(t, err)
+		assert.Equal(t, "mailed a@example.com", msg.Content)
+		req, err := json.Marshal(client.req)
+		assert.NoError(t, err)
+		assert.Contains(t, string(req), strings.Trim(placeholder, "<>"))
+		assert.NotContains(t, string(req), "a@example.com")
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		_, err := NewResponsesAPIChatModel(ctx, &ResponsesAPIConfig{Model: "test-model"})
+		assert.ErrorContains(t, err, "missing credentials")
+	})
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+// mutatingResponsesClient modifies the requests and headers it receives, like a client decorating them would,
+// so that the race detector reports any state of the model shared by the concurrent calls.
+type mutatingResponsesClient struct{}
+
+func (c *mutatingResponsesClient) CreateResponses(_ context.Context, req *responses.ResponsesRequest,
+	headers map[string]string) (*responses.ResponseObject, error) {
+	mutateRequest(req, headers)
+	return &responses.ResponseObject{
+		Status: responses.ResponseStatus_completed,
+		Output: []*responses.OutputItem{{Union: &responses.OutputItem_OutputMessage{OutputMessage: &responses.ItemOutputMessage{
+			Content: []*responses.OutputContentItem{{Union: &responses.OutputContentItem_Text{
+				Text: &responses.OutputContentItemText{Text: "hello"},
+			}}},
+		}}}},
+		Usage: &responses.Usage{},
+	}, nil
+}
+
+func (c *mutatingResponsesClient) CreateResponsesStream(_ context.Context, req *responses.ResponsesRequest,
+	headers map[string]string) (ResponsesStreamReader, error) {
+	mutateRequest(req, headers)
+	return &fakeResponsesStream{events: []*responses.Event{
+		{Event: &responses.Event_Text{Text: &responses.OutputTextEvent{Delta: ptrOf("hel")}}},
+		{Event: &responses.Event_Text{Text: &responses.OutputTextEvent{Delta: ptrOf("lo")}}},
+	}}, nil
+}
+
+func mutateRequest(req *responses.ResponsesRequest, headers map[string]string) {
+	for _, t := range req.Tools {
+		if f := t.GetToolFunction(); f != nil {
+			f.Description = ptrOf("mutated")
+		}
+	}
+	if req.Text != nil && req.Text.Format != nil {
+		req.Text.Format.Description = ptrOf("mutated")
+		req.Text.Format.Strict = ptrOf(false)
+	}
+	req.Model = "mutated"
+	if headers != nil {
+		headers["X-Mutated"] = "1"
+	}
+}
+
+func TestResponsesAPIChatModel_Concurrency(t *testing.T) {
+	ctx := context.Background()
+	cm, err := NewResponsesAPIChatModel(ctx, &ResponsesAPIConfig{
+		Model:        "test-model",
+		CustomHeader: map[string]string{"X-Test": "1"},
+		Client:       &mutatingResponsesClient{},
+		Thinking:     &arkModel.Thinking{Type: arkModel.ThinkingTypeEnabled},
+		ResponseFormat: &ResponseFormat{
+			Type: arkModel.ResponseFormatJSONSchema,
+			JSONSchema: &arkModel.ResponseFormatJSONSchemaJSONSchemaParam{
+				Name:        "answer",
+				Description: "the answer",
+				Schema:      map[string]any{"type": "object", "properties": map[string]any{}, "additionalProperties": false},
+				Strict:      true,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	tools := []*schema.ToolInfo{{Name: "search", Desc: "search the web"}}
+	bound, err := cm.WithTools(tools)
+	assert.NoError(t, err)
+	boundCM := bound.(*ResponsesAPIChatModel)
+
+	input := []*schema.Message{schema.SystemMessage("be brief"), schema.UserMessage("hi")}
+	calls := []func() error{
+		func() error {
+			_, err := boundCM.Generate(ctx, input)
+			return err
+		},
+		func() error {
+			sr, err := boundCM.Stream(ctx, input, model.WithTemperature(0.5))
+			if err != nil {
+				return err
+			}
+			defer sr.Close()
+			for {
+				if _, err = sr.Recv(); errors.Is(err, io.EOF) {
+					return nil
+				} else if err != nil {
+					return err
+				}
+			}
+		},
+		func() error {
+			_, err := boundCM.BuildRequestOnly(ctx, input, WithCustomHeader(map[string]string{"X-Call": "1"}))
+			return err
+		},
+		func() error {
+			clone, err := cm.WithTools([]*schema.ToolInfo{{Name: "lookup", Desc: "look up the docs"}})
+			if err != nil {
+				return err
+			}
+			_, err = clone.Generate(ctx, input, model.WithToolChoice(schema.ToolChoiceForced))
+			return err
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16*len(calls))
+	for i := 0; i < 16; i++ {
+		for _, call := range calls {
+			wg.Add(1)
+			go func(call func() error) {
+				defer wg.Done()
+				errs <- call()
+			}(call)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	// the requests modified by the client do not leak into the model
+	req, err := boundCM.BuildRequestOnly(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-model", req.Model)
+	assert.Equal(t, "search the web", req.Tools[0].GetToolFunction().GetDescription())
+	assert.Equal(t, "the answer", req.Text.Format.GetDescription())
+	assert.True(t, req.Text.Format.GetStrict())
+	assert.Equal(t, map[string]string{"X-Test": "1"}, boundCM.customHeader)
+}
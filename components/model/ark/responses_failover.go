@@ -0,0 +1,328 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+const (
+	defaultFailoverThreshold        = 3
+	defaultFailoverRecoveryInterval = 30 * time.Second
+	defaultFailoverConnectTimeout   = 3 * time.Second
+
+	// maxTrackedResponses bounds the number of the response IDs whose endpoint is remembered.
+	maxTrackedResponses = 1024
+)
+
+// FailoverConfig configures the failover of ResponsesAPIChatModel to a backup Ark endpoint, e.g. in another region,
+// so that an incident of a single region does not take down the model.
+//
+// A request failing to connect to the primary endpoint is retried once on the backup endpoint.
+// After FailureThreshold consecutive failures of the primary endpoint, all the requests go to the backup endpoint,
+// and every RecoveryInterval one request checks the health of the primary endpoint;
+// the requests go back to the primary endpoint once it succeeds.
+// Only the creation of a response fails over, errors in the middle of a stream are returned as is.
+// The endpoint creating a response is remembered for the latest responses, so that the requests continuing it
+// with previous_response_id, its cancellation and the reads of the stored response go to the same endpoint.
+// Requests continuing a stored response never fail over, the stored response is not found on the other endpoint.
+type FailoverConfig struct {
+	// BaseURL is the base URL of the backup endpoint.
+	// Optional. Default: the BaseURL of ResponsesAPIConfig
+	BaseURL string `json:"base_url"`
+
+	// Region is the region of the backup endpoint.
+	// Optional. Default: the Region of ResponsesAPIConfig
+	Region string `json:"region"`
+
+	// FailureThreshold is the number of consecutive failures of the primary endpoint switching to the backup endpoint.
+	// Optional. Default: 3
+	FailureThreshold int `json:"failure_threshold,omitempty"`
+
+	// RecoveryInterval is the interval between the health checks of the primary endpoint after a failover.
+	// Optional. Default: 30s
+	RecoveryInterval time.Duration `json:"recovery_interval,omitempty"`
+
+	// ConnectTimeout is the timeout of connecting to the primary endpoint, including the TLS handshake,
+	// so that an unreachable endpoint fails over quickly. Only applied to the default dialer of an *http.Transport.
+	// Optional. Default: 3s
+	ConnectTimeout time.Duration `json:"connect_timeout,omitempty"`
+
+	// IsFailure reports whether an error of the primary endpoint triggers the failover.
+	// Optional. Default: IsFailoverError
+	IsFailure func(err error) bool `json:"-"`
+}
+
+// IsFailoverError reports whether err is a connection error, i.e. the request did not reach the endpoint,
+// the errors triggering the failover by default. Timeouts and error responses do not fail over,
+// since the endpoint may already have processed the request.
+func IsFailoverError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// setConnectTimeout bounds the time client takes to connect, if it uses the default dialer of an *http.Transport.
+func setConnectTimeout(client *http.Client, timeout time.Duration) {
+	t, ok := client.Transport.(*extraBodyTransport)
+	if !ok {
+		return
+	}
+	base, ok := t.base.(*http.Transport)
+	if !ok || base != http.DefaultTransport && base.DialContext != nil {
+		return
+	}
+	nBase := base.Clone()
+	nBase.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+	nBase.TLSHandshakeTimeout = timeout
+	client.Transport = &extraBodyTransport{base: nBase}
+}
+
+// failoverResponsesClient sends the requests to the primary client, failing over to the backup client.
+// It is a circuit breaker: open after threshold consecutive failures, half-open every recoveryInterval.
+type failoverResponsesClient struct {
+	primary          ResponsesClient
+	backup           ResponsesClient
+	threshold        int
+	recoveryInterval time.Duration
+	isFailure        func(err error) bool
+	now              func() time.Time
+
+	mu         sync.Mutex
+	failures   int
+	failedOver bool
+	nextCheck  time.Time
+	// origins maps the IDs of the latest responses to the client creating them, originIDs in creation order.
+	origins   map[string]ResponsesClient
+	originIDs []string
+}
+
+func newFailoverResponsesClient(primary, backup ResponsesClient, config *FailoverConfig) *failoverResponsesClient {
+	c := &failoverResponsesClient{
+		primary:          primary,
+		backup:           backup,
+		threshold:        config.FailureThreshold,
+		recoveryInterval: config.RecoveryInterval,
+		isFailure:        config.IsFailure,
+		now:              time.Now,
+		origins:          make(map[string]ResponsesClient),
+	}
+	if c.threshold <= 0 {
+		c.threshold = defaultFailoverThreshold
+	}
+	if c.recoveryInterval <= 0 {
+		c.recoveryInterval = defaultFailoverRecoveryInterval
+	}
+	if c.isFailure == nil {
+		c.isFailure = IsFailoverError
+	}
+	return c
+}
+
+// usePrimary reports whether the next request goes to the primary client,
+// letting one request check the primary client every recoveryInterval after a failover.
+func (c *failoverResponsesClient) usePrimary() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.failedOver {
+		return true
+	}
+	if now := c.now(); !now.Before(c.nextCheck) {
+		c.nextCheck = now.Add(c.recoveryInterval)
+		return true
+	}
+	return false
+}
+
+// report records the result of a request to the primary client, returning whether it failed.
+func (c *failoverResponsesClient) report(err error) bool {
+	failed := err != nil && c.isFailure(err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !failed {
+		c.failures, c.failedOver = 0, false
+		return false
+	}
+	c.failures++
+	if !c.failedOver && c.failures >= c.threshold {
+		c.failedOver = true
+		c.nextCheck = c.now().Add(c.recoveryInterval)
+	}
+	return true
+}
+
+// isFailedOver reports whether the requests go to the backup client.
+func (c *failoverResponsesClient) isFailedOver() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.failedOver
+}
+
+// track remembers that client created the response of responseID, forgetting the oldest response if needed.
+func (c *failoverResponsesClient) track(responseID string, client ResponsesClient) {
+	if responseID == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.origins[responseID]; !ok {
+		if len(c.originIDs) >= maxTrackedResponses {
+			delete(c.origins, c.originIDs[0])
+			c.originIDs = c.originIDs[1:]
+		}
+		c.originIDs = append(c.originIDs, responseID)
+	}
+	c.origins[responseID] = client
+}
+
+// originOf returns the client which created the response of responseID, if it is remembered.
+func (c *failoverResponsesClient) originOf(responseID string) (ResponsesClient, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	client, ok := c.origins[responseID]
+	return client, ok
+}
+
+// clientOf returns the client which created the response of responseID,
+// or the client currently in use if the response is not remembered.
+func (c *failoverResponsesClient) clientOf(responseID string) ResponsesClient {
+	if client, ok := c.originOf(responseID); ok {
+		return client
+	}
+	if c.isFailedOver() {
+		return c.backup
+	}
+	return c.primary
+}
+
+// callWithFailover calls the primary client, retrying on the backup client if it fails, and returns the client called last.
+// Requests continuing a stored response with previous_response_id go to the client which created it if it is remembered,
+// and are never retried.
+func callWithFailover[T any](ctx context.Context, c *failoverResponsesClient, req *responses.ResponsesRequest,
+	call func(client ResponsesClient) (T, error)) (T, ResponsesClient, error) {
+	previousID := req.GetPreviousResponseId()
+	if previousID != "" {
+		if client, ok := c.originOf(previousID); ok {
+			resp, err := call(client)
+			return resp, client, err
+		}
+	}
+	if !c.usePrimary() {
+		resp, err := call(c.backup)
+		return resp, c.backup, err
+	}
+	resp, err := call(c.primary)
+	if c.report(err) && ctx.Err() == nil && previousID == "" {
+		resp, err = call(c.backup)
+		return resp, c.backup, err
+	}
+	return resp, c.primary, err
+}
+
+func (c *failoverResponsesClient) CreateResponses(ctx context.Context, req *responses.ResponsesRequest,
+	headers map[string]string) (*responses.ResponseObject, error) {
+	resp, client, err := callWithFailover(ctx, c, req, func(client ResponsesClient) (*responses.ResponseObject, error) {
+		return client.CreateResponses(ctx, req, headers)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.track(resp.GetId(), client)
+	return resp, nil
+}
+
+func (c *failoverResponsesClient) CreateResponsesStream(ctx context.Context, req *responses.ResponsesRequest,
+	headers map[string]string) (ResponsesStreamReader, error) {
+	sr, client, err := callWithFailover(ctx, c, req, func(client ResponsesClient) (ResponsesStreamReader, error) {
+		return client.CreateResponsesStream(ctx, req, headers)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &originTrackingStream{ResponsesStreamReader: sr, c: c, client: client}, nil
+}
+
+// GetResponses reads the stored response from the endpoint which created it,
+// or from the endpoint currently in use if the response is not remembered.
+func (c *failoverResponsesClient) GetResponses(ctx context.Context, responseID string,
+	headers map[string]string) (*responses.ResponseObject, error) {
+	storeClient, err := c.storeClient(responseID)
+	if err != nil {
+		return nil, err
+	}
+	return storeClient.GetResponses(ctx, responseID, headers)
+}
+
+func (c *failoverResponsesClient) ListResponseInputItems(ctx context.Context, req *responses.ListInputItemsRequest,
+	headers map[string]string) (*responses.ListInputItemsResponse, error) {
+	storeClient, err := c.storeClient(req.GetResponseId())
+	if err != nil {
+		return nil, err
+	}
+	return storeClient.ListResponseInputItems(ctx, req, headers)
+}
+
+// CancelResponses cancels the response on the endpoint which created it,
+// or on the endpoint currently in use if the response is not remembered.
+func (c *failoverResponsesClient) CancelResponses(ctx context.Context, responseID string,
+	headers map[string]string) (*responses.ResponseObject, error) {
+	cancelClient, ok := c.clientOf(responseID).(ResponsesCancelClient)
+	if !ok {
+		return nil, errors.New("responses client does not implement ResponsesCancelClient")
+	}
+	return cancelClient.CancelResponses(ctx, responseID, headers)
+}
+
+func (c *failoverResponsesClient) storeClient(responseID string) (ResponsesStoreClient, error) {
+	storeClient, ok := c.clientOf(responseID).(ResponsesStoreClient)
+	if !ok {
+		return nil, errors.New("responses client does not implement ResponsesStoreClient")
+	}
+	return storeClient, nil
+}
+
+// originTrackingStream remembers the client creating the streamed response once its ID is received.
+type originTrackingStream struct {
+	ResponsesStreamReader
+	c      *failoverResponsesClient
+	client ResponsesClient
+}
+
+func (s *originTrackingStream) Recv() (*responses.Event, error) {
+	event, err := s.ResponsesStreamReader.Recv()
+	if err == nil {
+		if id := event.GetResponse().GetResponse().GetId(); id != "" {
+			s.c.track(id, s.client)
+		}
+	}
+	return event, err
+}
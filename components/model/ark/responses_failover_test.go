@@ -0,0 +1,245 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	arkModel "github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+type countingResponsesClient struct {
+	fakeResponsesClient
+	calls int
+}
+
+func (c *countingResponsesClient) CreateResponses(ctx context.Context, req *responses.ResponsesRequest,
+	headers map[string]string) (*responses.ResponseObject, error) {
+	c.calls++
+	return c.fakeResponsesClient.CreateResponses(ctx, req, headers)
+}
+
+// connErr is the error of the ark sdk for a request failing to connect.
+var connErr = arkModel.NewRequestError(http.StatusInternalServerError, &url.Error{
+	Op:  "Post",
+	URL: "https://ark.cn-beijing.volces.com/api/v3/responses",
+	Err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+}, "")
+
+func TestIsFailoverError(t *testing.T) {
+	assert.False(t, IsFailoverError(nil))
+	assert.False(t, IsFailoverError(context.Canceled))
+	assert.False(t, IsFailoverError(context.DeadlineExceeded))
+	assert.False(t, IsFailoverError(&arkModel.APIError{HTTPStatusCode: http.StatusBadRequest}))
+	assert.False(t, IsFailoverError(&arkModel.APIError{HTTPStatusCode: http.StatusServiceUnavailable}))
+	assert.False(t, IsFailoverError(errors.New("invalid request")))
+	assert.False(t, IsFailoverError(arkModel.NewRequestError(http.StatusInternalServerError, &url.Error{
+		Op: "Post", Err: &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded},
+	}, "")))
+	assert.True(t, IsFailoverError(connErr))
+	assert.True(t, IsFailoverError(&url.Error{Op: "Post", Err: &net.DNSError{Err: "no such host", Name: "ark"}}))
+	assert.True(t, IsFailoverError(fmt.Errorf("send: %w", syscall.ECONNREFUSED)))
+}
+
+func TestSetConnectTimeout(t *testing.T) {
+	client := newExtraBodyHTTPClient(nil, nil)
+	setConnectTimeout(client, time.Second)
+	base := client.Transport.(*extraBodyTransport).base.(*http.Transport)
+	assert.NotSame(t, http.DefaultTransport, base)
+	assert.NotNil(t, base.DialContext)
+	assert.Equal(t, time.Second, base.TLSHandshakeTimeout)
+
+	// a custom dialer is kept
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) { return nil, errors.New("custom") }
+	client = newExtraBodyHTTPClient(&http.Client{Transport: &http.Transport{DialContext: dial}}, nil)
+	setConnectTimeout(client, time.Second)
+	assert.Zero(t, client.Transport.(*extraBodyTransport).base.(*http.Transport).TLSHandshakeTimeout)
+}
+
+func TestFailoverResponsesClient(t *testing.T) {
+	ctx := context.Background()
+	req := &responses.ResponsesRequest{Model: "test-model"}
+	primaryResp := &responses.ResponseObject{Id: "primary"}
+	backupResp := &responses.ResponseObject{Id: "backup"}
+
+	newClient := func() (*failoverResponsesClient, *countingResponsesClient, *countingResponsesClient, *time.Time) {
+		primary := &countingResponsesClient{fakeResponsesClient: fakeResponsesClient{resp: primaryResp}}
+		backup := &countingResponsesClient{fakeResponsesClient: fakeResponsesClient{resp: backupResp}}
+		now := time.Unix(0, 0)
+		c := newFailoverResponsesClient(primary, backup, &FailoverConfig{FailureThreshold: 2, RecoveryInterval: time.Minute})
+		c.now = func() time.Time { return now }
+		return c, primary, backup, &now
+	}
+
+	t.Run("primary healthy", func(t *testing.T) {
+		c, primary, backup, _ := newClient()
+		resp, err := c.CreateResponses(ctx, req, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "primary", resp.Id)
+		assert.Equal(t, 1, primary.calls)
+		assert.Equal(t, 0, backup.calls)
+	})
+
+	t.Run("client error not retried", func(t *testing.T) {
+		c, primary, backup, _ := newClient()
+		primary.err = &arkModel.APIError{HTTPStatusCode: http.StatusBadRequest, Message: "bad request"}
+		_, err := c.CreateResponses(ctx, req, nil)
+		assert.ErrorContains(t, err, "bad request")
+		assert.Equal(t, 0, backup.calls)
+		assert.False(t, c.isFailedOver())
+	})
+
+	t.Run("fail over and recover", func(t *testing.T) {
+		c, primary, backup, now := newClient()
+		primary.err = connErr
+
+		// each failure of the primary endpoint is retried on the backup endpoint
+		for i := 0; i < 2; i++ {
+			resp, err := c.CreateResponses(ctx, req, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, "backup", resp.Id)
+		}
+		assert.True(t, c.isFailedOver())
+		assert.Equal(t, 2, primary.calls)
+
+		// the primary endpoint is skipped until the recovery interval passes
+		_, err := c.CreateResponses(ctx, req, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, primary.calls)
+		assert.Equal(t, 3, backup.calls)
+
+		// the health check fails, the requests stay on the backup endpoint
+		*now = now.Add(time.Minute)
+		resp, err := c.CreateResponses(ctx, req, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "backup", resp.Id)
+		assert.Equal(t, 3, primary.calls)
+		assert.True(t, c.isFailedOver())
+
+		// the health check succeeds, the requests go back to the primary endpoint
+		primary.err = nil
+		*now = now.Add(time.Minute)
+		resp, err = c.CreateResponses(ctx, req, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "primary", resp.Id)
+		assert.False(t, c.isFailedOver())
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		c, primary, _, _ := newClient()
+		primary.err = connErr
+		sr, err := c.CreateResponsesStream(ctx, req, nil)
+		assert.NoError(t, err)
+		assert.NotNil(t, sr)
+	})
+
+	t.Run("timeout not retried", func(t *testing.T) {
+		c, primary, backup, _ := newClient()
+		primary.err = context.DeadlineExceeded
+		_, err := c.CreateResponses(ctx, req, nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, backup.calls)
+		assert.False(t, c.isFailedOver())
+	})
+
+	t.Run("previous response not retried", func(t *testing.T) {
+		c, primary, backup, _ := newClient()
+		primary.err = connErr
+		prev := "resp_1"
+		_, err := c.CreateResponses(ctx, &responses.ResponsesRequest{Model: "test-model", PreviousResponseId: &prev}, nil)
+		assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+		assert.Equal(t, 0, backup.calls)
+	})
+
+	t.Run("canceled context not retried", func(t *testing.T) {
+		c, primary, backup, _ := newClient()
+		primary.err = connErr
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := c.CreateResponses(cctx, req, nil)
+		assert.ErrorIs(t, err, syscall.ECONNREFUSED)
+		assert.Equal(t, 0, backup.calls)
+	})
+
+	t.Run("route to the origin of the response", func(t *testing.T) {
+		c, primary, backup, _ := newClient()
+		primary.canceled = make(chan string, 1)
+		backup.canceled = make(chan string, 1)
+
+		// the failure is retried on the backup endpoint, the primary endpoint stays in use
+		primary.err = connErr
+		resp, err := c.CreateResponses(ctx, req, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "backup", resp.Id)
+		assert.False(t, c.isFailedOver())
+		primary.err = nil
+
+		_, err = c.CancelResponses(ctx, "backup", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "backup", <-backup.canceled)
+
+		// the continuation goes to the backup endpoint storing the previous response
+		prev := "backup"
+		_, err = c.CreateResponses(ctx, &responses.ResponsesRequest{Model: "test-model", PreviousResponseId: &prev}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, backup.calls)
+		assert.Equal(t, 1, primary.calls)
+
+		// unknown responses go to the endpoint in use
+		_, err = c.CancelResponses(ctx, "unknown", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "unknown", <-primary.canceled)
+
+		// the ID of a streamed response is remembered once received
+		primary.err = connErr
+		backup.events = []*responses.Event{
+			{Event: &responses.Event_Response{Response: &responses.ResponseEvent{Response: &responses.ResponseObject{Id: "stream"}}}},
+		}
+		sr, err := c.CreateResponsesStream(ctx, req, nil)
+		assert.NoError(t, err)
+		_, err = sr.Recv()
+		assert.NoError(t, err)
+		primary.err = nil
+		_, err = c.CancelResponses(ctx, "stream", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "stream", <-backup.canceled)
+	})
+
+	t.Run("forget the oldest responses", func(t *testing.T) {
+		c, _, backup, _ := newClient()
+		for i := 0; i <= maxTrackedResponses; i++ {
+			c.track(fmt.Sprintf("resp-%d", i), backup)
+		}
+		_, ok := c.originOf("resp-0")
+		assert.False(t, ok)
+		client, ok := c.originOf(fmt.Sprintf("resp-%d", maxTrackedResponses))
+		assert.True(t, ok)
+		assert.Same(t, backup, client)
+		assert.Len(t, c.origins, maxTrackedResponses)
+	})
+}
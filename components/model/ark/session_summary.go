@@ -0,0 +1,165 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultSessionSummaryExpiryThreshold = 300
+
+	defaultSessionSummaryInstruction = "Summarize the conversation above so that it can be continued from the summary alone. " +
+		"Keep the facts, decisions, tool results, open questions and preferences of the user, and omit small talk. " +
+		"Reply with the summary only."
+
+	sessionSummaryPrefix = "Summary of the earlier conversation:\n"
+)
+
+// renewSessionCache renews the session cache of the input if it expires within the threshold of SessionCacheConfig.AutoSummary.
+// The messages up to the cached one are summarized and cached as a fresh prefix cache,
+// and the returned options continue the conversation from the prefix cache with the messages after the cached one.
+func (cm *ResponsesAPIChatModel) renewSessionCache(ctx context.Context, in []*schema.Message,
+	specOptions *arkOptions) ([]*schema.Message, *arkOptions, error) {
+	sCache := cm.sessionCacheOf(specOptions)
+	if sCache == nil || !sCache.EnableCache || sCache.AutoSummary == nil || specOptions.previousResponseID != nil {
+		return in, specOptions, nil
+	}
+	// the request fails as the session cache is not allowed, so nothing is summarized
+	if cm.storeResponses != nil && !*cm.storeResponses {
+		return in, specOptions, nil
+	}
+	if specOptions.cache != nil && specOptions.cache.ContextID != nil {
+		return in, specOptions, nil
+	}
+
+	autoSummary := sCache.AutoSummary
+	threshold := int64(autoSummary.ExpiryThreshold)
+	if threshold <= 0 {
+		threshold = defaultSessionSummaryExpiryThreshold
+	}
+
+	now := time.Now().Unix()
+	idx := -1
+	for i := len(in) - 1; i >= 0; i-- {
+		expireAtSec, ok := GetCacheExpiration(in[i])
+		if !ok || expireAtSec < now {
+			continue
+		}
+		if _, ok = GetResponseID(in[i]); ok {
+			if expireAtSec-now <= threshold {
+				idx = i
+			}
+			break
+		}
+	}
+	if idx < 0 || idx+1 >= len(in) {
+		return in, specOptions, nil
+	}
+
+	ttl := autoSummary.TTL
+	if ttl <= 0 {
+		ttl = sCache.TTL
+	}
+	respID, err := cm.createSummaryPrefixCache(ctx, in[:idx+1], autoSummary, ttl)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to renew the expiring session cache: %w", err)
+	}
+
+	nOptions := *specOptions
+	nOptions.previousResponseID = &respID
+	return in[idx+1:], &nOptions, nil
+}
+
+// sessionCacheOf returns the session cache config of the request, the option takes precedence over the config.
+func (cm *ResponsesAPIChatModel) sessionCacheOf(specOptions *arkOptions) *SessionCacheConfig {
+	if specOptions.cache != nil && specOptions.cache.SessionCache != nil {
+		return specOptions.cache.SessionCache
+	}
+	if cm.cache != nil {
+		return cm.cache.SessionCache
+	}
+	return nil
+}
+
+// createSummaryPrefixCache summarizes the history and caches the summary after the system messages of the history.
+func (cm *ResponsesAPIChatModel) createSummaryPrefixCache(ctx context.Context, history []*schema.Message,
+	autoSummary *SessionCacheAutoSummary, ttl int) (string, error) {
+	promptBuilder := autoSummary.PromptBuilder
+	if promptBuilder == nil {
+		promptBuilder = defaultSessionSummaryPrompt
+	}
+	prompt, err := promptBuilder(ctx, history)
+	if err != nil {
+		return "", fmt.Errorf("failed to build summary prompt: %w", err)
+	}
+
+	// summarize without tools and session cache, the callbacks are only reported by the request being built
+	summarizer := *cm
+	summarizer.disableCallbacks = true
+	summarizer.tools = nil
+	summarizer.rawTools = nil
+	summarizer.toolChoice = nil
+	summary, err := summarizer.Generate(ctx, prompt, WithCache(&CacheOption{
+		SessionCache: &SessionCacheConfig{EnableCache: false},
+	}))
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize history: %w", err)
+	}
+
+	var prefix []*schema.Message
+	for _, msg := range history {
+		if msg.Role == schema.System {
+			prefix = append(prefix, msg)
+		}
+	}
+	prefix = append(prefix, schema.UserMessage(sessionSummaryPrefix+summary.Content))
+
+	info, err := cm.CreatePrefixCache(ctx, prefix, ttl)
+	if err != nil {
+		return "", fmt.Errorf("failed to create prefix cache: %w", err)
+	}
+	return info.ResponseID, nil
+}
+
+// defaultSessionSummaryPrompt asks the model to summarize a transcript of the non-system messages of the history.
+func defaultSessionSummaryPrompt(_ context.Context, history []*schema.Message) ([]*schema.Message, error) {
+	var sb strings.Builder
+	for _, msg := range history {
+		if msg.Role == schema.System {
+			continue
+		}
+		if msg.Content != "" {
+			if msg.Role == schema.Tool && msg.ToolName != "" {
+				sb.WriteString(fmt.Sprintf("tool %s: %s\n", msg.ToolName, msg.Content))
+			} else {
+				sb.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, msg.Content))
+			}
+		}
+		for _, tc := range msg.ToolCalls {
+			sb.WriteString(fmt.Sprintf("%s called tool %s: %s\n", msg.Role, tc.Function.Name, tc.Function.Arguments))
+		}
+	}
+	return []*schema.Message{
+		schema.UserMessage("Conversation:\n" + sb.String() + "\n" + defaultSessionSummaryInstruction),
+	}, nil
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenewSessionCache(t *testing.T) {
+	PatchConvey("test renewSessionCache", t, func() {
+		ctx := context.Background()
+		now := time.Now().Unix()
+
+		cached := &schema.Message{Role: schema.Assistant, Content: "the answer is 42"}
+		setResponseID(cached, "resp-1")
+		setResponseCacheExpireAt(cached, arkResponseCacheExpireAt(now+60))
+		in := []*schema.Message{
+			schema.SystemMessage("you are a helpful assistant"),
+			schema.UserMessage("what is the answer?"),
+			cached,
+			schema.UserMessage("why?"),
+		}
+
+		cm := &ResponsesAPIChatModel{cache: &CacheConfig{SessionCache: &SessionCacheConfig{
+			EnableCache: true,
+			TTL:         3600,
+			AutoSummary: &SessionCacheAutoSummary{},
+		}}}
+
+		var (
+			summaryPrompt []*schema.Message
+			summaryOpts   *arkOptions
+			prefix        []*schema.Message
+			prefixTTL     int
+			prefixErr     error
+			summarizer    *ResponsesAPIChatModel
+		)
+		Mock((*ResponsesAPIChatModel).Generate).To(func(m *ResponsesAPIChatModel, _ context.Context, input []*schema.Message,
+			opts ...model.Option) (*schema.Message, error) {
+			summarizer = m
+			summaryPrompt = input
+			summaryOpts = model.GetImplSpecificOptions(&arkOptions{}, opts...)
+			return schema.AssistantMessage("the user asked for the answer, which is 42", nil), nil
+		}).Build()
+		Mock((*ResponsesAPIChatModel).CreatePrefixCache).To(func(_ context.Context, msgs []*schema.Message,
+			ttl int, _ ...model.Option) (*CacheInfo, error) {
+			if prefixErr != nil {
+				return nil, prefixErr
+			}
+			prefix = msgs
+			prefixTTL = ttl
+			return &CacheInfo{ResponseID: "prefix-1"}, nil
+		}).Build()
+
+		PatchConvey("renew expiring cache", func() {
+			nIn, nOpts, err := cm.renewSessionCache(ctx, in, &arkOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, in[3:], nIn)
+			assert.Equal(t, "prefix-1", *nOpts.previousResponseID)
+			// the summarization does not report callbacks of its own
+			assert.True(t, summarizer.disableCallbacks)
+			assert.False(t, cm.disableCallbacks)
+
+			assert.Len(t, summaryPrompt, 1)
+			assert.Contains(t, summaryPrompt[0].Content, "user: what is the answer?\nassistant: the answer is 42\n")
+			assert.NotContains(t, summaryPrompt[0].Content, "helpful assistant")
+			assert.False(t, summaryOpts.cache.SessionCache.EnableCache)
+
+			assert.Equal(t, []*schema.Message{
+				in[0],
+				schema.UserMessage(sessionSummaryPrefix + "the user asked for the answer, which is 42"),
+			}, prefix)
+			assert.Equal(t, 3600, prefixTTL)
+		})
+
+		PatchConvey("build request renews expiring cache", func() {
+			cm.model = "test-model"
+			call, err := cm.buildRequest(ctx, in, nil, true)
+			assert.NoError(t, err)
+			assert.Equal(t, "prefix-1", call.req.GetPreviousResponseId())
+			assert.Len(t, call.req.GetInput().GetListValue().GetListValue(), 1)
+		})
+
+		PatchConvey("build request only does not renew the cache", func() {
+			cm.model = "test-model"
+			req, err := cm.BuildRequestOnly(ctx, in)
+			assert.NoError(t, err)
+			assert.Nil(t, summaryPrompt)
+			assert.Nil(t, prefix)
+			assert.NotEqual(t, "prefix-1", req.GetPreviousResponseId())
+		})
+
+		PatchConvey("custom prompt and ttl", func() {
+			cm.cache.SessionCache.AutoSummary = &SessionCacheAutoSummary{
+				TTL: 600,
+				PromptBuilder: func(_ context.Context, history []*schema.Message) ([]*schema.Message, error) {
+					return []*schema.Message{schema.UserMessage(fmt.Sprintf("summarize %d messages", len(history)))}, nil
+				},
+			}
+			_, nOpts, err := cm.renewSessionCache(ctx, in, &arkOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, "prefix-1", *nOpts.previousResponseID)
+			assert.Equal(t, "summarize 3 messages", summaryPrompt[0].Content)
+			assert.Equal(t, 600, prefixTTL)
+		})
+
+		PatchConvey("cache not expiring", func() {
+			setResponseCacheExpireAt(cached, arkResponseCacheExpireAt(now+3600))
+			nIn, nOpts, err := cm.renewSessionCache(ctx, in, &arkOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, in, nIn)
+			assert.Nil(t, nOpts.previousResponseID)
+			assert.Nil(t, prefix)
+		})
+
+		PatchConvey("auto summary disabled", func() {
+			opts := &arkOptions{cache: &CacheOption{SessionCache: &SessionCacheConfig{EnableCache: true}}}
+			nIn, nOpts, err := cm.renewSessionCache(ctx, in, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, in, nIn)
+			assert.Equal(t, opts, nOpts)
+			assert.Nil(t, prefix)
+		})
+
+		PatchConvey("previous response id set", func() {
+			opts := &arkOptions{previousResponseID: ptrOf("resp-0")}
+			nIn, nOpts, err := cm.renewSessionCache(ctx, in, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, in, nIn)
+			assert.Equal(t, opts, nOpts)
+		})
+
+		PatchConvey("no input after the cached message", func() {
+			nIn, _, err := cm.renewSessionCache(ctx, in[:3], &arkOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, in[:3], nIn)
+			assert.Nil(t, prefix)
+		})
+
+		PatchConvey("renewal failure", func() {
+			prefixErr = fmt.Errorf("too few tokens")
+			_, _, err := cm.renewSessionCache(ctx, in, &arkOptions{})
+			assert.ErrorIs(t, err, prefixErr)
+
+			cm.model = "test-model"
+			_, err = cm.buildRequest(ctx, in, nil, true)
+			assert.ErrorIs(t, err, prefixErr)
+		})
+	})
+}
+
+func TestDefaultSessionSummaryPrompt(t *testing.T) {
+	prompt, err := defaultSessionSummaryPrompt(context.Background(), []*schema.Message{
+		schema.SystemMessage("system"),
+		schema.UserMessage("weather in Paris?"),
+		schema.AssistantMessage("", []schema.ToolCall{{Function: schema.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`}}}),
+		schema.ToolMessage("sunny", "call-1", schema.WithToolName("weather")),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, prompt, 1)
+	assert.Equal(t, schema.User, prompt[0].Role)
+	assert.Equal(t, "Conversation:\n"+
+		"user: weather in Paris?\n"+
+		"assistant called tool weather: {\"city\":\"Paris\"}\n"+
+		"tool weather: sunny\n"+
+		"\n"+defaultSessionSummaryInstruction, prompt[0].Content)
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"fmt"
+
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino-ext/libs/tokenestimate"
+	"github.com/cloudwego/eino/schema"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
+)
+
+// TokenEstimator estimates the number of input tokens of a message.
+// Use it to plug in the tokenizer of the target model for precise estimation.
+type TokenEstimator = tokenestimate.Estimator
+
+// MessageTokenEstimate is the estimated token count of an input message.
+type MessageTokenEstimate struct {
+	// Index is the index of the message in the input sent to the model.
+	Index int
+	// Role is the role of the message.
+	Role schema.RoleType
+	// Tokens is the estimated token count of the message.
+	Tokens int
+}
+
+// InputTooLargeError is returned before sending the request when the estimated input tokens
+// exceed MaxInputTokens. Messages lists the estimate of every message, so callers can decide
+// which messages to trim or summarize.
+type InputTooLargeError struct {
+	// MaxInputTokens is the configured limit.
+	MaxInputTokens int
+	// EstimatedTokens is the estimated total input tokens, including tools.
+	EstimatedTokens int
+	// ToolTokens is the estimated tokens of the tool definitions.
+	ToolTokens int
+	// Messages is the per-message breakdown.
+	Messages []MessageTokenEstimate
+}
+
+func (e *InputTooLargeError) Error() string {
+	return fmt.Sprintf("estimated input tokens %d exceed max input tokens %d (messages: %d, tools: %d tokens)",
+		e.EstimatedTokens, e.MaxInputTokens, len(e.Messages), e.ToolTokens)
+}
+
+// checkInputTokens estimates the input tokens of the request and returns an *InputTooLargeError
+// if they exceed maxInputTokens.
+func checkInputTokens(in []*schema.Message, tools []*responses.ResponsesTool, maxInputTokens int, estimator TokenEstimator) error {
+	if maxInputTokens <= 0 {
+		return nil
+	}
+	if estimator == nil {
+		estimator = tokenestimate.Message
+	}
+
+	total := 0
+	estimates := make([]MessageTokenEstimate, 0, len(in))
+	for i, msg := range in {
+		n := estimator(msg)
+		total += n
+		estimates = append(estimates, MessageTokenEstimate{Index: i, Role: msg.Role, Tokens: n})
+	}
+
+	toolTokens := 0
+	if len(tools) > 0 {
+		b, err := sonic.Marshal(tools)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tools for token estimation: %w", err)
+		}
+		toolTokens = tokenestimate.Text(string(b))
+	}
+	total += toolTokens
+
+	if total <= maxInputTokens {
+		return nil
+	}
+	return &InputTooLargeError{
+		MaxInputTokens:  maxInputTokens,
+		EstimatedTokens: total,
+		ToolTokens:      toolTokens,
+		Messages:        estimates,
+	}
+}
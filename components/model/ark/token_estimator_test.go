@@ -0,0 +1,70 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ark
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/libs/tokenestimate"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckInputTokens(t *testing.T) {
+	in := []*schema.Message{
+		schema.SystemMessage("you are a helpful assistant"),
+		schema.UserMessage(strings.Repeat("a", 400)),
+	}
+
+	assert.NoError(t, checkInputTokens(in, nil, 0, nil))
+	assert.NoError(t, checkInputTokens(in, nil, 1000, nil))
+
+	err := checkInputTokens(in, nil, 50, nil)
+	var tooLarge *InputTooLargeError
+	assert.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, 50, tooLarge.MaxInputTokens)
+	assert.Len(t, tooLarge.Messages, 2)
+	assert.Equal(t, schema.User, tooLarge.Messages[1].Role)
+	assert.Equal(t, tokenestimate.MessageOverheadTokens+100, tooLarge.Messages[1].Tokens)
+	assert.Equal(t, tooLarge.Messages[0].Tokens+tooLarge.Messages[1].Tokens, tooLarge.EstimatedTokens)
+
+	err = checkInputTokens(in, nil, 5, func(msg *schema.Message) int { return 1 })
+	assert.NoError(t, err)
+}
+
+func TestResponsesAPIMaxInputTokens(t *testing.T) {
+	maxInputTokens := 10
+	cm := &ResponsesAPIChatModel{
+		model:          "model",
+		maxInputTokens: &maxInputTokens,
+	}
+	in := []*schema.Message{schema.UserMessage(strings.Repeat("a", 400))}
+
+	options, specOptions, err := cm.getOptions(nil)
+	assert.NoError(t, err)
+	_, err = cm.genRequestAndOptions(in, options, specOptions)
+	var tooLarge *InputTooLargeError
+	assert.True(t, errors.As(err, &tooLarge))
+
+	options, specOptions, err = cm.getOptions([]model.Option{WithMaxInputTokens(1000)})
+	assert.NoError(t, err)
+	_, err = cm.genRequestAndOptions(in, options, specOptions)
+	assert.NoError(t, err)
+}
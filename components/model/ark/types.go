@@ -17,6 +17,10 @@
 package ark
 
 import (
+	"context"
+
+	"github.com/cloudwego/eino-ext/libs/callbackextra"
+	"github.com/cloudwego/eino/schema"
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 )
 
@@ -34,6 +38,29 @@ type SessionCacheConfig struct {
 
 	// TTL specifies the survival time of cached data in seconds, with a maximum of 3 * 86400(3 days).
 	TTL int `json:"ttl"`
+
+	// AutoSummary renews the session cache when it is about to expire: the messages up to the cached one
+	// are summarized by the model, and the summary is cached as a fresh prefix cache the conversation continues from.
+	// A failed renewal fails the request.
+	// Only supported by the ResponsesAPI.
+	// Optional.
+	AutoSummary *SessionCacheAutoSummary `json:"auto_summary,omitempty"`
+}
+
+// SessionCacheAutoSummary configures the renewal of the expiring session caches, see SessionCacheConfig.AutoSummary.
+type SessionCacheAutoSummary struct {
+	// ExpiryThreshold is the remaining lifetime in seconds below which the session cache is renewed.
+	// Optional. Default: 300
+	ExpiryThreshold int `json:"expiry_threshold"`
+
+	// TTL is the survival time in seconds of the renewed prefix cache.
+	// Optional. Default: SessionCacheConfig.TTL
+	TTL int `json:"ttl"`
+
+	// PromptBuilder builds the messages asking the model to summarize the history.
+	// The system messages of the history are kept verbatim in the prefix cache, and need not be summarized.
+	// Optional. Default: a transcript of the history with the default summarization instructions.
+	PromptBuilder func(ctx context.Context, history []*schema.Message) ([]*schema.Message, error) `json:"-"`
 }
 
 type APIType string
@@ -51,6 +78,25 @@ type ResponseFormat struct {
 	JSONSchema *model.ResponseFormatJSONSchemaJSONSchemaParam `json:"json_schema,omitempty"`
 }
 
+// ReasoningSummary specifies the granularity of the reasoning summary returned by the ResponsesAPI.
+type ReasoningSummary string
+
+const (
+	ReasoningSummaryAuto     ReasoningSummary = "auto"
+	ReasoningSummaryConcise  ReasoningSummary = "concise"
+	ReasoningSummaryDetailed ReasoningSummary = "detailed"
+	ReasoningSummaryNone     ReasoningSummary = "none"
+)
+
+// TextVerbosity specifies the verbosity of the text output of the ResponsesAPI.
+type TextVerbosity string
+
+const (
+	TextVerbosityLow    TextVerbosity = "low"
+	TextVerbosityMedium TextVerbosity = "medium"
+	TextVerbosityHigh   TextVerbosity = "high"
+)
+
 type caching string
 
 const (
@@ -59,9 +105,10 @@ const (
 )
 
 const (
-	callbackExtraKeyThinking      = "thinking"
-	callbackExtraKeyPreResponseID = "ark-previous-response-id"
-	callbackExtraModelName        = "model_name"
+	callbackExtraKeyThinking       = "thinking"
+	callbackExtraKeyThinkingConfig = callbackextra.KeyThinkingConfig
+	callbackExtraKeyPreResponseID  = "ark-previous-response-id"
+	callbackExtraModelName         = callbackextra.KeyModelName
 )
 
 type toolChoice string
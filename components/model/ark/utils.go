@@ -18,6 +18,9 @@ package ark
 
 import (
 	"fmt"
+
+	"github.com/cloudwego/eino-ext/libs/callbackextra"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 )
 
 const typ = "Ark"
@@ -62,3 +65,16 @@ func newPanicErr(info any, stack []byte) error {
 		stack: stack,
 	}
 }
+
+// callbacksEnabled reports whether callbacks are enabled by the EnableCallbacks config, which defaults to true.
+func callbacksEnabled(enable *bool) bool {
+	return enable == nil || *enable
+}
+
+// thinkingExtra converts the thinking config into the one reported in the callback extra, nil if there is none.
+func thinkingExtra(thinking *model.Thinking) *callbackextra.Thinking {
+	if thinking == nil {
+		return nil
+	}
+	return &callbackextra.Thinking{Type: callbackextra.ThinkingType(thinking.Type)}
+}
@@ -19,10 +19,27 @@ package ark
 import (
 	"testing"
 
+	"github.com/cloudwego/eino-ext/libs/callbackextra"
 	"github.com/stretchr/testify/assert"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model/responses"
 )
 
 func TestPanicErr(t *testing.T) {
 	err := newPanicErr("info", []byte("stack"))
 	assert.Equal(t, "panic error: info, \nstack: stack", err.Error())
 }
+
+func TestThinkingExtra(t *testing.T) {
+	assert.Nil(t, thinkingExtra(nil))
+	assert.Equal(t, &callbackextra.Thinking{Type: callbackextra.ThinkingDisabled},
+		thinkingExtra(&model.Thinking{Type: model.ThinkingTypeDisabled}))
+
+	// the ark thinking config keeps its own key, next to the shared one
+	thinking := &model.Thinking{Type: model.ThinkingTypeEnabled}
+	call := &responsesCall{specOptions: &arkOptions{thinking: thinking}, req: &responses.ResponsesRequest{}}
+	assert.Equal(t, map[string]any{
+		"thinking":        thinking,
+		"thinking_config": &callbackextra.Thinking{Type: callbackextra.ThinkingEnabled},
+	}, call.callbackExtra())
+}
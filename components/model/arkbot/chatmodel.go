@@ -24,13 +24,13 @@ import (
 	"log"
 	"net/http"
 	"runtime/debug"
-	"strings"
 	"time"
 
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 	autils "github.com/volcengine/volcengine-go-sdk/service/arkruntime/utils"
 
+	"github.com/cloudwego/eino-ext/libs/multimodal"
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	fmodel "github.com/cloudwego/eino/components/model"
@@ -132,6 +132,10 @@ type Config struct {
 
 	// ResponseFormat specifies the format that the model must output.
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+
+	// MediaLimits, if set, fails the input messages violating it before the request is sent, see multimodal.Limits.
+	// Optional. Default: no limits
+	MediaLimits *multimodal.Limits `json:"media_limits,omitempty"`
 }
 
 type ResponseFormat struct {
@@ -433,6 +437,10 @@ func (cm *ChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...f
 }
 
 func (cm *ChatModel) genRequest(in []*schema.Message, options *fmodel.Options) (req *model.BotChatCompletionRequest, err error) {
+	if err = cm.config.MediaLimits.ValidateMessages(in); err != nil {
+		return nil, err
+	}
+
 	req = &model.BotChatCompletionRequest{
 		MaxTokens:        dereferenceOrZero(options.MaxTokens),
 		Temperature:      dereferenceOrZero(options.Temperature),
@@ -755,7 +763,7 @@ func toArkContent(msg *schema.Message) (*model.ChatCompletionMessageContent, err
 					if part.Image.MIMEType == "" {
 						return nil, fmt.Errorf("image part must have MIMEType when using Base64Data")
 					}
-					imageURL, err = ensureDataURL(*part.Image.Base64Data, part.Image.MIMEType)
+					imageURL, err = multimodal.DataURL(*part.Image.Base64Data, part.Image.MIMEType)
 					if err != nil {
 						return nil, err
 					}
@@ -797,7 +805,7 @@ func toArkContent(msg *schema.Message) (*model.ChatCompletionMessageContent, err
 					if part.Image.MIMEType == "" {
 						return nil, fmt.Errorf("image part must have MIMEType when using Base64Data")
 					}
-					imageURL, err = ensureDataURL(*part.Image.Base64Data, part.Image.MIMEType)
+					imageURL, err = multimodal.DataURL(*part.Image.Base64Data, part.Image.MIMEType)
 					if err != nil {
 						return nil, err
 					}
@@ -895,16 +903,6 @@ func toTools(tls []*schema.ToolInfo) ([]tool, error) {
 	return tools, nil
 }
 
-func ensureDataURL(dataOfBase64, mimeType string) (string, error) {
-	if strings.HasPrefix(dataOfBase64, "data:") {
-		return "", fmt.Errorf("base64Data field must be a raw base64 string, but got a string with prefix 'data:'")
-	}
-	if mimeType == "" {
-		return "", fmt.Errorf("mimeType field is required")
-	}
-	return fmt.Sprintf("data:%s;base64,%s", mimeType, dataOfBase64), nil
-}
-
 func closeArkStreamReader(r *autils.BotChatCompletionStreamReader) error {
 	if r == nil || r.Response == nil || r.Response.Body == nil {
 		return nil
@@ -29,6 +29,7 @@ import (
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
 	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/utils"
 
+	"github.com/cloudwego/eino-ext/libs/multimodal"
 	fmodel "github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 )
@@ -540,3 +541,20 @@ func Test_toArkContent(t *testing.T) {
 		})
 	})
 }
+
+func TestMediaLimits(t *testing.T) {
+	cm := &ChatModel{config: &Config{
+		MediaLimits: &multimodal.Limits{MaxInlineBytes: 4},
+	}}
+
+	_, err := cm.genRequest([]*schema.Message{
+		schema.UserMessage("hi"),
+		{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: "data:image/png;base64,aGVsbG8gd29ybGQ="}},
+		}},
+	}, &fmodel.Options{})
+	var ve *multimodal.ValidationError
+	assert.True(t, errors.As(err, &ve))
+	assert.Equal(t, "messages[1].multi_content[0].image_url", ve.Field)
+	assert.Contains(t, ve.Reason, "exceeds the limit")
+}
@@ -0,0 +1,44 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package claude
+
+import (
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+// minThinkingBudgetTokens is the minimum of Thinking.BudgetTokens accepted by Claude.
+const minThinkingBudgetTokens = 1024
+
+// Validate checks the config without creating a client, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid.
+func (c *Config) Validate() error {
+	r := configcheck.New("claude.Config")
+	r.Checkf(!c.ByBedrock || !c.ByVertex, "ByVertex", "cannot be set together with ByBedrock")
+	r.Checkf((c.AccessKey == "") == (c.SecretAccessKey == ""), "SecretAccessKey", "AccessKey and SecretAccessKey must be set together")
+	r.Checkf(c.Model != "", "Model", "model is required")
+	configcheck.Positive(r, "MaxTokens", &c.MaxTokens)
+	configcheck.InRange(r, "Temperature", c.Temperature, 0, 1)
+	configcheck.InRange(r, "TopP", c.TopP, 0, 1)
+	configcheck.Positive(r, "TopK", c.TopK)
+	if c.Thinking != nil && c.Thinking.Enable {
+		r.Checkf(c.Thinking.BudgetTokens >= minThinkingBudgetTokens, "Thinking.BudgetTokens",
+			"must be at least %d, got %d", minThinkingBudgetTokens, c.Thinking.BudgetTokens)
+		r.Checkf(c.Thinking.BudgetTokens < c.MaxTokens, "Thinking.BudgetTokens",
+			"must be less than MaxTokens %d, got %d", c.MaxTokens, c.Thinking.BudgetTokens)
+	}
+	return r.Err()
+}
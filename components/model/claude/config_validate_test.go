@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package claude
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&Config{
+		Model:     "claude-sonnet-4-0",
+		MaxTokens: 4096,
+		Thinking:  &Thinking{Enable: true, BudgetTokens: 2048},
+	}).Validate())
+
+	temperature, topK := float32(1.5), int32(0)
+	err := (&Config{
+		ByBedrock:   true,
+		ByVertex:    true,
+		AccessKey:   "ak",
+		MaxTokens:   512,
+		Temperature: &temperature,
+		TopK:        &topK,
+		Thinking:    &Thinking{Enable: true, BudgetTokens: 1000},
+	}).Validate()
+	report, ok := err.(*configcheck.Report)
+	assert.True(t, ok)
+	assert.Equal(t, "claude.Config", report.Config)
+	assert.Equal(t, []configcheck.Issue{
+		{Field: "ByVertex", Message: "cannot be set together with ByBedrock"},
+		{Field: "SecretAccessKey", Message: "AccessKey and SecretAccessKey must be set together"},
+		{Field: "Model", Message: "model is required"},
+		{Field: "Temperature", Message: "must be in [0, 1], got 1.5"},
+		{Field: "TopK", Message: "must be positive, got 0"},
+		{Field: "Thinking.BudgetTokens", Message: "must be at least 1024, got 1000"},
+		{Field: "Thinking.BudgetTokens", Message: "must be less than MaxTokens 512, got 1000"},
+	}, report.Issues)
+}
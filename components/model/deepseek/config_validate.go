@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepseek
+
+import (
+	"time"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+// Validate checks the config without creating a client, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid.
+func (c *ChatModelConfig) Validate() error {
+	r := configcheck.New("deepseek.ChatModelConfig")
+	r.Checkf(c.Model != "", "Model", "model is required")
+	configcheck.NonNegative(r, "Timeout", &c.Timeout)
+	configcheck.NonNegative(r, "MaxTokens", &c.MaxTokens)
+	configcheck.InRange(r, "Temperature", &c.Temperature, 0, 2)
+	configcheck.InRange(r, "TopP", &c.TopP, 0, 1)
+	configcheck.InRange(r, "PresencePenalty", &c.PresencePenalty, -2, 2)
+	configcheck.InRange(r, "FrequencyPenalty", &c.FrequencyPenalty, -2, 2)
+	switch c.ResponseFormatType {
+	case "", ResponseFormatTypeText, ResponseFormatTypeJSONObject:
+	default:
+		r.Addf("ResponseFormatType", "unsupported response format type %q", c.ResponseFormatType)
+	}
+	configcheck.InRange(r, "TopLogProbs", &c.TopLogProbs, 0, 20)
+	r.Checkf(c.TopLogProbs == 0 || c.LogProbs, "TopLogProbs", "requires LogProbs")
+	configcheck.NonNegative(r, "StallTimeout", &c.StallTimeout)
+	if c.Shadow != nil {
+		r.AddError("Shadow", c.Shadow.validate())
+	}
+	if c.OffPeakWindow != nil {
+		checkTimeOfDay(r, "OffPeakWindow.Start", c.OffPeakWindow.Start)
+		checkTimeOfDay(r, "OffPeakWindow.End", c.OffPeakWindow.End)
+	}
+	return r.Err()
+}
+
+func checkTimeOfDay(r *configcheck.Report, field string, d time.Duration) {
+	r.Checkf(d >= 0 && d < 24*time.Hour, field, "must be in [0, 24h), got %v", d)
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepseek
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+func TestChatModelConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&ChatModelConfig{Model: "deepseek-chat", OffPeakWindow: &OffPeakWindow{Start: 16*time.Hour + 30*time.Minute, End: 30 * time.Minute}}).Validate())
+
+	err := (&ChatModelConfig{
+		MaxTokens:          -1,
+		Temperature:        2.5,
+		ResponseFormatType: "xml",
+		TopLogProbs:        3,
+		Shadow:             &ShadowConfig{SampleRate: 0.5},
+		OffPeakWindow:      &OffPeakWindow{Start: 25 * time.Hour},
+	}).Validate()
+	report, ok := err.(*configcheck.Report)
+	assert.True(t, ok)
+	assert.Equal(t, "deepseek.ChatModelConfig", report.Config)
+	assert.Equal(t, []configcheck.Issue{
+		{Field: "Model", Message: "model is required"},
+		{Field: "MaxTokens", Message: "must not be negative, got -1"},
+		{Field: "Temperature", Message: "must be in [0, 2], got 2.5"},
+		{Field: "ResponseFormatType", Message: `unsupported response format type "xml"`},
+		{Field: "TopLogProbs", Message: "requires LogProbs"},
+		{Field: "Shadow", Message: "shadow model is required"},
+		{Field: "OffPeakWindow.Start", Message: "must be in [0, 24h), got 25h0m0s"},
+	}, report.Issues)
+}
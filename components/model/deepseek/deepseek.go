@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"runtime/debug"
 	"strings"
@@ -34,6 +35,9 @@ import (
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/libs/pii"
+	"github.com/cloudwego/eino-ext/libs/stall"
 )
 
 var _ model.ToolCallingChatModel = (*ChatModel)(nil)
@@ -80,6 +84,8 @@ type ChatModelConfig struct {
 	Model string `json:"model"`
 
 	// MaxTokens limits the maximum number of tokens that can be generated in the chat completion
+	// For deepseek-reasoner, it bounds the reasoning and the answer together,
+	// as DeepSeek does not support a separate limit of the reasoning tokens.
 	// Range: [1, 8192].
 	// Optional. Default: 4096
 	MaxTokens int `json:"max_tokens,omitempty"`
@@ -119,6 +125,49 @@ type ChatModelConfig struct {
 
 	// TopLogProbs specifies the number of most likely tokens to return at each token position, each with an associated log probability.
 	TopLogProbs int `json:"top_log_probs"`
+
+	// StreamIncludeUsage specifies whether Stream requests the token usage with stream_options.include_usage.
+	// The usage is reported in the ResponseMeta of the chunks and in the callback outputs as soon as it is received,
+	// so streaming dashboards get the token counts before the stream completes.
+	// It can be overridden per call by WithStreamIncludeUsage.
+	// Optional. Default: true
+	StreamIncludeUsage *bool `json:"stream_include_usage,omitempty"`
+
+	// StallTimeout specifies the maximum duration to wait for the next chunk in Stream.
+	// If no chunk arrives within the window, the upstream request is canceled
+	// and a *stall.Error is sent on the StreamReader.
+	// Optional. Default: no stall detection
+	StallTimeout time.Duration `json:"stall_timeout,omitempty"`
+
+	// KeepReasoning specifies whether the reasoning content of deepseek-reasoner is kept in the returned messages.
+	// Set it to false to drop the thinking text, e.g. when it is neither displayed nor stored,
+	// the reasoning tokens are still counted in the CompletionTokens of the usage.
+	// It can be overridden per call by WithKeepReasoning.
+	// Optional. Default: true
+	KeepReasoning *bool `json:"keep_reasoning,omitempty"`
+
+	// Shadow mirrors a sampled share of the requests to a second model for shadow evaluation.
+	// The comparison is reported through callbacks, see ShadowReportRunName.
+	// Optional. Default: no mirroring
+	Shadow *ShadowConfig `json:"-"`
+
+	// Interceptor processes the input messages before they are sent and the output messages before they are returned,
+	// e.g. a *pii.Redactor replacing the personally identifiable information with placeholders restored in the response.
+	// The callbacks and the shadow model see the intercepted input and output messages.
+	// Optional. Default: none
+	Interceptor pii.Interceptor `json:"-"`
+
+	// EnableCallbacks controls the callbacks reported by the model itself, shadow reports are not affected.
+	// When false, a graph running the model reports the callbacks around it, see IsCallbacksEnabled.
+	// Optional. Default: true
+	EnableCallbacks *bool `json:"enable_callbacks,omitempty"`
+
+	// OffPeakWindow is the daily window within which DeepSeek bills the requests at the off-peak price,
+	// taken from the current pricing of DeepSeek. If set, the PricingTier of every request is reported in the Extra of the callbacks
+	// with CallbackExtraKeyPricingTier, and WithOffPeakOnly fails the requests outside the window.
+	// DeepSeek has no request field for the pricing, the tier is decided by the start time of the request.
+	// Optional. Default: no pricing tier
+	OffPeakWindow *OffPeakWindow `json:"off_peak_window,omitempty"`
 }
 
 var _ model.ToolCallingChatModel = (*ChatModel)(nil)
@@ -130,6 +179,10 @@ type ChatModel struct {
 	tools      []deepseek.Tool
 	rawTools   []*schema.ToolInfo
 	toolChoice *schema.ToolChoice
+
+	shadow *shadowMirror
+
+	disableCallbacks bool
 }
 
 func NewChatModel(_ context.Context, config *ChatModelConfig) (*ChatModel, error) {
@@ -141,9 +194,11 @@ func NewChatModel(_ context.Context, config *ChatModelConfig) (*ChatModel, error
 	if config.Timeout > 0 {
 		opts = append(opts, deepseek.WithTimeout(config.Timeout))
 	}
+	var doer deepseek.HTTPDoer = http.DefaultClient
 	if config.HTTPClient != nil {
-		opts = append(opts, deepseek.WithHTTPClient(config.HTTPClient))
+		doer = config.HTTPClient
 	}
+	opts = append(opts, deepseek.WithHTTPClient(&tracingDoer{doer: doer}))
 	if len(config.BaseURL) > 0 {
 		baseURL := config.BaseURL
 		// sdk won't add '/' automatically
@@ -156,11 +211,21 @@ func NewChatModel(_ context.Context, config *ChatModelConfig) (*ChatModel, error
 		opts = append(opts, deepseek.WithPath(config.Path))
 	}
 
+	shadow, err := newShadowMirror(config.Shadow)
+	if err != nil {
+		return nil, err
+	}
+
 	cli, err := deepseek.NewClientWithOptions(config.APIKey, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return &ChatModel{cli: cli, conf: config}, nil
+	return &ChatModel{
+		cli:              cli,
+		conf:             config,
+		shadow:           shadow,
+		disableCallbacks: config.EnableCallbacks != nil && !*config.EnableCallbacks,
+	}, nil
 }
 
 func toLogProbs(probs *deepseek.Logprobs) *schema.LogProbs {
@@ -204,21 +269,45 @@ func (cm *ChatModel) Generate(ctx context.Context, in []*schema.Message, opts ..
 
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
 
+	in, restorer, err := pii.Apply(ctx, cm.conf.Interceptor, in)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			outMsg = restorer.Restore(outMsg)
+		}
+	}()
+
 	req, cbInput, err := cm.generateRequest(ctx, in, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate request: %w", err)
 	}
+	keepReasoning := cm.keepReasoning(opts)
 
-	ctx = callbacks.OnStart(ctx, cbInput)
+	if !cm.disableCallbacks {
+		ctx = callbacks.OnStart(ctx, cbInput)
+	}
 	defer func() {
-		if err != nil {
+		if err != nil && !cm.disableCallbacks {
 			callbacks.OnError(ctx, err)
 		}
 	}()
 
-	resp, err := cm.cli.CreateChatCompletion(ctx, req)
+	start := time.Now()
+	shadow := cm.shadow.start(ctx, cm.GetType(), in, cbInput, opts)
+	defer func() {
+		if err != nil {
+			shadow.finish(nil, 0)
+			return
+		}
+		shadow.finish(outMsg, time.Since(start))
+	}()
+
+	reqCtx, trace := withRequestTrace(ctx)
+	resp, err := cm.cli.CreateChatCompletion(reqCtx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create chat completion: %w", err)
+		return nil, fmt.Errorf("failed to create chat completion: %w", convAPIError(err, trace))
 	}
 
 	if len(resp.Choices) == 0 {
@@ -239,12 +328,12 @@ func (cm *ChatModel) Generate(ctx context.Context, in []*schema.Message, opts ..
 			Content:   choice.Message.Content,
 			ToolCalls: toMessageToolCalls(choice.Message.ToolCalls),
 			ResponseMeta: &schema.ResponseMeta{
-				FinishReason: choice.FinishReason,
-				Usage:        toEinoTokenUsage(&resp.Usage),
-				LogProbs:     lp,
+				Usage:    toEinoTokenUsage(&resp.Usage),
+				LogProbs: lp,
 			},
 		}
-		if len(choice.Message.ReasoningContent) > 0 {
+		setFinishReason(outMsg, choice.FinishReason)
+		if keepReasoning && len(choice.Message.ReasoningContent) > 0 {
 			SetReasoningContent(outMsg, choice.Message.ReasoningContent)
 			outMsg.ReasoningContent = choice.Message.ReasoningContent
 		}
@@ -256,11 +345,14 @@ func (cm *ChatModel) Generate(ctx context.Context, in []*schema.Message, opts ..
 		return nil, fmt.Errorf("invalid response format: choice with index 0 not found")
 	}
 
-	callbacks.OnEnd(ctx, &model.CallbackOutput{
-		Message:    outMsg,
-		Config:     cbInput.Config,
-		TokenUsage: toCallbackUsage(outMsg.ResponseMeta.Usage),
-	})
+	if !cm.disableCallbacks {
+		callbacks.OnEnd(ctx, &model.CallbackOutput{
+			Message:    outMsg,
+			Config:     cbInput.Config,
+			TokenUsage: toCallbackUsage(outMsg.ResponseMeta.Usage),
+			Extra:      cbInput.Extra,
+		})
+	}
 
 	return outMsg, nil
 }
@@ -269,28 +361,60 @@ func (cm *ChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...m
 
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
 
+	in, restorer, err := pii.Apply(ctx, cm.conf.Interceptor, in)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			outStream = restorer.RestoreStream(outStream)
+		}
+	}()
+
 	req, cbInput, err := cm.generateStreamRequest(ctx, in, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate stream request: %w", err)
 	}
+	keepReasoning := cm.keepReasoning(opts)
 
-	ctx = callbacks.OnStart(ctx, cbInput)
+	if !cm.disableCallbacks {
+		ctx = callbacks.OnStart(ctx, cbInput)
+	}
 	defer func() {
-		if err != nil {
+		if err != nil && !cm.disableCallbacks {
 			callbacks.OnError(ctx, err)
 		}
 	}()
 
-	stream, err := cm.cli.CreateChatCompletionStream(ctx, req)
+	start := time.Now()
+	shadow := cm.shadow.start(ctx, cm.GetType(), in, cbInput, opts)
+
+	streamCtx, watcher, stopWatcher := stall.NewWatcher(ctx, "deepseek", cm.conf.StallTimeout)
+	streamCtx, trace := withRequestTrace(streamCtx)
+	stream, err := cm.cli.CreateChatCompletionStream(streamCtx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create chat stream completion: %w", err)
+		stopWatcher()
+		shadow.finish(nil, 0)
+		return nil, fmt.Errorf("failed to create chat stream completion: %w", convAPIError(watcher.WrapErr(err), trace))
 	}
 
 	sr, sw := schema.Pipe[*model.CallbackOutput](1)
 	go func() {
+		// sent collects the chunks for the shadow comparison, it is nil if the request is not mirrored
+		var sent []*schema.Message
+		completed := false
 		defer func() {
 			panicErr := recover()
 			_ = stream.Close()
+			stopWatcher()
+
+			if shadow != nil {
+				var primary *schema.Message
+				if completed && panicErr == nil && len(sent) > 0 {
+					primary, _ = schema.ConcatMessages(sent)
+				}
+				shadow.finish(primary, time.Since(start))
+			}
 
 			if panicErr != nil {
 				_ = sw.Send(nil, newPanicErr(panicErr, debug.Stack()))
@@ -302,24 +426,31 @@ func (cm *ChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...m
 		var lastEmptyMsg *schema.Message
 
 		for {
+			watcher.Arm()
 			chunk, chunkErr := stream.Recv()
+			watcher.Disarm()
 			if errors.Is(chunkErr, io.EOF) {
 				if lastEmptyMsg != nil {
 					sw.Send(&model.CallbackOutput{
 						Message:    lastEmptyMsg,
 						Config:     cbInput.Config,
 						TokenUsage: toModelCallbackUsage(lastEmptyMsg.ResponseMeta),
+						Extra:      maps.Clone(cbInput.Extra),
 					}, nil)
+					if shadow != nil {
+						sent = append(sent, lastEmptyMsg)
+					}
 				}
+				completed = true
 				return
 			}
 
 			if chunkErr != nil {
-				_ = sw.Send(nil, fmt.Errorf("failed to receive stream chunk from DeepSeek: %w", chunkErr))
+				_ = sw.Send(nil, fmt.Errorf("failed to receive stream chunk from DeepSeek: %w", watcher.WrapErr(chunkErr)))
 				return
 			}
 
-			msg, found, err := resolveStreamResponse(chunk)
+			msg, found, err := resolveStreamResponse(chunk, keepReasoning)
 			if err != nil {
 				_ = sw.Send(nil, fmt.Errorf("failed to resolve stream response from DeepSeek: %w", err))
 			}
@@ -337,7 +468,8 @@ func (cm *ChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...m
 				msg = cMsg
 			}
 
-			if msg.Content == "" && len(msg.ToolCalls) == 0 {
+			// empty chunks are merged into the next one, unless they carry the usage to report it immediately
+			if msg.Content == "" && len(msg.ToolCalls) == 0 && msg.ResponseMeta.Usage == nil {
 				if _, ok := GetReasoningContent(msg); !ok {
 					lastEmptyMsg = msg
 					continue
@@ -350,7 +482,12 @@ func (cm *ChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...m
 				Message:    msg,
 				Config:     cbInput.Config,
 				TokenUsage: toModelCallbackUsage(msg.ResponseMeta),
+				// every chunk gets its own Extra, which the handlers may modify
+				Extra: maps.Clone(cbInput.Extra),
 			}, nil)
+			if shadow != nil {
+				sent = append(sent, msg)
+			}
 
 			if closed {
 				return
@@ -359,10 +496,13 @@ func (cm *ChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...m
 
 	}()
 
-	ctx, nsr := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderWithConvert(sr,
+	nsr := schema.StreamReaderWithConvert(sr,
 		func(src *model.CallbackOutput) (callbacks.CallbackOutput, error) {
 			return src, nil
-		}))
+		})
+	if !cm.disableCallbacks {
+		_, nsr = callbacks.OnEndWithStreamOutput(ctx, nsr)
+	}
 
 	outStream = schema.StreamReaderWithConvert(nsr,
 		func(src callbacks.CallbackOutput) (*schema.Message, error) {
@@ -504,7 +644,7 @@ func (cm *ChatModel) GetType() string {
 }
 
 func (cm *ChatModel) IsCallbacksEnabled() bool {
-	return true
+	return !cm.disableCallbacks
 }
 
 func (cm *ChatModel) generateStreamRequest(ctx context.Context, in []*schema.Message, opts ...model.Option) (*deepseek.StreamChatCompletionRequest, *model.CallbackInput, error) {
@@ -512,9 +652,13 @@ func (cm *ChatModel) generateStreamRequest(ctx context.Context, in []*schema.Mes
 	if err != nil {
 		return nil, nil, err
 	}
+	specOptions := model.GetImplSpecificOptions(&options{
+		StreamIncludeUsage: cm.conf.StreamIncludeUsage,
+	}, opts...)
+
 	req := &deepseek.StreamChatCompletionRequest{
 		Stream:           true,
-		StreamOptions:    deepseek.StreamOptions{IncludeUsage: false},
+		StreamOptions:    deepseek.StreamOptions{IncludeUsage: specOptions.StreamIncludeUsage == nil || *specOptions.StreamIncludeUsage},
 		Model:            origReq.Model,
 		Messages:         origReq.Messages,
 		FrequencyPenalty: origReq.FrequencyPenalty,
@@ -531,7 +675,19 @@ func (cm *ChatModel) generateStreamRequest(ctx context.Context, in []*schema.Mes
 	return req, cbIn, nil
 }
 
+// keepReasoning reports whether the reasoning content is kept in the returned messages.
+func (cm *ChatModel) keepReasoning(opts []model.Option) bool {
+	specOptions := model.GetImplSpecificOptions(&options{
+		KeepReasoning: cm.conf.KeepReasoning,
+	}, opts...)
+	return specOptions.KeepReasoning == nil || *specOptions.KeepReasoning
+}
+
 func (cm *ChatModel) generateRequest(_ context.Context, in []*schema.Message, opts ...model.Option) (*deepseek.ChatCompletionRequest, *model.CallbackInput, error) {
+	extra, err := cm.pricingExtra(opts)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	options := model.GetCommonOptions(&model.Options{
 		Temperature: &cm.conf.Temperature,
@@ -566,6 +722,7 @@ func (cm *ChatModel) generateRequest(_ context.Context, in []*schema.Message, op
 			TopP:        req.TopP,
 			Stop:        req.Stop,
 		},
+		Extra: extra,
 	}
 
 	tools := cm.tools
@@ -580,7 +737,7 @@ func (cm *ChatModel) generateRequest(_ context.Context, in []*schema.Message, op
 	req.Tools = make([]deepseek.Tool, len(tools))
 	copy(req.Tools, tools)
 
-	err := populateToolChoice(req, options.ToolChoice, options.AllowedToolNames)
+	err = populateToolChoice(req, options.ToolChoice, options.AllowedToolNames)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -605,6 +762,26 @@ func (cm *ChatModel) generateRequest(_ context.Context, in []*schema.Message, op
 	return req, cbInput, nil
 }
 
+// pricingExtra returns the callback Extra reporting the pricing tier of the request starting now,
+// or an *OutsideOffPeakError if it is outside the off-peak window with WithOffPeakOnly.
+func (cm *ChatModel) pricingExtra(opts []model.Option) (map[string]any, error) {
+	specOptions := model.GetImplSpecificOptions(&options{}, opts...)
+	window := cm.conf.OffPeakWindow
+	if window == nil {
+		if specOptions.OffPeakOnly {
+			return nil, fmt.Errorf("WithOffPeakOnly requires ChatModelConfig.OffPeakWindow")
+		}
+		return nil, nil
+	}
+
+	now := timeNow()
+	tier := window.tier(now)
+	if specOptions.OffPeakOnly && tier != PricingTierOffPeak {
+		return nil, &OutsideOffPeakError{NextStart: window.nextStart(now)}
+	}
+	return map[string]any{CallbackExtraKeyPricingTier: tier}, nil
+}
+
 func populateToolChoice(req *deepseek.ChatCompletionRequest, tc *schema.ToolChoice, allowedToolNames []string) error {
 	if tc == nil {
 		return nil
@@ -785,7 +962,7 @@ func toModelCallbackUsage(respMeta *schema.ResponseMeta) *model.TokenUsage {
 	}
 }
 
-func resolveStreamResponse(resp *deepseek.StreamChatCompletionResponse) (msg *schema.Message, found bool, err error) {
+func resolveStreamResponse(resp *deepseek.StreamChatCompletionResponse, keepReasoning bool) (msg *schema.Message, found bool, err error) {
 	for _, choice := range resp.Choices {
 		// take 0 index as response, rewrite if needed
 		if choice.Index != 0 {
@@ -802,12 +979,12 @@ func resolveStreamResponse(resp *deepseek.StreamChatCompletionResponse) (msg *sc
 			Content:   choice.Delta.Content,
 			ToolCalls: toMessageToolCalls(choice.Delta.ToolCalls),
 			ResponseMeta: &schema.ResponseMeta{
-				FinishReason: choice.FinishReason,
-				Usage:        streamToEinoTokenUsage(resp.Usage),
-				LogProbs:     lp,
+				Usage:    streamToEinoTokenUsage(resp.Usage),
+				LogProbs: lp,
 			},
 		}
-		if len(choice.Delta.ReasoningContent) > 0 {
+		setFinishReason(msg, choice.FinishReason)
+		if keepReasoning && len(choice.Delta.ReasoningContent) > 0 {
 			SetReasoningContent(msg, choice.Delta.ReasoningContent)
 			msg.ReasoningContent = choice.Delta.ReasoningContent
 		}
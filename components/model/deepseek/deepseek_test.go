@@ -28,6 +28,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 
+	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 )
@@ -117,6 +118,52 @@ func TestChatModelGenerate(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestChatModelDisableCallbacks(t *testing.T) {
+	defer mockey.Mock((*deepseek.Client).CreateChatCompletion).To(func(ctx context.Context, request *deepseek.ChatCompletionRequest) (*deepseek.ChatCompletionResponse, error) {
+		return &deepseek.ChatCompletionResponse{
+			Choices: []deepseek.Choice{
+				{Index: 0, Message: deepseek.Message{Role: "assistant", Content: "hello world"}},
+			},
+		}, nil
+	}).Build().UnPatch()
+
+	var started, ended int
+	handler := callbacks.NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackInput) context.Context {
+			started++
+			return ctx
+		}).
+		OnEndFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackOutput) context.Context {
+			ended++
+			return ctx
+		}).Build()
+	ctx := callbacks.InitCallbacks(context.Background(), &callbacks.RunInfo{}, handler)
+
+	enableCallbacks := false
+	cm, err := NewChatModel(ctx, &ChatModelConfig{
+		APIKey:          "my-api-key",
+		Model:           "deepseek-chat",
+		EnableCallbacks: &enableCallbacks,
+	})
+	assert.Nil(t, err)
+	result, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.Nil(t, err)
+	assert.Equal(t, "hello world", result.Content)
+	assert.Equal(t, 0, started)
+	assert.False(t, cm.IsCallbacksEnabled())
+	assert.Equal(t, 0, ended)
+
+	cm, err = NewChatModel(ctx, &ChatModelConfig{
+		APIKey: "my-api-key",
+		Model:  "deepseek-chat",
+	})
+	assert.Nil(t, err)
+	_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, started)
+	assert.Equal(t, 1, ended)
+}
+
 func TestChatModelStream(t *testing.T) {
 	responses := []*deepseek.StreamChatCompletionResponse{
 		{
@@ -235,6 +282,66 @@ func TestChatModelStream(t *testing.T) {
 	}, msg)
 }
 
+func TestChatModelStreamIncludeUsage(t *testing.T) {
+	var includeUsage []bool
+	defer mockey.Mock((*deepseek.Client).CreateChatCompletionStream).To(func(ctx context.Context, request *deepseek.StreamChatCompletionRequest) (deepseek.ChatCompletionStream, error) {
+		includeUsage = append(includeUsage, request.StreamOptions.IncludeUsage)
+		return &mockStream{responses: []*deepseek.StreamChatCompletionResponse{
+			{Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{Role: "assistant"}}}},
+			{
+				Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{Content: "Hello"}}},
+				Usage:   &deepseek.StreamUsage{PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2},
+			},
+			{Usage: &deepseek.StreamUsage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3}},
+			{Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{Content: " World"}}}},
+		}}, nil
+	}).Build().UnPatch()
+
+	var usages []*model.TokenUsage
+	done := make(chan struct{})
+	handler := callbacks.NewHandlerBuilder().
+		OnEndWithStreamOutputFn(func(ctx context.Context, _ *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			defer close(done)
+			defer output.Close()
+			for {
+				chunk, err := output.Recv()
+				if err != nil {
+					break
+				}
+				usages = append(usages, model.ConvCallbackOutput(chunk).TokenUsage)
+			}
+			return ctx
+		}).Build()
+	ctx := callbacks.InitCallbacks(context.Background(), &callbacks.RunInfo{}, handler)
+
+	cm, err := NewChatModel(ctx, &ChatModelConfig{APIKey: "my-api-key", Model: "deepseek-chat"})
+	assert.Nil(t, err)
+	result, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.Nil(t, err)
+	msgs, err := schema.ConcatMessageStream(result)
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello World", msgs.Content)
+	assert.Equal(t, 3, msgs.ResponseMeta.Usage.TotalTokens)
+
+	// the interim usage chunk is reported before the stream completes
+	<-done
+	assert.Len(t, usages, 3)
+	assert.Equal(t, 2, usages[0].TotalTokens)
+	assert.Equal(t, 3, usages[1].TotalTokens)
+	assert.Nil(t, usages[2])
+
+	ctx = context.Background()
+	disabled := false
+	cm, err = NewChatModel(ctx, &ChatModelConfig{APIKey: "my-api-key", Model: "deepseek-chat", StreamIncludeUsage: &disabled})
+	assert.Nil(t, err)
+	_, err = cm.Stream(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.Nil(t, err)
+	_, err = cm.Stream(ctx, []*schema.Message{schema.UserMessage("hello")}, WithStreamIncludeUsage(true))
+	assert.Nil(t, err)
+
+	assert.Equal(t, []bool{true, false, true}, includeUsage)
+}
+
 type mockStream struct {
 	responses []*deepseek.StreamChatCompletionResponse
 	idx       int
@@ -413,3 +520,56 @@ func TestPopulateToolChoice(t *testing.T) {
 		})
 	}
 }
+
+func TestChatModelKeepReasoning(t *testing.T) {
+	defer mockey.Mock((*deepseek.Client).CreateChatCompletion).To(func(ctx context.Context, request *deepseek.ChatCompletionRequest) (*deepseek.ChatCompletionResponse, error) {
+		return &deepseek.ChatCompletionResponse{
+			Choices: []deepseek.Choice{{Index: 0, Message: deepseek.Message{Role: "assistant", Content: "4", ReasoningContent: "2+2=4"}}},
+			Usage:   deepseek.Usage{PromptTokens: 1, CompletionTokens: 5, TotalTokens: 6},
+		}, nil
+	}).Build().UnPatch()
+	defer mockey.Mock((*deepseek.Client).CreateChatCompletionStream).To(func(ctx context.Context, request *deepseek.StreamChatCompletionRequest) (deepseek.ChatCompletionStream, error) {
+		return &mockStream{responses: []*deepseek.StreamChatCompletionResponse{
+			{Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{Role: "assistant", ReasoningContent: "2+2"}}}},
+			{Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{ReasoningContent: "=4"}}}},
+			{Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{Content: "4"}}}},
+			{Usage: &deepseek.StreamUsage{PromptTokens: 1, CompletionTokens: 5, TotalTokens: 6}},
+		}}, nil
+	}).Build().UnPatch()
+
+	ctx := context.Background()
+	keep := false
+	cm, err := NewChatModel(ctx, &ChatModelConfig{APIKey: "my-api-key", Model: "deepseek-reasoner", KeepReasoning: &keep})
+	assert.Nil(t, err)
+	msgs := []*schema.Message{schema.UserMessage("2+2?")}
+
+	result, err := cm.Generate(ctx, msgs)
+	assert.Nil(t, err)
+	assert.Equal(t, "4", result.Content)
+	assert.Empty(t, result.ReasoningContent)
+	_, ok := GetReasoningContent(result)
+	assert.False(t, ok)
+	assert.Equal(t, 5, result.ResponseMeta.Usage.CompletionTokens)
+
+	result, err = cm.Generate(ctx, msgs, WithKeepReasoning(true))
+	assert.Nil(t, err)
+	assert.Equal(t, "2+2=4", result.ReasoningContent)
+
+	sr, err := cm.Stream(ctx, msgs)
+	assert.Nil(t, err)
+	var chunks []*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		assert.Empty(t, chunk.ReasoningContent)
+		chunks = append(chunks, chunk)
+	}
+	assert.Len(t, chunks, 2)
+	streamed, err := schema.ConcatMessages(chunks)
+	assert.Nil(t, err)
+	assert.Equal(t, "4", streamed.Content)
+	assert.Equal(t, 5, streamed.ResponseMeta.Usage.CompletionTokens)
+}
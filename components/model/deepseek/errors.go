@@ -0,0 +1,141 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepseek
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/cohesion-org/deepseek-go"
+)
+
+// requestIDHeaders are the response headers carrying the ID of the request, in order of preference.
+var requestIDHeaders = []string{"X-Request-Id", "X-Ds-Trace-Id"}
+
+// APIError is an error returned by the DeepSeek API, with the identifiers to include in a support ticket.
+// It is returned by Generate and Stream, get it with errors.As.
+// The original *deepseek.APIError can also be obtained by errors.As.
+type APIError struct {
+	// StatusCode is the HTTP status code, e.g. 402 if the balance is insufficient.
+	StatusCode int
+	// RequestID is the ID of the request given by the response headers, empty if none.
+	RequestID string
+	// Type is the type of the error given by the response body, e.g. "invalid_request_error", empty if none.
+	Type string
+	// Message is the error message.
+	Message string
+	// ResponseBody is the raw response body.
+	ResponseBody string
+
+	err error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("deepseek api error, status=%d, request_id=%s: %s", e.StatusCode, e.RequestID, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// convAPIError converts the *deepseek.APIError in err to an *APIError with the request ID recorded by the trace,
+// otherwise err is returned as is.
+func convAPIError(err error, trace *requestTrace) error {
+	var apiErr *deepseek.APIError
+	if err == nil || !errors.As(err, &apiErr) {
+		return err
+	}
+	e := &APIError{
+		StatusCode:   apiErr.StatusCode,
+		RequestID:    trace.get(),
+		Message:      apiErr.Message,
+		ResponseBody: apiErr.ResponseBody,
+		err:          err,
+	}
+	// the sdk only parses {"code": ..., "message": ...}, while the errors of the DeepSeek API are
+	// {"error": {"message": ..., "type": ...}}
+	var body struct {
+		Error *struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"error"`
+	}
+	if json.Unmarshal([]byte(apiErr.ResponseBody), &body) == nil && body.Error != nil {
+		e.Type = body.Error.Type
+		if body.Error.Message != "" {
+			e.Message = body.Error.Message
+		}
+	}
+	return e
+}
+
+// requestTrace records the ID of the last request sent by a call.
+type requestTrace struct {
+	mu        sync.Mutex
+	requestID string
+}
+
+func (t *requestTrace) set(requestID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requestID = requestID
+}
+
+func (t *requestTrace) get() string {
+	if t == nil {
+		return ""
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.requestID
+}
+
+type requestTraceKey struct{}
+
+func withRequestTrace(ctx context.Context) (context.Context, *requestTrace) {
+	trace := &requestTrace{}
+	return context.WithValue(ctx, requestTraceKey{}, trace), trace
+}
+
+// tracingDoer records the request ID of the responses into the requestTrace of the request context,
+// since the sdk drops the response headers of the errors.
+type tracingDoer struct {
+	doer deepseek.HTTPDoer
+}
+
+func (d *tracingDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := d.doer.Do(req)
+	if resp != nil {
+		if trace, ok := req.Context().Value(requestTraceKey{}).(*requestTrace); ok {
+			trace.set(requestIDOf(resp.Header))
+		}
+	}
+	return resp, err
+}
+
+func requestIDOf(header http.Header) string {
+	for _, key := range requestIDHeaders {
+		if id := header.Get(key); id != "" {
+			return id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepseek
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/cohesion-org/deepseek-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	ctx := context.Background()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"error":{"message":"Insufficient Balance","type":"unknown_error","param":null,"code":"invalid_request_error"}}`))
+	}))
+	defer srv.Close()
+
+	cm, err := NewChatModel(ctx, &ChatModelConfig{APIKey: "key", Model: "deepseek-chat", BaseURL: srv.URL, HTTPClient: srv.Client()})
+	assert.NoError(t, err)
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	_, err = cm.Generate(ctx, input)
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusPaymentRequired, apiErr.StatusCode)
+	assert.Equal(t, "req-123", apiErr.RequestID)
+	assert.Equal(t, "unknown_error", apiErr.Type)
+	assert.Equal(t, "Insufficient Balance", apiErr.Message)
+	assert.Contains(t, err.Error(), "request_id=req-123")
+	var sdkErr *deepseek.APIError
+	assert.True(t, errors.As(err, &sdkErr))
+
+	_, err = cm.Stream(ctx, input)
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusPaymentRequired, apiErr.StatusCode)
+	assert.Equal(t, "req-123", apiErr.RequestID)
+
+	// the other errors are returned as is
+	origin := errors.New("origin")
+	assert.Equal(t, origin, convAPIError(origin, nil))
+	assert.Nil(t, convAPIError(nil, nil))
+
+	// the message of the sdk is kept if the body is not the error of the DeepSeek API
+	err = convAPIError(&deepseek.APIError{StatusCode: 500, Message: "Internal server error", ResponseBody: "oops"}, nil)
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "Internal server error", apiErr.Message)
+	assert.Empty(t, apiErr.RequestID)
+}
+
+func TestRequestIDOf(t *testing.T) {
+	assert.Equal(t, "a", requestIDOf(http.Header{"X-Request-Id": {"a"}, "X-Ds-Trace-Id": {"b"}}))
+	assert.Equal(t, "b", requestIDOf(http.Header{"X-Ds-Trace-Id": {"b"}}))
+	assert.Empty(t, requestIDOf(http.Header{}))
+}
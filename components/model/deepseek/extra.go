@@ -17,12 +17,20 @@
 package deepseek
 
 import (
+	"github.com/cloudwego/eino-ext/libs/finishreason"
 	"github.com/cloudwego/eino/schema"
 )
 
+// finishReasonTable normalizes the finish reasons of DeepSeek, see https://api-docs.deepseek.com/api/create-chat-completion
+var finishReasonTable = finishreason.Merge(finishreason.Common, finishreason.Table{
+	// the request is interrupted due to insufficient resource of the inference system
+	"insufficient_system_resource": finishreason.Error,
+})
+
 const (
 	extraKeyReasoningContent = "_eino_deepseek_reasoning_content"
 	extraKeyPrefix           = "_eino_deepseek_prefix"
+	extraKeyRawFinishReason  = "_eino_deepseek_raw_finish_reason"
 )
 
 func SetReasoningContent(message *schema.Message, content string) {
@@ -60,3 +68,26 @@ func HasPrefix(message *schema.Message) bool {
 	_, ok := message.Extra[extraKeyPrefix].(bool)
 	return ok
 }
+
+// GetRawFinishReason returns the finish reason returned by DeepSeek,
+// only set when it is normalized to another value in ResponseMeta.FinishReason,
+// e.g. "insufficient_system_resource" normalized to finishreason.Error.
+func GetRawFinishReason(message *schema.Message) (string, bool) {
+	if message == nil || message.Extra == nil {
+		return "", false
+	}
+	result, ok := message.Extra[extraKeyRawFinishReason].(string)
+	return result, ok
+}
+
+// setFinishReason sets the normalized finish reason to the message, keeping the raw one in the extra.
+func setFinishReason(message *schema.Message, reason string) {
+	normalized, changed := finishReasonTable.Normalize(reason)
+	message.ResponseMeta.FinishReason = normalized
+	if changed {
+		if message.Extra == nil {
+			message.Extra = make(map[string]interface{})
+		}
+		message.Extra[extraKeyRawFinishReason] = reason
+	}
+}
@@ -19,6 +19,7 @@ package deepseek
 import (
 	"testing"
 
+	"github.com/cloudwego/eino-ext/libs/finishreason"
 	"github.com/cloudwego/eino/schema"
 	"github.com/stretchr/testify/assert"
 )
@@ -53,3 +54,23 @@ func TestPrefix(t *testing.T) {
 	SetPrefix(msg)
 	assert.True(t, HasPrefix(msg))
 }
+
+func TestFinishReason(t *testing.T) {
+	msg := &schema.Message{ResponseMeta: &schema.ResponseMeta{}}
+	setFinishReason(msg, "stop")
+	assert.Equal(t, finishreason.Stop, msg.ResponseMeta.FinishReason)
+	_, ok := GetRawFinishReason(msg)
+	assert.False(t, ok)
+
+	msg = &schema.Message{ResponseMeta: &schema.ResponseMeta{}}
+	setFinishReason(msg, "insufficient_system_resource")
+	assert.Equal(t, finishreason.Error, msg.ResponseMeta.FinishReason)
+	raw, ok := GetRawFinishReason(msg)
+	assert.True(t, ok)
+	assert.Equal(t, "insufficient_system_resource", raw)
+
+	msg = &schema.Message{ResponseMeta: &schema.ResponseMeta{}}
+	setFinishReason(msg, "")
+	assert.Equal(t, "", msg.ResponseMeta.FinishReason)
+	assert.Nil(t, msg.Extra)
+}
@@ -8,6 +8,10 @@ require (
 	github.com/bytedance/mockey v1.2.14
 	github.com/bytedance/sonic v1.14.1
 	github.com/cloudwego/eino v0.7.13
+	github.com/cloudwego/eino-ext/libs/configcheck v0.1.0
+	github.com/cloudwego/eino-ext/libs/finishreason v0.1.0
+	github.com/cloudwego/eino-ext/libs/pii v0.1.1
+	github.com/cloudwego/eino-ext/libs/stall v0.1.0
 	github.com/cohesion-org/deepseek-go v1.3.2
 	github.com/eino-contrib/jsonschema v1.0.3
 	github.com/stretchr/testify v1.10.0
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepseek
+
+import "github.com/cloudwego/eino/components/model"
+
+type options struct {
+	StreamIncludeUsage *bool
+	KeepReasoning      *bool
+	OffPeakOnly        bool
+}
+
+// WithStreamIncludeUsage overrides ChatModelConfig.StreamIncludeUsage for a single Stream call.
+func WithStreamIncludeUsage(includeUsage bool) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.StreamIncludeUsage = &includeUsage
+	})
+}
+
+// WithKeepReasoning overrides ChatModelConfig.KeepReasoning for a single call.
+func WithKeepReasoning(keep bool) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.KeepReasoning = &keep
+	})
+}
+
+// WithOffPeakOnly fails the call with an *OutsideOffPeakError instead of sending it if it starts outside
+// ChatModelConfig.OffPeakWindow, e.g. for the batch jobs which must be billed at the off-peak price.
+func WithOffPeakOnly() model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.OffPeakOnly = true
+	})
+}
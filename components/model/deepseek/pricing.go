@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepseek
+
+import (
+	"fmt"
+	"time"
+)
+
+// CallbackExtraKeyPricingTier is the key of the PricingTier of the request in the Extra of the callback input and output,
+// reported when ChatModelConfig.OffPeakWindow is set.
+const CallbackExtraKeyPricingTier = "deepseek_pricing_tier"
+
+// PricingTier is the price DeepSeek bills a request at, decided by the time the request starts.
+type PricingTier string
+
+const (
+	// PricingTierStandard is the standard price.
+	PricingTierStandard PricingTier = "standard"
+	// PricingTierOffPeak is the discounted price of the requests started within the off-peak window.
+	PricingTierOffPeak PricingTier = "off_peak"
+)
+
+// OffPeakWindow is the daily window in UTC within which DeepSeek bills the requests at the off-peak price,
+// given as the offsets from midnight. End before Start means the window spans midnight.
+// There is no default, as DeepSeek changes or discontinues the discount with its pricing,
+// see https://api-docs.deepseek.com/quick_start/pricing for the current one.
+type OffPeakWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// OutsideOffPeakError is returned by the requests with WithOffPeakOnly started outside the off-peak window.
+type OutsideOffPeakError struct {
+	// NextStart is the start of the next off-peak window.
+	NextStart time.Time
+}
+
+func (e *OutsideOffPeakError) Error() string {
+	return fmt.Sprintf("request is outside the off-peak window of deepseek, the next one starts at %s",
+		e.NextStart.Format(time.RFC3339))
+}
+
+// timeNow is replaced in tests.
+var timeNow = time.Now
+
+// tier returns the pricing tier of the request started at t.
+func (w *OffPeakWindow) tier(t time.Time) PricingTier {
+	offset := sinceMidnight(t)
+	var offPeak bool
+	if w.Start <= w.End {
+		offPeak = offset >= w.Start && offset < w.End
+	} else {
+		offPeak = offset >= w.Start || offset < w.End
+	}
+	if offPeak {
+		return PricingTierOffPeak
+	}
+	return PricingTierStandard
+}
+
+// nextStart returns the start of the first off-peak window after t.
+func (w *OffPeakWindow) nextStart(t time.Time) time.Time {
+	t = t.UTC()
+	start := t.Truncate(24 * time.Hour).Add(w.Start)
+	if !start.After(t) {
+		start = start.Add(24 * time.Hour)
+	}
+	return start
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	t = t.UTC()
+	return t.Sub(t.Truncate(24 * time.Hour))
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepseek
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/cohesion-org/deepseek-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffPeakWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 3, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	w := &OffPeakWindow{Start: 16*time.Hour + 30*time.Minute, End: 30 * time.Minute}
+	assert.Equal(t, PricingTierStandard, w.tier(at(16, 29)))
+	assert.Equal(t, PricingTierOffPeak, w.tier(at(16, 30)))
+	assert.Equal(t, PricingTierOffPeak, w.tier(at(0, 29)))
+	assert.Equal(t, PricingTierStandard, w.tier(at(0, 30)))
+	// the window is in UTC whatever the location of the time
+	assert.Equal(t, PricingTierOffPeak, w.tier(at(17, 0).In(time.FixedZone("UTC+8", 8*3600))))
+
+	assert.Equal(t, at(16, 30), w.nextStart(at(10, 0)))
+	assert.Equal(t, at(16, 30).Add(24*time.Hour), w.nextStart(at(16, 30)))
+
+	day := &OffPeakWindow{Start: 2 * time.Hour, End: 6 * time.Hour}
+	assert.Equal(t, PricingTierOffPeak, day.tier(at(3, 0)))
+	assert.Equal(t, PricingTierStandard, day.tier(at(23, 0)))
+}
+
+func TestPricingExtra(t *testing.T) {
+	defer func(orig func() time.Time) { timeNow = orig }(timeNow)
+	ctx := context.Background()
+	msgs := []*schema.Message{schema.UserMessage("hi")}
+
+	cm := &ChatModel{conf: &ChatModelConfig{Model: "deepseek-chat"}}
+	_, cbInput, err := cm.generateRequest(ctx, msgs)
+	assert.NoError(t, err)
+	assert.Nil(t, cbInput.Extra)
+	_, _, err = cm.generateRequest(ctx, msgs, WithOffPeakOnly())
+	assert.ErrorContains(t, err, "requires ChatModelConfig.OffPeakWindow")
+
+	cm = &ChatModel{conf: &ChatModelConfig{Model: "deepseek-chat", OffPeakWindow: &OffPeakWindow{Start: 16*time.Hour + 30*time.Minute, End: 30 * time.Minute}}}
+	timeNow = func() time.Time { return time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC) }
+	_, cbInput, err = cm.generateRequest(ctx, msgs, WithOffPeakOnly())
+	assert.NoError(t, err)
+	assert.Equal(t, PricingTierOffPeak, cbInput.Extra[CallbackExtraKeyPricingTier])
+
+	timeNow = func() time.Time { return time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC) }
+	_, cbInput, err = cm.generateRequest(ctx, msgs)
+	assert.NoError(t, err)
+	assert.Equal(t, PricingTierStandard, cbInput.Extra[CallbackExtraKeyPricingTier])
+
+	_, _, err = cm.generateRequest(ctx, msgs, WithOffPeakOnly())
+	var outsideErr *OutsideOffPeakError
+	assert.True(t, errors.As(err, &outsideErr))
+	assert.Equal(t, time.Date(2025, 3, 1, 16, 30, 0, 0, time.UTC), outsideErr.NextStart)
+}
+
+func TestStreamPricingExtra(t *testing.T) {
+	defer func(orig func() time.Time) { timeNow = orig }(timeNow)
+	timeNow = func() time.Time { return time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC) }
+	defer mockey.Mock((*deepseek.Client).CreateChatCompletionStream).To(func(ctx context.Context, request *deepseek.StreamChatCompletionRequest) (deepseek.ChatCompletionStream, error) {
+		return &mockStream{responses: []*deepseek.StreamChatCompletionResponse{
+			{Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{Role: "assistant", Content: "Hello"}}}},
+			{Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{Content: " World"}}}},
+		}}, nil
+	}).Build().UnPatch()
+
+	var extras []map[string]any
+	done := make(chan struct{})
+	handler := callbacks.NewHandlerBuilder().
+		OnEndWithStreamOutputFn(func(ctx context.Context, _ *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			defer close(done)
+			defer output.Close()
+			for {
+				chunk, err := output.Recv()
+				if err != nil {
+					break
+				}
+				extra := model.ConvCallbackOutput(chunk).Extra
+				extras = append(extras, extra)
+				// a handler modifying the Extra of a chunk does not modify the other chunks
+				extra["handled"] = len(extras)
+			}
+			return ctx
+		}).Build()
+	ctx := callbacks.InitCallbacks(context.Background(), &callbacks.RunInfo{}, handler)
+
+	cm, err := NewChatModel(ctx, &ChatModelConfig{
+		APIKey:        "my-api-key",
+		Model:         "deepseek-chat",
+		OffPeakWindow: &OffPeakWindow{Start: 16*time.Hour + 30*time.Minute, End: 30 * time.Minute},
+	})
+	assert.NoError(t, err)
+	result, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.NoError(t, err)
+	_, err = schema.ConcatMessageStream(result)
+	assert.NoError(t, err)
+
+	<-done
+	assert.Len(t, extras, 2)
+	for i, extra := range extras {
+		assert.Equal(t, PricingTierOffPeak, extra[CallbackExtraKeyPricingTier])
+		assert.Equal(t, i+1, extra["handled"])
+	}
+}
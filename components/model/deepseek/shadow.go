@@ -0,0 +1,304 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepseek
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// ShadowRunName is the RunInfo.Name of the mirrored request sent to ShadowConfig.Model.
+	ShadowRunName = "DeepSeekShadow"
+	// ShadowReportRunName is the RunInfo.Name of the callbacks reporting the comparison of a mirrored request.
+	// Its OnEnd output carries the shadow message and a *ShadowReport, see GetShadowReport.
+	// If the mirrored request fails, OnError is called instead.
+	ShadowReportRunName = "DeepSeekShadowReport"
+
+	extraKeyShadowReport = "_eino_deepseek_shadow_report"
+
+	defaultShadowTimeout        = time.Minute
+	defaultShadowMaxConcurrency = 8
+)
+
+// ShadowConfig mirrors a sampled share of the requests to a second model, e.g. to evaluate a model migration.
+// Mirrored requests are sent asynchronously and never affect the primary response.
+type ShadowConfig struct {
+	// Model receives the mirrored requests through Generate, with the same input, tools and options.
+	// Required.
+	Model model.BaseChatModel
+
+	// SampleRate is the share of requests to mirror.
+	// Range: (0.0, 1.0].
+	// Required.
+	SampleRate float64
+
+	// Timeout bounds every mirrored request.
+	// Optional. Default: 1 minute
+	Timeout time.Duration
+
+	// MaxConcurrency limits the number of in-flight mirrored requests, requests beyond the limit are not mirrored.
+	// Optional. Default: 8
+	MaxConcurrency int
+}
+
+// ShadowReport compares the response of the primary model with the response of the shadow model.
+type ShadowReport struct {
+	PrimaryMessage *schema.Message
+	ShadowMessage  *schema.Message
+
+	// PrimaryLatency is the latency of the primary request, until the end of the stream for Stream.
+	PrimaryLatency time.Duration
+	// ShadowLatency is the latency of the mirrored Generate request.
+	ShadowLatency time.Duration
+
+	// ContentSimilarity is the Jaccard similarity of the character bigrams of both contents, in [0.0, 1.0].
+	ContentSimilarity float64
+	// ToolCallsMatch reports whether both responses call the same tools with the same arguments in the same order.
+	ToolCallsMatch bool
+	// FinishReasonMatch reports whether both responses have the same finish reason.
+	FinishReasonMatch bool
+}
+
+// GetShadowReport returns the report carried by the OnEnd output of the ShadowReportRunName callbacks.
+func GetShadowReport(output *model.CallbackOutput) (*ShadowReport, bool) {
+	if output == nil || output.Extra == nil {
+		return nil, false
+	}
+	report, ok := output.Extra[extraKeyShadowReport].(*ShadowReport)
+	return report, ok
+}
+
+func (c *ShadowConfig) validate() error {
+	if c.Model == nil {
+		return fmt.Errorf("shadow model is required")
+	}
+	if c.SampleRate <= 0 || c.SampleRate > 1 {
+		return fmt.Errorf("shadow sample rate must be in (0, 1], got %v", c.SampleRate)
+	}
+	return nil
+}
+
+type shadowMirror struct {
+	conf *ShadowConfig
+	sem  chan struct{}
+}
+
+func newShadowMirror(conf *ShadowConfig) (*shadowMirror, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	maxConcurrency := conf.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = defaultShadowMaxConcurrency
+	}
+	return &shadowMirror{
+		conf: conf,
+		sem:  make(chan struct{}, maxConcurrency),
+	}, nil
+}
+
+// shadowRun is a mirrored request waiting for the primary response to be compared with.
+// A nil *shadowRun is valid and does nothing.
+type shadowRun struct {
+	primary chan shadowPrimaryResult
+}
+
+type shadowPrimaryResult struct {
+	msg     *schema.Message
+	latency time.Duration
+}
+
+// start mirrors the request if it is sampled. It returns nil if the request is not mirrored.
+func (m *shadowMirror) start(ctx context.Context, primaryType string, in []*schema.Message,
+	cbInput *model.CallbackInput, opts []model.Option) *shadowRun {
+	if m == nil || rand.Float64() >= m.conf.SampleRate {
+		return nil
+	}
+	select {
+	case m.sem <- struct{}{}:
+	default:
+		return nil
+	}
+
+	// tools bound to the primary model are not bound to the shadow model
+	shadowOpts := make([]model.Option, 0, len(opts)+2)
+	if len(cbInput.Tools) > 0 {
+		shadowOpts = append(shadowOpts, model.WithTools(cbInput.Tools))
+	}
+	if cbInput.ToolChoice != nil {
+		shadowOpts = append(shadowOpts, model.WithToolChoice(*cbInput.ToolChoice))
+	}
+	shadowOpts = append(shadowOpts, opts...)
+
+	run := &shadowRun{primary: make(chan shadowPrimaryResult, 1)}
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		defer func() {
+			<-m.sem
+			_ = recover()
+		}()
+
+		timeout := m.conf.Timeout
+		if timeout <= 0 {
+			timeout = defaultShadowTimeout
+		}
+		shadowCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		runInfo := &callbacks.RunInfo{
+			Name:      ShadowRunName,
+			Component: components.ComponentOfChatModel,
+		}
+		if typ, ok := components.GetType(m.conf.Model); ok {
+			runInfo.Type = typ
+		}
+
+		start := time.Now()
+		shadowMsg, shadowErr := m.conf.Model.Generate(callbacks.ReuseHandlers(shadowCtx, runInfo), in, shadowOpts...)
+		shadowLatency := time.Since(start)
+
+		primary := <-run.primary
+		if primary.msg == nil {
+			// the primary request failed, there is nothing to compare with
+			return
+		}
+
+		reportCtx := callbacks.ReuseHandlers(ctx, &callbacks.RunInfo{
+			Name:      ShadowReportRunName,
+			Type:      primaryType,
+			Component: components.ComponentOfChatModel,
+		})
+		reportCtx = callbacks.OnStart(reportCtx, cbInput)
+		if shadowErr != nil {
+			callbacks.OnError(reportCtx, fmt.Errorf("shadow request failed: %w", shadowErr))
+			return
+		}
+
+		report := compareShadow(primary.msg, shadowMsg)
+		report.PrimaryLatency = primary.latency
+		report.ShadowLatency = shadowLatency
+
+		callbacks.OnEnd(reportCtx, &model.CallbackOutput{
+			Message: shadowMsg,
+			Config:  cbInput.Config,
+			Extra: map[string]any{
+				extraKeyShadowReport: report,
+			},
+		})
+	}()
+
+	return run
+}
+
+// finish hands the primary response over to the mirrored request. A nil msg means the primary request failed.
+// It must be called exactly once.
+func (r *shadowRun) finish(msg *schema.Message, latency time.Duration) {
+	if r == nil {
+		return
+	}
+	r.primary <- shadowPrimaryResult{msg: msg, latency: latency}
+}
+
+func compareShadow(primary, shadow *schema.Message) *ShadowReport {
+	report := &ShadowReport{
+		PrimaryMessage: primary,
+		ShadowMessage:  shadow,
+	}
+	if shadow == nil {
+		return report
+	}
+
+	report.ContentSimilarity = bigramSimilarity(primary.Content, shadow.Content)
+	report.ToolCallsMatch = toolCallsMatch(primary.ToolCalls, shadow.ToolCalls)
+	report.FinishReasonMatch = finishReason(primary) == finishReason(shadow)
+	return report
+}
+
+func finishReason(msg *schema.Message) string {
+	if msg.ResponseMeta == nil {
+		return ""
+	}
+	return msg.ResponseMeta.FinishReason
+}
+
+// bigramSimilarity is the Jaccard similarity of the character bigrams of a and b,
+// which works for both space separated languages and CJK text.
+func bigramSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	sa, sb := bigrams(a), bigrams(b)
+	if len(sa) == 0 || len(sb) == 0 {
+		return 0
+	}
+	inter := 0
+	for k := range sa {
+		if _, ok := sb[k]; ok {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(sa)+len(sb)-inter)
+}
+
+func bigrams(s string) map[string]struct{} {
+	runes := []rune(s)
+	ret := make(map[string]struct{}, len(runes))
+	if len(runes) == 1 {
+		ret[s] = struct{}{}
+	}
+	for i := 0; i+1 < len(runes); i++ {
+		ret[string(runes[i:i+2])] = struct{}{}
+	}
+	return ret
+}
+
+func toolCallsMatch(a, b []schema.ToolCall) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Function.Name != b[i].Function.Name || !jsonEqual(a[i].Function.Arguments, b[i].Function.Arguments) {
+			return false
+		}
+	}
+	return true
+}
+
+func jsonEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var va, vb any
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,202 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deepseek
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	"github.com/cohesion-org/deepseek-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type mockShadowModel struct {
+	msg   *schema.Message
+	err   error
+	input []*schema.Message
+}
+
+func (m *mockShadowModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.input = input
+	return m.msg, m.err
+}
+
+func (m *mockShadowModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+type shadowResult struct {
+	report *ShadowReport
+	err    error
+}
+
+func shadowReportCtx(ctx context.Context) (context.Context, chan shadowResult) {
+	ch := make(chan shadowResult, 1)
+	handler := callbacks.NewHandlerBuilder().
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if info.Name == ShadowReportRunName {
+				report, _ := GetShadowReport(model.ConvCallbackOutput(output))
+				ch <- shadowResult{report: report}
+			}
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			if info.Name == ShadowReportRunName {
+				ch <- shadowResult{err: err}
+			}
+			return ctx
+		}).
+		Build()
+	return callbacks.InitCallbacks(ctx, nil, handler), ch
+}
+
+func waitShadow(t *testing.T, ch chan shadowResult) shadowResult {
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(3 * time.Second):
+		t.Fatal("shadow report not received")
+		return shadowResult{}
+	}
+}
+
+func TestShadowConfig(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewChatModel(ctx, &ChatModelConfig{APIKey: "my-api-key", Model: "deepseek-chat", Shadow: &ShadowConfig{SampleRate: 1}})
+	assert.Error(t, err)
+	_, err = NewChatModel(ctx, &ChatModelConfig{APIKey: "my-api-key", Model: "deepseek-chat", Shadow: &ShadowConfig{Model: &mockShadowModel{}, SampleRate: 1.5}})
+	assert.Error(t, err)
+	cm, err := NewChatModel(ctx, &ChatModelConfig{APIKey: "my-api-key", Model: "deepseek-chat", Shadow: &ShadowConfig{Model: &mockShadowModel{}, SampleRate: 0.5}})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultShadowMaxConcurrency, cap(cm.shadow.sem))
+}
+
+func TestShadowGenerate(t *testing.T) {
+	defer mockey.Mock((*deepseek.Client).CreateChatCompletion).To(func(ctx context.Context, request *deepseek.ChatCompletionRequest) (*deepseek.ChatCompletionResponse, error) {
+		return &deepseek.ChatCompletionResponse{
+			Choices: []deepseek.Choice{
+				{
+					Index:        0,
+					FinishReason: "stop",
+					Message:      deepseek.Message{Role: "assistant", Content: "hello world"},
+				},
+			},
+		}, nil
+	}).Build().UnPatch()
+
+	t.Run("report", func(t *testing.T) {
+		shadowModel := &mockShadowModel{msg: &schema.Message{
+			Role:         schema.Assistant,
+			Content:      "hello world",
+			ResponseMeta: &schema.ResponseMeta{FinishReason: "stop"},
+		}}
+		cm, err := NewChatModel(context.Background(), &ChatModelConfig{
+			APIKey: "my-api-key",
+			Model:  "deepseek-chat",
+			Shadow: &ShadowConfig{Model: shadowModel, SampleRate: 1},
+		})
+		assert.NoError(t, err)
+
+		ctx, ch := shadowReportCtx(context.Background())
+		msg, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", msg.Content)
+
+		r := waitShadow(t, ch)
+		assert.NoError(t, r.err)
+		assert.NotNil(t, r.report)
+		assert.Equal(t, 1.0, r.report.ContentSimilarity)
+		assert.True(t, r.report.ToolCallsMatch)
+		assert.True(t, r.report.FinishReasonMatch)
+		assert.Equal(t, "hi", shadowModel.input[0].Content)
+	})
+
+	t.Run("shadow error", func(t *testing.T) {
+		cm, err := NewChatModel(context.Background(), &ChatModelConfig{
+			APIKey: "my-api-key",
+			Model:  "deepseek-chat",
+			Shadow: &ShadowConfig{Model: &mockShadowModel{err: errors.New("shadow failed")}, SampleRate: 1},
+		})
+		assert.NoError(t, err)
+
+		ctx, ch := shadowReportCtx(context.Background())
+		msg, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", msg.Content)
+
+		r := waitShadow(t, ch)
+		assert.ErrorContains(t, r.err, "shadow failed")
+	})
+}
+
+func TestShadowStream(t *testing.T) {
+	defer mockey.Mock((*deepseek.Client).CreateChatCompletionStream).To(func(ctx context.Context, request *deepseek.StreamChatCompletionRequest) (deepseek.ChatCompletionStream, error) {
+		return &mockStream{responses: []*deepseek.StreamChatCompletionResponse{
+			{Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{Role: "assistant", Content: "hello"}}}},
+			{Choices: []deepseek.StreamChoices{{Index: 0, Delta: deepseek.StreamDelta{Role: "assistant", Content: " world"}}}},
+		}}, nil
+	}).Build().UnPatch()
+
+	cm, err := NewChatModel(context.Background(), &ChatModelConfig{
+		APIKey: "my-api-key",
+		Model:  "deepseek-chat",
+		Shadow: &ShadowConfig{Model: &mockShadowModel{msg: &schema.Message{
+			Role:    schema.Assistant,
+			Content: "hello there",
+		}}, SampleRate: 1},
+	})
+	assert.NoError(t, err)
+
+	ctx, ch := shadowReportCtx(context.Background())
+	sr, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+	assert.NoError(t, err)
+	for {
+		_, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+	}
+
+	r := waitShadow(t, ch)
+	assert.NoError(t, r.err)
+	assert.Equal(t, "hello world", r.report.PrimaryMessage.Content)
+	assert.Greater(t, r.report.ContentSimilarity, 0.0)
+	assert.Less(t, r.report.ContentSimilarity, 1.0)
+}
+
+func TestCompareShadow(t *testing.T) {
+	assert.Equal(t, 1.0, bigramSimilarity("", ""))
+	assert.Equal(t, 0.0, bigramSimilarity("abc", ""))
+	assert.Equal(t, 1.0, bigramSimilarity("你好", "你好"))
+	assert.InDelta(t, 1.0/3, bigramSimilarity("abc", "abd"), 1e-9)
+
+	a := []schema.ToolCall{{Function: schema.FunctionCall{Name: "f", Arguments: `{"a":1,"b":2}`}}}
+	b := []schema.ToolCall{{Function: schema.FunctionCall{Name: "f", Arguments: `{"b":2, "a":1}`}}}
+	c := []schema.ToolCall{{Function: schema.FunctionCall{Name: "g", Arguments: `{"a":1,"b":2}`}}}
+	assert.True(t, toolCallsMatch(a, b))
+	assert.False(t, toolCallsMatch(a, c))
+	assert.False(t, toolCallsMatch(a, nil))
+}
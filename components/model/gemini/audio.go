@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// audioStreamAligner re-chunks the audio streamed by native audio models, so that every chunk but the last
+// one encodes a multiple of 3 bytes. The base64 strings of the chunks then carry no padding, and the audio
+// concatenated by schema.ConcatMessages is a valid base64 string of the complete audio.
+type audioStreamAligner struct {
+	pending  []byte
+	mimeType string
+}
+
+func (a *audioStreamAligner) align(message *schema.Message) error {
+	if message == nil {
+		return nil
+	}
+	for i := range message.AssistantGenMultiContent {
+		part := &message.AssistantGenMultiContent[i]
+		if part.Type != schema.ChatMessagePartTypeAudioURL || part.Audio == nil || part.Audio.Base64Data == nil {
+			continue
+		}
+
+		data, err := base64.StdEncoding.DecodeString(*part.Audio.Base64Data)
+		if err != nil {
+			return fmt.Errorf("failed to decode audio chunk: %w", err)
+		}
+		if len(a.pending) > 0 {
+			data = append(a.pending, data...)
+		}
+
+		n := len(data) - len(data)%3
+		a.pending = append([]byte(nil), data[n:]...)
+		a.mimeType = part.Audio.MIMEType
+
+		encoded := base64.StdEncoding.EncodeToString(data[:n])
+		part.Audio.Base64Data = &encoded
+	}
+	return nil
+}
+
+// flush returns a message carrying the remaining audio bytes, or nil if there are none.
+func (a *audioStreamAligner) flush() *schema.Message {
+	if len(a.pending) == 0 {
+		return nil
+	}
+	encoded := base64.StdEncoding.EncodeToString(a.pending)
+	a.pending = nil
+	return &schema.Message{
+		Role: schema.Assistant,
+		AssistantGenMultiContent: []schema.MessageOutputPart{
+			{
+				Type: schema.ChatMessagePartTypeAudioURL,
+				Audio: &schema.MessageOutputAudio{
+					MessagePartCommon: schema.MessagePartCommon{
+						Base64Data: &encoded,
+						MIMEType:   a.mimeType,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudioStreamAligner(t *testing.T) {
+	audio := []byte("0123456789abcdefghijklmnopqrstuvwxyz!")
+	chunks := [][]byte{audio[:4], audio[4:5], audio[5:16], audio[16:]}
+
+	newChunk := func(data []byte) *schema.Message {
+		encoded := base64.StdEncoding.EncodeToString(data)
+		return &schema.Message{
+			Role: schema.Assistant,
+			AssistantGenMultiContent: []schema.MessageOutputPart{
+				{
+					Type: schema.ChatMessagePartTypeAudioURL,
+					Audio: &schema.MessageOutputAudio{
+						MessagePartCommon: schema.MessagePartCommon{
+							Base64Data: &encoded,
+							MIMEType:   "audio/L16;codec=pcm;rate=24000",
+							Extra:      map[string]any{audioMetaDataKey: parseAudioMetaData("audio/L16;codec=pcm;rate=24000")},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	aligner := &audioStreamAligner{}
+	var msgs []*schema.Message
+	for _, chunk := range chunks {
+		msg := newChunk(chunk)
+		assert.NoError(t, aligner.align(msg))
+		b64 := *msg.AssistantGenMultiContent[0].Audio.Base64Data
+		assert.NotContains(t, b64, "=")
+		msgs = append(msgs, msg)
+	}
+	last := aligner.flush()
+	assert.NotNil(t, last)
+	msgs = append(msgs, last)
+	assert.Nil(t, aligner.flush())
+
+	merged, err := schema.ConcatMessages(msgs)
+	assert.NoError(t, err)
+	assert.Len(t, merged.AssistantGenMultiContent, 1)
+
+	part := merged.AssistantGenMultiContent[0]
+	data, err := base64.StdEncoding.DecodeString(*part.Audio.Base64Data)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(audio, data))
+	assert.Equal(t, &AudioMetaData{Codec: "pcm", SampleRate: 24000}, GetOutputAudioMetaData(part.Audio))
+
+	t.Run("invalid base64", func(t *testing.T) {
+		invalid := "!!!"
+		msg := &schema.Message{AssistantGenMultiContent: []schema.MessageOutputPart{
+			{Type: schema.ChatMessagePartTypeAudioURL, Audio: &schema.MessageOutputAudio{MessagePartCommon: schema.MessagePartCommon{Base64Data: &invalid}}},
+		}}
+		assert.Error(t, (&audioStreamAligner{}).align(msg))
+	})
+}
+
+func TestGetOutputAudioMetaData(t *testing.T) {
+	assert.Nil(t, GetOutputAudioMetaData(nil))
+	assert.Nil(t, GetOutputAudioMetaData(&schema.MessageOutputAudio{MessagePartCommon: schema.MessagePartCommon{MIMEType: "audio/mp3"}}))
+	assert.Equal(t, &AudioMetaData{SampleRate: 16000},
+		GetOutputAudioMetaData(&schema.MessageOutputAudio{MessagePartCommon: schema.MessagePartCommon{MIMEType: "audio/pcm;rate=16000"}}))
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"github.com/cloudwego/eino-ext/libs/callbackextra"
+	"google.golang.org/genai"
+)
+
+// The keys of the Extra of the callback input and output shared with the other model components,
+// so that the traces of several providers look the same.
+const (
+	// CallbackExtraKeyModelName is the key of the model version which served the request, in the callback output only.
+	// The value is a string.
+	CallbackExtraKeyModelName = callbackextra.KeyModelName
+	// CallbackExtraKeyThinking is the key of the thinking config of the request, the value is a *genai.ThinkingConfig.
+	CallbackExtraKeyThinking = "thinking"
+	// CallbackExtraKeyThinkingConfig is the key of the thinking config of the request converted into the one
+	// reported by the other model components, the value is a *callbackextra.Thinking.
+	CallbackExtraKeyThinkingConfig = callbackextra.KeyThinkingConfig
+	// CallbackExtraKeyCachedContent is the key of the name of the cached content used by the request,
+	// the value is a string.
+	CallbackExtraKeyCachedContent = callbackextra.KeyCachedContent
+)
+
+// callbackExtra returns the Extra of the callback input reporting the request config, nil if there is nothing to report.
+func callbackExtra(conf *genai.GenerateContentConfig) map[string]any {
+	if conf == nil {
+		return nil
+	}
+	extra := make(map[string]any)
+	if len(conf.Labels) > 0 {
+		extra[CallbackExtraKeyLabels] = conf.Labels
+	}
+	if conf.ThinkingConfig != nil {
+		extra[CallbackExtraKeyThinking] = conf.ThinkingConfig
+		extra[CallbackExtraKeyThinkingConfig] = thinkingExtra(conf.ThinkingConfig)
+	}
+	if conf.CachedContent != "" {
+		extra[CallbackExtraKeyCachedContent] = conf.CachedContent
+	}
+	if len(extra) == 0 {
+		return nil
+	}
+	return extra
+}
+
+// thinkingExtra converts the thinking config into the one reported by the model components:
+// a zero budget disables the thinking, a positive one enables it and the others let the model decide.
+func thinkingExtra(conf *genai.ThinkingConfig) *callbackextra.Thinking {
+	if conf.ThinkingBudget == nil || *conf.ThinkingBudget < 0 {
+		return &callbackextra.Thinking{Type: callbackextra.ThinkingAuto}
+	}
+	if *conf.ThinkingBudget == 0 {
+		return &callbackextra.Thinking{Type: callbackextra.ThinkingDisabled}
+	}
+	budget := int(*conf.ThinkingBudget)
+	return &callbackextra.Thinking{Type: callbackextra.ThinkingEnabled, BudgetTokens: &budget}
+}
+
+// callbackOutputExtra returns the Extra of the callback output, which adds the model version to the callbackExtra.
+func callbackOutputExtra(conf *genai.GenerateContentConfig, modelVersion string) map[string]any {
+	extra := callbackExtra(conf)
+	if modelVersion == "" {
+		return extra
+	}
+	if extra == nil {
+		extra = make(map[string]any, 1)
+	}
+	extra[CallbackExtraKeyModelName] = modelVersion
+	return extra
+}
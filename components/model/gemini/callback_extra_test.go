@@ -0,0 +1,77 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/libs/callbackextra"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestCallbackExtra(t *testing.T) {
+	thinking := &genai.ThinkingConfig{IncludeThoughts: true}
+	cm, err := NewChatModel(context.Background(), &Config{Model: "test model", ThinkingConfig: thinking})
+	require.NoError(t, err)
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	typ, ok := components.GetType(cm)
+	assert.True(t, ok)
+	assert.Equal(t, "Gemini", typ)
+	assert.True(t, components.IsCallbacksEnabled(cm))
+
+	_, _, conf, _, err := cm.genInputAndConf(input, WithCachedContentName("cachedContents/abc"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		CallbackExtraKeyThinking:       thinking,
+		CallbackExtraKeyThinkingConfig: &callbackextra.Thinking{Type: callbackextra.ThinkingAuto},
+		CallbackExtraKeyCachedContent:  "cachedContents/abc",
+	}, callbackExtra(conf))
+
+	output := convCallbackOutput(schema.AssistantMessage("hello", nil), nil, conf, "gemini-2.5-flash-001")
+	assert.Equal(t, "gemini-2.5-flash-001", output.Extra[CallbackExtraKeyModelName])
+	assert.Equal(t, thinking, output.Extra[CallbackExtraKeyThinking])
+	assert.Equal(t, &callbackextra.Thinking{Type: callbackextra.ThinkingAuto}, output.Extra[CallbackExtraKeyThinkingConfig])
+	// the output extra does not modify the input extra
+	assert.NotContains(t, callbackExtra(conf), CallbackExtraKeyModelName)
+
+	output = convCallbackOutput(schema.AssistantMessage("hello", nil), nil, &genai.GenerateContentConfig{}, "gemini-2.5-flash-001")
+	assert.Equal(t, map[string]any{CallbackExtraKeyModelName: "gemini-2.5-flash-001"}, output.Extra)
+	assert.Nil(t, callbackOutputExtra(nil, ""))
+}
+
+func TestThinkingExtra(t *testing.T) {
+	budget := func(n int32) *int32 { return &n }
+	enabled := 1024
+	cases := []struct {
+		conf     *genai.ThinkingConfig
+		expected *callbackextra.Thinking
+	}{
+		{conf: &genai.ThinkingConfig{IncludeThoughts: true}, expected: &callbackextra.Thinking{Type: callbackextra.ThinkingAuto}},
+		{conf: &genai.ThinkingConfig{ThinkingBudget: budget(-1)}, expected: &callbackextra.Thinking{Type: callbackextra.ThinkingAuto}},
+		{conf: &genai.ThinkingConfig{ThinkingBudget: budget(0)}, expected: &callbackextra.Thinking{Type: callbackextra.ThinkingDisabled}},
+		{conf: &genai.ThinkingConfig{ThinkingBudget: budget(1024)}, expected: &callbackextra.Thinking{Type: callbackextra.ThinkingEnabled, BudgetTokens: &enabled}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, thinkingExtra(c.conf))
+	}
+}
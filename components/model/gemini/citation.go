@@ -0,0 +1,119 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"google.golang.org/genai"
+)
+
+func init() {
+	compose.RegisterStreamChunkConcatFunc(func(chunks [][]Citation) (final []Citation, err error) {
+		for _, chunk := range chunks {
+			final = append(final, chunk...)
+		}
+		return final, nil
+	})
+	schema.RegisterName[[]Citation]("_eino_ext_gemini_citations")
+}
+
+const citationsKey = "gemini_citations"
+
+// Citation is a source grounding a segment of the generated text, normalized from the grounding supports
+// and grounding chunks of the grounding metadata, e.g. to render an inline citation marker at EndIndex.
+type Citation struct {
+	// StartIndex is the start byte offset of the segment in the text of the message, inclusive.
+	StartIndex int `json:"start_index"`
+	// EndIndex is the end byte offset of the segment in the text of the message, exclusive.
+	EndIndex int `json:"end_index"`
+	// URI is the URI of the source, e.g. the web page, the retrieved document or the Google Maps place.
+	URI string `json:"uri,omitempty"`
+	// Title is the title of the source.
+	Title string `json:"title,omitempty"`
+	// Score is the confidence of the source supporting the segment, in [0, 1], or 0 if Gemini returned none.
+	Score float32 `json:"score,omitempty"`
+}
+
+// GetCitations returns the citations of the message, ordered by segment and then by source,
+// or nil if the message is not grounded, e.g. without the Google Search tool.
+func GetCitations(m *schema.Message) []Citation {
+	if m == nil {
+		return nil
+	}
+	if citations, ok := m.Extra[citationsKey].([]Citation); ok {
+		return citations
+	}
+	return nil
+}
+
+func setCitations(m *schema.Message, gm *genai.GroundingMetadata) {
+	if m == nil {
+		return
+	}
+	citations := convCitations(gm)
+	if len(citations) == 0 {
+		return
+	}
+	if m.Extra == nil {
+		m.Extra = make(map[string]any)
+	}
+	m.Extra[citationsKey] = citations
+}
+
+// convCitations joins each grounding support with the grounding chunks it references,
+// skipping the supports without a segment and the indices out of range.
+func convCitations(gm *genai.GroundingMetadata) []Citation {
+	if gm == nil {
+		return nil
+	}
+	var citations []Citation
+	for _, support := range gm.GroundingSupports {
+		if support == nil || support.Segment == nil {
+			continue
+		}
+		for i, idx := range support.GroundingChunkIndices {
+			if idx < 0 || int(idx) >= len(gm.GroundingChunks) || gm.GroundingChunks[idx] == nil {
+				continue
+			}
+			uri, title := groundingChunkSource(gm.GroundingChunks[idx])
+			citation := Citation{
+				StartIndex: int(support.Segment.StartIndex),
+				EndIndex:   int(support.Segment.EndIndex),
+				URI:        uri,
+				Title:      title,
+			}
+			if i < len(support.ConfidenceScores) {
+				citation.Score = support.ConfidenceScores[i]
+			}
+			citations = append(citations, citation)
+		}
+	}
+	return citations
+}
+
+func groundingChunkSource(chunk *genai.GroundingChunk) (uri, title string) {
+	switch {
+	case chunk.Web != nil:
+		return chunk.Web.URI, chunk.Web.Title
+	case chunk.RetrievedContext != nil:
+		return chunk.RetrievedContext.URI, chunk.RetrievedContext.Title
+	case chunk.Maps != nil:
+		return chunk.Maps.URI, chunk.Maps.Title
+	}
+	return "", ""
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genai"
+)
+
+func TestCitations(t *testing.T) {
+	gm := &genai.GroundingMetadata{
+		GroundingChunks: []*genai.GroundingChunk{
+			{Web: &genai.GroundingChunkWeb{URI: "https://a.com", Title: "a.com"}},
+			{RetrievedContext: &genai.GroundingChunkRetrievedContext{URI: "gs://bucket/doc.pdf", Title: "doc"}},
+			{Maps: &genai.GroundingChunkMaps{URI: "https://maps.google.com/?cid=1", Title: "Cafe"}},
+		},
+		GroundingSupports: []*genai.GroundingSupport{
+			{
+				Segment:               &genai.Segment{StartIndex: 0, EndIndex: 10, Text: "first part"},
+				GroundingChunkIndices: []int32{0, 1},
+				ConfidenceScores:      []float32{0.9, 0.5},
+			},
+			{Segment: &genai.Segment{StartIndex: 11, EndIndex: 20}, GroundingChunkIndices: []int32{2, 5}},
+			{GroundingChunkIndices: []int32{0}},
+		},
+	}
+
+	msg, err := convCandidate(&genai.Candidate{
+		Content:           &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "first part, second"}}},
+		GroundingMetadata: gm,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Citation{
+		{StartIndex: 0, EndIndex: 10, URI: "https://a.com", Title: "a.com", Score: 0.9},
+		{StartIndex: 0, EndIndex: 10, URI: "gs://bucket/doc.pdf", Title: "doc", Score: 0.5},
+		{StartIndex: 11, EndIndex: 20, URI: "https://maps.google.com/?cid=1", Title: "Cafe"},
+	}, GetCitations(msg))
+
+	msg, err = convCandidate(&genai.Candidate{Content: &genai.Content{Role: "model", Parts: []*genai.Part{{Text: "hi"}}}})
+	assert.NoError(t, err)
+	assert.Nil(t, GetCitations(msg))
+	assert.Nil(t, GetCitations(nil))
+
+	concatenated, err := schema.ConcatMessages([]*schema.Message{
+		{Role: schema.Assistant, Content: "a", Extra: map[string]any{citationsKey: []Citation{{EndIndex: 1, URI: "x"}}}},
+		{Role: schema.Assistant, Content: "b", Extra: map[string]any{citationsKey: []Citation{{StartIndex: 1, EndIndex: 2, URI: "y"}}}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, GetCitations(concatenated), 2)
+}
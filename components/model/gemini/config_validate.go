@@ -0,0 +1,77 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+	"google.golang.org/genai"
+)
+
+// maxStopSequences is the max number of stop sequences accepted by the Gemini API.
+const maxStopSequences = 5
+
+// Validate checks the config without calling the Gemini API, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid.
+func (c *Config) Validate() error {
+	r := configcheck.New("gemini.Config")
+	r.Checkf(c.Client != nil, "Client", "is required")
+	configcheck.Positive(r, "MaxTokens", c.MaxTokens)
+	configcheck.InRange(r, "Temperature", c.Temperature, 0, 2)
+	configcheck.InRange(r, "TopP", c.TopP, 0, 1)
+	configcheck.Positive(r, "TopK", c.TopK)
+	r.Checkf(len(c.Stop) <= maxStopSequences, "Stop", "at most %d sequences are allowed, got %d", maxStopSequences, len(c.Stop))
+	configcheck.InRange(r, "PresencePenalty", c.PresencePenalty, -2, 2)
+	configcheck.InRange(r, "FrequencyPenalty", c.FrequencyPenalty, -2, 2)
+	for _, m := range c.ResponseModalities {
+		switch m {
+		case GeminiResponseModalityText, GeminiResponseModalityImage, GeminiResponseModalityAudio:
+		default:
+			r.Addf("ResponseModalities", "unsupported modality %q", m)
+		}
+	}
+	switch c.MediaResolution {
+	case "", genai.MediaResolutionUnspecified, genai.MediaResolutionLow, genai.MediaResolutionMedium, genai.MediaResolutionHigh:
+	default:
+		r.Addf("MediaResolution", "unsupported value %q", c.MediaResolution)
+	}
+	if c.Cache != nil {
+		configcheck.NonNegative(r, "Cache.TTL", &c.Cache.TTL)
+	}
+	configcheck.NonNegative(r, "StallTimeout", &c.StallTimeout)
+	switch c.SystemMessageMode {
+	case SystemMessageModeDefault, SystemMessageModeMerge, SystemMessageModeStrictSingle:
+	default:
+		r.Addf("SystemMessageMode", "unknown system message mode %q", c.SystemMessageMode)
+	}
+	if c.HistoryCompression != nil {
+		r.Checkf(c.HistoryCompression.Compressor != nil, "HistoryCompression.Compressor", "is required")
+		configcheck.Positive(r, "HistoryCompression.MaxInputTokens", &c.HistoryCompression.MaxInputTokens)
+	}
+	if c.Retry != nil {
+		configcheck.NonNegative(r, "Retry.MaxAttempts", &c.Retry.MaxAttempts)
+		configcheck.NonNegative(r, "Retry.InitialBackoff", &c.Retry.InitialBackoff)
+		configcheck.NonNegative(r, "Retry.MaxBackoff", &c.Retry.MaxBackoff)
+		for _, kind := range c.Retry.RetryableKinds {
+			switch kind {
+			case ErrorKindQuota, ErrorKindInvalidArgument, ErrorKindInternal, ErrorKindOther:
+			default:
+				r.Addf("Retry.RetryableKinds", "unknown error kind %q", kind)
+			}
+		}
+	}
+	return r.Err()
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	cli := &genai.Client{}
+	assert.NoError(t, (&Config{Client: cli, Model: "gemini-2.5-flash"}).Validate())
+
+	err := (&Config{
+		Temperature:        genai.Ptr[float32](2.5),
+		TopK:               genai.Ptr[int32](0),
+		Stop:               []string{"1", "2", "3", "4", "5", "6"},
+		ResponseModalities: []GeminiResponseModality{GeminiResponseModalityText, "VIDEO"},
+		SystemMessageMode:  "drop",
+		HistoryCompression: &HistoryCompressionConfig{},
+		Retry:              &RetryConfig{InitialBackoff: -time.Second, RetryableKinds: []ErrorKind{"timeout"}},
+	}).Validate()
+	report, ok := err.(*configcheck.Report)
+	require.True(t, ok)
+	assert.Equal(t, "gemini.Config", report.Config)
+	assert.Equal(t, []configcheck.Issue{
+		{Field: "Client", Message: "is required"},
+		{Field: "Temperature", Message: "must be in [0, 2], got 2.5"},
+		{Field: "TopK", Message: "must be positive, got 0"},
+		{Field: "Stop", Message: "at most 5 sequences are allowed, got 6"},
+		{Field: "ResponseModalities", Message: `unsupported modality "VIDEO"`},
+		{Field: "SystemMessageMode", Message: `unknown system message mode "drop"`},
+		{Field: "HistoryCompression.Compressor", Message: "is required"},
+		{Field: "HistoryCompression.MaxInputTokens", Message: "must be positive, got 0"},
+		{Field: "Retry.InitialBackoff", Message: "must not be negative, got -1s"},
+		{Field: "Retry.RetryableKinds", Message: `unknown error kind "timeout"`},
+	}, report.Issues)
+}
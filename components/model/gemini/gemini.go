@@ -31,13 +31,20 @@ import (
 	"github.com/google/uuid"
 	"google.golang.org/genai"
 
+	"github.com/cloudwego/eino-ext/libs/multimodal"
+	"github.com/cloudwego/eino-ext/libs/pii"
+	"github.com/cloudwego/eino-ext/libs/stall"
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 )
 
-var _ model.ToolCallingChatModel = (*ChatModel)(nil)
+var (
+	_ model.ToolCallingChatModel = (*ChatModel)(nil)
+	_ components.Typer           = (*ChatModel)(nil)
+	_ components.Checker         = (*ChatModel)(nil)
+)
 
 // NewChatModel creates a new Gemini chat model instance
 //
@@ -56,6 +63,10 @@ var _ model.ToolCallingChatModel = (*ChatModel)(nil)
 //	    Model: "gemini-pro",
 //	})
 func NewChatModel(_ context.Context, cfg *Config) (*ChatModel, error) {
+	var retry *RetryConfig
+	if cfg.Retry != nil {
+		retry = cfg.Retry.withDefaults()
+	}
 	return &ChatModel{
 		cli: cfg.Client,
 
@@ -64,6 +75,10 @@ func NewChatModel(_ context.Context, cfg *Config) (*ChatModel, error) {
 		temperature:                 cfg.Temperature,
 		topP:                        cfg.TopP,
 		topK:                        cfg.TopK,
+		stop:                        cfg.Stop,
+		seed:                        cfg.Seed,
+		presencePenalty:             cfg.PresencePenalty,
+		frequencyPenalty:            cfg.FrequencyPenalty,
 		responseJSONSchema:          cfg.ResponseJSONSchema,
 		enableCodeExecution:         cfg.EnableCodeExecution,
 		enableGoogleSearch:          cfg.EnableGoogleSearch,
@@ -72,12 +87,24 @@ func NewChatModel(_ context.Context, cfg *Config) (*ChatModel, error) {
 		enableURLContext:            cfg.EnableURLContext,
 		enableFileSearch:            cfg.EnableFileSearch,
 		enableGoogleMaps:            cfg.EnableGoogleMaps,
+		retrievalConfig:             cfg.RetrievalConfig,
 		safetySettings:              cfg.SafetySettings,
 		thinkingConfig:              cfg.ThinkingConfig,
 		imageConfig:                 cfg.ImageConfig,
 		responseModalities:          cfg.ResponseModalities,
+		speechConfig:                cfg.SpeechConfig,
 		mediaResolution:             cfg.MediaResolution,
 		cache:                       cfg.Cache,
+		stallTimeout:                cfg.StallTimeout,
+		systemMessageMode:           cfg.SystemMessageMode,
+		systemMessageSeparator:      cfg.SystemMessageSeparator,
+		mediaLimits:                 cfg.MediaLimits,
+		interceptor:                 cfg.Interceptor,
+		historyCompression:          cfg.HistoryCompression,
+		disableCallbacks:            cfg.EnableCallbacks != nil && !*cfg.EnableCallbacks,
+		labels:                      cfg.Labels,
+		retry:                       retry,
+		separateReasoningChunks:     cfg.SeparateReasoningChunks,
 	}, nil
 }
 
@@ -109,6 +136,26 @@ type Config struct {
 	// Optional. Example: topK := int32(40)
 	TopK *int32
 
+	// Stop specifies the sequences where the model stops generating, up to 5 sequences
+	// Can be overridden by model.WithStop
+	// Optional. Example: []string{"\n\n"}
+	Stop []string
+
+	// Seed makes the sampling deterministic on a best-effort basis, repeated requests with the same seed
+	// and parameters tend to return the same result
+	// Optional. Example: seed := int32(42)
+	Seed *int32
+
+	// PresencePenalty penalizes the tokens already present in the response, encouraging new topics
+	// Range: [-2.0, 2.0), where positive values discourage repetition
+	// Optional. Example: presencePenalty := float32(0.5)
+	PresencePenalty *float32
+
+	// FrequencyPenalty penalizes the tokens by their number of occurrences in the response
+	// Range: [-2.0, 2.0), where positive values discourage repetition
+	// Optional. Example: frequencyPenalty := float32(0.5)
+	FrequencyPenalty *float32
+
 	// ResponseJSONSchema defines the structure for JSON responses
 	// Optional. Used when you want structured output in JSON format
 	ResponseJSONSchema *jsonschema.Schema
@@ -125,6 +172,12 @@ type Config struct {
 	EnableFileSearch            *genai.FileSearch
 	EnableGoogleMaps            *genai.GoogleMaps
 
+	// RetrievalConfig is the retrieval config of the Google Maps tool, e.g. the location of the user
+	// to ground the answers on the places nearby. Set EnableGoogleMaps.EnableWidget to get the widget context token,
+	// see GetGoogleMapsWidgetContextToken. Can be overridden by WithToolConfig.
+	// Optional.
+	RetrievalConfig *genai.RetrievalConfig
+
 	// SafetySettings configures content filtering for different harm categories
 	// Controls the model's filtering behavior for potentially harmful content
 	// Optional.
@@ -141,11 +194,76 @@ type Config struct {
 	// Optional.
 	ResponseModalities []GeminiResponseModality
 
+	// SpeechConfig is the speech generation configuration, e.g. the voice,
+	// used by native audio models when ResponseModalities contains GeminiResponseModalityAudio.
+	// Optional.
+	SpeechConfig *genai.SpeechConfig
+
+	// MediaResolution is the resolution of the image and video inputs, trading the input tokens of the media
+	// against the quality of the answers, e.g. genai.MediaResolutionLow to cut the cost of video understanding.
+	// It can be overridden per request by WithMediaResolution,
+	// and per image by the Detail of the image, low or high.
+	// Optional. Default: the resolution of the model.
 	MediaResolution genai.MediaResolution
 
 	// Cache controls prefix cache settings for the model.
 	// Optional. used to CreatePrefixCache for reused inputs.
 	Cache *CacheConfig
+
+	// StallTimeout specifies the maximum duration to wait for the next chunk in Stream.
+	// If no chunk arrives within the window, the upstream request is canceled
+	// and a *stall.Error is sent on the StreamReader.
+	// Optional. Default: no stall detection
+	StallTimeout time.Duration
+
+	// SystemMessageMode defines how system messages are mapped to the system instruction,
+	// e.g. SystemMessageModeMerge for agents adding system messages in the middle of the conversation.
+	// Optional. Default: SystemMessageModeDefault
+	SystemMessageMode SystemMessageMode
+
+	// SystemMessageSeparator joins the system messages merged by SystemMessageModeMerge.
+	// Optional. Default: "\n\n"
+	SystemMessageSeparator string
+
+	// MediaLimits, if set, fails the input messages violating it before the request is sent, see multimodal.Limits.
+	// Optional. Default: no limits
+	MediaLimits *multimodal.Limits
+
+	// Interceptor processes the input messages before they are sent and the output messages before they are returned,
+	// e.g. a *pii.Redactor replacing the personally identifiable information with placeholders restored in the response.
+	// The callbacks see the intercepted input and output messages.
+	// Optional. Default: none
+	Interceptor pii.Interceptor
+
+	// HistoryCompression compresses the input messages whose estimated tokens exceed a threshold,
+	// e.g. summarizing the older turns with NewSummaryCompressor, before they are converted to genai contents.
+	// Optional. Default: no compression
+	HistoryCompression *HistoryCompressionConfig
+
+	// EnableCallbacks is returned by IsCallbacksEnabled. When false, the model reports no callbacks itself
+	// and leaves them to the graph running it, which only reports the messages without the model config.
+	// Optional. Default: true
+	EnableCallbacks *bool
+
+	// Labels are user-defined metadata sent with every request, e.g. the team or the feature,
+	// to break down the billed charges in the Vertex AI billing export.
+	// They are merged with the labels of WithLabels and reported in the Extra of the callbacks.
+	// Note: only supported by the Vertex AI backend, the Gemini API returns an error for labels.
+	// Optional.
+	Labels map[string]string
+
+	// Retry enables the retries of the requests failed with a quota (RESOURCE_EXHAUSTED) or internal error,
+	// honoring the delay suggested by the RetryInfo of the error.
+	// The errors of the Gemini API are returned as *APIError classified by ErrorKind whether it is set or not.
+	// Optional. Default: no retry
+	Retry *RetryConfig
+
+	// SeparateReasoningChunks streams the thoughts and the response of the model in separate chunks,
+	// instead of chunks mixing ReasoningContent and Content deltas, tagged with their StreamChunkType,
+	// see GetStreamChunkType. It makes it easy for UIs to render the thoughts in a collapsible pane.
+	// It can be overridden by WithSeparateReasoningChunks. Only applies to Stream.
+	// Optional. Default: false
+	SeparateReasoningChunks bool
 }
 
 // CacheConfig controls prefix cache settings for the model.
@@ -164,6 +282,10 @@ type ChatModel struct {
 	topP                        *float32
 	temperature                 *float32
 	topK                        *int32
+	stop                        []string
+	seed                        *int32
+	presencePenalty             *float32
+	frequencyPenalty            *float32
 	responseJSONSchema          *jsonschema.Schema
 	tools                       []*genai.FunctionDeclaration
 	origTools                   []*schema.ToolInfo
@@ -175,18 +297,45 @@ type ChatModel struct {
 	enableURLContext            *genai.URLContext
 	enableFileSearch            *genai.FileSearch
 	enableGoogleMaps            *genai.GoogleMaps
+	retrievalConfig             *genai.RetrievalConfig
 	safetySettings              []*genai.SafetySetting
 	thinkingConfig              *genai.ThinkingConfig
 	imageConfig                 *genai.ImageConfig
 	responseModalities          []GeminiResponseModality
+	speechConfig                *genai.SpeechConfig
 	mediaResolution             genai.MediaResolution
 	cache                       *CacheConfig
+	stallTimeout                time.Duration
+	systemMessageMode           SystemMessageMode
+	systemMessageSeparator      string
+	mediaLimits                 *multimodal.Limits
+	interceptor                 pii.Interceptor
+	historyCompression          *HistoryCompressionConfig
+	disableCallbacks            bool
+	labels                      map[string]string
+	retry                       *RetryConfig
+	separateReasoningChunks     bool
 }
 
 func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (message *schema.Message, err error) {
 
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
 
+	input, restorer, err := pii.Apply(ctx, cm.interceptor, input)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			message = restorer.Restore(message)
+		}
+	}()
+
+	input, err = compressHistory(ctx, cm.historyCompression, input)
+	if err != nil {
+		return nil, err
+	}
+
 	modelName, nInput, genaiConf, cbConf, err := cm.genInputAndConf(input, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("genInputAndConf for Generate failed: %w", err)
@@ -196,14 +345,17 @@ func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts
 		Tools:      cm.origTools,
 		ToolChoice: cm.toolChoice,
 	}, opts...)
-	ctx = callbacks.OnStart(ctx, &model.CallbackInput{
-		Messages:   input,
-		Tools:      co.Tools,
-		ToolChoice: co.ToolChoice,
-		Config:     cbConf,
-	})
+	if !cm.disableCallbacks {
+		ctx = callbacks.OnStart(ctx, &model.CallbackInput{
+			Messages:   input,
+			Tools:      co.Tools,
+			ToolChoice: co.ToolChoice,
+			Config:     cbConf,
+			Extra:      callbackExtra(genaiConf),
+		})
+	}
 	defer func() {
-		if err != nil {
+		if err != nil && !cm.disableCallbacks {
 			callbacks.OnError(ctx, err)
 		}
 	}()
@@ -217,7 +369,9 @@ func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts
 	}
 
 	// Generate content using the Gemini API
-	result, err := cm.cli.Models.GenerateContent(ctx, modelName, contents, genaiConf)
+	result, err := withRetry(ctx, cm.retry, func() (*genai.GenerateContentResponse, error) {
+		return cm.cli.Models.GenerateContent(ctx, modelName, contents, genaiConf)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("send message fail: %w", err)
 	}
@@ -228,7 +382,9 @@ func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts
 		return nil, fmt.Errorf("convert response fail: %w", err)
 	}
 
-	callbacks.OnEnd(ctx, convCallbackOutput(message, cbConf))
+	if !cm.disableCallbacks {
+		callbacks.OnEnd(ctx, convCallbackOutput(message, cbConf, genaiConf, result.ModelVersion))
+	}
 	return message, nil
 }
 
@@ -236,6 +392,21 @@ func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts .
 
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
 
+	input, restorer, err := pii.Apply(ctx, cm.interceptor, input)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			result = restorer.RestoreStream(result)
+		}
+	}()
+
+	input, err = compressHistory(ctx, cm.historyCompression, input)
+	if err != nil {
+		return nil, err
+	}
+
 	modelName, nInput, genaiConf, cbConf, err := cm.genInputAndConf(input, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("genInputAndConf for Stream failed: %w", err)
@@ -245,14 +416,17 @@ func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts .
 		Tools:      cm.origTools,
 		ToolChoice: cm.toolChoice,
 	}, opts...)
-	ctx = callbacks.OnStart(ctx, &model.CallbackInput{
-		Messages:   input,
-		Tools:      co.Tools,
-		ToolChoice: co.ToolChoice,
-		Config:     cbConf,
-	})
+	if !cm.disableCallbacks {
+		ctx = callbacks.OnStart(ctx, &model.CallbackInput{
+			Messages:   input,
+			Tools:      co.Tools,
+			ToolChoice: co.ToolChoice,
+			Config:     cbConf,
+			Extra:      callbackExtra(genaiConf),
+		})
+	}
 	defer func() {
-		if err != nil {
+		if err != nil && !cm.disableCallbacks {
 			callbacks.OnError(ctx, err)
 		}
 	}()
@@ -265,37 +439,88 @@ func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts .
 	if err != nil {
 		return nil, fmt.Errorf("convert schema message fail: %w", err)
 	}
-	resultIter := cm.cli.Models.GenerateContentStream(ctx, modelName, contents, genaiConf)
-
+	streamCtx, watcher, stopWatcher := stall.NewWatcher(ctx, "gemini", cm.stallTimeout)
+	aligner := &audioStreamAligner{}
 	sr, sw := schema.Pipe[*model.CallbackOutput](1)
+	separateReasoning := cm.separateReasoningChunks
+	if o := model.GetImplSpecificOptions(&options{}, opts...); o.SeparateReasoningChunks != nil {
+		separateReasoning = *o.SeparateReasoningChunks
+	}
+	send := func(message *schema.Message, modelVersion string) bool {
+		if !separateReasoning {
+			return sw.Send(convCallbackOutput(message, cbConf, genaiConf, modelVersion), nil)
+		}
+		for _, chunk := range separateReasoningChunk(message) {
+			if closed := sw.Send(convCallbackOutput(chunk, cbConf, genaiConf, modelVersion), nil); closed {
+				return true
+			}
+		}
+		return false
+	}
+
 	go func() {
 		defer func() {
 			pe := recover()
+			stopWatcher()
 
 			if pe != nil {
 				_ = sw.Send(nil, newPanicErr(pe, debug.Stack()))
 			}
 			sw.Close()
 		}()
-		for resp, err_ := range resultIter {
-			if err_ != nil {
-				sw.Send(nil, err_)
-				return
+		var modelVersion string
+		// the request is retried only if it fails before the first chunk, so no chunk is sent twice
+		for attempt := 1; ; attempt++ {
+			var retryErr error
+			received := false
+			for resp, err_ := range cm.cli.Models.GenerateContentStream(streamCtx, modelName, contents, genaiConf) {
+				watcher.Disarm()
+				if err_ != nil {
+					err_ = convAPIError(watcher.WrapErr(err_))
+					if !received && cm.retry.retryable(attempt, err_) {
+						retryErr = err_
+						break
+					}
+					sw.Send(nil, err_)
+					return
+				}
+				received = true
+				if resp.ModelVersion != "" {
+					modelVersion = resp.ModelVersion
+				}
+				message, err_ := convStreamResponse(resp)
+				if err_ != nil {
+					sw.Send(nil, err_)
+					return
+				}
+				if err_ = aligner.align(message); err_ != nil {
+					sw.Send(nil, err_)
+					return
+				}
+				if closed := send(message, modelVersion); closed {
+					return
+				}
+				watcher.Arm()
 			}
-			message, err_ := convResponse(resp)
-			if err_ != nil {
-				sw.Send(nil, err_)
-				return
+			if retryErr == nil {
+				break
 			}
-			closed := sw.Send(convCallbackOutput(message, cbConf), nil)
-			if closed {
+			if err_ := cm.retry.wait(streamCtx, attempt, retryErr); err_ != nil {
+				sw.Send(nil, err_)
 				return
 			}
+			watcher.Arm()
+		}
+		if message := aligner.flush(); message != nil {
+			send(message, modelVersion)
 		}
 	}()
-	srList := sr.Copy(2)
-	callbacks.OnEndWithStreamOutput(ctx, srList[0])
-	return schema.StreamReaderWithConvert(srList[1], func(t *model.CallbackOutput) (*schema.Message, error) {
+	if !cm.disableCallbacks {
+		srList := sr.Copy(2)
+		callbacks.OnEndWithStreamOutput(ctx, srList[0])
+		sr = srList[1]
+	}
+	return schema.StreamReaderWithConvert(sr, func(t *model.CallbackOutput) (*schema.Message, error) {
 		return t.Message, nil
 	}), nil
 }
@@ -454,18 +679,27 @@ func populateToolChoice(m *genai.GenerateContentConfig, toolChoice *schema.ToolC
 }
 
 func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Option) (string, []*schema.Message, *genai.GenerateContentConfig, *model.Config, error) {
+	if err := cm.mediaLimits.ValidateMessages(input); err != nil {
+		return "", nil, nil, nil, err
+	}
+
 	commonOptions := model.GetCommonOptions(&model.Options{
 		Temperature: cm.temperature,
 		MaxTokens:   cm.maxTokens,
 		TopP:        cm.topP,
+		Stop:        cm.stop,
 		Tools:       nil,
 		ToolChoice:  cm.toolChoice,
 	}, opts...)
 	geminiOptions := model.GetImplSpecificOptions(&options{
 		TopK:               cm.topK,
+		Seed:               cm.seed,
+		PresencePenalty:    cm.presencePenalty,
+		FrequencyPenalty:   cm.frequencyPenalty,
 		ResponseJSONSchema: cm.responseJSONSchema,
 		ResponseModalities: cm.responseModalities,
 		ImageConfig:        cm.imageConfig,
+		SpeechConfig:       cm.speechConfig,
 	}, opts...)
 	conf := &model.Config{}
 
@@ -529,7 +763,16 @@ func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Opti
 		})
 	}
 
+	for _, t := range geminiOptions.Tools {
+		if t != nil {
+			m.Tools = append(m.Tools, t)
+		}
+	}
+
 	m.MediaResolution = cm.mediaResolution
+	if geminiOptions.MediaResolution != "" {
+		m.MediaResolution = geminiOptions.MediaResolution
+	}
 
 	if commonOptions.MaxTokens != nil {
 		conf.MaxTokens = *commonOptions.MaxTokens
@@ -547,11 +790,34 @@ func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Opti
 		topK := float32(*geminiOptions.TopK)
 		m.TopK = &topK
 	}
+	if len(commonOptions.Stop) > 0 {
+		conf.Stop = commonOptions.Stop
+		m.StopSequences = commonOptions.Stop
+	}
+	m.Seed = geminiOptions.Seed
+	m.PresencePenalty = geminiOptions.PresencePenalty
+	m.FrequencyPenalty = geminiOptions.FrequencyPenalty
 
 	err := populateToolChoice(m, commonOptions.ToolChoice, commonOptions.AllowedToolNames)
 	if err != nil {
 		return "", nil, nil, nil, err
 	}
+	if cm.retrievalConfig != nil {
+		if m.ToolConfig == nil {
+			m.ToolConfig = &genai.ToolConfig{}
+		}
+		m.ToolConfig.RetrievalConfig = cm.retrievalConfig
+	}
+	if geminiOptions.ToolConfig != nil {
+		toolConfig := *geminiOptions.ToolConfig
+		if toolConfig.FunctionCallingConfig == nil && m.ToolConfig != nil {
+			toolConfig.FunctionCallingConfig = m.ToolConfig.FunctionCallingConfig
+		}
+		if toolConfig.RetrievalConfig == nil && m.ToolConfig != nil {
+			toolConfig.RetrievalConfig = m.ToolConfig.RetrievalConfig
+		}
+		m.ToolConfig = &toolConfig
+	}
 
 	if geminiOptions.ResponseJSONSchema != nil {
 		m.ResponseMIMEType = "application/json"
@@ -565,16 +831,11 @@ func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Opti
 		}
 	}
 
-	nInput := make([]*schema.Message, len(input))
-	copy(nInput, input)
-	if len(input) > 1 && input[0].Role == schema.System {
-		var err error
-		m.SystemInstruction, err = convSchemaMessage(input[0])
-		if err != nil {
-			return "", nil, nil, nil, fmt.Errorf("failed to convert system instruction: %w", err)
-		}
-		nInput = input[1:]
+	systemInstruction, nInput, err := cm.splitSystemInstruction(input)
+	if err != nil {
+		return "", nil, nil, nil, err
 	}
+	m.SystemInstruction = systemInstruction
 
 	m.ThinkingConfig = cm.thinkingConfig
 	if geminiOptions.ThinkingConfig != nil {
@@ -585,6 +846,12 @@ func (cm *ChatModel) genInputAndConf(input []*schema.Message, opts ...model.Opti
 		m.ImageConfig = geminiOptions.ImageConfig
 	}
 
+	if geminiOptions.SpeechConfig != nil {
+		m.SpeechConfig = geminiOptions.SpeechConfig
+	}
+
+	m.Labels = mergeLabels(cm.labels, geminiOptions.Labels)
+
 	if len(geminiOptions.CachedContentName) > 0 {
 		m.CachedContent = geminiOptions.CachedContentName
 		// remove system instruction and tools when using cached content
@@ -619,7 +886,7 @@ func (cm *ChatModel) toGeminiTools(tools []*schema.ToolInfo) ([]*genai.FunctionD
 // convToolMessageToPart converts a tool response message into a Gemini part.
 func convToolMessageToPart(toolName string, msg *schema.Message) (*genai.Part, error) {
 	if len(msg.UserInputMultiContent) > 0 {
-		return convMultiModalToolMessageToPart(toolName, msg.UserInputMultiContent)
+		return convMultiModalToolMessageToPart(toolName, msg.Content, msg.UserInputMultiContent)
 	}
 	response := make(map[string]any)
 	err := sonic.UnmarshalString(msg.Content, &response)
@@ -630,7 +897,10 @@ func convToolMessageToPart(toolName string, msg *schema.Message) (*genai.Part, e
 	return genai.NewPartFromFunctionResponse(toolName, response), nil
 }
 
-func convMultiModalToolMessageToPart(toolName string, inputs []schema.MessageInputPart) (*genai.Part, error) {
+// convMultiModalToolMessageToPart converts a multi-modal tool result into a function response with parts,
+// e.g. a tool returning a screenshot. The text part, or the message content if there is no text part,
+// is used as the structured response.
+func convMultiModalToolMessageToPart(toolName string, content string, inputs []schema.MessageInputPart) (*genai.Part, error) {
 	var text *string
 	var parts []*genai.FunctionResponsePart
 	for _, input := range inputs {
@@ -686,6 +956,9 @@ func convMultiModalToolMessageToPart(toolName string, inputs []schema.MessageInp
 			return nil, fmt.Errorf("unknown part type: %s", input.Type)
 		}
 	}
+	if text == nil && content != "" {
+		text = &content
+	}
 	response := make(map[string]any)
 	if text != nil {
 		err := sonic.UnmarshalString(*text, &response)
@@ -876,6 +1149,7 @@ func convInputMedia(contents []schema.MessageInputPart) ([]*genai.Part, error) {
 			if err != nil {
 				return nil, err
 			}
+			p.MediaResolution = partMediaResolution(content.Image.Detail)
 			result = append(result, p)
 
 		case schema.ChatMessagePartTypeAudioURL:
@@ -898,11 +1172,11 @@ func convInputMedia(contents []schema.MessageInputPart) ([]*genai.Part, error) {
 				return nil, err
 			}
 
-			if content.Video.Extra != nil {
-				if videoMetaData := GetInputVideoMetaData(content.Video); videoMetaData != nil {
-					p.VideoMetadata = videoMetaData
-				}
+			videoMetaData, err := videoMetaDataOf(content.Video.Extra)
+			if err != nil {
+				return nil, err
 			}
+			p.VideoMetadata = videoMetaData
 
 			result = append(result, p)
 
@@ -980,7 +1254,7 @@ func convOutputMedia(contents []schema.MessageOutputPart) ([]*genai.Part, error)
 
 func toGenAIDataPart(b64 *string, url *string, mimeType string, partType schema.ChatMessagePartType) (*genai.Part, error) {
 	if b64 != nil {
-		data, err := decodeBase64Data(*b64)
+		data, err := multimodal.DecodeBase64(*b64)
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode [%s] base64 data: %w", partType, err)
 		}
@@ -993,7 +1267,7 @@ func toGenAIDataPart(b64 *string, url *string, mimeType string, partType schema.
 
 func toFunctionResponsePart(b64 *string, url *string, mimeType string, partType schema.ChatMessagePartType, displayName string) (*genai.FunctionResponsePart, error) {
 	if b64 != nil {
-		data, err := decodeBase64Data(*b64)
+		data, err := multimodal.DecodeBase64(*b64)
 		if err != nil {
 			return nil, fmt.Errorf("failed to decode [%s] base64 data: %w", partType, err)
 		}
@@ -1038,22 +1312,25 @@ func convMedia(contents []schema.ChatMessagePart) ([]*genai.Part, error) {
 			result = append(result, genai.NewPartFromText(content.Text))
 		case schema.ChatMessagePartTypeImageURL:
 			if content.ImageURL != nil {
+				var p *genai.Part
 				if content.ImageURL.URI != "" {
-					result = append(result, genai.NewPartFromURI(content.ImageURL.URI, content.ImageURL.MIMEType))
+					p = genai.NewPartFromURI(content.ImageURL.URI, content.ImageURL.MIMEType)
 				} else {
-					data, err := decodeBase64Data(content.ImageURL.URL)
+					data, err := multimodal.DecodeBase64(content.ImageURL.URL)
 					if err != nil {
 						return nil, fmt.Errorf("failed to decode base64 data URL: %w", err)
 					}
-					result = append(result, genai.NewPartFromBytes(data, content.ImageURL.MIMEType))
+					p = genai.NewPartFromBytes(data, content.ImageURL.MIMEType)
 				}
+				p.MediaResolution = partMediaResolution(content.ImageURL.Detail)
+				result = append(result, p)
 			}
 		case schema.ChatMessagePartTypeAudioURL:
 			if content.AudioURL != nil {
 				if content.AudioURL.URI != "" {
 					result = append(result, genai.NewPartFromURI(content.AudioURL.URI, content.AudioURL.MIMEType))
 				} else {
-					data, err := decodeBase64Data(content.AudioURL.URL)
+					data, err := multimodal.DecodeBase64(content.AudioURL.URL)
 					if err != nil {
 						return nil, fmt.Errorf("failed to decode base64 data URL: %w", err)
 					}
@@ -1062,18 +1339,19 @@ func convMedia(contents []schema.ChatMessagePart) ([]*genai.Part, error) {
 			}
 		case schema.ChatMessagePartTypeVideoURL:
 			if content.VideoURL != nil {
-				if content.VideoURL.Extra != nil {
-					videoMetaData := GetVideoMetaData(content.VideoURL)
-					if videoMetaData != nil {
-						result = append(result, &genai.Part{
-							VideoMetadata: videoMetaData,
-						})
-					}
+				videoMetaData, err := videoMetaDataOf(content.VideoURL.Extra)
+				if err != nil {
+					return nil, err
+				}
+				if videoMetaData != nil {
+					result = append(result, &genai.Part{
+						VideoMetadata: videoMetaData,
+					})
 				}
 				if content.VideoURL.URI != "" {
 					result = append(result, genai.NewPartFromURI(content.VideoURL.URI, content.VideoURL.MIMEType))
 				} else {
-					data, err := decodeBase64Data(content.VideoURL.URL)
+					data, err := multimodal.DecodeBase64(content.VideoURL.URL)
 					if err != nil {
 						return nil, fmt.Errorf("failed to decode base64 data URL: %w", err)
 					}
@@ -1085,7 +1363,7 @@ func convMedia(contents []schema.ChatMessagePart) ([]*genai.Part, error) {
 				if content.FileURL.URI != "" {
 					result = append(result, genai.NewPartFromURI(content.FileURL.URI, content.FileURL.MIMEType))
 				} else {
-					data, err := decodeBase64Data(content.FileURL.URL)
+					data, err := multimodal.DecodeBase64(content.FileURL.URL)
 					if err != nil {
 						return nil, fmt.Errorf("failed to decode base64 data URL: %w", err)
 					}
@@ -1097,63 +1375,55 @@ func convMedia(contents []schema.ChatMessagePart) ([]*genai.Part, error) {
 	return result, nil
 }
 
-// decodeBase64Data decodes a base64 data URL string into raw bytes.
-// It correctly handles the "data:[<mediatype>];base64," prefix.
-func decodeBase64Data(dataURL string) ([]byte, error) {
-	// Check if a web URL is passed by mistake.
-	if strings.HasPrefix(dataURL, "http") {
-		return nil, fmt.Errorf("invalid input: expected base64 data or data URL, but got a web URL starting with 'http'. Please fetch the content from the URL first")
-	}
-	// Find the comma that separates the prefix from the data
-	commaIndex := strings.Index(dataURL, ",")
-	if commaIndex == -1 {
-		// If no comma, assume it's a raw base64 string and try to decode it directly.
-		decoded, err := base64.StdEncoding.DecodeString(dataURL)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode raw base64 data: %w", err)
+func convResponse(resp *genai.GenerateContentResponse) (*schema.Message, error) {
+	if len(resp.Candidates) == 0 {
+		if resp.PromptFeedback != nil && resp.PromptFeedback.BlockReason != "" {
+			return nil, &PromptBlockedError{Feedback: resp.PromptFeedback}
 		}
-		return decoded, nil
+		return nil, fmt.Errorf("gemini result is empty")
 	}
 
-	// Extract the base64 part of the data URL
-	base64Data := dataURL[commaIndex+1:]
-
-	// Decode the base64 string
-	decoded, err := base64.StdEncoding.DecodeString(base64Data)
+	message, err := convCandidate(resp.Candidates[0])
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode base64 data from data URL: %w", err)
+		return nil, fmt.Errorf("convert candidate fail: %w", err)
 	}
 
-	return decoded, nil
+	setPromptFeedback(message, resp.PromptFeedback)
+	setUsage(message, resp.UsageMetadata)
+	return message, nil
 }
 
-func convResponse(resp *genai.GenerateContentResponse) (*schema.Message, error) {
-	if len(resp.Candidates) == 0 {
-		return nil, fmt.Errorf("gemini result is empty")
+// convStreamResponse converts a stream chunk, the final chunk may carry the usage metadata without candidates.
+func convStreamResponse(resp *genai.GenerateContentResponse) (*schema.Message, error) {
+	if len(resp.Candidates) == 0 && resp.UsageMetadata != nil &&
+		(resp.PromptFeedback == nil || resp.PromptFeedback.BlockReason == "") {
+		message := &schema.Message{Role: schema.Assistant}
+		setPromptFeedback(message, resp.PromptFeedback)
+		setUsage(message, resp.UsageMetadata)
+		return message, nil
 	}
+	return convResponse(resp)
+}
 
-	message, err := convCandidate(resp.Candidates[0])
-	if err != nil {
-		return nil, fmt.Errorf("convert candidate fail: %w", err)
+func setUsage(message *schema.Message, usage *genai.GenerateContentResponseUsageMetadata) {
+	if usage == nil {
+		return
 	}
-
-	if resp.UsageMetadata != nil {
-		if message.ResponseMeta == nil {
-			message.ResponseMeta = &schema.ResponseMeta{}
-		}
-		message.ResponseMeta.Usage = &schema.TokenUsage{
-			PromptTokens: int(resp.UsageMetadata.PromptTokenCount),
-			PromptTokenDetails: schema.PromptTokenDetails{
-				CachedTokens: int(resp.UsageMetadata.CachedContentTokenCount),
-			},
-			CompletionTokens: int(resp.UsageMetadata.CandidatesTokenCount),
-			TotalTokens:      int(resp.UsageMetadata.TotalTokenCount),
-			CompletionTokensDetails: schema.CompletionTokensDetails{
-				ReasoningTokens: int(resp.UsageMetadata.ThoughtsTokenCount),
-			},
-		}
+	if message.ResponseMeta == nil {
+		message.ResponseMeta = &schema.ResponseMeta{}
 	}
-	return message, nil
+	message.ResponseMeta.Usage = &schema.TokenUsage{
+		PromptTokens: int(usage.PromptTokenCount),
+		PromptTokenDetails: schema.PromptTokenDetails{
+			CachedTokens: int(usage.CachedContentTokenCount),
+		},
+		CompletionTokens: int(usage.CandidatesTokenCount),
+		TotalTokens:      int(usage.TotalTokenCount),
+		CompletionTokensDetails: schema.CompletionTokensDetails{
+			ReasoningTokens: int(usage.ThoughtsTokenCount),
+		},
+	}
+	setUsageMetadata(message, usage)
 }
 
 func convCandidate(candidate *genai.Candidate) (*schema.Message, error) {
@@ -1165,6 +1435,7 @@ func convCandidate(candidate *genai.Candidate) (*schema.Message, error) {
 
 	if candidate.GroundingMetadata != nil {
 		setGroundMetadata(result, candidate.GroundingMetadata)
+		setCitations(result, candidate.GroundingMetadata)
 	}
 
 	if candidate.Content != nil {
@@ -1288,6 +1559,17 @@ func toMultiOutPart(part *genai.Part) (schema.MessageOutputPart, error) {
 					MIMEType:   mimeType,
 				},
 			}
+		case strings.HasPrefix(mimeType, "audio/"):
+			res.Type = schema.ChatMessagePartTypeAudioURL
+			res.Audio = &schema.MessageOutputAudio{
+				MessagePartCommon: schema.MessagePartCommon{
+					Base64Data: &encodedStr,
+					MIMEType:   mimeType,
+				},
+			}
+			if meta := parseAudioMetaData(mimeType); meta != nil {
+				res.Audio.Extra = map[string]any{audioMetaDataKey: meta}
+			}
 		default:
 			return schema.MessageOutputPart{}, fmt.Errorf("unsupported media type from Gemini model response: MIMEType=%s", mimeType)
 		}
@@ -1320,10 +1602,12 @@ func convFC(part *genai.Part) (*schema.ToolCall, error) {
 	return toolCall, nil
 }
 
-func convCallbackOutput(message *schema.Message, conf *model.Config) *model.CallbackOutput {
+func convCallbackOutput(message *schema.Message, conf *model.Config, genaiConf *genai.GenerateContentConfig,
+	modelVersion string) *model.CallbackOutput {
 	callbackOutput := &model.CallbackOutput{
 		Message: message,
 		Config:  conf,
+		Extra:   callbackOutputExtra(genaiConf, modelVersion),
 	}
 	if message.ResponseMeta != nil && message.ResponseMeta.Usage != nil {
 		callbackOutput.TokenUsage = &model.TokenUsage{
@@ -1342,7 +1626,7 @@ func convCallbackOutput(message *schema.Message, conf *model.Config) *model.Call
 }
 
 func (cm *ChatModel) IsCallbacksEnabled() bool {
-	return true
+	return !cm.disableCallbacks
 }
 
 type GeminiResponseModality string
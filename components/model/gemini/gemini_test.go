@@ -25,6 +25,7 @@ import (
 
 	"github.com/bytedance/mockey"
 	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/eino-contrib/jsonschema"
 	"github.com/google/uuid"
@@ -71,6 +72,43 @@ func TestGemini(t *testing.T) {
 		assert.Equal(t, 100, resp.ResponseMeta.Usage.TotalTokens)
 		assert.Equal(t, 50, resp.ResponseMeta.Usage.CompletionTokensDetails.ReasoningTokens)
 	})
+	mockey.PatchConvey("disable callbacks", t, func() {
+		defer mockey.Mock(genai.Models.GenerateContent).Return(&genai.GenerateContentResponse{
+			Candidates: []*genai.Candidate{
+				{Content: &genai.Content{Role: "model", Parts: []*genai.Part{genai.NewPartFromText("Hello")}}},
+			},
+		}, nil).Build().UnPatch()
+
+		var started, ended int
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackInput) context.Context {
+				started++
+				return ctx
+			}).
+			OnEndFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackOutput) context.Context {
+				ended++
+				return ctx
+			}).Build()
+		cbCtx := callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, handler)
+
+		enableCallbacks := false
+		cm, err := NewChatModel(ctx, &Config{
+			Client:          &genai.Client{Models: &genai.Models{}},
+			EnableCallbacks: &enableCallbacks,
+		})
+		assert.NoError(t, err)
+		resp, err := cm.Generate(cbCtx, []*schema.Message{schema.UserMessage("Hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, "Hello", resp.Content)
+		assert.Equal(t, 0, started)
+		assert.Equal(t, 0, ended)
+		assert.False(t, cm.IsCallbacksEnabled())
+
+		_, err = model.Generate(cbCtx, []*schema.Message{schema.UserMessage("Hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, started)
+		assert.Equal(t, 1, ended)
+	})
 	mockey.PatchConvey("stream", t, func() {
 		respList := []*genai.GenerateContentResponse{
 			{Candidates: []*genai.Candidate{{
@@ -312,6 +350,22 @@ func Test_toMultiOutPart(t *testing.T) {
 		assert.Equal(t, encoded, *part.Image.Base64Data)
 	})
 
+	t.Run("audio part", func(t *testing.T) {
+		data := []byte("fake-audio-data")
+		encoded := base64.StdEncoding.EncodeToString(data)
+		part, err := toMultiOutPart(&genai.Part{
+			InlineData: &genai.Blob{
+				MIMEType: "audio/L16;codec=pcm;rate=24000",
+				Data:     data,
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, schema.ChatMessagePartTypeAudioURL, part.Type)
+		assert.NotNil(t, part.Audio)
+		assert.Equal(t, encoded, *part.Audio.Base64Data)
+		assert.Equal(t, &AudioMetaData{Codec: "pcm", SampleRate: 24000}, GetOutputAudioMetaData(part.Audio))
+	})
+
 	t.Run("unsupported type", func(t *testing.T) {
 		part, err := toMultiOutPart(&genai.Part{
 			InlineData: &genai.Blob{
@@ -517,6 +571,82 @@ func TestChatModel_convMedia(t *testing.T) {
 	})
 }
 
+func TestConvMultiModalToolMessage(t *testing.T) {
+	imageB64 := base64.StdEncoding.EncodeToString([]byte("png-bytes"))
+	imageURL := "gs://bucket/screenshot.png"
+
+	t.Run("image parts with text", func(t *testing.T) {
+		msg := &schema.Message{
+			Role: schema.Tool,
+			UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: `{"status":"ok"}`},
+				SetMultiModalToolResultDisplayName(schema.MessageInputPart{
+					Type: schema.ChatMessagePartTypeImageURL,
+					Image: &schema.MessageInputImage{
+						MessagePartCommon: schema.MessagePartCommon{Base64Data: &imageB64, MIMEType: "image/png"},
+					},
+				}, "inline.png"),
+				{
+					Type: schema.ChatMessagePartTypeImageURL,
+					Image: &schema.MessageInputImage{
+						MessagePartCommon: schema.MessagePartCommon{URL: &imageURL, MIMEType: "image/png"},
+					},
+				},
+			},
+		}
+		part, err := convToolMessageToPart("screenshot", msg)
+		assert.NoError(t, err)
+		assert.Equal(t, "screenshot", part.FunctionResponse.Name)
+		assert.Equal(t, "ok", part.FunctionResponse.Response["status"])
+		assert.Len(t, part.FunctionResponse.Parts, 2)
+		assert.Equal(t, []byte("png-bytes"), part.FunctionResponse.Parts[0].InlineData.Data)
+		assert.Equal(t, "inline.png", part.FunctionResponse.Parts[0].InlineData.DisplayName)
+		assert.Equal(t, imageURL, part.FunctionResponse.Parts[1].FileData.FileURI)
+	})
+
+	t.Run("content used as response without text part", func(t *testing.T) {
+		msg := &schema.Message{
+			Role:    schema.Tool,
+			Content: "captured",
+			UserInputMultiContent: []schema.MessageInputPart{
+				{
+					Type: schema.ChatMessagePartTypeImageURL,
+					Image: &schema.MessageInputImage{
+						MessagePartCommon: schema.MessagePartCommon{Base64Data: &imageB64, MIMEType: "image/png"},
+					},
+				},
+			},
+		}
+		part, err := convToolMessageToPart("screenshot", msg)
+		assert.NoError(t, err)
+		assert.Equal(t, "captured", part.FunctionResponse.Response["output"])
+		assert.Len(t, part.FunctionResponse.Parts, 1)
+	})
+
+	t.Run("multiple text parts", func(t *testing.T) {
+		msg := &schema.Message{
+			Role: schema.Tool,
+			UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "a"},
+				{Type: schema.ChatMessagePartTypeText, Text: "b"},
+			},
+		}
+		_, err := convToolMessageToPart("screenshot", msg)
+		assert.Error(t, err)
+	})
+
+	t.Run("empty image", func(t *testing.T) {
+		msg := &schema.Message{
+			Role: schema.Tool,
+			UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{}},
+			},
+		}
+		_, err := convToolMessageToPart("screenshot", msg)
+		assert.Error(t, err)
+	})
+}
+
 func TestThoughtSignatureRoundTrip(t *testing.T) {
 	t.Run("convToolMessageToPart", func(t *testing.T) {
 		part, err := convToolMessageToPart("tool_1", schema.ToolMessage(`{"result":"ok"}`, ""))
@@ -1248,6 +1378,113 @@ func TestPopulateToolChoice(t *testing.T) {
 	}
 }
 
+func TestGenInputAndConfSampling(t *testing.T) {
+	seed, presence, frequency := int32(42), float32(0.5), float32(-0.5)
+	cm := &ChatModel{model: "test model", stop: []string{"END"}, seed: &seed, presencePenalty: &presence, frequencyPenalty: &frequency}
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	t.Run("config", func(t *testing.T) {
+		_, _, conf, cbConf, err := cm.genInputAndConf(input)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"END"}, conf.StopSequences)
+		assert.Equal(t, []string{"END"}, cbConf.Stop)
+		assert.Equal(t, int32(42), *conf.Seed)
+		assert.Equal(t, float32(0.5), *conf.PresencePenalty)
+		assert.Equal(t, float32(-0.5), *conf.FrequencyPenalty)
+	})
+
+	t.Run("options override config", func(t *testing.T) {
+		_, _, conf, cbConf, err := cm.genInputAndConf(input,
+			model.WithStop([]string{"STOP", "\n\n"}),
+			WithSeed(7),
+			WithPresencePenalty(1),
+			WithFrequencyPenalty(1.5),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"STOP", "\n\n"}, conf.StopSequences)
+		assert.Equal(t, []string{"STOP", "\n\n"}, cbConf.Stop)
+		assert.Equal(t, int32(7), *conf.Seed)
+		assert.Equal(t, float32(1), *conf.PresencePenalty)
+		assert.Equal(t, float32(1.5), *conf.FrequencyPenalty)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		_, _, conf, _, err := (&ChatModel{model: "test model"}).genInputAndConf(input)
+		assert.NoError(t, err)
+		assert.Nil(t, conf.StopSequences)
+		assert.Nil(t, conf.Seed)
+		assert.Nil(t, conf.PresencePenalty)
+		assert.Nil(t, conf.FrequencyPenalty)
+	})
+}
+
+func TestGenInputAndConfPerCallTools(t *testing.T) {
+	cm := &ChatModel{model: "test model"}
+	assert.NoError(t, cm.BindTools([]*schema.ToolInfo{{Name: "bound_tool"}}))
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	t.Run("bound tools", func(t *testing.T) {
+		_, _, conf, _, err := cm.genInputAndConf(input)
+		assert.NoError(t, err)
+		assert.Len(t, conf.Tools, 1)
+		assert.Equal(t, "bound_tool", conf.Tools[0].FunctionDeclarations[0].Name)
+		assert.Equal(t, genai.FunctionCallingConfigModeAuto, conf.ToolConfig.FunctionCallingConfig.Mode)
+	})
+
+	t.Run("per-call tools and tool choice", func(t *testing.T) {
+		_, _, conf, _, err := cm.genInputAndConf(input,
+			model.WithTools([]*schema.ToolInfo{{Name: "call_tool"}}),
+			model.WithToolChoice(schema.ToolChoiceForced),
+			WithGeminiTools(&genai.Tool{GoogleSearch: &genai.GoogleSearch{}}, nil),
+		)
+		assert.NoError(t, err)
+		assert.Len(t, conf.Tools, 2)
+		assert.Equal(t, "call_tool", conf.Tools[0].FunctionDeclarations[0].Name)
+		assert.NotNil(t, conf.Tools[1].GoogleSearch)
+		assert.Equal(t, genai.FunctionCallingConfigModeAny, conf.ToolConfig.FunctionCallingConfig.Mode)
+		assert.Equal(t, []*schema.ToolInfo{{Name: "bound_tool"}}, cm.origTools)
+	})
+
+	t.Run("per-call tool config", func(t *testing.T) {
+		retrieval := &genai.RetrievalConfig{LanguageCode: "en"}
+		_, _, conf, _, err := cm.genInputAndConf(input, WithToolConfig(&genai.ToolConfig{RetrievalConfig: retrieval}))
+		assert.NoError(t, err)
+		assert.Equal(t, retrieval, conf.ToolConfig.RetrievalConfig)
+		assert.Equal(t, genai.FunctionCallingConfigModeAuto, conf.ToolConfig.FunctionCallingConfig.Mode)
+
+		fcc := &genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeValidated}
+		_, _, conf, _, err = cm.genInputAndConf(input, WithToolConfig(&genai.ToolConfig{FunctionCallingConfig: fcc}))
+		assert.NoError(t, err)
+		assert.Equal(t, fcc, conf.ToolConfig.FunctionCallingConfig)
+	})
+
+	t.Run("configured retrieval config", func(t *testing.T) {
+		lat, lng := 34.05, -118.25
+		retrieval := &genai.RetrievalConfig{LatLng: &genai.LatLng{Latitude: &lat, Longitude: &lng}}
+		mapsModel := &ChatModel{
+			model:            "test model",
+			enableGoogleMaps: &genai.GoogleMaps{EnableWidget: genai.Ptr(true)},
+			retrievalConfig:  retrieval,
+		}
+		_, _, conf, _, err := mapsModel.genInputAndConf(input)
+		assert.NoError(t, err)
+		assert.Len(t, conf.Tools, 1)
+		assert.True(t, *conf.Tools[0].GoogleMaps.EnableWidget)
+		assert.Equal(t, retrieval, conf.ToolConfig.RetrievalConfig)
+
+		fcc := &genai.FunctionCallingConfig{Mode: genai.FunctionCallingConfigModeNone}
+		_, _, conf, _, err = mapsModel.genInputAndConf(input, WithToolConfig(&genai.ToolConfig{FunctionCallingConfig: fcc}))
+		assert.NoError(t, err)
+		assert.Equal(t, fcc, conf.ToolConfig.FunctionCallingConfig)
+		assert.Equal(t, retrieval, conf.ToolConfig.RetrievalConfig)
+
+		override := &genai.RetrievalConfig{LanguageCode: "en"}
+		_, _, conf, _, err = mapsModel.genInputAndConf(input, WithToolConfig(&genai.ToolConfig{RetrievalConfig: override}))
+		assert.NoError(t, err)
+		assert.Equal(t, override, conf.ToolConfig.RetrievalConfig)
+	})
+}
+
 // isValidUUID checks if a string is a valid UUID format
 func isValidUUID(u string) bool {
 	_, err := uuid.Parse(u)
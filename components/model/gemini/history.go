@@ -0,0 +1,198 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino-ext/libs/tokenestimate"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultKeepRecentMessages = 4
+
+	defaultSummaryInstruction = "Summarize the conversation above so that it can be continued from the summary alone. " +
+		"Keep the facts, decisions, tool calls and their results, open questions and preferences of the user, and omit small talk. " +
+		"Reply with the summary only."
+
+	summaryPrefix = "Summary of the earlier conversation:\n"
+)
+
+// HistoryCompressor compresses the chat history before it is converted to genai contents,
+// e.g. by summarizing the older turns. See NewSummaryCompressor.
+type HistoryCompressor interface {
+	// Compress returns the compressed history to send instead of history.
+	Compress(ctx context.Context, history []*schema.Message) ([]*schema.Message, error)
+}
+
+// HistoryCompressionConfig configures the compression of the chat history of Generate and Stream.
+type HistoryCompressionConfig struct {
+	// Compressor compresses the history.
+	// Required.
+	Compressor HistoryCompressor
+
+	// MaxInputTokens is the threshold of the estimated input tokens above which the history is compressed.
+	// Required.
+	MaxInputTokens int
+
+	// TokenEstimator estimates the input tokens of a message,
+	// plug in the tokenizer of the target model for precise estimation.
+	// Optional. Default: tokenestimate.Message
+	TokenEstimator tokenestimate.Estimator
+}
+
+// compressHistory compresses the input if its estimated tokens exceed the threshold of the config.
+func compressHistory(ctx context.Context, conf *HistoryCompressionConfig, input []*schema.Message) ([]*schema.Message, error) {
+	if conf == nil || conf.Compressor == nil || conf.MaxInputTokens <= 0 {
+		return input, nil
+	}
+	tokens := tokenestimate.Messages(input, conf.TokenEstimator)
+	if tokens <= conf.MaxInputTokens {
+		return input, nil
+	}
+
+	// clear the run info of this model, so that the models of the compressor report their callbacks
+	// under their own run info instead of as this model
+	compressed, err := conf.Compressor.Compress(callbacks.ReuseHandlers(ctx, nil), input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress history of %d estimated tokens: %w", tokens, err)
+	}
+	return compressed, nil
+}
+
+// SummaryCompressorConfig configures the HistoryCompressor returned by NewSummaryCompressor.
+type SummaryCompressorConfig struct {
+	// Model summarizes the older turns, e.g. the same model or a cheaper one.
+	// Required.
+	Model model.BaseChatModel
+
+	// KeepRecent is the number of the most recent messages kept as is. The kept messages are extended
+	// to the assistant message calling the tools, so that a tool call is never separated from its results.
+	// Optional. Default: 4
+	KeepRecent int
+
+	// Instruction asks the model for the summary, appended after the transcript of the older turns.
+	// Optional. Default: an instruction keeping the facts, decisions and tool results
+	Instruction string
+}
+
+// NewSummaryCompressor returns a HistoryCompressor summarizing the older turns of the history into a single user message.
+// The leading system messages and the most recent messages are kept as is. The older turns, tool calls included,
+// are sent to the model as a plain text transcript, so the model needs no tool declarations.
+func NewSummaryCompressor(config *SummaryCompressorConfig) (HistoryCompressor, error) {
+	if config == nil || config.Model == nil {
+		return nil, errors.New("summary model is required")
+	}
+	c := &summaryCompressor{
+		model:       config.Model,
+		keepRecent:  config.KeepRecent,
+		instruction: config.Instruction,
+	}
+	if c.keepRecent <= 0 {
+		c.keepRecent = defaultKeepRecentMessages
+	}
+	if c.instruction == "" {
+		c.instruction = defaultSummaryInstruction
+	}
+	return c, nil
+}
+
+type summaryCompressor struct {
+	model       model.BaseChatModel
+	keepRecent  int
+	instruction string
+}
+
+func (c *summaryCompressor) Compress(ctx context.Context, history []*schema.Message) ([]*schema.Message, error) {
+	start := 0
+	for start < len(history) && history[start] != nil && history[start].Role == schema.System {
+		start++
+	}
+
+	split := len(history) - c.keepRecent
+	// keep the tool results with the assistant message calling the tools
+	for split > start && history[split] != nil && history[split].Role == schema.Tool {
+		split--
+	}
+	if split <= start {
+		return history, nil
+	}
+
+	summary, err := c.model.Generate(ctx, []*schema.Message{
+		schema.UserMessage(transcript(history[start:split]) + "\n" + c.instruction),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize %d messages: %w", split-start, err)
+	}
+
+	compressed := make([]*schema.Message, 0, start+1+len(history)-split)
+	compressed = append(compressed, history[:start]...)
+	compressed = append(compressed, schema.UserMessage(summaryPrefix+summary.Content))
+	return append(compressed, history[split:]...), nil
+}
+
+// transcript renders the messages as plain text, one turn per paragraph.
+func transcript(msgs []*schema.Message) string {
+	var sb strings.Builder
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		switch msg.Role {
+		case schema.Tool:
+			fmt.Fprintf(&sb, "tool %s returned: %s\n\n", msg.ToolName, messageText(msg))
+		default:
+			if text := messageText(msg); text != "" {
+				fmt.Fprintf(&sb, "%s: %s\n\n", msg.Role, text)
+			}
+			for _, tc := range msg.ToolCalls {
+				fmt.Fprintf(&sb, "%s called tool %s with %s\n\n", msg.Role, tc.Function.Name, tc.Function.Arguments)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// messageText returns the content of the message, or its text parts if the content is empty.
+func messageText(msg *schema.Message) string {
+	if msg.Content != "" {
+		return msg.Content
+	}
+	var texts []string
+	for _, part := range msg.UserInputMultiContent {
+		if part.Type == schema.ChatMessagePartTypeText {
+			texts = append(texts, part.Text)
+		}
+	}
+	for _, part := range msg.AssistantGenMultiContent {
+		if part.Type == schema.ChatMessagePartTypeText {
+			texts = append(texts, part.Text)
+		}
+	}
+	for _, part := range msg.MultiContent {
+		if part.Type == schema.ChatMessagePartTypeText {
+			texts = append(texts, part.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+type fakeSummaryModel struct {
+	input []*schema.Message
+	err   error
+}
+
+func (m *fakeSummaryModel) Generate(ctx context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	ctx = callbacks.EnsureRunInfo(ctx, "FakeSummary", components.ComponentOfChatModel)
+	callbacks.OnStart(ctx, &model.CallbackInput{Messages: input})
+	m.input = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	return schema.AssistantMessage("the user asked for the weather of two cities", nil), nil
+}
+
+func (m *fakeSummaryModel) Stream(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestHistoryCompression(t *testing.T) {
+	ctx := context.Background()
+
+	toolCall := func(id, city string) *schema.Message {
+		return schema.AssistantMessage("", []schema.ToolCall{{
+			ID:       id,
+			Function: schema.FunctionCall{Name: "get_weather", Arguments: `{"city":"` + city + `"}`},
+		}})
+	}
+	history := []*schema.Message{
+		schema.SystemMessage("You are a helpful assistant."),
+		schema.UserMessage("What's the weather in Beijing?"),
+		toolCall("call_1", "Beijing"),
+		schema.ToolMessage(`{"weather":"sunny"}`, "call_1", schema.WithToolName("get_weather")),
+		schema.AssistantMessage("It is sunny in Beijing.", nil),
+		schema.UserMessage("And in Shanghai and Tokyo?"),
+		schema.AssistantMessage("", []schema.ToolCall{
+			{ID: "call_2", Function: schema.FunctionCall{Name: "get_weather", Arguments: `{"city":"Shanghai"}`}},
+			{ID: "call_3", Function: schema.FunctionCall{Name: "get_weather", Arguments: `{"city":"Tokyo"}`}},
+		}),
+		schema.ToolMessage(`{"weather":"rainy"}`, "call_2", schema.WithToolName("get_weather")),
+		schema.ToolMessage(`{"weather":"cloudy"}`, "call_3", schema.WithToolName("get_weather")),
+	}
+
+	t.Run("keep tool results with their call", func(t *testing.T) {
+		summarizer := &fakeSummaryModel{}
+		compressor, err := NewSummaryCompressor(&SummaryCompressorConfig{Model: summarizer, KeepRecent: 2})
+		require.NoError(t, err)
+
+		compressed, err := compressor.Compress(ctx, history)
+		require.NoError(t, err)
+		require.Len(t, compressed, 5)
+		assert.Equal(t, history[0], compressed[0])
+		assert.Equal(t, schema.User, compressed[1].Role)
+		assert.Equal(t, summaryPrefix+"the user asked for the weather of two cities", compressed[1].Content)
+		assert.Equal(t, history[6:], compressed[2:])
+
+		require.Len(t, summarizer.input, 1)
+		prompt := summarizer.input[0].Content
+		assert.Contains(t, prompt, "user: What's the weather in Beijing?")
+		assert.Contains(t, prompt, `assistant called tool get_weather with {"city":"Beijing"}`)
+		assert.Contains(t, prompt, `tool get_weather returned: {"weather":"sunny"}`)
+		assert.Contains(t, prompt, "user: And in Shanghai and Tokyo?")
+		assert.NotContains(t, prompt, "You are a helpful assistant.")
+		assert.NotContains(t, prompt, "Shanghai\"}")
+		assert.Contains(t, prompt, defaultSummaryInstruction)
+	})
+
+	t.Run("nothing to summarize", func(t *testing.T) {
+		summarizer := &fakeSummaryModel{}
+		compressor, err := NewSummaryCompressor(&SummaryCompressorConfig{Model: summarizer, KeepRecent: 3})
+		require.NoError(t, err)
+
+		// the kept messages reach back to the first user message
+		compressed, err := compressor.Compress(ctx, history[:4])
+		require.NoError(t, err)
+		assert.Equal(t, history[:4], compressed)
+		assert.Nil(t, summarizer.input)
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		summarizer := &fakeSummaryModel{}
+		compressor, err := NewSummaryCompressor(&SummaryCompressorConfig{Model: summarizer})
+		require.NoError(t, err)
+		conf := &HistoryCompressionConfig{
+			Compressor:     compressor,
+			MaxInputTokens: len(history),
+			TokenEstimator: func(*schema.Message) int { return 1 },
+		}
+
+		out, err := compressHistory(ctx, conf, history)
+		require.NoError(t, err)
+		assert.Equal(t, history, out)
+
+		conf.MaxInputTokens = len(history) - 1
+		out, err = compressHistory(ctx, conf, history)
+		require.NoError(t, err)
+		assert.Len(t, out, 6)
+		assert.Equal(t, history[5:], out[2:])
+	})
+
+	t.Run("summary error", func(t *testing.T) {
+		compressor, err := NewSummaryCompressor(&SummaryCompressorConfig{Model: &fakeSummaryModel{err: errors.New("quota exceeded")}})
+		require.NoError(t, err)
+
+		_, err = compressHistory(ctx, &HistoryCompressionConfig{Compressor: compressor, MaxInputTokens: 1}, history)
+		assert.ErrorContains(t, err, "quota exceeded")
+	})
+
+	t.Run("transcript of multi content", func(t *testing.T) {
+		msgs := []*schema.Message{
+			{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+				{Type: schema.ChatMessagePartTypeText, Text: "describe the image"},
+				{Type: schema.ChatMessagePartTypeImageURL},
+			}},
+			{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "and this one"},
+			}},
+		}
+		assert.Equal(t, "user: describe the image\n\nuser: and this one\n\n", transcript(msgs))
+	})
+
+	t.Run("missing model", func(t *testing.T) {
+		_, err := NewSummaryCompressor(&SummaryCompressorConfig{})
+		assert.Error(t, err)
+	})
+}
+
+func TestHistoryCompressionCallbacks(t *testing.T) {
+	mockey.PatchConvey("compressed input reported by OnStart", t, func() {
+		defer mockey.Mock(genai.Models.GenerateContent).Return(&genai.GenerateContentResponse{
+			Candidates: []*genai.Candidate{
+				{Content: &genai.Content{Role: "model", Parts: []*genai.Part{genai.NewPartFromText("Hello")}}},
+			},
+		}, nil).Build().UnPatch()
+
+		type start struct {
+			typ      string
+			messages []*schema.Message
+		}
+		var starts []start
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				starts = append(starts, start{typ: info.Type, messages: model.ConvCallbackInput(input).Messages})
+				return ctx
+			}).Build()
+		ctx := callbacks.InitCallbacks(context.Background(), nil, handler)
+
+		compressor, err := NewSummaryCompressor(&SummaryCompressorConfig{Model: &fakeSummaryModel{}, KeepRecent: 1})
+		require.NoError(t, err)
+		cm, err := NewChatModel(ctx, &Config{
+			Client:             &genai.Client{Models: &genai.Models{}},
+			HistoryCompression: &HistoryCompressionConfig{Compressor: compressor, MaxInputTokens: 1},
+		})
+		require.NoError(t, err)
+
+		history := []*schema.Message{
+			schema.UserMessage("What's the weather in Beijing?"),
+			schema.AssistantMessage("It is sunny in Beijing.", nil),
+			schema.UserMessage("And in Shanghai?"),
+		}
+		_, err = cm.Generate(ctx, history)
+		require.NoError(t, err)
+
+		require.Len(t, starts, 2)
+		assert.Equal(t, "FakeSummary", starts[0].typ)
+		assert.Equal(t, "Gemini", starts[1].typ)
+		require.Len(t, starts[1].messages, 2)
+		assert.Equal(t, summaryPrefix+"the user asked for the weather of two cities", starts[1].messages[0].Content)
+		assert.Equal(t, history[2], starts[1].messages[1])
+	})
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+// CallbackExtraKeyLabels is the key of the request labels in the Extra of the callback input and output,
+// the value is a map[string]string.
+const CallbackExtraKeyLabels = "labels"
+
+// mergeLabels returns the labels of the config overridden by the labels of the request.
+func mergeLabels(configLabels, requestLabels map[string]string) map[string]string {
+	if len(requestLabels) == 0 {
+		return configLabels
+	}
+	if len(configLabels) == 0 {
+		return requestLabels
+	}
+	labels := make(map[string]string, len(configLabels)+len(requestLabels))
+	for k, v := range configLabels {
+		labels[k] = v
+	}
+	for k, v := range requestLabels {
+		labels[k] = v
+	}
+	return labels
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	cm, err := NewChatModel(context.Background(), &Config{
+		Model:  "test model",
+		Labels: map[string]string{"team": "search", "feature": "qa"},
+	})
+	require.NoError(t, err)
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	t.Run("config labels", func(t *testing.T) {
+		_, _, conf, _, err := cm.genInputAndConf(input)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"team": "search", "feature": "qa"}, conf.Labels)
+		assert.Equal(t, map[string]any{CallbackExtraKeyLabels: conf.Labels}, callbackExtra(conf))
+
+		output := convCallbackOutput(schema.AssistantMessage("hello", nil), nil, conf, "")
+		assert.Equal(t, conf.Labels, output.Extra[CallbackExtraKeyLabels])
+	})
+
+	t.Run("per-call labels", func(t *testing.T) {
+		_, _, conf, _, err := cm.genInputAndConf(input, WithLabels(map[string]string{"feature": "summary", "user": "u1"}))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"team": "search", "feature": "summary", "user": "u1"}, conf.Labels)
+		// the config labels are not modified
+		assert.Equal(t, map[string]string{"team": "search", "feature": "qa"}, cm.labels)
+	})
+
+	t.Run("no labels", func(t *testing.T) {
+		cm := &ChatModel{model: "test model"}
+		_, _, conf, _, err := cm.genInputAndConf(input)
+		require.NoError(t, err)
+		assert.Nil(t, conf.Labels)
+		assert.Nil(t, callbackExtra(conf))
+	})
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino-ext/libs/multimodal"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestMediaLimits(t *testing.T) {
+	cm := &ChatModel{model: "test model", mediaLimits: &multimodal.Limits{
+		MaxInlineBytes:   4,
+		AllowedMIMETypes: []string{"image/*", "application/pdf"},
+	}}
+	pdfURL, videoURL, imageData := "gs://bucket/a.pdf", "gs://bucket/a.mp4", "aGVsbG8gd29ybGQ="
+
+	_, _, _, _, err := cm.genInputAndConf([]*schema.Message{{
+		Role: schema.User,
+		UserInputMultiContent: []schema.MessageInputPart{
+			{Type: schema.ChatMessagePartTypeFileURL, File: &schema.MessageInputFile{MessagePartCommon: schema.MessagePartCommon{
+				URL: &pdfURL, MIMEType: "application/pdf",
+			}}},
+		},
+	}})
+	assert.NoError(t, err)
+
+	_, _, _, _, err = cm.genInputAndConf([]*schema.Message{
+		schema.UserMessage("hi"),
+		{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+			{Type: schema.ChatMessagePartTypeVideoURL, Video: &schema.MessageInputVideo{MessagePartCommon: schema.MessagePartCommon{
+				URL: &videoURL, MIMEType: "video/mp4",
+			}}},
+		}},
+	})
+	var ve *multimodal.ValidationError
+	assert.True(t, errors.As(err, &ve))
+	assert.Equal(t, "messages[1].user_input_multi_content[0].video", ve.Field)
+
+	_, _, _, _, err = cm.genInputAndConf([]*schema.Message{{
+		Role: schema.User,
+		UserInputMultiContent: []schema.MessageInputPart{
+			{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{MessagePartCommon: schema.MessagePartCommon{
+				Base64Data: &imageData, MIMEType: "image/png",
+			}}},
+		},
+	}})
+	assert.ErrorContains(t, err, "exceeds the limit")
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"github.com/cloudwego/eino/schema"
+	"google.golang.org/genai"
+)
+
+// partMediaResolution maps the detail of an input image onto the media resolution of its part,
+// like the image detail of the ark models: low and high select the tokenization quality of the image,
+// auto and empty leave it to the request-wide MediaResolution.
+func partMediaResolution(detail schema.ImageURLDetail) *genai.PartMediaResolution {
+	switch detail {
+	case schema.ImageURLDetailLow:
+		return &genai.PartMediaResolution{Level: genai.PartMediaResolutionLevelMediaResolutionLow}
+	case schema.ImageURLDetailHigh:
+		return &genai.PartMediaResolution{Level: genai.PartMediaResolutionLevelMediaResolutionHigh}
+	default:
+		return nil
+	}
+}
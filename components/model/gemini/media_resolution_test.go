@@ -0,0 +1,63 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestMediaResolution(t *testing.T) {
+	cm, err := NewChatModel(context.Background(), &Config{Model: "test model", MediaResolution: genai.MediaResolutionMedium})
+	require.NoError(t, err)
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	_, _, conf, _, err := cm.genInputAndConf(input)
+	require.NoError(t, err)
+	assert.Equal(t, genai.MediaResolutionMedium, conf.MediaResolution)
+
+	_, _, conf, _, err = cm.genInputAndConf(input, WithMediaResolution(genai.MediaResolutionLow))
+	require.NoError(t, err)
+	assert.Equal(t, genai.MediaResolutionLow, conf.MediaResolution)
+
+	image := func(detail schema.ImageURLDetail) schema.MessageInputPart {
+		url := "gs://bucket/image.png"
+		return schema.MessageInputPart{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{
+			MessagePartCommon: schema.MessagePartCommon{URL: &url, MIMEType: "image/png"},
+			Detail:            detail,
+		}}
+	}
+	parts, err := convInputMedia([]schema.MessageInputPart{
+		image(schema.ImageURLDetailLow), image(schema.ImageURLDetailHigh), image(schema.ImageURLDetailAuto), image(""),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &genai.PartMediaResolution{Level: genai.PartMediaResolutionLevelMediaResolutionLow}, parts[0].MediaResolution)
+	assert.Equal(t, &genai.PartMediaResolution{Level: genai.PartMediaResolutionLevelMediaResolutionHigh}, parts[1].MediaResolution)
+	assert.Nil(t, parts[2].MediaResolution)
+	assert.Nil(t, parts[3].MediaResolution)
+
+	parts, err = convMedia([]schema.ChatMessagePart{{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{
+		URI: "gs://bucket/image.png", MIMEType: "image/png", Detail: schema.ImageURLDetailHigh,
+	}}})
+	require.NoError(t, err)
+	assert.Equal(t, &genai.PartMediaResolution{Level: genai.PartMediaResolutionLevelMediaResolutionHigh}, parts[0].MediaResolution)
+}
@@ -18,11 +18,16 @@ package gemini
 
 import (
 	"encoding/base64"
+	"errors"
+	"mime"
+	"strconv"
 	"strings"
 
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 	"google.golang.org/genai"
+
+	"github.com/cloudwego/eino-ext/libs/multimodal"
 )
 
 func init() {
@@ -99,6 +104,33 @@ func init() {
 		return ret, nil
 	})
 	schema.RegisterName[*genai.GroundingMetadata]("_eino_ext_gemini_ground_metadata")
+
+	compose.RegisterStreamChunkConcatFunc(func(chunks []*AudioMetaData) (final *AudioMetaData, err error) {
+		for _, chunk := range chunks {
+			if chunk != nil {
+				return chunk, nil
+			}
+		}
+		return nil, nil
+	})
+	schema.RegisterName[*AudioMetaData]("_eino_ext_gemini_audio_meta_data")
+
+	compose.RegisterStreamChunkConcatFunc(concatLast[genai.GenerateContentResponsePromptFeedback])
+	schema.RegisterName[*genai.GenerateContentResponsePromptFeedback]("_eino_ext_gemini_prompt_feedback")
+
+	// the usage metadata of the stream chunks is cumulative, the last one is the final usage
+	compose.RegisterStreamChunkConcatFunc(concatLast[genai.GenerateContentResponseUsageMetadata])
+	schema.RegisterName[*genai.GenerateContentResponseUsageMetadata]("_eino_ext_gemini_usage_metadata")
+}
+
+// concatLast concatenates the stream chunks of the extras which every chunk reports in full, keeping the last one.
+func concatLast[T any](chunks []*T) (*T, error) {
+	for i := len(chunks) - 1; i >= 0; i-- {
+		if chunks[i] != nil {
+			return chunks[i], nil
+		}
+	}
+	return nil, nil
 }
 
 const (
@@ -107,6 +139,9 @@ const (
 	specialParteKey     = "gemini_special_part"
 	groundMetadataKey   = "gemini_ground_metadata"
 	displayNameKey      = "gemini_display_name"
+	audioMetaDataKey    = "gemini_audio_meta_data"
+	promptFeedbackKey   = "gemini_prompt_feedback"
+	usageMetadataKey    = "gemini_usage_metadata"
 )
 
 // Deprecated: use SetInputVideoMetaData instead.
@@ -160,6 +195,34 @@ func getVideoMetaData(extra map[string]any) *genai.VideoMetadata {
 	return videoMetaData
 }
 
+// videoMetaDataOf returns the video metadata of a video part, set by SetInputVideoMetaData,
+// or else converted from the provider-portable multimodal.VideoMetadata.
+// A part carrying both is rejected, since one of them would be silently ignored.
+func videoMetaDataOf(extra map[string]any) (*genai.VideoMetadata, error) {
+	videoMetaData := getVideoMetaData(extra)
+	meta := multimodal.GetVideoMetadata(extra)
+	if videoMetaData != nil && meta != nil {
+		return nil, errors.New("video part carries both the metadata of SetInputVideoMetaData and multimodal.VideoMetadata, set only one of them")
+	}
+	if videoMetaData != nil {
+		return videoMetaData, nil
+	}
+	if meta == nil {
+		return nil, nil
+	}
+	if err := meta.Validate(); err != nil {
+		return nil, err
+	}
+	videoMetaData = &genai.VideoMetadata{
+		StartOffset: meta.StartOffset,
+		EndOffset:   meta.EndOffset,
+	}
+	if meta.FPS > 0 {
+		videoMetaData.FPS = &meta.FPS
+	}
+	return videoMetaData, nil
+}
+
 // setMessageThoughtSignature stores the thought signature in the Message's Extra field.
 // This is used for non-functionCall responses where the signature appears on text/inlineData parts.
 //
@@ -289,6 +352,89 @@ func GetGroundMetadata(m *schema.Message) *genai.GroundingMetadata {
 	return nil
 }
 
+// GetGoogleMapsWidgetContextToken returns the context token of the Google Maps widget from the grounding metadata
+// of the message, which renders the places grounding the answer with the Google Maps JavaScript API.
+// The token is only returned if the EnableWidget of the Google Maps tool is set.
+func GetGoogleMapsWidgetContextToken(m *schema.Message) (string, bool) {
+	gm := GetGroundMetadata(m)
+	if gm == nil || gm.GoogleMapsWidgetContextToken == "" {
+		return "", false
+	}
+	return gm.GoogleMapsWidgetContextToken, true
+}
+
+// GetGoogleMapsGroundingChunks returns the Google Maps places grounding the message, in the order of the grounding chunks,
+// e.g. to render the place titles and links next to the answer.
+func GetGoogleMapsGroundingChunks(m *schema.Message) []*genai.GroundingChunkMaps {
+	gm := GetGroundMetadata(m)
+	if gm == nil {
+		return nil
+	}
+	var places []*genai.GroundingChunkMaps
+	for _, chunk := range gm.GroundingChunks {
+		if chunk != nil && chunk.Maps != nil {
+			places = append(places, chunk.Maps)
+		}
+	}
+	return places
+}
+
+func setPromptFeedback(m *schema.Message, feedback *genai.GenerateContentResponsePromptFeedback) {
+	if m == nil || feedback == nil {
+		return
+	}
+	if m.Extra == nil {
+		m.Extra = make(map[string]any)
+	}
+	m.Extra[promptFeedbackKey] = feedback
+}
+
+// GetPromptFeedback returns the prompt feedback of the response, i.e. the safety ratings of the prompt,
+// or nil if Gemini returned none. A blocked prompt fails with a *PromptBlockedError instead.
+func GetPromptFeedback(m *schema.Message) *genai.GenerateContentResponsePromptFeedback {
+	if m == nil {
+		return nil
+	}
+	if feedback, ok := m.Extra[promptFeedbackKey].(*genai.GenerateContentResponsePromptFeedback); ok {
+		return feedback
+	}
+	return nil
+}
+
+func setUsageMetadata(m *schema.Message, usage *genai.GenerateContentResponseUsageMetadata) {
+	if m == nil || usage == nil {
+		return
+	}
+	if m.Extra == nil {
+		m.Extra = make(map[string]any)
+	}
+	m.Extra[usageMetadataKey] = usage
+}
+
+// GetUsageMetadata returns the usage metadata of the response, or nil if Gemini returned none.
+// Besides the token counts of ResponseMeta.Usage, it carries the per-modality breakdown of the prompt,
+// candidates, cached content and tool use prompt tokens, e.g. the audio tokens of the prompt.
+// The usage metadata of a stream chunk is cumulative, so the concatenated message carries the final usage.
+func GetUsageMetadata(m *schema.Message) *genai.GenerateContentResponseUsageMetadata {
+	if m == nil {
+		return nil
+	}
+	if usage, ok := m.Extra[usageMetadataKey].(*genai.GenerateContentResponseUsageMetadata); ok {
+		return usage
+	}
+	return nil
+}
+
+// GetFinishReason returns the reason why Gemini stopped generating the message,
+// e.g. genai.FinishReasonStop, genai.FinishReasonMaxTokens or genai.FinishReasonSafety,
+// or an empty string if it is unknown, e.g. for a chunk in the middle of a stream.
+func GetFinishReason(m *schema.Message) genai.FinishReason {
+	if m == nil || m.ResponseMeta == nil {
+		return ""
+	}
+	return genai.FinishReason(m.ResponseMeta.FinishReason)
+}
+
 func SetMultiModalToolResultDisplayName(input schema.MessageInputPart, displayName string) schema.MessageInputPart {
 	if input.Extra == nil {
 		input.Extra = make(map[string]any)
@@ -307,3 +453,38 @@ func getMultiModalToolResultDisplayName(input schema.MessageInputPart) string {
 	}
 	return displayName
 }
+
+// AudioMetaData describes the audio generated by native audio models.
+type AudioMetaData struct {
+	// Codec is the audio codec, e.g. "pcm".
+	Codec string `json:"codec,omitempty"`
+	// SampleRate is the sample rate in Hz, e.g. 24000.
+	SampleRate int `json:"sample_rate,omitempty"`
+}
+
+// GetOutputAudioMetaData returns the codec and sample rate of the audio generated by the model,
+// which are parsed from its MIME type, e.g. "audio/L16;codec=pcm;rate=24000".
+func GetOutputAudioMetaData(part *schema.MessageOutputAudio) *AudioMetaData {
+	if part == nil {
+		return nil
+	}
+	if meta, ok := part.Extra[audioMetaDataKey].(*AudioMetaData); ok {
+		return meta
+	}
+	return parseAudioMetaData(part.MIMEType)
+}
+
+func parseAudioMetaData(mimeType string) *AudioMetaData {
+	_, params, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return nil
+	}
+	meta := &AudioMetaData{Codec: params["codec"]}
+	if rate, err := strconv.Atoi(params["rate"]); err == nil {
+		meta.SampleRate = rate
+	}
+	if meta.Codec == "" && meta.SampleRate == 0 {
+		return nil
+	}
+	return meta
+}
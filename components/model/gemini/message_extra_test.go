@@ -19,11 +19,14 @@ package gemini
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/genai"
 
 	"github.com/cloudwego/eino/schema"
+
+	"github.com/cloudwego/eino-ext/libs/multimodal"
 )
 
 func TestVideoMetaDataFunctions(t *testing.T) {
@@ -41,6 +44,32 @@ func TestVideoMetaDataFunctions(t *testing.T) {
 		SetInputVideoMetaData(nil, metaData)
 		assert.Nil(t, GetInputVideoMetaData(nil))
 	})
+
+	t.Run("TestPortableVideoMetadata", func(t *testing.T) {
+		uri := "gs://bucket/video.mp4"
+		video := &schema.MessageInputVideo{MessagePartCommon: schema.MessagePartCommon{URL: &uri, MIMEType: "video/mp4"}}
+		video.Extra = multimodal.SetVideoMetadata(video.Extra, &multimodal.VideoMetadata{
+			FPS: 2, StartOffset: 10 * time.Second, EndOffset: 40 * time.Second})
+		parts, err := convInputMedia([]schema.MessageInputPart{{Type: schema.ChatMessagePartTypeVideoURL, Video: video}})
+		assert.NoError(t, err)
+		assert.Equal(t, &genai.VideoMetadata{FPS: ptr(2), StartOffset: 10 * time.Second, EndOffset: 40 * time.Second},
+			parts[0].VideoMetadata)
+
+		// a part carrying both the gemini and the portable metadata is rejected
+		SetInputVideoMetaData(video, &genai.VideoMetadata{FPS: ptr(5)})
+		_, err = convInputMedia([]schema.MessageInputPart{{Type: schema.ChatMessagePartTypeVideoURL, Video: video}})
+		assert.ErrorContains(t, err, "set only one of them")
+
+		video.Extra = nil
+		SetInputVideoMetaData(video, &genai.VideoMetadata{FPS: ptr(5)})
+		parts, err = convInputMedia([]schema.MessageInputPart{{Type: schema.ChatMessagePartTypeVideoURL, Video: video}})
+		assert.NoError(t, err)
+		assert.Equal(t, &genai.VideoMetadata{FPS: ptr(5)}, parts[0].VideoMetadata)
+
+		video.Extra = multimodal.SetVideoMetadata(nil, &multimodal.VideoMetadata{StartOffset: time.Minute, EndOffset: time.Second})
+		_, err = convInputMedia([]schema.MessageInputPart{{Type: schema.ChatMessagePartTypeVideoURL, Video: video}})
+		assert.ErrorContains(t, err, "invalid video metadata")
+	})
 }
 
 func TestMessageThoughtSignatureFunctions(t *testing.T) {
@@ -210,3 +239,89 @@ func TestCustomConcat(t *testing.T) {
 		"CodeExecutionResult": &genai.CodeExecutionResult{Outcome: "2", Output: "123"},
 	}, msg.Extra)
 }
+
+func TestGoogleMapsGrounding(t *testing.T) {
+	msg := &schema.Message{}
+	_, ok := GetGoogleMapsWidgetContextToken(msg)
+	assert.False(t, ok)
+	assert.Nil(t, GetGoogleMapsGroundingChunks(msg))
+
+	setGroundMetadata(msg, &genai.GroundingMetadata{
+		GoogleMapsWidgetContextToken: "widgetcontent/token",
+		GroundingChunks: []*genai.GroundingChunk{
+			{Web: &genai.GroundingChunkWeb{URI: "https://example.com"}},
+			{Maps: &genai.GroundingChunkMaps{PlaceID: "places/1", Title: "Cafe", URI: "https://maps.google.com/?cid=1"}},
+			{Maps: &genai.GroundingChunkMaps{PlaceID: "places/2", Title: "Park"}},
+		},
+	})
+	token, ok := GetGoogleMapsWidgetContextToken(msg)
+	assert.True(t, ok)
+	assert.Equal(t, "widgetcontent/token", token)
+
+	places := GetGoogleMapsGroundingChunks(msg)
+	assert.Len(t, places, 2)
+	assert.Equal(t, "places/1", places[0].PlaceID)
+	assert.Equal(t, "Park", places[1].Title)
+}
+
+func TestUsageMetadata(t *testing.T) {
+	chunkUsage := &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     10,
+		CandidatesTokenCount: 2,
+		TotalTokenCount:      12,
+		PromptTokensDetails: []*genai.ModalityTokenCount{
+			{Modality: genai.MediaModalityText, TokenCount: 4},
+			{Modality: genai.MediaModalityAudio, TokenCount: 6},
+		},
+	}
+	finalUsage := &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:     10,
+		CandidatesTokenCount: 5,
+		ThoughtsTokenCount:   3,
+		TotalTokenCount:      18,
+		PromptTokensDetails:  chunkUsage.PromptTokensDetails,
+		CandidatesTokensDetails: []*genai.ModalityTokenCount{
+			{Modality: genai.MediaModalityText, TokenCount: 5},
+		},
+	}
+
+	first, err := convStreamResponse(&genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			Content: &genai.Content{Role: roleModel, Parts: []*genai.Part{{Text: "Hello"}}},
+		}},
+		UsageMetadata: chunkUsage,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 12, first.ResponseMeta.Usage.TotalTokens)
+	assert.Equal(t, chunkUsage, GetUsageMetadata(first))
+
+	// the final chunk carries the usage without candidates
+	final, err := convStreamResponse(&genai.GenerateContentResponse{UsageMetadata: finalUsage})
+	assert.NoError(t, err)
+	assert.Equal(t, schema.Assistant, final.Role)
+	assert.Equal(t, &schema.TokenUsage{
+		PromptTokens:            10,
+		CompletionTokens:        5,
+		TotalTokens:             18,
+		CompletionTokensDetails: schema.CompletionTokensDetails{ReasoningTokens: 3},
+	}, final.ResponseMeta.Usage)
+
+	message, err := schema.ConcatMessages([]*schema.Message{first, final})
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", message.Content)
+	assert.Equal(t, 18, message.ResponseMeta.Usage.TotalTokens)
+	assert.Equal(t, finalUsage, GetUsageMetadata(message))
+
+	_, err = convStreamResponse(&genai.GenerateContentResponse{})
+	assert.EqualError(t, err, "gemini result is empty")
+
+	_, err = convStreamResponse(&genai.GenerateContentResponse{
+		PromptFeedback: &genai.GenerateContentResponsePromptFeedback{BlockReason: genai.BlockedReasonSafety},
+		UsageMetadata:  chunkUsage,
+	})
+	var blockedErr *PromptBlockedError
+	assert.ErrorAs(t, err, &blockedErr)
+
+	assert.Nil(t, GetUsageMetadata(nil))
+	assert.Nil(t, GetUsageMetadata(&schema.Message{}))
+}
@@ -25,11 +25,21 @@ import (
 
 type options struct {
 	TopK               *int32
+	Seed               *int32
+	PresencePenalty    *float32
+	FrequencyPenalty   *float32
 	ResponseJSONSchema *jsonschema.Schema
 	ThinkingConfig     *genai.ThinkingConfig
 	ResponseModalities []GeminiResponseModality
 	ImageConfig        *genai.ImageConfig
+	SpeechConfig       *genai.SpeechConfig
+	MediaResolution    genai.MediaResolution
 	CachedContentName  string
+	ToolConfig         *genai.ToolConfig
+	Tools              []*genai.Tool
+	Labels             map[string]string
+
+	SeparateReasoningChunks *bool
 }
 
 func WithTopK(k int32) model.Option {
@@ -38,6 +48,30 @@ func WithTopK(k int32) model.Option {
 	})
 }
 
+// WithSeed sets the seed of the sampling for a single request, overriding Config.Seed.
+// Optional.
+func WithSeed(seed int32) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.Seed = &seed
+	})
+}
+
+// WithPresencePenalty sets the presence penalty for a single request, overriding Config.PresencePenalty.
+// Optional.
+func WithPresencePenalty(penalty float32) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.PresencePenalty = &penalty
+	})
+}
+
+// WithFrequencyPenalty sets the frequency penalty for a single request, overriding Config.FrequencyPenalty.
+// Optional.
+func WithFrequencyPenalty(penalty float32) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.FrequencyPenalty = &penalty
+	})
+}
+
 func WithResponseJSONSchema(s *jsonschema.Schema) model.Option {
 	return model.WrapImplSpecificOptFn(func(o *options) {
 		o.ResponseJSONSchema = s
@@ -72,3 +106,56 @@ func WithImageConfig(cfg *genai.ImageConfig) model.Option {
 		o.ImageConfig = cfg
 	})
 }
+
+// WithSpeechConfig sets the speech generation configuration for native audio models, e.g. the voice.
+// Optional.
+func WithSpeechConfig(cfg *genai.SpeechConfig) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.SpeechConfig = cfg
+	})
+}
+
+// WithMediaResolution sets the resolution of the image and video inputs for a single request,
+// overriding Config.MediaResolution.
+// Optional.
+func WithMediaResolution(resolution genai.MediaResolution) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.MediaResolution = resolution
+	})
+}
+
+// WithToolConfig overrides the tool config of a single request, e.g. to set the retrieval config of the Google Maps tool.
+// If its FunctionCallingConfig is nil, the function calling config converted from the tool choice is kept.
+// Optional.
+func WithToolConfig(cfg *genai.ToolConfig) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.ToolConfig = cfg
+	})
+}
+
+// WithGeminiTools adds Gemini tools to a single request, e.g. a built-in tool such as Google Search,
+// in addition to the function tools and the built-in tools enabled by the Config.
+// Optional.
+func WithGeminiTools(tools ...*genai.Tool) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.Tools = tools
+	})
+}
+
+// WithLabels adds labels to a single request for the billing attribution on Vertex AI,
+// the labels override the Config.Labels with the same keys.
+// Optional.
+func WithLabels(labels map[string]string) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.Labels = labels
+	})
+}
+
+// WithSeparateReasoningChunks streams the thoughts and the response of the model in separate chunks
+// for a single request, overriding Config.SeparateReasoningChunks.
+// Optional.
+func WithSeparateReasoningChunks(separate bool) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.SeparateReasoningChunks = &separate
+	})
+}
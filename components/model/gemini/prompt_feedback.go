@@ -0,0 +1,42 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"fmt"
+
+	"google.golang.org/genai"
+)
+
+// PromptBlockedError is returned when Gemini blocks the prompt and generates no candidate,
+// e.g. for safety reasons. Use errors.As to branch on the block reason.
+type PromptBlockedError struct {
+	// Feedback is the prompt feedback of the response, including the block reason and the safety ratings.
+	Feedback *genai.GenerateContentResponsePromptFeedback
+}
+
+func (e *PromptBlockedError) Error() string {
+	if e.Feedback.BlockReasonMessage != "" {
+		return fmt.Sprintf("gemini blocked the prompt, reason: %s, message: %s", e.Feedback.BlockReason, e.Feedback.BlockReasonMessage)
+	}
+	return fmt.Sprintf("gemini blocked the prompt, reason: %s", e.Feedback.BlockReason)
+}
+
+// BlockReason returns the reason why the prompt was blocked, e.g. genai.BlockedReasonSafety.
+func (e *PromptBlockedError) BlockReason() genai.BlockedReason {
+	return e.Feedback.BlockReason
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genai"
+)
+
+func TestPromptFeedback(t *testing.T) {
+	t.Run("blocked prompt", func(t *testing.T) {
+		_, err := convResponse(&genai.GenerateContentResponse{
+			PromptFeedback: &genai.GenerateContentResponsePromptFeedback{
+				BlockReason:        genai.BlockedReasonSafety,
+				BlockReasonMessage: "unsafe",
+			},
+		})
+		err = fmt.Errorf("convert response fail: %w", err)
+
+		var blockedErr *PromptBlockedError
+		assert.True(t, errors.As(err, &blockedErr))
+		assert.Equal(t, genai.BlockedReasonSafety, blockedErr.BlockReason())
+		assert.Contains(t, err.Error(), "reason: SAFETY, message: unsafe")
+	})
+
+	t.Run("empty result without block reason", func(t *testing.T) {
+		_, err := convResponse(&genai.GenerateContentResponse{
+			PromptFeedback: &genai.GenerateContentResponsePromptFeedback{},
+		})
+		var blockedErr *PromptBlockedError
+		assert.False(t, errors.As(err, &blockedErr))
+		assert.EqualError(t, err, "gemini result is empty")
+	})
+
+	t.Run("feedback and finish reason", func(t *testing.T) {
+		feedback := &genai.GenerateContentResponsePromptFeedback{
+			SafetyRatings: []*genai.SafetyRating{{Category: genai.HarmCategoryHarassment, Probability: genai.HarmProbabilityLow}},
+		}
+		message, err := convResponse(&genai.GenerateContentResponse{
+			Candidates: []*genai.Candidate{{
+				Content:      &genai.Content{Role: roleModel, Parts: []*genai.Part{{Text: "partial"}}},
+				FinishReason: genai.FinishReasonMaxTokens,
+			}},
+			PromptFeedback: feedback,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, feedback, GetPromptFeedback(message))
+		assert.Equal(t, genai.FinishReasonMaxTokens, GetFinishReason(message))
+	})
+
+	t.Run("accessors on empty message", func(t *testing.T) {
+		assert.Nil(t, GetPromptFeedback(nil))
+		assert.Nil(t, GetPromptFeedback(&schema.Message{}))
+		assert.Equal(t, genai.FinishReason(""), GetFinishReason(nil))
+		assert.Equal(t, genai.FinishReason(""), GetFinishReason(&schema.Message{}))
+	})
+
+	t.Run("concat stream chunks", func(t *testing.T) {
+		feedback := &genai.GenerateContentResponsePromptFeedback{}
+		first := &schema.Message{Role: schema.Assistant, Content: "a"}
+		setPromptFeedback(first, feedback)
+		last := &schema.Message{Role: schema.Assistant, Content: "b", ResponseMeta: &schema.ResponseMeta{FinishReason: string(genai.FinishReasonStop)}}
+
+		message, err := schema.ConcatMessages([]*schema.Message{first, last})
+		assert.NoError(t, err)
+		assert.Equal(t, feedback, GetPromptFeedback(message))
+		assert.Equal(t, genai.FinishReasonStop, GetFinishReason(message))
+	})
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// StreamChunkType tells whether a chunk of a stream carries the thoughts or the response of the model,
+// see Config.SeparateReasoningChunks.
+type StreamChunkType string
+
+const (
+	// StreamChunkTypeReasoning is a chunk of thoughts, carried by ReasoningContent only.
+	StreamChunkTypeReasoning StreamChunkType = "reasoning"
+	// StreamChunkTypeContent is a chunk of the response, e.g. the content, tool calls, or output parts.
+	StreamChunkTypeContent StreamChunkType = "content"
+)
+
+const streamChunkTypeKey = "gemini_stream_chunk_type"
+
+func init() {
+	// the type of the concatenated chunks is the type of the last chunk
+	compose.RegisterStreamChunkConcatFunc(func(chunks []StreamChunkType) (StreamChunkType, error) {
+		for i := len(chunks) - 1; i >= 0; i-- {
+			if chunks[i] != "" {
+				return chunks[i], nil
+			}
+		}
+		return "", nil
+	})
+	schema.RegisterName[StreamChunkType]("_eino_ext_gemini_stream_chunk_type")
+}
+
+// GetStreamChunkType returns the type of a chunk streamed with Config.SeparateReasoningChunks or WithSeparateReasoningChunks,
+// so that UIs route the thoughts to a collapsible pane and the response to the main view.
+func GetStreamChunkType(msg *schema.Message) (StreamChunkType, bool) {
+	if msg == nil || msg.Extra == nil {
+		return "", false
+	}
+	typ, ok := msg.Extra[streamChunkTypeKey].(StreamChunkType)
+	return typ, ok
+}
+
+// separateReasoningChunk splits a chunk carrying both thoughts and response into a reasoning chunk
+// followed by a content chunk, and tags the chunks with their StreamChunkType.
+// The thought signatures, the response meta, and the other extra fields stay with the content chunk,
+// or with the reasoning chunk if there is no response, so that no empty content chunk is added.
+func separateReasoningChunk(msg *schema.Message) []*schema.Message {
+	if msg.ReasoningContent == "" {
+		setStreamChunkType(msg, StreamChunkTypeContent)
+		return []*schema.Message{msg}
+	}
+	if msg.Content == "" && len(msg.MultiContent) == 0 && len(msg.AssistantGenMultiContent) == 0 &&
+		len(msg.ToolCalls) == 0 {
+		setStreamChunkType(msg, StreamChunkTypeReasoning)
+		return []*schema.Message{msg}
+	}
+
+	reasoning := &schema.Message{Role: msg.Role, ReasoningContent: msg.ReasoningContent}
+	setStreamChunkType(reasoning, StreamChunkTypeReasoning)
+	content := *msg
+	content.ReasoningContent = ""
+	content.Extra = make(map[string]any, len(msg.Extra)+1)
+	for k, v := range msg.Extra {
+		content.Extra[k] = v
+	}
+	setStreamChunkType(&content, StreamChunkTypeContent)
+	return []*schema.Message{reasoning, &content}
+}
+
+func setStreamChunkType(msg *schema.Message, typ StreamChunkType) {
+	if msg.Extra == nil {
+		msg.Extra = make(map[string]any)
+	}
+	msg.Extra[streamChunkTypeKey] = typ
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeparateReasoningChunk(t *testing.T) {
+	// a chunk mixing thoughts and response is split, the thoughts first
+	meta := &schema.ResponseMeta{FinishReason: "STOP"}
+	msg := &schema.Message{Role: schema.Assistant, ReasoningContent: "let me think", Content: "answer",
+		ResponseMeta: meta, Extra: map[string]any{"k": "v"}}
+	chunks := separateReasoningChunk(msg)
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, "let me think", chunks[0].ReasoningContent)
+	assert.Empty(t, chunks[0].Content)
+	assert.Nil(t, chunks[0].ResponseMeta)
+	typ, ok := GetStreamChunkType(chunks[0])
+	assert.True(t, ok)
+	assert.Equal(t, StreamChunkTypeReasoning, typ)
+	assert.Empty(t, chunks[1].ReasoningContent)
+	assert.Equal(t, "answer", chunks[1].Content)
+	assert.Equal(t, meta, chunks[1].ResponseMeta)
+	assert.Equal(t, "v", chunks[1].Extra["k"])
+	typ, _ = GetStreamChunkType(chunks[1])
+	assert.Equal(t, StreamChunkTypeContent, typ)
+	// the original chunk is left untouched
+	assert.Equal(t, "let me think", msg.ReasoningContent)
+	assert.NotContains(t, msg.Extra, streamChunkTypeKey)
+
+	// chunks with either the thoughts or the response are only tagged
+	chunks = separateReasoningChunk(&schema.Message{Role: schema.Assistant, ReasoningContent: "hmm"})
+	assert.Len(t, chunks, 1)
+	typ, _ = GetStreamChunkType(chunks[0])
+	assert.Equal(t, StreamChunkTypeReasoning, typ)
+	// the response meta of a chunk of thoughts stays with it, without an extra empty content chunk
+	chunks = separateReasoningChunk(&schema.Message{Role: schema.Assistant, ReasoningContent: "hmm", ResponseMeta: meta})
+	assert.Len(t, chunks, 1)
+	assert.Equal(t, meta, chunks[0].ResponseMeta)
+	typ, _ = GetStreamChunkType(chunks[0])
+	assert.Equal(t, StreamChunkTypeReasoning, typ)
+	chunks = separateReasoningChunk(&schema.Message{Role: schema.Assistant, Content: "hi"})
+	assert.Len(t, chunks, 1)
+	typ, _ = GetStreamChunkType(chunks[0])
+	assert.Equal(t, StreamChunkTypeContent, typ)
+
+	_, ok = GetStreamChunkType(&schema.Message{Role: schema.Assistant})
+	assert.False(t, ok)
+	_, ok = GetStreamChunkType(nil)
+	assert.False(t, ok)
+
+	// the chunks concatenate back into the whole message
+	concatenated, err := schema.ConcatMessages(append(
+		separateReasoningChunk(&schema.Message{Role: schema.Assistant, ReasoningContent: "a"}),
+		separateReasoningChunk(&schema.Message{Role: schema.Assistant, ReasoningContent: "b", Content: "c"})...))
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", concatenated.ReasoningContent)
+	assert.Equal(t, "c", concatenated.Content)
+	typ, _ = GetStreamChunkType(concatenated)
+	assert.Equal(t, StreamChunkTypeContent, typ)
+
+	o := model.GetImplSpecificOptions(&options{}, WithSeparateReasoningChunks(true))
+	assert.True(t, *o.SeparateReasoningChunks)
+}
@@ -0,0 +1,235 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = time.Second
+	defaultRetryMaxBackoff     = time.Minute
+
+	retryInfoType = "type.googleapis.com/google.rpc.RetryInfo"
+)
+
+// ErrorKind is the category of an error returned by the Gemini API, given by its status.
+type ErrorKind string
+
+const (
+	// ErrorKindQuota is a RESOURCE_EXHAUSTED (429) error, e.g. the rate limit or the quota is exceeded.
+	// It is usually retryable after the delay suggested by the RetryInfo of the error.
+	ErrorKindQuota ErrorKind = "quota"
+	// ErrorKindInvalidArgument is an INVALID_ARGUMENT, FAILED_PRECONDITION or OUT_OF_RANGE (400) error,
+	// i.e. the request is rejected, so retrying the same request fails again.
+	ErrorKindInvalidArgument ErrorKind = "invalid_argument"
+	// ErrorKindInternal is an INTERNAL, UNAVAILABLE or DEADLINE_EXCEEDED (5xx) error, which is usually transient.
+	ErrorKindInternal ErrorKind = "internal"
+	// ErrorKindOther is any other error of the Gemini API, e.g. PERMISSION_DENIED or NOT_FOUND.
+	ErrorKindOther ErrorKind = "other"
+)
+
+// APIError is an error returned by the Gemini API, classified by Kind.
+// The original genai.APIError can be obtained by errors.As.
+type APIError struct {
+	// Kind is the category of the error.
+	Kind ErrorKind
+	// Code is the HTTP status code.
+	Code int
+	// Status is the status of the error, e.g. "RESOURCE_EXHAUSTED".
+	Status string
+	// Message is the error message returned by the Gemini API.
+	Message string
+	// RetryDelay is the delay suggested by the RetryInfo of the error details before retrying, 0 if none.
+	RetryDelay time.Duration
+
+	err error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gemini %s error, code=%d, status=%s: %s", e.Kind, e.Code, e.Status, e.Message)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// convAPIError converts the genai.APIError in err to an *APIError, otherwise err is returned as is.
+func convAPIError(err error) error {
+	var apiErr genai.APIError
+	if err == nil || !errors.As(err, &apiErr) {
+		return err
+	}
+	return &APIError{
+		Kind:       classifyAPIError(apiErr.Code, apiErr.Status),
+		Code:       apiErr.Code,
+		Status:     apiErr.Status,
+		Message:    apiErr.Message,
+		RetryDelay: retryDelayOf(apiErr.Details),
+		err:        err,
+	}
+}
+
+func classifyAPIError(code int, status string) ErrorKind {
+	switch status {
+	case "RESOURCE_EXHAUSTED":
+		return ErrorKindQuota
+	case "INVALID_ARGUMENT", "FAILED_PRECONDITION", "OUT_OF_RANGE":
+		return ErrorKindInvalidArgument
+	case "INTERNAL", "UNAVAILABLE", "DEADLINE_EXCEEDED":
+		return ErrorKindInternal
+	}
+	switch {
+	case code == 429:
+		return ErrorKindQuota
+	case code == 400:
+		return ErrorKindInvalidArgument
+	case code >= 500:
+		return ErrorKindInternal
+	default:
+		return ErrorKindOther
+	}
+}
+
+// retryDelayOf returns the delay of the RetryInfo in the error details, e.g. {"retryDelay": "30s"}.
+func retryDelayOf(details []map[string]any) time.Duration {
+	for _, detail := range details {
+		if detail["@type"] != retryInfoType {
+			continue
+		}
+		delay, _ := detail["retryDelay"].(string)
+		if d, err := time.ParseDuration(delay); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// RetryConfig configures the retries of the requests failed with a retryable error of the Gemini API.
+// The retries wait for the delay suggested by the RetryInfo of the error,
+// or an exponential backoff with jitter if none.
+// A Stream request is only retried if it fails before the first chunk is received.
+type RetryConfig struct {
+	// MaxAttempts is the max number of attempts, including the first one.
+	// Default is 3.
+	MaxAttempts int
+
+	// InitialBackoff is the backoff before the first retry, doubled for each following retry.
+	// Default is 1s.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the backoff and the delay suggested by the RetryInfo.
+	// Default is 1m.
+	MaxBackoff time.Duration
+
+	// RetryableKinds are the kinds of the errors which are retried.
+	// Default is ErrorKindQuota and ErrorKindInternal.
+	RetryableKinds []ErrorKind
+}
+
+func (c *RetryConfig) withDefaults() *RetryConfig {
+	nc := *c
+	if nc.MaxAttempts <= 0 {
+		nc.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if nc.InitialBackoff <= 0 {
+		nc.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if nc.MaxBackoff <= 0 {
+		nc.MaxBackoff = defaultRetryMaxBackoff
+	}
+	if len(nc.RetryableKinds) == 0 {
+		nc.RetryableKinds = []ErrorKind{ErrorKindQuota, ErrorKindInternal}
+	}
+	return &nc
+}
+
+// retryable reports whether the attempt, starting from 1, failed with err is followed by a retry.
+// A nil *RetryConfig never retries.
+func (c *RetryConfig) retryable(attempt int, err error) bool {
+	var apiErr *APIError
+	if c == nil || attempt >= c.MaxAttempts || !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, kind := range c.RetryableKinds {
+		if apiErr.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the wait time before the retry following the attempt, starting from 1.
+// The exponential backoff is jittered in [backoff/2, backoff) to spread the retries of concurrent requests.
+func (c *RetryConfig) backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryDelay > 0 {
+		if apiErr.RetryDelay > c.MaxBackoff {
+			return c.MaxBackoff
+		}
+		return apiErr.RetryDelay
+	}
+
+	d := c.InitialBackoff
+	for i := 1; i < attempt && d < c.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > c.MaxBackoff {
+		d = c.MaxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// wait waits for the backoff before the retry following the attempt, or returns the error of ctx if it is done first.
+func (c *RetryConfig) wait(ctx context.Context, attempt int, err error) error {
+	timer := time.NewTimer(c.backoff(attempt, err))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// withRetry calls fn until it succeeds, fails with an error which is not retryable, or the attempts are exhausted.
+// The genai.APIError returned by fn is converted to an *APIError.
+func withRetry[T any](ctx context.Context, conf *RetryConfig, fn func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		ret, err := fn()
+		if err == nil {
+			return ret, nil
+		}
+		err = convAPIError(err)
+		if !conf.retryable(attempt, err) {
+			if attempt > 1 {
+				err = fmt.Errorf("failed after %d attempts: %w", attempt, err)
+			}
+			return ret, err
+		}
+		if err = conf.wait(ctx, attempt, err); err != nil {
+			return ret, err
+		}
+	}
+}
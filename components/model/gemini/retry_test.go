@@ -0,0 +1,151 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genai"
+)
+
+func TestConvAPIError(t *testing.T) {
+	t.Run("not an api error", func(t *testing.T) {
+		err := errors.New("network")
+		assert.Equal(t, err, convAPIError(err))
+		assert.Nil(t, convAPIError(nil))
+	})
+
+	t.Run("classify", func(t *testing.T) {
+		cases := []struct {
+			code   int
+			status string
+			kind   ErrorKind
+		}{
+			{429, "RESOURCE_EXHAUSTED", ErrorKindQuota},
+			{429, "", ErrorKindQuota},
+			{400, "INVALID_ARGUMENT", ErrorKindInvalidArgument},
+			{400, "FAILED_PRECONDITION", ErrorKindInvalidArgument},
+			{500, "INTERNAL", ErrorKindInternal},
+			{503, "UNAVAILABLE", ErrorKindInternal},
+			{502, "", ErrorKindInternal},
+			{403, "PERMISSION_DENIED", ErrorKindOther},
+		}
+		for _, c := range cases {
+			err := convAPIError(fmt.Errorf("wrapped: %w", genai.APIError{Code: c.code, Status: c.status, Message: "msg"}))
+			var apiErr *APIError
+			assert.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, c.kind, apiErr.Kind, "code=%d status=%s", c.code, c.status)
+
+			var origErr genai.APIError
+			assert.True(t, errors.As(err, &origErr))
+		}
+	})
+
+	t.Run("retry info", func(t *testing.T) {
+		err := convAPIError(genai.APIError{Code: 429, Status: "RESOURCE_EXHAUSTED", Details: []map[string]any{
+			{"@type": "type.googleapis.com/google.rpc.QuotaFailure"},
+			{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "36s"},
+		}})
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 36*time.Second, apiErr.RetryDelay)
+		assert.Contains(t, apiErr.Error(), "gemini quota error, code=429, status=RESOURCE_EXHAUSTED")
+	})
+}
+
+func TestWithRetry(t *testing.T) {
+	ctx := context.Background()
+	quotaErr := genai.APIError{Code: 429, Status: "RESOURCE_EXHAUSTED", Details: []map[string]any{
+		{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "0.001s"},
+	}}
+	conf := (&RetryConfig{InitialBackoff: time.Millisecond}).withDefaults()
+
+	t.Run("no retry config", func(t *testing.T) {
+		calls := 0
+		_, err := withRetry(ctx, nil, func() (int, error) {
+			calls++
+			return 0, quotaErr
+		})
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("retry until success", func(t *testing.T) {
+		calls := 0
+		ret, err := withRetry(ctx, conf, func() (int, error) {
+			calls++
+			if calls < 3 {
+				return 0, genai.APIError{Code: 503, Status: "UNAVAILABLE"}
+			}
+			return 42, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 42, ret)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		calls := 0
+		_, err := withRetry(ctx, conf, func() (int, error) {
+			calls++
+			return 0, quotaErr
+		})
+		assert.ErrorContains(t, err, "failed after 3 attempts")
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, ErrorKindQuota, apiErr.Kind)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("invalid argument is not retried", func(t *testing.T) {
+		calls := 0
+		_, err := withRetry(ctx, conf, func() (int, error) {
+			calls++
+			return 0, genai.APIError{Code: 400, Status: "INVALID_ARGUMENT"}
+		})
+		var apiErr *APIError
+		assert.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, ErrorKindInvalidArgument, apiErr.Kind)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("context done while waiting", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := withRetry(cctx, (&RetryConfig{InitialBackoff: time.Hour}).withDefaults(), func() (int, error) {
+			return 0, genai.APIError{Code: 500, Status: "INTERNAL"}
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		c := (&RetryConfig{InitialBackoff: time.Second, MaxBackoff: 4 * time.Second}).withDefaults()
+		internalErr := convAPIError(genai.APIError{Code: 500, Status: "INTERNAL"})
+		assert.InDelta(t, float64(time.Second), float64(c.backoff(1, internalErr)), float64(time.Second/2))
+		assert.LessOrEqual(t, c.backoff(5, internalErr), 4*time.Second)
+		longDelay := convAPIError(genai.APIError{Code: 429, Details: []map[string]any{
+			{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "60s"},
+		}})
+		assert.Equal(t, 4*time.Second, c.backoff(1, longDelay))
+	})
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/eino/schema"
+	"google.golang.org/genai"
+)
+
+// SystemMessageMode defines how system messages of the input are mapped to the system instruction of Gemini,
+// which accepts a single system instruction and no system role in the contents.
+type SystemMessageMode string
+
+const (
+	// SystemMessageModeDefault uses the first message as the system instruction if it is a system message,
+	// other system messages are sent as user messages.
+	SystemMessageModeDefault SystemMessageMode = ""
+	// SystemMessageModeMerge merges all system messages, wherever they appear in the conversation,
+	// into the system instruction, joined by SystemMessageSeparator.
+	SystemMessageModeMerge SystemMessageMode = "merge"
+	// SystemMessageModeStrictSingle rejects the input if it has more than one system message
+	// or a system message that is not the first message.
+	SystemMessageModeStrictSingle SystemMessageMode = "strict_single"
+)
+
+const defaultSystemMessageSeparator = "\n\n"
+
+// splitSystemInstruction extracts the system instruction from input according to the system message mode,
+// and returns the remaining messages sent as contents.
+func (cm *ChatModel) splitSystemInstruction(input []*schema.Message) (*genai.Content, []*schema.Message, error) {
+	switch cm.systemMessageMode {
+	case SystemMessageModeDefault:
+	case SystemMessageModeMerge:
+		return cm.mergeSystemMessages(input)
+	case SystemMessageModeStrictSingle:
+		for i, msg := range input {
+			if msg != nil && msg.Role == schema.System && i > 0 {
+				return nil, nil, fmt.Errorf("system message at index %d is not allowed by SystemMessageModeStrictSingle, "+
+					"only the first message can be a system message", i)
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown system message mode: %s", cm.systemMessageMode)
+	}
+
+	// A system message without other messages is sent as a user message, as Gemini requires at least one content.
+	if len(input) > 1 && input[0] != nil && input[0].Role == schema.System {
+		instruction, err := convSchemaMessage(input[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert system instruction: %w", err)
+		}
+		return instruction, input[1:], nil
+	}
+	return nil, input, nil
+}
+
+func (cm *ChatModel) mergeSystemMessages(input []*schema.Message) (*genai.Content, []*schema.Message, error) {
+	var systemMsgs, others []*schema.Message
+	for _, msg := range input {
+		if msg != nil && msg.Role == schema.System {
+			systemMsgs = append(systemMsgs, msg)
+		} else {
+			others = append(others, msg)
+		}
+	}
+	// Keep the input as is if there is nothing left to send as contents.
+	if len(systemMsgs) == 0 || len(others) == 0 {
+		return nil, input, nil
+	}
+
+	separator := cm.systemMessageSeparator
+	if separator == "" {
+		separator = defaultSystemMessageSeparator
+	}
+
+	var parts []*genai.Part
+	for i, msg := range systemMsgs {
+		content, err := convSchemaMessage(msg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert system instruction: %w", err)
+		}
+		if content == nil || len(content.Parts) == 0 {
+			continue
+		}
+		if i > 0 && len(parts) > 0 {
+			parts = append(parts, genai.NewPartFromText(separator))
+		}
+		parts = append(parts, content.Parts...)
+	}
+	if len(parts) == 0 {
+		return nil, others, nil
+	}
+
+	return &genai.Content{
+		Role:  roleUser,
+		Parts: mergeTextParts(parts),
+	}, others, nil
+}
+
+// mergeTextParts joins adjacent plain text parts, so that text-only system messages
+// result in a single text part.
+func mergeTextParts(parts []*genai.Part) []*genai.Part {
+	isPlainText := func(p *genai.Part) bool {
+		return p.Text != "" && !p.Thought && len(p.ThoughtSignature) == 0 &&
+			p.InlineData == nil && p.FileData == nil && p.VideoMetadata == nil && p.MediaResolution == nil &&
+			p.FunctionCall == nil && p.FunctionResponse == nil && p.ExecutableCode == nil && p.CodeExecutionResult == nil
+	}
+
+	result := make([]*genai.Part, 0, len(parts))
+	for _, p := range parts {
+		if len(result) > 0 && isPlainText(p) && isPlainText(result[len(result)-1]) {
+			result[len(result)-1] = genai.NewPartFromText(result[len(result)-1].Text + p.Text)
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gemini
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestSystemMessageMode(t *testing.T) {
+	ctx := context.Background()
+
+	// ReAct agent layout: the agent prompt, the conversation with a tool round trip,
+	// and a system message appended by the agent before the next model call.
+	toolCallID := "call_1"
+	reactInput := []*schema.Message{
+		schema.SystemMessage("You are a helpful assistant."),
+		schema.UserMessage("What's the weather in Beijing?"),
+		schema.AssistantMessage("", []schema.ToolCall{{
+			ID:       toolCallID,
+			Function: schema.FunctionCall{Name: "get_weather", Arguments: `{"city":"Beijing"}`},
+		}}),
+		schema.ToolMessage(`{"weather":"sunny"}`, toolCallID, schema.WithToolName("get_weather")),
+		schema.SystemMessage("Answer in one sentence."),
+	}
+
+	newModel := func(mode SystemMessageMode, separator string) *ChatModel {
+		cm, err := NewChatModel(ctx, &Config{
+			Model:                  "gemini-test",
+			SystemMessageMode:      mode,
+			SystemMessageSeparator: separator,
+		})
+		require.NoError(t, err)
+		return cm
+	}
+
+	t.Run("default", func(t *testing.T) {
+		cm := newModel(SystemMessageModeDefault, "")
+		_, nInput, conf, _, err := cm.genInputAndConf(reactInput)
+		require.NoError(t, err)
+		require.NotNil(t, conf.SystemInstruction)
+		assert.Equal(t, "You are a helpful assistant.", conf.SystemInstruction.Parts[0].Text)
+		assert.Equal(t, reactInput[1:], nInput)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		cm := newModel(SystemMessageModeMerge, "")
+		_, nInput, conf, _, err := cm.genInputAndConf(reactInput)
+		require.NoError(t, err)
+		require.NotNil(t, conf.SystemInstruction)
+		require.Len(t, conf.SystemInstruction.Parts, 1)
+		assert.Equal(t, "You are a helpful assistant.\n\nAnswer in one sentence.", conf.SystemInstruction.Parts[0].Text)
+		assert.Equal(t, reactInput[1:4], nInput)
+
+		contents, err := convSchemaMessages(nInput)
+		require.NoError(t, err)
+		for _, c := range contents {
+			assert.NotEqual(t, "Answer in one sentence.", c.Parts[0].Text)
+		}
+	})
+
+	t.Run("merge with separator", func(t *testing.T) {
+		cm := newModel(SystemMessageModeMerge, "\n---\n")
+		input := []*schema.Message{
+			schema.SystemMessage("rule 1"),
+			schema.SystemMessage("rule 2"),
+			schema.UserMessage("hi"),
+		}
+		_, nInput, conf, _, err := cm.genInputAndConf(input)
+		require.NoError(t, err)
+		require.Len(t, conf.SystemInstruction.Parts, 1)
+		assert.Equal(t, "rule 1\n---\nrule 2", conf.SystemInstruction.Parts[0].Text)
+		assert.Equal(t, input[2:], nInput)
+	})
+
+	t.Run("merge without other messages", func(t *testing.T) {
+		cm := newModel(SystemMessageModeMerge, "")
+		input := []*schema.Message{
+			schema.SystemMessage("rule 1"),
+			schema.SystemMessage("rule 2"),
+		}
+		_, nInput, conf, _, err := cm.genInputAndConf(input)
+		require.NoError(t, err)
+		assert.Nil(t, conf.SystemInstruction)
+		assert.Equal(t, input, nInput)
+	})
+
+	t.Run("strict single", func(t *testing.T) {
+		cm := newModel(SystemMessageModeStrictSingle, "")
+		_, _, _, _, err := cm.genInputAndConf(reactInput)
+		assert.ErrorContains(t, err, "system message at index 4")
+
+		_, nInput, conf, _, err := cm.genInputAndConf(reactInput[:4])
+		require.NoError(t, err)
+		assert.Equal(t, "You are a helpful assistant.", conf.SystemInstruction.Parts[0].Text)
+		assert.Equal(t, reactInput[1:4], nInput)
+
+		_, _, _, _, err = cm.genInputAndConf([]*schema.Message{
+			schema.UserMessage("hi"),
+			schema.SystemMessage("late prompt"),
+		})
+		assert.ErrorContains(t, err, "system message at index 1")
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		cm := newModel("unknown", "")
+		_, _, _, _, err := cm.genInputAndConf(reactInput)
+		assert.ErrorContains(t, err, "unknown system message mode")
+	})
+}
+
+func TestMergeTextParts(t *testing.T) {
+	parts := mergeTextParts([]*genai.Part{
+		genai.NewPartFromText("a"),
+		genai.NewPartFromText("b"),
+		{InlineData: &genai.Blob{MIMEType: "image/png", Data: []byte{1}}},
+		genai.NewPartFromText("c"),
+	})
+	require.Len(t, parts, 3)
+	assert.Equal(t, "ab", parts[0].Text)
+	assert.NotNil(t, parts[1].InlineData)
+	assert.Equal(t, "c", parts[2].Text)
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ollama
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+// Validate checks the config without creating a client, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid.
+func (c *ChatModelConfig) Validate() error {
+	r := configcheck.New("ollama.ChatModelConfig")
+	if _, err := url.Parse(c.BaseURL); err != nil {
+		r.AddError("BaseURL", err)
+	}
+	configcheck.NonNegative(r, "Timeout", &c.Timeout)
+	r.Checkf(c.Model != "", "Model", "model is required")
+	r.Checkf(len(c.Format) == 0 || json.Valid(c.Format), "Format", "must be \"json\" or a JSON schema, got %s", c.Format)
+	if o := c.Options; o != nil {
+		configcheck.NonNegative(r, "Options.Temperature", &o.Temperature)
+		configcheck.InRange(r, "Options.TopP", &o.TopP, 0, 1)
+		configcheck.InRange(r, "Options.MinP", &o.MinP, 0, 1)
+		configcheck.NonNegative(r, "Options.TopK", &o.TopK)
+		configcheck.NonNegative(r, "Options.NumCtx", &o.NumCtx)
+		// -1 generates without limit, -2 until the context is filled.
+		r.Checkf(o.NumPredict >= -2, "Options.NumPredict", "must not be less than -2, got %d", o.NumPredict)
+	}
+	r.Checkf(c.Thinking.IsValid(), "Thinking", "must be a bool or one of \"high\", \"medium\" and \"low\", got %v", thinkingValue(c.Thinking))
+	return r.Err()
+}
+
+func thinkingValue(t *ThinkValue) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.Value
+}
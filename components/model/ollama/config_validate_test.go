@@ -0,0 +1,61 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ollama
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+func TestChatModelConfig_Validate(t *testing.T) {
+	convey.Convey("test ChatModelConfig.Validate", t, func() {
+		convey.Convey("valid", func() {
+			err := (&ChatModelConfig{
+				BaseURL:  "http://localhost:11434",
+				Model:    "llama3",
+				Format:   json.RawMessage(`"json"`),
+				Options:  &Options{Temperature: 0.7, NumPredict: -1},
+				Thinking: &ThinkValue{Value: "high"},
+			}).Validate()
+			convey.So(err, convey.ShouldBeNil)
+		})
+
+		convey.Convey("invalid", func() {
+			err := (&ChatModelConfig{
+				BaseURL:  "http://[::1",
+				Format:   json.RawMessage("json"),
+				Options:  &Options{TopP: 2, NumPredict: -3},
+				Thinking: &ThinkValue{Value: "max"},
+			}).Validate()
+			report, ok := err.(*configcheck.Report)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(report.Config, convey.ShouldEqual, "ollama.ChatModelConfig")
+			convey.So(report.Issues, convey.ShouldResemble, []configcheck.Issue{
+				{Field: "BaseURL", Message: `parse "http://[::1": missing ']' in host`},
+				{Field: "Model", Message: "model is required"},
+				{Field: "Format", Message: `must be "json" or a JSON schema, got json`},
+				{Field: "Options.TopP", Message: "must be in [0, 1], got 2"},
+				{Field: "Options.NumPredict", Message: "must not be less than -2, got -3"},
+				{Field: "Thinking", Message: `must be a bool or one of "high", "medium" and "low", got max`},
+			})
+		})
+	})
+}
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+func TestChatModelConfig_Validate(t *testing.T) {
+	if err := (&ChatModelConfig{Model: "gpt-4o"}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	maxTokens, temperature := 0, float32(2.5)
+	err := (&ChatModelConfig{
+		ByAzure:         true,
+		MaxTokens:       &maxTokens,
+		Temperature:     &temperature,
+		ResponseFormat:  &ChatCompletionResponseFormat{Type: ChatCompletionResponseFormatTypeJSONSchema},
+		ReasoningEffort: "max",
+		Modalities:      []Modality{TextModality, AudioModality, "video"},
+	}).Validate()
+	report, ok := err.(*configcheck.Report)
+	if !ok {
+		t.Fatalf("got %T, want *configcheck.Report", err)
+	}
+	if report.Config != "openai.ChatModelConfig" {
+		t.Errorf("got config %q", report.Config)
+	}
+	want := []configcheck.Issue{
+		{Field: "Model", Message: "model is required"},
+		{Field: "BaseURL", Message: "required by Azure"},
+		{Field: "MaxTokens", Message: "must be positive, got 0"},
+		{Field: "Temperature", Message: "must be in [0, 2], got 2.5"},
+		{Field: "ResponseFormat.JSONSchema", Message: `required by the "json_schema" response format`},
+		{Field: "ReasoningEffort", Message: `unsupported reasoning effort "max"`},
+		{Field: "Modalities", Message: `unsupported modality "video"`},
+		{Field: "Audio", Message: `required by the "audio" modality`},
+	}
+	if !reflect.DeepEqual(report.Issues, want) {
+		t.Errorf("got issues %v, want %v", report.Issues, want)
+	}
+}
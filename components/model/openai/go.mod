@@ -7,6 +7,7 @@ require (
 	github.com/bytedance/sonic v1.14.1
 	github.com/cloudwego/eino v0.7.13
 	github.com/cloudwego/eino-ext/libs/acl/openai v0.1.14
+	github.com/cloudwego/eino-ext/libs/configcheck v0.1.0
 	github.com/eino-contrib/jsonschema v1.0.3
 	github.com/meguminnnnnnnnn/go-openai v0.1.1
 	github.com/wk8/go-ordered-map/v2 v2.1.8
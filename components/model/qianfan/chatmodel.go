@@ -17,14 +17,16 @@
 package qianfan
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"runtime/debug"
-	"strings"
 
 	"github.com/baidubce/bce-qianfan-sdk/go/qianfan"
 
+	"github.com/cloudwego/eino-ext/libs/multimodal"
+	"github.com/cloudwego/eino-ext/libs/pii"
 	"github.com/cloudwego/eino/components"
 
 	"github.com/cloudwego/eino/callbacks"
@@ -58,6 +60,13 @@ type ChatModelConfig struct {
 	// LLMRetryBackoffFactor is the backoff factor for retries.
 	LLMRetryBackoffFactor *float32
 
+	// RateLimitRetry enables retrying the requests throttled by Qianfan with a jittered exponential backoff,
+	// failing with a *RateLimitedError when the attempts are exhausted. Streams are only retried before the first chunk.
+	// The retries of the SDK, see LLMRetryCount, are kept, so the throttled requests are retried by
+	// RateLimitRetry only once the SDK gives up, and the attempts multiply if both retry the throttling errors.
+	// Optional. Default is not to retry.
+	RateLimitRetry *RateLimitRetryConfig
+
 	// Temperature controls the randomness of the output. A higher value makes the output more random, while a lower value makes it more focused and deterministic. Default is 0.95, range (0, 1.0].
 	Temperature *float32
 
@@ -90,6 +99,29 @@ type ChatModelConfig struct {
 
 	// ResponseFormat specifies the format of the response.
 	ResponseFormat *ResponseFormat
+
+	// EnableWebSearch enables the built-in web search of the models supporting it,
+	// the search results are returned in the Extra of the message, see GetSearchResults. Defaults to disabled.
+	EnableWebSearch *WebSearchConfig
+
+	// StrictOrdering fails the requests whose messages are in an order rejected by Qianfan with a *MessageOrderError.
+	// Set it to false to fix them up instead: the system messages are merged and moved to the front,
+	// and the consecutive user messages are merged. The orders which cannot be fixed,
+	// e.g. a tool message not following an assistant message with tool calls, always fail. Defaults to true.
+	StrictOrdering *bool
+
+	// MediaLimits, if set, fails the input messages violating it before the request is sent, see multimodal.Limits.
+	// Defaults to no limits.
+	MediaLimits *multimodal.Limits
+
+	// Interceptor processes the input messages before they are sent and the output messages before they are returned,
+	// e.g. a *pii.Redactor replacing the personally identifiable information with placeholders restored in the response.
+	// The callbacks see the intercepted input and output messages. Defaults to none.
+	Interceptor pii.Interceptor
+
+	// EnableCallbacks is false to leave the callbacks to the graph running the model,
+	// which then reports them without the Qianfan request config. Defaults to true.
+	EnableCallbacks *bool
 }
 
 type ChatModel struct {
@@ -98,6 +130,7 @@ type ChatModel struct {
 	tools      []qianfan.Tool
 	toolChoice *schema.ToolChoice
 	config     *ChatModelConfig
+	retry      *RateLimitRetryConfig
 }
 
 type image struct {
@@ -153,9 +186,14 @@ func NewChatModel(ctx context.Context, config *ChatModelConfig) (*ChatModel, err
 		config.ParallelToolCalls = of(defaultParallelToolCalls)
 	}
 
+	var retry *RateLimitRetryConfig
+	if config.RateLimitRetry != nil {
+		retry = config.RateLimitRetry.withDefaults()
+	}
+
 	cc := qianfan.NewChatCompletionV2(opts...)
 
-	return &ChatModel{cc, nil, nil, nil, config}, nil
+	return &ChatModel{cc, nil, nil, nil, config, retry}, nil
 }
 
 func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (
@@ -163,19 +201,38 @@ func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts
 
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
 
+	input, restorer, err := pii.Apply(ctx, cm.config.Interceptor, input)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			outMsg = restorer.Restore(outMsg)
+		}
+	}()
+
 	req, cbInput, err := cm.genRequest(input, false, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx = callbacks.OnStart(ctx, cbInput)
+	if cm.IsCallbacksEnabled() {
+		ctx = callbacks.OnStart(ctx, cbInput)
+	}
 	defer func() {
-		if err != nil {
+		if err != nil && cm.IsCallbacksEnabled() {
 			callbacks.OnError(ctx, err)
 		}
 	}()
 
-	r, err := cm.cc.Do(ctx, req)
+	var r *qianfan.ChatCompletionV2Response
+	err = retryRateLimited(ctx, cm.retry, func() (err error) {
+		r, err = cm.cc.Do(ctx, req)
+		if err != nil {
+			return err
+		}
+		return cm.throttledRespErr(r)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("[qianfan][Generate] ChatCompletionV2 error, %w", err)
 	}
@@ -185,11 +242,13 @@ func (cm *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts
 		return nil, fmt.Errorf("[qianfan][Generate] resolve resp failed, %w", err)
 	}
 
-	ctx = callbacks.OnEnd(ctx, &model.CallbackOutput{
-		Message:    outMsg,
-		Config:     cbInput.Config,
-		TokenUsage: toModelCallbackUsage(outMsg),
-	})
+	if cm.IsCallbacksEnabled() {
+		ctx = callbacks.OnEnd(ctx, &model.CallbackOutput{
+			Message:    outMsg,
+			Config:     cbInput.Config,
+			TokenUsage: toModelCallbackUsage(outMsg),
+		})
+	}
 
 	return outMsg, nil
 }
@@ -199,19 +258,35 @@ func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts .
 
 	ctx = callbacks.EnsureRunInfo(ctx, cm.GetType(), components.ComponentOfChatModel)
 
+	input, restorer, err := pii.Apply(ctx, cm.config.Interceptor, input)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err == nil {
+			outStream = restorer.RestoreStream(outStream)
+		}
+	}()
+
 	req, cbInput, err := cm.genRequest(input, true, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	ctx = callbacks.OnStart(ctx, cbInput)
+	if cm.IsCallbacksEnabled() {
+		ctx = callbacks.OnStart(ctx, cbInput)
+	}
 	defer func() {
-		if err != nil {
+		if err != nil && cm.IsCallbacksEnabled() {
 			callbacks.OnError(ctx, err)
 		}
 	}()
 
-	r, err := cm.cc.Stream(ctx, req)
+	var r *qianfan.ChatCompletionV2ResponseStream
+	err = retryRateLimited(ctx, cm.retry, func() (err error) {
+		r, err = cm.cc.Stream(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("[qianfan][Stream] ChatCompletionV2 error, %w", err)
 	}
@@ -255,11 +330,14 @@ func (cm *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts .
 
 	}()
 
-	ctx, nsr := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderWithConvert(
+	nsr := schema.StreamReaderWithConvert(
 		sr, func(src *model.CallbackOutput) (callbacks.CallbackOutput, error) {
 			return src, nil
 		},
-	))
+	)
+	if cm.IsCallbacksEnabled() {
+		_, nsr = callbacks.OnEndWithStreamOutput(ctx, nsr)
+	}
 
 	outStream = schema.StreamReaderWithConvert(nsr,
 		func(src callbacks.CallbackOutput) (*schema.Message, error) {
@@ -325,6 +403,14 @@ func (cm *ChatModel) BindForcedTools(tools []*schema.ToolInfo) error {
 func (cm *ChatModel) genRequest(input []*schema.Message, isStream bool, opts ...model.Option) (
 	*qianfan.ChatCompletionV2Request, *model.CallbackInput, error) {
 
+	if err := cm.config.MediaLimits.ValidateMessages(input); err != nil {
+		return nil, nil, err
+	}
+	input, err := normalizeMessageOrder(input, cm.config.StrictOrdering != nil && !*cm.config.StrictOrdering)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	options := model.GetCommonOptions(&model.Options{
 		Temperature: cm.config.Temperature,
 		MaxTokens:   cm.config.MaxCompletionTokens,
@@ -349,7 +435,6 @@ func (cm *ChatModel) genRequest(input []*schema.Message, isStream bool, opts ...
 
 	tools := cm.tools
 	if options.Tools != nil {
-		var err error
 		if tools, err = toQianfanTools(options.Tools); err != nil {
 			return nil, nil, err
 		}
@@ -379,9 +464,13 @@ func (cm *ChatModel) genRequest(input []*schema.Message, isStream bool, opts ...
 		return nil, nil, err
 	}
 
-	req.SetExtra(map[string]interface{}{
+	extra := map[string]interface{}{
 		"messages": messages,
-	})
+	}
+	if cm.config.EnableWebSearch != nil {
+		extra["web_search"] = toWebSearchParam(cm.config.EnableWebSearch)
+	}
+	req.SetExtra(extra)
 
 	if isStream {
 		req.StreamOptions = &qianfan.StreamOptions{IncludeUsage: true}
@@ -498,13 +587,6 @@ func toQianfanMultiModalMessages(input []*schema.Message) ([]chatCompletionV3Mes
 	return messages, nil
 }
 
-func validateBase64Data(data string) error {
-	if strings.HasPrefix(data, "data:") {
-		return errors.New("base64 data represents the binary data in Base64 encoded string format, cannot start with 'data:'")
-	}
-	return nil
-}
-
 func toUserInputMultiContentParts(inMsg *schema.Message) ([]contentPart, error) {
 	if inMsg.Role == schema.Assistant {
 		return nil, errors.New("invalid role for UserInputMultiContent: role must not be 'assistant'")
@@ -534,12 +616,11 @@ func toUserInputMultiContentParts(inMsg *schema.Message) ([]contentPart, error)
 					return nil, errors.New("MIME type is required for base64-encoded image data")
 				}
 
-				err := validateBase64Data(*mm.Image.Base64Data)
+				dataURL, err := multimodal.DataURL(*mm.Image.Base64Data, mm.Image.MIMEType)
 				if err != nil {
 					return nil, err
 				}
-
-				part.ImageURL.URL = fmt.Sprintf("data:%s;base64,%s", mm.Image.MIMEType, *mm.Image.Base64Data)
+				part.ImageURL.URL = dataURL
 			} else {
 				return nil, errors.New("image message part must have url or base64 data")
 			}
@@ -564,12 +645,11 @@ func toUserInputMultiContentParts(inMsg *schema.Message) ([]contentPart, error)
 				if mm.Video.MIMEType == "" {
 					return nil, errors.New("MIME type is required for base64-encoded video data")
 				}
-				err := validateBase64Data(*mm.Video.Base64Data)
+				dataURL, err := multimodal.DataURL(*mm.Video.Base64Data, mm.Video.MIMEType)
 				if err != nil {
 					return nil, err
 				}
-
-				part.VideoURL.URL = fmt.Sprintf("data:%s;base64,%s", mm.Video.MIMEType, *mm.Video.Base64Data)
+				part.VideoURL.URL = dataURL
 			} else {
 				return nil, errors.New("video message part must have url or base64 data")
 			}
@@ -610,12 +690,11 @@ func toAssistantGenMultiContentParts(inMsg *schema.Message) ([]contentPart, erro
 					return nil, errors.New("MIME type is required for base64-encoded image data")
 				}
 
-				err := validateBase64Data(*mm.Image.Base64Data)
+				dataURL, err := multimodal.DataURL(*mm.Image.Base64Data, mm.Image.MIMEType)
 				if err != nil {
 					return nil, err
 				}
-
-				part.ImageURL.URL = fmt.Sprintf("data:%s;base64,%s", mm.Image.MIMEType, *mm.Image.Base64Data)
+				part.ImageURL.URL = dataURL
 			} else {
 				return nil, errors.New("image message part must have url or base64 data")
 			}
@@ -640,12 +719,11 @@ func toAssistantGenMultiContentParts(inMsg *schema.Message) ([]contentPart, erro
 				if mm.Video.MIMEType == "" {
 					return nil, errors.New("MIME type is required for base64-encoded video data")
 				}
-				err := validateBase64Data(*mm.Video.Base64Data)
+				dataURL, err := multimodal.DataURL(*mm.Video.Base64Data, mm.Video.MIMEType)
 				if err != nil {
 					return nil, err
 				}
-
-				part.VideoURL.URL = *mm.Video.Base64Data
+				part.VideoURL.URL = dataURL
 			} else {
 				return nil, errors.New("video message part must have url or base64 data")
 			}
@@ -657,10 +735,24 @@ func toAssistantGenMultiContentParts(inMsg *schema.Message) ([]contentPart, erro
 	return parts, nil
 }
 
+func respErr(e *qianfan.ChatCompletionV2Error) error {
+	return fmt.Errorf("[resolveQianfanResponse] resp with err: code=%s, msg=%s, type=%s", e.Code, e.Message, e.Type)
+}
+
+// throttledRespErr returns the error of the response if it is a throttling error to retry, see ChatModelConfig.RateLimitRetry.
+func (cm *ChatModel) throttledRespErr(resp *qianfan.ChatCompletionV2Response) error {
+	if cm.retry == nil || resp == nil || resp.Error == nil {
+		return nil
+	}
+	if err := respErr(resp.Error); cm.retry.IsRateLimited(err) {
+		return withRetryAfter(err, resp.GetResponse())
+	}
+	return nil
+}
+
 func resolveQianfanResponse(resp *qianfan.ChatCompletionV2Response) (*schema.Message, error) {
 	if resp.Error != nil {
-		return nil, fmt.Errorf("[resolveQianfanResponse] resp with err: code=%s, msg=%s, type=%s",
-			resp.Error.Code, resp.Error.Message, resp.Error.Type)
+		return nil, respErr(resp.Error)
 	}
 
 	if len(resp.Choices) == 0 {
@@ -705,6 +797,8 @@ func resolveQianfanResponse(resp *qianfan.ChatCompletionV2Response) (*schema.Mes
 		return nil, fmt.Errorf("unsupported role from qianfan: %s", choice.Message.Role)
 	}
 
+	setSearchResults(msg, resp.Body)
+
 	return msg, nil
 }
 
@@ -762,7 +856,7 @@ func (cm *ChatModel) GetType() string {
 }
 
 func (cm *ChatModel) IsCallbacksEnabled() bool {
-	return true
+	return cm.config == nil || cm.config.EnableCallbacks == nil || *cm.config.EnableCallbacks
 }
 
 type panicErr struct {
@@ -784,8 +878,7 @@ func newPanicErr(info any, stack []byte) error {
 func resolveQianfanStreamResponse(resp *qianfan.ChatCompletionV2Response) (
 	msg *schema.Message, found bool, err error) {
 	if resp.Error != nil {
-		return nil, false, fmt.Errorf("[resolveQianfanResponse] resp with err: code=%s, msg=%s, type=%s",
-			resp.Error.Code, resp.Error.Message, resp.Error.Type)
+		return nil, false, respErr(resp.Error)
 	}
 
 	for _, choice := range resp.Choices {
@@ -815,6 +908,12 @@ func resolveQianfanStreamResponse(resp *qianfan.ChatCompletionV2Response) (
 		}
 	}
 
+	if !found && bytes.Contains(resp.Body, []byte(`"search_results"`)) {
+		found = true
+		msg = &schema.Message{Role: schema.Assistant}
+	}
+	setSearchResults(msg, resp.Body)
+
 	return msg, found, nil
 }
 
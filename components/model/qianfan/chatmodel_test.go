@@ -26,6 +26,8 @@ import (
 	"github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cloudwego/eino-ext/libs/multimodal"
+	"github.com/cloudwego/eino/callbacks"
 	fmodel "github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 )
@@ -181,6 +183,44 @@ func Test_Generate(t *testing.T) {
 			convey.So(outMsg.Role, convey.ShouldEqual, schema.Assistant)
 			convey.So(len(outMsg.ToolCalls), convey.ShouldEqual, 1)
 		})
+
+		PatchConvey("test disable callbacks", func() {
+			Mock(GetMethod(cli, "Do")).Return(
+				&qianfan.ChatCompletionV2Response{
+					Choices: []qianfan.ChatCompletionV2Choice{
+						{
+							Index:   0,
+							Message: qianfan.ChatCompletionV2Message{Role: "assistant", Content: "test_content"},
+						},
+					},
+				}, nil).Build()
+
+			var started, ended int
+			handler := callbacks.NewHandlerBuilder().
+				OnStartFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackInput) context.Context {
+					started++
+					return ctx
+				}).
+				OnEndFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackOutput) context.Context {
+					ended++
+					return ctx
+				}).Build()
+			cbCtx := callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, handler)
+
+			m.config.EnableCallbacks = of(false)
+			outMsg, err := m.Generate(cbCtx, msgs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(outMsg.Content, convey.ShouldEqual, "test_content")
+			convey.So(started, convey.ShouldEqual, 0)
+			convey.So(ended, convey.ShouldEqual, 0)
+			convey.So(m.IsCallbacksEnabled(), convey.ShouldBeFalse)
+
+			m.config.EnableCallbacks = nil
+			_, err = m.Generate(cbCtx, msgs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(started, convey.ShouldEqual, 1)
+			convey.So(ended, convey.ShouldEqual, 1)
+		})
 	})
 }
 
@@ -577,3 +617,27 @@ func TestPopulateToolChoice(t *testing.T) {
 		convey.So(err.Error(), convey.ShouldEqual, "[qianfan][genRequest] tool choice=unsupported not support")
 	})
 }
+
+func TestMediaLimits(t *testing.T) {
+	cm := &ChatModel{config: &ChatModelConfig{
+		Model:       "test",
+		MediaLimits: &multimodal.Limits{MaxPartsPerMessage: 1, AllowedMIMETypes: []string{"image/*"}},
+	}}
+
+	_, _, err := cm.genRequest([]*schema.Message{
+		{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeText, Text: "describe"},
+			{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: "https://example.com/a.png"}},
+		}},
+	}, false)
+	assert.ErrorContains(t, err, "messages[0].multi_content: 2 content parts exceed the limit of 1")
+
+	_, _, err = cm.genRequest([]*schema.Message{
+		{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeVideoURL, VideoURL: &schema.ChatMessageVideoURL{URL: "https://example.com/a.mp4", MIMEType: "video/mp4"}},
+		}},
+	}, false)
+	var ve *multimodal.ValidationError
+	assert.True(t, errors.As(err, &ve))
+	assert.Equal(t, "messages[0].multi_content[0].video_url", ve.Field)
+}
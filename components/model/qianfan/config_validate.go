@@ -0,0 +1,47 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+// Validate checks the config without creating a client, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid.
+func (c *ChatModelConfig) Validate() error {
+	r := configcheck.New("qianfan.ChatModelConfig")
+	configcheck.NonNegative(r, "LLMRetryCount", c.LLMRetryCount)
+	configcheck.Positive(r, "LLMRetryTimeout", c.LLMRetryTimeout)
+	configcheck.NonNegative(r, "LLMRetryBackoffFactor", c.LLMRetryBackoffFactor)
+	if c.RateLimitRetry != nil {
+		configcheck.NonNegative(r, "RateLimitRetry.MaxAttempts", &c.RateLimitRetry.MaxAttempts)
+		configcheck.NonNegative(r, "RateLimitRetry.InitialBackoff", &c.RateLimitRetry.InitialBackoff)
+		configcheck.NonNegative(r, "RateLimitRetry.MaxBackoff", &c.RateLimitRetry.MaxBackoff)
+	}
+	if c.Temperature != nil {
+		r.Checkf(*c.Temperature > 0 && *c.Temperature <= 1, "Temperature", "must be in (0, 1], got %v", *c.Temperature)
+	}
+	configcheck.InRange(r, "TopP", c.TopP, 0, 1)
+	configcheck.InRange(r, "PenaltyScore", c.PenaltyScore, 1, 2)
+	configcheck.Positive(r, "MaxCompletionTokens", c.MaxCompletionTokens)
+	if c.Seed != nil {
+		r.Checkf(*c.Seed > 0 && *c.Seed < 2147483647, "Seed", "must be in (0, 2147483647), got %d", *c.Seed)
+	}
+	configcheck.InRange(r, "FrequencyPenalty", c.FrequencyPenalty, -2, 2)
+	configcheck.InRange(r, "PresencePenalty", c.PresencePenalty, -2, 2)
+	return r.Err()
+}
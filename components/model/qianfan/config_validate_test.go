@@ -0,0 +1,48 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatModelConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&ChatModelConfig{Model: "ernie-3.5-8k"}).Validate())
+
+	temperature, penaltyScore, maxTokens, seed := float32(0), 0.5, 0, 0
+	err := (&ChatModelConfig{
+		RateLimitRetry:      &RateLimitRetryConfig{MaxBackoff: -time.Second},
+		Temperature:         &temperature,
+		PenaltyScore:        &penaltyScore,
+		MaxCompletionTokens: &maxTokens,
+		Seed:                &seed,
+	}).Validate()
+	report, ok := err.(*configcheck.Report)
+	assert.True(t, ok)
+	assert.Equal(t, "qianfan.ChatModelConfig", report.Config)
+	assert.Equal(t, []configcheck.Issue{
+		{Field: "RateLimitRetry.MaxBackoff", Message: "must not be negative, got -1s"},
+		{Field: "Temperature", Message: "must be in (0, 1], got 0"},
+		{Field: "PenaltyScore", Message: "must be in [1, 2], got 0.5"},
+		{Field: "MaxCompletionTokens", Message: "must be positive, got 0"},
+		{Field: "Seed", Message: "must be in (0, 2147483647), got 0"},
+	}, report.Issues)
+}
@@ -6,6 +6,9 @@ require (
 	github.com/baidubce/bce-qianfan-sdk/go/qianfan v0.0.14
 	github.com/bytedance/mockey v1.2.13
 	github.com/cloudwego/eino v0.7.13
+	github.com/cloudwego/eino-ext/libs/configcheck v0.1.0
+	github.com/cloudwego/eino-ext/libs/multimodal v0.2.0
+	github.com/cloudwego/eino-ext/libs/pii v0.1.1
 	github.com/smartystreets/goconvey v1.8.1
 	github.com/stretchr/testify v1.10.0
 )
@@ -22,6 +25,7 @@ require (
 	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/eino-contrib/jsonschema v1.0.3 // indirect
 	github.com/fsnotify/fsnotify v1.7.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
 	github.com/goph/emperror v0.17.2 // indirect
 	github.com/gopherjs/gopherjs v1.17.2 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
@@ -0,0 +1,416 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/baidubce/bce-qianfan-sdk/go/qianfan"
+
+	"github.com/cloudwego/eino-ext/libs/multimodal"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultImageBaseURL = "https://qianfan.baidubce.com/v2"
+	defaultImageTimeout = 2 * time.Minute
+	imageGenerationPath = "/images/generations"
+
+	// defaultImageMIMEType is used when the MIME type of a base64-encoded image cannot be detected
+	defaultImageMIMEType = "image/png"
+)
+
+// ImageGenerationConfig config for qianfan image generation, e.g. the iRAG models.
+type ImageGenerationConfig struct {
+	// APIKey is the API key of the Qianfan v2 API, sent as the bearer token.
+	// Required.
+	APIKey string
+
+	// Model is the image generation model to use, e.g. "irag-1.0".
+	// Required.
+	Model string
+
+	// BaseURL is the base URL of the Qianfan v2 API.
+	// Optional. Default is "https://qianfan.baidubce.com/v2".
+	BaseURL string
+
+	// HTTPClient specifies the client to send HTTP requests.
+	// Optional. Default is a client with a 2 minutes timeout.
+	HTTPClient *http.Client
+
+	// Size is the size of the generated images, e.g. "1024x1024".
+	// Optional. Default is decided by the model.
+	Size string
+
+	// N is the number of images to generate.
+	// Optional. Default is 1.
+	N *int
+
+	// RateLimitRetry enables retrying the requests throttled by Qianfan, see ChatModelConfig.RateLimitRetry.
+	// Optional. Default is not to retry.
+	RateLimitRetry *RateLimitRetryConfig
+
+	// EnableCallbacks, see ChatModelConfig.EnableCallbacks. Defaults to true.
+	EnableCallbacks *bool
+}
+
+// ImageGenerationModel generates images with the Qianfan image generation models, e.g. the iRAG models.
+// The prompt is built from the text of the system and user messages, and the first image of the user messages
+// is sent as the reference image. The images are returned as the image parts of AssistantGenMultiContent,
+// which can be sent back to the ChatModel in the conversation history.
+type ImageGenerationModel struct {
+	config  *ImageGenerationConfig
+	baseURL string
+	cli     *http.Client
+	retry   *RateLimitRetryConfig
+}
+
+type imageGenerationRequest struct {
+	Model      string `json:"model"`
+	Prompt     string `json:"prompt"`
+	N          *int   `json:"n,omitempty"`
+	Size       string `json:"size,omitempty"`
+	ReferImage string `json:"refer_image,omitempty"`
+}
+
+type imageGenerationData struct {
+	URL     string `json:"url,omitempty"`
+	B64JSON string `json:"b64_json,omitempty"`
+}
+
+type imageGenerationResponse struct {
+	ID      string                         `json:"id"`
+	Created int64                          `json:"created"`
+	Data    []imageGenerationData          `json:"data"`
+	Error   *qianfan.ChatCompletionV2Error `json:"error,omitempty"`
+}
+
+var _ model.BaseChatModel = (*ImageGenerationModel)(nil)
+
+func NewImageGenerationModel(_ context.Context, config *ImageGenerationConfig) (*ImageGenerationModel, error) {
+	if config == nil {
+		return nil, errors.New("[qianfan] image generation model requires config")
+	}
+	if config.APIKey == "" {
+		return nil, errors.New("[qianfan] image generation model requires APIKey")
+	}
+	if config.Model == "" {
+		return nil, errors.New("[qianfan] image generation model requires Model")
+	}
+
+	baseURL := defaultImageBaseURL
+	if config.BaseURL != "" {
+		baseURL = strings.TrimSuffix(config.BaseURL, "/")
+	}
+	cli := config.HTTPClient
+	if cli == nil {
+		cli = &http.Client{Timeout: defaultImageTimeout}
+	}
+	var retry *RateLimitRetryConfig
+	if config.RateLimitRetry != nil {
+		retry = config.RateLimitRetry.withDefaults()
+	}
+
+	return &ImageGenerationModel{
+		config:  config,
+		baseURL: baseURL,
+		cli:     cli,
+		retry:   retry,
+	}, nil
+}
+
+func (im *ImageGenerationModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (
+	outMsg *schema.Message, err error) {
+
+	ctx = callbacks.EnsureRunInfo(ctx, im.GetType(), components.ComponentOfChatModel)
+
+	req, cbInput, err := im.genRequest(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if im.IsCallbacksEnabled() {
+		ctx = callbacks.OnStart(ctx, cbInput)
+	}
+	defer func() {
+		if err != nil && im.IsCallbacksEnabled() {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	var resp *imageGenerationResponse
+	err = retryRateLimited(ctx, im.retry, func() (err error) {
+		resp, err = im.generateImages(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[qianfan][ImageGeneration] generate images error, %w", err)
+	}
+
+	outMsg, err = resolveImageGenerationResponse(resp)
+	if err != nil {
+		return nil, fmt.Errorf("[qianfan][ImageGeneration] resolve resp failed, %w", err)
+	}
+
+	if im.IsCallbacksEnabled() {
+		callbacks.OnEnd(ctx, &model.CallbackOutput{
+			Message: outMsg,
+			Config:  cbInput.Config,
+		})
+	}
+
+	return outMsg, nil
+}
+
+// Stream generates the images as Generate does and returns them in a single chunk,
+// as the image generation API does not stream.
+func (im *ImageGenerationModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (
+	outStream *schema.StreamReader[*schema.Message], err error) {
+
+	ctx = callbacks.EnsureRunInfo(ctx, im.GetType(), components.ComponentOfChatModel)
+
+	req, cbInput, err := im.genRequest(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if im.IsCallbacksEnabled() {
+		ctx = callbacks.OnStart(ctx, cbInput)
+	}
+	defer func() {
+		if err != nil && im.IsCallbacksEnabled() {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	var resp *imageGenerationResponse
+	err = retryRateLimited(ctx, im.retry, func() (err error) {
+		resp, err = im.generateImages(ctx, req)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("[qianfan][ImageGeneration] generate images error, %w", err)
+	}
+
+	msg, err := resolveImageGenerationResponse(resp)
+	if err != nil {
+		return nil, fmt.Errorf("[qianfan][ImageGeneration] resolve resp failed, %w", err)
+	}
+
+	nsr := schema.StreamReaderFromArray([]callbacks.CallbackOutput{&model.CallbackOutput{
+		Message: msg,
+		Config:  cbInput.Config,
+	}})
+	if im.IsCallbacksEnabled() {
+		_, nsr = callbacks.OnEndWithStreamOutput(ctx, nsr)
+	}
+
+	outStream = schema.StreamReaderWithConvert(nsr,
+		func(src callbacks.CallbackOutput) (*schema.Message, error) {
+			s := src.(*model.CallbackOutput)
+			if s.Message == nil {
+				return nil, schema.ErrNoValue
+			}
+
+			return s.Message, nil
+		},
+	)
+
+	return outStream, nil
+}
+
+func (im *ImageGenerationModel) genRequest(input []*schema.Message, opts ...model.Option) (
+	*imageGenerationRequest, *model.CallbackInput, error) {
+
+	options := model.GetCommonOptions(&model.Options{
+		Model: &im.config.Model,
+	}, opts...)
+
+	prompt, referImage, err := toImagePromptAndReferImage(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &imageGenerationRequest{
+		Model:      dereferenceOrZero(options.Model),
+		Prompt:     prompt,
+		N:          im.config.N,
+		Size:       im.config.Size,
+		ReferImage: referImage,
+	}
+
+	cbInput := &model.CallbackInput{
+		Messages: input,
+		Config: &model.Config{
+			Model: req.Model,
+		},
+	}
+
+	return req, cbInput, nil
+}
+
+// toImagePromptAndReferImage joins the text of the system and user messages into the prompt,
+// and returns the first image of the user messages as the reference image.
+func toImagePromptAndReferImage(input []*schema.Message) (prompt string, referImage string, err error) {
+	var texts []string
+	for _, msg := range input {
+		if msg.Role != schema.System && msg.Role != schema.User {
+			continue
+		}
+		if msg.Content != "" {
+			texts = append(texts, msg.Content)
+		}
+		for _, part := range msg.UserInputMultiContent {
+			switch part.Type {
+			case schema.ChatMessagePartTypeText:
+				if part.Text != "" {
+					texts = append(texts, part.Text)
+				}
+			case schema.ChatMessagePartTypeImageURL:
+				if referImage != "" || part.Image == nil {
+					continue
+				}
+				if part.Image.URL != nil {
+					referImage = *part.Image.URL
+				} else if part.Image.Base64Data != nil {
+					if part.Image.MIMEType == "" {
+						return "", "", errors.New("MIME type is required for base64-encoded image data")
+					}
+					referImage, err = multimodal.DataURL(*part.Image.Base64Data, part.Image.MIMEType)
+					if err != nil {
+						return "", "", err
+					}
+				}
+			}
+		}
+	}
+
+	if len(texts) == 0 {
+		return "", "", errors.New("[qianfan][ImageGeneration] prompt is empty")
+	}
+	return strings.Join(texts, "\n"), referImage, nil
+}
+
+func (im *ImageGenerationModel) generateImages(ctx context.Context, req *imageGenerationRequest) (*imageGenerationResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request failed: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, im.baseURL+imageGenerationPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+im.config.APIKey)
+
+	httpResp, err := im.cli.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response failed: %w", err)
+	}
+
+	resp := &imageGenerationResponse{}
+	if err = json.Unmarshal(respBody, resp); err != nil {
+		if httpResp.StatusCode != http.StatusOK {
+			return nil, withRetryAfter(fmt.Errorf("unexpected status code %d: %s", httpResp.StatusCode, string(respBody)), httpResp)
+		}
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, withRetryAfter(respErr(resp.Error), httpResp)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, withRetryAfter(fmt.Errorf("unexpected status code %d: %s", httpResp.StatusCode, string(respBody)), httpResp)
+	}
+
+	return resp, nil
+}
+
+func resolveImageGenerationResponse(resp *imageGenerationResponse) (*schema.Message, error) {
+	parts := make([]schema.MessageOutputPart, 0, len(resp.Data))
+	for _, data := range resp.Data {
+		img := &schema.MessageOutputImage{}
+		switch {
+		case data.URL != "":
+			url := data.URL
+			img.URL = &url
+		case data.B64JSON != "":
+			b64 := data.B64JSON
+			img.Base64Data = &b64
+			img.MIMEType = detectImageMIMEType(b64)
+		default:
+			continue
+		}
+		parts = append(parts, schema.MessageOutputPart{
+			Type:  schema.ChatMessagePartTypeImageURL,
+			Image: img,
+		})
+	}
+
+	if len(parts) == 0 {
+		return nil, errors.New("image data is empty")
+	}
+
+	return &schema.Message{
+		Role:                     schema.Assistant,
+		AssistantGenMultiContent: parts,
+	}, nil
+}
+
+// detectImageMIMEType detects the MIME type of the base64-encoded image,
+// which is required to send the image back in the conversation history.
+func detectImageMIMEType(b64 string) string {
+	// 684 base64 characters decode to the 512 bytes used by the content sniffing
+	prefix := b64
+	if len(prefix) > 684 {
+		prefix = prefix[:684]
+	}
+	data, err := base64.StdEncoding.DecodeString(prefix[:len(prefix)/4*4])
+	if err != nil {
+		return defaultImageMIMEType
+	}
+	if mimeType := multimodal.SniffMIMEType(data); strings.HasPrefix(mimeType, "image/") {
+		return mimeType
+	}
+	return defaultImageMIMEType
+}
+
+func (im *ImageGenerationModel) GetType() string {
+	return getType()
+}
+
+func (im *ImageGenerationModel) IsCallbacksEnabled() bool {
+	return im.config.EnableCallbacks == nil || *im.config.EnableCallbacks
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// pngHeader is the signature of a PNG image, enough to detect its MIME type
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newImageGenerationServer(t *testing.T, handler func(req *imageGenerationRequest) (int, string)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, imageGenerationPath, r.URL.Path)
+		assert.Equal(t, "Bearer my-api-key", r.Header.Get("Authorization"))
+		req := &imageGenerationRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+		code, body := handler(req)
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestImageGenerationModel(t *testing.T) {
+	ctx := context.Background()
+	b64 := base64.StdEncoding.EncodeToString(pngHeader)
+
+	t.Run("generate", func(t *testing.T) {
+		var got *imageGenerationRequest
+		srv := newImageGenerationServer(t, func(req *imageGenerationRequest) (int, string) {
+			got = req
+			return http.StatusOK, `{"id":"1","data":[{"url":"https://example.com/a.jpg"},{"b64_json":"` + b64 + `"}]}`
+		})
+		defer srv.Close()
+
+		n := 2
+		im, err := NewImageGenerationModel(ctx, &ImageGenerationConfig{
+			APIKey:  "my-api-key",
+			Model:   "irag-1.0",
+			BaseURL: srv.URL,
+			Size:    "1024x1024",
+			N:       &n,
+		})
+		assert.NoError(t, err)
+
+		referURL := "https://example.com/ref.jpg"
+		msg, err := im.Generate(ctx, []*schema.Message{
+			schema.SystemMessage("watercolor style"),
+			{
+				Role: schema.User,
+				UserInputMultiContent: []schema.MessageInputPart{
+					{Type: schema.ChatMessagePartTypeText, Text: "a cat"},
+					{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{
+						MessagePartCommon: schema.MessagePartCommon{URL: &referURL},
+					}},
+				},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &imageGenerationRequest{
+			Model:      "irag-1.0",
+			Prompt:     "watercolor style\na cat",
+			N:          &n,
+			Size:       "1024x1024",
+			ReferImage: referURL,
+		}, got)
+
+		assert.Equal(t, schema.Assistant, msg.Role)
+		assert.Len(t, msg.AssistantGenMultiContent, 2)
+		assert.Equal(t, "https://example.com/a.jpg", *msg.AssistantGenMultiContent[0].Image.URL)
+		assert.Equal(t, b64, *msg.AssistantGenMultiContent[1].Image.Base64Data)
+		assert.Equal(t, "image/png", msg.AssistantGenMultiContent[1].Image.MIMEType)
+
+		// the generated images can be sent back to the chat model in the conversation history
+		messages, err := toQianfanMultiModalMessages([]*schema.Message{schema.UserMessage("draw a cat"), msg})
+		assert.NoError(t, err)
+		assert.Equal(t, []contentPart{
+			{Type: ImageURL, ImageURL: &image{URL: "https://example.com/a.jpg"}},
+			{Type: ImageURL, ImageURL: &image{URL: "data:image/png;base64," + b64}},
+		}, messages[1].Content)
+
+		sr, err := im.Stream(ctx, []*schema.Message{schema.UserMessage("a cat")})
+		assert.NoError(t, err)
+		chunk, err := sr.Recv()
+		assert.NoError(t, err)
+		assert.Len(t, chunk.AssistantGenMultiContent, 2)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		srv := newImageGenerationServer(t, func(req *imageGenerationRequest) (int, string) {
+			return http.StatusBadRequest, `{"error":{"code":"invalid_argument","message":"bad prompt","type":"invalid_request_error"}}`
+		})
+		defer srv.Close()
+
+		im, err := NewImageGenerationModel(ctx, &ImageGenerationConfig{APIKey: "my-api-key", Model: "irag-1.0", BaseURL: srv.URL})
+		assert.NoError(t, err)
+		_, err = im.Generate(ctx, []*schema.Message{schema.UserMessage("a cat")})
+		assert.ErrorContains(t, err, "bad prompt")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := NewImageGenerationModel(ctx, &ImageGenerationConfig{Model: "irag-1.0"})
+		assert.Error(t, err)
+
+		im, err := NewImageGenerationModel(ctx, &ImageGenerationConfig{APIKey: "my-api-key", Model: "irag-1.0"})
+		assert.NoError(t, err)
+		_, err = im.Generate(ctx, []*schema.Message{schema.AssistantMessage("hi", nil)})
+		assert.ErrorContains(t, err, "prompt is empty")
+	})
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// MessageOrderError is returned when the input messages are in an order rejected by Qianfan,
+// e.g. a tool message not answering the tool calls of an assistant message.
+type MessageOrderError struct {
+	// Index is the index of the message at fault in the input messages.
+	Index int
+	// Reason describes the violated ordering rule.
+	Reason string
+}
+
+func (e *MessageOrderError) Error() string {
+	return fmt.Sprintf("[qianfan] invalid message order at messages[%d]: %s", e.Index, e.Reason)
+}
+
+// normalizeMessageOrder checks the input messages against the ordering rules of Qianfan:
+// a single system message first, then a user message, no consecutive user messages,
+// and tool messages following the assistant message with the tool calls they answer.
+// With fixup, the system messages are merged and moved to the front, and the consecutive user messages merged,
+// instead of failing. The input messages are never modified.
+func normalizeMessageOrder(input []*schema.Message, fixup bool) ([]*schema.Message, error) {
+	var (
+		system []*schema.Message
+		others = make([]*schema.Message, 0, len(input))
+		// indices holds the input index of every message of others
+		indices = make([]int, 0, len(input))
+	)
+	for i, msg := range input {
+		if msg == nil {
+			continue
+		}
+		if msg.Role != schema.System {
+			others = append(others, msg)
+			indices = append(indices, i)
+			continue
+		}
+		if !fixup && (len(system) > 0 || len(others) > 0) {
+			return nil, &MessageOrderError{Index: i, Reason: "the system message must be the only one and come first"}
+		}
+		system = append(system, msg)
+	}
+
+	result := make([]*schema.Message, 0, len(input))
+	if len(system) > 0 {
+		result = append(result, mergeMessages(system, "\n\n"))
+	}
+
+	var prev *schema.Message
+	for j, msg := range others {
+		idx := indices[j]
+		switch msg.Role {
+		case schema.User:
+			if prev != nil && prev.Role == schema.User {
+				if !fixup {
+					return nil, &MessageOrderError{Index: idx, Reason: "consecutive user messages"}
+				}
+				prev = mergeMessages([]*schema.Message{prev, msg}, "\n")
+				result[len(result)-1] = prev
+				continue
+			}
+		case schema.Assistant:
+			if prev == nil {
+				return nil, &MessageOrderError{Index: idx, Reason: "the first message after the system message must be a user message, got assistant"}
+			}
+		case schema.Tool:
+			if prev == nil || (prev.Role != schema.Tool && (prev.Role != schema.Assistant || len(prev.ToolCalls) == 0)) {
+				return nil, &MessageOrderError{Index: idx, Reason: "the tool message must follow an assistant message with tool calls"}
+			}
+		}
+		result = append(result, msg)
+		prev = msg
+	}
+	return result, nil
+}
+
+// mergeMessages returns a copy of the first message with the contents of all the messages, joined by sep.
+// The multi contents are concatenated, with the text contents converted into text parts.
+func mergeMessages(msgs []*schema.Message, sep string) *schema.Message {
+	if len(msgs) == 1 {
+		return msgs[0]
+	}
+
+	merged := *msgs[0]
+	multi := false
+	for _, msg := range msgs {
+		if len(msg.UserInputMultiContent) > 0 {
+			multi = true
+			break
+		}
+	}
+	if !multi {
+		contents := make([]string, 0, len(msgs))
+		for _, msg := range msgs {
+			contents = append(contents, msg.Content)
+		}
+		merged.Content = strings.Join(contents, sep)
+		return &merged
+	}
+
+	merged.Content = ""
+	merged.UserInputMultiContent = nil
+	for _, msg := range msgs {
+		if len(msg.UserInputMultiContent) == 0 {
+			if msg.Content == "" {
+				continue
+			}
+			merged.UserInputMultiContent = append(merged.UserInputMultiContent, schema.MessageInputPart{
+				Type: schema.ChatMessagePartTypeText,
+				Text: msg.Content,
+			})
+			continue
+		}
+		merged.UserInputMultiContent = append(merged.UserInputMultiContent, msg.UserInputMultiContent...)
+	}
+	return &merged
+}
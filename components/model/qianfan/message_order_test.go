@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeMessageOrder(t *testing.T) {
+	toolCall := schema.AssistantMessage("", []schema.ToolCall{{ID: "call_1", Function: schema.FunctionCall{Name: "search"}}})
+
+	t.Run("valid order is kept", func(t *testing.T) {
+		input := []*schema.Message{
+			schema.SystemMessage("be brief"),
+			schema.UserMessage("hi"),
+			toolCall,
+			schema.ToolMessage("result", "call_1"),
+			schema.AssistantMessage("done", nil),
+		}
+		for _, fixup := range []bool{false, true} {
+			msgs, err := normalizeMessageOrder(input, fixup)
+			assert.NoError(t, err)
+			assert.Equal(t, input, msgs)
+		}
+	})
+
+	t.Run("fixups", func(t *testing.T) {
+		input := []*schema.Message{
+			schema.UserMessage("hi"),
+			schema.SystemMessage("be brief"),
+			schema.UserMessage("look at this"),
+			{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{{Type: schema.ChatMessagePartTypeText, Text: "and this"}}},
+			schema.SystemMessage("answer in English"),
+		}
+		msgs, err := normalizeMessageOrder(input, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []*schema.Message{
+			schema.SystemMessage("be brief\n\nanswer in English"),
+			{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "hi\nlook at this"},
+				{Type: schema.ChatMessagePartTypeText, Text: "and this"},
+			}},
+		}, msgs)
+		// the input messages are not modified
+		assert.Equal(t, "hi", input[0].Content)
+		assert.Equal(t, "be brief", input[1].Content)
+
+		msgs, err = normalizeMessageOrder([]*schema.Message{schema.UserMessage("a"), schema.UserMessage("b")}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []*schema.Message{schema.UserMessage("a\nb")}, msgs)
+	})
+
+	t.Run("no fixups by default", func(t *testing.T) {
+		_, err := normalizeMessageOrder([]*schema.Message{schema.UserMessage("hi"), schema.SystemMessage("be brief")}, false)
+		var orderErr *MessageOrderError
+		assert.True(t, errors.As(err, &orderErr))
+		assert.Equal(t, 1, orderErr.Index)
+
+		_, err = normalizeMessageOrder([]*schema.Message{schema.UserMessage("a"), schema.UserMessage("b")}, false)
+		assert.EqualError(t, err, "[qianfan] invalid message order at messages[1]: consecutive user messages")
+	})
+
+	t.Run("unfixable orders", func(t *testing.T) {
+		for _, fixup := range []bool{false, true} {
+			_, err := normalizeMessageOrder([]*schema.Message{
+				schema.SystemMessage("be brief"),
+				schema.ToolMessage("result", "call_1"),
+			}, fixup)
+			assert.EqualError(t, err, "[qianfan] invalid message order at messages[1]: the tool message must follow an assistant message with tool calls")
+
+			_, err = normalizeMessageOrder([]*schema.Message{
+				schema.UserMessage("hi"),
+				schema.AssistantMessage("hello", nil),
+				schema.ToolMessage("result", "call_1"),
+			}, fixup)
+			assert.ErrorContains(t, err, "messages[2]")
+
+			_, err = normalizeMessageOrder([]*schema.Message{schema.AssistantMessage("hello", nil)}, fixup)
+			assert.ErrorContains(t, err, "must be a user message")
+		}
+	})
+}
@@ -0,0 +1,223 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRateLimitMaxAttempts    = 3
+	defaultRateLimitInitialBackoff = time.Second
+	defaultRateLimitMaxBackoff     = 30 * time.Second
+)
+
+// rateLimitErrorPatterns are the lower-cased messages of the throttling errors of Qianfan,
+// e.g. the QPS / RPM / TPM limit errors (codes 18, 336501 and 336502) and the server high load error (code 336100)
+// of the v1 API, and the "*_rate_limit_exceeded" error codes of the v2 API.
+var rateLimitErrorPatterns = []string{
+	"rate_limit_exceeded",
+	"qps request limit reached",
+	"rate limit reached",
+	"server high load",
+	"too many requests",
+}
+
+// RateLimitRetryConfig configures the retry of the requests throttled by Qianfan.
+// The retries wait for an exponential backoff with jitter, or the wait time suggested by the server if known.
+type RateLimitRetryConfig struct {
+	// MaxAttempts is the max number of attempts, including the first one.
+	// Default is 3.
+	MaxAttempts int
+
+	// InitialBackoff is the backoff before the first retry, doubled for each following retry.
+	// Default is 1s.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the backoff and the wait time suggested by the server.
+	// Default is 30s.
+	MaxBackoff time.Duration
+
+	// IsRateLimited reports whether the error is a throttling error which is retried.
+	// Default matches the QPS / RPM / TPM limit and server high load errors of Qianfan.
+	IsRateLimited func(err error) bool
+
+	// RetryAfter returns the wait time suggested by the server for the error.
+	// Default honors the Retry-After header of the throttled responses,
+	// and the errors implementing interface{ RetryAfter() time.Duration }.
+	RetryAfter func(err error) (time.Duration, bool)
+}
+
+// RateLimitedError is returned when the request is still throttled by Qianfan after all the attempts.
+type RateLimitedError struct {
+	// Attempts is the number of attempts made.
+	Attempts int
+	// Err is the throttling error of the last attempt.
+	Err error
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.Err
+}
+
+func (c *RateLimitRetryConfig) withDefaults() *RateLimitRetryConfig {
+	nc := *c
+	if nc.MaxAttempts <= 0 {
+		nc.MaxAttempts = defaultRateLimitMaxAttempts
+	}
+	if nc.InitialBackoff <= 0 {
+		nc.InitialBackoff = defaultRateLimitInitialBackoff
+	}
+	if nc.MaxBackoff <= 0 {
+		nc.MaxBackoff = defaultRateLimitMaxBackoff
+	}
+	if nc.IsRateLimited == nil {
+		nc.IsRateLimited = isRateLimitError
+	}
+	if nc.RetryAfter == nil {
+		nc.RetryAfter = retryAfterOf
+	}
+	return &nc
+}
+
+// isRateLimitError reports whether the error message matches a throttling error of Qianfan.
+func isRateLimitError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, pattern := range rateLimitErrorPatterns {
+		if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryAfterError is a throttling error whose response suggests a wait time with the Retry-After header.
+type retryAfterError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// withRetryAfter attaches the wait time of the Retry-After header of the response to the error, if any.
+func withRetryAfter(err error, resp *http.Response) error {
+	if err == nil || resp == nil {
+		return err
+	}
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		return &retryAfterError{err: err, retryAfter: d}
+	}
+	return err
+}
+
+// parseRetryAfter parses the value of the Retry-After header, either a number of seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+func retryAfterOf(err error) (time.Duration, bool) {
+	var ra interface{ RetryAfter() time.Duration }
+	if errors.As(err, &ra) {
+		return ra.RetryAfter(), true
+	}
+	return 0, false
+}
+
+// backoff returns the wait time before the retry following the attempt, starting from 1.
+// The exponential backoff is jittered in [backoff/2, backoff) to spread the retries of concurrent requests.
+func (c *RateLimitRetryConfig) backoff(attempt int, err error) time.Duration {
+	if d, ok := c.RetryAfter(err); ok && d > 0 {
+		if d > c.MaxBackoff {
+			return c.MaxBackoff
+		}
+		return d
+	}
+
+	d := c.InitialBackoff
+	for i := 1; i < attempt && d < c.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > c.MaxBackoff {
+		d = c.MaxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryRateLimited calls fn until it succeeds, fails with an error which is not a throttling error,
+// or the attempts are exhausted, in which case a *RateLimitedError is returned.
+// fn is called once if conf is nil.
+func retryRateLimited(ctx context.Context, conf *RateLimitRetryConfig, fn func() error) error {
+	if conf == nil {
+		return fn()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !conf.IsRateLimited(err) {
+			return err
+		}
+		if attempt >= conf.MaxAttempts {
+			return &RateLimitedError{Attempts: attempt, Err: err}
+		}
+
+		timer := time.NewTimer(conf.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/baidubce/bce-qianfan-sdk/go/qianfan"
+	. "github.com/bytedance/mockey"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type retryAfterErr struct {
+	after time.Duration
+}
+
+func (e *retryAfterErr) Error() string {
+	return "rpm_rate_limit_exceeded"
+}
+
+func (e *retryAfterErr) RetryAfter() time.Duration {
+	return e.after
+}
+
+func TestRateLimitRetryConfig(t *testing.T) {
+	convey.Convey("test RateLimitRetryConfig", t, func() {
+		conf := (&RateLimitRetryConfig{}).withDefaults()
+		convey.So(conf.MaxAttempts, convey.ShouldEqual, defaultRateLimitMaxAttempts)
+		convey.So(conf.InitialBackoff, convey.ShouldEqual, defaultRateLimitInitialBackoff)
+		convey.So(conf.MaxBackoff, convey.ShouldEqual, defaultRateLimitMaxBackoff)
+
+		convey.Convey("test isRateLimitError", func() {
+			convey.So(isRateLimitError(errors.New("code=rpm_rate_limit_exceeded, msg=too fast")), convey.ShouldBeTrue)
+			convey.So(isRateLimitError(errors.New("Open api qps request limit reached")), convey.ShouldBeTrue)
+			convey.So(isRateLimitError(errors.New("Rate limit reached for TPM")), convey.ShouldBeTrue)
+			convey.So(isRateLimitError(errors.New("invalid argument")), convey.ShouldBeFalse)
+		})
+
+		convey.Convey("test backoff", func() {
+			for attempt := 1; attempt <= 10; attempt++ {
+				d := conf.backoff(attempt, errors.New("rate limit reached"))
+				convey.So(d, convey.ShouldBeLessThanOrEqualTo, conf.MaxBackoff)
+			}
+			d := conf.backoff(2, errors.New("rate limit reached"))
+			convey.So(d, convey.ShouldBeGreaterThanOrEqualTo, time.Second)
+			convey.So(d, convey.ShouldBeLessThanOrEqualTo, 2*time.Second)
+
+			convey.So(conf.backoff(1, &retryAfterErr{after: 5 * time.Second}), convey.ShouldEqual, 5*time.Second)
+			convey.So(conf.backoff(1, &retryAfterErr{after: time.Hour}), convey.ShouldEqual, conf.MaxBackoff)
+
+			throttled := errors.New("rpm_rate_limit_exceeded")
+			resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+			err := withRetryAfter(throttled, resp)
+			convey.So(errors.Is(err, throttled), convey.ShouldBeTrue)
+			convey.So(conf.backoff(1, err), convey.ShouldEqual, 7*time.Second)
+			convey.So(withRetryAfter(throttled, &http.Response{Header: http.Header{}}), convey.ShouldEqual, throttled)
+			convey.So(withRetryAfter(throttled, nil), convey.ShouldEqual, throttled)
+		})
+
+		convey.Convey("test parseRetryAfter", func() {
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			cases := []struct {
+				value string
+				d     time.Duration
+				ok    bool
+			}{
+				{value: "", ok: false},
+				{value: "3", d: 3 * time.Second, ok: true},
+				{value: " 0 ", d: 0, ok: true},
+				{value: "-1", ok: false},
+				{value: "soon", ok: false},
+				{value: "Wed, 01 Jan 2025 00:00:10 GMT", d: 10 * time.Second, ok: true},
+				{value: "Tue, 31 Dec 2024 23:59:50 GMT", d: 0, ok: true},
+			}
+			for _, c := range cases {
+				d, ok := parseRetryAfter(c.value, now)
+				convey.So(ok, convey.ShouldEqual, c.ok)
+				convey.So(d, convey.ShouldEqual, c.d)
+			}
+		})
+	})
+}
+
+func TestRetryRateLimited(t *testing.T) {
+	convey.Convey("test retryRateLimited", t, func() {
+		ctx := context.Background()
+		conf := (&RateLimitRetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond}).withDefaults()
+		throttled := errors.New("rpm_rate_limit_exceeded")
+
+		convey.Convey("test succeed after retry", func() {
+			calls := 0
+			err := retryRateLimited(ctx, conf, func() error {
+				calls++
+				if calls < 3 {
+					return throttled
+				}
+				return nil
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(calls, convey.ShouldEqual, 3)
+		})
+
+		convey.Convey("test exhausted", func() {
+			calls := 0
+			err := retryRateLimited(ctx, conf, func() error {
+				calls++
+				return throttled
+			})
+			var rlErr *RateLimitedError
+			convey.So(errors.As(err, &rlErr), convey.ShouldBeTrue)
+			convey.So(rlErr.Attempts, convey.ShouldEqual, 3)
+			convey.So(errors.Is(err, throttled), convey.ShouldBeTrue)
+			convey.So(calls, convey.ShouldEqual, 3)
+		})
+
+		convey.Convey("test other error not retried", func() {
+			calls := 0
+			other := errors.New("invalid argument")
+			err := retryRateLimited(ctx, conf, func() error {
+				calls++
+				return other
+			})
+			convey.So(err, convey.ShouldEqual, other)
+			convey.So(calls, convey.ShouldEqual, 1)
+		})
+
+		convey.Convey("test disabled", func() {
+			calls := 0
+			err := retryRateLimited(ctx, nil, func() error {
+				calls++
+				return throttled
+			})
+			convey.So(err, convey.ShouldEqual, throttled)
+			convey.So(calls, convey.ShouldEqual, 1)
+		})
+
+		convey.Convey("test context canceled", func() {
+			cctx, cancel := context.WithCancel(ctx)
+			cancel()
+			err := retryRateLimited(cctx, (&RateLimitRetryConfig{InitialBackoff: time.Hour}).withDefaults(), func() error {
+				return throttled
+			})
+			convey.So(errors.Is(err, context.Canceled), convey.ShouldBeTrue)
+		})
+	})
+}
+
+func TestGenerateWithRateLimitRetry(t *testing.T) {
+	PatchConvey("test Generate with RateLimitRetry", t, func() {
+		ctx := context.Background()
+		m, err := NewChatModel(ctx, &ChatModelConfig{
+			Model:          "asd",
+			RateLimitRetry: &RateLimitRetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+		})
+		convey.So(err, convey.ShouldBeNil)
+		msgs := []*schema.Message{schema.UserMessage("test")}
+
+		throttled := &qianfan.ChatCompletionV2Response{
+			Error: &qianfan.ChatCompletionV2Error{Code: "rpm_rate_limit_exceeded", Message: "too fast"},
+		}
+		success := &qianfan.ChatCompletionV2Response{
+			Choices: []qianfan.ChatCompletionV2Choice{
+				{Index: 0, Message: qianfan.ChatCompletionV2Message{Role: "assistant", Content: "ok"}},
+			},
+		}
+
+		PatchConvey("test retried throttled response", func() {
+			mocker := Mock(GetMethod(m.cc, "Do")).Return(Sequence(throttled, nil).Then(success, nil)).Build()
+
+			outMsg, err := m.Generate(ctx, msgs)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(outMsg.Content, convey.ShouldEqual, "ok")
+			convey.So(mocker.Times(), convey.ShouldEqual, 2)
+		})
+
+		PatchConvey("test exhausted", func() {
+			Mock(GetMethod(m.cc, "Do")).Return(throttled, nil).Build()
+
+			_, err := m.Generate(ctx, msgs)
+			var rlErr *RateLimitedError
+			convey.So(errors.As(err, &rlErr), convey.ShouldBeTrue)
+			convey.So(rlErr.Attempts, convey.ShouldEqual, 2)
+		})
+
+		PatchConvey("test stream open retried", func() {
+			mocker := Mock(GetMethod(m.cc, "Stream")).Return(nil, errors.New("Open api qps request limit reached")).Build()
+
+			_, err := m.Stream(ctx, msgs)
+			var rlErr *RateLimitedError
+			convey.So(errors.As(err, &rlErr), convey.ShouldBeTrue)
+			convey.So(mocker.Times(), convey.ShouldEqual, 2)
+		})
+	})
+}
+
+func TestRateLimitRetryKeepsSDKRetries(t *testing.T) {
+	PatchConvey("test RateLimitRetry keeps the retries of the SDK", t, func() {
+		m, err := NewChatModel(context.Background(), &ChatModelConfig{
+			Model:          "asd",
+			LLMRetryCount:  of(3),
+			RateLimitRetry: &RateLimitRetryConfig{},
+		})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(m.cc.Options.LLMRetryCount, convey.ShouldEqual, 3)
+		convey.So(m.retry, convey.ShouldNotBeNil)
+	})
+}
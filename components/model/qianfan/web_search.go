@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+func init() {
+	compose.RegisterStreamChunkConcatFunc(func(chunks [][]SearchResult) (final []SearchResult, err error) {
+		seen := make(map[SearchResult]bool)
+		for _, chunk := range chunks {
+			for _, r := range chunk {
+				if !seen[r] {
+					seen[r] = true
+					final = append(final, r)
+				}
+			}
+		}
+		return final, nil
+	})
+	schema.RegisterName[[]SearchResult]("_eino_ext_qianfan_search_results")
+}
+
+const keyOfQianfanSearchResults = "qianfan_search_results"
+
+// WebSearchConfig enables the built-in web search of the models supporting it, e.g. ERNIE 4.0 and ERNIE 4.5,
+// see: https://cloud.baidu.com/doc/qianfan-api/s/3m7of64lb
+type WebSearchConfig struct {
+	// EnableCitation adds the citation markers of the search results, e.g. "^[1]^", to the generated content.
+	EnableCitation bool `json:"enable_citation"`
+	// EnableTrace returns the search results the content is based on, see GetSearchResults.
+	EnableTrace bool `json:"enable_trace"`
+}
+
+// SearchResult is a web page returned by the web search, the Index matching the citation markers of the content.
+type SearchResult struct {
+	Index int    `json:"index"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+// GetSearchResults returns the search results of the message generated with ChatModelConfig.EnableWebSearch,
+// false if the response did not return any, e.g. the model did not search or WebSearchConfig.EnableTrace is false.
+func GetSearchResults(msg *schema.Message) ([]SearchResult, bool) {
+	if msg == nil || msg.Extra == nil {
+		return nil, false
+	}
+	results, ok := msg.Extra[keyOfQianfanSearchResults].([]SearchResult)
+	return results, ok && len(results) > 0
+}
+
+func toWebSearchParam(config *WebSearchConfig) map[string]any {
+	return map[string]any{
+		"enable":          true,
+		"enable_citation": config.EnableCitation,
+		"enable_trace":    config.EnableTrace,
+	}
+}
+
+var searchResultsKey = []byte(`"search_results"`)
+
+// setSearchResults sets the search results of the raw response body to the message,
+// the Qianfan SDK does not decode them into the response.
+// The body is only decoded if it contains the search results, since most stream chunks do not.
+func setSearchResults(msg *schema.Message, body []byte) {
+	if msg == nil || !bytes.Contains(body, searchResultsKey) {
+		return
+	}
+	var resp struct {
+		SearchResults []SearchResult `json:"search_results"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.SearchResults) == 0 {
+		return
+	}
+	if msg.Extra == nil {
+		msg.Extra = make(map[string]any)
+	}
+	msg.Extra[keyOfQianfanSearchResults] = resp.SearchResults
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qianfan
+
+import (
+	"testing"
+
+	"github.com/baidubce/bce-qianfan-sdk/go/qianfan"
+	. "github.com/bytedance/mockey"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestWebSearch(t *testing.T) {
+	PatchConvey("test web search", t, func() {
+		body := []byte(`{"id":"as-1","search_results":[{"index":1,"url":"https://a.com","title":"A"},{"index":2,"url":"https://b.com","title":"B"}]}`)
+		expected := []SearchResult{{Index: 1, URL: "https://a.com", Title: "A"}, {Index: 2, URL: "https://b.com", Title: "B"}}
+
+		PatchConvey("test request param", func() {
+			convey.So(toWebSearchParam(&WebSearchConfig{EnableTrace: true}), convey.ShouldResemble, map[string]any{
+				"enable":          true,
+				"enable_citation": false,
+				"enable_trace":    true,
+			})
+		})
+
+		PatchConvey("test generate", func() {
+			resp := &qianfan.ChatCompletionV2Response{
+				Choices: []qianfan.ChatCompletionV2Choice{
+					{Index: 0, Message: qianfan.ChatCompletionV2Message{Role: "assistant", Content: "answer^[1]^"}},
+				},
+			}
+			resp.Body = body
+
+			msg, err := resolveQianfanResponse(resp)
+			convey.So(err, convey.ShouldBeNil)
+			results, ok := GetSearchResults(msg)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(results, convey.ShouldResemble, expected)
+		})
+
+		PatchConvey("test stream", func() {
+			first := &qianfan.ChatCompletionV2Response{}
+			first.Body = body
+			second := &qianfan.ChatCompletionV2Response{
+				Choices: []qianfan.ChatCompletionV2Choice{
+					{Index: 0, Delta: qianfan.ChatCompletionV2Delta{Content: "answer"}},
+				},
+			}
+			second.Body = []byte(`{"id":"as-1","choices":[{"index":0,"delta":{"content":"answer"}}]}`)
+			third := &qianfan.ChatCompletionV2Response{
+				Choices: []qianfan.ChatCompletionV2Choice{
+					{Index: 0, Delta: qianfan.ChatCompletionV2Delta{Content: "^[1]^"}},
+				},
+			}
+			third.Body = body
+
+			var chunks []*schema.Message
+			for _, resp := range []*qianfan.ChatCompletionV2Response{first, second, third} {
+				msg, found, err := resolveQianfanStreamResponse(resp)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(found, convey.ShouldBeTrue)
+				chunks = append(chunks, msg)
+			}
+			msg, err := schema.ConcatMessages(chunks)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(msg.Content, convey.ShouldEqual, "answer^[1]^")
+			results, ok := GetSearchResults(msg)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(results, convey.ShouldResemble, expected)
+		})
+
+		PatchConvey("test without search results", func() {
+			msg := &schema.Message{Role: schema.Assistant}
+			setSearchResults(msg, []byte(`{"id":"as-1"}`))
+			_, ok := GetSearchResults(msg)
+			convey.So(ok, convey.ShouldBeFalse)
+			convey.So(msg.Extra, convey.ShouldBeNil)
+		})
+	})
+}
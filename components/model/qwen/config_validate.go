@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qwen
+
+import (
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+// Validate checks the config without creating a client, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid.
+func (c *ChatModelConfig) Validate() error {
+	r := configcheck.New("qwen.ChatModelConfig")
+	r.Checkf(c.Model != "", "Model", "model is required")
+	configcheck.NonNegative(r, "Timeout", &c.Timeout)
+	configcheck.Positive(r, "MaxTokens", c.MaxTokens)
+	configcheck.InRange(r, "Temperature", c.Temperature, 0, 2)
+	configcheck.InRange(r, "TopP", c.TopP, 0, 1)
+	configcheck.InRange(r, "PresencePenalty", c.PresencePenalty, -2, 2)
+	configcheck.InRange(r, "FrequencyPenalty", c.FrequencyPenalty, -2, 2)
+	if c.ResponseFormat != nil {
+		switch c.ResponseFormat.Type {
+		case openai.ChatCompletionResponseFormatTypeText, openai.ChatCompletionResponseFormatTypeJSONObject:
+		case openai.ChatCompletionResponseFormatTypeJSONSchema:
+			r.Checkf(c.ResponseFormat.JSONSchema != nil, "ResponseFormat.JSONSchema", "required by the %q response format", c.ResponseFormat.Type)
+		default:
+			r.Addf("ResponseFormat.Type", "unsupported response format type %q", c.ResponseFormat.Type)
+		}
+	}
+	audio := false
+	for _, m := range c.Modalities {
+		switch m {
+		case openai.TextModality:
+		case openai.AudioModality:
+			audio = true
+		default:
+			r.Addf("Modalities", "unsupported modality %q", m)
+		}
+	}
+	r.Checkf(!audio || c.Audio != nil, "Audio", "required by the %q modality", openai.AudioModality)
+	return r.Err()
+}
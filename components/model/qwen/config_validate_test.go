@@ -0,0 +1,52 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package qwen
+
+import (
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/cloudwego/eino-ext/libs/acl/openai"
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+func TestChatModelConfig_Validate(t *testing.T) {
+	convey.Convey("test ChatModelConfig.Validate", t, func() {
+		convey.Convey("valid", func() {
+			convey.So((&ChatModelConfig{Model: "qwen-plus"}).Validate(), convey.ShouldBeNil)
+		})
+
+		convey.Convey("invalid", func() {
+			topP := float32(1.5)
+			err := (&ChatModelConfig{
+				TopP:           &topP,
+				ResponseFormat: &openai.ChatCompletionResponseFormat{Type: "xml"},
+				Modalities:     []Modality{openai.AudioModality},
+			}).Validate()
+			report, ok := err.(*configcheck.Report)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(report.Config, convey.ShouldEqual, "qwen.ChatModelConfig")
+			convey.So(report.Issues, convey.ShouldResemble, []configcheck.Issue{
+				{Field: "Model", Message: "model is required"},
+				{Field: "TopP", Message: "must be in [0, 1], got 1.5"},
+				{Field: "ResponseFormat.Type", Message: `unsupported response format type "xml"`},
+				{Field: "Audio", Message: `required by the "audio" modality`},
+			})
+		})
+	})
+}
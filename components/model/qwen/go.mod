@@ -6,6 +6,7 @@ require (
 	github.com/bytedance/mockey v1.3.0
 	github.com/cloudwego/eino v0.7.13
 	github.com/cloudwego/eino-ext/libs/acl/openai v0.1.14
+	github.com/cloudwego/eino-ext/libs/configcheck v0.1.0
 	github.com/smartystreets/goconvey v1.8.1
 )
 
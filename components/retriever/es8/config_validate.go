@@ -0,0 +1,31 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es8
+
+import (
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+// Validate checks the config without calling Elasticsearch, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid. Unlike NewRetriever, it does not fill in the defaults of the config.
+func (c *RetrieverConfig) Validate() error {
+	r := configcheck.New("es8.RetrieverConfig")
+	r.Checkf(c.Client != nil, "Client", "es client not provided")
+	r.Checkf(c.SearchMode != nil, "SearchMode", "search mode not provided")
+	configcheck.NonNegative(r, "TopK", &c.TopK)
+	return r.Err()
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+)
+
+// MetadataKeyBound is the Document.MetaData key of the value bound by a ResultBinder.
+// Use ResultBinder.From to read it back.
+const MetadataKeyBound = "_es9_bound"
+
+// bindTag is the struct tag marking the fields of T set by the ResultBinder from the hit rather than _source.
+const bindTag = "eino"
+
+// ResultBinder decodes the _source of the hits into the user struct T and converts them into Documents,
+// so metadata-heavy applications read typed fields instead of map[string]any.
+//
+// The _source fields are mapped by the json tags of T. The eino tag marks the fields filled from the hit:
+//
+//	type Article struct {
+//		ID      string   `eino:"id"`                   // the _id of the hit
+//		Score   float64  `eino:"score"`                // the _score of the hit
+//		Body    string   `json:"body" eino:"content"` // Document.Content
+//		Authors []string `json:"authors"`
+//	}
+//
+// Set Parser as RetrieverConfig.ResultParser, then get the bound values of the retrieved documents with From.
+type ResultBinder[T any] struct {
+	idField      []int
+	scoreField   []int
+	contentField []int
+	// contentKey is the _source key of the content, removed from the metadata.
+	contentKey string
+}
+
+// NewResultBinder returns a ResultBinder for T, which must be a struct.
+// The fields tagged eino:"id" and eino:"content" must be strings, the field tagged eino:"score" a float64.
+// Without a field tagged eino:"content", the "content" field of _source is used as Document.Content if it is a string.
+func NewResultBinder[T any]() (*ResultBinder[T], error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("[NewResultBinder] bound type must be a struct, got %s", typ)
+	}
+
+	b := &ResultBinder[T]{contentKey: contentField}
+	for _, f := range reflect.VisibleFields(typ) {
+		tag := f.Tag.Get(bindTag)
+		if tag == "" || !f.IsExported() {
+			continue
+		}
+		var want reflect.Kind
+		switch tag {
+		case "id":
+			b.idField, want = f.Index, reflect.String
+		case "score":
+			b.scoreField, want = f.Index, reflect.Float64
+		case "content":
+			b.contentField, want = f.Index, reflect.String
+			b.contentKey = jsonName(f)
+		default:
+			return nil, fmt.Errorf("[NewResultBinder] unknown eino tag %q of field %s", tag, f.Name)
+		}
+		if f.Type.Kind() != want {
+			return nil, fmt.Errorf("[NewResultBinder] field %s tagged eino:%q must be a %s, got %s", f.Name, tag, want, f.Type)
+		}
+	}
+	return b, nil
+}
+
+// Bind decodes the hit into a T.
+func (b *ResultBinder[T]) Bind(hit types.Hit) (*T, error) {
+	if hit.Source_ == nil {
+		return nil, fmt.Errorf("[ResultBinder] field '_source' not found in hit")
+	}
+
+	v := new(T)
+	if err := json.Unmarshal(hit.Source_, v); err != nil {
+		return nil, fmt.Errorf("[ResultBinder] unmarshal _source into %T failed: %w", v, err)
+	}
+	rv := reflect.ValueOf(v).Elem()
+	if b.idField != nil && hit.Id_ != nil {
+		rv.FieldByIndex(b.idField).SetString(*hit.Id_)
+	}
+	if b.scoreField != nil && hit.Score_ != nil {
+		rv.FieldByIndex(b.scoreField).SetFloat(float64(*hit.Score_))
+	}
+	return v, nil
+}
+
+// Parser returns a result parser binding each hit into a T, usable as RetrieverConfig.ResultParser.
+// The Document carries the bound value under MetadataKeyBound next to the other _source fields and the score,
+// like the default result parser.
+func (b *ResultBinder[T]) Parser() func(ctx context.Context, hit types.Hit) (*schema.Document, error) {
+	return func(ctx context.Context, hit types.Hit) (*schema.Document, error) {
+		return b.ToDocument(hit)
+	}
+}
+
+// ToDocument binds the hit into a T and converts it into a Document.
+func (b *ResultBinder[T]) ToDocument(hit types.Hit) (*schema.Document, error) {
+	if hit.Id_ == nil {
+		return nil, fmt.Errorf("[ResultBinder] field '_id' not found in hit")
+	}
+	v, err := b.Bind(hit)
+	if err != nil {
+		return nil, err
+	}
+
+	source := make(map[string]any)
+	if err = json.Unmarshal(hit.Source_, &source); err != nil {
+		return nil, fmt.Errorf("[ResultBinder] unmarshal _source failed: %w", err)
+	}
+
+	var content string
+	if b.contentField != nil {
+		content = reflect.ValueOf(v).Elem().FieldByIndex(b.contentField).String()
+	} else if s, ok := source[b.contentKey].(string); ok {
+		content = s
+	}
+
+	score := 0.0
+	if hit.Score_ != nil {
+		score = float64(*hit.Score_)
+	}
+
+	meta := make(map[string]any, len(source)+2)
+	for k, val := range source {
+		// encoding/json matches the keys case-insensitively
+		if !strings.EqualFold(k, b.contentKey) {
+			meta[k] = val
+		}
+	}
+	meta["score"] = score
+	meta[MetadataKeyBound] = v
+
+	doc := &schema.Document{
+		ID:       *hit.Id_,
+		Content:  content,
+		MetaData: meta,
+	}
+	return doc.WithScore(score), nil
+}
+
+// From returns the value bound into the document by the Parser of the binder.
+func (b *ResultBinder[T]) From(doc *schema.Document) (*T, bool) {
+	if doc == nil || doc.MetaData == nil {
+		return nil, false
+	}
+	v, ok := doc.MetaData[MetadataKeyBound].(*T)
+	return v, ok
+}
+
+// jsonName returns the _source key of the field as encoding/json maps it.
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
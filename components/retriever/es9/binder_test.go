@@ -0,0 +1,101 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type testArticle struct {
+	ID      string   `eino:"id"`
+	Score   float64  `eino:"score"`
+	Body    string   `json:"body" eino:"content"`
+	Authors []string `json:"authors"`
+	Year    int      `json:"year"`
+}
+
+func TestResultBinder(t *testing.T) {
+	ctx := context.Background()
+	id, score := "doc_1", types.Float64(0.8)
+	hit := types.Hit{
+		Id_:     &id,
+		Score_:  &score,
+		Source_: []byte(`{"body":"hello","authors":["a","b"],"year":2024}`),
+	}
+
+	t.Run("bind", func(t *testing.T) {
+		b, err := NewResultBinder[testArticle]()
+		assert.NoError(t, err)
+
+		doc, err := b.Parser()(ctx, hit)
+		assert.NoError(t, err)
+		assert.Equal(t, "doc_1", doc.ID)
+		assert.Equal(t, "hello", doc.Content)
+		assert.Equal(t, 0.8, doc.Score())
+		assert.NotContains(t, doc.MetaData, "body")
+		assert.Equal(t, float64(2024), doc.MetaData["year"])
+
+		article, ok := b.From(doc)
+		assert.True(t, ok)
+		assert.Equal(t, &testArticle{ID: "doc_1", Score: 0.8, Body: "hello", Authors: []string{"a", "b"}, Year: 2024}, article)
+
+		_, err = b.Bind(types.Hit{Id_: &id})
+		assert.ErrorContains(t, err, "_source")
+		_, err = b.ToDocument(types.Hit{Source_: hit.Source_})
+		assert.ErrorContains(t, err, "_id")
+		_, err = b.Bind(types.Hit{Id_: &id, Source_: []byte(`{"year":"2024"}`)})
+		assert.Error(t, err)
+	})
+
+	t.Run("default content field", func(t *testing.T) {
+		type note struct {
+			Title string `json:"title"`
+		}
+		b, err := NewResultBinder[note]()
+		assert.NoError(t, err)
+
+		doc, err := b.ToDocument(types.Hit{Id_: &id, Source_: []byte(`{"title":"t","content":"c"}`)})
+		assert.NoError(t, err)
+		assert.Equal(t, "c", doc.Content)
+		assert.Equal(t, "t", doc.MetaData["title"])
+		assert.NotContains(t, doc.MetaData, "content")
+
+		_, ok := b.From(nil)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := NewResultBinder[string]()
+		assert.ErrorContains(t, err, "must be a struct")
+
+		type badContent struct {
+			Body int `eino:"content"`
+		}
+		_, err = NewResultBinder[badContent]()
+		assert.ErrorContains(t, err, "must be a string")
+
+		type badTag struct {
+			Body string `eino:"body"`
+		}
+		_, err = NewResultBinder[badTag]()
+		assert.ErrorContains(t, err, "unknown eino tag")
+	})
+}
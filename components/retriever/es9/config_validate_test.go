@@ -0,0 +1,46 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetrieverConfig_Validate(t *testing.T) {
+	conf := &RetrieverConfig{Client: &elasticsearch.Client{}, SearchMode: &mockSearchMode{}, Rerank: &RerankConfig{InferenceID: "rerank"}}
+	assert.NoError(t, conf.Validate())
+	// the defaults are not filled in
+	assert.Equal(t, 0, conf.TopK)
+	assert.Nil(t, conf.ResultParser)
+	assert.Equal(t, "", conf.Rerank.Field)
+
+	err := (&RetrieverConfig{TopK: 200, MaxResultWindow: 100, Rerank: &RerankConfig{RankWindowSize: -1}}).Validate()
+	report, ok := err.(*configcheck.Report)
+	assert.True(t, ok)
+	assert.Equal(t, "es9.RetrieverConfig", report.Config)
+	assert.Equal(t, []configcheck.Issue{
+		{Field: "Client", Message: "es client not provided"},
+		{Field: "SearchMode", Message: "search mode not provided"},
+		{Field: "TopK", Message: "must not exceed MaxResultWindow 100, got 200"},
+		{Field: "Rerank.InferenceID", Message: "rerank inference id not provided"},
+		{Field: "Rerank.RankWindowSize", Message: "must not be negative, got -1"},
+	}, report.Issues)
+}
@@ -20,6 +20,10 @@ const typ = "ElasticSearch9"
 
 const (
 	defaultTopK = 10
+	// defaultMaxResultWindow is the default index.max_result_window of Elasticsearch.
+	defaultMaxResultWindow = 10000
+
+	contentField = "content"
 )
 
 func GetType() string {
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+)
+
+// applyBoolFilters merges the filters into the request built by the search mode:
+// they are added to the filter of every knn search, and to the bool.filter section of the query,
+// wrapping a non-bool query in a bool query which keeps its scores.
+// A filter-only query is added for requests without query and knn.
+// Requests with a retriever, e.g. raw requests, are not supported.
+func applyBoolFilters(req *search.Request, filters []map[string]any) error {
+	if len(filters) == 0 {
+		return nil
+	}
+	if req.Retriever != nil {
+		return fmt.Errorf("bool filters are not supported by requests with a retriever")
+	}
+
+	queries := make([]types.Query, 0, len(filters))
+	for _, filter := range filters {
+		data, err := json.Marshal(filter)
+		if err != nil {
+			return fmt.Errorf("failed to marshal filter: %w", err)
+		}
+		var q types.Query
+		if err = json.Unmarshal(data, &q); err != nil {
+			return fmt.Errorf("failed to unmarshal filter %s: %w", data, err)
+		}
+		queries = append(queries, q)
+	}
+
+	for i := range req.Knn {
+		req.Knn[i].Filter = appendQueries(req.Knn[i].Filter, queries)
+	}
+
+	switch {
+	case req.Query != nil && req.Query.Bool != nil:
+		req.Query.Bool.Filter = appendQueries(req.Query.Bool.Filter, queries)
+	case req.Query != nil:
+		req.Query = &types.Query{Bool: &types.BoolQuery{
+			Must:   []types.Query{*req.Query},
+			Filter: queries,
+		}}
+	case len(req.Knn) == 0:
+		// a filter-only query would add all the matched documents to the knn hits, so it's only used without knn
+		req.Query = &types.Query{Bool: &types.BoolQuery{Filter: queries}}
+	}
+	return nil
+}
+
+// appendQueries returns a new slice, the filters of the search mode may be shared with the options.
+func appendQueries(dst, src []types.Query) []types.Query {
+	res := make([]types.Query, 0, len(dst)+len(src))
+	res = append(res, dst...)
+	return append(res, src...)
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// requestBody returns the marshaled request as a map.
+func requestBody(t *testing.T, req *search.Request) map[string]any {
+	data, err := json.Marshal(req)
+	assert.NoError(t, err)
+	body := make(map[string]any)
+	assert.NoError(t, json.Unmarshal(data, &body))
+	return body
+}
+
+func TestBoolFilterOptions(t *testing.T) {
+	io := retriever.GetImplSpecificOptions(&ImplOptions{},
+		WithTermsFilter("tag", "a", "b"),
+		WithRangeFilter("price", 10, nil),
+		WithRangeFilter("date", "2025-01-01", "2025-12-31"),
+		WithExistsFilter("author"),
+	)
+	assert.Equal(t, []map[string]any{
+		{"terms": map[string]any{"tag": []any{"a", "b"}}},
+		{"range": map[string]any{"price": map[string]any{"gte": 10}}},
+		{"range": map[string]any{"date": map[string]any{"gte": "2025-01-01", "lte": "2025-12-31"}}},
+		{"exists": map[string]any{"field": "author"}},
+	}, io.BoolFilters)
+}
+
+func TestApplyBoolFilters(t *testing.T) {
+	io := retriever.GetImplSpecificOptions(&ImplOptions{},
+		WithTermsFilter("tag", "a", "b"),
+		WithExistsFilter("author"),
+	)
+	expected := []any{
+		map[string]any{"terms": map[string]any{"tag": []any{"a", "b"}}},
+		map[string]any{"exists": map[string]any{"field": "author"}},
+	}
+
+	t.Run("no filters", func(t *testing.T) {
+		req := &search.Request{}
+		assert.NoError(t, applyBoolFilters(req, nil))
+		assert.Nil(t, req.Query)
+	})
+
+	t.Run("bool query", func(t *testing.T) {
+		modeFilters := []types.Query{{Term: map[string]types.TermQuery{"lang": {Value: "en"}}}}
+		req := &search.Request{Query: &types.Query{Bool: &types.BoolQuery{
+			Must:   []types.Query{{Match: map[string]types.MatchQuery{"content": {Query: "hello"}}}},
+			Filter: modeFilters[:1:1],
+		}}}
+		assert.NoError(t, applyBoolFilters(req, io.BoolFilters))
+		assert.Len(t, req.Query.Bool.Filter, 3)
+		assert.Len(t, req.Query.Bool.Must, 1)
+		assert.Len(t, modeFilters, 1)
+
+		query := requestBody(t, req)["query"].(map[string]any)
+		assert.Equal(t, expected, query["bool"].(map[string]any)["filter"].([]any)[1:])
+	})
+
+	t.Run("non-bool query", func(t *testing.T) {
+		req := &search.Request{Query: &types.Query{Match: map[string]types.MatchQuery{"content": {Query: "hello"}}}}
+		assert.NoError(t, applyBoolFilters(req, io.BoolFilters))
+
+		boolQuery := requestBody(t, req)["query"].(map[string]any)["bool"].(map[string]any)
+		assert.Equal(t, expected, boolQuery["filter"])
+		must := boolQuery["must"].([]any)
+		assert.Len(t, must, 1)
+		assert.Contains(t, must[0].(map[string]any), "match")
+	})
+
+	t.Run("knn", func(t *testing.T) {
+		req := &search.Request{Knn: []types.KnnSearch{{Field: "vector", QueryVector: []float32{1, 2}}}}
+		assert.NoError(t, applyBoolFilters(req, io.BoolFilters))
+		assert.Nil(t, req.Query)
+
+		knn := requestBody(t, req)["knn"].([]any)[0].(map[string]any)
+		assert.Equal(t, expected, knn["filter"])
+	})
+
+	t.Run("empty request", func(t *testing.T) {
+		req := &search.Request{}
+		assert.NoError(t, applyBoolFilters(req, io.BoolFilters))
+
+		boolQuery := requestBody(t, req)["query"].(map[string]any)["bool"].(map[string]any)
+		assert.Equal(t, expected, boolQuery["filter"])
+		assert.NotContains(t, boolQuery, "must")
+	})
+
+	t.Run("retriever", func(t *testing.T) {
+		req, err := search.NewRequest().FromJSON(`{"retriever":{"standard":{"query":{"match_all":{}}}}}`)
+		assert.NoError(t, err)
+		assert.ErrorContains(t, applyBoolFilters(req, io.BoolFilters), "not supported")
+	})
+}
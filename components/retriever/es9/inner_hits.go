@@ -0,0 +1,75 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+)
+
+// MetadataKeyInnerHits is the Document.MetaData key of the inner hits of a hit, set by the default ResultParser.
+// Use GetInnerHits to read them.
+const MetadataKeyInnerHits = "_es9_inner_hits"
+
+// InnerHit is a nested object matched by a query with inner hits, e.g. a passage of a document
+// retrieved by search_mode.SearchModeNestedApproximate.
+type InnerHit struct {
+	// Offset is the position of the object in the nested field of the document.
+	Offset int
+	// Score is the score of the object.
+	Score float64
+	// Source is the _source of the object.
+	Source map[string]any
+}
+
+// GetInnerHits returns the inner hits of the document with the given name, the nested path by default,
+// best match first.
+func GetInnerHits(doc *schema.Document, name string) []*InnerHit {
+	if doc == nil || doc.MetaData == nil {
+		return nil
+	}
+	innerHits, _ := doc.MetaData[MetadataKeyInnerHits].(map[string][]*InnerHit)
+	return innerHits[name]
+}
+
+// parseInnerHits converts the inner hits of the hit, keyed by their name.
+func parseInnerHits(hit types.Hit) (map[string][]*InnerHit, error) {
+	innerHits := make(map[string][]*InnerHit, len(hit.InnerHits))
+	for name, result := range hit.InnerHits {
+		hits := make([]*InnerHit, 0, len(result.Hits.Hits))
+		for _, h := range result.Hits.Hits {
+			ih := &InnerHit{}
+			if h.Nested_ != nil {
+				ih.Offset = h.Nested_.Offset
+			}
+			if h.Score_ != nil {
+				ih.Score = float64(*h.Score_)
+			}
+			if h.Source_ != nil {
+				if err := json.Unmarshal(h.Source_, &ih.Source); err != nil {
+					return nil, fmt.Errorf("unmarshal inner hit '%s' failed: %v", name, err)
+				}
+			}
+			hits = append(hits, ih)
+		}
+		innerHits[name] = hits
+	}
+	return innerHits, nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInnerHits(t *testing.T) {
+	ctx := context.Background()
+	id, score, passageScore := "doc_1", types.Float64(0.9), types.Float64(0.95)
+	hit := types.Hit{
+		Id_:     &id,
+		Score_:  &score,
+		Source_: []byte(`{"content":"long document"}`),
+		InnerHits: map[string]types.InnerHitsResult{
+			"passages": {Hits: types.HitsMetadata{Hits: []types.Hit{{
+				Score_:  &passageScore,
+				Nested_: &types.NestedIdentity{Field: "passages", Offset: 4},
+				Source_: []byte(`{"text":"best passage"}`),
+			}}}},
+		},
+	}
+
+	doc, err := defaultResultParser(ctx, hit)
+	assert.NoError(t, err)
+	assert.Equal(t, "long document", doc.Content)
+	assert.Equal(t, []*InnerHit{{Offset: 4, Score: 0.95, Source: map[string]any{"text": "best passage"}}},
+		GetInnerHits(doc, "passages"))
+	assert.Nil(t, GetInnerHits(doc, "other"))
+
+	hit.InnerHits["passages"].Hits.Hits[0].Source_ = []byte(`not json`)
+	_, err = defaultResultParser(ctx, hit)
+	assert.ErrorContains(t, err, "unmarshal inner hit 'passages' failed")
+
+	doc, err = defaultResultParser(ctx, types.Hit{Id_: &id, Source_: []byte(`{"content":"a"}`)})
+	assert.NoError(t, err)
+	assert.NotContains(t, doc.MetaData, MetadataKeyInnerHits)
+	assert.Nil(t, GetInnerHits(doc, "passages"))
+}
@@ -26,6 +26,13 @@ import (
 type ImplOptions struct {
 	Filters      []types.Query      `json:"filters,omitempty"`
 	SparseVector map[string]float32 `json:"sparse_vector,omitempty"`
+	// BoolFilters are the filter clauses added by WithTermsFilter, WithRangeFilter and WithExistsFilter,
+	// in the JSON form of the query DSL.
+	BoolFilters []map[string]any `json:"bool_filters,omitempty"`
+	// Offset is the number of hits to skip, set by WithOffset.
+	Offset *int `json:"offset,omitempty"`
+	// Routing is the routing value of the search request, set by WithRouting.
+	Routing string `json:"routing,omitempty"`
 }
 
 // WithFilters sets filters for the retrieve query.
@@ -45,3 +52,63 @@ func WithSparseVector(sparse map[string]float32) retriever.Option {
 		o.SparseVector = sparse
 	})
 }
+
+// WithOffset skips the first from hits, e.g. to fetch the pages after the first one in UI pagination.
+// The page, from + TopK, must not exceed RetrieverConfig.MaxResultWindow,
+// use a point in time (PIT) with search_after to page through deep results.
+func WithOffset(from int) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.Offset = &from
+	})
+}
+
+// WithRouting sets the routing value of the search request, so only the shards of the value are searched.
+// It is required for the custom-routed indices, e.g. sharded by tenant, where the documents are indexed
+// with a routing value, otherwise the search fans out to all the shards.
+// Multiple values can be given separated by commas.
+func WithRouting(value string) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.Routing = value
+	})
+}
+
+// WithTermsFilter filters the documents whose field matches any of the values.
+// Unlike WithFilters, the filter is merged into the bool.filter section of the request regardless of the search mode.
+// It can be given multiple times, all the filters must match.
+func WithTermsFilter(field string, values ...any) retriever.Option {
+	return withBoolFilter(map[string]any{
+		"terms": map[string]any{field: values},
+	})
+}
+
+// WithRangeFilter filters the documents whose field is within [gte, lte], e.g. numbers or dates.
+// A nil bound leaves the range unbounded on that side.
+// Unlike WithFilters, the filter is merged into the bool.filter section of the request regardless of the search mode.
+// It can be given multiple times, all the filters must match.
+func WithRangeFilter(field string, gte, lte any) retriever.Option {
+	bounds := make(map[string]any, 2)
+	if gte != nil {
+		bounds["gte"] = gte
+	}
+	if lte != nil {
+		bounds["lte"] = lte
+	}
+	return withBoolFilter(map[string]any{
+		"range": map[string]any{field: bounds},
+	})
+}
+
+// WithExistsFilter filters the documents which have an indexed value of the field.
+// Unlike WithFilters, the filter is merged into the bool.filter section of the request regardless of the search mode.
+// It can be given multiple times, all the filters must match.
+func WithExistsFilter(field string) retriever.Option {
+	return withBoolFilter(map[string]any{
+		"exists": map[string]any{"field": field},
+	})
+}
+
+func withBoolFilter(filter map[string]any) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.BoolFilters = append(o.BoolFilters, filter)
+	})
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+)
+
+// applyOffset sets the offset of WithOffset to the request, and checks that the page is within the max result window.
+// The k of the knn searches, which defaults to the size in Elasticsearch, is raised to cover the page,
+// otherwise the pages after the first one would be empty, and so is num_candidates which must not be less than k.
+func applyOffset(req *search.Request, offset *int, topK, maxResultWindow int) error {
+	if offset != nil {
+		if *offset < 0 {
+			return fmt.Errorf("offset must not be negative, got=%d", *offset)
+		}
+		from := *offset
+		req.From = &from
+	}
+	if req.From == nil {
+		return nil
+	}
+
+	size := topK
+	if req.Size != nil {
+		size = *req.Size
+	}
+	end := *req.From + size
+	if end > maxResultWindow {
+		return fmt.Errorf("offset %d + size %d exceeds the max result window %d, "+
+			"use a point in time (PIT) with search_after to page through deep results", *req.From, size, maxResultWindow)
+	}
+
+	for i := range req.Knn {
+		knn := &req.Knn[i]
+		if knn.K == nil || *knn.K < end {
+			k := end
+			knn.K = &k
+		}
+		if knn.NumCandidates != nil && *knn.NumCandidates < *knn.K {
+			numCandidates := *knn.K
+			knn.NumCandidates = &numCandidates
+		}
+	}
+	return nil
+}
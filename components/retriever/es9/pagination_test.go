@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOffset(t *testing.T) {
+	io := retriever.GetImplSpecificOptions(&ImplOptions{}, WithOffset(20))
+	assert.Equal(t, 20, *io.Offset)
+
+	r, err := NewRetriever(context.Background(), &RetrieverConfig{
+		Client:     &elasticsearch.Client{},
+		SearchMode: &mockSearchMode{},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultMaxResultWindow, r.config.MaxResultWindow)
+}
+
+func TestApplyOffset(t *testing.T) {
+	offset := func(from int) *int { return &from }
+
+	t.Run("no offset", func(t *testing.T) {
+		req := &search.Request{Knn: []types.KnnSearch{{Field: "vector"}}}
+		assert.NoError(t, applyOffset(req, nil, 10, defaultMaxResultWindow))
+		assert.Nil(t, req.From)
+		assert.Nil(t, req.Knn[0].K)
+	})
+
+	t.Run("query", func(t *testing.T) {
+		size := 5
+		req := &search.Request{Query: &types.Query{MatchAll: &types.MatchAllQuery{}}, Size: &size}
+		assert.NoError(t, applyOffset(req, offset(20), 10, defaultMaxResultWindow))
+		assert.Equal(t, 20, *req.From)
+		assert.Equal(t, 5, *req.Size)
+	})
+
+	t.Run("knn k covers the page", func(t *testing.T) {
+		k, small, numCandidates := 100, 5, 20
+		req := &search.Request{Knn: []types.KnnSearch{
+			{Field: "vector"},
+			{Field: "image_vector", K: &k},
+			{Field: "text_vector", K: &small, NumCandidates: &numCandidates},
+		}}
+		assert.NoError(t, applyOffset(req, offset(20), 10, defaultMaxResultWindow))
+		assert.Equal(t, 30, *req.Knn[0].K)
+		assert.Equal(t, 100, *req.Knn[1].K)
+		assert.Equal(t, 30, *req.Knn[2].K)
+		assert.Equal(t, 30, *req.Knn[2].NumCandidates)
+		// the values set by the user are not modified
+		assert.Equal(t, 5, small)
+		assert.Equal(t, 20, numCandidates)
+	})
+
+	t.Run("from of the search mode", func(t *testing.T) {
+		from := 9995
+		req := &search.Request{From: &from}
+		assert.ErrorContains(t, applyOffset(req, nil, 10, defaultMaxResultWindow),
+			"offset 9995 + size 10 exceeds the max result window 10000")
+	})
+
+	t.Run("deep paging", func(t *testing.T) {
+		req := &search.Request{}
+		err := applyOffset(req, offset(95), 10, 100)
+		assert.ErrorContains(t, err, "exceeds the max result window 100")
+		assert.ErrorContains(t, err, "point in time (PIT) with search_after")
+
+		assert.NoError(t, applyOffset(&search.Request{}, offset(90), 10, 100))
+	})
+
+	t.Run("negative offset", func(t *testing.T) {
+		assert.ErrorContains(t, applyOffset(&search.Request{}, offset(-1), 10, defaultMaxResultWindow), "must not be negative")
+	})
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+)
+
+const defaultRerankWindowSize = 50
+
+// RerankConfig configures the second-stage semantic reranking by the text_similarity_reranker retriever,
+// which reranks the documents of the search mode with a rerank inference endpoint of Elasticsearch,
+// e.g. the Elastic Rerank model or a Cohere / Jina AI rerank endpoint created by the inference API.
+// The returned documents are ordered by the rerank scores, which are also their scores.
+// See https://www.elastic.co/docs/reference/elasticsearch/rest-apis/retrievers/text-similarity-reranker-retriever
+type RerankConfig struct {
+	// InferenceID is the id of the rerank inference endpoint, e.g. ".rerank-v1-elasticsearch".
+	// Required.
+	InferenceID string `json:"inference_id"`
+	// Field is the text field of the documents compared with the query.
+	// Default is "content".
+	Field string `json:"field"`
+	// RankWindowSize is the number of top documents of the search mode to rerank, at least the offset + TopK.
+	// Default is the larger of TopK and 50.
+	RankWindowSize int `json:"rank_window_size"`
+}
+
+func (c *RerankConfig) validate() error {
+	if c.InferenceID == "" {
+		return fmt.Errorf("rerank inference id not provided")
+	}
+	if c.RankWindowSize < 0 {
+		return fmt.Errorf("rerank window size must not be negative, got=%d", c.RankWindowSize)
+	}
+	if c.Field == "" {
+		c.Field = contentField
+	}
+	return nil
+}
+
+// rerankRequest wraps the query and knn of the request built by the search mode into a first-stage retriever,
+// which is reranked by a text_similarity_reranker retriever with the query as the inference text.
+// The min_score of the request, i.e. the score threshold, is applied to the rerank scores.
+func rerankRequest(req *search.Request, conf *RerankConfig, query string, topK int) (*search.Request, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("[rerankRequest] marshal request failed: %w", err)
+	}
+	body := make(map[string]any)
+	if err = json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("[rerankRequest] unmarshal request failed: %w", err)
+	}
+
+	if req.Size != nil {
+		topK = *req.Size
+	}
+	if req.From != nil {
+		// the requested page must be within the reranked documents
+		topK += *req.From
+	}
+	windowSize := conf.RankWindowSize
+	if windowSize == 0 {
+		windowSize = defaultRerankWindowSize
+	}
+	if windowSize < topK {
+		windowSize = topK
+	}
+
+	first, err := firstStageRetriever(body, windowSize)
+	if err != nil {
+		return nil, err
+	}
+	reranker := map[string]any{
+		"retriever":        first,
+		"field":            conf.Field,
+		"inference_id":     conf.InferenceID,
+		"inference_text":   query,
+		"rank_window_size": windowSize,
+	}
+	if minScore, ok := body["min_score"]; ok {
+		reranker["min_score"] = minScore
+	}
+	for _, k := range []string{"query", "knn", "rank", "min_score"} {
+		delete(body, k)
+	}
+	body["retriever"] = map[string]any{"text_similarity_reranker": reranker}
+
+	if data, err = json.Marshal(body); err != nil {
+		return nil, fmt.Errorf("[rerankRequest] marshal rerank request failed: %w", err)
+	}
+	return search.NewRequest().FromJSON(string(data))
+}
+
+// firstStageRetriever converts the query and knn of the request body into a retriever:
+// a standard retriever for the query, knn retrievers for the knn searches, combined by a rrf retriever if ranked by RRF.
+func firstStageRetriever(body map[string]any, windowSize int) (map[string]any, error) {
+	if _, ok := body["retriever"]; ok {
+		return nil, fmt.Errorf("[rerankRequest] request with retriever is not supported")
+	}
+
+	var retrievers []any
+	if q, ok := body["query"]; ok {
+		retrievers = append(retrievers, map[string]any{"standard": map[string]any{"query": q}})
+	}
+	knns, _ := body["knn"].([]any)
+	for _, v := range knns {
+		knn, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("[rerankRequest] invalid knn search: %v", v)
+		}
+		// k and num_candidates are required by the knn retriever, which does not support boost
+		if _, ok = knn["k"]; !ok {
+			knn["k"] = windowSize
+		}
+		if _, ok = knn["num_candidates"]; !ok {
+			knn["num_candidates"] = knn["k"]
+		}
+		delete(knn, "boost")
+		retrievers = append(retrievers, map[string]any{"knn": knn})
+	}
+
+	switch {
+	case len(retrievers) == 0:
+		return nil, fmt.Errorf("[rerankRequest] request without query or knn can not be reranked")
+	case len(retrievers) == 1:
+		return retrievers[0].(map[string]any), nil
+	}
+
+	rank, _ := body["rank"].(map[string]any)
+	rrf, ok := rank["rrf"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("[rerankRequest] combining query and knn without RRF can not be reranked")
+	}
+	combined := make(map[string]any, len(rrf)+1)
+	for k, v := range rrf {
+		combined[k] = v
+	}
+	combined["retrievers"] = retrievers
+	return map[string]any{"rrf": combined}, nil
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// rerankBody returns the text_similarity_reranker of the marshaled request.
+func rerankBody(t *testing.T, req *search.Request) (map[string]any, map[string]any) {
+	data, err := json.Marshal(req)
+	assert.NoError(t, err)
+	body := make(map[string]any)
+	assert.NoError(t, json.Unmarshal(data, &body))
+	retriever, _ := body["retriever"].(map[string]any)
+	reranker, _ := retriever["text_similarity_reranker"].(map[string]any)
+	return body, reranker
+}
+
+func TestRerankConfig_validate(t *testing.T) {
+	assert.Error(t, (&RerankConfig{}).validate())
+	assert.Error(t, (&RerankConfig{InferenceID: "rerank", RankWindowSize: -1}).validate())
+
+	conf := &RerankConfig{InferenceID: "rerank"}
+	assert.NoError(t, conf.validate())
+	assert.Equal(t, contentField, conf.Field)
+
+	_, err := NewRetriever(context.Background(), &RetrieverConfig{
+		Client:     &elasticsearch.Client{},
+		SearchMode: &mockSearchMode{},
+		Rerank:     &RerankConfig{},
+	})
+	assert.ErrorContains(t, err, "invalid rerank config")
+}
+
+func TestRerankRequest(t *testing.T) {
+	conf := &RerankConfig{InferenceID: "my-rerank", Field: "content"}
+
+	t.Run("query", func(t *testing.T) {
+		size := 5
+		minScore := types.Float64(0.5)
+		req := &search.Request{
+			Query:    &types.Query{Match: map[string]types.MatchQuery{"content": {Query: "hello"}}},
+			Size:     &size,
+			MinScore: &minScore,
+			Source_:  false,
+		}
+		wrapped, err := rerankRequest(req, conf, "hello", 10)
+		assert.NoError(t, err)
+		assert.Nil(t, wrapped.Query)
+		assert.Nil(t, wrapped.MinScore)
+		assert.Equal(t, 5, *wrapped.Size)
+
+		body, reranker := rerankBody(t, wrapped)
+		assert.Equal(t, false, body["_source"])
+		assert.Equal(t, "my-rerank", reranker["inference_id"])
+		assert.Equal(t, "hello", reranker["inference_text"])
+		assert.Equal(t, "content", reranker["field"])
+		assert.Equal(t, float64(defaultRerankWindowSize), reranker["rank_window_size"])
+		assert.Equal(t, 0.5, reranker["min_score"])
+		first := reranker["retriever"].(map[string]any)
+		assert.Contains(t, first["standard"].(map[string]any), "query")
+	})
+
+	t.Run("knn", func(t *testing.T) {
+		boost := float32(2)
+		req := &search.Request{Knn: []types.KnnSearch{{Field: "vector", QueryVector: []float32{1, 2}, Boost: &boost}}}
+		wrapped, err := rerankRequest(req, &RerankConfig{InferenceID: "my-rerank", Field: "content", RankWindowSize: 5}, "hello", 20)
+		assert.NoError(t, err)
+		assert.Nil(t, wrapped.Knn)
+
+		_, reranker := rerankBody(t, wrapped)
+		assert.Equal(t, float64(20), reranker["rank_window_size"])
+		knn := reranker["retriever"].(map[string]any)["knn"].(map[string]any)
+		assert.Equal(t, "vector", knn["field"])
+		assert.Equal(t, float64(20), knn["k"])
+		assert.Equal(t, float64(20), knn["num_candidates"])
+		assert.NotContains(t, knn, "boost")
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		size, from := 10, 60
+		req := &search.Request{
+			Query: &types.Query{Match: map[string]types.MatchQuery{"content": {Query: "hello"}}},
+			Size:  &size,
+			From:  &from,
+		}
+		wrapped, err := rerankRequest(req, conf, "hello", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 60, *wrapped.From)
+
+		_, reranker := rerankBody(t, wrapped)
+		assert.Equal(t, float64(70), reranker["rank_window_size"])
+	})
+
+	t.Run("hybrid with rrf", func(t *testing.T) {
+		k := 10
+		req := &search.Request{
+			Query: &types.Query{Match: map[string]types.MatchQuery{"content": {Query: "hello"}}},
+			Knn:   []types.KnnSearch{{Field: "vector", QueryVector: []float32{1, 2}, K: &k, NumCandidates: &k}},
+			Rank:  &types.RankContainer{Rrf: &types.RrfRank{}},
+		}
+		wrapped, err := rerankRequest(req, conf, "hello", 10)
+		assert.NoError(t, err)
+		assert.Nil(t, wrapped.Rank)
+
+		_, reranker := rerankBody(t, wrapped)
+		rrf := reranker["retriever"].(map[string]any)["rrf"].(map[string]any)
+		assert.Len(t, rrf["retrievers"], 2)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := rerankRequest(&search.Request{}, conf, "hello", 10)
+		assert.ErrorContains(t, err, "without query or knn")
+
+		_, err = rerankRequest(&search.Request{
+			Query: &types.Query{MatchAll: &types.MatchAllQuery{}},
+			Knn:   []types.KnnSearch{{Field: "vector", QueryVector: []float32{1, 2}}},
+		}, conf, "hello", 10)
+		assert.ErrorContains(t, err, "without RRF")
+	})
+}
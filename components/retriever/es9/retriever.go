@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path"
 
 	"github.com/cloudwego/eino/components"
 	"github.com/elastic/go-elasticsearch/v9"
@@ -44,10 +45,15 @@ type RetrieverConfig struct {
 	TopK int `json:"top_k"`
 	// ScoreThreshold filters results with a similarity score below this value.
 	ScoreThreshold *float64 `json:"score_threshold"`
+	// MaxResultWindow is the index.max_result_window setting of the index,
+	// requests whose WithOffset offset + TopK exceeds it are rejected before being sent.
+	// Default is 10000.
+	MaxResultWindow int `json:"max_result_window"`
 
 	// SearchMode defines the strategy for retrieval (e.g., dense vector, keyword).
 	// use search_mode.SearchModeExactMatch with string query
 	// use search_mode.SearchModeApproximate with search_mode.ApproximateQuery
+	// use search_mode.SearchModeNestedApproximate with string query, for vectors of nested passages
 	// use search_mode.SearchModeDenseVectorSimilarity with search_mode.DenseVectorSimilarityQuery
 	// use search_mode.SearchModeSparseVectorTextExpansion with search_mode.SparseVectorTextExpansionQuery
 	// use search_mode.SearchModeRawStringRequest with json search request
@@ -58,6 +64,39 @@ type RetrieverConfig struct {
 	// Embedding is the embedding model used for vectorization.
 	// It is required when SearchMode needs it.
 	Embedding embedding.Embedder
+	// EnableCallbacks is false to let the graph running the retriever report its callbacks,
+	// without the search stats extra. Default is true.
+	EnableCallbacks *bool `json:"enable_callbacks,omitempty"`
+
+	// SourceIncludes lists the _source fields to return, wildcards are supported.
+	// Use it with SourceExcludes to avoid fetching large fields, e.g. vectors or the full content, in high-TopK retrieval.
+	// Default returns the whole _source.
+	SourceIncludes []string `json:"source_includes"`
+	// SourceExcludes lists the _source fields not to return, wildcards are supported.
+	SourceExcludes []string `json:"source_excludes"`
+	// StoredFields lists the stored fields to return in the fields of the hits.
+	// Note that Elasticsearch does not return _source when StoredFields is set, unless SourceIncludes or SourceExcludes is set.
+	StoredFields []string `json:"stored_fields"`
+
+	// Rerank enables the second-stage semantic reranking of the documents of the search mode
+	// by a rerank inference endpoint of Elasticsearch, see RerankConfig.
+	// ScoreThreshold applies to the rerank scores when it is set.
+	// Optional.
+	Rerank *RerankConfig `json:"rerank"`
+
+	// ValidateVectorDims checks the query vector of the Embedding against the dims and element_type
+	// of the dense_vector field in the index mapping, returning a *VectorDimsError naming the field and dims
+	// instead of an opaque 400 response of Elasticsearch.
+	// The mapping of each index is fetched at its first Retrieve, and the check of an index stops once a query vector fits.
+	// Only applies to search modes implementing VectorFieldReporter.
+	// Default is false.
+	ValidateVectorDims bool `json:"validate_vector_dims"`
+
+	// AttachSearchStats sets the *SearchStats of the search, i.e. the took time, timed_out and the shard statistics,
+	// in the metadata of every returned document under MetadataKeySearchStats, see GetSearchStats.
+	// The stats are always reported in the callback output Extra under CallbackExtraKeySearchStats.
+	// Default is false.
+	AttachSearchStats bool `json:"attach_search_stats"`
 }
 
 // SearchMode defines the interface for building Elasticsearch search requests.
@@ -72,6 +111,8 @@ type SearchMode interface {
 type Retriever struct {
 	client *elasticsearch.Client
 	config *RetrieverConfig
+
+	dims vectorDimsValidator
 }
 
 // NewRetriever creates a new ES9 retriever with the provided configuration.
@@ -84,13 +125,28 @@ func NewRetriever(_ context.Context, conf *RetrieverConfig) (*Retriever, error)
 		conf.TopK = defaultTopK
 	}
 
+	if conf.MaxResultWindow == 0 {
+		conf.MaxResultWindow = defaultMaxResultWindow
+	}
+
 	if conf.ResultParser == nil {
-		conf.ResultParser = defaultResultParser
+		if contentFetched(conf) {
+			conf.ResultParser = defaultResultParser
+		} else {
+			// metadata only retrieval, the content is filtered out by the config
+			conf.ResultParser = newDefaultResultParser(false)
+		}
 	}
 
 	if conf.Client == nil {
 		return nil, fmt.Errorf("[NewRetriever] es client not provided")
 	}
+
+	if conf.Rerank != nil {
+		if err := conf.Rerank.validate(); err != nil {
+			return nil, fmt.Errorf("[NewRetriever] invalid rerank config: %w", err)
+		}
+	}
 	return &Retriever{
 		client: conf.Client,
 		config: conf,
@@ -107,26 +163,63 @@ func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retrieve
 	}, opts...)
 
 	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
-	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
-		Query:          query,
-		TopK:           *options.TopK,
-		ScoreThreshold: options.ScoreThreshold,
-	})
+	cbEnabled := r.IsCallbacksEnabled()
+	if cbEnabled {
+		ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+			Query:          query,
+			TopK:           *options.TopK,
+			ScoreThreshold: options.ScoreThreshold,
+		})
+	}
 	defer func() {
-		if err != nil {
+		if err != nil && cbEnabled {
 			callbacks.OnError(ctx, err)
 		}
 	}()
 
+	var (
+		vectorField string
+		recorder    *vectorRecorder
+	)
+	if reporter, ok := r.config.SearchMode.(VectorFieldReporter); ok && r.config.ValidateVectorDims &&
+		options.Embedding != nil && !r.dims.isValidated(*options.Index) {
+		if vectorField = reporter.VectorField(); vectorField != "" {
+			recorder = &vectorRecorder{Embedder: options.Embedding}
+			opts = append(opts[:len(opts):len(opts)], retriever.WithEmbedding(recorder))
+		}
+	}
+
 	req, err := r.config.SearchMode.BuildRequest(ctx, r.config, query, opts...)
 	if err != nil {
 		return nil, err
 	}
+	if recorder != nil && recorder.vector != nil {
+		if err = r.dims.validate(ctx, r, *options.Index, vectorField, recorder.vector); err != nil {
+			return nil, err
+		}
+	}
+	io := retriever.GetImplSpecificOptions(&ImplOptions{}, opts...)
+	if err = applyBoolFilters(req, io.BoolFilters); err != nil {
+		return nil, err
+	}
+	if err = applyOffset(req, io.Offset, *options.TopK, r.config.MaxResultWindow); err != nil {
+		return nil, err
+	}
+	r.applyFieldFilters(req)
+	if r.config.Rerank != nil {
+		req, err = rerankRequest(req, r.config.Rerank, query, *options.TopK)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	resp, err := search.NewSearchFunc(r.client)().
+	s := search.NewSearchFunc(r.client)().
 		Index(r.config.Index).
-		Request(req).
-		Do(ctx)
+		Request(req)
+	if io.Routing != "" {
+		s = s.Routing(io.Routing)
+	}
+	resp, err := s.Do(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +229,19 @@ func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retrieve
 		return nil, err
 	}
 
-	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+	if !cbEnabled && !r.config.AttachSearchStats {
+		return docs, nil
+	}
+	stats := newSearchStats(resp)
+	if r.config.AttachSearchStats {
+		attachSearchStats(docs, stats)
+	}
+	if cbEnabled {
+		callbacks.OnEnd(ctx, &retriever.CallbackOutput{
+			Docs:  docs,
+			Extra: map[string]any{CallbackExtraKeySearchStats: stats},
+		})
+	}
 
 	return docs, nil
 }
@@ -164,54 +269,125 @@ func (r *Retriever) GetType() string {
 	return typ
 }
 
-// IsCallbacksEnabled checks if callbacks are enabled for this retriever.
+// IsCallbacksEnabled reports whether the retriever reports its callbacks itself, see RetrieverConfig.EnableCallbacks.
 func (r *Retriever) IsCallbacksEnabled() bool {
-	return true
+	return r.config.EnableCallbacks == nil || *r.config.EnableCallbacks
 }
 
-func defaultResultParser(ctx context.Context, hit types.Hit) (*schema.Document, error) {
-	if hit.Id_ == nil {
-		return nil, fmt.Errorf("defaultResultParser: field '_id' not found in hit")
+// applyFieldFilters sets the _source filtering and stored_fields of the config to the request,
+// unless they are already set by the search mode.
+func (r *Retriever) applyFieldFilters(req *search.Request) {
+	if req.Source_ == nil && (len(r.config.SourceIncludes) > 0 || len(r.config.SourceExcludes) > 0) {
+		req.Source_ = &types.SourceFilter{
+			Includes: r.config.SourceIncludes,
+			Excludes: r.config.SourceExcludes,
+		}
 	}
-	id := *hit.Id_
-
-	score := 0.0
-	if hit.Score_ != nil {
-		score = float64(*hit.Score_)
+	if req.StoredFields == nil && len(r.config.StoredFields) > 0 {
+		req.StoredFields = r.config.StoredFields
 	}
+}
 
-	if hit.Source_ == nil {
-		return nil, fmt.Errorf("defaultResultParser: field '_source' not found in document %s", id)
+// contentFetched reports whether the content field is returned with the field filters of the config.
+func contentFetched(conf *RetrieverConfig) bool {
+	if containsField(conf.StoredFields, contentField) {
+		return true
 	}
-
-	var source map[string]any
-	if err := json.Unmarshal(hit.Source_, &source); err != nil {
-		return nil, fmt.Errorf("defaultResultParser: unmarshal document content failed: %v", err)
+	if len(conf.StoredFields) > 0 && len(conf.SourceIncludes) == 0 && len(conf.SourceExcludes) == 0 {
+		// _source is disabled by stored_fields
+		return false
 	}
-
-	val, ok := source["content"]
-	if !ok {
-		return nil, fmt.Errorf("defaultResultParser: field 'content' not found in document %s; please use a custom ResultParser or ensure index mapping has 'content' field", id)
+	if containsField(conf.SourceExcludes, contentField) {
+		return false
 	}
+	return len(conf.SourceIncludes) == 0 || containsField(conf.SourceIncludes, contentField)
+}
 
-	content, ok := val.(string)
-	if !ok {
-		return nil, fmt.Errorf("defaultResultParser: field 'content' in document %s is not a string", id)
+// containsField reports whether any of the field patterns matches the field.
+func containsField(patterns []string, field string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, field); matched {
+			return true
+		}
 	}
+	return false
+}
 
-	// Remove content from metadata to avoid duplication if it's large
-	meta := make(map[string]any, len(source)+1)
-	for k, v := range source {
-		if k != "content" {
-			meta[k] = v
+var defaultResultParser = newDefaultResultParser(true)
+
+// newDefaultResultParser returns a parser which uses the content field as Document.Content,
+// and the other _source fields and the stored fields as Document.MetaData, with the inner hits under MetadataKeyInnerHits.
+// Documents without content are returned with an empty Content if requireContent is false.
+func newDefaultResultParser(requireContent bool) func(ctx context.Context, hit types.Hit) (*schema.Document, error) {
+	return func(ctx context.Context, hit types.Hit) (*schema.Document, error) {
+		if hit.Id_ == nil {
+			return nil, fmt.Errorf("defaultResultParser: field '_id' not found in hit")
 		}
-	}
-	meta["score"] = score
+		id := *hit.Id_
 
-	doc := &schema.Document{
-		ID:       id,
-		Content:  content,
-		MetaData: meta,
+		score := 0.0
+		if hit.Score_ != nil {
+			score = float64(*hit.Score_)
+		}
+
+		if hit.Source_ == nil && len(hit.Fields) == 0 {
+			return nil, fmt.Errorf("defaultResultParser: field '_source' not found in document %s", id)
+		}
+
+		source := make(map[string]any)
+		if hit.Source_ != nil {
+			if err := json.Unmarshal(hit.Source_, &source); err != nil {
+				return nil, fmt.Errorf("defaultResultParser: unmarshal document content failed: %v", err)
+			}
+		}
+		for k, raw := range hit.Fields {
+			if _, ok := source[k]; ok {
+				continue
+			}
+			var values []any
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return nil, fmt.Errorf("defaultResultParser: unmarshal stored field '%s' of document %s failed: %v", k, id, err)
+			}
+			// stored fields are always returned as arrays, unwrap single values to match _source
+			if len(values) == 1 {
+				source[k] = values[0]
+			} else {
+				source[k] = values
+			}
+		}
+
+		var content string
+		val, ok := source[contentField]
+		if ok {
+			content, ok = val.(string)
+			if !ok {
+				return nil, fmt.Errorf("defaultResultParser: field 'content' in document %s is not a string", id)
+			}
+		} else if requireContent {
+			return nil, fmt.Errorf("defaultResultParser: field 'content' not found in document %s; please use a custom ResultParser or ensure index mapping has 'content' field", id)
+		}
+
+		// Remove content from metadata to avoid duplication if it's large
+		meta := make(map[string]any, len(source)+1)
+		for k, v := range source {
+			if k != contentField {
+				meta[k] = v
+			}
+		}
+		meta["score"] = score
+		if len(hit.InnerHits) > 0 {
+			innerHits, err := parseInnerHits(hit)
+			if err != nil {
+				return nil, fmt.Errorf("defaultResultParser: %v in document %s", err, id)
+			}
+			meta[MetadataKeyInnerHits] = innerHits
+		}
+
+		doc := &schema.Document{
+			ID:       id,
+			Content:  content,
+			MetaData: meta,
+		}
+		return doc.WithScore(score), nil
 	}
-	return doc.WithScore(score), nil
 }
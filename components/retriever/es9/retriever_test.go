@@ -248,3 +248,109 @@ type mockSearchMode struct{}
 func (m *mockSearchMode) BuildRequest(ctx context.Context, conf *RetrieverConfig, query string, opts ...retriever.Option) (*search.Request, error) {
 	return &search.Request{}, nil
 }
+
+func TestFieldFilters(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("apply_to_request", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &RetrieverConfig{
+			Client:         &elasticsearch.Client{},
+			Index:          "eino_ut",
+			SearchMode:     &mockSearchMode{},
+			SourceIncludes: []string{"content", "meta.*"},
+			SourceExcludes: []string{"vector"},
+			StoredFields:   []string{"title"},
+		})
+		assert.NoError(t, err)
+
+		req := &search.Request{}
+		r.applyFieldFilters(req)
+		assert.Equal(t, &types.SourceFilter{Includes: []string{"content", "meta.*"}, Excludes: []string{"vector"}}, req.Source_)
+		assert.Equal(t, []string{"title"}, req.StoredFields)
+
+		// filters set by the search mode are kept
+		req = &search.Request{Source_: false, StoredFields: []string{"_none_"}}
+		r.applyFieldFilters(req)
+		assert.Equal(t, false, req.Source_)
+		assert.Equal(t, []string{"_none_"}, req.StoredFields)
+	})
+
+	t.Run("content_fetched", func(t *testing.T) {
+		assert.True(t, contentFetched(&RetrieverConfig{}))
+		assert.True(t, contentFetched(&RetrieverConfig{SourceIncludes: []string{"cont*"}}))
+		assert.True(t, contentFetched(&RetrieverConfig{StoredFields: []string{"content"}}))
+		assert.False(t, contentFetched(&RetrieverConfig{SourceIncludes: []string{"title"}}))
+		assert.False(t, contentFetched(&RetrieverConfig{SourceExcludes: []string{"content"}}))
+		assert.False(t, contentFetched(&RetrieverConfig{StoredFields: []string{"title"}}))
+	})
+
+	t.Run("metadata_only", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &RetrieverConfig{
+			Client:         &elasticsearch.Client{},
+			Index:          "eino_ut",
+			SearchMode:     &mockSearchMode{},
+			SourceExcludes: []string{"content"},
+			StoredFields:   []string{"title", "tags"},
+		})
+		assert.NoError(t, err)
+
+		mockSearch := search.NewSearchFunc(r.client)()
+
+		defer mockey.Mock(mockey.GetMethod(mockSearch, "Index")).
+			Return(mockSearch).Build().Patch().UnPatch()
+
+		defer mockey.Mock(mockey.GetMethod(mockSearch, "Request")).
+			Return(mockSearch).Build().Patch().UnPatch()
+
+		defer mockey.Mock(mockey.GetMethod(mockSearch, "Do")).Return(&search.Response{
+			Hits: types.HitsMetadata{
+				Hits: []types.Hit{
+					{
+						Id_:     func() *string { s := "doc_1"; return &s }(),
+						Source_: json.RawMessage([]byte(`{"extra": "metadata"}`)),
+						Fields: map[string]json.RawMessage{
+							"title": json.RawMessage(`["hello"]`),
+							"tags":  json.RawMessage(`["a", "b"]`),
+						},
+					},
+				},
+			},
+		}, nil).Build().Patch().UnPatch()
+
+		docs, err := r.Retrieve(ctx, "test query")
+		assert.NoError(t, err)
+		assert.Len(t, docs, 1)
+		assert.Equal(t, "", docs[0].Content)
+		assert.Equal(t, "metadata", docs[0].MetaData["extra"])
+		assert.Equal(t, "hello", docs[0].MetaData["title"])
+		assert.Equal(t, []any{"a", "b"}, docs[0].MetaData["tags"])
+	})
+}
+
+func TestWithRouting(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRetriever(ctx, &RetrieverConfig{
+		Client:     &elasticsearch.Client{},
+		Index:      "eino_ut",
+		TopK:       10,
+		SearchMode: &mockSearchMode{},
+	})
+	assert.NoError(t, err)
+
+	mockSearch := search.NewSearchFunc(r.client)()
+	defer mockey.Mock(mockey.GetMethod(mockSearch, "Do")).Return(&search.Response{}, nil).Build().Patch().UnPatch()
+
+	var routing []string
+	defer mockey.Mock(mockey.GetMethod(mockSearch, "Routing")).To(func(s *search.Search, value string) *search.Search {
+		routing = append(routing, value)
+		return s
+	}).Build().Patch().UnPatch()
+
+	_, err = r.Retrieve(ctx, "how are you")
+	assert.NoError(t, err)
+	assert.Empty(t, routing)
+
+	_, err = r.Retrieve(ctx, "how are you", WithRouting("tenant-42"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tenant-42"}, routing)
+}
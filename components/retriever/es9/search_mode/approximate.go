@@ -75,6 +75,14 @@ type approximate struct {
 	config *ApproximateConfig
 }
 
+// VectorField implements es9.VectorFieldReporter, the query vector is built by Elasticsearch with QueryVectorBuilderModelID.
+func (a *approximate) VectorField() string {
+	if a.config.QueryVectorBuilderModelID != nil {
+		return ""
+	}
+	return a.config.VectorFieldName
+}
+
 func (a *approximate) BuildRequest(ctx context.Context, conf *es9.RetrieverConfig, query string, opts ...retriever.Option) (*search.Request, error) {
 
 	co := retriever.GetCommonOptions(&retriever.Options{
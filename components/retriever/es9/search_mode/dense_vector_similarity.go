@@ -30,11 +30,17 @@ import (
 // SearchModeDenseVectorSimilarity calculates the embedding similarity between a dense_vector field and the query.
 // See: https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-script-score-query.html#vector-functions
 func SearchModeDenseVectorSimilarity(typ DenseVectorSimilarityType, vectorFieldName string) es9.SearchMode {
-	return &denseVectorSimilarity{fmt.Sprintf(denseVectorScriptMap[typ], vectorFieldName)}
+	return &denseVectorSimilarity{script: fmt.Sprintf(denseVectorScriptMap[typ], vectorFieldName), vectorField: vectorFieldName}
 }
 
 type denseVectorSimilarity struct {
-	script string
+	script      string
+	vectorField string
+}
+
+// VectorField implements es9.VectorFieldReporter.
+func (d *denseVectorSimilarity) VectorField() string {
+	return d.vectorField
 }
 
 func (d *denseVectorSimilarity) BuildRequest(ctx context.Context, conf *es9.RetrieverConfig, query string,
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types/enums/childscoremode"
+
+	"github.com/cloudwego/eino-ext/components/retriever/es9"
+)
+
+const defaultInnerHitsSize = 3
+
+// SearchModeNestedApproximate retrieves parent documents by a KNN search on a dense_vector field of nested objects,
+// e.g. the passages of a long document, each with its own vector.
+// The scores of the matched passages are aggregated into the score of the parent document by ScoreMode,
+// and the best matching passages are returned as inner hits, read them with es9.GetInnerHits.
+// See:
+//
+//	Nested KNN: https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-knn-query.html#knn-query-with-nested-query
+//	Inner hits: https://www.elastic.co/guide/en/elasticsearch/reference/current/inner-hits.html
+func SearchModeNestedApproximate(config *NestedApproximateConfig) es9.SearchMode {
+	return &nestedApproximate{config}
+}
+
+// NestedApproximateConfig contains configuration for the NestedApproximate search mode.
+type NestedApproximateConfig struct {
+	// Path is the path of the nested field holding the passages, e.g. "passages".
+	// This field is required.
+	Path string
+	// VectorFieldName is the full name of the dense_vector field of the passages, e.g. "passages.vector".
+	// This field is required.
+	VectorFieldName string
+	// QueryVectorBuilderModelID is the model ID for the query vector builder.
+	// See: https://www.elastic.co/guide/en/machine-learning/current/ml-nlp-text-emb-vector-search-example.html
+	QueryVectorBuilderModelID *string
+	// ScoreMode aggregates the scores of the matched passages into the score of the parent document,
+	// e.g. childscoremode.Avg to favor the documents matching as a whole over the ones with a single close passage.
+	// Default is childscoremode.Max, the score of the best passage.
+	ScoreMode *childscoremode.ChildScoreMode
+	// InnerHitsSize is the number of best matching passages returned with each parent document.
+	// Default is 3, a negative value disables the inner hits.
+	InnerHitsSize int
+	// InnerHitsSourceIncludes lists the passage fields returned in the inner hits, wildcards are supported.
+	// Default returns the whole passage.
+	InnerHitsSourceIncludes []string
+	// InnerHitsSourceExcludes lists the passage fields not returned in the inner hits, e.g. "passages.vector".
+	InnerHitsSourceExcludes []string
+	// Boost is a floating-point number used to decrease or increase the relevance scores of the query.
+	Boost *float32
+	// K is the number of nearest passages to return.
+	K *int
+	// NumCandidates is the number of nearest neighbor candidates to consider per shard.
+	NumCandidates *int
+	// Similarity is the minimum similarity for a passage to be considered a match.
+	Similarity *float32
+}
+
+type nestedApproximate struct {
+	config *NestedApproximateConfig
+}
+
+// VectorField implements es9.VectorFieldReporter, the query vector is built by Elasticsearch with QueryVectorBuilderModelID.
+func (n *nestedApproximate) VectorField() string {
+	if n.config == nil || n.config.QueryVectorBuilderModelID != nil {
+		return ""
+	}
+	return n.config.VectorFieldName
+}
+
+func (n *nestedApproximate) BuildRequest(ctx context.Context, conf *es9.RetrieverConfig, query string,
+	opts ...retriever.Option) (*search.Request, error) {
+
+	if n.config == nil || n.config.Path == "" || n.config.VectorFieldName == "" {
+		return nil, fmt.Errorf("[BuildRequest][SearchModeNestedApproximate] nested path or vector field not provided")
+	}
+
+	co := retriever.GetCommonOptions(&retriever.Options{
+		Index:          ptrWithoutZero(conf.Index),
+		TopK:           ptrWithoutZero(conf.TopK),
+		ScoreThreshold: conf.ScoreThreshold,
+		Embedding:      conf.Embedding,
+	}, opts...)
+
+	io := retriever.GetImplSpecificOptions[es9.ImplOptions](nil, opts...)
+
+	// the filters apply to the parent documents, they filter the passages during the search,
+	// so that the filtered out documents do not take the place of the matching ones in the top k
+	knn := &types.KnnQuery{
+		Field:         n.config.VectorFieldName,
+		Filter:        io.Filters,
+		K:             n.config.K,
+		NumCandidates: n.config.NumCandidates,
+		Similarity:    n.config.Similarity,
+	}
+
+	if n.config.QueryVectorBuilderModelID != nil {
+		knn.QueryVectorBuilder = &types.QueryVectorBuilder{TextEmbedding: &types.TextEmbedding{
+			ModelId:   *n.config.QueryVectorBuilderModelID,
+			ModelText: query,
+		}}
+	} else {
+		emb := co.Embedding
+		if emb == nil {
+			return nil, fmt.Errorf("[BuildRequest][SearchModeNestedApproximate] embedding not provided")
+		}
+
+		vector, err := emb.EmbedStrings(makeEmbeddingCtx(ctx, emb), []string{query})
+		if err != nil {
+			return nil, fmt.Errorf("[BuildRequest][SearchModeNestedApproximate] embedding failed, %w", err)
+		}
+
+		if len(vector) != 1 {
+			return nil, fmt.Errorf("[BuildRequest][SearchModeNestedApproximate] vector len error, expected=1, got=%d", len(vector))
+		}
+
+		knn.QueryVector = f64To32(vector[0])
+	}
+
+	nested := &types.NestedQuery{
+		Boost:     n.config.Boost,
+		Path:      n.config.Path,
+		Query:     types.Query{Knn: knn},
+		ScoreMode: n.config.ScoreMode,
+	}
+	if n.config.InnerHitsSize >= 0 {
+		name, size := n.config.Path, n.config.InnerHitsSize
+		if size == 0 {
+			size = defaultInnerHitsSize
+		}
+		nested.InnerHits = &types.InnerHits{Name: &name, Size: &size}
+		if len(n.config.InnerHitsSourceIncludes) > 0 || len(n.config.InnerHitsSourceExcludes) > 0 {
+			nested.InnerHits.Source_ = &types.SourceFilter{
+				Includes: n.config.InnerHitsSourceIncludes,
+				Excludes: n.config.InnerHitsSourceExcludes,
+			}
+		}
+	}
+
+	req := &search.Request{Query: &types.Query{Nested: nested}, Size: co.TopK}
+	if co.ScoreThreshold != nil {
+		req.MinScore = (*types.Float64)(ptrWithoutZero(*co.ScoreThreshold))
+	}
+
+	return req, nil
+}
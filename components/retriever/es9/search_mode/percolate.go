@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+
+	"github.com/cloudwego/eino-ext/components/retriever/es9"
+)
+
+// SearchModePercolate reverses the search: the index stores queries in a percolator field,
+// and the retriever returns the stored queries matching the document given as the query string,
+// e.g. to notify the subscribers whose saved query matches a newly arrived document.
+// See: https://www.elastic.co/guide/en/elasticsearch/reference/current/query-dsl-percolate-query.html
+func SearchModePercolate(config *PercolateConfig) es9.SearchMode {
+	return &percolate{config}
+}
+
+// PercolateConfig contains configuration for the Percolate search mode.
+type PercolateConfig struct {
+	// Field is the name of the percolator field storing the queries.
+	// This field is required.
+	Field string
+	// DocumentField is the field of the percolated document set to the query string,
+	// e.g. "content" to match the stored queries against a plain text.
+	// If empty, the query string must be the JSON of a document, or a JSON array of documents
+	// to percolate them at once.
+	DocumentField string
+}
+
+type percolate struct {
+	config *PercolateConfig
+}
+
+func (p *percolate) BuildRequest(ctx context.Context, conf *es9.RetrieverConfig, query string,
+	opts ...retriever.Option) (*search.Request, error) {
+
+	if p.config == nil || p.config.Field == "" {
+		return nil, fmt.Errorf("[BuildRequest][SearchModePercolate] percolator field not provided")
+	}
+
+	co := retriever.GetCommonOptions(&retriever.Options{
+		Index:          ptrWithoutZero(conf.Index),
+		TopK:           ptrWithoutZero(conf.TopK),
+		ScoreThreshold: conf.ScoreThreshold,
+		Embedding:      conf.Embedding,
+	}, opts...)
+
+	io := retriever.GetImplSpecificOptions[es9.ImplOptions](nil, opts...)
+
+	pq := &types.PercolateQuery{Field: p.config.Field}
+	if p.config.DocumentField != "" {
+		doc, err := json.Marshal(map[string]string{p.config.DocumentField: query})
+		if err != nil {
+			return nil, fmt.Errorf("[BuildRequest][SearchModePercolate] marshal document failed, %w", err)
+		}
+		pq.Document = doc
+	} else {
+		trimmed := strings.TrimSpace(query)
+		switch {
+		case strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)):
+			pq.Document = json.RawMessage(trimmed)
+		case strings.HasPrefix(trimmed, "["):
+			var docs []json.RawMessage
+			if err := json.Unmarshal([]byte(trimmed), &docs); err != nil {
+				return nil, fmt.Errorf("[BuildRequest][SearchModePercolate] unmarshal documents failed, %w", err)
+			}
+			if len(docs) == 0 {
+				return nil, fmt.Errorf("[BuildRequest][SearchModePercolate] no document to percolate")
+			}
+			pq.Documents = docs
+		default:
+			return nil, fmt.Errorf("[BuildRequest][SearchModePercolate] query must be a JSON document or an array of documents when DocumentField is empty")
+		}
+	}
+
+	q := &types.Query{Percolate: pq}
+	if len(io.Filters) > 0 {
+		q = &types.Query{
+			Bool: &types.BoolQuery{
+				Filter: io.Filters,
+				Must:   []types.Query{*q},
+			},
+		}
+	}
+
+	req := &search.Request{Query: q, Size: co.TopK}
+	if co.ScoreThreshold != nil {
+		req.MinScore = (*types.Float64)(ptrWithoutZero(*co.ScoreThreshold))
+	}
+
+	return req, nil
+}
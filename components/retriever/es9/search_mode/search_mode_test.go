@@ -23,6 +23,8 @@ import (
 
 	"github.com/cloudwego/eino-ext/components/retriever/es9"
 	"github.com/cloudwego/eino/components/embedding"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types/enums/childscoremode"
 	"github.com/smartystreets/goconvey/convey"
 )
 
@@ -224,6 +226,114 @@ func TestSparseVectorTextExpansion(t *testing.T) {
 	})
 }
 
+func TestPercolate(t *testing.T) {
+	convey.Convey("test Percolate", t, func() {
+		ctx := context.Background()
+		conf := &es9.RetrieverConfig{TopK: 10}
+
+		convey.Convey("test plain text document", func() {
+			searchMode := SearchModePercolate(&PercolateConfig{Field: "query", DocumentField: "content"})
+			req, err := searchMode.BuildRequest(ctx, conf, `breaking "news"`)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(req.Query.Percolate, convey.ShouldNotBeNil)
+			convey.So(req.Query.Percolate.Field, convey.ShouldEqual, "query")
+			convey.So(string(req.Query.Percolate.Document), convey.ShouldEqual, `{"content":"breaking \"news\""}`)
+			convey.So(*req.Size, convey.ShouldEqual, 10)
+		})
+
+		convey.Convey("test json documents", func() {
+			searchMode := SearchModePercolate(&PercolateConfig{Field: "query"})
+			req, err := searchMode.BuildRequest(ctx, conf, `{"content":"a","tag":"b"}`)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(string(req.Query.Percolate.Document), convey.ShouldEqual, `{"content":"a","tag":"b"}`)
+
+			req, err = searchMode.BuildRequest(ctx, conf, `[{"content":"a"},{"content":"b"}]`)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(req.Query.Percolate.Documents), convey.ShouldEqual, 2)
+
+			_, err = searchMode.BuildRequest(ctx, conf, "plain text")
+			convey.So(err, convey.ShouldNotBeNil)
+			_, err = searchMode.BuildRequest(ctx, conf, "[]")
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test with filters", func() {
+			searchMode := SearchModePercolate(&PercolateConfig{Field: "query", DocumentField: "content"})
+			req, err := searchMode.BuildRequest(ctx, conf, "news", es9.WithFilters([]types.Query{
+				{Term: map[string]types.TermQuery{"owner": {Value: "alice"}}},
+			}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(req.Query.Bool, convey.ShouldNotBeNil)
+			convey.So(len(req.Query.Bool.Filter), convey.ShouldEqual, 1)
+			convey.So(req.Query.Bool.Must[0].Percolate, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test missing field", func() {
+			_, err := SearchModePercolate(&PercolateConfig{}).BuildRequest(ctx, conf, "news")
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
+
+func TestNestedApproximate(t *testing.T) {
+	convey.Convey("test NestedApproximate", t, func() {
+		ctx := context.Background()
+		conf := &es9.RetrieverConfig{TopK: 10, Embedding: &MockEmbedder{}}
+
+		convey.Convey("test success", func() {
+			searchMode := SearchModeNestedApproximate(&NestedApproximateConfig{
+				Path:                    "passages",
+				VectorFieldName:         "passages.vector",
+				ScoreMode:               &childscoremode.Avg,
+				K:                       of(20),
+				InnerHitsSourceExcludes: []string{"passages.vector"},
+			})
+			convey.So(searchMode.(es9.VectorFieldReporter).VectorField(), convey.ShouldEqual, "passages.vector")
+
+			req, err := searchMode.BuildRequest(ctx, conf, "test_query")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(*req.Size, convey.ShouldEqual, 10)
+			nested := req.Query.Nested
+			convey.So(nested, convey.ShouldNotBeNil)
+			convey.So(nested.Path, convey.ShouldEqual, "passages")
+			convey.So(*nested.ScoreMode, convey.ShouldEqual, childscoremode.Avg)
+			convey.So(nested.Query.Knn.Field, convey.ShouldEqual, "passages.vector")
+			convey.So(*nested.Query.Knn.K, convey.ShouldEqual, 20)
+			convey.So(nested.Query.Knn.QueryVector, convey.ShouldResemble, []float32{0.1, 0.2})
+			convey.So(*nested.InnerHits.Name, convey.ShouldEqual, "passages")
+			convey.So(*nested.InnerHits.Size, convey.ShouldEqual, 3)
+			convey.So(nested.InnerHits.Source_, convey.ShouldResemble, &types.SourceFilter{Excludes: []string{"passages.vector"}})
+		})
+
+		convey.Convey("test with filters and without inner hits", func() {
+			searchMode := SearchModeNestedApproximate(&NestedApproximateConfig{
+				Path:            "passages",
+				VectorFieldName: "passages.vector",
+				InnerHitsSize:   -1,
+			})
+			req, err := searchMode.BuildRequest(ctx, conf, "test_query", es9.WithFilters([]types.Query{
+				{Term: map[string]types.TermQuery{"lang": {Value: "en"}}},
+			}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(req.Query.Bool, convey.ShouldBeNil)
+			convey.So(len(req.Query.Nested.Query.Knn.Filter), convey.ShouldEqual, 1)
+			convey.So(req.Query.Nested.InnerHits, convey.ShouldBeNil)
+		})
+
+		convey.Convey("test errors", func() {
+			_, err := SearchModeNestedApproximate(&NestedApproximateConfig{Path: "passages"}).BuildRequest(ctx, conf, "test_query")
+			convey.So(err, convey.ShouldNotBeNil)
+
+			conf.Embedding = &MockEmbedder{err: fmt.Errorf("mock error")}
+			_, err = SearchModeNestedApproximate(&NestedApproximateConfig{
+				Path:            "passages",
+				VectorFieldName: "passages.vector",
+			}).BuildRequest(ctx, conf, "test_query")
+			convey.So(err.Error(), convey.ShouldContainSubstring, "embedding failed")
+		})
+	})
+}
+
 func of[T any](v T) *T {
 	return &v
 }
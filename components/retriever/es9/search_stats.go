@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+)
+
+// CallbackExtraKeySearchStats is the key of the *SearchStats of the search in the Extra of retriever.CallbackOutput.
+const CallbackExtraKeySearchStats = "es9_search_stats"
+
+// MetadataKeySearchStats is the Document.MetaData key of the *SearchStats of the search which returned the document,
+// set when RetrieverConfig.AttachSearchStats is enabled. Use GetSearchStats to read it.
+const MetadataKeySearchStats = "_es9_search_stats"
+
+// SearchStats are the statistics of a search reported by Elasticsearch, to investigate the slow or partial searches
+// without enabling the slow logs of the index.
+type SearchStats struct {
+	// Took is the time Elasticsearch spent executing the search, excluding the network and the queueing on the client.
+	Took time.Duration
+	// TimedOut reports whether the search timed out, in which case the documents are the ones found until then.
+	TimedOut bool
+	// Shards are the statistics of the shards searched.
+	Shards ShardStats
+}
+
+// ShardStats are the numbers of shards searched, a search with failed shards returns the documents of the other shards.
+type ShardStats struct {
+	Total      int
+	Successful int
+	// Skipped is the number of shards skipped by the pre-filter, as none of their documents could match.
+	Skipped int
+	Failed  int
+	// Failures are the reasons of the failed shards.
+	Failures []ShardFailure
+}
+
+// ShardFailure is the failure of a shard.
+type ShardFailure struct {
+	Index  string
+	Node   string
+	Reason string
+}
+
+// GetSearchStats returns the statistics of the search which returned the document,
+// set when RetrieverConfig.AttachSearchStats is enabled.
+func GetSearchStats(doc *schema.Document) (*SearchStats, bool) {
+	if doc == nil || doc.MetaData == nil {
+		return nil, false
+	}
+	stats, ok := doc.MetaData[MetadataKeySearchStats].(*SearchStats)
+	return stats, ok
+}
+
+func newSearchStats(resp *search.Response) *SearchStats {
+	shards := resp.Shards_
+	stats := &SearchStats{
+		Took:     time.Duration(resp.Took) * time.Millisecond,
+		TimedOut: resp.TimedOut,
+		Shards: ShardStats{
+			Total:      int(shards.Total),
+			Successful: int(shards.Successful),
+			Failed:     int(shards.Failed),
+		},
+	}
+	if shards.Skipped != nil {
+		stats.Shards.Skipped = int(*shards.Skipped)
+	}
+	for _, f := range shards.Failures {
+		failure := ShardFailure{Reason: f.Reason.Type}
+		if f.Index != nil {
+			failure.Index = *f.Index
+		}
+		if f.Node != nil {
+			failure.Node = *f.Node
+		}
+		if f.Reason.Reason != nil {
+			failure.Reason = *f.Reason.Reason
+		}
+		stats.Shards.Failures = append(stats.Shards.Failures, failure)
+	}
+	return stats
+}
+
+// attachSearchStats sets the stats under MetadataKeySearchStats of the documents.
+func attachSearchStats(docs []*schema.Document, stats *SearchStats) {
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		if doc.MetaData == nil {
+			doc.MetaData = make(map[string]any)
+		}
+		doc.MetaData[MetadataKeySearchStats] = stats
+	}
+}
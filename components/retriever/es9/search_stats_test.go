@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchStats(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRetriever(ctx, &RetrieverConfig{
+		Client:     &elasticsearch.Client{},
+		Index:      "eino_ut",
+		SearchMode: &mockSearchMode{},
+	})
+	assert.NoError(t, err)
+
+	id, skipped, index, reason := "doc_1", uint(2), "eino_ut", "too many clauses"
+	mockSearch := search.NewSearchFunc(r.client)()
+	defer mockey.Mock(mockey.GetMethod(mockSearch, "Index")).
+		Return(mockSearch).Build().Patch().UnPatch()
+	defer mockey.Mock(mockey.GetMethod(mockSearch, "Request")).
+		Return(mockSearch).Build().Patch().UnPatch()
+	defer mockey.Mock(mockey.GetMethod(mockSearch, "Do")).Return(&search.Response{
+		Took:     120,
+		TimedOut: true,
+		Shards_: types.ShardStatistics{
+			Total:      5,
+			Successful: 2,
+			Skipped:    &skipped,
+			Failed:     1,
+			Failures: []types.ShardFailure{{
+				Index:  &index,
+				Reason: types.ErrorCause{Type: "query_shard_exception", Reason: &reason},
+			}},
+		},
+		Hits: types.HitsMetadata{Hits: []types.Hit{{Id_: &id, Source_: json.RawMessage(`{"content": "hello"}`)}}},
+	}, nil).Build().Patch().UnPatch()
+
+	want := &SearchStats{
+		Took:     120 * time.Millisecond,
+		TimedOut: true,
+		Shards: ShardStats{
+			Total:      5,
+			Successful: 2,
+			Skipped:    2,
+			Failed:     1,
+			Failures:   []ShardFailure{{Index: "eino_ut", Reason: "too many clauses"}},
+		},
+	}
+
+	var extra map[string]any
+	handler := callbacks.NewHandlerBuilder().OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+		extra = retriever.ConvCallbackOutput(output).Extra
+		return ctx
+	}).Build()
+	docs, err := r.Retrieve(callbacks.InitCallbacks(ctx, nil, handler), "query")
+	assert.NoError(t, err)
+	assert.Equal(t, want, extra[CallbackExtraKeySearchStats])
+	_, ok := GetSearchStats(docs[0])
+	assert.False(t, ok)
+
+	r.config.AttachSearchStats = true
+	docs, err = r.Retrieve(ctx, "query")
+	assert.NoError(t, err)
+	stats, ok := GetSearchStats(docs[0])
+	assert.True(t, ok)
+	assert.Equal(t, want, stats)
+}
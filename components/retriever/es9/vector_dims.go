@@ -0,0 +1,197 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/indices/getfieldmapping"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+)
+
+// VectorFieldReporter is implemented by the search modes searching a dense_vector field with the query vector
+// of the Embedding, e.g. search_mode.SearchModeApproximate, so that RetrieverConfig.ValidateVectorDims
+// can check the query vector against the mapping of the field.
+type VectorFieldReporter interface {
+	// VectorField returns the name of the searched dense_vector field,
+	// empty if the query vector is not embedded on the client side.
+	VectorField() string
+}
+
+// VectorDimsError is returned by Retrieve with RetrieverConfig.ValidateVectorDims when the query vector
+// of the Embedding does not fit the dense_vector field of the index mapping.
+type VectorDimsError struct {
+	Index string
+	Field string
+	// MappingDims is the dims of the field in the index mapping.
+	MappingDims int
+	// ElementType is the element_type of the field, e.g. "float", "byte" or "bit".
+	ElementType string
+	// VectorDims is the dims of the query vector of the Embedding.
+	VectorDims int
+	// Reason describes the mismatch.
+	Reason string
+}
+
+func (e *VectorDimsError) Error() string {
+	return fmt.Sprintf("[Retrieve] query vector does not fit dense_vector field %q of index %q (dims=%d, element_type=%s): %s",
+		e.Field, e.Index, e.MappingDims, e.ElementType, e.Reason)
+}
+
+// vectorMapping is the mapping of a dense_vector field.
+type vectorMapping struct {
+	dims        int
+	elementType string
+}
+
+// vectorDimsValidator checks the query vectors against the mapping of the vector field of each index
+// until one fits.
+type vectorDimsValidator struct {
+	mu        sync.Mutex
+	validated map[string]bool
+	mappings  map[string]*vectorMapping
+}
+
+func (v *vectorDimsValidator) isValidated(index string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.validated[index]
+}
+
+// validate checks vector against the mapping of field in index, fetching the mapping once per index.
+// The mapping is fetched without holding the lock, so a slow fetch does not block the other retrievals.
+func (v *vectorDimsValidator) validate(ctx context.Context, r *Retriever, index, field string, vector []float64) error {
+	v.mu.Lock()
+	if v.validated[index] {
+		v.mu.Unlock()
+		return nil
+	}
+	mapping := v.mappings[index]
+	v.mu.Unlock()
+
+	if mapping == nil {
+		var err error
+		if mapping, err = fetchVectorMapping(ctx, r, index, field); err != nil {
+			return err
+		}
+		v.mu.Lock()
+		if v.mappings == nil {
+			v.mappings = make(map[string]*vectorMapping)
+		}
+		v.mappings[index] = mapping
+		v.mu.Unlock()
+	}
+
+	if reason := checkVector(mapping, vector); reason != "" {
+		return &VectorDimsError{
+			Index:       index,
+			Field:       field,
+			MappingDims: mapping.dims,
+			ElementType: mapping.elementType,
+			VectorDims:  len(vector),
+			Reason:      reason,
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.validated == nil {
+		v.validated = make(map[string]bool)
+	}
+	v.validated[index] = true
+	return nil
+}
+
+func fetchVectorMapping(ctx context.Context, r *Retriever, index, field string) (*vectorMapping, error) {
+	resp, err := getfieldmapping.NewGetFieldMappingFunc(r.client)(field).
+		Index(index).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("[Retrieve] get mapping of vector field %q failed: %w", field, err)
+	}
+
+	for _, im := range resp {
+		for _, fm := range im.Mappings {
+			for _, prop := range fm.Mapping {
+				dv, ok := prop.(*types.DenseVectorProperty)
+				if !ok {
+					return nil, fmt.Errorf("[Retrieve] field %q of index %q is not a dense_vector field", field, index)
+				}
+				mapping := &vectorMapping{elementType: "float"}
+				if dv.Dims != nil {
+					mapping.dims = *dv.Dims
+				}
+				if dv.ElementType != nil {
+					mapping.elementType = dv.ElementType.String()
+				}
+				return mapping, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("[Retrieve] vector field %q not found in the mapping of index %q", field, index)
+}
+
+// checkVector returns the reason vector does not fit the mapping, empty if it fits.
+func checkVector(mapping *vectorMapping, vector []float64) string {
+	dims := len(vector)
+	if mapping.elementType == "bit" {
+		// bit vectors are given as bytes, 8 dimensions each
+		dims *= 8
+	}
+	if mapping.dims > 0 && dims != mapping.dims {
+		return fmt.Sprintf("embedding outputs %d dims, but the mapping has %d dims", dims, mapping.dims)
+	}
+	if mapping.elementType == "byte" || mapping.elementType == "bit" {
+		for i, x := range vector {
+			if x != math.Trunc(x) || x < math.MinInt8 || x > math.MaxInt8 {
+				return fmt.Sprintf("element_type %s requires integers in [-128, 127], but element %d is %v",
+					mapping.elementType, i, x)
+			}
+		}
+	}
+	return ""
+}
+
+// vectorRecorder records the last vector embedded by the search mode.
+type vectorRecorder struct {
+	embedding.Embedder
+	vector []float64
+}
+
+func (e *vectorRecorder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	vectors, err := e.Embedder.EmbedStrings(ctx, texts, opts...)
+	if err == nil && len(vectors) > 0 {
+		e.vector = vectors[0]
+	}
+	return vectors, err
+}
+
+// GetType returns the type of the recorded embedder, keeping the run info of its callbacks.
+func (e *vectorRecorder) GetType() string {
+	typ, _ := components.GetType(e.Embedder)
+	return typ
+}
+
+// IsCallbacksEnabled reports whether the recorded embedder reports callbacks itself.
+func (e *vectorRecorder) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(e.Embedder)
+}
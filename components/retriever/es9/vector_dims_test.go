@@ -0,0 +1,140 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package es9
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/elastic/go-elasticsearch/v9"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/indices/getfieldmapping"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockVectorSearchMode struct{}
+
+func (m *mockVectorSearchMode) BuildRequest(ctx context.Context, conf *RetrieverConfig, query string, opts ...retriever.Option) (*search.Request, error) {
+	co := retriever.GetCommonOptions(&retriever.Options{Embedding: conf.Embedding}, opts...)
+	if _, err := co.Embedding.EmbedStrings(ctx, []string{query}); err != nil {
+		return nil, err
+	}
+	return &search.Request{}, nil
+}
+
+func (m *mockVectorSearchMode) VectorField() string {
+	return "vector"
+}
+
+type mockEmbedding struct {
+	dims  int
+	calls int
+}
+
+func (m *mockEmbedding) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	m.calls++
+	return [][]float64{make([]float64, m.dims)}, nil
+}
+
+func TestValidateVectorDims(t *testing.T) {
+	ctx := context.Background()
+	dims := 4
+
+	newRetriever := func(emb *mockEmbedding) *Retriever {
+		r, err := NewRetriever(ctx, &RetrieverConfig{
+			Client:             &elasticsearch.Client{},
+			Index:              "eino_ut",
+			SearchMode:         &mockVectorSearchMode{},
+			Embedding:          emb,
+			ValidateVectorDims: true,
+		})
+		assert.NoError(t, err)
+		return r
+	}
+
+	mockSearch := search.NewSearchFunc(&elasticsearch.Client{})()
+	defer mockey.Mock(mockey.GetMethod(mockSearch, "Index")).Return(mockSearch).Build().UnPatch()
+	defer mockey.Mock(mockey.GetMethod(mockSearch, "Request")).Return(mockSearch).Build().UnPatch()
+	defer mockey.Mock(mockey.GetMethod(mockSearch, "Do")).Return(&search.Response{}, nil).Build().UnPatch()
+
+	mockMapping := getfieldmapping.NewGetFieldMappingFunc(&elasticsearch.Client{})("vector")
+	defer mockey.Mock(mockey.GetMethod(mockMapping, "Index")).Return(mockMapping).Build().UnPatch()
+	mappingMocker := mockey.Mock(mockey.GetMethod(mockMapping, "Do")).Return(getfieldmapping.Response{
+		"eino_ut": {Mappings: map[string]types.FieldMapping{
+			"vector": {FullName: "vector", Mapping: map[string]types.Property{
+				"vector": &types.DenseVectorProperty{Dims: &dims},
+			}},
+		}},
+	}, nil).Build()
+	defer mappingMocker.UnPatch()
+
+	t.Run("mismatch", func(t *testing.T) {
+		r := newRetriever(&mockEmbedding{dims: 3})
+		_, err := r.Retrieve(ctx, "query")
+		var dimsErr *VectorDimsError
+		assert.True(t, errors.As(err, &dimsErr))
+		assert.Equal(t, "eino_ut", dimsErr.Index)
+		assert.Equal(t, "vector", dimsErr.Field)
+		assert.Equal(t, 4, dimsErr.MappingDims)
+		assert.Equal(t, 3, dimsErr.VectorDims)
+		assert.Contains(t, err.Error(), "embedding outputs 3 dims, but the mapping has 4 dims")
+	})
+
+	t.Run("match once", func(t *testing.T) {
+		emb := &mockEmbedding{dims: 4}
+		r := newRetriever(emb)
+		before := mappingMocker.Times()
+		_, err := r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		_, err = r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, mappingMocker.Times()-before)
+		assert.Equal(t, 2, emb.calls)
+	})
+
+	t.Run("with index", func(t *testing.T) {
+		r := newRetriever(&mockEmbedding{dims: 4})
+		before := mappingMocker.Times()
+		_, err := r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		_, err = r.Retrieve(ctx, "query", retriever.WithIndex("eino_ut_other"))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, mappingMocker.Times()-before)
+		assert.True(t, r.dims.isValidated("eino_ut"))
+		assert.True(t, r.dims.isValidated("eino_ut_other"))
+
+		r = newRetriever(&mockEmbedding{dims: 3})
+		_, err = r.Retrieve(ctx, "query", retriever.WithIndex("eino_ut_other"))
+		var dimsErr *VectorDimsError
+		assert.True(t, errors.As(err, &dimsErr))
+		assert.Equal(t, "eino_ut_other", dimsErr.Index)
+	})
+}
+
+func TestCheckVector(t *testing.T) {
+	assert.Empty(t, checkVector(&vectorMapping{dims: 2, elementType: "float"}, []float64{0.1, 0.2}))
+	assert.Empty(t, checkVector(&vectorMapping{dims: 2, elementType: "byte"}, []float64{-128, 127}))
+	assert.Empty(t, checkVector(&vectorMapping{dims: 16, elementType: "bit"}, []float64{1, -1}))
+	assert.Contains(t, checkVector(&vectorMapping{dims: 3, elementType: "float"}, []float64{0.1, 0.2}), "2 dims")
+	assert.Contains(t, checkVector(&vectorMapping{dims: 2, elementType: "byte"}, []float64{0.5, 1}), "element 0 is 0.5")
+	assert.Contains(t, checkVector(&vectorMapping{dims: 8, elementType: "bit"}, []float64{1, 2}), "16 dims")
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+// Validate checks the config without connecting to Milvus, and returns a *configcheck.Report listing all its problems,
+// or nil if the config is valid. Unlike NewRetriever, it does not fill in the defaults of the config,
+// and the search mode is not checked against the collection schema.
+func (c *RetrieverConfig) Validate() error {
+	r := configcheck.New("milvus2.RetrieverConfig")
+	r.Checkf(c.Client != nil || c.ClientConfig != nil, "Client", "milvus client or client config not provided")
+	r.Checkf(c.SearchMode != nil, "SearchMode", "search mode not provided")
+	configcheck.NonNegative(r, "ReplicaNumber", &c.ReplicaNumber)
+	configcheck.NonNegative(r, "CollectionStatsInterval", &c.CollectionStatsInterval)
+	if c.DocumentConverter == nil {
+		id, content, metadata := orDefault(c.IDField, defaultIDField), orDefault(c.ContentField, defaultContentField),
+			orDefault(c.MetadataField, defaultMetadataField)
+		r.Checkf(id != content && id != metadata && content != metadata, "IDField",
+			"id, content and metadata fields must be distinct, got %q, %q and %q", id, content, metadata)
+	}
+	return r.Err()
+}
+
+func orDefault[T comparable](v, def T) T {
+	var zero T
+	if v == zero {
+		return def
+	}
+	return v
+}
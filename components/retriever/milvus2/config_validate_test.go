@@ -0,0 +1,54 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestRetrieverConfig_Validate(t *testing.T) {
+	convey.Convey("test RetrieverConfig.Validate", t, func() {
+		convey.Convey("test valid config is not modified", func() {
+			config := &RetrieverConfig{
+				ClientConfig: &milvusclient.ClientConfig{Address: "localhost:19530"},
+				SearchMode:   &mockSearchMode{},
+			}
+			convey.So(config.Validate(), convey.ShouldBeNil)
+			convey.So(config.Collection, convey.ShouldEqual, "")
+			convey.So(config.TopK, convey.ShouldEqual, 0)
+			convey.So(config.DocumentConverter, convey.ShouldBeNil)
+		})
+
+		convey.Convey("test all issues are reported", func() {
+			config := &RetrieverConfig{ReplicaNumber: -1, MetadataField: "content"}
+			err := config.Validate()
+			report, ok := err.(*configcheck.Report)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(report.Config, convey.ShouldEqual, "milvus2.RetrieverConfig")
+			convey.So(report.Issues, convey.ShouldResemble, []configcheck.Issue{
+				{Field: "Client", Message: "milvus client or client config not provided"},
+				{Field: "SearchMode", Message: "search mode not provided"},
+				{Field: "ReplicaNumber", Message: "must not be negative, got -1"},
+				{Field: "IDField", Message: `id, content and metadata fields must be distinct, got "id", "content" and "content"`},
+			})
+		})
+	})
+}
@@ -0,0 +1,328 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/retriever"
+)
+
+var fieldNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Field references a scalar field, a dynamic field or a key of a JSON field in a filter expression.
+// Create it with F or Meta.
+type Field struct {
+	path string
+	err  error
+}
+
+// Expr is a Milvus boolean expression built with Field methods and combined with And, Or and Not.
+// See https://milvus.io/docs/boolean.md for the expression syntax.
+//
+// Example:
+//
+//	expr := milvus2.F("year").Gte(2023).And(milvus2.F("tag").In("coding", "life"))
+//	docs, err := r.Retrieve(ctx, query, milvus2.WithFilterExpr(expr))
+type Expr struct {
+	expr     string
+	compound bool
+	err      error
+}
+
+// F returns a reference to the field with the given name.
+func F(name string) Field {
+	if !fieldNamePattern.MatchString(name) {
+		return Field{path: name, err: fmt.Errorf("invalid field name: %q", name)}
+	}
+	return Field{path: name}
+}
+
+// Meta returns a reference to a key of the default metadata JSON field, i.e. metadata["key"].
+func Meta(key string) Field {
+	return F(defaultMetadataField).Key(key)
+}
+
+// Key returns a reference to a key of the JSON field, e.g. F("info").Key("city") renders info["city"].
+func (f Field) Key(key string) Field {
+	f.path = f.path + "[" + quoteString(key) + "]"
+	return f
+}
+
+// Index returns a reference to an element of the array field, e.g. F("tags").Index(0) renders tags[0].
+func (f Field) Index(i int) Field {
+	if i < 0 && f.err == nil {
+		f.err = fmt.Errorf("invalid index %d of field %q", i, f.path)
+	}
+	f.path = f.path + "[" + strconv.Itoa(i) + "]"
+	return f
+}
+
+// Eq renders "field == value".
+func (f Field) Eq(value any) *Expr { return f.compare("==", value) }
+
+// Ne renders "field != value".
+func (f Field) Ne(value any) *Expr { return f.compare("!=", value) }
+
+// Gt renders "field > value".
+func (f Field) Gt(value any) *Expr { return f.compare(">", value) }
+
+// Gte renders "field >= value".
+func (f Field) Gte(value any) *Expr { return f.compare(">=", value) }
+
+// Lt renders "field < value".
+func (f Field) Lt(value any) *Expr { return f.compare("<", value) }
+
+// Lte renders "field <= value".
+func (f Field) Lte(value any) *Expr { return f.compare("<=", value) }
+
+// In renders "field in [values...]".
+func (f Field) In(values ...any) *Expr { return f.compare("in", listValue(values)) }
+
+// NotIn renders "field not in [values...]".
+func (f Field) NotIn(values ...any) *Expr { return f.compare("not in", listValue(values)) }
+
+// Like renders "field like pattern", where % matches any characters, e.g. F("title").Like("Go%").
+func (f Field) Like(pattern string) *Expr { return f.compare("like", pattern) }
+
+// IsNull renders "field is null".
+func (f Field) IsNull() *Expr { return f.unary("is null") }
+
+// IsNotNull renders "field is not null".
+func (f Field) IsNotNull() *Expr { return f.unary("is not null") }
+
+// JSONContains renders "json_contains(field, value)".
+func (f Field) JSONContains(value any) *Expr { return f.call("json_contains", value) }
+
+// JSONContainsAll renders "json_contains_all(field, [values...])".
+func (f Field) JSONContainsAll(values ...any) *Expr {
+	return f.call("json_contains_all", listValue(values))
+}
+
+// JSONContainsAny renders "json_contains_any(field, [values...])".
+func (f Field) JSONContainsAny(values ...any) *Expr {
+	return f.call("json_contains_any", listValue(values))
+}
+
+// ArrayContains renders "array_contains(field, value)".
+func (f Field) ArrayContains(value any) *Expr { return f.call("array_contains", value) }
+
+// ArrayContainsAll renders "array_contains_all(field, [values...])".
+func (f Field) ArrayContainsAll(values ...any) *Expr {
+	return f.call("array_contains_all", listValue(values))
+}
+
+// ArrayContainsAny renders "array_contains_any(field, [values...])".
+func (f Field) ArrayContainsAny(values ...any) *Expr {
+	return f.call("array_contains_any", listValue(values))
+}
+
+// ArrayLength returns an expression builder on "array_length(field)", e.g. F("tags").ArrayLength().Gt(2).
+func (f Field) ArrayLength() Field {
+	f.path = "array_length(" + f.path + ")"
+	return f
+}
+
+func (f Field) compare(op string, value any) *Expr {
+	if f.err != nil {
+		return &Expr{err: f.err}
+	}
+	v, err := formatValue(value)
+	if err != nil {
+		return &Expr{err: invalidFilter(fmt.Sprintf("%s %s %v", f.path, op, value), err)}
+	}
+	return &Expr{expr: f.path + " " + op + " " + v}
+}
+
+func (f Field) unary(op string) *Expr {
+	if f.err != nil {
+		return &Expr{err: f.err}
+	}
+	return &Expr{expr: f.path + " " + op}
+}
+
+func (f Field) call(fn string, value any) *Expr {
+	if f.err != nil {
+		return &Expr{err: f.err}
+	}
+	v, err := formatValue(value)
+	if err != nil {
+		return &Expr{err: invalidFilter(fmt.Sprintf("%s(%s, %v)", fn, f.path, value), err)}
+	}
+	return &Expr{expr: fn + "(" + f.path + ", " + v + ")"}
+}
+
+// Raw wraps a hand-written expression so it can be combined with built expressions.
+func Raw(expr string) *Expr {
+	if strings.TrimSpace(expr) == "" {
+		return &Expr{err: invalidFilter(expr, errors.New("empty expression"))}
+	}
+	return &Expr{expr: expr, compound: true}
+}
+
+// And combines the expressions with "and". Nil expressions are ignored.
+func And(exprs ...*Expr) *Expr { return join("and", exprs) }
+
+// Or combines the expressions with "or". Nil expressions are ignored.
+func Or(exprs ...*Expr) *Expr { return join("or", exprs) }
+
+// Not negates the expression.
+func Not(e *Expr) *Expr {
+	if e == nil {
+		return &Expr{err: errors.New("not: nil expression")}
+	}
+	if e.err != nil {
+		return e
+	}
+	return &Expr{expr: "not (" + e.expr + ")"}
+}
+
+// And combines e and others with "and".
+func (e *Expr) And(others ...*Expr) *Expr { return And(append([]*Expr{e}, others...)...) }
+
+// Or combines e and others with "or".
+func (e *Expr) Or(others ...*Expr) *Expr { return Or(append([]*Expr{e}, others...)...) }
+
+// Not negates e.
+func (e *Expr) Not() *Expr { return Not(e) }
+
+// Build returns the rendered expression, or the first error met while building it.
+func (e *Expr) Build() (string, error) {
+	if e == nil {
+		return "", errors.New("nil expression")
+	}
+	if e.err != nil {
+		return "", e.err
+	}
+	return e.expr, nil
+}
+
+// String returns the rendered expression.
+// An invalid expression renders as a string which Milvus rejects, so a broken filter
+// fails the search instead of silently matching everything. Use Build to check the error.
+func (e *Expr) String() string {
+	s, err := e.Build()
+	if err != nil {
+		return fmt.Sprintf("<invalid filter: %v>", err)
+	}
+	return s
+}
+
+// WithFilterExpr returns an option that sets the filter built by the expression builder.
+// It is equivalent to WithFilter(expr.String()).
+func WithFilterExpr(expr *Expr) retriever.Option {
+	return WithFilter(expr.String())
+}
+
+func join(op string, exprs []*Expr) *Expr {
+	var parts []string
+	var last *Expr
+	for _, e := range exprs {
+		if e == nil {
+			continue
+		}
+		if e.err != nil {
+			return e
+		}
+		last = e
+		if e.compound {
+			parts = append(parts, "("+e.expr+")")
+		} else {
+			parts = append(parts, e.expr)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return &Expr{err: fmt.Errorf("%s: no expression", op)}
+	case 1:
+		return last
+	}
+	return &Expr{expr: strings.Join(parts, " "+op+" "), compound: true}
+}
+
+// invalidFilter quotes the expression as written by the user, since the rendered one does not exist.
+func invalidFilter(expr string, err error) error {
+	return fmt.Errorf("invalid filter %q: %w", expr, err)
+}
+
+// listValue allows both In("a", "b") and In([]string{"a", "b"}).
+func listValue(values []any) any {
+	if len(values) == 1 {
+		if k := reflect.ValueOf(values[0]).Kind(); k == reflect.Slice || k == reflect.Array {
+			return values[0]
+		}
+	}
+	return values
+}
+
+// formatValue renders a Go value as a Milvus literal.
+// Supported types are strings, booleans, numbers and slices or arrays of them.
+func formatValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", errors.New("nil value")
+	case string:
+		return quoteString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.String:
+		return quoteString(rv.String()), nil
+	case reflect.Slice, reflect.Array:
+		items := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			item := rv.Index(i).Interface()
+			if k := reflect.ValueOf(item).Kind(); k == reflect.Slice || k == reflect.Array {
+				return "", fmt.Errorf("nested list is not supported")
+			}
+			s, err := formatValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	}
+	return "", fmt.Errorf("unsupported value type %T", value)
+}
+
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func quoteString(s string) string {
+	return `"` + stringEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterExpr(t *testing.T) {
+	convey.Convey("test filter expression builder", t, func() {
+		convey.Convey("test comparison", func() {
+			convey.So(F("year").Gte(2023).String(), convey.ShouldEqual, `year >= 2023`)
+			convey.So(F("score").Lt(0.5).String(), convey.ShouldEqual, `score < 0.5`)
+			convey.So(F("published").Eq(true).String(), convey.ShouldEqual, `published == true`)
+			convey.So(F("title").Ne(`say "hi"`).String(), convey.ShouldEqual, `title != "say \"hi\""`)
+			convey.So(F("title").Like("Go%").String(), convey.ShouldEqual, `title like "Go%"`)
+			convey.So(F("tag").IsNull().String(), convey.ShouldEqual, `tag is null`)
+		})
+
+		convey.Convey("test in", func() {
+			convey.So(F("tag").In("coding", "life").String(), convey.ShouldEqual, `tag in ["coding", "life"]`)
+			convey.So(F("tag").NotIn([]string{"a", "b"}).String(), convey.ShouldEqual, `tag not in ["a", "b"]`)
+			convey.So(F("id").In(1, 2).String(), convey.ShouldEqual, `id in [1, 2]`)
+		})
+
+		convey.Convey("test json and array", func() {
+			convey.So(Meta("city").Eq("Paris").String(), convey.ShouldEqual, `metadata["city"] == "Paris"`)
+			convey.So(F("info").Key("a").Key("b").Gt(1).String(), convey.ShouldEqual, `info["a"]["b"] > 1`)
+			convey.So(F("tags").Index(0).Eq("x").String(), convey.ShouldEqual, `tags[0] == "x"`)
+			convey.So(F("tags").ArrayContainsAny("a", "b").String(), convey.ShouldEqual, `array_contains_any(tags, ["a", "b"])`)
+			convey.So(Meta("tags").JSONContains("a").String(), convey.ShouldEqual, `json_contains(metadata["tags"], "a")`)
+			convey.So(F("tags").ArrayLength().Gt(2).String(), convey.ShouldEqual, `array_length(tags) > 2`)
+		})
+
+		convey.Convey("test logical", func() {
+			expr := F("year").Gte(2023).And(F("tag").In("coding", "life"))
+			convey.So(expr.String(), convey.ShouldEqual, `year >= 2023 and tag in ["coding", "life"]`)
+
+			expr = Or(expr, F("pinned").Eq(true)).And(Not(F("deleted").Eq(true)))
+			convey.So(expr.String(), convey.ShouldEqual,
+				`((year >= 2023 and tag in ["coding", "life"]) or pinned == true) and not (deleted == true)`)
+
+			convey.So(And(nil, F("a").Eq(1)).String(), convey.ShouldEqual, `a == 1`)
+			convey.So(Raw("a > 1 or b < 2").And(F("c").Eq(3)).String(), convey.ShouldEqual, `(a > 1 or b < 2) and c == 3`)
+		})
+
+		convey.Convey("test invalid", func() {
+			_, err := F("bad name").Eq(1).Build()
+			convey.So(err, convey.ShouldNotBeNil)
+
+			convey.So(err.Error(), convey.ShouldContainSubstring, `"bad name"`)
+
+			_, err = F("a").Eq(struct{}{}).Build()
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `"a == {}"`)
+
+			expr := F("a").Eq(1).And(F("b").In([]int{1}, []int{2}))
+			_, err = expr.Build()
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `"b in [[1] [2]]"`)
+
+			_, err = Raw("  ").Build()
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `"  "`)
+			convey.So(expr.String(), convey.ShouldStartWith, "<invalid filter:")
+
+			_, err = And().Build()
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		convey.Convey("test WithFilterExpr", func() {
+			opts := retriever.GetImplSpecificOptions(&ImplOptions{}, WithFilterExpr(F("year").Gte(2023)))
+			convey.So(opts.Filter, convey.ShouldEqual, `year >= 2023`)
+		})
+	})
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/bytedance/sonic"
+)
+
+// metadataCodecKey and metadataDataKey wrap metadata compressed by the milvus2 indexer.
+// They must be kept in sync with the milvus2 indexer.
+const (
+	metadataCodecKey     = "_eino_metadata_codec"
+	metadataDataKey      = "_eino_metadata_data"
+	metadataCodecGzipB64 = "gzip+base64"
+)
+
+// decodeMetadata unmarshals the metadata JSON, transparently decompressing
+// metadata stored compressed by the milvus2 indexer.
+func decodeMetadata(metaBytes []byte) (map[string]any, error) {
+	var meta map[string]any
+	if err := sonic.Unmarshal(metaBytes, &meta); err != nil {
+		return nil, err
+	}
+
+	codec, ok := meta[metadataCodecKey].(string)
+	if !ok || len(meta) != 2 {
+		return meta, nil
+	}
+	if codec != metadataCodecGzipB64 {
+		return nil, fmt.Errorf("unsupported metadata codec: %s", codec)
+	}
+	data, ok := meta[metadataDataKey].(string)
+	if !ok {
+		return meta, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode compressed metadata: %w", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress metadata: %w", err)
+	}
+	defer zr.Close()
+	decompressed, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress metadata: %w", err)
+	}
+
+	meta = nil
+	if err := sonic.Unmarshal(decompressed, &meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
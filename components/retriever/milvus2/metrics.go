@@ -0,0 +1,177 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+)
+
+// Keys of the Extra of retriever.CallbackOutput.
+const (
+	// CallbackExtraKeyLatency is the time.Duration spent by the search mode, including query embedding.
+	CallbackExtraKeyLatency = "milvus2_latency"
+	// CallbackExtraKeyNQ is the int number of query vectors of the search requests sent to Milvus,
+	// summed over the sub-requests of a hybrid search and the batches of an iterator.
+	// Only set when the search mode reports it with ReportNQ, e.g. not for the query based modes.
+	CallbackExtraKeyNQ = "milvus2_nq"
+	// CallbackExtraKeyResultCount is the number of returned documents.
+	CallbackExtraKeyResultCount = "milvus2_result_count"
+	// CallbackExtraKeyCollectionRowCount is the row count of the collection reported by Milvus,
+	// only set when RetrieverConfig.CollectionStatsInterval is enabled.
+	CallbackExtraKeyCollectionRowCount = "milvus2_collection_row_count"
+)
+
+// Keys of the Extra of retriever.CallbackInput, describing the searched collection
+// so that APM dashboards can slice the retrievals. See SpanAttributes.
+const (
+	// CallbackExtraKeyCollection is the name of the searched collection.
+	CallbackExtraKeyCollection = "milvus2_collection"
+	// CallbackExtraKeyPartitions is the []string of searched partitions, only set when RetrieverConfig.Partitions is set.
+	CallbackExtraKeyPartitions = "milvus2_partitions"
+	// CallbackExtraKeyIndexType is the index type of the VectorField, or of the SparseVectorField
+	// when the VectorField has no index, as described by Milvus when the retriever was created.
+	// Not set when the index could not be described.
+	CallbackExtraKeyIndexType = "milvus2_index_type"
+	// CallbackExtraKeyMetricType is the metric type of the search mode,
+	// only set when the search mode implements MetricTypeReporter and reports a non-empty metric type.
+	CallbackExtraKeyMetricType = "milvus2_metric_type"
+	// CallbackExtraKeyFilterHash is the hex FNV-1a hash of the filter expression set by WithFilter,
+	// which groups the retrievals by filter without exposing the filtered values.
+	CallbackExtraKeyFilterHash = "milvus2_filter_hash"
+)
+
+type nqCounterKey struct{}
+
+// withNQCounter returns a context collecting the nq reported by the search mode with ReportNQ.
+func withNQCounter(ctx context.Context) (context.Context, *atomic.Int64) {
+	counter := &atomic.Int64{}
+	return context.WithValue(ctx, nqCounterKey{}, counter), counter
+}
+
+// ReportNQ adds nq, the number of query vectors of a search request sent to Milvus, to the CallbackExtraKeyNQ
+// of the Retrieve running in ctx. The built-in search modes call it with the nq of their requests,
+// custom search modes may call it as well. It does nothing when the callbacks of the retriever are disabled.
+func ReportNQ(ctx context.Context, nq int64) {
+	if counter, ok := ctx.Value(nqCounterKey{}).(*atomic.Int64); ok {
+		counter.Add(nq)
+	}
+}
+
+// collectionStats caches the collection row count reported by Milvus,
+// refreshing it at most once per interval. A nil *collectionStats is disabled.
+type collectionStats struct {
+	interval time.Duration
+
+	mu        sync.Mutex
+	updatedAt time.Time
+	rowCount  int64
+	valid     bool
+}
+
+func newCollectionStats(interval time.Duration) *collectionStats {
+	if interval <= 0 {
+		return nil
+	}
+	return &collectionStats{interval: interval}
+}
+
+// getRowCount returns the cached row count, refreshing it if it is stale.
+// Refresh failures are ignored and the last known value, if any, is returned.
+func (s *collectionStats) getRowCount(ctx context.Context, cli *milvusclient.Client, collection string) (int64, bool) {
+	if s == nil || cli == nil {
+		return 0, false
+	}
+
+	s.mu.Lock()
+	if time.Since(s.updatedAt) < s.interval {
+		defer s.mu.Unlock()
+		return s.rowCount, s.valid
+	}
+	// claim the refresh so that the concurrent calls return the cached value instead of waiting for the RPC
+	s.updatedAt = time.Now()
+	s.mu.Unlock()
+
+	stats, err := cli.GetCollectionStats(ctx, milvusclient.NewGetCollectionStatsOption(collection))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		return s.rowCount, s.valid
+	}
+	n, err := strconv.ParseInt(stats["row_count"], 10, 64)
+	if err != nil {
+		return s.rowCount, s.valid
+	}
+	s.rowCount, s.valid = n, true
+	return s.rowCount, s.valid
+}
+
+// describeIndexType returns the index type of the VectorField, or of the SparseVectorField
+// when the VectorField has no index. Failures are ignored, an empty type is returned then.
+func describeIndexType(ctx context.Context, cli *milvusclient.Client, conf *RetrieverConfig) string {
+	for _, field := range []string{conf.VectorField, conf.SparseVectorField} {
+		desc, err := cli.DescribeIndex(ctx, milvusclient.NewDescribeIndexOption(conf.Collection, field))
+		if err != nil || desc.Index == nil {
+			continue
+		}
+		// the described index is generic, its type is one of its params
+		if indexType := desc.Params()[index.IndexTypeKey]; indexType != "" {
+			return indexType
+		}
+	}
+	return ""
+}
+
+// callbackInputExtra returns the Extra of retriever.CallbackInput describing the search.
+func (r *Retriever) callbackInputExtra(opts ...retriever.Option) map[string]any {
+	conf := r.config
+	extra := map[string]any{
+		CallbackExtraKeyCollection: conf.Collection,
+	}
+	if len(conf.Partitions) > 0 {
+		extra[CallbackExtraKeyPartitions] = conf.Partitions
+	}
+	if r.indexType != "" {
+		extra[CallbackExtraKeyIndexType] = r.indexType
+	}
+	if reporter, ok := conf.SearchMode.(MetricTypeReporter); ok {
+		if metricType := reporter.GetMetricType(); metricType != "" {
+			extra[CallbackExtraKeyMetricType] = string(metricType)
+		}
+	}
+	io := retriever.GetImplSpecificOptions(&ImplOptions{}, opts...)
+	if io.Filter != "" {
+		extra[CallbackExtraKeyFilterHash] = filterHash(io.Filter)
+	}
+	return extra
+}
+
+// filterHash returns the hex FNV-1a hash of the filter expression.
+func filterHash(filter string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(filter))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/index"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestCollectionStats(t *testing.T) {
+	PatchConvey("test collectionStats", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+
+		convey.So(newCollectionStats(0), convey.ShouldBeNil)
+		// no Retrieve running in ctx
+		ReportNQ(ctx, 1)
+		var disabled *collectionStats
+		_, ok := disabled.getRowCount(ctx, mockClient, "c")
+		convey.So(ok, convey.ShouldBeFalse)
+
+		PatchConvey("test cached within interval", func() {
+			mocker := Mock(GetMethod(mockClient, "GetCollectionStats")).Return(map[string]string{"row_count": "100"}, nil).Build()
+
+			stats := newCollectionStats(time.Hour)
+			n, ok := stats.getRowCount(ctx, mockClient, "c")
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(n, convey.ShouldEqual, 100)
+			n, _ = stats.getRowCount(ctx, mockClient, "c")
+			convey.So(n, convey.ShouldEqual, 100)
+			convey.So(mocker.MockTimes(), convey.ShouldEqual, 1)
+		})
+
+		PatchConvey("test error keeps last value", func() {
+			Mock(GetMethod(mockClient, "GetCollectionStats")).Return(nil, fmt.Errorf("stats error")).Build()
+
+			stats := newCollectionStats(time.Nanosecond)
+			stats.rowCount, stats.valid = 7, true
+			n, ok := stats.getRowCount(ctx, mockClient, "c")
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(n, convey.ShouldEqual, 7)
+		})
+	})
+}
+
+func TestRetrieve_CallbackExtra(t *testing.T) {
+	PatchConvey("test Retrieve callback extra", t, func() {
+		mockClient := &milvusclient.Client{}
+		mockSM := &mockSearchMode{
+			retrieveFunc: func(ctx context.Context, client *milvusclient.Client, conf *RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+				ReportNQ(ctx, 2)
+				ReportNQ(ctx, 1)
+				return []*schema.Document{{ID: "1"}, {ID: "2"}}, nil
+			},
+		}
+		Mock(GetMethod(mockClient, "GetCollectionStats")).Return(map[string]string{"row_count": "42"}, nil).Build()
+
+		r := &Retriever{
+			client: mockClient,
+			config: &RetrieverConfig{Collection: "test_collection", TopK: 10, SearchMode: mockSM},
+			stats:  newCollectionStats(time.Minute),
+		}
+
+		var inputExtra, extra map[string]any
+		handler := callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			inputExtra = retriever.ConvCallbackInput(input).Extra
+			return ctx
+		}).OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			extra = retriever.ConvCallbackOutput(output).Extra
+			return ctx
+		}).Build()
+		ctx := callbacks.InitCallbacks(context.Background(), nil, handler)
+
+		_, err := r.Retrieve(ctx, "query")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(extra[CallbackExtraKeyNQ], convey.ShouldEqual, 3)
+		convey.So(extra[CallbackExtraKeyResultCount], convey.ShouldEqual, 2)
+		convey.So(extra[CallbackExtraKeyCollectionRowCount], convey.ShouldEqual, int64(42))
+		_, ok := extra[CallbackExtraKeyLatency].(time.Duration)
+		convey.So(ok, convey.ShouldBeTrue)
+
+		convey.So(inputExtra[CallbackExtraKeyCollection], convey.ShouldEqual, "test_collection")
+		_, ok = inputExtra[CallbackExtraKeyIndexType]
+		convey.So(ok, convey.ShouldBeFalse)
+		_, ok = inputExtra[CallbackExtraKeyPartitions]
+		convey.So(ok, convey.ShouldBeFalse)
+		_, ok = inputExtra[CallbackExtraKeyFilterHash]
+		convey.So(ok, convey.ShouldBeFalse)
+
+		PatchConvey("test input extra with partitions, metric type and filter", func() {
+			r.config.Partitions = []string{"p1"}
+			r.indexType = "HNSW"
+			r.config.SearchMode = &metricTypeSearchMode{mockSearchMode: mockSM, metricType: COSINE}
+
+			_, err := r.Retrieve(ctx, "query", WithFilter("tenant == 'a'"))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(inputExtra[CallbackExtraKeyPartitions], convey.ShouldResemble, []string{"p1"})
+			convey.So(inputExtra[CallbackExtraKeyIndexType], convey.ShouldEqual, "HNSW")
+			convey.So(inputExtra[CallbackExtraKeyMetricType], convey.ShouldEqual, "COSINE")
+			convey.So(inputExtra[CallbackExtraKeyFilterHash], convey.ShouldEqual, filterHash("tenant == 'a'"))
+		})
+	})
+}
+
+// metricTypeSearchMode is a mockSearchMode reporting its metric type.
+type metricTypeSearchMode struct {
+	*mockSearchMode
+	metricType MetricType
+}
+
+func (m *metricTypeSearchMode) GetMetricType() MetricType {
+	return m.metricType
+}
+
+func TestDescribeIndexType(t *testing.T) {
+	PatchConvey("test describeIndexType", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		conf := &RetrieverConfig{Collection: "c", VectorField: "vector", SparseVectorField: "sparse"}
+
+		PatchConvey("test dense index", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{
+				Index: index.NewGenericIndex("vector", map[string]string{index.IndexTypeKey: "HNSW"}),
+			}, nil).Build()
+			convey.So(describeIndexType(ctx, mockClient, conf), convey.ShouldEqual, "HNSW")
+		})
+
+		PatchConvey("test sparse index when the dense field has none", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(Sequence(milvusclient.IndexDescription{}, fmt.Errorf("index not found")).
+				Then(milvusclient.IndexDescription{
+					Index: index.NewGenericIndex("sparse", map[string]string{index.IndexTypeKey: "SPARSE_INVERTED_INDEX"}),
+				}, nil)).Build()
+			convey.So(describeIndexType(ctx, mockClient, conf), convey.ShouldEqual, "SPARSE_INVERTED_INDEX")
+		})
+
+		PatchConvey("test no index", func() {
+			Mock(GetMethod(mockClient, "DescribeIndex")).Return(milvusclient.IndexDescription{}, fmt.Errorf("index not found")).Build()
+			convey.So(describeIndexType(ctx, mockClient, conf), convey.ShouldEqual, "")
+		})
+	})
+}
+
+func TestFilterHash(t *testing.T) {
+	convey.Convey("test filterHash", t, func() {
+		convey.So(filterHash("a == 1"), convey.ShouldEqual, filterHash("a == 1"))
+		convey.So(filterHash("a == 1"), convey.ShouldNotEqual, filterHash("a == 2"))
+		convey.So(filterHash(""), convey.ShouldEqual, "cbf29ce484222325")
+	})
+}
+
+func TestSpanAttributes(t *testing.T) {
+	convey.Convey("test SpanAttributes", t, func() {
+		attrs := SpanAttributes(map[string]any{
+			CallbackExtraKeyCollection:  "c",
+			CallbackExtraKeyPartitions:  []string{"p1", "p2"},
+			CallbackExtraKeyNQ:          1,
+			CallbackExtraKeyLatency:     1500 * time.Millisecond,
+			CallbackExtraKeyResultCount: 3,
+			"unknown":                   "ignored",
+		})
+		convey.So(attrs, convey.ShouldResemble, []attribute.KeyValue{
+			attribute.String("db.system", "milvus"),
+			attribute.String("db.collection.name", "c"),
+			attribute.StringSlice("milvus.partitions", []string{"p1", "p2"}),
+			attribute.Int("milvus.nq", 1),
+			attribute.Int("milvus.result_count", 3),
+			attribute.Int64("milvus.latency_ms", 1500),
+		})
+
+		convey.So(SpanAttributes(nil), convey.ShouldResemble, []attribute.KeyValue{attribute.String("db.system", "milvus")})
+	})
+}
@@ -16,7 +16,13 @@
 
 package milvus2
 
-import "github.com/cloudwego/eino/components/retriever"
+import (
+	"time"
+
+	"github.com/cloudwego/eino/components/retriever"
+
+	"github.com/cloudwego/eino-ext/libs/milvus2/retry"
+)
 
 // ImplOptions contains implementation-specific options for retrieval operations.
 type ImplOptions struct {
@@ -26,6 +32,20 @@ type ImplOptions struct {
 
 	// Grouping configuration for grouping search.
 	Grouping *GroupingConfig
+
+	// QueryVectors are precomputed query vectors keyed by vector field, e.g. the embedding of a query image.
+	// Only used by Hybrid search.
+	QueryVectors map[string][]float64
+
+	// FieldQueries are query texts keyed by vector field, embedded instead of the query of Retrieve.
+	// Only used by Hybrid search.
+	FieldQueries map[string]string
+
+	// SearchTimeout bounds each attempt of the Milvus search call, zero means no timeout.
+	SearchTimeout time.Duration
+
+	// RetryPolicy retries the Milvus search call failed with a transient error, nil means no retry.
+	RetryPolicy *RetryPolicy
 }
 
 // WithFilter returns an option that sets a boolean filter expression for search results.
@@ -57,3 +77,48 @@ func WithGrouping(groupByField string, groupSize int, strict bool) retriever.Opt
 		}
 	})
 }
+
+// WithQueryVector returns an option that searches the dense vector field with the given vector
+// instead of the embedding of the query, enabling cross-modal retrieval in Hybrid search,
+// e.g. an image embedding for the image vector field next to the text query for the text vector field.
+func WithQueryVector(vectorField string, vector []float64) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		if o.QueryVectors == nil {
+			o.QueryVectors = make(map[string][]float64)
+		}
+		o.QueryVectors[vectorField] = vector
+	})
+}
+
+// WithFieldQuery returns an option that embeds the given text instead of the query of Retrieve
+// for the dense vector field in Hybrid search. The text is embedded by the Embedding of the SubRequest.
+func WithFieldQuery(vectorField string, query string) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		if o.FieldQueries == nil {
+			o.FieldQueries = make(map[string]string)
+		}
+		o.FieldQueries[vectorField] = query
+	})
+}
+
+// WithSearchTimeout returns an option that bounds each attempt of the Search, HybridSearch
+// and SearchIterator calls of the search mode with the timeout d.
+func WithSearchTimeout(d time.Duration) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.SearchTimeout = d
+	})
+}
+
+// RetryPolicy configures the retries of the Milvus search calls failed with a transient error.
+// It is the policy of the milvus2 indexer, see retry.Policy; a timed out attempt of WithSearchTimeout
+// is also retried.
+type RetryPolicy = retry.Policy
+
+// WithRetryPolicy returns an option that retries the Search, HybridSearch and SearchIterator calls
+// of the search mode failed with a transient error, e.g. the rate limit is exceeded or the Milvus proxy
+// is unavailable under load. The backoff is doubled after each retry up to MaxBackoff.
+func WithRetryPolicy(policy *RetryPolicy) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *ImplOptions) {
+		o.RetryPolicy = policy
+	})
+}
@@ -19,8 +19,8 @@ package milvus2
 import (
 	"context"
 	"fmt"
+	"time"
 
-	"github.com/bytedance/sonic"
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/embedding"
@@ -47,15 +47,27 @@ type RetrieverConfig struct {
 	Partitions []string
 
 	// VectorField is the name of the vector field in the collection.
-	// Default: "vector"
+	// Default: the only dense vector field of the collection, otherwise "vector"
 	VectorField string
 
 	// SparseVectorField is the field name for sparse vectors.
-	// Default: "sparse_vector"
+	// Default: the only sparse vector field of the collection, otherwise "sparse_vector"
 	SparseVectorField string
 
+	// IDField is the name of the primary key field storing the document ID.
+	// Default: "id"
+	IDField string
+
+	// ContentField is the name of the field storing the document content.
+	// Default: "content"
+	ContentField string
+
+	// MetadataField is the name of the JSON field storing the document metadata.
+	// Default: "metadata"
+	MetadataField string
+
 	// OutputFields specifies which fields to return in search results.
-	// Default: all fields
+	// Default: ["*"], all fields
 	OutputFields []string
 
 	// TopK is the number of results to return.
@@ -66,28 +78,67 @@ type RetrieverConfig struct {
 	// Default: ConsistencyLevelBounded
 	ConsistencyLevel ConsistencyLevel
 
+	// ReplicaNumber is the number of in-memory replicas to load the collection with.
+	// Optional. Default: 0, the replica number of the collection
+	ReplicaNumber int
+
+	// ResourceGroups are the resource groups to load the collection replicas in,
+	// e.g. to isolate latency-sensitive online retrieval from batch analytics.
+	// Milvus serves searches from the loaded replicas, so searches are routed to the query nodes of these groups.
+	// ReplicaNumber and ResourceGroups only apply when the collection is not loaded yet, see ReloadCollection.
+	// Optional.
+	ResourceGroups []string
+
+	// ReloadCollection applies ReplicaNumber and ResourceGroups to an already loaded collection by loading it again.
+	// Milvus has no per-search routing: the replicas are shared by every client of the collection,
+	// so reloading moves the searches of all of them, and the load may take a while on large collections.
+	// Optional. Default: false
+	ReloadCollection bool
+
 	// SearchMode defines the search strategy.
 	// Required.
 	SearchMode SearchMode
 
+	// DefaultSearchParams are the search parameters applied by all vector search modes,
+	// e.g. "ef", "nprobe" or "drop_ratio_search", to tune recall without touching every search mode.
+	// The SearchParams of a search mode or a hybrid SubRequest override them key by key.
+	// Optional.
+	DefaultSearchParams map[string]string
+
 	// DocumentConverter converts Milvus search results to EINO documents.
 	// If nil, uses default conversion.
 	DocumentConverter func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error)
 
+	// CollectionStatsInterval enables reporting the collection row count in the callback output,
+	// queried from Milvus at most once per interval.
+	// Optional. Default: 0, disabled
+	CollectionStatsInterval time.Duration
+
 	// Embedding is the embedder for query vectorization.
 	// Optional. Required if SearchMode uses vector search.
 	Embedding embedding.Embedder
+
+	// EnableCallbacks is false to let the graph running the retriever report its callbacks,
+	// without the collection, index and latency extras.
+	// Optional. Default: true
+	EnableCallbacks *bool
 }
 
 // Retriever implements the retriever.Retriever interface for Milvus 2.x using the V2 SDK.
 type Retriever struct {
 	client *milvusclient.Client
 	config *RetrieverConfig
+	stats  *collectionStats
+
+	// indexType is the CallbackExtraKeyIndexType, described once by NewRetriever.
+	indexType string
 }
 
 // NewRetriever creates a new Milvus2 retriever with the provided configuration.
 // It returns an error if the configuration is invalid.
+// The VectorField and SparseVectorField left empty are inferred from the collection schema.
 func NewRetriever(ctx context.Context, conf *RetrieverConfig) (*Retriever, error) {
+	unset := unsetSchemaFields(conf)
 	if err := conf.validate(); err != nil {
 		return nil, err
 	}
@@ -101,10 +152,19 @@ func NewRetriever(ctx context.Context, conf *RetrieverConfig) (*Retriever, error
 		return nil, err
 	}
 
-	return &Retriever{
+	if err := inspectCollection(ctx, cli, conf, unset); err != nil {
+		return nil, err
+	}
+
+	r := &Retriever{
 		client: cli,
 		config: conf,
-	}, nil
+		stats:  newCollectionStats(conf.CollectionStatsInterval),
+	}
+	if r.IsCallbacksEnabled() {
+		r.indexType = describeIndexType(ctx, cli, conf)
+	}
+	return r, nil
 }
 
 func initClient(ctx context.Context, conf *RetrieverConfig) (*milvusclient.Client, error) {
@@ -137,8 +197,16 @@ func loadCollection(ctx context.Context, cli *milvusclient.Client, conf *Retriev
 	if err != nil {
 		return fmt.Errorf("[NewRetriever] failed to get load state: %w", err)
 	}
-	if loadState.State != entity.LoadStateLoaded {
-		loadTask, err := cli.LoadCollection(ctx, milvusclient.NewLoadCollectionOption(conf.Collection))
+	reload := conf.ReloadCollection && (conf.ReplicaNumber > 0 || len(conf.ResourceGroups) > 0)
+	if loadState.State != entity.LoadStateLoaded || reload {
+		loadOpt := milvusclient.NewLoadCollectionOption(conf.Collection)
+		if conf.ReplicaNumber > 0 {
+			loadOpt = loadOpt.WithReplica(conf.ReplicaNumber)
+		}
+		if len(conf.ResourceGroups) > 0 {
+			loadOpt = loadOpt.WithResourceGroup(conf.ResourceGroups...)
+		}
+		loadTask, err := cli.LoadCollection(ctx, loadOpt)
 		if err != nil {
 			return fmt.Errorf("[NewRetriever] failed to load collection: %w", err)
 		}
@@ -149,13 +217,42 @@ func loadCollection(ctx context.Context, cli *milvusclient.Client, conf *Retriev
 	return nil
 }
 
+// inspectCollection describes the collection to infer the unset fields of the config from its schema,
+// then validates the search mode against it if it implements SearchModeValidator.
+func inspectCollection(ctx context.Context, cli *milvusclient.Client, conf *RetrieverConfig, unset schemaFields) error {
+	validator, ok := conf.SearchMode.(SearchModeValidator)
+	if !ok && !unset.any() {
+		return nil
+	}
+
+	collection, err := cli.DescribeCollection(ctx, milvusclient.NewDescribeCollectionOption(conf.Collection))
+	if err != nil {
+		return fmt.Errorf("[NewRetriever] failed to describe collection: %w", err)
+	}
+	if err := applySchema(conf, unset, collection); err != nil {
+		return fmt.Errorf("[NewRetriever] %w", err)
+	}
+	if !ok {
+		return nil
+	}
+	if err := validator.Validate(ctx, conf, collection); err != nil {
+		return fmt.Errorf("[NewRetriever] invalid search mode: %w", err)
+	}
+	return nil
+}
+
 // Retrieve searches for documents matching the given query.
 // It returns the matching documents or an error.
 func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
 	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
+	if !r.IsCallbacksEnabled() {
+		return r.config.SearchMode.Retrieve(ctx, r.client, r.config, query, opts...)
+	}
+
 	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
 		Query: query,
 		TopK:  r.config.TopK,
+		Extra: r.callbackInputExtra(opts...),
 	})
 	defer func() {
 		if err != nil {
@@ -163,12 +260,25 @@ func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retrieve
 		}
 	}()
 
-	docs, err = r.config.SearchMode.Retrieve(ctx, r.client, r.config, query, opts...)
+	start := time.Now()
+	searchCtx, nq := withNQCounter(ctx)
+	docs, err = r.config.SearchMode.Retrieve(searchCtx, r.client, r.config, query, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs})
+	extra := map[string]any{
+		CallbackExtraKeyLatency:     time.Since(start),
+		CallbackExtraKeyResultCount: len(docs),
+	}
+	if n := nq.Load(); n > 0 {
+		extra[CallbackExtraKeyNQ] = int(n)
+	}
+	if rowCount, ok := r.stats.getRowCount(ctx, r.client, r.config.Collection); ok {
+		extra[CallbackExtraKeyCollectionRowCount] = rowCount
+	}
+
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{Docs: docs, Extra: extra})
 	return docs, nil
 }
 
@@ -177,18 +287,15 @@ func (r *Retriever) GetType() string {
 	return typ
 }
 
-// IsCallbacksEnabled checks if callbacks are enabled for this retriever.
+// IsCallbacksEnabled returns RetrieverConfig.EnableCallbacks.
 func (r *Retriever) IsCallbacksEnabled() bool {
-	return true
+	return r.config.EnableCallbacks == nil || *r.config.EnableCallbacks
 }
 
 // validate checks the configuration and sets default values.
 func (c *RetrieverConfig) validate() error {
-	if c.Client == nil && c.ClientConfig == nil {
-		return fmt.Errorf("[NewRetriever] milvus client or client config not provided")
-	}
-	if c.SearchMode == nil {
-		return fmt.Errorf("[NewRetriever] search mode not provided")
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("[NewRetriever] %w", err)
 	}
 	// Embedding validation is delegated to the specific SearchMode implementation.
 	if c.Collection == "" {
@@ -200,6 +307,15 @@ func (c *RetrieverConfig) validate() error {
 	if c.SparseVectorField == "" {
 		c.SparseVectorField = defaultSparseVectorField
 	}
+	if c.IDField == "" {
+		c.IDField = defaultIDField
+	}
+	if c.ContentField == "" {
+		c.ContentField = defaultContentField
+	}
+	if c.MetadataField == "" {
+		c.MetadataField = defaultMetadataField
+	}
 	if len(c.OutputFields) == 0 {
 		c.OutputFields = []string{"*"}
 	}
@@ -207,13 +323,25 @@ func (c *RetrieverConfig) validate() error {
 		c.TopK = defaultTopK
 	}
 	if c.DocumentConverter == nil {
-		c.DocumentConverter = defaultDocumentConverter()
+		c.DocumentConverter = defaultDocumentConverter(scalarFields{
+			id:       c.IDField,
+			content:  c.ContentField,
+			metadata: c.MetadataField,
+		})
 	}
 	return nil
 }
 
-// defaultDocumentConverter returns the default result to document converter.
-func defaultDocumentConverter() func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
+// scalarFields are the names of the id, content and metadata fields read by the default converter.
+type scalarFields struct {
+	id       string
+	content  string
+	metadata string
+}
+
+// defaultDocumentConverter returns the default result to document converter, reading the scalar columns of fields.
+// The other output fields are returned in the document metadata.
+func defaultDocumentConverter(fields scalarFields) func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
 	return func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
 		docs := make([]*schema.Document, 0, result.ResultCount)
 
@@ -233,22 +361,21 @@ func defaultDocumentConverter() func(ctx context.Context, result milvusclient.Re
 				}
 
 				switch field.Name() {
-				case "id":
+				case fields.id:
 					if id, ok := val.(string); ok {
 						doc.ID = id
 					} else if idStr, err := field.GetAsString(i); err == nil {
 						doc.ID = idStr
 					}
-				case defaultContentField:
+				case fields.content:
 					if content, ok := val.(string); ok {
 						doc.Content = content
 					} else if contentStr, err := field.GetAsString(i); err == nil {
 						doc.Content = contentStr
 					}
-				case defaultMetadataField:
+				case fields.metadata:
 					if metaBytes, ok := val.([]byte); ok {
-						var meta map[string]any
-						if err := sonic.Unmarshal(metaBytes, &meta); err == nil {
+						if meta, err := decodeMetadata(metaBytes); err == nil {
 							for k, v := range meta {
 								doc.MetaData[k] = v
 							}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/schema"
@@ -31,6 +32,9 @@ import (
 	"github.com/smartystreets/goconvey/convey"
 )
 
+// defaultScalarFields are the field names of the collections created by the milvus2 indexer with the default configuration.
+var defaultScalarFields = scalarFields{id: defaultIDField, content: defaultContentField, metadata: defaultMetadataField}
+
 // mockEmbedding implements embedding.Embedder for testing
 type mockEmbedding struct {
 	err  error
@@ -98,6 +102,9 @@ func TestRetrieverConfig_validate(t *testing.T) {
 			}
 			err := config.validate()
 			convey.So(err, convey.ShouldBeNil)
+			convey.So(config.IDField, convey.ShouldEqual, defaultIDField)
+			convey.So(config.ContentField, convey.ShouldEqual, defaultContentField)
+			convey.So(config.MetadataField, convey.ShouldEqual, defaultMetadataField)
 		})
 
 		convey.Convey("test missing search mode", func() {
@@ -111,6 +118,17 @@ func TestRetrieverConfig_validate(t *testing.T) {
 			convey.So(err, convey.ShouldNotBeNil)
 			convey.So(err.Error(), convey.ShouldContainSubstring, "search mode")
 		})
+
+		convey.Convey("test negative replica number", func() {
+			config := &RetrieverConfig{
+				ClientConfig:  &milvusclient.ClientConfig{Address: "localhost:19530"},
+				SearchMode:    mockSM,
+				ReplicaNumber: -1,
+			}
+			err := config.validate()
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "ReplicaNumber: must not be negative")
+		})
 	})
 }
 
@@ -160,6 +178,10 @@ func TestRetriever_IsCallbacksEnabled(t *testing.T) {
 		}
 		result := r.IsCallbacksEnabled()
 		convey.So(result, convey.ShouldBeTrue)
+
+		enableCallbacks := false
+		r.config.EnableCallbacks = &enableCallbacks
+		convey.So(r.IsCallbacksEnabled(), convey.ShouldBeFalse)
 	})
 }
 
@@ -221,7 +243,7 @@ func TestWithGrouping(t *testing.T) {
 func TestDocumentConverter(t *testing.T) {
 	convey.Convey("test defaultDocumentConverter", t, func() {
 		ctx := context.Background()
-		converter := defaultDocumentConverter()
+		converter := defaultDocumentConverter(defaultScalarFields)
 
 		convey.Convey("convert standard results with scores and metadata", func() {
 			ids := []string{"1", "2"}
@@ -270,6 +292,45 @@ func TestDocumentConverter(t *testing.T) {
 			_, hasScore := docs[0].MetaData["score"]
 			convey.So(hasScore, convey.ShouldBeFalse)
 		})
+
+		convey.Convey("convert results with custom field names", func() {
+			converter := defaultDocumentConverter(scalarFields{id: "pk", content: "text", metadata: "attrs"})
+			idCol := column.NewColumnVarChar("pk", []string{"5"})
+			resultSet := milvusclient.ResultSet{
+				ResultCount: 1,
+				IDs:         idCol,
+				Fields: []column.Column{
+					idCol,
+					column.NewColumnVarChar("text", []string{"doc5"}),
+					column.NewColumnJSONBytes("attrs", [][]byte{[]byte(`{"key": "val5"}`)}),
+					column.NewColumnVarChar("content", []string{"other"}),
+				},
+			}
+			docs, err := converter(ctx, resultSet)
+
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 1)
+			convey.So(docs[0].ID, convey.ShouldEqual, "5")
+			convey.So(docs[0].Content, convey.ShouldEqual, "doc5")
+			convey.So(docs[0].MetaData["key"], convey.ShouldEqual, "val5")
+			convey.So(docs[0].MetaData["content"], convey.ShouldEqual, "other")
+		})
+
+		convey.Convey("convert results with compressed metadata", func() {
+			// gzip+base64 of {"key":"compressed"}
+			metas := [][]byte{
+				[]byte(`{"_eino_metadata_codec":"gzip+base64","_eino_metadata_data":"H4sIAAAAAAAC/6tWyk6tVLJSSs7PLShKLS5OTVGqBQBt1tozFAAAAA=="}`),
+			}
+
+			resultSet := createMockQueryResult([]string{"4"}, []string{"doc4"}, metas)
+			docs, err := converter(ctx, resultSet)
+
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 1)
+			convey.So(docs[0].MetaData["key"], convey.ShouldEqual, "compressed")
+			_, hasCodec := docs[0].MetaData["_eino_metadata_codec"]
+			convey.So(hasCodec, convey.ShouldBeFalse)
+		})
 	})
 }
 
@@ -385,6 +446,36 @@ func TestLoadCollection(t *testing.T) {
 			convey.So(err, convey.ShouldBeNil)
 		})
 
+		PatchConvey("Already loaded, keep replica and resource groups", func() {
+			Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
+			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateLoaded}, nil).Build()
+			loadMocker := Mock(GetMethod(mockClient, "LoadCollection")).Return(milvusclient.LoadTask{}, nil).Build()
+
+			err := loadCollection(ctx, mockClient, &RetrieverConfig{
+				Collection:     "test_coll",
+				ReplicaNumber:  2,
+				ResourceGroups: []string{"rg_online"},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(loadMocker.Times(), convey.ShouldEqual, 0)
+		})
+
+		PatchConvey("Already loaded, reload with replica and resource groups", func() {
+			Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
+			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateLoaded}, nil).Build()
+			loadMocker := Mock(GetMethod(mockClient, "LoadCollection")).Return(milvusclient.LoadTask{}, nil).Build()
+			Mock(GetMethod(&milvusclient.LoadTask{}, "Await")).Return(nil).Build()
+
+			err := loadCollection(ctx, mockClient, &RetrieverConfig{
+				Collection:       "test_coll",
+				ReplicaNumber:    2,
+				ResourceGroups:   []string{"rg_online"},
+				ReloadCollection: true,
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(loadMocker.Times(), convey.ShouldEqual, 1)
+		})
+
 		PatchConvey("LoadCollection error", func() {
 			Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
 			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateCode(0)}, nil).Build()
@@ -448,14 +539,63 @@ func TestNewRetriever_FullFlow(t *testing.T) {
 			Mock(milvusclient.New).Return(mockClient, nil).Build()
 			Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
 			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateLoaded}, nil).Build()
+			Mock(GetMethod(mockClient, "DescribeCollection")).Return(&entity.Collection{Name: "test", Schema: entity.NewSchema().
+				WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeVarChar)).
+				WithField(entity.NewField().WithName("content").WithDataType(entity.FieldTypeVarChar)).
+				WithField(entity.NewField().WithName("embedding").WithDataType(entity.FieldTypeFloatVector))}, nil).Build()
+
+			r, err := NewRetriever(ctx, conf)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(r, convey.ShouldNotBeNil)
+			convey.So(conf.VectorField, convey.ShouldEqual, "embedding")
+			convey.So(conf.OutputFields, convey.ShouldResemble, []string{"*"})
+		})
+
+		PatchConvey("describe collection error", func() {
+			mockClient := &milvusclient.Client{}
+			Mock(milvusclient.New).Return(mockClient, nil).Build()
+			Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
+			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateLoaded}, nil).Build()
+			Mock(GetMethod(mockClient, "DescribeCollection")).Return(nil, fmt.Errorf("describe error")).Build()
+
+			r, err := NewRetriever(ctx, conf)
+			convey.So(r, convey.ShouldBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "[NewRetriever] failed to describe collection")
+		})
+
+		PatchConvey("search mode validation", func() {
+			mockClient := &milvusclient.Client{}
+			Mock(milvusclient.New).Return(mockClient, nil).Build()
+			Mock(GetMethod(mockClient, "HasCollection")).Return(true, nil).Build()
+			Mock(GetMethod(mockClient, "GetLoadState")).Return(entity.LoadState{State: entity.LoadStateLoaded}, nil).Build()
+			Mock(GetMethod(mockClient, "DescribeCollection")).Return(&entity.Collection{Name: "test"}, nil).Build()
 
+			validator := &mockValidatingSearchMode{err: fmt.Errorf("field not found")}
+			conf.SearchMode = validator
 			r, err := NewRetriever(ctx, conf)
+			convey.So(r, convey.ShouldBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "[NewRetriever] invalid search mode: field not found")
+			convey.So(validator.collection.Name, convey.ShouldEqual, "test")
+
+			validator.err = nil
+			r, err = NewRetriever(ctx, conf)
 			convey.So(err, convey.ShouldBeNil)
 			convey.So(r, convey.ShouldNotBeNil)
 		})
 	})
 }
 
+type mockValidatingSearchMode struct {
+	mockSearchMode
+	err        error
+	collection *entity.Collection
+}
+
+func (m *mockValidatingSearchMode) Validate(ctx context.Context, conf *RetrieverConfig, collection *entity.Collection) error {
+	m.collection = collection
+	return m.err
+}
+
 func TestRetrieve_Callbacks(t *testing.T) {
 	PatchConvey("test Retrieve callbacks", t, func() {
 		ctx := context.Background()
@@ -484,5 +624,33 @@ func TestRetrieve_Callbacks(t *testing.T) {
 		docs, err := r.Retrieve(ctx, "query")
 		convey.So(err, convey.ShouldBeNil)
 		convey.So(docs, convey.ShouldNotBeNil)
+
+		PatchConvey("disable callbacks", func() {
+			var started, ended int
+			handler := callbacks.NewHandlerBuilder().
+				OnStartFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackInput) context.Context {
+					started++
+					return ctx
+				}).
+				OnEndFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackOutput) context.Context {
+					ended++
+					return ctx
+				}).Build()
+			cbCtx := callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, handler)
+
+			enableCallbacks := false
+			r.config.EnableCallbacks = &enableCallbacks
+			docs, err := r.Retrieve(cbCtx, "query")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldNotBeNil)
+			convey.So(started, convey.ShouldEqual, 0)
+			convey.So(ended, convey.ShouldEqual, 0)
+
+			r.config.EnableCallbacks = nil
+			_, err = r.Retrieve(cbCtx, "query")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(started, convey.ShouldEqual, 1)
+			convey.So(ended, convey.ShouldEqual, 1)
+		})
 	})
 }
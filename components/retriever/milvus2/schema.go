@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"fmt"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+)
+
+// schemaFields records the fields of the config left empty by the user,
+// which NewRetriever infers from the collection schema instead of keeping their defaults.
+type schemaFields struct {
+	vectorField       bool
+	sparseVectorField bool
+}
+
+// unsetSchemaFields returns the fields of the config to infer, it must be called before the defaults are set.
+func unsetSchemaFields(c *RetrieverConfig) schemaFields {
+	return schemaFields{
+		vectorField:       c.VectorField == "",
+		sparseVectorField: c.SparseVectorField == "",
+	}
+}
+
+func (s schemaFields) any() bool {
+	return s.vectorField || s.sparseVectorField
+}
+
+// applySchema infers the unset vector fields of the config from the collection schema.
+// VectorField and SparseVectorField are the only dense and sparse vector fields of the collection,
+// the default names are kept if present or if the collection has no such field.
+// A collection with several dense vector fields but none with the default name is rejected,
+// since searching an arbitrary one of them would return wrong results, VectorField must be set then.
+// Several sparse vector fields keep the default name, as most search modes do not use it.
+func applySchema(c *RetrieverConfig, unset schemaFields, collection *entity.Collection) error {
+	if collection == nil || collection.Schema == nil {
+		return nil
+	}
+	sch := collection.Schema
+
+	if unset.vectorField {
+		name, candidates := inferVectorField(sch, c.VectorField, isDenseVector)
+		if len(candidates) > 1 {
+			return fmt.Errorf("collection %q has several dense vector fields %q, set VectorField to the one to search",
+				collection.Name, candidates)
+		}
+		c.VectorField = name
+	}
+	if unset.sparseVectorField {
+		c.SparseVectorField, _ = inferVectorField(sch, c.SparseVectorField, isSparseVector)
+	}
+	return nil
+}
+
+// inferVectorField returns the only field of the schema matching the vector type, or the default name
+// if the schema has a field with that name or no matching field at all.
+// It also returns the matching fields, which are ambiguous when there are several of them.
+func inferVectorField(sch *entity.Schema, defaultName string, match func(*entity.Field) bool) (string, []string) {
+	var candidates []string
+	for _, f := range sch.Fields {
+		if !match(f) {
+			continue
+		}
+		if f.Name == defaultName {
+			return defaultName, nil
+		}
+		candidates = append(candidates, f.Name)
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	return defaultName, candidates
+}
+
+func isDenseVector(f *entity.Field) bool {
+	switch f.DataType {
+	case entity.FieldTypeFloatVector, entity.FieldTypeFloat16Vector, entity.FieldTypeBFloat16Vector,
+		entity.FieldTypeBinaryVector, entity.FieldTypeInt8Vector:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSparseVector(f *entity.Field) bool {
+	return f.DataType == entity.FieldTypeSparseVector
+}
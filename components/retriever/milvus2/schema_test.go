@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"testing"
+
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestApplySchema(t *testing.T) {
+	convey.Convey("test applySchema", t, func() {
+		field := func(name string, typ entity.FieldType) *entity.Field {
+			return entity.NewField().WithName(name).WithDataType(typ)
+		}
+		collectionOf := func(fields ...*entity.Field) *entity.Collection {
+			sch := entity.NewSchema().WithName("kb")
+			for _, f := range fields {
+				sch.WithField(f)
+			}
+			return &entity.Collection{Name: "kb", Schema: sch}
+		}
+		inferErr := func(conf *RetrieverConfig, collection *entity.Collection) (*RetrieverConfig, error) {
+			conf.ClientConfig = &milvusclient.ClientConfig{Address: "localhost:19530"}
+			conf.SearchMode = &mockSearchMode{}
+			unset := unsetSchemaFields(conf)
+			convey.So(conf.validate(), convey.ShouldBeNil)
+			return conf, applySchema(conf, unset, collection)
+		}
+		infer := func(conf *RetrieverConfig, collection *entity.Collection) *RetrieverConfig {
+			conf, err := inferErr(conf, collection)
+			convey.So(err, convey.ShouldBeNil)
+			return conf
+		}
+
+		convey.Convey("infer the only vector fields and keep all output fields", func() {
+			conf := infer(&RetrieverConfig{}, collectionOf(
+				field("chunk_id", entity.FieldTypeVarChar),
+				field("text", entity.FieldTypeVarChar),
+				field("embedding", entity.FieldTypeFloatVector),
+				field("bm25", entity.FieldTypeSparseVector),
+				field("attrs", entity.FieldTypeJSON),
+			))
+			convey.So(conf.VectorField, convey.ShouldEqual, "embedding")
+			convey.So(conf.SparseVectorField, convey.ShouldEqual, "bm25")
+			convey.So(conf.OutputFields, convey.ShouldResemble, []string{"*"})
+		})
+
+		convey.Convey("keep the configured fields", func() {
+			conf := infer(&RetrieverConfig{
+				VectorField:  "embedding",
+				OutputFields: []string{"text"},
+			}, collectionOf(
+				field("id", entity.FieldTypeInt64),
+				field("text", entity.FieldTypeVarChar),
+				field("embedding", entity.FieldTypeFloatVector),
+				field("image_embedding", entity.FieldTypeFloatVector),
+			))
+			convey.So(conf.VectorField, convey.ShouldEqual, "embedding")
+			convey.So(conf.SparseVectorField, convey.ShouldEqual, defaultSparseVectorField)
+			convey.So(conf.OutputFields, convey.ShouldResemble, []string{"text"})
+		})
+
+		convey.Convey("reject several dense vector fields without the default name", func() {
+			_, err := inferErr(&RetrieverConfig{}, collectionOf(
+				field("id", entity.FieldTypeInt64),
+				field("text_vector", entity.FieldTypeFloatVector),
+				field("image_vector", entity.FieldTypeBinaryVector),
+			))
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "set VectorField")
+		})
+
+		convey.Convey("keep the default name of ambiguous schemas", func() {
+			conf := infer(&RetrieverConfig{}, collectionOf(
+				field("id", entity.FieldTypeInt64),
+				field("sparse_a", entity.FieldTypeSparseVector),
+				field("sparse_b", entity.FieldTypeSparseVector),
+				field("embedding", entity.FieldTypeFloatVector),
+			))
+			convey.So(conf.SparseVectorField, convey.ShouldEqual, defaultSparseVectorField)
+
+			conf = infer(&RetrieverConfig{}, collectionOf(
+				field("id", entity.FieldTypeInt64),
+				field("vector", entity.FieldTypeFloatVector),
+				field("title_vector", entity.FieldTypeFloatVector),
+			))
+			convey.So(conf.VectorField, convey.ShouldEqual, defaultVectorField)
+		})
+
+		convey.Convey("keep the defaults without schema", func() {
+			conf := infer(&RetrieverConfig{}, nil)
+			convey.So(conf.VectorField, convey.ShouldEqual, defaultVectorField)
+			convey.So(conf.SparseVectorField, convey.ShouldEqual, defaultSparseVectorField)
+			convey.So(conf.OutputFields, convey.ShouldResemble, []string{"*"})
+		})
+	})
+}
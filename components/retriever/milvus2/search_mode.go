@@ -21,6 +21,7 @@ import (
 
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/entity"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
 )
 
@@ -30,3 +31,18 @@ type SearchMode interface {
 	// Retrieve performs the search operation using the provided client and configuration.
 	Retrieve(ctx context.Context, client *milvusclient.Client, conf *RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error)
 }
+
+// SearchModeValidator is implemented by search modes which validate their configuration
+// against the collection schema when the retriever is created,
+// so that misconfigurations fail in NewRetriever instead of the first query.
+type SearchModeValidator interface {
+	// Validate checks the search mode against the retriever configuration and the described collection.
+	Validate(ctx context.Context, conf *RetrieverConfig, collection *entity.Collection) error
+}
+
+// MetricTypeReporter is implemented by search modes which search with a single metric type,
+// reported in the callback input extra under CallbackExtraKeyMetricType.
+type MetricTypeReporter interface {
+	// GetMetricType returns the metric type of the search, empty if it is inferred by Milvus.
+	GetMetricType() MetricType
+}
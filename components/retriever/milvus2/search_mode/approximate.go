@@ -35,6 +35,16 @@ type Approximate struct {
 	// MetricType specifies the metric type for vector similarity.
 	// Default: L2.
 	MetricType milvus2.MetricType
+
+	// SearchParams contains extra search parameters (e.g., "nprobe", "ef").
+	// They override RetrieverConfig.DefaultSearchParams.
+	SearchParams map[string]string
+
+	// TextQuery passes the query text to Milvus as is instead of embedding it,
+	// for the full-text search on the sparse vector field generated by a BM25 function.
+	// RetrieverConfig.SparseVectorField is searched instead of VectorField, and Embedding is not required.
+	// It is always enabled when MetricType is BM25.
+	TextQuery bool
 }
 
 // NewApproximate creates a new Approximate search mode with the specified metric type.
@@ -47,26 +57,54 @@ func NewApproximate(metricType milvus2.MetricType) *Approximate {
 	}
 }
 
+// WithSearchParams sets additional search parameters such as "nprobe" or "ef".
+func (a *Approximate) WithSearchParams(params map[string]string) *Approximate {
+	a.SearchParams = params
+	return a
+}
+
+// WithTextQuery sets whether the query text is passed to Milvus as is instead of embedding it.
+func (a *Approximate) WithTextQuery(enabled bool) *Approximate {
+	a.TextQuery = enabled
+	return a
+}
+
+// GetMetricType returns the metric type of the approximate search.
+func (a *Approximate) GetMetricType() milvus2.MetricType {
+	return a.MetricType
+}
+
 // Retrieve performs the approximate vector search.
 func (a *Approximate) Retrieve(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
-	if conf.Embedding == nil {
-		return nil, fmt.Errorf("embedding is required for approximate search")
-	}
+	var (
+		searchOpt milvusclient.SearchOption
+		err       error
+	)
+	if a.isTextQuery() {
+		searchOpt, err = a.BuildTextSearchOption(conf, query, opts...)
+	} else {
+		if conf.Embedding == nil {
+			return nil, fmt.Errorf("embedding is required for approximate search")
+		}
 
-	queryVector, err := EmbedQuery(ctx, conf.Embedding, query)
-	if err != nil {
-		return nil, err
-	}
+		queryVector, embErr := EmbedQuery(ctx, conf.Embedding, query)
+		if embErr != nil {
+			return nil, embErr
+		}
 
-	searchOpt, err := a.BuildSearchOption(ctx, conf, queryVector, opts...)
+		searchOpt, err = a.BuildSearchOption(ctx, conf, queryVector, opts...)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to build search option: %w", err)
 	}
 
-	result, err := client.Search(ctx, searchOpt)
+	result, err := callWithRetry(ctx, func(ctx context.Context) ([]milvusclient.ResultSet, error) {
+		return client.Search(ctx, searchOpt)
+	}, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search: %w", err)
 	}
+	reportSearchNQ(ctx, searchOpt)
 
 	if len(result) == 0 {
 		return []*schema.Document{}, nil
@@ -77,6 +115,21 @@ func (a *Approximate) Retrieve(ctx context.Context, client *milvusclient.Client,
 
 // BuildSearchOption creates a SearchOption for ANN search with the configured metric type.
 func (a *Approximate) BuildSearchOption(ctx context.Context, conf *milvus2.RetrieverConfig, queryVector []float32, opts ...retriever.Option) (milvusclient.SearchOption, error) {
+	return a.buildSearchOption(conf, conf.VectorField, entity.FloatVector(queryVector), opts...)
+}
+
+// BuildTextSearchOption creates a SearchOption for the full-text search of the query text on SparseVectorField,
+// which Milvus converts to a sparse vector with the BM25 function of the field.
+func (a *Approximate) BuildTextSearchOption(conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) (milvusclient.SearchOption, error) {
+	return a.buildSearchOption(conf, conf.SparseVectorField, entity.Text(query), opts...)
+}
+
+// isTextQuery reports whether the query text is searched as is, see TextQuery.
+func (a *Approximate) isTextQuery() bool {
+	return a.TextQuery || a.MetricType == milvus2.BM25
+}
+
+func (a *Approximate) buildSearchOption(conf *milvus2.RetrieverConfig, annsField string, vector entity.Vector, opts ...retriever.Option) (milvusclient.SearchOption, error) {
 	io := retriever.GetImplSpecificOptions(&milvus2.ImplOptions{}, opts...)
 	co := retriever.GetCommonOptions(&retriever.Options{
 		TopK: &conf.TopK,
@@ -88,10 +141,14 @@ func (a *Approximate) BuildSearchOption(ctx context.Context, conf *milvus2.Retri
 		topK = *co.TopK
 	}
 
-	searchOpt := milvusclient.NewSearchOption(conf.Collection, topK, []entity.Vector{entity.FloatVector(queryVector)}).
-		WithANNSField(conf.VectorField).
+	searchOpt := milvusclient.NewSearchOption(conf.Collection, topK, []entity.Vector{vector}).
+		WithANNSField(annsField).
 		WithOutputFields(conf.OutputFields...)
 
+	for k, v := range mergeSearchParams(conf, a.SearchParams) {
+		searchOpt.WithSearchParam(k, v)
+	}
+
 	// Apply metric type
 	if a.MetricType != "" {
 		searchOpt.WithSearchParam("metric_type", string(a.MetricType))
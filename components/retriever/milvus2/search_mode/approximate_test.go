@@ -59,6 +59,16 @@ func TestNewApproximate(t *testing.T) {
 	})
 }
 
+func TestApproximate_WithSearchParams(t *testing.T) {
+	convey.Convey("test Approximate.WithSearchParams", t, func() {
+		a := NewApproximate(milvus2.L2)
+		params := map[string]string{"ef": "64"}
+		result := a.WithSearchParams(params)
+		convey.So(result, convey.ShouldEqual, a)
+		convey.So(a.SearchParams, convey.ShouldResemble, params)
+	})
+}
+
 func TestApproximate_BuildSearchOption(t *testing.T) {
 	convey.Convey("test Approximate.BuildSearchOption", t, func() {
 		ctx := context.Background()
@@ -116,6 +126,35 @@ func TestApproximate_BuildSearchOption(t *testing.T) {
 			convey.So(err, convey.ShouldBeNil)
 			convey.So(opt, convey.ShouldNotBeNil)
 		})
+
+		convey.Convey("test text search option", func() {
+			approx := NewApproximate(milvus2.BM25)
+			opt, err := approx.BuildTextSearchOption(&milvus2.RetrieverConfig{
+				Collection:        "test_collection",
+				VectorField:       "vector",
+				SparseVectorField: "sparse_vector",
+				TopK:              10,
+			}, "query", milvus2.WithFilter("id > 10"))
+			convey.So(err, convey.ShouldBeNil)
+			req, err := opt.Request()
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(req.GetDsl(), convey.ShouldEqual, "id > 10")
+			var annsField string
+			for _, kv := range req.GetSearchParams() {
+				if kv.GetKey() == "anns_field" {
+					annsField = kv.GetValue()
+				}
+			}
+			convey.So(annsField, convey.ShouldEqual, "sparse_vector")
+		})
+	})
+}
+
+func TestApproximate_TextQuery(t *testing.T) {
+	convey.Convey("test Approximate text query detection", t, func() {
+		convey.So(NewApproximate(milvus2.BM25).isTextQuery(), convey.ShouldBeTrue)
+		convey.So(NewApproximate(milvus2.IP).isTextQuery(), convey.ShouldBeFalse)
+		convey.So(NewApproximate(milvus2.IP).WithTextQuery(true).isTextQuery(), convey.ShouldBeTrue)
 	})
 }
 
@@ -123,6 +162,7 @@ func TestApproximate_BuildSearchOption(t *testing.T) {
 func TestApproximate_ImplementsSearchMode(t *testing.T) {
 	convey.Convey("test Approximate implements SearchMode", t, func() {
 		var _ milvus2.SearchMode = (*Approximate)(nil)
+		var _ milvus2.MetricTypeReporter = (*Approximate)(nil)
 	})
 }
 
@@ -159,10 +199,13 @@ func TestApproximate_Retrieve(t *testing.T) {
 				return []*schema.Document{{ID: "1"}}, nil
 			}
 			config.DocumentConverter = mockConverter
+			var nq int64
+			Mock(milvus2.ReportNQ).To(func(_ context.Context, n int64) { nq += n }).Build()
 
 			docs, err := approx.Retrieve(ctx, mockClient, config, "query")
 			convey.So(err, convey.ShouldBeNil)
 			convey.So(len(docs), convey.ShouldEqual, 1)
+			convey.So(nq, convey.ShouldEqual, 1)
 		})
 
 		PatchConvey("embedding error", func() {
@@ -183,6 +226,25 @@ func TestApproximate_Retrieve(t *testing.T) {
 			convey.So(docs, convey.ShouldBeNil)
 		})
 
+		PatchConvey("text query without embedding", func() {
+			textConfig := &milvus2.RetrieverConfig{
+				Collection:        "test_collection",
+				VectorField:       "vector",
+				SparseVectorField: "sparse_vector",
+				TopK:              10,
+				OutputFields:      []string{"id", "content"},
+				DocumentConverter: func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
+					return []*schema.Document{{ID: "1"}}, nil
+				},
+			}
+			mocker := Mock(GetMethod(mockClient, "Search")).Return([]milvusclient.ResultSet{{ResultCount: 1}}, nil).Build()
+
+			docs, err := NewApproximate(milvus2.BM25).Retrieve(ctx, mockClient, textConfig, "query")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 1)
+			convey.So(mocker.Times(), convey.ShouldEqual, 1)
+		})
+
 		PatchConvey("empty result", func() {
 			mockEmb.err = nil
 			Mock(GetMethod(mockClient, "Search")).Return([]milvusclient.ResultSet{}, nil).Build()
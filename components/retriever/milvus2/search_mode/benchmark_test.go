@@ -0,0 +1,66 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+)
+
+const benchmarkDim = 1024
+
+func BenchmarkHybrid_BuildHybridSearchOption(b *testing.B) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+	queryVector := make([]float32, benchmarkDim)
+	imageVector := make([]float64, benchmarkDim)
+	for i := range queryVector {
+		queryVector[i] = rng.Float32()
+		imageVector[i] = rng.Float64()
+	}
+
+	conf := &milvus2.RetrieverConfig{
+		Collection:          "test_collection",
+		VectorField:         "vector",
+		SparseVectorField:   "sparse_vector",
+		TopK:                10,
+		DefaultSearchParams: map[string]string{"ef": "64"},
+	}
+	hybrid := NewHybrid(milvusclient.NewRRFReranker(),
+		&SubRequest{VectorField: "vector", VectorType: milvus2.DenseVector, MetricType: milvus2.COSINE, TopK: 20},
+		&SubRequest{VectorField: "image_vector", VectorType: milvus2.DenseVector, MetricType: milvus2.IP, TopK: 20},
+		&SubRequest{VectorType: milvus2.SparseVector, MetricType: milvus2.BM25, TopK: 20},
+	)
+	opts := []retriever.Option{
+		milvus2.WithFilter("tag == 'a'"),
+		milvus2.WithQueryVector("image_vector", imageVector),
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := hybrid.BuildHybridSearchOption(ctx, conf, queryVector, "query", opts...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/entity"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+)
+
+// Fusion is the method fusing the results of the search modes of Composite.
+type Fusion string
+
+const (
+	// FusionRRF scores a document by Reciprocal Rank Fusion, the sum of 1/(k+rank) over the results it appears in.
+	FusionRRF Fusion = "rrf"
+	// FusionWeighted scores a document by the weighted sum of its scores, normalized to [0, 1] in every result.
+	FusionWeighted Fusion = "weighted"
+)
+
+// Composite runs several search modes concurrently, e.g. Approximate and Sparse on different fields,
+// and fuses their results on the client side, like Hybrid with RRFReranker or WeightedReranker does on the server.
+// It covers the Milvus servers too old for hybrid search, and the combinations Hybrid does not support,
+// e.g. a Range search with a Scalar lookup.
+//
+// The documents are matched across the results by ID, the fused score is set as the document score,
+// and the documents are returned by descending fused score.
+type Composite struct {
+	// Modes are the search modes to run, each with the query and options of Retrieve.
+	Modes []milvus2.SearchMode
+
+	// Fusion is the fusion method.
+	// Default: FusionRRF
+	Fusion Fusion
+
+	// RRFK is the smoothing parameter k of FusionRRF.
+	// Default: 60
+	RRFK int
+
+	// Weights are the weights of the Modes for FusionWeighted, in the same order.
+	// Default: equal weights
+	Weights []float64
+
+	// TopK overrides the final number of results to return.
+	// If 0, uses RetrieverConfig.TopK.
+	TopK int
+}
+
+// NewComposite creates a new Composite search mode fusing the results of modes with RRF.
+func NewComposite(modes ...milvus2.SearchMode) *Composite {
+	return &Composite{
+		Modes:  modes,
+		Fusion: FusionRRF,
+	}
+}
+
+// WithRRF fuses the results with Reciprocal Rank Fusion, with the smoothing parameter k.
+func (c *Composite) WithRRF(k int) *Composite {
+	c.Fusion = FusionRRF
+	c.RRFK = k
+	return c
+}
+
+// WithWeighted fuses the results with the weighted sum of the normalized scores.
+func (c *Composite) WithWeighted(weights ...float64) *Composite {
+	c.Fusion = FusionWeighted
+	c.Weights = weights
+	return c
+}
+
+// Validate checks the fusion configuration and validates the modes implementing milvus2.SearchModeValidator.
+func (c *Composite) Validate(ctx context.Context, conf *milvus2.RetrieverConfig, collection *entity.Collection) error {
+	if len(c.Modes) < 2 {
+		return fmt.Errorf("composite search requires at least 2 Modes")
+	}
+	switch c.Fusion {
+	case "", FusionRRF:
+	case FusionWeighted:
+		if len(c.Weights) > 0 && len(c.Weights) != len(c.Modes) {
+			return fmt.Errorf("composite search has %d Weights for %d Modes", len(c.Weights), len(c.Modes))
+		}
+	default:
+		return fmt.Errorf("unsupported fusion: %s", c.Fusion)
+	}
+	for i, mode := range c.Modes {
+		if mode == nil {
+			return fmt.Errorf("Mode %d is nil", i)
+		}
+		if v, ok := mode.(milvus2.SearchModeValidator); ok {
+			if err := v.Validate(ctx, conf, collection); err != nil {
+				return fmt.Errorf("Mode %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Retrieve runs the modes concurrently and fuses their results, failing if any of them fails.
+func (c *Composite) Retrieve(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	results := make([][]*schema.Document, len(c.Modes))
+	errs := make([]error, len(c.Modes))
+
+	var wg sync.WaitGroup
+	for i, mode := range c.Modes {
+		wg.Add(1)
+		go func(i int, mode milvus2.SearchMode) {
+			defer wg.Done()
+			defer func() {
+				if p := recover(); p != nil {
+					errs[i] = fmt.Errorf("panic: %v", p)
+				}
+			}()
+			results[i], errs[i] = mode.Retrieve(ctx, client, conf, query, opts...)
+		}(i, mode)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("composite mode %d failed: %w", i, err)
+		}
+	}
+
+	topK := conf.TopK
+	if c.TopK > 0 {
+		topK = c.TopK
+	}
+	co := retriever.GetCommonOptions(&retriever.Options{TopK: &topK}, opts...)
+	if co.TopK != nil {
+		topK = *co.TopK
+	}
+
+	return c.fuse(results, topK), nil
+}
+
+// fuse merges the results by document ID, keeping the first occurrence of a document,
+// and returns up to topK documents by descending fused score.
+func (c *Composite) fuse(results [][]*schema.Document, topK int) []*schema.Document {
+	scores := make(map[string]float64)
+	docs := make(map[string]*schema.Document)
+	var order []string
+
+	for i, result := range results {
+		var normalized []float64
+		weight := 1.0
+		if c.Fusion == FusionWeighted {
+			normalized = normalizeScores(result)
+			if len(c.Weights) > 0 {
+				weight = c.Weights[i]
+			}
+		}
+
+		for rank, doc := range result {
+			if _, ok := docs[doc.ID]; !ok {
+				docs[doc.ID] = doc
+				order = append(order, doc.ID)
+			}
+			if c.Fusion == FusionWeighted {
+				scores[doc.ID] += weight * normalized[rank]
+			} else {
+				k := c.RRFK
+				if k <= 0 {
+					k = 60
+				}
+				scores[doc.ID] += 1 / float64(k+rank+1)
+			}
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+	if topK > 0 && len(order) > topK {
+		order = order[:topK]
+	}
+
+	fused := make([]*schema.Document, 0, len(order))
+	for _, id := range order {
+		fused = append(fused, docs[id].WithScore(scores[id]))
+	}
+	return fused
+}
+
+// normalizeScores min-max normalizes the scores of the result to [0, 1], 1 for the best document.
+// The results are ordered best first, so a result with ascending scores, e.g. by L2 distance, is inverted.
+func normalizeScores(result []*schema.Document) []float64 {
+	normalized := make([]float64, len(result))
+	if len(result) == 0 {
+		return normalized
+	}
+	best, worst := result[0].Score(), result[len(result)-1].Score()
+	for i, doc := range result {
+		if best == worst {
+			normalized[i] = 1
+			continue
+		}
+		normalized[i] = (doc.Score() - worst) / (best - worst)
+	}
+	return normalized
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+)
+
+type fixedSearchMode struct {
+	docs []*schema.Document
+	err  error
+}
+
+func (m *fixedSearchMode) Retrieve(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	docs := make([]*schema.Document, 0, len(m.docs))
+	for _, doc := range m.docs {
+		docs = append(docs, (&schema.Document{ID: doc.ID}).WithScore(doc.Score()))
+	}
+	return docs, m.err
+}
+
+func scoredDocs(pairs ...any) []*schema.Document {
+	var docs []*schema.Document
+	for i := 0; i < len(pairs); i += 2 {
+		docs = append(docs, (&schema.Document{ID: pairs[i].(string)}).WithScore(pairs[i+1].(float64)))
+	}
+	return docs
+}
+
+func docIDs(docs []*schema.Document) []string {
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids
+}
+
+func TestComposite_Retrieve(t *testing.T) {
+	convey.Convey("test Composite.Retrieve", t, func() {
+		ctx := context.Background()
+		conf := &milvus2.RetrieverConfig{Collection: "test_collection", TopK: 10}
+
+		// dense results by L2 distance, ascending; sparse results by BM25 score, descending
+		dense := &fixedSearchMode{docs: scoredDocs("a", 0.1, "b", 0.5, "c", 0.9)}
+		sparse := &fixedSearchMode{docs: scoredDocs("c", 12.0, "d", 8.0, "b", 2.0)}
+
+		convey.Convey("test rrf fusion", func() {
+			docs, err := NewComposite(dense, sparse).Retrieve(ctx, nil, conf, "query")
+			convey.So(err, convey.ShouldBeNil)
+			// b and c appear in both results, c ranks higher in total
+			convey.So(docIDs(docs), convey.ShouldResemble, []string{"c", "b", "a", "d"})
+			convey.So(docs[0].Score(), convey.ShouldAlmostEqual, 1.0/63+1.0/61)
+		})
+
+		convey.Convey("test weighted fusion", func() {
+			docs, err := NewComposite(dense, sparse).WithWeighted(0.8, 0.2).Retrieve(ctx, nil, conf, "query")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docIDs(docs), convey.ShouldResemble, []string{"a", "b", "c", "d"})
+			convey.So(docs[0].Score(), convey.ShouldAlmostEqual, 0.8)
+			convey.So(docs[1].Score(), convey.ShouldAlmostEqual, 0.8*0.5)
+		})
+
+		convey.Convey("test top k", func() {
+			docs, err := NewComposite(dense, sparse).Retrieve(ctx, nil, conf, "query", retriever.WithTopK(2))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docIDs(docs), convey.ShouldResemble, []string{"c", "b"})
+
+			composite := NewComposite(dense, sparse)
+			composite.TopK = 1
+			docs, err = composite.Retrieve(ctx, nil, conf, "query")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(docs, convey.ShouldHaveLength, 1)
+		})
+
+		convey.Convey("test mode error", func() {
+			_, err := NewComposite(dense, &fixedSearchMode{err: fmt.Errorf("search failed")}).Retrieve(ctx, nil, conf, "query")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "composite mode 1 failed")
+		})
+	})
+}
+
+func TestComposite_Validate(t *testing.T) {
+	convey.Convey("test Composite.Validate", t, func() {
+		ctx := context.Background()
+		conf := &milvus2.RetrieverConfig{Collection: "test_collection"}
+		mode := &fixedSearchMode{}
+
+		convey.So(NewComposite(mode, mode).Validate(ctx, conf, nil), convey.ShouldBeNil)
+		convey.So(NewComposite(mode).Validate(ctx, conf, nil), convey.ShouldNotBeNil)
+		convey.So(NewComposite(mode, nil).Validate(ctx, conf, nil), convey.ShouldNotBeNil)
+		convey.So(NewComposite(mode, mode).WithWeighted(1).Validate(ctx, conf, nil), convey.ShouldNotBeNil)
+		convey.So(NewComposite(mode, mode).WithWeighted(0.3, 0.7).Validate(ctx, conf, nil), convey.ShouldBeNil)
+		convey.So((&Composite{Modes: []milvus2.SearchMode{mode, mode}, Fusion: "max"}).Validate(ctx, conf, nil), convey.ShouldNotBeNil)
+	})
+}
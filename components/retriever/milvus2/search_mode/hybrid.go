@@ -20,18 +20,22 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/schema"
 	"github.com/milvus-io/milvus/client/v2/entity"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
 
 	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+	"github.com/cloudwego/eino-ext/libs/configcheck"
 )
 
 // Hybrid implements hybrid search with reranking.
 // It allows defining multiple ANN requests that share the same query vector
 // (e.g., searching against different vector fields with different parameters),
 // and then fusing the results using a Reranker.
+// Dense sub-requests can also use their own query vectors, given by milvus2.WithQueryVector,
+// or computed by their own Embedding, which enables cross-modal retrieval over multi-vector collections.
 type Hybrid struct {
 	// SubRequests defines the configuration for each sub-search.
 	SubRequests []*SubRequest
@@ -43,6 +47,12 @@ type Hybrid struct {
 	// TopK overrides the final number of results to return.
 	// If 0, uses RetrieverConfig.TopK.
 	TopK int
+
+	// AllowSingle allows a hybrid search with a single SubRequest, e.g. to rerank its results with the Reranker.
+	// As fusing a single result list with RRFReranker or WeightedReranker keeps its order,
+	// a single dense SubRequest with them is sent as a regular search, which is cheaper,
+	// so the scores of the documents are the ones of the SubRequest metric instead of the fused ones.
+	AllowSingle bool
 }
 
 // SubRequest defines a single ANN search request within a hybrid search.
@@ -60,11 +70,18 @@ type SubRequest struct {
 	TopK int
 
 	// SearchParams are extra parameters (e.g. "nprobe", "ef").
+	// They override RetrieverConfig.DefaultSearchParams.
 	SearchParams map[string]string
 
 	// VectorType specifies the type of vector field (e.g., DenseVector, SparseVector).
+	// It must match the type of the field in the collection, which is checked by NewRetriever.
 	// Default: DenseVector
 	VectorType milvus2.VectorType
+
+	// Embedding embeds the query of this dense sub-request, e.g. the text encoder of an image-text model
+	// for an image vector field. The query is the text given by milvus2.WithFieldQuery, or the query of Retrieve.
+	// Default: RetrieverConfig.Embedding, whose query vector is shared by the sub-requests.
+	Embedding embedding.Embedder
 }
 
 // NewHybrid creates a new Hybrid search mode with the given reranker and sub-requests.
@@ -75,6 +92,68 @@ func NewHybrid(reranker milvusclient.Reranker, subRequests ...*SubRequest) *Hybr
 	}
 }
 
+// WithAllowSingle sets whether a hybrid search with a single SubRequest is allowed, see AllowSingle.
+func (h *Hybrid) WithAllowSingle(allow bool) *Hybrid {
+	h.AllowSingle = allow
+	return h
+}
+
+// Validate checks the sub-requests against the collection schema:
+// every field must exist with the type of its VectorType, dense sub-requests need an embedder,
+// and sparse sub-requests need a function (e.g. BM25) generating the field from the query text.
+// It returns a *configcheck.Report listing the problems of all the sub-requests.
+func (h *Hybrid) Validate(ctx context.Context, conf *milvus2.RetrieverConfig, collection *entity.Collection) error {
+	r := configcheck.New("search_mode.Hybrid")
+	r.AddError("SubRequests", h.checkSubRequestCount())
+	r.Checkf(h.Reranker != nil, "Reranker", "hybrid search requires a Reranker")
+
+	fields := make(map[string]*entity.Field)
+	functionOutputs := make(map[string]bool)
+	if collection != nil && collection.Schema != nil {
+		for _, f := range collection.Schema.Fields {
+			fields[f.Name] = f
+		}
+		for _, fn := range collection.Schema.Functions {
+			for _, output := range fn.OutputFieldNames {
+				functionOutputs[output] = true
+			}
+		}
+	}
+
+	for i, req := range h.SubRequests {
+		name := fmt.Sprintf("SubRequests[%d]", i)
+		if req == nil {
+			r.Addf(name, "is nil")
+			continue
+		}
+		field := req.vectorField(conf)
+
+		f, ok := fields[field]
+		if !ok {
+			r.Addf(name, "field %q not found in collection %q", field, conf.Collection)
+			continue
+		}
+
+		switch req.VectorType {
+		case milvus2.SparseVector:
+			r.Checkf(f.DataType == entity.FieldTypeSparseVector, name+".VectorType",
+				"VectorType is SparseVector but field %q is %s", field, f.DataType.Name())
+			r.Checkf(functionOutputs[field], name, "sparse field %q is not the output of a collection function (e.g. BM25), "+
+				"so it cannot be searched with the query text", field)
+		case "", milvus2.DenseVector:
+			r.Checkf(f.DataType == entity.FieldTypeFloatVector, name+".VectorType",
+				"VectorType is DenseVector but field %q is %s; set VectorType to SparseVector for sparse fields",
+				field, f.DataType.Name())
+			r.Checkf(req.Embedding != nil || conf.Embedding != nil, name+".Embedding",
+				"dense field %q requires SubRequest.Embedding or RetrieverConfig.Embedding", field)
+		default:
+			r.Addf(name+".VectorType", "unknown VectorType %q, expected %q or %q",
+				req.VectorType, milvus2.DenseVector, milvus2.SparseVector)
+		}
+	}
+	return r.Err()
+}
+
 // BuildSearchOption returns an error because Hybrid search mode requires BuildHybridSearchOption.
 func (h *Hybrid) BuildSearchOption(ctx context.Context, conf *milvus2.RetrieverConfig, queryVector []float32, opts ...retriever.Option) (milvusclient.SearchOption, error) {
 	return nil, fmt.Errorf("Hybrid search mode requires BuildHybridSearchOption")
@@ -82,23 +161,48 @@ func (h *Hybrid) BuildSearchOption(ctx context.Context, conf *milvus2.RetrieverC
 
 // Retrieve performs the hybrid search operation.
 func (h *Hybrid) Retrieve(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
-	if conf.Embedding == nil {
-		return nil, fmt.Errorf("embedding is required for hybrid search")
+	var (
+		queryVector []float32
+		err         error
+	)
+	io := retriever.GetImplSpecificOptions(&milvus2.ImplOptions{}, opts...)
+	if h.needsSharedQueryVector(conf, io) {
+		if conf.Embedding == nil {
+			return nil, fmt.Errorf("embedding is required for hybrid search")
+		}
+		queryVector, err = EmbedQuery(ctx, conf.Embedding, query)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	queryVector, err := EmbedQuery(ctx, conf.Embedding, query)
-	if err != nil {
-		return nil, err
-	}
+	var result []milvusclient.ResultSet
+	if h.searchesSingleDirectly() {
+		searchOpt, err := h.buildSingleSearchOption(ctx, conf, queryVector, query, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build search option: %w", err)
+		}
 
-	searchOpt, err := h.BuildHybridSearchOption(ctx, conf, queryVector, query, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build hybrid search option: %w", err)
-	}
+		result, err = callWithRetry(ctx, func(ctx context.Context) ([]milvusclient.ResultSet, error) {
+			return client.Search(ctx, searchOpt)
+		}, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search: %w", err)
+		}
+		reportSearchNQ(ctx, searchOpt)
+	} else {
+		searchOpt, err := h.BuildHybridSearchOption(ctx, conf, queryVector, query, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build hybrid search option: %w", err)
+		}
 
-	result, err := client.HybridSearch(ctx, searchOpt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to hybrid search: %w", err)
+		result, err = callWithRetry(ctx, func(ctx context.Context) ([]milvusclient.ResultSet, error) {
+			return client.HybridSearch(ctx, searchOpt)
+		}, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hybrid search: %w", err)
+		}
+		reportHybridSearchNQ(ctx, searchOpt)
 	}
 
 	if len(result) == 0 {
@@ -111,9 +215,8 @@ func (h *Hybrid) Retrieve(ctx context.Context, client *milvusclient.Client, conf
 // BuildHybridSearchOption creates a HybridSearchOption for multi-vector search with reranking.
 // It is internal to the hybrid implementation now/helper but kept as method for cleaner code.
 func (h *Hybrid) BuildHybridSearchOption(ctx context.Context, conf *milvus2.RetrieverConfig, queryVector []float32, query string, opts ...retriever.Option) (milvusclient.HybridSearchOption, error) {
-	// Validate SubRequests - hybrid requires at least 2 SubRequests for meaningful fusion
-	if len(h.SubRequests) < 2 {
-		return nil, fmt.Errorf("hybrid search requires at least 2 SubRequests; use Approximate or Sparse search mode for single-vector search")
+	if err := h.checkSubRequestCount(); err != nil {
+		return nil, err
 	}
 
 	io := retriever.GetImplSpecificOptions(&milvus2.ImplOptions{}, opts...)
@@ -121,25 +224,15 @@ func (h *Hybrid) BuildHybridSearchOption(ctx context.Context, conf *milvus2.Retr
 		TopK: &conf.TopK,
 	}, opts...)
 
-	finalTopK := conf.TopK
-	if h.TopK > 0 {
-		finalTopK = h.TopK
-	}
-	if co.TopK != nil {
-		finalTopK = *co.TopK
+	finalTopK := h.finalTopK(conf, co)
+
+	if err := h.checkFieldQueries(conf, io); err != nil {
+		return nil, err
 	}
 
 	annRequests := make([]*milvusclient.AnnRequest, 0, len(h.SubRequests))
 	for _, req := range h.SubRequests {
-		// Determine vector field
-		field := req.VectorField
-		if field == "" {
-			if req.VectorType == milvus2.SparseVector {
-				field = conf.SparseVectorField
-			} else {
-				field = conf.VectorField
-			}
-		}
+		field := req.vectorField(conf)
 
 		// Determine Limit
 		limit := req.TopK
@@ -154,14 +247,15 @@ func (h *Hybrid) BuildHybridSearchOption(ctx context.Context, conf *milvus2.Retr
 			annReq = milvusclient.NewAnnRequest(field, limit, entity.Text(query))
 		} else {
 			// Dense vector: require query vector
-			if len(queryVector) == 0 {
-				return nil, fmt.Errorf("dense vector SubRequest requires embedding, but query vector is empty")
+			vector, err := req.queryVector(ctx, conf, field, queryVector, query, io)
+			if err != nil {
+				return nil, err
 			}
-			annReq = milvusclient.NewAnnRequest(field, limit, entity.FloatVector(queryVector))
+			annReq = milvusclient.NewAnnRequest(field, limit, entity.FloatVector(vector))
 		}
 
 		// Apply search params
-		for k, v := range req.SearchParams {
+		for k, v := range mergeSearchParams(conf, req.SearchParams) {
 			annReq.WithSearchParam(k, v)
 		}
 
@@ -199,3 +293,191 @@ func (h *Hybrid) BuildHybridSearchOption(ctx context.Context, conf *milvus2.Retr
 
 	return hybridOpt, nil
 }
+
+// checkSubRequestCount ensures there are at least 2 SubRequests to fuse, or 1 if AllowSingle is set.
+func (h *Hybrid) checkSubRequestCount() error {
+	if h.AllowSingle {
+		if len(h.SubRequests) == 0 {
+			return fmt.Errorf("hybrid search requires at least 1 SubRequest")
+		}
+		return nil
+	}
+	if len(h.SubRequests) < 2 {
+		return fmt.Errorf("hybrid search requires at least 2 SubRequests; use Approximate or Sparse search mode " +
+			"for single-vector search, or set AllowSingle to search with a single SubRequest")
+	}
+	return nil
+}
+
+// finalTopK returns the number of results of the search.
+func (h *Hybrid) finalTopK(conf *milvus2.RetrieverConfig, co *retriever.Options) int {
+	finalTopK := conf.TopK
+	if h.TopK > 0 {
+		finalTopK = h.TopK
+	}
+	if co.TopK != nil {
+		finalTopK = *co.TopK
+	}
+	return finalTopK
+}
+
+// searchesSingleDirectly reports whether the single dense SubRequest allowed by AllowSingle is sent as a regular search,
+// as its reranker only fuses the result lists and does not change the order of a single one.
+func (h *Hybrid) searchesSingleDirectly() bool {
+	if !h.AllowSingle || len(h.SubRequests) != 1 || h.SubRequests[0] == nil ||
+		h.SubRequests[0].VectorType == milvus2.SparseVector {
+		return false
+	}
+	// the reranker types of milvusclient are unexported, tell them apart by their strategy param
+	for _, kv := range h.Reranker.GetParams() {
+		if kv.GetKey() == "strategy" {
+			return kv.GetValue() == "rrf" || kv.GetValue() == "weighted"
+		}
+	}
+	return false
+}
+
+// buildSingleSearchOption creates a SearchOption for the single dense SubRequest, see searchesSingleDirectly.
+func (h *Hybrid) buildSingleSearchOption(ctx context.Context, conf *milvus2.RetrieverConfig, queryVector []float32, query string, opts ...retriever.Option) (milvusclient.SearchOption, error) {
+	io := retriever.GetImplSpecificOptions(&milvus2.ImplOptions{}, opts...)
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK: &conf.TopK,
+	}, opts...)
+
+	if err := h.checkFieldQueries(conf, io); err != nil {
+		return nil, err
+	}
+
+	req := h.SubRequests[0]
+	field := req.vectorField(conf)
+	vector, err := req.queryVector(ctx, conf, field, queryVector, query, io)
+	if err != nil {
+		return nil, err
+	}
+
+	searchOpt := milvusclient.NewSearchOption(conf.Collection, h.finalTopK(conf, co), []entity.Vector{entity.FloatVector(vector)}).
+		WithANNSField(field).
+		WithOutputFields(conf.OutputFields...)
+
+	for k, v := range mergeSearchParams(conf, req.SearchParams) {
+		searchOpt.WithSearchParam(k, v)
+	}
+
+	if req.MetricType != "" {
+		searchOpt.WithSearchParam("metric_type", string(req.MetricType))
+	}
+
+	if len(conf.Partitions) > 0 {
+		searchOpt = searchOpt.WithPartitions(conf.Partitions...)
+	}
+
+	if io.Filter != "" {
+		searchOpt = searchOpt.WithFilter(io.Filter)
+	}
+
+	if io.Grouping != nil {
+		searchOpt = searchOpt.WithGroupByField(io.Grouping.GroupByField).
+			WithGroupSize(io.Grouping.GroupSize)
+		if io.Grouping.StrictGroupSize {
+			searchOpt = searchOpt.WithStrictGroupSize(true)
+		}
+	}
+
+	if conf.ConsistencyLevel != milvus2.ConsistencyLevelDefault {
+		searchOpt = searchOpt.WithConsistencyLevel(conf.ConsistencyLevel.ToEntity())
+	}
+
+	return searchOpt, nil
+}
+
+// vectorField returns the vector field searched by the sub-request.
+func (r *SubRequest) vectorField(conf *milvus2.RetrieverConfig) string {
+	if r.VectorField != "" {
+		return r.VectorField
+	}
+	if r.VectorType == milvus2.SparseVector {
+		return conf.SparseVectorField
+	}
+	return conf.VectorField
+}
+
+// usesSharedQueryVector reports whether the dense sub-request searches with the embedding of
+// the query of Retrieve by RetrieverConfig.Embedding.
+func (r *SubRequest) usesSharedQueryVector(field string, io *milvus2.ImplOptions) bool {
+	if r.VectorType == milvus2.SparseVector || r.Embedding != nil {
+		return false
+	}
+	if _, ok := io.QueryVectors[field]; ok {
+		return false
+	}
+	_, ok := io.FieldQueries[field]
+	return !ok
+}
+
+// queryVector returns the query vector of the dense sub-request.
+func (r *SubRequest) queryVector(ctx context.Context, conf *milvus2.RetrieverConfig, field string,
+	sharedVector []float32, query string, io *milvus2.ImplOptions) ([]float32, error) {
+
+	if vector, ok := io.QueryVectors[field]; ok {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("query vector of field %s is empty", field)
+		}
+		return toFloat32(vector), nil
+	}
+
+	if r.usesSharedQueryVector(field, io) {
+		if len(sharedVector) == 0 {
+			return nil, fmt.Errorf("dense vector SubRequest requires embedding, but query vector is empty")
+		}
+		return sharedVector, nil
+	}
+
+	emb := r.Embedding
+	if emb == nil {
+		emb = conf.Embedding
+	}
+	if fieldQuery, ok := io.FieldQueries[field]; ok {
+		query = fieldQuery
+	}
+	vector, err := EmbedQuery(ctx, emb, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query of field %s: %w", field, err)
+	}
+	return vector, nil
+}
+
+// needsSharedQueryVector reports whether any dense sub-request searches with the shared query vector.
+func (h *Hybrid) needsSharedQueryVector(conf *milvus2.RetrieverConfig, io *milvus2.ImplOptions) bool {
+	for _, req := range h.SubRequests {
+		if req.usesSharedQueryVector(req.vectorField(conf), io) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFieldQueries ensures the per-field query vectors and texts target dense sub-requests,
+// so that a misspelled field does not silently fall back to the shared query vector.
+func (h *Hybrid) checkFieldQueries(conf *milvus2.RetrieverConfig, io *milvus2.ImplOptions) error {
+	if len(io.QueryVectors) == 0 && len(io.FieldQueries) == 0 {
+		return nil
+	}
+
+	denseFields := make(map[string]bool, len(h.SubRequests))
+	for _, req := range h.SubRequests {
+		if req.VectorType != milvus2.SparseVector {
+			denseFields[req.vectorField(conf)] = true
+		}
+	}
+	for field := range io.QueryVectors {
+		if !denseFields[field] {
+			return fmt.Errorf("query vector is given for field %s, which is not searched by any dense SubRequest", field)
+		}
+	}
+	for field := range io.FieldQueries {
+		if !denseFields[field] {
+			return fmt.Errorf("field query is given for field %s, which is not searched by any dense SubRequest", field)
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package search_mode
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/smartystreets/goconvey/convey"
 
 	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+	"github.com/cloudwego/eino-ext/libs/configcheck"
 )
 
 func TestNewHybrid(t *testing.T) {
@@ -293,6 +295,7 @@ func TestHybrid_BuildHybridSearchOption(t *testing.T) {
 func TestHybrid_ImplementsSearchMode(t *testing.T) {
 	convey.Convey("test Hybrid implements SearchMode", t, func() {
 		var _ milvus2.SearchMode = (*Hybrid)(nil)
+		var _ milvus2.SearchModeValidator = (*Hybrid)(nil)
 	})
 }
 
@@ -372,6 +375,39 @@ func TestHybridSearchTopK(t *testing.T) {
 	})
 }
 
+func TestHybrid_DefaultSearchParams(t *testing.T) {
+	PatchConvey("test Hybrid with RetrieverConfig.DefaultSearchParams", t, func() {
+		ctx := context.Background()
+		config := &milvus2.RetrieverConfig{
+			Collection:          "test_collection",
+			VectorField:         "vector",
+			TopK:                10,
+			DefaultSearchParams: map[string]string{"ef": "64", "nprobe": "16"},
+		}
+		hybrid := NewHybrid(milvusclient.NewRRFReranker(),
+			&SubRequest{VectorField: "vector", MetricType: milvus2.L2, SearchParams: map[string]string{"ef": "128"}},
+			&SubRequest{VectorField: "vector2", MetricType: milvus2.IP},
+		)
+
+		var captured []map[string]string
+		Mock(milvusclient.NewAnnRequest).To(func(fieldName string, limit int, vectors ...entity.Vector) *milvusclient.AnnRequest {
+			captured = append(captured, map[string]string{})
+			return &milvusclient.AnnRequest{}
+		}).Build()
+		Mock((*milvusclient.AnnRequest).WithSearchParam).To(func(r *milvusclient.AnnRequest, key string, value string) *milvusclient.AnnRequest {
+			captured[len(captured)-1][key] = value
+			return r
+		}).Build()
+
+		_, err := hybrid.BuildHybridSearchOption(ctx, config, make([]float32, 128), "query")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(captured, convey.ShouldResemble, []map[string]string{
+			{"ef": "128", "nprobe": "16", "metric_type": "L2"},
+			{"ef": "64", "nprobe": "16", "metric_type": "IP"},
+		})
+	})
+}
+
 func TestHybrid_Retrieve(t *testing.T) {
 	PatchConvey("test Hybrid.Retrieve", t, func() {
 		ctx := context.Background()
@@ -412,10 +448,14 @@ func TestHybrid_Retrieve(t *testing.T) {
 				return []*schema.Document{{ID: "1"}}, nil
 			}
 			config.DocumentConverter = mockConverter
+			var nq int64
+			Mock(milvus2.ReportNQ).To(func(_ context.Context, n int64) { nq += n }).Build()
 
 			docs, err := hybrid.Retrieve(ctx, mockClient, config, "query")
 			convey.So(err, convey.ShouldBeNil)
 			convey.So(len(docs), convey.ShouldEqual, 1)
+			// one query vector per sub-request
+			convey.So(nq, convey.ShouldEqual, len(hybrid.SubRequests))
 		})
 
 		PatchConvey("embedding error", func() {
@@ -425,6 +465,30 @@ func TestHybrid_Retrieve(t *testing.T) {
 			convey.So(docs, convey.ShouldBeNil)
 		})
 
+		PatchConvey("single sub-request", func() {
+			config.DocumentConverter = func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
+				return []*schema.Document{{ID: "1"}}, nil
+			}
+			searchMocker := Mock(GetMethod(mockClient, "Search")).Return([]milvusclient.ResultSet{{ResultCount: 1}}, nil).Build()
+			hybridMocker := Mock(GetMethod(mockClient, "HybridSearch")).Return([]milvusclient.ResultSet{{ResultCount: 1}}, nil).Build()
+
+			_, err := NewHybrid(reranker, subReq1).Retrieve(ctx, mockClient, config, "query")
+			convey.So(err, convey.ShouldNotBeNil)
+
+			// fusing a single result list with RRF keeps its order, so it is sent as a regular search
+			docs, err := NewHybrid(reranker, subReq1).WithAllowSingle(true).Retrieve(ctx, mockClient, config, "query")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 1)
+			convey.So(searchMocker.Times(), convey.ShouldEqual, 1)
+			convey.So(hybridMocker.Times(), convey.ShouldEqual, 0)
+
+			sparse := &SubRequest{VectorType: milvus2.SparseVector, MetricType: milvus2.BM25}
+			docs, err = NewHybrid(reranker, sparse).WithAllowSingle(true).Retrieve(ctx, mockClient, config, "query")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 1)
+			convey.So(hybridMocker.Times(), convey.ShouldEqual, 1)
+		})
+
 		PatchConvey("search error", func() {
 			mockEmb.err = nil
 			Mock(GetMethod(mockClient, "HybridSearch")).Return(nil, fmt.Errorf("search error")).Build()
@@ -438,12 +502,175 @@ func TestHybrid_Retrieve(t *testing.T) {
 
 // mockHybridEmbedding implements embedding.Embedder for testing
 type mockHybridEmbedding struct {
-	err error
+	err   error
+	texts []string
 }
 
 func (m *mockHybridEmbedding) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	m.texts = append(m.texts, texts...)
 	return [][]float64{make([]float64, 128)}, nil
 }
+
+func TestHybrid_MultiVectorQuery(t *testing.T) {
+	PatchConvey("test Hybrid multi-vector query", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		textEmb := &mockHybridEmbedding{}
+		imageEmb := &mockHybridEmbedding{}
+
+		config := &milvus2.RetrieverConfig{
+			Collection:  "test_collection",
+			VectorField: "text_vector",
+			TopK:        10,
+			DocumentConverter: func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
+				return []*schema.Document{{ID: "1"}}, nil
+			},
+		}
+		hybrid := NewHybrid(milvusclient.NewRRFReranker(),
+			&SubRequest{MetricType: milvus2.COSINE},
+			&SubRequest{VectorField: "image_vector", MetricType: milvus2.COSINE},
+		)
+
+		var capturedDims []int
+		Mock(milvusclient.NewAnnRequest).To(func(fieldName string, limit int, vectors ...entity.Vector) *milvusclient.AnnRequest {
+			capturedDims = append(capturedDims, vectors[0].Dim())
+			return &milvusclient.AnnRequest{}
+		}).Build()
+		Mock((*milvusclient.AnnRequest).WithSearchParam).To(func(r *milvusclient.AnnRequest, key string, value string) *milvusclient.AnnRequest {
+			return r
+		}).Build()
+		Mock(GetMethod(mockClient, "HybridSearch")).Return([]milvusclient.ResultSet{{ResultCount: 1}}, nil).Build()
+
+		PatchConvey("query vector option", func() {
+			config.Embedding = textEmb
+			docs, err := hybrid.Retrieve(ctx, mockClient, config, "red shoes",
+				milvus2.WithQueryVector("image_vector", []float64{0.1, 0.2, 0.3}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 1)
+			convey.So(capturedDims, convey.ShouldResemble, []int{128, 3})
+			convey.So(textEmb.texts, convey.ShouldResemble, []string{"red shoes"})
+		})
+
+		PatchConvey("sub-request embedding with field query", func() {
+			config.Embedding = textEmb
+			hybrid.SubRequests[1].Embedding = imageEmb
+			_, err := hybrid.Retrieve(ctx, mockClient, config, "red shoes",
+				milvus2.WithFieldQuery("image_vector", "a photo of red shoes"))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(textEmb.texts, convey.ShouldResemble, []string{"red shoes"})
+			convey.So(imageEmb.texts, convey.ShouldResemble, []string{"a photo of red shoes"})
+		})
+
+		PatchConvey("no shared embedding needed", func() {
+			_, err := hybrid.Retrieve(ctx, mockClient, config, "red shoes",
+				milvus2.WithQueryVector("text_vector", []float64{0.1}),
+				milvus2.WithQueryVector("image_vector", []float64{0.2}))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(capturedDims, convey.ShouldResemble, []int{1, 1})
+		})
+
+		PatchConvey("unknown field", func() {
+			config.Embedding = textEmb
+			_, err := hybrid.Retrieve(ctx, mockClient, config, "red shoes",
+				milvus2.WithQueryVector("img_vector", []float64{0.1}))
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "field img_vector, which is not searched by any dense SubRequest")
+		})
+
+		PatchConvey("sub-request embedding error", func() {
+			config.Embedding = textEmb
+			hybrid.SubRequests[1].Embedding = &mockHybridEmbedding{err: fmt.Errorf("embed error")}
+			_, err := hybrid.Retrieve(ctx, mockClient, config, "red shoes")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "failed to embed query of field image_vector")
+		})
+	})
+}
+
+func TestHybrid_Validate(t *testing.T) {
+	convey.Convey("test Hybrid.Validate", t, func() {
+		ctx := context.Background()
+		collection := &entity.Collection{
+			Schema: entity.NewSchema().
+				WithField(entity.NewField().WithName("id").WithDataType(entity.FieldTypeVarChar).WithIsPrimaryKey(true)).
+				WithField(entity.NewField().WithName("content").WithDataType(entity.FieldTypeVarChar)).
+				WithField(entity.NewField().WithName("vector").WithDataType(entity.FieldTypeFloatVector).WithDim(128)).
+				WithField(entity.NewField().WithName("sparse_vector").WithDataType(entity.FieldTypeSparseVector)).
+				WithField(entity.NewField().WithName("precomputed_sparse").WithDataType(entity.FieldTypeSparseVector)).
+				WithFunction(entity.NewFunction().WithName("bm25").WithType(entity.FunctionTypeBM25).
+					WithInputFields("content").WithOutputFields("sparse_vector")),
+		}
+		config := &milvus2.RetrieverConfig{
+			Collection:        "test_collection",
+			VectorField:       "vector",
+			SparseVectorField: "sparse_vector",
+			Embedding:         &mockHybridEmbedding{},
+		}
+		newHybrid := func(subRequests ...*SubRequest) *Hybrid {
+			return NewHybrid(milvusclient.NewRRFReranker(), subRequests...)
+		}
+
+		convey.Convey("test valid", func() {
+			h := newHybrid(&SubRequest{}, &SubRequest{VectorType: milvus2.SparseVector})
+			convey.So(h.Validate(ctx, config, collection), convey.ShouldBeNil)
+		})
+
+		convey.Convey("test sub-request count and reranker", func() {
+			err := newHybrid(&SubRequest{}).Validate(ctx, config, collection)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "at least 2 SubRequests")
+
+			convey.So(newHybrid(&SubRequest{}).WithAllowSingle(true).Validate(ctx, config, collection), convey.ShouldBeNil)
+			err = newHybrid().WithAllowSingle(true).Validate(ctx, config, collection)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "at least 1 SubRequest")
+
+			err = NewHybrid(nil, &SubRequest{}, &SubRequest{VectorType: milvus2.SparseVector}).Validate(ctx, config, collection)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "requires a Reranker")
+		})
+
+		convey.Convey("test missing field", func() {
+			err := newHybrid(&SubRequest{}, &SubRequest{VectorField: "image_vector"}).Validate(ctx, config, collection)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `SubRequests[1]: field "image_vector" not found in collection "test_collection"`)
+		})
+
+		convey.Convey("test type mismatch", func() {
+			err := newHybrid(&SubRequest{}, &SubRequest{VectorField: "sparse_vector"}).Validate(ctx, config, collection)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `VectorType is DenseVector but field "sparse_vector"`)
+
+			err = newHybrid(&SubRequest{}, &SubRequest{VectorField: "vector", VectorType: milvus2.SparseVector}).Validate(ctx, config, collection)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `VectorType is SparseVector but field "vector"`)
+
+			err = newHybrid(&SubRequest{}, &SubRequest{VectorType: "binary"}).Validate(ctx, config, collection)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `unknown VectorType "binary"`)
+		})
+
+		convey.Convey("test all problems are reported", func() {
+			h := NewHybrid(nil, &SubRequest{VectorField: "image_vector"}, nil, &SubRequest{VectorType: "binary"})
+			err := h.Validate(ctx, config, collection)
+			var report *configcheck.Report
+			convey.So(errors.As(err, &report), convey.ShouldBeTrue)
+			convey.So(report.Issues, convey.ShouldHaveLength, 4)
+			convey.So(report.Issues[0].Field, convey.ShouldEqual, "Reranker")
+			convey.So(report.Issues[3].Field, convey.ShouldEqual, "SubRequests[2].VectorType")
+		})
+
+		convey.Convey("test sparse field without function", func() {
+			h := newHybrid(&SubRequest{}, &SubRequest{VectorField: "precomputed_sparse", VectorType: milvus2.SparseVector})
+			err := h.Validate(ctx, config, collection)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `sparse field "precomputed_sparse" is not the output of a collection function`)
+		})
+
+		convey.Convey("test dense without embedding", func() {
+			noEmbConfig := *config
+			noEmbConfig.Embedding = nil
+			h := newHybrid(&SubRequest{}, &SubRequest{VectorType: milvus2.SparseVector})
+			err := h.Validate(ctx, &noEmbConfig, collection)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `dense field "vector" requires SubRequest.Embedding or RetrieverConfig.Embedding`)
+
+			h.SubRequests[0].Embedding = &mockHybridEmbedding{}
+			convey.So(h.Validate(ctx, &noEmbConfig, collection), convey.ShouldBeNil)
+		})
+	})
+}
@@ -42,6 +42,7 @@ type Iterator struct {
 	BatchSize int
 
 	// SearchParams contains extra search parameters (e.g., "nprobe", "ef").
+	// They override RetrieverConfig.DefaultSearchParams.
 	SearchParams map[string]string
 }
 
@@ -70,6 +71,11 @@ func (i *Iterator) BuildSearchOption(ctx context.Context, conf *milvus2.Retrieve
 	return nil, fmt.Errorf("Iterator search mode requires BuildSearchIteratorOption")
 }
 
+// GetMetricType returns the metric type of the iterator search.
+func (i *Iterator) GetMetricType() milvus2.MetricType {
+	return i.MetricType
+}
+
 // Retrieve performs the search iterator operation, fetching all results.
 func (i *Iterator) Retrieve(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
 	if conf.Embedding == nil {
@@ -86,7 +92,9 @@ func (i *Iterator) Retrieve(ctx context.Context, client *milvusclient.Client, co
 		return nil, fmt.Errorf("failed to build search iterator option: %w", err)
 	}
 
-	iterator, err := client.SearchIterator(ctx, iterOpt)
+	iterator, err := callWithRetry(ctx, func(ctx context.Context) (milvusclient.SearchIterator, error) {
+		return client.SearchIterator(ctx, iterOpt)
+	}, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create search iterator: %w", err)
 	}
@@ -100,6 +108,8 @@ func (i *Iterator) Retrieve(ctx context.Context, client *milvusclient.Client, co
 			}
 			return nil, fmt.Errorf("iterator next failed: %w", err)
 		}
+		// every batch is searched with the single query vector
+		milvus2.ReportNQ(ctx, 1)
 		if res.ResultCount == 0 {
 			break
 		}
@@ -134,7 +144,7 @@ func (i *Iterator) BuildSearchIteratorOption(ctx context.Context, conf *milvus2.
 	if i.MetricType != "" {
 		opt.WithSearchParam("metric_type", string(i.MetricType))
 	}
-	for k, v := range i.SearchParams {
+	for k, v := range mergeSearchParams(conf, i.SearchParams) {
 		opt.WithSearchParam(k, v)
 	}
 
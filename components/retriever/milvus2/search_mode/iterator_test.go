@@ -168,6 +168,7 @@ func TestIterator_BuildSearchIteratorOption(t *testing.T) {
 func TestIterator_ImplementsSearchMode(t *testing.T) {
 	convey.Convey("test Iterator implements SearchMode", t, func() {
 		var _ milvus2.SearchMode = (*Iterator)(nil)
+		var _ milvus2.MetricTypeReporter = (*Iterator)(nil)
 	})
 }
 
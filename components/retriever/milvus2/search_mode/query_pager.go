@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+)
+
+// defaultQueryBatchSize is the number of rows fetched per Query call when the batch size is not set.
+const defaultQueryBatchSize = 1000
+
+// lastPKParam is the name of the template parameter holding the cursor of queryPager.
+const lastPKParam = "eino_last_pk"
+
+// queryPager scans the rows matching a filter in batches with the Milvus Query API.
+// Milvus returns limited query results ordered by primary key, so every page after the first
+// one is queried with "<IDField> > <last primary key>" added to the filter.
+// Unlike offset paging, the cost of a page does not grow with the number of rows already scanned.
+type queryPager struct {
+	conf      *milvus2.RetrieverConfig
+	filter    string
+	batchSize int
+
+	lastPK any
+	done   bool
+}
+
+func newQueryPager(conf *milvus2.RetrieverConfig, query string, batchSize int, opts ...retriever.Option) *queryPager {
+	if batchSize <= 0 {
+		batchSize = defaultQueryBatchSize
+	}
+	io := retriever.GetImplSpecificOptions(&milvus2.ImplOptions{}, opts...)
+	return &queryPager{
+		conf:      conf,
+		filter:    combineFilter(query, io.Filter),
+		batchSize: batchSize,
+	}
+}
+
+// queryOption returns the QueryOption of the next page.
+func (p *queryPager) queryOption() milvusclient.QueryOption {
+	filter := p.filter
+	if p.lastPK != nil {
+		filter = combineFilter(filter, p.conf.IDField+" > {"+lastPKParam+"}")
+	}
+
+	opt := milvusclient.NewQueryOption(p.conf.Collection).
+		WithFilter(filter).
+		WithOutputFields(p.conf.OutputFields...).
+		WithLimit(p.batchSize)
+
+	if p.lastPK != nil {
+		opt = opt.WithTemplateParam(lastPKParam, p.lastPK)
+	}
+
+	if len(p.conf.Partitions) > 0 {
+		opt = opt.WithPartitions(p.conf.Partitions...)
+	}
+
+	if p.conf.ConsistencyLevel != milvus2.ConsistencyLevelDefault {
+		opt = opt.WithConsistencyLevel(p.conf.ConsistencyLevel.ToEntity())
+	}
+
+	return opt
+}
+
+// next returns the next page. An empty result set means all matching rows have been scanned.
+func (p *queryPager) next(ctx context.Context, client *milvusclient.Client) (milvusclient.ResultSet, error) {
+	if p.done {
+		return milvusclient.ResultSet{}, nil
+	}
+
+	res, err := client.Query(ctx, p.queryOption())
+	if err != nil {
+		return milvusclient.ResultSet{}, fmt.Errorf("failed to query: %w", err)
+	}
+	if res.ResultCount < p.batchSize {
+		p.done = true
+	}
+	if res.ResultCount == 0 {
+		return res, nil
+	}
+
+	pk := res.GetColumn(p.conf.IDField)
+	if pk == nil {
+		return milvusclient.ResultSet{}, fmt.Errorf("query result misses the primary key field %q", p.conf.IDField)
+	}
+	last, err := pk.Get(res.ResultCount - 1)
+	if err != nil {
+		return milvusclient.ResultSet{}, fmt.Errorf("failed to read the primary key: %w", err)
+	}
+	p.lastPK = last
+
+	return res, nil
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+)
+
+func TestQueryPager(t *testing.T) {
+	PatchConvey("test queryPager", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+		config := &milvus2.RetrieverConfig{
+			Collection: "test_collection",
+			IDField:    "pk",
+		}
+
+		PatchConvey("pages with the primary key cursor", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(Sequence(milvusclient.ResultSet{
+				ResultCount: 2,
+				Fields:      milvusclient.DataSet{column.NewColumnInt64("pk", []int64{3, 7})},
+			}, nil).Then(milvusclient.ResultSet{
+				ResultCount: 1,
+				Fields:      milvusclient.DataSet{column.NewColumnInt64("pk", []int64{9})},
+			}, nil)).Build()
+
+			pager := newQueryPager(config, "tag == 'a'", 2)
+			req, err := pager.queryOption().Request()
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(req.GetExpr(), convey.ShouldEqual, "tag == 'a'")
+
+			res, err := pager.next(ctx, mockClient)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(res.ResultCount, convey.ShouldEqual, 2)
+
+			req, err = pager.queryOption().Request()
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(req.GetExpr(), convey.ShouldEqual, "(tag == 'a') and (pk > {eino_last_pk})")
+			convey.So(req.GetExprTemplateValues()[lastPKParam].GetInt64Val(), convey.ShouldEqual, 7)
+
+			res, err = pager.next(ctx, mockClient)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(res.ResultCount, convey.ShouldEqual, 1)
+
+			// the last page is shorter than the batch size, no more query is needed
+			res, err = pager.next(ctx, mockClient)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(res.ResultCount, convey.ShouldEqual, 0)
+		})
+
+		PatchConvey("missing primary key field", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(milvusclient.ResultSet{
+				ResultCount: 1,
+				Fields:      milvusclient.DataSet{column.NewColumnInt64("id", []int64{1})},
+			}, nil).Build()
+
+			_, err := newQueryPager(config, "", 1).next(ctx, mockClient)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, `primary key field "pk"`)
+		})
+
+		PatchConvey("query error", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(milvusclient.ResultSet{}, fmt.Errorf("query error")).Build()
+
+			_, err := newQueryPager(config, "", 1).next(ctx, mockClient)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+	})
+}
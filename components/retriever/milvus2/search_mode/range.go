@@ -44,6 +44,10 @@ type Range struct {
 	// For IP: excludes vectors where score > RangeFilter.
 	// Optional; leave nil unless performing ring searches.
 	RangeFilter *float64
+
+	// SearchParams contains extra search parameters (e.g., "nprobe", "ef").
+	// They override RetrieverConfig.DefaultSearchParams, while Radius and RangeFilter take precedence over both.
+	SearchParams map[string]string
 }
 
 // NewRange creates a new Range search mode.
@@ -63,6 +67,17 @@ func (r *Range) WithRangeFilter(rangeFilter float64) *Range {
 	return r
 }
 
+// WithSearchParams sets additional search parameters such as "nprobe" or "ef".
+func (r *Range) WithSearchParams(params map[string]string) *Range {
+	r.SearchParams = params
+	return r
+}
+
+// GetMetricType returns the metric type of the range search.
+func (r *Range) GetMetricType() milvus2.MetricType {
+	return r.MetricType
+}
+
 // Retrieve performs the range search operation.
 func (r *Range) Retrieve(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
 	if conf.Embedding == nil {
@@ -79,10 +94,13 @@ func (r *Range) Retrieve(ctx context.Context, client *milvusclient.Client, conf
 		return nil, fmt.Errorf("failed to build search option: %w", err)
 	}
 
-	result, err := client.Search(ctx, searchOpt)
+	result, err := callWithRetry(ctx, func(ctx context.Context) ([]milvusclient.ResultSet, error) {
+		return client.Search(ctx, searchOpt)
+	}, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search: %w", err)
 	}
+	reportSearchNQ(ctx, searchOpt)
 
 	if len(result) == 0 {
 		return []*schema.Document{}, nil
@@ -106,8 +124,13 @@ func (r *Range) BuildSearchOption(ctx context.Context, conf *milvus2.RetrieverCo
 
 	searchOpt := milvusclient.NewSearchOption(conf.Collection, topK, []entity.Vector{entity.FloatVector(queryVector)}).
 		WithANNSField(conf.VectorField).
-		WithOutputFields(conf.OutputFields...).
-		WithSearchParam("radius", fmt.Sprintf("%v", r.Radius))
+		WithOutputFields(conf.OutputFields...)
+
+	for k, v := range mergeSearchParams(conf, r.SearchParams) {
+		searchOpt.WithSearchParam(k, v)
+	}
+
+	searchOpt.WithSearchParam("radius", fmt.Sprintf("%v", r.Radius))
 
 	// Apply metric type
 	if r.MetricType != "" {
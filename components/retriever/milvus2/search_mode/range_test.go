@@ -66,6 +66,16 @@ func TestRange_WithRangeFilter(t *testing.T) {
 	})
 }
 
+func TestRange_WithSearchParams(t *testing.T) {
+	convey.Convey("test Range.WithSearchParams", t, func() {
+		r := NewRange(milvus2.L2, 0.5)
+		params := map[string]string{"nprobe": "16"}
+		result := r.WithSearchParams(params)
+		convey.So(result, convey.ShouldEqual, r)
+		convey.So(r.SearchParams, convey.ShouldResemble, params)
+	})
+}
+
 func TestRange_BuildSearchOption(t *testing.T) {
 	convey.Convey("test Range.BuildSearchOption", t, func() {
 		ctx := context.Background()
@@ -144,6 +154,7 @@ func TestRange_BuildSearchOption(t *testing.T) {
 func TestRange_ImplementsSearchMode(t *testing.T) {
 	convey.Convey("test Range implements SearchMode", t, func() {
 		var _ milvus2.SearchMode = (*Range)(nil)
+		var _ milvus2.MetricTypeReporter = (*Range)(nil)
 	})
 }
 
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/eino/components/retriever"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+	"github.com/cloudwego/eino-ext/libs/milvus2/retry"
+)
+
+// errAttemptTimeout marks an attempt timed out by WithSearchTimeout while the context of Retrieve is not done.
+var errAttemptTimeout = errors.New("search attempt timed out")
+
+// callWithRetry runs the Milvus call with the per-attempt timeout and the retry policy
+// set by WithSearchTimeout and WithRetryPolicy. Only transient errors and timed out attempts are retried,
+// the call is not retried once ctx is done.
+func callWithRetry[T any](ctx context.Context, call func(ctx context.Context) (T, error), opts ...retriever.Option) (T, error) {
+	io := retriever.GetImplSpecificOptions(&milvus2.ImplOptions{}, opts...)
+
+	var policy *retry.Policy
+	if io.RetryPolicy != nil {
+		p := *io.RetryPolicy
+		isRetryable := p.IsRetryable
+		if isRetryable == nil {
+			isRetryable = retry.IsTransientError
+		}
+		p.IsRetryable = func(err error) bool {
+			return errors.Is(err, errAttemptTimeout) || isRetryable(err)
+		}
+		policy = &p
+	}
+
+	return retry.Do(ctx, policy, func() (T, error) {
+		return callWithTimeout(ctx, io.SearchTimeout, call)
+	})
+}
+
+func callWithTimeout[T any](ctx context.Context, timeout time.Duration, call func(ctx context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return call(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	res, err := call(attemptCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %v: %w", errAttemptTimeout, timeout, err)
+	}
+	return res, err
+}
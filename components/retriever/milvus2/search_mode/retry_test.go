@@ -0,0 +1,103 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"github.com/smartystreets/goconvey/convey"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+)
+
+func TestCallWithRetry(t *testing.T) {
+	convey.Convey("test callWithRetry", t, func() {
+		ctx := context.Background()
+		policy := &milvus2.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+		flaky := func(failures int, err error) (func(ctx context.Context) (int, error), *int) {
+			calls := 0
+			return func(ctx context.Context) (int, error) {
+				calls++
+				if calls <= failures {
+					return 0, err
+				}
+				return calls, nil
+			}, &calls
+		}
+
+		convey.Convey("test no retry by default", func() {
+			call, calls := flaky(1, merr.WrapErrServiceUnavailable("proxy down"))
+			_, err := callWithRetry(ctx, call)
+			convey.So(errors.Is(err, merr.ErrServiceUnavailable), convey.ShouldBeTrue)
+			convey.So(*calls, convey.ShouldEqual, 1)
+		})
+
+		convey.Convey("test transient errors are retried until success", func() {
+			call, calls := flaky(2, merr.WrapErrServiceRateLimit(10))
+			res, err := callWithRetry(ctx, call, milvus2.WithRetryPolicy(policy))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(res, convey.ShouldEqual, 3)
+			convey.So(*calls, convey.ShouldEqual, 3)
+			convey.So(policy.IsRetryable, convey.ShouldBeNil)
+		})
+
+		convey.Convey("test attempts exhausted", func() {
+			call, calls := flaky(5, merr.WrapErrServiceUnavailable("proxy down"))
+			_, err := callWithRetry(ctx, call, milvus2.WithRetryPolicy(policy))
+			convey.So(errors.Is(err, merr.ErrServiceUnavailable), convey.ShouldBeTrue)
+			convey.So(*calls, convey.ShouldEqual, 3)
+		})
+
+		convey.Convey("test permanent errors are not retried", func() {
+			call, calls := flaky(5, merr.WrapErrCollectionNotFound("c"))
+			_, err := callWithRetry(ctx, call, milvus2.WithRetryPolicy(policy))
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(*calls, convey.ShouldEqual, 1)
+		})
+
+		convey.Convey("test timeout per attempt", func() {
+			calls := 0
+			res, err := callWithRetry(ctx, func(ctx context.Context) (int, error) {
+				calls++
+				if calls == 1 {
+					<-ctx.Done()
+					return 0, ctx.Err()
+				}
+				return calls, nil
+			}, milvus2.WithSearchTimeout(10*time.Millisecond), milvus2.WithRetryPolicy(policy))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(res, convey.ShouldEqual, 2)
+		})
+
+		convey.Convey("test no retry once ctx is canceled", func() {
+			cctx, cancel := context.WithCancel(ctx)
+			calls := 0
+			_, err := callWithRetry(cctx, func(ctx context.Context) (int, error) {
+				calls++
+				cancel()
+				return 0, merr.WrapErrServiceUnavailable("proxy down")
+			}, milvus2.WithRetryPolicy(policy))
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(calls, convey.ShouldEqual, 1)
+		})
+	})
+}
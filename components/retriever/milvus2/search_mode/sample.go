@@ -0,0 +1,180 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+)
+
+// Sample implements random sampling of the rows matching a filter.
+// Like Scalar, it treats the query string as a boolean filter expression, and an empty query
+// samples the whole collection. Matching rows are scanned in pages with the Milvus Query API and
+// a uniform random sample of TopK documents is kept with reservoir sampling.
+//
+// It scans every matching row, so it is meant for building eval sets and inspecting
+// collection contents rather than for online retrieval. Use MaxScan to bound the cost.
+type Sample struct {
+	// BatchSize controls how many rows are fetched per network call.
+	// Default: 1000.
+	BatchSize int
+
+	// MaxScan limits the number of rows scanned. The sample is drawn from the scanned rows only.
+	// Default: 0, scans all matching rows.
+	MaxScan int64
+
+	// StratifyBy returns the stratum of a document, e.g. the value of a metadata key.
+	// When set, up to TopK documents are sampled from every stratum independently,
+	// and the strata are returned in the order they are first seen.
+	// Optional.
+	StratifyBy func(doc *schema.Document) string
+
+	// Seed makes the sampling reproducible when set.
+	// Optional. Default: seeded from the current time.
+	Seed *int64
+}
+
+// NewSample creates a new Sample search mode.
+func NewSample(batchSize int) *Sample {
+	if batchSize <= 0 {
+		batchSize = defaultQueryBatchSize
+	}
+	return &Sample{
+		BatchSize: batchSize,
+	}
+}
+
+// WithMaxScan limits the number of rows scanned.
+func (s *Sample) WithMaxScan(n int64) *Sample {
+	s.MaxScan = n
+	return s
+}
+
+// WithStratifyBy enables stratified sampling, taking up to TopK documents from every stratum.
+func (s *Sample) WithStratifyBy(fn func(doc *schema.Document) string) *Sample {
+	s.StratifyBy = fn
+	return s
+}
+
+// WithSeed makes the sampling reproducible.
+func (s *Sample) WithSeed(seed int64) *Sample {
+	s.Seed = &seed
+	return s
+}
+
+// Retrieve scans the rows matching the filter and returns a random sample of them.
+func (s *Sample) Retrieve(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK: &conf.TopK,
+	}, opts...)
+
+	size := conf.TopK
+	if co.TopK != nil {
+		size = *co.TopK
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("sample size must be positive, got %d", size)
+	}
+
+	seed := time.Now().UnixNano()
+	if s.Seed != nil {
+		seed = *s.Seed
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	pager := newQueryPager(conf, query, s.BatchSize, opts...)
+	reservoirs := make(map[string]*reservoir)
+	var strata []string
+	var scanned int64
+
+scan:
+	for {
+		res, err := pager.next(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		if res.ResultCount == 0 {
+			break
+		}
+
+		batchDocs, err := conf.DocumentConverter(ctx, res)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert batch results: %w", err)
+		}
+
+		for _, doc := range batchDocs {
+			var stratum string
+			if s.StratifyBy != nil {
+				stratum = s.StratifyBy(doc)
+			}
+			r, ok := reservoirs[stratum]
+			if !ok {
+				r = &reservoir{size: size}
+				reservoirs[stratum] = r
+				strata = append(strata, stratum)
+			}
+			r.add(doc, rng)
+
+			scanned++
+			if s.MaxScan > 0 && scanned >= s.MaxScan {
+				break scan
+			}
+		}
+	}
+
+	var docs []*schema.Document
+	for _, stratum := range strata {
+		r := reservoirs[stratum]
+		// The reservoir keeps early rows in their original positions, shuffle to randomize the order as well.
+		rng.Shuffle(len(r.docs), func(i, j int) { r.docs[i], r.docs[j] = r.docs[j], r.docs[i] })
+		docs = append(docs, r.docs...)
+	}
+
+	return docs, nil
+}
+
+// BuildQueryOption creates the QueryOption of the first page of rows matching the query and filter.
+// The following pages add a primary key cursor to the filter.
+func (s *Sample) BuildQueryOption(ctx context.Context, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) (milvusclient.QueryOption, error) {
+	return newQueryPager(conf, query, s.BatchSize, opts...).queryOption(), nil
+}
+
+// reservoir keeps a uniform random sample of up to size documents (Algorithm R).
+type reservoir struct {
+	size int
+	seen int
+	docs []*schema.Document
+}
+
+func (r *reservoir) add(doc *schema.Document, rng *rand.Rand) {
+	r.seen++
+	if len(r.docs) < r.size {
+		r.docs = append(r.docs, doc)
+		return
+	}
+	if j := rng.Intn(r.seen); j < r.size {
+		r.docs[j] = doc
+	}
+}
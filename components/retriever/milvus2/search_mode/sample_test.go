@@ -0,0 +1,207 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package search_mode
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+	"github.com/smartystreets/goconvey/convey"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
+)
+
+func TestNewSample(t *testing.T) {
+	convey.Convey("test NewSample", t, func() {
+		convey.So(NewSample(0).BatchSize, convey.ShouldEqual, 1000)
+		convey.So(NewSample(50).BatchSize, convey.ShouldEqual, 50)
+
+		s := NewSample(10).WithMaxScan(100).WithSeed(1)
+		convey.So(s.MaxScan, convey.ShouldEqual, 100)
+		convey.So(*s.Seed, convey.ShouldEqual, 1)
+	})
+}
+
+func TestSample_BuildQueryOption(t *testing.T) {
+	convey.Convey("test Sample.BuildQueryOption", t, func() {
+		ctx := context.Background()
+		config := &milvus2.RetrieverConfig{
+			Collection:   "test_collection",
+			TopK:         10,
+			OutputFields: []string{"id", "content"},
+			Partitions:   []string{"p1"},
+		}
+
+		opt, err := NewSample(10).BuildQueryOption(ctx, config, "id > 10", milvus2.WithFilter("tag == 'a'"))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(opt, convey.ShouldNotBeNil)
+		req, err := opt.Request()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(req.GetExpr(), convey.ShouldEqual, "(id > 10) and (tag == 'a')")
+
+		opt, err = NewSample(10).BuildQueryOption(ctx, config, "")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(opt, convey.ShouldNotBeNil)
+	})
+}
+
+// Verify interface implementation
+func TestSample_ImplementsSearchMode(t *testing.T) {
+	convey.Convey("test Sample implements SearchMode", t, func() {
+		var _ milvus2.SearchMode = (*Sample)(nil)
+	})
+}
+
+func TestSample_Retrieve(t *testing.T) {
+	PatchConvey("test Sample.Retrieve", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+
+		// 3 pages of 10 rows and an empty one, the category is "even" or "odd".
+		// The sequence restarts after the empty page, so every Retrieve scans the same rows.
+		page := func(n int) milvusclient.ResultSet {
+			ids := make([]int64, 0, 10)
+			for j := 0; j < 10 && n < 3; j++ {
+				ids = append(ids, int64(n*10+j))
+			}
+			return milvusclient.ResultSet{ResultCount: len(ids), Fields: milvusclient.DataSet{column.NewColumnInt64("id", ids)}}
+		}
+		pages := Sequence(page(0), nil)
+		for n := 1; n <= 3; n++ {
+			pages = pages.Then(page(n), nil)
+		}
+
+		config := &milvus2.RetrieverConfig{
+			Collection: "test_collection",
+			IDField:    "id",
+			TopK:       5,
+			DocumentConverter: func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
+				docs := make([]*schema.Document, 0, result.ResultCount)
+				for i := 0; i < result.ResultCount; i++ {
+					n, _ := result.GetColumn("id").GetAsInt64(i)
+					category := "even"
+					if n%2 == 1 {
+						category = "odd"
+					}
+					docs = append(docs, &schema.Document{ID: strconv.FormatInt(n, 10), MetaData: map[string]any{"category": category}})
+				}
+				return docs, nil
+			},
+		}
+
+		PatchConvey("uniform sample", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(pages).Build()
+
+			docs, err := NewSample(10).WithSeed(1).Retrieve(ctx, mockClient, config, "")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 5)
+
+			ids := make(map[string]bool)
+			for _, doc := range docs {
+				ids[doc.ID] = true
+			}
+			convey.So(len(ids), convey.ShouldEqual, 5)
+		})
+
+		PatchConvey("reproducible with seed", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(pages).Build()
+
+			docs1, err := NewSample(10).WithSeed(42).Retrieve(ctx, mockClient, config, "")
+			convey.So(err, convey.ShouldBeNil)
+			docs2, err := NewSample(10).WithSeed(42).Retrieve(ctx, mockClient, config, "")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs1), convey.ShouldEqual, len(docs2))
+			for i := range docs1 {
+				convey.So(docs1[i].ID, convey.ShouldEqual, docs2[i].ID)
+			}
+		})
+
+		PatchConvey("stratified sample", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(pages).Build()
+
+			s := NewSample(10).WithSeed(1).WithStratifyBy(func(doc *schema.Document) string {
+				return doc.MetaData["category"].(string)
+			})
+			docs, err := s.Retrieve(ctx, mockClient, config, "", retriever.WithTopK(3))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 6)
+			for i, doc := range docs {
+				want := "even"
+				if i >= 3 {
+					want = "odd"
+				}
+				convey.So(doc.MetaData["category"], convey.ShouldEqual, want)
+			}
+		})
+
+		PatchConvey("max scan", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(pages).Build()
+
+			docs, err := NewSample(10).WithMaxScan(3).Retrieve(ctx, mockClient, config, "")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 3)
+			for _, doc := range docs {
+				n, _ := strconv.Atoi(doc.ID)
+				convey.So(n, convey.ShouldBeLessThan, 3)
+			}
+		})
+
+		PatchConvey("invalid sample size", func() {
+			docs, err := NewSample(10).Retrieve(ctx, mockClient, config, "", retriever.WithTopK(0))
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(docs, convey.ShouldBeNil)
+		})
+
+		PatchConvey("query error", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(milvusclient.ResultSet{}, fmt.Errorf("query error")).Build()
+
+			docs, err := NewSample(10).Retrieve(ctx, mockClient, config, "")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(docs, convey.ShouldBeNil)
+		})
+	})
+}
+
+func TestReservoir(t *testing.T) {
+	convey.Convey("test reservoir keeps every row with equal probability", t, func() {
+		rng := rand.New(rand.NewSource(1))
+		counts := make([]int, 10)
+		for round := 0; round < 2000; round++ {
+			r := &reservoir{size: 2}
+			for i := 0; i < 10; i++ {
+				r.add(&schema.Document{ID: strconv.Itoa(i)}, rng)
+			}
+			convey.So(len(r.docs), convey.ShouldEqual, 2)
+			for _, doc := range r.docs {
+				n, _ := strconv.Atoi(doc.ID)
+				counts[n]++
+			}
+		}
+		// Every row is expected to be kept 400 times.
+		for _, c := range counts {
+			convey.So(c, convey.ShouldBeBetween, 300, 500)
+		}
+	})
+}
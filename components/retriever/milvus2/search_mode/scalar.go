@@ -18,7 +18,10 @@ package search_mode
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/schema"
@@ -27,17 +30,56 @@ import (
 	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
 )
 
-// Scalar implements scalar/metadata search using the Milvus Query API.
-// It treats the query string as a boolean filter expression (e.g., "id > 10").
-type Scalar struct{}
+// Scalar implements scalar/metadata search using the Milvus Query API, without any embedding.
+// It treats the query string as a boolean filter expression (e.g., "id > 10"), combined with
+// the filter of WithFilter, so exact-match lookups by metadata go through the same Retriever API
+// and DocumentConverter as the vector search modes.
+type Scalar struct {
+	// Less orders the matching documents, e.g. SortByMetadata("updated_at", true).
+	// Milvus Query does not sort its results, so when set, the matching rows are scanned
+	// in pages of BatchSize and the first TopK documents in the order are returned.
+	// Use MaxScan to bound the cost.
+	// Optional. Default: TopK matching rows in the order returned by Milvus.
+	Less func(a, b *schema.Document) bool
+
+	// BatchSize controls how many rows are fetched per network call when sorting.
+	// Default: 1000.
+	BatchSize int
+
+	// MaxScan limits the number of rows scanned when sorting. Only the scanned rows are sorted.
+	// Default: 0, scans all matching rows.
+	MaxScan int64
+}
 
 // NewScalar creates a new Scalar search mode.
 func NewScalar() *Scalar {
 	return &Scalar{}
 }
 
+// WithSort orders the matching documents by less, see Scalar.Less.
+func (s *Scalar) WithSort(less func(a, b *schema.Document) bool) *Scalar {
+	s.Less = less
+	return s
+}
+
+// WithBatchSize sets the number of rows fetched per network call when sorting.
+func (s *Scalar) WithBatchSize(batchSize int) *Scalar {
+	s.BatchSize = batchSize
+	return s
+}
+
+// WithMaxScan limits the number of rows scanned when sorting.
+func (s *Scalar) WithMaxScan(n int64) *Scalar {
+	s.MaxScan = n
+	return s
+}
+
 // Retrieve performs the scalar query search.
 func (s *Scalar) Retrieve(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	if s.Less != nil {
+		return s.retrieveSorted(ctx, client, conf, query, opts...)
+	}
+
 	queryOpt, err := s.BuildQueryOption(ctx, conf, query, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build query option: %w", err)
@@ -51,6 +93,45 @@ func (s *Scalar) Retrieve(ctx context.Context, client *milvusclient.Client, conf
 	return conf.DocumentConverter(ctx, result)
 }
 
+// retrieveSorted scans the rows matching the filter and returns the first TopK documents ordered by Less.
+func (s *Scalar) retrieveSorted(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK: &conf.TopK,
+	}, opts...)
+
+	topK := conf.TopK
+	if co.TopK != nil {
+		topK = *co.TopK
+	}
+
+	pager := newQueryPager(conf, query, s.BatchSize, opts...)
+	var docs []*schema.Document
+	for s.MaxScan <= 0 || int64(len(docs)) < s.MaxScan {
+		res, err := pager.next(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		if res.ResultCount == 0 {
+			break
+		}
+
+		batchDocs, err := conf.DocumentConverter(ctx, res)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert batch results: %w", err)
+		}
+		docs = append(docs, batchDocs...)
+	}
+	if s.MaxScan > 0 && int64(len(docs)) > s.MaxScan {
+		docs = docs[:s.MaxScan]
+	}
+
+	sort.SliceStable(docs, func(i, j int) bool { return s.Less(docs[i], docs[j]) })
+	if topK > 0 && len(docs) > topK {
+		docs = docs[:topK]
+	}
+	return docs, nil
+}
+
 // BuildQueryOption creates a QueryOption for scalar/metadata-based document retrieval.
 func (s *Scalar) BuildQueryOption(ctx context.Context, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) (milvusclient.QueryOption, error) {
 	io := retriever.GetImplSpecificOptions(&milvus2.ImplOptions{}, opts...)
@@ -63,18 +144,8 @@ func (s *Scalar) BuildQueryOption(ctx context.Context, conf *milvus2.RetrieverCo
 		finalTopK = *co.TopK
 	}
 
-	// Combine query and filter with AND logic
-	expr := query
-	if io.Filter != "" {
-		if expr != "" {
-			expr = "(" + expr + ") and (" + io.Filter + ")"
-		} else {
-			expr = io.Filter
-		}
-	}
-
 	opt := milvusclient.NewQueryOption(conf.Collection).
-		WithFilter(expr).
+		WithFilter(combineFilter(query, io.Filter)).
 		WithOutputFields(conf.OutputFields...).
 		WithLimit(int(finalTopK))
 
@@ -89,3 +160,71 @@ func (s *Scalar) BuildQueryOption(ctx context.Context, conf *milvus2.RetrieverCo
 
 	return opt, nil
 }
+
+// SortByMetadata returns a Less function ordering the documents by the value of the metadata key,
+// for Scalar.WithSort. Numbers are compared numerically and other values by their string form,
+// documents without the key are placed last.
+func SortByMetadata(key string, descending bool) func(a, b *schema.Document) bool {
+	return func(a, b *schema.Document) bool {
+		va, okA := a.MetaData[key]
+		vb, okB := b.MetaData[key]
+		if !okA || !okB {
+			return okA && !okB
+		}
+		c := compareValues(va, vb)
+		if descending {
+			return c > 0
+		}
+		return c < 0
+	}
+}
+
+func compareValues(a, b any) int {
+	fa, okA := toFloat64(a)
+	fb, okB := toFloat64(b)
+	if okA && okB {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		default:
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func toFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
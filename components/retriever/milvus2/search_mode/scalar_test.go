@@ -19,11 +19,13 @@ package search_mode
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"testing"
 
 	. "github.com/bytedance/mockey"
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/schema"
+	"github.com/milvus-io/milvus/client/v2/column"
 	"github.com/milvus-io/milvus/client/v2/milvusclient"
 	"github.com/smartystreets/goconvey/convey"
 
@@ -149,3 +151,86 @@ func TestScalar_Retrieve(t *testing.T) {
 		})
 	})
 }
+
+func TestScalar_RetrieveSorted(t *testing.T) {
+	PatchConvey("test Scalar.Retrieve with sort", t, func() {
+		ctx := context.Background()
+		mockClient := &milvusclient.Client{}
+
+		// 2 pages of 5 rows and an empty one, the score of the row n is (n*7)%10.
+		page := func(n int) milvusclient.ResultSet {
+			ids := make([]int64, 0, 5)
+			for j := 0; j < 5 && n < 2; j++ {
+				ids = append(ids, int64(n*5+j))
+			}
+			return milvusclient.ResultSet{ResultCount: len(ids), Fields: milvusclient.DataSet{column.NewColumnInt64("id", ids)}}
+		}
+		pages := Sequence(page(0), nil).Then(page(1), nil).Then(page(2), nil)
+
+		config := &milvus2.RetrieverConfig{
+			Collection: "test_collection",
+			IDField:    "id",
+			TopK:       3,
+			DocumentConverter: func(ctx context.Context, result milvusclient.ResultSet) ([]*schema.Document, error) {
+				docs := make([]*schema.Document, 0, result.ResultCount)
+				for i := 0; i < result.ResultCount; i++ {
+					id, _ := result.GetColumn("id").GetAsInt64(i)
+					n := int(id)
+					docs = append(docs, &schema.Document{ID: strconv.Itoa(n), MetaData: map[string]any{"score": (n * 7) % 10}})
+				}
+				return docs, nil
+			},
+		}
+
+		PatchConvey("descending", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(pages).Build()
+
+			docs, err := NewScalar().WithBatchSize(5).WithSort(SortByMetadata("score", true)).Retrieve(ctx, mockClient, config, `source == "a"`)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 3)
+			convey.So(docs[0].MetaData["score"], convey.ShouldEqual, 9)
+			convey.So(docs[1].MetaData["score"], convey.ShouldEqual, 8)
+			convey.So(docs[2].MetaData["score"], convey.ShouldEqual, 7)
+		})
+
+		PatchConvey("max scan", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(pages).Build()
+
+			docs, err := NewScalar().WithBatchSize(5).WithSort(SortByMetadata("score", false)).WithMaxScan(4).
+				Retrieve(ctx, mockClient, config, "", retriever.WithTopK(10))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 4)
+			convey.So(docs[0].ID, convey.ShouldEqual, "0")
+			convey.So(docs[3].ID, convey.ShouldEqual, "1")
+		})
+
+		PatchConvey("query error", func() {
+			Mock(GetMethod(mockClient, "Query")).Return(milvusclient.ResultSet{}, fmt.Errorf("query error")).Build()
+
+			docs, err := NewScalar().WithBatchSize(5).WithSort(SortByMetadata("score", true)).Retrieve(ctx, mockClient, config, "")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(docs, convey.ShouldBeNil)
+		})
+	})
+}
+
+func TestSortByMetadata(t *testing.T) {
+	convey.Convey("test SortByMetadata", t, func() {
+		docs := []*schema.Document{
+			{ID: "missing"},
+			{ID: "b", MetaData: map[string]any{"k": "b"}},
+			{ID: "10", MetaData: map[string]any{"k": float64(10)}},
+			{ID: "a", MetaData: map[string]any{"k": "a"}},
+			{ID: "9", MetaData: map[string]any{"k": int64(9)}},
+		}
+		less := SortByMetadata("k", false)
+		convey.So(less(docs[4], docs[2]), convey.ShouldBeTrue)
+		convey.So(less(docs[3], docs[1]), convey.ShouldBeTrue)
+		convey.So(less(docs[1], docs[0]), convey.ShouldBeTrue)
+		convey.So(less(docs[0], docs[1]), convey.ShouldBeFalse)
+
+		desc := SortByMetadata("k", true)
+		convey.So(desc(docs[2], docs[4]), convey.ShouldBeTrue)
+		convey.So(desc(docs[1], docs[0]), convey.ShouldBeTrue)
+	})
+}
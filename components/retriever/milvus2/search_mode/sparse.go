@@ -34,6 +34,10 @@ type Sparse struct {
 	// MetricType specifies the metric type for sparse similarity.
 	// Default: BM25 (or IP if not specified but typically BM25 for text).
 	MetricType milvus2.MetricType
+
+	// SearchParams contains extra search parameters (e.g., "drop_ratio_search").
+	// They override RetrieverConfig.DefaultSearchParams.
+	SearchParams map[string]string
 }
 
 // NewSparse creates a new Sparse search mode.
@@ -46,6 +50,17 @@ func NewSparse(metricType milvus2.MetricType) *Sparse {
 	}
 }
 
+// WithSearchParams sets additional search parameters such as "drop_ratio_search".
+func (s *Sparse) WithSearchParams(params map[string]string) *Sparse {
+	s.SearchParams = params
+	return s
+}
+
+// GetMetricType returns the metric type of the sparse search.
+func (s *Sparse) GetMetricType() milvus2.MetricType {
+	return s.MetricType
+}
+
 // Retrieve performs the sparse search operation (text search via function).
 func (s *Sparse) Retrieve(ctx context.Context, client *milvusclient.Client, conf *milvus2.RetrieverConfig, query string, opts ...retriever.Option) ([]*schema.Document, error) {
 	searchOpt, err := s.BuildSparseSearchOption(ctx, conf, query, opts...)
@@ -53,10 +68,13 @@ func (s *Sparse) Retrieve(ctx context.Context, client *milvusclient.Client, conf
 		return nil, fmt.Errorf("failed to build sparse search option: %w", err)
 	}
 
-	result, err := client.Search(ctx, searchOpt)
+	result, err := callWithRetry(ctx, func(ctx context.Context) ([]milvusclient.ResultSet, error) {
+		return client.Search(ctx, searchOpt)
+	}, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search: %w", err)
 	}
+	reportSearchNQ(ctx, searchOpt)
 
 	if len(result) == 0 {
 		return []*schema.Document{}, nil
@@ -82,6 +100,10 @@ func (s *Sparse) BuildSparseSearchOption(ctx context.Context, conf *milvus2.Retr
 		WithANNSField(conf.SparseVectorField).
 		WithOutputFields(conf.OutputFields...)
 
+	for k, v := range mergeSearchParams(conf, s.SearchParams) {
+		searchOpt.WithSearchParam(k, v)
+	}
+
 	// Apply metric type
 	if s.MetricType != "" {
 		searchOpt.WithSearchParam("metric_type", string(s.MetricType))
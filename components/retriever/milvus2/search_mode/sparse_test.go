@@ -42,6 +42,16 @@ func TestNewSparse(t *testing.T) {
 	})
 }
 
+func TestSparse_WithSearchParams(t *testing.T) {
+	convey.Convey("test Sparse.WithSearchParams", t, func() {
+		s := NewSparse(milvus2.BM25)
+		params := map[string]string{"drop_ratio_search": "0.2"}
+		result := s.WithSearchParams(params)
+		convey.So(result, convey.ShouldEqual, s)
+		convey.So(s.SearchParams, convey.ShouldResemble, params)
+	})
+}
+
 func TestSparse_BuildSparseSearchOption(t *testing.T) {
 	convey.Convey("test Sparse.BuildSparseSearchOption", t, func() {
 		ctx := context.Background()
@@ -89,6 +99,7 @@ func TestSparse_BuildSparseSearchOption(t *testing.T) {
 func TestSparse_ImplementsSearchMode(t *testing.T) {
 	convey.Convey("test Sparse implements SearchMode", t, func() {
 		var _ milvus2.SearchMode = (*Sparse)(nil)
+		var _ milvus2.MetricTypeReporter = (*Sparse)(nil)
 	})
 }
 
@@ -23,6 +23,9 @@ import (
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/embedding"
+	"github.com/milvus-io/milvus/client/v2/milvusclient"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
 )
 
 // EmbedQuery embeds the query string into a vector.
@@ -53,9 +56,63 @@ func EmbedQuery(ctx context.Context, emb embedding.Embedder, query string) ([]fl
 		return nil, fmt.Errorf("[Retriever] invalid embedding result: expected 1, got %d", len(vectors))
 	}
 
-	queryVector := make([]float32, len(vectors[0]))
-	for i, v := range vectors[0] {
-		queryVector[i] = float32(v)
+	return toFloat32(vectors[0]), nil
+}
+
+func toFloat32(vector []float64) []float32 {
+	result := make([]float32, len(vector))
+	for i, v := range vector {
+		result[i] = float32(v)
+	}
+	return result
+}
+
+// mergeSearchParams returns the RetrieverConfig.DefaultSearchParams overridden by the params of the search mode.
+func mergeSearchParams(conf *milvus2.RetrieverConfig, params map[string]string) map[string]string {
+	if len(conf.DefaultSearchParams) == 0 {
+		return params
+	}
+	if len(params) == 0 {
+		// the merged params are only read, no need to copy the defaults
+		return conf.DefaultSearchParams
+	}
+	merged := make(map[string]string, len(conf.DefaultSearchParams)+len(params))
+	for k, v := range conf.DefaultSearchParams {
+		merged[k] = v
+	}
+	for k, v := range params {
+		merged[k] = v
+	}
+	return merged
+}
+
+// combineFilter combines the query expression and the filter with AND logic.
+func combineFilter(query, filter string) string {
+	if filter == "" {
+		return query
+	}
+	if query == "" {
+		return filter
+	}
+	return "(" + query + ") and (" + filter + ")"
+}
+
+// reportSearchNQ reports the nq of the search request to the callbacks of the Retrieve, see milvus2.ReportNQ.
+func reportSearchNQ(ctx context.Context, opt milvusclient.SearchOption) {
+	if req, err := opt.Request(); err == nil {
+		milvus2.ReportNQ(ctx, req.GetNq())
+	}
+}
+
+// reportHybridSearchNQ reports the nq summed over the sub-requests of the hybrid search request.
+func reportHybridSearchNQ(ctx context.Context, opt milvusclient.HybridSearchOption) {
+	req, err := opt.HybridRequest()
+	if err != nil {
+		return
+	}
+	var nq int64
+	for _, sub := range req.GetRequests() {
+		nq += sub.GetNq()
 	}
-	return queryVector, nil
+	milvus2.ReportNQ(ctx, nq)
 }
@@ -23,6 +23,8 @@ import (
 
 	"github.com/cloudwego/eino/components/embedding"
 	. "github.com/smartystreets/goconvey/convey"
+
+	milvus2 "github.com/cloudwego/eino-ext/components/retriever/milvus2"
 )
 
 // mockEmbedding implements embedding.Embedder for testing
@@ -78,3 +80,25 @@ func TestEmbedQuery(t *testing.T) {
 		})
 	})
 }
+
+func TestCombineFilter(t *testing.T) {
+	Convey("test combineFilter", t, func() {
+		So(combineFilter("", ""), ShouldEqual, "")
+		So(combineFilter("id > 10", ""), ShouldEqual, "id > 10")
+		So(combineFilter("", "tag == 'a'"), ShouldEqual, "tag == 'a'")
+		So(combineFilter("id > 10", "tag == 'a'"), ShouldEqual, "(id > 10) and (tag == 'a')")
+	})
+}
+
+func TestMergeSearchParams(t *testing.T) {
+	Convey("test mergeSearchParams", t, func() {
+		params := map[string]string{"ef": "128"}
+		So(mergeSearchParams(&milvus2.RetrieverConfig{}, params), ShouldResemble, params)
+		So(mergeSearchParams(&milvus2.RetrieverConfig{}, nil), ShouldBeNil)
+
+		conf := &milvus2.RetrieverConfig{DefaultSearchParams: map[string]string{"ef": "64", "nprobe": "16"}}
+		So(mergeSearchParams(conf, nil), ShouldResemble, map[string]string{"ef": "64", "nprobe": "16"})
+		So(mergeSearchParams(conf, params), ShouldResemble, map[string]string{"ef": "128", "nprobe": "16"})
+		So(conf.DefaultSearchParams, ShouldResemble, map[string]string{"ef": "64", "nprobe": "16"})
+	})
+}
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultRetrieverToolName = "search_knowledge_base"
+	defaultRetrieverToolDesc = "Search the knowledge base for the documents relevant to the query."
+)
+
+// RetrieverToolConfig contains configuration for the tool built by NewRetrieverTool.
+type RetrieverToolConfig struct {
+	// Name is the name of the tool presented to the model.
+	// Optional. Default: "search_knowledge_base"
+	Name string
+
+	// Desc is the description of the tool presented to the model, which should tell what the knowledge base holds.
+	// Optional. Default: "Search the knowledge base for the documents relevant to the query."
+	Desc string
+
+	// MaxTopK caps the top_k the model may ask for.
+	// Optional. Default: the TopK of the retriever.
+	MaxTopK int
+
+	// AllowFilter exposes the filter parameter, a Milvus boolean expression written by the model.
+	// Only enable it if the model may read every document of the collection, as the filter is not restricted.
+	// Optional. Default: false
+	AllowFilter bool
+
+	// FilterDesc describes the filterable fields to the model, e.g. `category (string), year (int64)`.
+	// Only used when AllowFilter is true.
+	// Optional.
+	FilterDesc string
+
+	// DocumentFormatter formats the retrieved documents into the tool result.
+	// Optional. Default: a JSON array of the id, content, score and metadata of the documents.
+	DocumentFormatter func(ctx context.Context, docs []*schema.Document) (string, error)
+}
+
+// retrieverToolArgs are the arguments of the tool built by NewRetrieverTool.
+type retrieverToolArgs struct {
+	Query  string `json:"query"`
+	TopK   int    `json:"top_k,omitempty"`
+	Filter string `json:"filter,omitempty"`
+}
+
+// retrieverToolDocument is a retrieved document in the default tool result.
+type retrieverToolDocument struct {
+	ID       string         `json:"id"`
+	Content  string         `json:"content"`
+	Score    float64        `json:"score"`
+	MetaData map[string]any `json:"metadata,omitempty"`
+}
+
+type retrieverTool struct {
+	retriever *Retriever
+	config    *RetrieverToolConfig
+	info      *schema.ToolInfo
+}
+
+// NewRetrieverTool returns a tool searching the knowledge base with the retriever,
+// so an agent can look up documents by adding a single tool.
+// The model passes the query, and optionally top_k and, if AllowFilter is set, a filter expression.
+func NewRetrieverTool(r *Retriever, conf *RetrieverToolConfig) (tool.InvokableTool, error) {
+	if r == nil {
+		return nil, fmt.Errorf("[NewRetrieverTool] retriever not provided")
+	}
+	c := RetrieverToolConfig{}
+	if conf != nil {
+		c = *conf
+	}
+	if c.Name == "" {
+		c.Name = defaultRetrieverToolName
+	}
+	if c.Desc == "" {
+		c.Desc = defaultRetrieverToolDesc
+	}
+	if c.MaxTopK <= 0 {
+		c.MaxTopK = r.config.TopK
+	}
+	if c.DocumentFormatter == nil {
+		c.DocumentFormatter = defaultDocumentFormatter
+	}
+
+	params := map[string]*schema.ParameterInfo{
+		"query": {
+			Type:     schema.String,
+			Desc:     "The search query, phrased like the text of the documents to find.",
+			Required: true,
+		},
+		"top_k": {
+			Type: schema.Integer,
+			Desc: fmt.Sprintf("The number of documents to return, at most %d.", c.MaxTopK),
+		},
+	}
+	if c.AllowFilter {
+		desc := "A Milvus boolean expression filtering the documents, e.g. `year >= 2024`."
+		if c.FilterDesc != "" {
+			desc += " Filterable fields: " + c.FilterDesc
+		}
+		params["filter"] = &schema.ParameterInfo{Type: schema.String, Desc: desc}
+	}
+
+	return &retrieverTool{
+		retriever: r,
+		config:    &c,
+		info: &schema.ToolInfo{
+			Name:        c.Name,
+			Desc:        c.Desc,
+			ParamsOneOf: schema.NewParamsOneOfByParams(params),
+		},
+	}, nil
+}
+
+func (t *retrieverTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return t.info, nil
+}
+
+func (t *retrieverTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	var args retrieverToolArgs
+	if err := sonic.UnmarshalString(argumentsInJSON, &args); err != nil {
+		return "", fmt.Errorf("[RetrieverTool] invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Query) == "" {
+		return "", fmt.Errorf("[RetrieverTool] query is required")
+	}
+
+	var opts []retriever.Option
+	if args.TopK > 0 {
+		opts = append(opts, retriever.WithTopK(min(args.TopK, t.config.MaxTopK)))
+	}
+	if args.Filter != "" {
+		if !t.config.AllowFilter {
+			return "", fmt.Errorf("[RetrieverTool] filter is not allowed")
+		}
+		opts = append(opts, WithFilter(args.Filter))
+	}
+
+	docs, err := t.retriever.Retrieve(ctx, args.Query, opts...)
+	if err != nil {
+		return "", fmt.Errorf("[RetrieverTool] retrieve failed: %w", err)
+	}
+	return t.config.DocumentFormatter(ctx, docs)
+}
+
+func defaultDocumentFormatter(_ context.Context, docs []*schema.Document) (string, error) {
+	results := make([]retrieverToolDocument, 0, len(docs))
+	for _, doc := range docs {
+		results = append(results, retrieverToolDocument{
+			ID:       doc.ID,
+			Content:  doc.Content,
+			Score:    doc.Score(),
+			MetaData: userMetaData(doc.MetaData),
+		})
+	}
+	return sonic.MarshalString(results)
+}
+
+// userMetaData returns the metadata without the keys set by eino such as "_score", which start with an underscore.
+func userMetaData(meta map[string]any) map[string]any {
+	var user map[string]any
+	for k, v := range meta {
+		if strings.HasPrefix(k, "_") {
+			continue
+		}
+		if user == nil {
+			user = make(map[string]any, len(meta))
+		}
+		user[k] = v
+	}
+	return user
+}
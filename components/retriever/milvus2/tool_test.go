@@ -0,0 +1,101 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package milvus2
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/bytedance/mockey"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewRetrieverTool(t *testing.T) {
+	PatchConvey("test NewRetrieverTool", t, func() {
+		ctx := context.Background()
+		r := &Retriever{config: &RetrieverConfig{Collection: "test_collection", TopK: 5}}
+
+		PatchConvey("test nil retriever", func() {
+			_, err := NewRetrieverTool(nil, nil)
+			convey.So(err, convey.ShouldNotBeNil)
+		})
+
+		PatchConvey("test info", func() {
+			tl, err := NewRetrieverTool(r, nil)
+			convey.So(err, convey.ShouldBeNil)
+			info, err := tl.Info(ctx)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(info.Name, convey.ShouldEqual, defaultRetrieverToolName)
+			js, err := info.ParamsOneOf.ToJSONSchema()
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(js.Required, convey.ShouldResemble, []string{"query"})
+			_, ok := js.Properties.Get("filter")
+			convey.So(ok, convey.ShouldBeFalse)
+
+			tl, err = NewRetrieverTool(r, &RetrieverToolConfig{Name: "kb", AllowFilter: true, FilterDesc: "year (int64)"})
+			convey.So(err, convey.ShouldBeNil)
+			info, err = tl.Info(ctx)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(info.Name, convey.ShouldEqual, "kb")
+			js, err = info.ParamsOneOf.ToJSONSchema()
+			convey.So(err, convey.ShouldBeNil)
+			filter, ok := js.Properties.Get("filter")
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(filter.Description, convey.ShouldContainSubstring, "year (int64)")
+		})
+
+		PatchConvey("test run", func() {
+			var gotQuery string
+			var gotOpts *retriever.Options
+			var gotImpl *ImplOptions
+			Mock(GetMethod(r, "Retrieve")).To(func(_ context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+				gotQuery = query
+				gotOpts = retriever.GetCommonOptions(&retriever.Options{}, opts...)
+				gotImpl = retriever.GetImplSpecificOptions(&ImplOptions{}, opts...)
+				doc := (&schema.Document{ID: "1", Content: "milvus", MetaData: map[string]any{"year": 2024}}).WithScore(0.9)
+				return []*schema.Document{doc}, nil
+			}).Build()
+
+			tl, err := NewRetrieverTool(r, &RetrieverToolConfig{AllowFilter: true})
+			convey.So(err, convey.ShouldBeNil)
+			out, err := tl.InvokableRun(ctx, `{"query":"vector db","top_k":100,"filter":"year >= 2024"}`)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(gotQuery, convey.ShouldEqual, "vector db")
+			convey.So(*gotOpts.TopK, convey.ShouldEqual, 5)
+			convey.So(gotImpl.Filter, convey.ShouldEqual, "year >= 2024")
+			convey.So(out, convey.ShouldEqual, `[{"id":"1","content":"milvus","score":0.9,"metadata":{"year":2024}}]`)
+		})
+
+		PatchConvey("test run errors", func() {
+			Mock(GetMethod(r, "Retrieve")).Return(nil, fmt.Errorf("search error")).Build()
+			tl, err := NewRetrieverTool(r, nil)
+			convey.So(err, convey.ShouldBeNil)
+
+			_, err = tl.InvokableRun(ctx, `{"query":""}`)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "query is required")
+			_, err = tl.InvokableRun(ctx, `{"query":"q","filter":"year >= 2024"}`)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "filter is not allowed")
+			_, err = tl.InvokableRun(ctx, `not json`)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "invalid arguments")
+			_, err = tl.InvokableRun(ctx, `{"query":"q"}`)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "search error")
+		})
+	})
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/smartystreets/goconvey/convey"
+
+	"github.com/cloudwego/eino-ext/libs/configcheck"
+)
+
+func TestRetrieverConfig_Validate(t *testing.T) {
+	convey.Convey("test RetrieverConfig.Validate", t, func() {
+		convey.Convey("valid", func() {
+			conf := &RetrieverConfig{Client: &redis.Client{}, Index: "mock_index", Embedding: &mockEmbedding{}}
+			convey.So(conf.Validate(), convey.ShouldBeNil)
+			// the defaults are not filled in
+			convey.So(conf.TopK, convey.ShouldEqual, 0)
+			convey.So(conf.VectorField, convey.ShouldEqual, "")
+		})
+
+		convey.Convey("invalid", func() {
+			threshold := -0.5
+			err := (&RetrieverConfig{DistanceThreshold: &threshold, TopK: -1}).Validate()
+			report, ok := err.(*configcheck.Report)
+			convey.So(ok, convey.ShouldBeTrue)
+			convey.So(report.Config, convey.ShouldEqual, "redis.RetrieverConfig")
+			convey.So(report.Issues, convey.ShouldResemble, []configcheck.Issue{
+				{Field: "Client", Message: "redis client not provided"},
+				{Field: "Index", Message: "redis index not provided"},
+				{Field: "DistanceThreshold", Message: "must not be negative, got -0.5"},
+				{Field: "TopK", Message: "must not be negative, got -1"},
+				{Field: "Embedding", Message: "embedding not provided"},
+			})
+		})
+	})
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package text2sql
+
+const (
+	typ = "Text2SQL"
+
+	defaultMaxRows = 100
+)
+
+// Dialect is the SQL dialect the ChatModel generates queries in.
+type Dialect string
+
+const (
+	// DialectClickHouse generates ClickHouse SQL.
+	DialectClickHouse Dialect = "ClickHouse"
+	// DialectStarRocks generates StarRocks SQL, which is largely MySQL compatible.
+	DialectStarRocks Dialect = "StarRocks"
+)
+
+// Metadata keys of the provenance metadata set on every retrieved document.
+const (
+	// MetaKeySQL is the SQL statement the document was retrieved by.
+	MetaKeySQL = "_sql"
+	// MetaKeyDialect is the Dialect of the SQL statement.
+	MetaKeyDialect = "_dialect"
+	// MetaKeyRowIndex is the zero-based index of the row in the query result.
+	MetaKeyRowIndex = "_row_index"
+	// MetaKeyRow is the row as a map from column name to value, set by the default RowConverter.
+	MetaKeyRow = "_row"
+)
+
+// CallbackExtraKeySQL is the key of the executed SQL statement in the Extra of the callback output.
+const CallbackExtraKeySQL = "sql"
@@ -0,0 +1,325 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package text2sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RetrieverConfig contains configuration for the text-to-SQL retriever.
+type RetrieverConfig struct {
+	// DB is the database to query, e.g. opened with the database/sql driver of clickhouse-go,
+	// or with the MySQL driver for StarRocks.
+	// Use an account with read-only privileges, the statement check of the retriever is a second line of defense.
+	// Required.
+	DB *sql.DB
+
+	// Dialect is the SQL dialect of DB, told to the ChatModel.
+	// Required.
+	Dialect Dialect
+
+	// Tables declares the tables the ChatModel may query.
+	// Required.
+	Tables []*Table
+
+	// ChatModel generates the SQL statement from the natural-language query.
+	// Required.
+	ChatModel model.BaseChatModel
+
+	// SystemPrompt replaces the default instructions of the SQL generation prompt.
+	// The declared schema is always appended to it.
+	// Optional.
+	SystemPrompt string
+
+	// MaxRows is the max number of rows converted into documents, overridden by retriever.WithTopK.
+	// Default: 100
+	MaxRows int
+
+	// QueryTimeout is the timeout of executing the SQL statement.
+	// Optional. Default: 0, no timeout
+	QueryTimeout time.Duration
+
+	// DisableReadOnlyTx executes the SQL statement outside of a transaction,
+	// for drivers not supporting read-only transactions.
+	// Optional. Default: false, the statement is executed in a read-only transaction
+	DisableReadOnlyTx bool
+
+	// AllowedTables are the tables the SQL statement may read from, matched exactly against the references
+	// of the statement, e.g. "db.orders" only allows the qualified reference "db.orders".
+	// Table functions are never allowed.
+	// Optional. Default: the names of Tables
+	AllowedTables []string
+
+	// RowConverter converts a result row into a document.
+	// The provenance metadata (MetaKeySQL, MetaKeyDialect, MetaKeyRowIndex) is added to the returned document.
+	// If nil, uses the default conversion, see IDColumn.
+	RowConverter func(ctx context.Context, row *Row) (*schema.Document, error)
+
+	// IDColumn is the column used as the document ID by the default RowConverter.
+	// Optional. Default: the row index
+	IDColumn string
+}
+
+// Table declares a table the ChatModel may query.
+type Table struct {
+	// Name is the table name.
+	Name string
+	// Description explains the content of the table to the ChatModel.
+	Description string
+	// Columns are the columns of the table.
+	Columns []*Column
+}
+
+// Column declares a column of a Table.
+type Column struct {
+	// Name is the column name.
+	Name string
+	// Type is the SQL type of the column, e.g. "UInt64" or "DECIMAL(18, 2)".
+	Type string
+	// Description explains the content of the column to the ChatModel.
+	Description string
+}
+
+// Row is a row of the query result.
+type Row struct {
+	// SQL is the SQL statement the row was retrieved by.
+	SQL string
+	// Index is the zero-based index of the row in the query result.
+	Index int
+	// Columns are the column names of the result.
+	Columns []string
+	// Values are the values of the row, in the order of Columns.
+	// []byte values are converted to string.
+	Values []any
+}
+
+// Retriever implements the retriever.Retriever interface by generating SQL from the query with a ChatModel.
+type Retriever struct {
+	config        *RetrieverConfig
+	allowedTables map[string]bool
+}
+
+// NewRetriever creates a new text-to-SQL retriever with the provided configuration.
+// It returns an error if the configuration is invalid.
+func NewRetriever(_ context.Context, conf *RetrieverConfig) (*Retriever, error) {
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+	// the defaults are filled in on a copy, so that a config shared by several retrievers is not modified
+	config := *conf
+	config.setDefaults()
+	allowed := make(map[string]bool, len(config.AllowedTables))
+	for _, t := range config.AllowedTables {
+		allowed[t] = true
+	}
+	return &Retriever{config: &config, allowedTables: allowed}, nil
+}
+
+// Retrieve generates a read-only SQL statement for the query, executes it and converts the rows into documents.
+func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) (docs []*schema.Document, err error) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK: &r.config.MaxRows,
+	}, opts...)
+	maxRows := r.config.MaxRows
+	if co.TopK != nil && *co.TopK > 0 {
+		maxRows = *co.TopK
+	}
+
+	ctx = callbacks.EnsureRunInfo(ctx, r.GetType(), components.ComponentOfRetriever)
+	ctx = callbacks.OnStart(ctx, &retriever.CallbackInput{
+		Query: query,
+		TopK:  maxRows,
+	})
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	stmt, err := r.generateSQL(ctx, query, maxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.execute(ctx, stmt, maxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	docs = make([]*schema.Document, 0, len(rows))
+	for _, row := range rows {
+		doc, err := r.config.RowConverter(ctx, row)
+		if err != nil {
+			return nil, fmt.Errorf("[Retriever.Retrieve] failed to convert row %d: %w", row.Index, err)
+		}
+		if doc == nil {
+			continue
+		}
+		if doc.MetaData == nil {
+			doc.MetaData = make(map[string]any)
+		}
+		doc.MetaData[MetaKeySQL] = stmt
+		doc.MetaData[MetaKeyDialect] = string(r.config.Dialect)
+		doc.MetaData[MetaKeyRowIndex] = row.Index
+		docs = append(docs, doc)
+	}
+
+	callbacks.OnEnd(ctx, &retriever.CallbackOutput{
+		Docs:  docs,
+		Extra: map[string]any{CallbackExtraKeySQL: stmt},
+	})
+	return docs, nil
+}
+
+// generateSQL asks the ChatModel for the SQL statement of the query
+// and checks that it is read-only and only reads from the allowed tables.
+func (r *Retriever) generateSQL(ctx context.Context, query string, maxRows int) (string, error) {
+	msg, err := r.config.ChatModel.Generate(ctx, buildPrompt(r.config, query, maxRows))
+	if err != nil {
+		return "", fmt.Errorf("[Retriever.Retrieve] failed to generate sql: %w", err)
+	}
+
+	stmt, err := checkReadOnly(extractSQL(msg.Content))
+	if err != nil {
+		return "", fmt.Errorf("[Retriever.Retrieve] invalid generated sql: %w", err)
+	}
+	if err := checkTables(stmt, r.allowedTables); err != nil {
+		return "", fmt.Errorf("[Retriever.Retrieve] invalid generated sql: %w", err)
+	}
+	return stmt, nil
+}
+
+// execute runs the statement and reads at most maxRows rows.
+func (r *Retriever) execute(ctx context.Context, stmt string, maxRows int) ([]*Row, error) {
+	if r.config.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.QueryTimeout)
+		defer cancel()
+	}
+
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if !r.config.DisableReadOnlyTx {
+		var tx *sql.Tx
+		tx, err = r.config.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, fmt.Errorf("[Retriever.Retrieve] failed to begin read-only transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		rows, err = tx.QueryContext(ctx, stmt)
+	} else {
+		rows, err = r.config.DB.QueryContext(ctx, stmt)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[Retriever.Retrieve] failed to execute sql: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("[Retriever.Retrieve] failed to get columns: %w", err)
+	}
+
+	var result []*Row
+	for len(result) < maxRows && rows.Next() {
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("[Retriever.Retrieve] failed to scan row: %w", err)
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result = append(result, &Row{
+			SQL:     stmt,
+			Index:   len(result),
+			Columns: columns,
+			Values:  values,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("[Retriever.Retrieve] failed to read rows: %w", err)
+	}
+	return result, nil
+}
+
+// GetType returns the type of the retriever.
+func (r *Retriever) GetType() string {
+	return typ
+}
+
+// IsCallbacksEnabled checks if callbacks are enabled for this retriever.
+func (r *Retriever) IsCallbacksEnabled() bool {
+	return true
+}
+
+// validate checks the configuration.
+func (c *RetrieverConfig) validate() error {
+	if c == nil {
+		return fmt.Errorf("[NewRetriever] config is required")
+	}
+	if c.DB == nil {
+		return fmt.Errorf("[NewRetriever] db is required")
+	}
+	if c.Dialect == "" {
+		return fmt.Errorf("[NewRetriever] dialect is required")
+	}
+	if c.ChatModel == nil {
+		return fmt.Errorf("[NewRetriever] chat model is required")
+	}
+	if len(c.Tables) == 0 {
+		return fmt.Errorf("[NewRetriever] at least one table is required")
+	}
+	for _, t := range c.Tables {
+		if t == nil || t.Name == "" {
+			return fmt.Errorf("[NewRetriever] table name is required")
+		}
+	}
+	return nil
+}
+
+// setDefaults fills in the default values of the configuration.
+func (c *RetrieverConfig) setDefaults() {
+	if len(c.AllowedTables) == 0 {
+		c.AllowedTables = make([]string, 0, len(c.Tables))
+		for _, t := range c.Tables {
+			c.AllowedTables = append(c.AllowedTables, t.Name)
+		}
+	}
+	if c.MaxRows <= 0 {
+		c.MaxRows = defaultMaxRows
+	}
+	if c.RowConverter == nil {
+		c.RowConverter = defaultRowConverter(c.IDColumn)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package text2sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+type mockChatModel struct {
+	reply string
+	err   error
+	input []*schema.Message
+}
+
+func (m *mockChatModel) Generate(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	m.input = input
+	if m.err != nil {
+		return nil, m.err
+	}
+	return schema.AssistantMessage(m.reply, nil), nil
+}
+
+func (m *mockChatModel) Stream(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// fakeDriver serves the same result for every query, recording the executed queries.
+type fakeDriver struct {
+	columns []string
+	rows    [][]driver.Value
+	err     error
+	queries []string
+	// readOnlyTxs counts the read-only transactions begun.
+	readOnlyTxs int
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, fmt.Errorf("not implemented") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, fmt.Errorf("not implemented") }
+
+func (c *fakeConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if opts.ReadOnly {
+		c.d.readOnlyTxs++
+	}
+	return fakeTx{}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.d.queries = append(c.d.queries, query)
+	if c.d.err != nil {
+		return nil, c.d.err
+	}
+	return &fakeRows{columns: c.d.columns, rows: c.d.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+func newFakeDB(d *fakeDriver) *sql.DB {
+	return sql.OpenDB(&fakeConnector{d: d})
+}
+
+type fakeConnector struct{ d *fakeDriver }
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c *fakeConnector) Driver() driver.Driver                        { return c.d }
+
+func TestNewRetriever(t *testing.T) {
+	convey.Convey("test NewRetriever", t, func() {
+		ctx := context.Background()
+		db := newFakeDB(&fakeDriver{})
+		tables := []*Table{{Name: "orders"}}
+		cm := &mockChatModel{}
+
+		convey.Convey("test config validation", func() {
+			for _, tc := range []struct {
+				conf   *RetrieverConfig
+				reason string
+			}{
+				{nil, "config is required"},
+				{&RetrieverConfig{Dialect: DialectClickHouse, Tables: tables, ChatModel: cm}, "db is required"},
+				{&RetrieverConfig{DB: db, Tables: tables, ChatModel: cm}, "dialect is required"},
+				{&RetrieverConfig{DB: db, Dialect: DialectClickHouse, Tables: tables}, "chat model is required"},
+				{&RetrieverConfig{DB: db, Dialect: DialectClickHouse, ChatModel: cm}, "at least one table"},
+				{&RetrieverConfig{DB: db, Dialect: DialectClickHouse, ChatModel: cm, Tables: []*Table{{}}}, "table name is required"},
+			} {
+				ret, err := NewRetriever(ctx, tc.conf)
+				convey.So(err, convey.ShouldNotBeNil)
+				convey.So(err.Error(), convey.ShouldContainSubstring, tc.reason)
+				convey.So(ret, convey.ShouldBeNil)
+			}
+		})
+
+		convey.Convey("test defaults", func() {
+			conf := &RetrieverConfig{DB: db, Dialect: DialectStarRocks, Tables: tables, ChatModel: cm}
+			ret, err := NewRetriever(ctx, conf)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(ret.GetType(), convey.ShouldEqual, typ)
+			convey.So(ret.IsCallbacksEnabled(), convey.ShouldBeTrue)
+			convey.So(ret.config.MaxRows, convey.ShouldEqual, defaultMaxRows)
+			convey.So(ret.config.RowConverter, convey.ShouldNotBeNil)
+			convey.So(ret.config.AllowedTables, convey.ShouldNotBeEmpty)
+			// the caller's config is not modified
+			convey.So(conf.MaxRows, convey.ShouldEqual, 0)
+			convey.So(conf.RowConverter, convey.ShouldBeNil)
+			convey.So(conf.AllowedTables, convey.ShouldBeEmpty)
+		})
+	})
+}
+
+func TestRetriever_Retrieve(t *testing.T) {
+	convey.Convey("test Retriever.Retrieve", t, func() {
+		ctx := context.Background()
+		d := &fakeDriver{
+			columns: []string{"id", "name"},
+			rows: [][]driver.Value{
+				{int64(1), []byte("alice")},
+				{int64(2), []byte("bob")},
+				{int64(3), []byte("carol")},
+			},
+		}
+		cm := &mockChatModel{reply: "```sql\nSELECT id, name FROM users LIMIT 3;\n```"}
+		ret, err := NewRetriever(ctx, &RetrieverConfig{
+			DB:           newFakeDB(d),
+			Dialect:      DialectClickHouse,
+			Tables:       []*Table{{Name: "users"}},
+			ChatModel:    cm,
+			IDColumn:     "id",
+			QueryTimeout: time.Second,
+		})
+		convey.So(err, convey.ShouldBeNil)
+
+		convey.Convey("test success", func() {
+			docs, err := ret.Retrieve(ctx, "list users")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(d.queries, convey.ShouldResemble, []string{"SELECT id, name FROM users LIMIT 3"})
+			convey.So(d.readOnlyTxs, convey.ShouldEqual, 1)
+			convey.So(cm.input[1].Content, convey.ShouldEqual, "list users")
+			convey.So(len(docs), convey.ShouldEqual, 3)
+			convey.So(docs[1].ID, convey.ShouldEqual, "2")
+			convey.So(docs[1].Content, convey.ShouldEqual, "id: 2\nname: bob")
+			convey.So(docs[1].MetaData[MetaKeySQL], convey.ShouldEqual, "SELECT id, name FROM users LIMIT 3")
+			convey.So(docs[1].MetaData[MetaKeyDialect], convey.ShouldEqual, "ClickHouse")
+			convey.So(docs[1].MetaData[MetaKeyRowIndex], convey.ShouldEqual, 1)
+		})
+
+		convey.Convey("test top k caps rows", func() {
+			docs, err := ret.Retrieve(ctx, "list users", retriever.WithTopK(2))
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 2)
+			convey.So(cm.input[0].Content, convey.ShouldContainSubstring, "at most 2 rows")
+		})
+
+		convey.Convey("test custom row converter", func() {
+			ret.config.RowConverter = func(_ context.Context, row *Row) (*schema.Document, error) {
+				if row.Index == 0 {
+					return nil, nil
+				}
+				return &schema.Document{Content: row.Values[1].(string)}, nil
+			}
+			docs, err := ret.Retrieve(ctx, "list users")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(docs), convey.ShouldEqual, 2)
+			convey.So(docs[0].Content, convey.ShouldEqual, "bob")
+			convey.So(docs[0].MetaData[MetaKeyRowIndex], convey.ShouldEqual, 1)
+		})
+
+		convey.Convey("test generate error", func() {
+			cm.err = fmt.Errorf("model error")
+			_, err := ret.Retrieve(ctx, "list users")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "model error")
+		})
+
+		convey.Convey("test writing sql is not executed", func() {
+			cm.reply = "DROP TABLE users"
+			_, err := ret.Retrieve(ctx, "drop users")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "invalid generated sql")
+			convey.So(len(d.queries), convey.ShouldEqual, 0)
+		})
+
+		convey.Convey("test sql reading other tables is not executed", func() {
+			cm.reply = "SELECT * FROM secrets"
+			_, err := ret.Retrieve(ctx, "list secrets")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "table secrets is not allowed")
+			convey.So(len(d.queries), convey.ShouldEqual, 0)
+		})
+
+		convey.Convey("test allowed tables", func() {
+			ret, err := NewRetriever(ctx, &RetrieverConfig{
+				DB:            newFakeDB(d),
+				Dialect:       DialectClickHouse,
+				Tables:        []*Table{{Name: "users"}},
+				ChatModel:     cm,
+				AllowedTables: []string{"db.users"},
+			})
+			convey.So(err, convey.ShouldBeNil)
+			_, err = ret.Retrieve(ctx, "list users")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "table users is not allowed")
+
+			cm.reply = "SELECT id, name FROM db.users"
+			_, err = ret.Retrieve(ctx, "list users")
+			convey.So(err, convey.ShouldBeNil)
+		})
+
+		convey.Convey("test execute error", func() {
+			d.err = fmt.Errorf("syntax error")
+			_, err := ret.Retrieve(ctx, "list users")
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(err.Error(), convey.ShouldContainSubstring, "syntax error")
+		})
+
+		convey.Convey("test disable read-only transaction", func() {
+			ret.config.DisableReadOnlyTx = true
+			_, err := ret.Retrieve(ctx, "list users")
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(len(d.queries), convey.ShouldEqual, 1)
+			convey.So(d.readOnlyTxs, convey.ShouldEqual, 0)
+		})
+	})
+}
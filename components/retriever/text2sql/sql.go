@@ -0,0 +1,366 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package text2sql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// forbiddenKeywords are the keywords of statements modifying data, schema, privileges or server state.
+var forbiddenKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "UPSERT": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true,
+	"GRANT": true, "REVOKE": true, "SET": true, "USE": true, "KILL": true, "SYSTEM": true,
+	"ATTACH": true, "DETACH": true, "OPTIMIZE": true, "EXCHANGE": true, "UNDROP": true,
+	"INTO": true, "OUTFILE": true, "LOAD": true, "EXPORT": true, "SUBMIT": true, "INSTALL": true,
+	"CALL": true, "EXECUTE": true, "BACKUP": true, "RESTORE": true,
+}
+
+// tableFunctions are the table functions reading from files, URLs or other servers,
+// e.g. ClickHouse url(), file() and remote() or StarRocks FILES(), keyed in lower case.
+var tableFunctions = map[string]bool{
+	"url": true, "urlcluster": true, "file": true, "filecluster": true, "files": true,
+	"remote": true, "remotesecure": true, "cluster": true, "clusterallreplicas": true,
+	"s3": true, "s3cluster": true, "gcs": true, "oss": true, "cosn": true,
+	"azureblobstorage": true, "azureblobstoragecluster": true, "hdfs": true, "hdfscluster": true,
+	"mysql": true, "postgresql": true, "sqlite": true, "jdbc": true, "odbc": true,
+	"mongodb": true, "redis": true, "executable": true, "input": true,
+}
+
+// fromFunctions are the functions taking a FROM clause in their arguments, e.g. EXTRACT(YEAR FROM d).
+var fromFunctions = map[string]bool{
+	"EXTRACT": true, "TRIM": true, "SUBSTRING": true, "SUBSTR": true, "POSITION": true, "OVERLAY": true,
+}
+
+// buildPrompt builds the messages asking the ChatModel for the SQL statement of the query.
+func buildPrompt(conf *RetrieverConfig, query string, maxRows int) []*schema.Message {
+	instructions := conf.SystemPrompt
+	if instructions == "" {
+		instructions = fmt.Sprintf("You are an expert in %s SQL. "+
+			"Write a single read-only SELECT statement answering the question of the user, "+
+			"using only the tables and columns of the schema below.\n"+
+			"Return at most %d rows with a LIMIT clause.\n"+
+			"Reply with the SQL statement only, without explanation.", conf.Dialect, maxRows)
+	}
+	return []*schema.Message{
+		schema.SystemMessage(instructions + "\n\nSchema:\n" + describeTables(conf.Tables)),
+		schema.UserMessage(query),
+	}
+}
+
+// describeTables describes the declared tables to the ChatModel.
+func describeTables(tables []*Table) string {
+	var sb strings.Builder
+	for _, t := range tables {
+		sb.WriteString("Table ")
+		sb.WriteString(t.Name)
+		if t.Description != "" {
+			sb.WriteString(": ")
+			sb.WriteString(t.Description)
+		}
+		sb.WriteString("\n")
+		for _, c := range t.Columns {
+			sb.WriteString("  - ")
+			sb.WriteString(c.Name)
+			if c.Type != "" {
+				sb.WriteString(" ")
+				sb.WriteString(c.Type)
+			}
+			if c.Description != "" {
+				sb.WriteString(": ")
+				sb.WriteString(c.Description)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// extractSQL returns the SQL statement of the reply of the ChatModel,
+// which is the first fenced code block if there is one.
+func extractSQL(content string) string {
+	start := strings.Index(content, "```")
+	if start < 0 {
+		return strings.TrimSpace(content)
+	}
+	block := content[start+3:]
+	// skip the language tag, e.g. ```sql
+	if nl := strings.IndexByte(block, '\n'); nl >= 0 {
+		block = block[nl+1:]
+	}
+	if end := strings.Index(block, "```"); end >= 0 {
+		block = block[:end]
+	}
+	return strings.TrimSpace(block)
+}
+
+// checkReadOnly checks that stmt is a single SELECT statement without keywords of writing statements
+// nor table functions reading external data, and returns it without the trailing semicolons.
+func checkReadOnly(stmt string) (string, error) {
+	masked, err := maskSQL(stmt)
+	if err != nil {
+		return "", err
+	}
+
+	end := len(masked)
+	for {
+		trimmed := strings.TrimRight(masked[:end], " \t\r\n")
+		if !strings.HasSuffix(trimmed, ";") {
+			end = len(trimmed)
+			break
+		}
+		end = len(trimmed) - 1
+	}
+	masked = masked[:end]
+	if strings.Contains(masked, ";") {
+		return "", fmt.Errorf("multiple statements are not allowed")
+	}
+
+	words := strings.FieldsFunc(strings.ToUpper(masked), func(r rune) bool {
+		return !(r == '_' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if len(words) == 0 {
+		return "", fmt.Errorf("empty statement")
+	}
+	if words[0] != "SELECT" && words[0] != "WITH" {
+		return "", fmt.Errorf("only SELECT statements are allowed, got %s", words[0])
+	}
+	for _, w := range words {
+		if forbiddenKeywords[w] {
+			return "", fmt.Errorf("keyword %s is not allowed", w)
+		}
+	}
+	tokens := tokenize(stmt[:end], masked)
+	for i, t := range tokens {
+		if t.isName() && i+1 < len(tokens) && tokens[i+1].is("(") && tableFunctions[strings.ToLower(t.text)] {
+			return "", fmt.Errorf("table function %s is not allowed", t.text)
+		}
+	}
+	return strings.TrimSpace(stmt[:end]), nil
+}
+
+// checkTables checks that stmt only reads from the allowed tables or its common table expressions.
+func checkTables(stmt string, allowed map[string]bool) error {
+	masked, err := maskSQL(stmt)
+	if err != nil {
+		return err
+	}
+	tokens := tokenize(stmt, masked)
+
+	ctes := make(map[string]bool)
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i].isName() && tokens[i+1].isKeyword("AS") && tokens[i+2].is("(") {
+			ctes[tokens[i].text] = true
+		}
+	}
+
+	// inFromFunction records for every open parenthesis whether it opens the arguments of a function in fromFunctions.
+	var inFromFunction []bool
+	for i, t := range tokens {
+		switch {
+		case t.is("("):
+			inFromFunction = append(inFromFunction, i > 0 && tokens[i-1].kind == tokenWord &&
+				fromFunctions[strings.ToUpper(tokens[i-1].text)])
+		case t.is(")"):
+			if len(inFromFunction) > 0 {
+				inFromFunction = inFromFunction[:len(inFromFunction)-1]
+			}
+		case t.isKeyword("FROM") || t.isKeyword("JOIN"):
+			if len(inFromFunction) > 0 && inFromFunction[len(inFromFunction)-1] {
+				continue
+			}
+			// ARRAY JOIN joins a column, IS DISTINCT FROM compares values
+			if i > 0 && (tokens[i-1].isKeyword("ARRAY") || tokens[i-1].isKeyword("DISTINCT")) {
+				continue
+			}
+			if err := checkTableRefs(tokens[i+1:], allowed, ctes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkTableRefs checks the comma separated table references following a FROM or JOIN keyword.
+// Subqueries are left to the caller, which checks every FROM and JOIN of the statement.
+func checkTableRefs(tokens []token, allowed, ctes map[string]bool) error {
+	for i := 0; ; {
+		if i < len(tokens) && tokens[i].is("(") {
+			return nil
+		}
+		if i >= len(tokens) || !tokens[i].isName() {
+			return fmt.Errorf("missing table name")
+		}
+		name, qualified := tokens[i].text, false
+		for i++; i+1 < len(tokens) && tokens[i].is(".") && tokens[i+1].isName(); i += 2 {
+			name += "." + tokens[i+1].text
+			qualified = true
+		}
+		if i < len(tokens) && tokens[i].is("(") {
+			return fmt.Errorf("table function %s is not allowed", name)
+		}
+		if !allowed[name] && (qualified || !ctes[name]) {
+			return fmt.Errorf("table %s is not allowed", name)
+		}
+
+		// skip the alias
+		if i < len(tokens) && tokens[i].isKeyword("AS") {
+			i++
+		}
+		if i+1 < len(tokens) && tokens[i].isName() && tokens[i+1].is(",") {
+			i++
+		}
+		if i >= len(tokens) || !tokens[i].is(",") {
+			return nil
+		}
+		i++
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenQuoted
+	tokenString
+	tokenPunct
+)
+
+// token is a word, quoted identifier, string literal or punctuation character of a SQL statement.
+type token struct {
+	kind tokenKind
+	// text is the word, the unquoted identifier or the punctuation character, empty for string literals.
+	text string
+}
+
+func (t token) is(punct string) bool { return t.kind == tokenPunct && t.text == punct }
+
+func (t token) isKeyword(kw string) bool { return t.kind == tokenWord && strings.EqualFold(t.text, kw) }
+
+func (t token) isName() bool { return t.kind == tokenWord || t.kind == tokenQuoted }
+
+// tokenize splits stmt into tokens, using the masked statement of maskSQL to skip comments.
+func tokenize(stmt, masked string) []token {
+	var tokens []token
+	for i := 0; i < len(masked); {
+		c := masked[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1 + strings.IndexByte(masked[i+1:], c)
+			if c == '\'' {
+				tokens = append(tokens, token{kind: tokenString})
+			} else {
+				tokens = append(tokens, token{kind: tokenQuoted, text: stmt[i+1 : j]})
+			}
+			i = j + 1
+		case isWordByte(c):
+			j := i + 1
+			for j < len(masked) && isWordByte(masked[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: stmt[i:j]})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokenPunct, text: stmt[i : i+1]})
+			i++
+		}
+	}
+	return tokens
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// maskSQL replaces comments and the content of quoted strings and identifiers with spaces,
+// keeping the byte offsets of stmt.
+func maskSQL(stmt string) (string, error) {
+	masked := []byte(stmt)
+	for i := 0; i < len(masked); {
+		switch {
+		case masked[i] == '\'' || masked[i] == '"' || masked[i] == '`':
+			quote := masked[i]
+			j := i + 1
+			for ; j < len(masked) && masked[j] != quote; j++ {
+				if masked[j] == '\\' && j+1 < len(masked) {
+					masked[j] = ' '
+					j++
+				}
+				masked[j] = ' '
+			}
+			if j >= len(masked) {
+				return "", fmt.Errorf("unterminated quote %c", quote)
+			}
+			i = j + 1
+		case strings.HasPrefix(stmt[i:], "--") || masked[i] == '#':
+			for ; i < len(masked) && masked[i] != '\n'; i++ {
+				masked[i] = ' '
+			}
+		case strings.HasPrefix(stmt[i:], "/*"):
+			end := strings.Index(stmt[i+2:], "*/")
+			if end < 0 {
+				return "", fmt.Errorf("unterminated comment")
+			}
+			for j := i; j < i+2+end+2; j++ {
+				masked[j] = ' '
+			}
+			i += 2 + end + 2
+		default:
+			i++
+		}
+	}
+	return string(masked), nil
+}
+
+// defaultRowConverter converts a row into a document whose content lists the columns as "name: value" lines.
+func defaultRowConverter(idColumn string) func(ctx context.Context, row *Row) (*schema.Document, error) {
+	return func(_ context.Context, row *Row) (*schema.Document, error) {
+		doc := &schema.Document{
+			ID:       strconv.Itoa(row.Index),
+			MetaData: make(map[string]any, 1),
+		}
+
+		var sb strings.Builder
+		values := make(map[string]any, len(row.Columns))
+		for i, col := range row.Columns {
+			values[col] = row.Values[i]
+			if col == idColumn && row.Values[i] != nil {
+				doc.ID = fmt.Sprint(row.Values[i])
+			}
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(col)
+			sb.WriteString(": ")
+			if row.Values[i] == nil {
+				sb.WriteString("NULL")
+			} else {
+				sb.WriteString(fmt.Sprint(row.Values[i]))
+			}
+		}
+		doc.Content = sb.String()
+		doc.MetaData[MetaKeyRow] = values
+		return doc, nil
+	}
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package text2sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestCheckReadOnly(t *testing.T) {
+	convey.Convey("test checkReadOnly", t, func() {
+		convey.Convey("test allowed statements", func() {
+			for stmt, expected := range map[string]string{
+				"SELECT * FROM orders LIMIT 10":                              "SELECT * FROM orders LIMIT 10",
+				"select count(*) from orders;  ":                             "select count(*) from orders",
+				"WITH t AS (SELECT 1) SELECT * FROM t;;":                     "WITH t AS (SELECT 1) SELECT * FROM t",
+				"SELECT 'drop table; delete' AS s":                           "SELECT 'drop table; delete' AS s",
+				"SELECT `update` FROM t -- insert\n":                         "SELECT `update` FROM t",
+				"/* delete */ SELECT 1":                                      "/* delete */ SELECT 1",
+				"SELECT 'it''s', \"a\\\"b\" FROM t":                          "SELECT 'it''s', \"a\\\"b\" FROM t",
+				"SELECT name FROM t SETTINGS max_execution_time = 10":        "SELECT name FROM t SETTINGS max_execution_time = 10",
+				"SELECT replace(name, 'a', 'b') FROM t WHERE created_at > 1": "SELECT replace(name, 'a', 'b') FROM t WHERE created_at > 1",
+			} {
+				got, err := checkReadOnly(stmt)
+				convey.So(err, convey.ShouldBeNil)
+				convey.So(got, convey.ShouldEqual, expected)
+			}
+		})
+
+		convey.Convey("test rejected statements", func() {
+			for stmt, reason := range map[string]string{
+				"":                                                 "empty statement",
+				"-- comment only":                                  "empty statement",
+				"DELETE FROM orders":                               "only SELECT",
+				"SHOW TABLES":                                      "only SELECT",
+				"SELECT 1; DROP TABLE orders":                      "multiple statements",
+				"SELECT * FROM t INTO OUTFILE 'out.csv'":           "keyword INTO",
+				"WITH t AS (DELETE FROM x) SELECT 1":               "keyword DELETE",
+				"SELECT 'unterminated":                             "unterminated quote",
+				"SELECT 1 /* unterminated":                         "unterminated comment",
+				"select * from t; set global read_only=0;":         "multiple statements",
+				"SELECT * FROM url('http://x/a.csv', CSV)":         "table function url",
+				"SELECT * FROM t JOIN file ('a.csv') f ON 1":       "table function file",
+				"SELECT count() FROM remoteSecure('h:9440', db.t)": "table function remoteSecure",
+				"SELECT * FROM FILES(\"path\" = \"s3://b/a\")":     "table function FILES",
+			} {
+				_, err := checkReadOnly(stmt)
+				convey.So(err, convey.ShouldNotBeNil)
+				convey.So(err.Error(), convey.ShouldContainSubstring, reason)
+			}
+		})
+	})
+}
+
+func TestCheckTables(t *testing.T) {
+	convey.Convey("test checkTables", t, func() {
+		allowed := map[string]bool{"orders": true, "db.users": true}
+
+		convey.Convey("test allowed statements", func() {
+			for _, stmt := range []string{
+				"SELECT 1",
+				"SELECT * FROM orders",
+				"SELECT * FROM `orders` AS o, db.users u WHERE o.uid = u.id",
+				"SELECT * FROM orders o LEFT JOIN \"db\".\"users\" ON 1 = 1",
+				"SELECT * FROM (SELECT id FROM orders) t",
+				"WITH t AS (SELECT id FROM orders) SELECT * FROM t",
+				"SELECT EXTRACT(YEAR FROM created_at), TRIM(BOTH ' ' FROM name) FROM orders",
+				"SELECT tag FROM orders ARRAY JOIN tags AS tag",
+				"SELECT 'FROM secrets' FROM orders -- FROM secrets",
+			} {
+				convey.So(checkTables(stmt, allowed), convey.ShouldBeNil)
+			}
+		})
+
+		convey.Convey("test rejected statements", func() {
+			for stmt, reason := range map[string]string{
+				"SELECT * FROM secrets":                              "table secrets",
+				"SELECT * FROM users":                                "table users",
+				"SELECT * FROM orders, secrets":                      "table secrets",
+				"SELECT * FROM orders o, other.orders":               "table other.orders",
+				"SELECT * FROM orders JOIN system.tables ON 1":       "table system.tables",
+				"SELECT * FROM orders WHERE id IN (SELECT 1 FROM x)": "table x",
+				"SELECT * FROM numbers(10)":                          "table function numbers",
+				"WITH t AS (SELECT 1) SELECT * FROM db.t":            "table db.t",
+				"SELECT 1 FROM":                                      "missing table name",
+			} {
+				err := checkTables(stmt, allowed)
+				convey.So(err, convey.ShouldNotBeNil)
+				convey.So(err.Error(), convey.ShouldContainSubstring, reason)
+			}
+		})
+	})
+}
+
+func TestExtractSQL(t *testing.T) {
+	convey.Convey("test extractSQL", t, func() {
+		convey.So(extractSQL("  SELECT 1\n"), convey.ShouldEqual, "SELECT 1")
+		convey.So(extractSQL("Here it is:\n```sql\nSELECT 1\nFROM t\n```\nDone."), convey.ShouldEqual, "SELECT 1\nFROM t")
+		convey.So(extractSQL("```\nSELECT 2\n```"), convey.ShouldEqual, "SELECT 2")
+		convey.So(extractSQL("```sql\nSELECT 3"), convey.ShouldEqual, "SELECT 3")
+	})
+}
+
+func TestBuildPrompt(t *testing.T) {
+	convey.Convey("test buildPrompt", t, func() {
+		conf := &RetrieverConfig{
+			Dialect: DialectClickHouse,
+			Tables: []*Table{{
+				Name:        "orders",
+				Description: "Orders placed by customers",
+				Columns: []*Column{
+					{Name: "id", Type: "UInt64", Description: "order id"},
+					{Name: "amount", Type: "Decimal(18, 2)"},
+				},
+			}},
+		}
+
+		msgs := buildPrompt(conf, "total amount of orders", 20)
+		convey.So(len(msgs), convey.ShouldEqual, 2)
+		convey.So(msgs[0].Role, convey.ShouldEqual, schema.System)
+		convey.So(msgs[0].Content, convey.ShouldContainSubstring, "ClickHouse SQL")
+		convey.So(msgs[0].Content, convey.ShouldContainSubstring, "at most 20 rows")
+		convey.So(msgs[0].Content, convey.ShouldEndWith,
+			"Schema:\nTable orders: Orders placed by customers\n  - id UInt64: order id\n  - amount Decimal(18, 2)\n")
+		convey.So(msgs[1].Role, convey.ShouldEqual, schema.User)
+		convey.So(msgs[1].Content, convey.ShouldEqual, "total amount of orders")
+
+		conf.SystemPrompt = "Use StarRocks SQL."
+		msgs = buildPrompt(conf, "q", 20)
+		convey.So(msgs[0].Content, convey.ShouldStartWith, "Use StarRocks SQL.\n\nSchema:\nTable orders")
+	})
+}
+
+func TestDefaultRowConverter(t *testing.T) {
+	convey.Convey("test defaultRowConverter", t, func() {
+		ctx := context.Background()
+		row := &Row{
+			Index:   3,
+			Columns: []string{"id", "name", "amount"},
+			Values:  []any{int64(42), "alice", nil},
+		}
+
+		doc, err := defaultRowConverter("")(ctx, row)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(doc.ID, convey.ShouldEqual, "3")
+		convey.So(doc.Content, convey.ShouldEqual, "id: 42\nname: alice\namount: NULL")
+		convey.So(doc.MetaData[MetaKeyRow], convey.ShouldResemble, map[string]any{
+			"id": int64(42), "name": "alice", "amount": nil,
+		})
+
+		doc, err = defaultRowConverter("id")(ctx, row)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(doc.ID, convey.ShouldEqual, "42")
+	})
+}
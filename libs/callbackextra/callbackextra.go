@@ -0,0 +1,48 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package callbackextra defines the keys and values of the Extra of the callback input and output
+// shared by the model components, so that the traces of several providers look the same.
+package callbackextra
+
+const (
+	// KeyModelName is the key of the model version which served the request, the value is a string.
+	KeyModelName = "model_name"
+	// KeyThinkingConfig is the key of the thinking config of the request, the value is a *Thinking.
+	// The components may also report their own thinking config under another key, e.g. "thinking".
+	KeyThinkingConfig = "thinking_config"
+	// KeyCachedContent is the key of the name of the cached content used by the request, the value is a string.
+	KeyCachedContent = "cached_content"
+)
+
+// ThinkingType tells whether the model thinks before answering.
+type ThinkingType string
+
+const (
+	// ThinkingEnabled means the model always thinks.
+	ThinkingEnabled ThinkingType = "enabled"
+	// ThinkingDisabled means the model never thinks.
+	ThinkingDisabled ThinkingType = "disabled"
+	// ThinkingAuto means the model decides whether to think.
+	ThinkingAuto ThinkingType = "auto"
+)
+
+// Thinking is the thinking config of a request, as reported under KeyThinkingConfig.
+type Thinking struct {
+	Type ThinkingType `json:"type"`
+	// BudgetTokens is the max number of tokens the model thinks with, nil when the provider decides.
+	BudgetTokens *int `json:"budget_tokens,omitempty"`
+}
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbackextra
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestThinkingJSON(t *testing.T) {
+	budget := 1024
+	cases := []struct {
+		thinking *Thinking
+		expected string
+	}{
+		{thinking: &Thinking{Type: ThinkingAuto}, expected: `{"type":"auto"}`},
+		{thinking: &Thinking{Type: ThinkingEnabled, BudgetTokens: &budget}, expected: `{"type":"enabled","budget_tokens":1024}`},
+	}
+	for _, c := range cases {
+		b, err := json.Marshal(c.thinking)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != c.expected {
+			t.Errorf("expected %s, got %s", c.expected, b)
+		}
+	}
+}
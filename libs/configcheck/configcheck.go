@@ -0,0 +1,140 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package configcheck collects the problems of the configs of the components into a single error,
+// so that the applications validate all their configs at startup, before any client is constructed,
+// and fix every problem at once instead of one per run.
+package configcheck
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Issue is a problem of a config field.
+type Issue struct {
+	// Field is the path of the field at fault, e.g. "Vector.MetricType".
+	Field string
+	// Message describes the problem.
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Field == "" {
+		return i.Message
+	}
+	return i.Field + ": " + i.Message
+}
+
+// Report lists the issues of a config, it is the error returned by the Validate methods of the configs.
+type Report struct {
+	// Config is the name of the config, e.g. "milvus2.IndexerConfig".
+	Config string
+	// Issues are the problems found, in the order of the checks.
+	Issues []Issue
+}
+
+// New returns an empty report of the config.
+func New(config string) *Report {
+	return &Report{Config: config}
+}
+
+// Addf adds an issue of the field.
+func (r *Report) Addf(field, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Checkf adds an issue of the field unless ok.
+func (r *Report) Checkf(ok bool, field, format string, args ...interface{}) {
+	if !ok {
+		r.Addf(field, format, args...)
+	}
+}
+
+// AddError adds err as an issue of the field, the issues of a *Report wrapped in err are added under the field.
+// A nil err is ignored.
+func (r *Report) AddError(field string, err error) {
+	if err == nil {
+		return
+	}
+	var nested *Report
+	if !errors.As(err, &nested) {
+		r.Issues = append(r.Issues, Issue{Field: field, Message: err.Error()})
+		return
+	}
+	for _, issue := range nested.Issues {
+		switch {
+		case field == "":
+		case issue.Field == "":
+			issue.Field = field
+		default:
+			issue.Field = field + "." + issue.Field
+		}
+		r.Issues = append(r.Issues, issue)
+	}
+}
+
+// Err returns the report as an error, nil if there is no issue.
+func (r *Report) Err() error {
+	if r == nil || len(r.Issues) == 0 {
+		return nil
+	}
+	return r
+}
+
+func (r *Report) Error() string {
+	var sb strings.Builder
+	sb.WriteString(r.Config)
+	if len(r.Issues) == 1 {
+		sb.WriteString(": 1 issue: ")
+	} else {
+		sb.WriteString(fmt.Sprintf(": %d issues: ", len(r.Issues)))
+	}
+	for i, issue := range r.Issues {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(issue.String())
+	}
+	return sb.String()
+}
+
+// Number is the constraint of the numeric fields checked by InRange, Positive and NonNegative.
+type Number interface {
+	~int | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// InRange adds an issue of the field if v is set and out of [min, max].
+func InRange[T Number](r *Report, field string, v *T, min, max T) {
+	if v != nil && (*v < min || *v > max) {
+		r.Addf(field, "must be in [%v, %v], got %v", min, max, *v)
+	}
+}
+
+// Positive adds an issue of the field if v is set and not greater than zero.
+func Positive[T Number](r *Report, field string, v *T) {
+	if v != nil && *v <= 0 {
+		r.Addf(field, "must be positive, got %v", *v)
+	}
+}
+
+// NonNegative adds an issue of the field if v is set and negative.
+func NonNegative[T Number](r *Report, field string, v *T) {
+	if v != nil && *v < 0 {
+		r.Addf(field, "must not be negative, got %v", *v)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package configcheck
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	r := New("test.Config")
+	if r.Err() != nil {
+		t.Fatal("an empty report must not be an error")
+	}
+
+	r.Checkf(true, "Model", "model is required")
+	r.Checkf(false, "Model", "model is required")
+	r.Addf("", "credentials missing: set %s", "APIKey")
+
+	nested := New("test.Nested")
+	nested.Addf("Threshold", "must be positive, got %d", -1)
+	nested.Addf("", "nested problem")
+	r.AddError("Cache", nested.Err())
+	r.AddError("Limits", errors.New("invalid limits"))
+	wrapped := New("test.Wrapped")
+	wrapped.Addf("TTL", "must not be negative, got %d", -1)
+	r.AddError("Session", fmt.Errorf("invalid session: %w", wrapped.Err()))
+	r.AddError("Ignored", nil)
+
+	err := r.Err()
+	want := "test.Config: 6 issues: Model: model is required; credentials missing: set APIKey; " +
+		"Cache.Threshold: must be positive, got -1; Cache: nested problem; Limits: invalid limits; " +
+		"Session.TTL: must not be negative, got -1"
+	if err == nil || err.Error() != want {
+		t.Fatalf("Error() = %v, want %s", err, want)
+	}
+
+	var report *Report
+	if !errors.As(err, &report) || len(report.Issues) != 6 || report.Issues[2].Field != "Cache.Threshold" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	single := New("test.Config")
+	single.Addf("Model", "model is required")
+	if got := single.Error(); got != "test.Config: 1 issue: Model: model is required" {
+		t.Fatalf("Error() = %s", got)
+	}
+}
+
+func TestNumberChecks(t *testing.T) {
+	r := New("test.Config")
+	temperature, topP, maxTokens, timeout, retries := float32(2.5), 0.5, 0, -time.Second, 0
+	InRange(r, "Temperature", &temperature, 0, 2)
+	InRange(r, "TopP", &topP, 0, 1)
+	InRange[float64](r, "Unset", nil, 0, 1)
+	Positive(r, "MaxTokens", &maxTokens)
+	Positive(r, "Timeout", &timeout)
+	NonNegative(r, "RetryTimes", &retries)
+
+	want := "test.Config: 3 issues: Temperature: must be in [0, 2], got 2.5; MaxTokens: must be positive, got 0; " +
+		"Timeout: must be positive, got -1s"
+	if err := r.Err(); err == nil || err.Error() != want {
+		t.Fatalf("Error() = %v, want %s", err, want)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package finishreason normalizes the provider-specific finish reasons of the model components
+// into a small set of stable values, so that flow control does not depend on the provider.
+package finishreason
+
+import "strings"
+
+// The standard finish reasons, following the values of the OpenAI chat completion API.
+const (
+	// Stop means the model finished naturally or hit a stop sequence.
+	Stop = "stop"
+	// Length means the output was truncated by the max tokens or the context window.
+	Length = "length"
+	// ToolCalls means the model called tools.
+	ToolCalls = "tool_calls"
+	// ContentFilter means the output was blocked or truncated by the content moderation.
+	ContentFilter = "content_filter"
+	// Error means the generation was interrupted on the provider side, e.g. because of insufficient resources.
+	// The request can usually be retried.
+	Error = "error"
+)
+
+// Table maps provider-specific finish reasons to the standard ones.
+type Table map[string]string
+
+// Common is the table of the finish reasons shared by the providers, e.g. OpenAI, Anthropic, Gemini and Bedrock.
+// Normalize looks the reasons up case-insensitively, the keys are in lower case.
+var Common = Table{
+	Stop:            Stop,
+	"normal":        Stop,
+	"end_turn":      Stop,
+	"stop_sequence": Stop,
+
+	Length:                          Length,
+	"max_tokens":                    Length,
+	"max_output_tokens":             Length,
+	"model_length":                  Length,
+	"model_context_window_exceeded": Length,
+
+	ToolCalls:        ToolCalls,
+	"tool_call":      ToolCalls,
+	"tool-calls":     ToolCalls,
+	"tool_use":       ToolCalls,
+	"function_call":  ToolCalls,
+	"function_calls": ToolCalls,
+
+	ContentFilter:          ContentFilter,
+	"content-filter":       ContentFilter,
+	"content_filtered":     ContentFilter,
+	"sensitive":            ContentFilter,
+	"refusal":              ContentFilter,
+	"safety":               ContentFilter,
+	"recitation":           ContentFilter,
+	"blocklist":            ContentFilter,
+	"prohibited_content":   ContentFilter,
+	"spii":                 ContentFilter,
+	"image_safety":         ContentFilter,
+	"guardrail_intervened": ContentFilter,
+
+	"internal_error":          Error,
+	"malformed_function_call": Error,
+}
+
+// Merge returns a table with the entries of the tables, later tables take precedence.
+func Merge(tables ...Table) Table {
+	merged := make(Table)
+	for _, t := range tables {
+		for k, v := range t {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// Normalize maps the finish reason by the table, falling back to the lower-cased reason, e.g. "MAX_TOKENS" of Gemini.
+// Reasons not in the table are returned as is, and changed reports whether the reason was mapped to another value.
+func (t Table) Normalize(reason string) (normalized string, changed bool) {
+	if reason == "" {
+		return "", false
+	}
+	if v, ok := t[reason]; ok {
+		return v, v != reason
+	}
+	if v, ok := t[strings.ToLower(reason)]; ok {
+		return v, v != reason
+	}
+	return reason, false
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package finishreason
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	table := Merge(Common, Table{"insufficient_system_resource": Error, "normal": "custom"})
+
+	cases := []struct {
+		reason     string
+		normalized string
+		changed    bool
+	}{
+		{reason: "", normalized: "", changed: false},
+		{reason: Stop, normalized: Stop, changed: false},
+		{reason: "function_call", normalized: ToolCalls, changed: true},
+		{reason: "insufficient_system_resource", normalized: Error, changed: true},
+		{reason: "normal", normalized: "custom", changed: true},
+		{reason: "content-filter", normalized: ContentFilter, changed: true},
+		{reason: "refusal", normalized: ContentFilter, changed: true},
+		{reason: "SAFETY", normalized: ContentFilter, changed: true},
+		{reason: "MAX_TOKENS", normalized: Length, changed: true},
+		{reason: "STOP", normalized: Stop, changed: true},
+		{reason: "tool_use", normalized: ToolCalls, changed: true},
+		{reason: "tool_call", normalized: ToolCalls, changed: true},
+		{reason: "unknown", normalized: "unknown", changed: false},
+	}
+	for _, c := range cases {
+		normalized, changed := table.Normalize(c.reason)
+		if normalized != c.normalized || changed != c.changed {
+			t.Fatalf("Normalize(%q) = %q, %v, want %q, %v", c.reason, normalized, changed, c.normalized, c.changed)
+		}
+	}
+
+	if Common["normal"] != Stop {
+		t.Fatal("Merge must not modify the tables")
+	}
+}
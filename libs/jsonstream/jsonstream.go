@@ -0,0 +1,511 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package jsonstream incrementally parses the JSON documents streamed by the chat models,
+// e.g. the structured output of a JSON response format, reporting the values as soon as they are parsed.
+package jsonstream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Event is an event of the incremental parsing of the JSON output streamed by the model, see Parser.
+type Event struct {
+	// Path locates the value in the JSON document, made of object keys (string) and array indexes (int),
+	// e.g. ["items", 0, "name"]. The path of the whole document is empty.
+	Path []any
+	// Value is the parsed value, of the types encoding/json decodes into an interface value:
+	// string, float64, bool, nil, map[string]any or []any.
+	// For a partial string, it is the prefix of the string received so far.
+	Value any
+	// Partial reports whether Value is the prefix of a string still being streamed.
+	// The other values are only reported once complete, objects and arrays once their closing bracket is received.
+	Partial bool
+}
+
+// Parser assembles a JSON document from the chunks of a stream, reporting the values as soon as they are parsed,
+// so that the UIs progressively render the structured output of a model instead of waiting for the whole document.
+// It is not safe for concurrent use.
+type Parser struct {
+	stack  []*jsonFrame
+	root   any
+	offset int
+	err    error
+	events []*Event
+
+	lex       jsonLexState
+	isKey     bool
+	buf       []byte
+	hex       []byte
+	surrogate rune
+	emitted   int
+}
+
+type jsonFrameKind int
+
+const (
+	jsonFrameRoot jsonFrameKind = iota
+	jsonFrameObject
+	jsonFrameArray
+)
+
+type jsonFrameState int
+
+const (
+	jsonExpectValue jsonFrameState = iota
+	jsonExpectValueOrEnd
+	jsonExpectKey
+	jsonExpectKeyOrEnd
+	jsonExpectColon
+	jsonExpectCommaOrEnd
+	jsonDone
+)
+
+// jsonFrame is a container being parsed, holding its complete children.
+type jsonFrame struct {
+	kind  jsonFrameKind
+	state jsonFrameState
+	obj   map[string]any
+	arr   []any
+	key   string
+}
+
+type jsonLexState int
+
+const (
+	jsonLexNone jsonLexState = iota
+	jsonLexString
+	jsonLexEscape
+	jsonLexUnicode
+	jsonLexNumber
+	jsonLexLiteral
+)
+
+// NewParser returns a parser of a single JSON document.
+func NewParser() *Parser {
+	return &Parser{stack: []*jsonFrame{{kind: jsonFrameRoot}}}
+}
+
+// Feed parses the next chunk of the document, and returns the events of the values completed by the chunk,
+// followed by a partial event of the string value it ends in, if the string has grown.
+// Once an error is returned, the parser fails all the following calls with the same error.
+func (p *Parser) Feed(chunk string) ([]*Event, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.events = nil
+	for i := 0; i < len(chunk); {
+		consumed, err := p.step(chunk[i])
+		if err != nil {
+			p.err = err
+			return p.events, err
+		}
+		if consumed {
+			i++
+			p.offset++
+		}
+	}
+	p.emitPartial()
+	return p.events, nil
+}
+
+// Close ends the document, and returns the event of a trailing top-level number,
+// or an error wrapping io.ErrUnexpectedEOF if the document is incomplete.
+func (p *Parser) Close() ([]*Event, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.events = nil
+	if p.lex == jsonLexNumber {
+		if err := p.completeNumber(); err != nil {
+			p.err = err
+			return nil, err
+		}
+	}
+	if p.stack[0].state != jsonDone {
+		p.err = fmt.Errorf("incomplete JSON document at offset %d: %w", p.offset, io.ErrUnexpectedEOF)
+		return p.events, p.err
+	}
+	return p.events, nil
+}
+
+// Snapshot returns the document parsed so far, including the prefix of the string being streamed
+// but not the numbers and literals being parsed, e.g. {"items": [{"name": "Al"}]} after `{"items": [{"name": "Al`.
+// The objects and arrays of the snapshot are copies, which are not updated by the following chunks.
+func (p *Parser) Snapshot() any {
+	var child any
+	hasChild := p.inValueString()
+	if hasChild {
+		child = validUTF8Prefix(p.buf)
+	}
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		f := p.stack[i]
+		switch f.kind {
+		case jsonFrameRoot:
+			if f.state == jsonDone {
+				return p.root
+			}
+			return child
+		case jsonFrameObject:
+			obj := make(map[string]any, len(f.obj)+1)
+			for k, v := range f.obj {
+				obj[k] = v
+			}
+			if hasChild {
+				obj[f.key] = child
+			}
+			child = obj
+		case jsonFrameArray:
+			arr := make([]any, len(f.arr), len(f.arr)+1)
+			copy(arr, f.arr)
+			if hasChild {
+				arr = append(arr, child)
+			}
+			child = arr
+		}
+		hasChild = true
+	}
+	return child
+}
+
+// step handles the byte c, and reports whether it is consumed: a number ends at the byte following it,
+// which is then handled again as a structural byte.
+func (p *Parser) step(c byte) (bool, error) {
+	switch p.lex {
+	case jsonLexString:
+		switch {
+		case c == '"':
+			p.lex = jsonLexNone
+			p.flushSurrogate()
+			s := string(p.buf)
+			if p.isKey {
+				top := p.top()
+				top.key, top.state = s, jsonExpectColon
+				return true, nil
+			}
+			p.completeValue(s)
+		case c == '\\':
+			p.lex = jsonLexEscape
+		case c < 0x20:
+			return false, p.syntaxError(c, "in string")
+		default:
+			p.flushSurrogate()
+			p.buf = append(p.buf, c)
+		}
+		return true, nil
+	case jsonLexEscape:
+		if c == 'u' {
+			p.lex, p.hex = jsonLexUnicode, p.hex[:0]
+			return true, nil
+		}
+		p.flushSurrogate()
+		switch c {
+		case '"', '\\', '/':
+			p.buf = append(p.buf, c)
+		case 'b':
+			p.buf = append(p.buf, '\b')
+		case 'f':
+			p.buf = append(p.buf, '\f')
+		case 'n':
+			p.buf = append(p.buf, '\n')
+		case 'r':
+			p.buf = append(p.buf, '\r')
+		case 't':
+			p.buf = append(p.buf, '\t')
+		default:
+			return false, p.syntaxError(c, "in string escape")
+		}
+		p.lex = jsonLexString
+		return true, nil
+	case jsonLexUnicode:
+		if !isHexDigit(c) {
+			return false, p.syntaxError(c, "in \\u escape")
+		}
+		p.hex = append(p.hex, c)
+		if len(p.hex) == 4 {
+			n, _ := strconv.ParseUint(string(p.hex), 16, 32)
+			p.appendUnicode(rune(n))
+			p.lex = jsonLexString
+		}
+		return true, nil
+	case jsonLexNumber:
+		if isNumberByte(c) {
+			p.buf = append(p.buf, c)
+			return true, nil
+		}
+		return false, p.completeNumber()
+	case jsonLexLiteral:
+		p.buf = append(p.buf, c)
+		return true, p.scanLiteral()
+	}
+
+	if isSpace(c) {
+		return true, nil
+	}
+	top := p.top()
+	switch top.state {
+	case jsonDone:
+		return false, p.syntaxError(c, "after top-level value")
+	case jsonExpectKeyOrEnd, jsonExpectKey:
+		if c == '}' && top.state == jsonExpectKeyOrEnd {
+			p.closeContainer()
+			return true, nil
+		}
+		if c != '"' {
+			return false, p.syntaxError(c, "looking for beginning of object key string")
+		}
+		p.lex, p.isKey, p.buf = jsonLexString, true, p.buf[:0]
+	case jsonExpectColon:
+		if c != ':' {
+			return false, p.syntaxError(c, "after object key")
+		}
+		top.state = jsonExpectValue
+	case jsonExpectCommaOrEnd:
+		switch {
+		case c == ',' && top.kind == jsonFrameObject:
+			top.state = jsonExpectKey
+		case c == ',':
+			top.state = jsonExpectValue
+		case c == '}' && top.kind == jsonFrameObject, c == ']' && top.kind == jsonFrameArray:
+			p.closeContainer()
+		default:
+			return false, p.syntaxError(c, "after value")
+		}
+	case jsonExpectValueOrEnd, jsonExpectValue:
+		if c == ']' && top.state == jsonExpectValueOrEnd {
+			p.closeContainer()
+			return true, nil
+		}
+		return true, p.startValue(c)
+	}
+	return true, nil
+}
+
+func (p *Parser) startValue(c byte) error {
+	switch {
+	case c == '{':
+		p.stack = append(p.stack, &jsonFrame{kind: jsonFrameObject, state: jsonExpectKeyOrEnd, obj: map[string]any{}})
+	case c == '[':
+		p.stack = append(p.stack, &jsonFrame{kind: jsonFrameArray, state: jsonExpectValueOrEnd, arr: []any{}})
+	case c == '"':
+		p.lex, p.isKey, p.buf, p.emitted = jsonLexString, false, p.buf[:0], 0
+	case c == '-' || (c >= '0' && c <= '9'):
+		p.lex, p.buf = jsonLexNumber, append(p.buf[:0], c)
+	case c == 't' || c == 'f' || c == 'n':
+		p.lex, p.buf = jsonLexLiteral, append(p.buf[:0], c)
+	default:
+		return p.syntaxError(c, "looking for beginning of value")
+	}
+	return nil
+}
+
+func (p *Parser) completeNumber() error {
+	p.lex = jsonLexNone
+	if !json.Valid(p.buf) {
+		return fmt.Errorf("invalid JSON number %q at offset %d", p.buf, p.offset)
+	}
+	n, err := strconv.ParseFloat(string(p.buf), 64)
+	if err != nil {
+		return fmt.Errorf("invalid JSON number %q at offset %d: %w", p.buf, p.offset, err)
+	}
+	p.completeValue(n)
+	return nil
+}
+
+func (p *Parser) scanLiteral() error {
+	lit := string(p.buf)
+	for _, want := range [...]struct {
+		s string
+		v any
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if lit == want.s {
+			p.lex = jsonLexNone
+			p.completeValue(want.v)
+			return nil
+		}
+		if len(lit) < len(want.s) && want.s[:len(lit)] == lit {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid JSON literal %q at offset %d", lit, p.offset)
+}
+
+// completeValue reports the value and adds it to its container.
+func (p *Parser) completeValue(v any) {
+	top := p.top()
+	p.events = append(p.events, &Event{Path: p.path(), Value: v})
+	switch top.kind {
+	case jsonFrameRoot:
+		p.root, top.state = v, jsonDone
+	case jsonFrameObject:
+		top.obj[top.key], top.state = v, jsonExpectCommaOrEnd
+	case jsonFrameArray:
+		top.arr, top.state = append(top.arr, v), jsonExpectCommaOrEnd
+	}
+}
+
+func (p *Parser) closeContainer() {
+	f := p.top()
+	p.stack = p.stack[:len(p.stack)-1]
+	if f.kind == jsonFrameObject {
+		p.completeValue(f.obj)
+	} else {
+		p.completeValue(f.arr)
+	}
+}
+
+// emitPartial reports the prefix of the string value being streamed if it has grown since the last event.
+func (p *Parser) emitPartial() {
+	if !p.inValueString() {
+		return
+	}
+	s := validUTF8Prefix(p.buf)
+	if len(s) <= p.emitted {
+		return
+	}
+	p.emitted = len(s)
+	p.events = append(p.events, &Event{Path: p.path(), Value: s, Partial: true})
+}
+
+func (p *Parser) inValueString() bool {
+	return !p.isKey && (p.lex == jsonLexString || p.lex == jsonLexEscape || p.lex == jsonLexUnicode)
+}
+
+// path returns the path of the value being parsed in the top container.
+func (p *Parser) path() []any {
+	path := make([]any, 0, len(p.stack)-1)
+	for _, f := range p.stack[1:] {
+		if f.kind == jsonFrameObject {
+			path = append(path, f.key)
+		} else {
+			path = append(path, len(f.arr))
+		}
+	}
+	return path
+}
+
+func (p *Parser) top() *jsonFrame {
+	return p.stack[len(p.stack)-1]
+}
+
+// appendUnicode appends the rune of a \u escape, combining the UTF-16 surrogate pairs.
+func (p *Parser) appendUnicode(r rune) {
+	if p.surrogate != 0 {
+		combined := utf16.DecodeRune(p.surrogate, r)
+		p.surrogate = 0
+		if combined != utf8.RuneError {
+			p.buf = utf8.AppendRune(p.buf, combined)
+			return
+		}
+		p.buf = utf8.AppendRune(p.buf, utf8.RuneError)
+	}
+	if utf16.IsSurrogate(r) {
+		p.surrogate = r
+		return
+	}
+	p.buf = utf8.AppendRune(p.buf, r)
+}
+
+// flushSurrogate replaces a high surrogate not followed by a low one with the replacement character, as encoding/json does.
+func (p *Parser) flushSurrogate() {
+	if p.surrogate != 0 {
+		p.surrogate = 0
+		p.buf = utf8.AppendRune(p.buf, utf8.RuneError)
+	}
+}
+
+func (p *Parser) syntaxError(c byte, context string) error {
+	return fmt.Errorf("invalid character %q %s at offset %d", c, context, p.offset)
+}
+
+// validUTF8Prefix drops the incomplete UTF-8 sequence a chunk may end in.
+func validUTF8Prefix(b []byte) string {
+	for i := 0; i < utf8.UTFMax && i < len(b); i++ {
+		if utf8.Valid(b[:len(b)-i]) {
+			return string(b[:len(b)-i])
+		}
+	}
+	return string(b)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isNumberByte(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.' || c == 'e' || c == 'E'
+}
+
+// StreamEvents parses the content of the message stream of a chat model generating a JSON document,
+// e.g. with a JSON response format, into a stream of Event, see Parser. The reasoning content and the tool calls are ignored.
+// An incomplete or invalid document ends the stream with an error. Closing the returned stream closes sr.
+func StreamEvents(sr *schema.StreamReader[*schema.Message]) *schema.StreamReader[*Event] {
+	out, sw := schema.Pipe[*Event](1)
+	go func() {
+		defer func() {
+			if pe := recover(); pe != nil {
+				_ = sw.Send(nil, fmt.Errorf("jsonstream: panic: %v\nstack: %s", pe, debug.Stack()))
+			}
+			sr.Close()
+			sw.Close()
+		}()
+
+		parser := NewParser()
+		send := func(events []*Event, err error) bool {
+			for _, event := range events {
+				if closed := sw.Send(event, nil); closed {
+					return false
+				}
+			}
+			if err != nil {
+				_ = sw.Send(nil, err)
+				return false
+			}
+			return true
+		}
+		for {
+			msg, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				send(parser.Close())
+				return
+			}
+			if err != nil {
+				_ = sw.Send(nil, err)
+				return
+			}
+			if msg == nil || msg.Content == "" {
+				continue
+			}
+			if !send(parser.Feed(msg.Content)) {
+				return
+			}
+		}
+	}()
+	return out
+}
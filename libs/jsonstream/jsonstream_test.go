@@ -0,0 +1,198 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonstream
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func feed(t *testing.T, p *Parser, chunk string) []*Event {
+	t.Helper()
+	events, err := p.Feed(chunk)
+	if err != nil {
+		t.Fatalf("Feed(%q) failed: %v", chunk, err)
+	}
+	return events
+}
+
+func checkEvents(t *testing.T, got, expected []*Event) {
+	t.Helper()
+	if len(got) == 0 && len(expected) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("events = %s, expected %s", dumpEvents(got), dumpEvents(expected))
+	}
+}
+
+func dumpEvents(events []*Event) string {
+	data, _ := json.Marshal(events)
+	return string(data)
+}
+
+func TestParser(t *testing.T) {
+	t.Run("events", func(t *testing.T) {
+		p := NewParser()
+		checkEvents(t, feed(t, p, `{"title": "Hel`), []*Event{{Path: []any{"title"}, Value: "Hel", Partial: true}})
+		if expected := map[string]any{"title": "Hel"}; !reflect.DeepEqual(p.Snapshot(), expected) {
+			t.Errorf("Snapshot() = %v, expected %v", p.Snapshot(), expected)
+		}
+
+		checkEvents(t, feed(t, p, `lo", "items": [{"n": 1`), []*Event{{Path: []any{"title"}, Value: "Hello"}})
+		// the number may go on in the next chunk
+		if expected := map[string]any{"title": "Hello", "items": []any{map[string]any{}}}; !reflect.DeepEqual(p.Snapshot(), expected) {
+			t.Errorf("Snapshot() = %v, expected %v", p.Snapshot(), expected)
+		}
+
+		checkEvents(t, feed(t, p, `2, "ok": true}, null], "empty": {}}`), []*Event{
+			{Path: []any{"items", 0, "n"}, Value: float64(12)},
+			{Path: []any{"items", 0, "ok"}, Value: true},
+			{Path: []any{"items", 0}, Value: map[string]any{"n": float64(12), "ok": true}},
+			{Path: []any{"items", 1}, Value: nil},
+			{Path: []any{"items"}, Value: []any{map[string]any{"n": float64(12), "ok": true}, nil}},
+			{Path: []any{"empty"}, Value: map[string]any{}},
+			{Path: []any{}, Value: p.Snapshot()},
+		})
+
+		events, err := p.Close()
+		if err != nil {
+			t.Fatalf("Close() failed: %v", err)
+		}
+		checkEvents(t, events, nil)
+	})
+
+	t.Run("partial strings", func(t *testing.T) {
+		p := NewParser()
+		checkEvents(t, feed(t, p, `["a\"b\u00`), []*Event{{Path: []any{0}, Value: `a"b`, Partial: true}})
+
+		// no event until the string grows
+		checkEvents(t, feed(t, p, `e`), nil)
+
+		// the incomplete UTF-8 sequence of "中" is held back
+		checkEvents(t, feed(t, p, "9\xe4\xb8"), []*Event{{Path: []any{0}, Value: `a"bé`, Partial: true}})
+
+		checkEvents(t, feed(t, p, "\xad\\ud83d\\ude00\"]"), []*Event{
+			{Path: []any{0}, Value: `a"bé中😀`},
+			{Path: []any{}, Value: []any{`a"bé中😀`}},
+		})
+	})
+
+	t.Run("top-level number", func(t *testing.T) {
+		p := NewParser()
+		checkEvents(t, feed(t, p, `-1.5e`), nil)
+		feed(t, p, `2`)
+		events, err := p.Close()
+		if err != nil {
+			t.Fatalf("Close() failed: %v", err)
+		}
+		checkEvents(t, events, []*Event{{Path: []any{}, Value: -150.0}})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			name, input, err string
+		}{
+			{"bad value", `{"a": x}`, `invalid character 'x' looking for beginning of value at offset 6`},
+			{"missing colon", `{"a" 1}`, `invalid character '1' after object key at offset 5`},
+			{"bad literal", `[tru]`, `invalid JSON literal "tru]" at offset 4`},
+			{"bad number", `[1.]`, `invalid JSON number "1." at offset 3`},
+			{"trailing data", `{} {}`, `invalid character '{' after top-level value at offset 3`},
+			{"control character", "\"a\nb\"", `invalid character '\n' in string at offset 2`},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				p := NewParser()
+				_, err := p.Feed(tc.input)
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("Feed() error = %v, expected %s", err, tc.err)
+				}
+				// the parser stays failed
+				if _, err2 := p.Feed(`{}`); err2 != err {
+					t.Errorf("Feed() after the error = %v, expected %v", err2, err)
+				}
+			})
+		}
+
+		p := NewParser()
+		feed(t, p, `{"a": [1, 2`)
+		if _, err := p.Close(); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("Close() error = %v, expected io.ErrUnexpectedEOF", err)
+		}
+	})
+
+	t.Run("any chunking", func(t *testing.T) {
+		doc := `{"name": "Ark \"模型\"", "tags": ["a", "b\\n"], "score": 0.25, "nested": {"deep": [[1, -2e3], {}]}, "ok": false}`
+		var expected any
+		if err := json.Unmarshal([]byte(doc), &expected); err != nil {
+			t.Fatal(err)
+		}
+		for size := 1; size <= len(doc); size++ {
+			p := NewParser()
+			for i := 0; i < len(doc); i += size {
+				end := i + size
+				if end > len(doc) {
+					end = len(doc)
+				}
+				feed(t, p, doc[i:end])
+			}
+			if _, err := p.Close(); err != nil {
+				t.Fatalf("Close() with chunk size %d failed: %v", size, err)
+			}
+			if !reflect.DeepEqual(p.Snapshot(), expected) {
+				t.Errorf("Snapshot() with chunk size %d = %v, expected %v", size, p.Snapshot(), expected)
+			}
+		}
+	})
+}
+
+func TestStreamEvents(t *testing.T) {
+	sr := schema.StreamReaderFromArray([]*schema.Message{
+		{Role: schema.Assistant, ReasoningContent: "thinking"},
+		{Role: schema.Assistant, Content: `{"answer": "fo`},
+		{Role: schema.Assistant, Content: `o"}`},
+	})
+	out := StreamEvents(sr)
+	defer out.Close()
+
+	var events []*Event
+	for {
+		event, err := out.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() failed: %v", err)
+		}
+		events = append(events, event)
+	}
+	checkEvents(t, events, []*Event{
+		{Path: []any{"answer"}, Value: "fo", Partial: true},
+		{Path: []any{"answer"}, Value: "foo"},
+		{Path: []any{}, Value: map[string]any{"answer": "foo"}},
+	})
+
+	out = StreamEvents(schema.StreamReaderFromArray([]*schema.Message{{Content: `{"answer": `}}))
+	defer out.Close()
+	if _, err := out.Recv(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Recv() error = %v, expected io.ErrUnexpectedEOF", err)
+	}
+}
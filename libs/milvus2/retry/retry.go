@@ -0,0 +1,140 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package retry retries the Milvus calls failed with a transient error.
+// It is shared by the milvus2 indexer and retriever, so that both classify the errors the same way.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+)
+
+// transientErrors are the Milvus errors which are expected to succeed when retried later.
+var transientErrors = []error{
+	merr.ErrServiceNotReady,
+	merr.ErrServiceUnavailable,
+	merr.ErrServiceTooManyRequests,
+	merr.ErrServiceRateLimit,
+	merr.ErrServiceResourceInsufficient,
+	merr.ErrCollectionNotFullyLoaded,
+	merr.ErrCollectionOnRecovering,
+}
+
+// Policy configures the retries of the Milvus calls failed with a transient error,
+// e.g. the rate limit of Milvus is exceeded or a node is unavailable.
+// Permanent errors, e.g. a schema mismatch, are returned immediately.
+// The zero value of a field means its default, a Policy is never modified by Do.
+type Policy struct {
+	// MaxAttempts is the max number of attempts, including the first one.
+	// Optional. Default: 3
+	MaxAttempts int
+
+	// InitialBackoff is the backoff before the first retry, doubled after each retry.
+	// Optional. Default: 200ms
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the backoff between retries.
+	// Optional. Default: 5s
+	MaxBackoff time.Duration
+
+	// IsRetryable reports whether an error is transient.
+	// Optional. Default: IsTransientError
+	IsRetryable func(err error) bool
+}
+
+// Validate checks that the fields of the policy are not negative.
+func (p *Policy) Validate() error {
+	if p.MaxAttempts < 0 || p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return fmt.Errorf("retry policy must not be negative")
+	}
+	return nil
+}
+
+// withDefaults returns a copy of the policy with the zero fields set to their defaults.
+func (p Policy) withDefaults() Policy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = defaultMaxAttempts
+	}
+	if p.InitialBackoff == 0 {
+		p.InitialBackoff = defaultInitialBackoff
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = defaultMaxBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	if p.IsRetryable == nil {
+		p.IsRetryable = IsTransientError
+	}
+	return p
+}
+
+// IsTransientError reports whether err is a transient error of Milvus, e.g. the rate limit is exceeded,
+// the service is not ready or unavailable, or the gRPC connection is unavailable.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, transient := range transientErrors {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// Do calls fn until it succeeds, fails with an error not retryable by the policy, the attempts are exhausted
+// or ctx is done. It returns the result and the error of the last attempt. A nil policy calls fn once.
+func Do[T any](ctx context.Context, policy *Policy, fn func() (T, error)) (T, error) {
+	result, err := fn()
+	if policy == nil {
+		return result, err
+	}
+	p := policy.withDefaults()
+	backoff := p.InitialBackoff
+	for attempt := 1; attempt < p.MaxAttempts && err != nil && ctx.Err() == nil && p.IsRetryable(err); attempt++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+		result, err = fn()
+	}
+	return result, err
+}
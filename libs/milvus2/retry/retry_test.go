@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/milvus-io/milvus/pkg/v2/util/merr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsTransientError(t *testing.T) {
+	cases := []struct {
+		err       error
+		transient bool
+	}{
+		{err: fmt.Errorf("upsert: %w", merr.WrapErrServiceRateLimit(10)), transient: true},
+		{err: merr.WrapErrServiceUnavailable("node down"), transient: true},
+		{err: status.Error(codes.Unavailable, "connection refused"), transient: true},
+		{err: merr.WrapErrCollectionNotFound("c"), transient: false},
+		{err: status.Error(codes.InvalidArgument, "bad"), transient: false},
+		{err: errors.New("schema mismatch"), transient: false},
+		{err: nil, transient: false},
+	}
+	for _, c := range cases {
+		if got := IsTransientError(c.err); got != c.transient {
+			t.Errorf("IsTransientError(%v) = %v, want %v", c.err, got, c.transient)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Policy{MaxAttempts: -1}).Validate(); err == nil {
+		t.Error("negative MaxAttempts should be rejected")
+	}
+	if err := (&Policy{}).Validate(); err != nil {
+		t.Errorf("zero policy should be valid: %v", err)
+	}
+}
+
+func TestDo(t *testing.T) {
+	ctx := context.Background()
+	policy := &Policy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	calls := 0
+	result, err := Do(ctx, policy, func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, merr.WrapErrServiceRateLimit(10)
+		}
+		return calls, nil
+	})
+	if err != nil || result != 3 {
+		t.Fatalf("transient errors should be retried, got %d, %v", result, err)
+	}
+	if policy.MaxBackoff != 0 || policy.IsRetryable != nil {
+		t.Errorf("policy should not be modified: %+v", *policy)
+	}
+
+	calls = 0
+	_, err = Do(ctx, policy, func() (int, error) {
+		calls++
+		return 0, merr.WrapErrServiceUnavailable("node down")
+	})
+	if err == nil || calls != 3 {
+		t.Errorf("attempts should be exhausted, got %d calls, %v", calls, err)
+	}
+
+	calls = 0
+	_, err = Do(ctx, policy, func() (int, error) {
+		calls++
+		return 0, merr.WrapErrCollectionNotFound("c")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("permanent errors should be returned immediately, got %d calls, %v", calls, err)
+	}
+
+	calls = 0
+	_, _ = Do(ctx, nil, func() (int, error) {
+		calls++
+		return 0, merr.WrapErrServiceRateLimit(10)
+	})
+	if calls != 1 {
+		t.Errorf("nil policy should call once, got %d calls", calls)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	calls = 0
+	_, err = Do(cctx, &Policy{MaxAttempts: 3, InitialBackoff: time.Hour}, func() (int, error) {
+		calls++
+		return 0, merr.WrapErrServiceRateLimit(10)
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("retries should stop when the context is done, got %d calls, %v", calls, err)
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	// uncapped, the backoffs would be 50ms, 100ms and 200ms
+	start := time.Now()
+	calls := 0
+	_, _ = Do(context.Background(), &Policy{MaxAttempts: 4, InitialBackoff: 50 * time.Millisecond, MaxBackoff: 50 * time.Millisecond},
+		func() (int, error) {
+			calls++
+			return 0, merr.WrapErrServiceRateLimit(10)
+		})
+	if calls != 4 {
+		t.Fatalf("expected 4 attempts, got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed >= 300*time.Millisecond {
+		t.Errorf("backoff should be capped, waited %v", elapsed)
+	}
+}
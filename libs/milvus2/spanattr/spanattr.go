@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package spanattr converts the callback extra of the Milvus components into OpenTelemetry span attributes.
+// It is shared by the milvus2 indexer and retriever, so that both report the same attributes the same way.
+package spanattr
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// System is the "db.system" attribute set first by SpanAttributes.
+var System = attribute.String("db.system", "milvus")
+
+// Key maps a key of the callback extra to an OpenTelemetry attribute key.
+type Key struct {
+	Extra string
+	Attr  attribute.Key
+}
+
+// SpanAttributes converts the values of keys found in extra into span attributes, in the order of keys,
+// after the System attribute. string, []string, int and int64 values are converted as is
+// and time.Duration values in milliseconds. Values of other types and unknown keys are ignored.
+func SpanAttributes(extra map[string]any, keys []Key) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{System}
+	for _, k := range keys {
+		switch v := extra[k.Extra].(type) {
+		case string:
+			attrs = append(attrs, k.Attr.String(v))
+		case []string:
+			attrs = append(attrs, k.Attr.StringSlice(v))
+		case int:
+			attrs = append(attrs, k.Attr.Int(v))
+		case int64:
+			attrs = append(attrs, k.Attr.Int64(v))
+		case time.Duration:
+			attrs = append(attrs, k.Attr.Int64(v.Milliseconds()))
+		}
+	}
+	return attrs
+}
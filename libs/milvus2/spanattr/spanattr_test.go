@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package spanattr
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSpanAttributes(t *testing.T) {
+	keys := []Key{
+		{Extra: "collection", Attr: "db.collection.name"},
+		{Extra: "partitions", Attr: "milvus.partitions"},
+		{Extra: "count", Attr: "milvus.count"},
+		{Extra: "rows", Attr: "milvus.rows"},
+		{Extra: "latency", Attr: "milvus.latency_ms"},
+		{Extra: "missing", Attr: "milvus.missing"},
+	}
+	attrs := SpanAttributes(map[string]any{
+		"collection": "c",
+		"partitions": []string{"p1", "p2"},
+		"count":      3,
+		"rows":       int64(42),
+		"latency":    1500 * time.Millisecond,
+		"unknown":    "ignored",
+	}, keys)
+	want := []attribute.KeyValue{
+		System,
+		attribute.String("db.collection.name", "c"),
+		attribute.StringSlice("milvus.partitions", []string{"p1", "p2"}),
+		attribute.Int("milvus.count", 3),
+		attribute.Int64("milvus.rows", 42),
+		attribute.Int64("milvus.latency_ms", 1500),
+	}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("SpanAttributes() = %v, want %v", attrs, want)
+	}
+
+	if attrs := SpanAttributes(nil, keys); !reflect.DeepEqual(attrs, []attribute.KeyValue{System}) {
+		t.Errorf("SpanAttributes(nil) = %v", attrs)
+	}
+}
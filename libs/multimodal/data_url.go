@@ -0,0 +1,86 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package multimodal provides the media handling shared by the model components:
+// data URL generation and parsing, base64 normalization, media limits and video metadata.
+package multimodal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// DataURL builds a "data:<mimeType>;base64,<data>" URL from raw base64 data.
+// It rejects data which is already a data URL, so that the prefix is never duplicated.
+func DataURL(base64Data, mimeType string) (string, error) {
+	if strings.HasPrefix(base64Data, "data:") {
+		return "", fmt.Errorf("base64Data field must be a raw base64 string, but got a string with prefix 'data:'")
+	}
+	if mimeType == "" {
+		return "", fmt.Errorf("mimeType field is required")
+	}
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64Data), nil
+}
+
+// EncodeDataURL encodes data into a base64 data URL.
+// The MIME type is sniffed from data if mimeType is empty.
+func EncodeDataURL(data []byte, mimeType string) string {
+	if mimeType == "" {
+		mimeType = SniffMIMEType(data)
+	}
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(data))
+}
+
+// ParseDataURL splits a base64 data URL into its MIME type and raw base64 data.
+func ParseDataURL(dataURL string) (mimeType, base64Data string, err error) {
+	if !strings.HasPrefix(dataURL, "data:") {
+		return "", "", fmt.Errorf("invalid data URL: missing 'data:' prefix")
+	}
+	meta, data, found := strings.Cut(dataURL[len("data:"):], ",")
+	if !found {
+		return "", "", fmt.Errorf("invalid data URL: missing ','")
+	}
+	if !strings.HasSuffix(meta, ";base64") {
+		return "", "", fmt.Errorf("invalid data URL: only base64 encoding is supported")
+	}
+	return strings.TrimSuffix(meta, ";base64"), data, nil
+}
+
+// DecodeBase64 decodes raw base64 data or a base64 data URL into bytes.
+// Web URLs are rejected, use Fetch to download their content.
+func DecodeBase64(data string) ([]byte, error) {
+	if strings.HasPrefix(data, "http://") || strings.HasPrefix(data, "https://") {
+		return nil, fmt.Errorf("invalid input: expected base64 data or data URL, but got a web URL starting with 'http'. Please fetch the content from the URL first")
+	}
+	if strings.HasPrefix(data, "data:") {
+		_, b64, err := ParseDataURL(data)
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 data from data URL: %w", err)
+		}
+		return decoded, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode raw base64 data: %w", err)
+	}
+	return decoded, nil
+}
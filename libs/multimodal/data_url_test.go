@@ -0,0 +1,80 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multimodal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDataURL(t *testing.T) {
+	got, err := DataURL("aGVsbG8=", "text/plain")
+	if err != nil || got != "data:text/plain;base64,aGVsbG8=" {
+		t.Fatalf("unexpected data URL: %s, %v", got, err)
+	}
+
+	if _, err = DataURL("data:text/plain;base64,aGVsbG8=", "text/plain"); err == nil {
+		t.Fatal("expected error for data URL input")
+	}
+	if _, err = DataURL("aGVsbG8=", ""); err == nil {
+		t.Fatal("expected error for empty MIME type")
+	}
+}
+
+func TestParseDataURL(t *testing.T) {
+	mimeType, data, err := ParseDataURL("data:image/png;base64,AAAA")
+	if err != nil || mimeType != "image/png" || data != "AAAA" {
+		t.Fatalf("unexpected result: %s, %s, %v", mimeType, data, err)
+	}
+
+	for _, in := range []string{"AAAA", "data:image/png;base64", "data:text/plain,hello"} {
+		if _, _, err = ParseDataURL(in); err == nil {
+			t.Fatalf("expected error for %q", in)
+		}
+	}
+}
+
+func TestDecodeBase64(t *testing.T) {
+	for _, in := range []string{"aGVsbG8=", "data:text/plain;base64,aGVsbG8="} {
+		got, err := DecodeBase64(in)
+		if err != nil || string(got) != "hello" {
+			t.Fatalf("unexpected result of %q: %s, %v", in, got, err)
+		}
+	}
+
+	_, err := DecodeBase64("https://example.com/a.png")
+	if err == nil || !strings.Contains(err.Error(), "web URL") {
+		t.Fatalf("expected web URL error, got %v", err)
+	}
+	if _, err = DecodeBase64("not base64!"); err == nil {
+		t.Fatal("expected decode error")
+	}
+}
+
+func TestEncodeDataURL(t *testing.T) {
+	png := append(append([]byte{}, pngSignature...), bytes.Repeat([]byte{0}, 8)...)
+	got := EncodeDataURL(png, "")
+	if !strings.HasPrefix(got, "data:image/png;base64,") {
+		t.Fatalf("unexpected data URL: %s", got)
+	}
+
+	decoded, err := DecodeBase64(got)
+	if err != nil || !bytes.Equal(decoded, png) {
+		t.Fatalf("round trip failed: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multimodal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Limits bounds the multimodal payloads of the input messages.
+// The model components check them before the request is sent, so invalid media fails fast
+// with a ValidationError naming the field at fault instead of deep inside the provider SDK.
+// The zero value of a limit means unlimited.
+type Limits struct {
+	// MaxPartsPerMessage is the max number of the content parts of a message.
+	MaxPartsPerMessage int
+	// MaxInlineBytes is the max decoded size in bytes of the media sent inline, as base64 data or a data URL.
+	MaxInlineBytes int64
+	// AllowedMIMETypes are the MIME types the media may have, e.g. "image/png" or "image/*".
+	// The MIME type of inline media without one is sniffed from the data,
+	// web URLs without a MIME type are not checked as the content is not fetched.
+	AllowedMIMETypes []string
+}
+
+// MediaRef is a media part of a message to check, given by URL or base64 data.
+type MediaRef struct {
+	URL        string
+	Base64Data string
+	MIMEType   string
+}
+
+// ValidationError reports the field of the messages violating the Limits,
+// e.g. "messages[1].user_input_multi_content[2].image".
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// CheckPartCount checks that the message of field does not have more than MaxPartsPerMessage parts.
+func (l *Limits) CheckPartCount(field string, n int) error {
+	if l == nil || l.MaxPartsPerMessage <= 0 || n <= l.MaxPartsPerMessage {
+		return nil
+	}
+	return &ValidationError{
+		Field:  field,
+		Reason: fmt.Sprintf("%d content parts exceed the limit of %d", n, l.MaxPartsPerMessage),
+	}
+}
+
+// CheckMedia checks that the media of field is well-formed and within the limits:
+// the base64 data and data URLs must decode, inline media must not exceed MaxInlineBytes,
+// and the MIME type must match AllowedMIMETypes.
+func (l *Limits) CheckMedia(field string, m MediaRef) error {
+	if l == nil {
+		return nil
+	}
+
+	mimeType, b64 := m.MIMEType, m.Base64Data
+	if b64 == "" && strings.HasPrefix(m.URL, "data:") {
+		urlMIMEType, data, err := ParseDataURL(m.URL)
+		if err != nil {
+			return &ValidationError{Field: field, Reason: err.Error()}
+		}
+		if mimeType == "" {
+			mimeType = urlMIMEType
+		}
+		b64 = data
+	}
+
+	if b64 != "" {
+		if strings.HasPrefix(b64, "data:") {
+			return &ValidationError{Field: field, Reason: "base64 data must be a raw base64 string, but got a data URL"}
+		}
+		// the size is checked before decoding, so oversized payloads are not decoded
+		padding := len(b64) - len(strings.TrimRight(b64, "="))
+		if size := int64(base64.StdEncoding.DecodedLen(len(b64)) - padding); l.MaxInlineBytes > 0 && size > l.MaxInlineBytes {
+			return &ValidationError{
+				Field:  field,
+				Reason: fmt.Sprintf("inline media of %d bytes exceeds the limit of %d bytes", size, l.MaxInlineBytes),
+			}
+		}
+		data, err := base64.StdEncoding.DecodeString(b64)
+		if err != nil {
+			return &ValidationError{Field: field, Reason: fmt.Sprintf("malformed base64 data: %v", err)}
+		}
+		if mimeType == "" && len(l.AllowedMIMETypes) > 0 {
+			mimeType = SniffMIMEType(data)
+		}
+	}
+
+	if len(l.AllowedMIMETypes) > 0 && mimeType != "" && !matchMIMEType(mimeType, l.AllowedMIMETypes) {
+		return &ValidationError{
+			Field:  field,
+			Reason: fmt.Sprintf("MIME type %s is not allowed, allowed: %s", mimeType, strings.Join(l.AllowedMIMETypes, ", ")),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multimodal
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	png := append(append([]byte{}, pngSignature...), pngChunk("IEND", nil)...)
+	pngB64 := base64.StdEncoding.EncodeToString(png)
+
+	var nilLimits *Limits
+	if err := nilLimits.CheckMedia("f", MediaRef{Base64Data: "!!"}); err != nil {
+		t.Fatalf("nil limits must not check, got %v", err)
+	}
+
+	limits := &Limits{MaxPartsPerMessage: 2, MaxInlineBytes: int64(len(png)), AllowedMIMETypes: []string{"image/*"}}
+	if err := limits.CheckPartCount("messages[0]", 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := limits.CheckPartCount("messages[0]", 3); err == nil || !strings.Contains(err.Error(), "messages[0]") {
+		t.Fatalf("expected part count error, got %v", err)
+	}
+
+	valid := []MediaRef{
+		{Base64Data: pngB64},
+		{Base64Data: pngB64, MIMEType: "image/png"},
+		{URL: "data:image/png;base64," + pngB64},
+		{URL: "https://example.com/a.png"},
+		{URL: "https://example.com/a", MIMEType: "image/jpeg"},
+	}
+	for _, m := range valid {
+		if err := limits.CheckMedia("f", m); err != nil {
+			t.Fatalf("unexpected error for %+v: %v", m, err)
+		}
+	}
+
+	invalid := map[string]MediaRef{
+		"malformed base64 data":     {Base64Data: "not base64!"},
+		"must be a raw base64":      {Base64Data: "data:image/png;base64," + pngB64},
+		"missing ','":               {URL: "data:image/png;base64"},
+		"exceeds the limit":         {Base64Data: base64.StdEncoding.EncodeToString(append(png, 0))},
+		"MIME type video/mp4":       {URL: "https://example.com/a.mp4", MIMEType: "video/mp4"},
+		"MIME type application/pdf": {URL: "data:application/pdf;base64," + pngB64},
+		"MIME type text/plain":      {Base64Data: base64.StdEncoding.EncodeToString([]byte("hello"))},
+	}
+	for reason, m := range invalid {
+		err := limits.CheckMedia("messages[1].user_input_multi_content[0].image", m)
+		var ve *ValidationError
+		if !errors.As(err, &ve) || !strings.Contains(ve.Reason, reason) {
+			t.Fatalf("expected error with %q, got %v", reason, err)
+		}
+		if ve.Field != "messages[1].user_input_multi_content[0].image" {
+			t.Fatalf("unexpected field: %s", ve.Field)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multimodal
+
+import (
+	"fmt"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ValidateMessages checks the content parts of the input messages against the limits before they are converted,
+// returning a *ValidationError naming the field at fault, e.g. messages[1].user_input_multi_content[0].image.
+// A nil Limits accepts all the messages.
+func (l *Limits) ValidateMessages(msgs []*schema.Message) error {
+	if l == nil {
+		return nil
+	}
+	for i, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		if err := l.validateInputParts(fmt.Sprintf("messages[%d].user_input_multi_content", i), msg.UserInputMultiContent); err != nil {
+			return err
+		}
+		if err := l.validateOutputParts(fmt.Sprintf("messages[%d].assistant_gen_multi_content", i), msg.AssistantGenMultiContent); err != nil {
+			return err
+		}
+		if err := l.validateLegacyParts(fmt.Sprintf("messages[%d].multi_content", i), msg.MultiContent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Limits) validateInputParts(field string, parts []schema.MessageInputPart) error {
+	if err := l.CheckPartCount(field, len(parts)); err != nil {
+		return err
+	}
+	for j, part := range parts {
+		var (
+			kind   string
+			common *schema.MessagePartCommon
+		)
+		switch {
+		case part.Image != nil:
+			kind, common = "image", &part.Image.MessagePartCommon
+		case part.Audio != nil:
+			kind, common = "audio", &part.Audio.MessagePartCommon
+		case part.Video != nil:
+			kind, common = "video", &part.Video.MessagePartCommon
+		case part.File != nil:
+			kind, common = "file", &part.File.MessagePartCommon
+		default:
+			continue
+		}
+		if err := l.CheckMedia(fmt.Sprintf("%s[%d].%s", field, j, kind), mediaRefOf(common)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Limits) validateOutputParts(field string, parts []schema.MessageOutputPart) error {
+	if err := l.CheckPartCount(field, len(parts)); err != nil {
+		return err
+	}
+	for j, part := range parts {
+		var (
+			kind   string
+			common *schema.MessagePartCommon
+		)
+		switch {
+		case part.Image != nil:
+			kind, common = "image", &part.Image.MessagePartCommon
+		case part.Audio != nil:
+			kind, common = "audio", &part.Audio.MessagePartCommon
+		case part.Video != nil:
+			kind, common = "video", &part.Video.MessagePartCommon
+		default:
+			continue
+		}
+		if err := l.CheckMedia(fmt.Sprintf("%s[%d].%s", field, j, kind), mediaRefOf(common)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Limits) validateLegacyParts(field string, parts []schema.ChatMessagePart) error {
+	if err := l.CheckPartCount(field, len(parts)); err != nil {
+		return err
+	}
+	for j, part := range parts {
+		var (
+			kind string
+			ref  MediaRef
+		)
+		switch {
+		case part.ImageURL != nil:
+			kind, ref = "image_url", MediaRef{URL: part.ImageURL.URL, MIMEType: part.ImageURL.MIMEType}
+		case part.AudioURL != nil:
+			kind, ref = "audio_url", MediaRef{URL: part.AudioURL.URL, MIMEType: part.AudioURL.MIMEType}
+		case part.VideoURL != nil:
+			kind, ref = "video_url", MediaRef{URL: part.VideoURL.URL, MIMEType: part.VideoURL.MIMEType}
+		case part.FileURL != nil:
+			kind, ref = "file_url", MediaRef{URL: part.FileURL.URL, MIMEType: part.FileURL.MIMEType}
+		default:
+			continue
+		}
+		if err := l.CheckMedia(fmt.Sprintf("%s[%d].%s", field, j, kind), ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mediaRefOf(common *schema.MessagePartCommon) MediaRef {
+	ref := MediaRef{MIMEType: common.MIMEType}
+	if common.URL != nil {
+		ref.URL = *common.URL
+	}
+	if common.Base64Data != nil {
+		ref.Base64Data = *common.Base64Data
+	}
+	return ref
+}
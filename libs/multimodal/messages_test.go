@@ -0,0 +1,105 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multimodal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestValidateMessages(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	limits := &Limits{MaxPartsPerMessage: 2, MaxInlineBytes: 4, AllowedMIMETypes: []string{"image/*"}}
+
+	var nilLimits *Limits
+	if err := nilLimits.ValidateMessages([]*schema.Message{{
+		Role:                  schema.User,
+		UserInputMultiContent: []schema.MessageInputPart{{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{MessagePartCommon: schema.MessagePartCommon{Base64Data: ptr("!!")}}}},
+	}}); err != nil {
+		t.Fatalf("nil limits must not check, got %v", err)
+	}
+
+	if err := limits.ValidateMessages([]*schema.Message{
+		schema.UserMessage("hi"),
+		nil,
+		{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+			{Type: schema.ChatMessagePartTypeText, Text: "describe"},
+			{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{MessagePartCommon: schema.MessagePartCommon{URL: ptr("https://example.com/a.png")}}},
+		}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		msg   *schema.Message
+		field string
+	}{
+		{
+			name: "too many parts",
+			msg: &schema.Message{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "a"},
+				{Type: schema.ChatMessagePartTypeText, Text: "b"},
+				{Type: schema.ChatMessagePartTypeText, Text: "c"},
+			}},
+			field: "messages[1].user_input_multi_content",
+		},
+		{
+			name: "oversized inline data",
+			msg: &schema.Message{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "a"},
+				{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{MessagePartCommon: schema.MessagePartCommon{Base64Data: ptr("aGVsbG8gd29ybGQ="), MIMEType: "image/png"}}},
+			}},
+			field: "messages[1].user_input_multi_content[1].image",
+		},
+		{
+			name: "disallowed file",
+			msg: &schema.Message{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeFileURL, File: &schema.MessageInputFile{MessagePartCommon: schema.MessagePartCommon{URL: ptr("https://example.com/a.pdf"), MIMEType: "application/pdf"}}},
+			}},
+			field: "messages[1].user_input_multi_content[0].file",
+		},
+		{
+			name: "disallowed MIME type",
+			msg: &schema.Message{Role: schema.Assistant, AssistantGenMultiContent: []schema.MessageOutputPart{
+				{Type: schema.ChatMessagePartTypeAudioURL, Audio: &schema.MessageOutputAudio{MessagePartCommon: schema.MessagePartCommon{URL: ptr("https://example.com/a.mp3"), MIMEType: "audio/mpeg"}}},
+			}},
+			field: "messages[1].assistant_gen_multi_content[0].audio",
+		},
+		{
+			name: "malformed data URL",
+			msg: &schema.Message{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+				{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: "data:image/png;base64"}},
+			}},
+			field: "messages[1].multi_content[0].image_url",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := limits.ValidateMessages([]*schema.Message{schema.SystemMessage("sys"), c.msg})
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("got %v, want a *ValidationError", err)
+			}
+			if ve.Field != c.field {
+				t.Fatalf("got field %q, want %q", ve.Field, c.field)
+			}
+		})
+	}
+}
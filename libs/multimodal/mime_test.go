@@ -0,0 +1,59 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multimodal
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+func pngChunk(typ string, payload []byte) []byte {
+	chunk := make([]byte, 4, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	chunk = append(chunk, typ...)
+	chunk = append(chunk, payload...)
+	return append(chunk, 0, 0, 0, 0)
+}
+
+func TestSniffMIMEType(t *testing.T) {
+	cases := map[string]string{
+		string(pngSignature): "image/png",
+		"hello":              "text/plain",
+		"":                   "text/plain",
+	}
+	for data, want := range cases {
+		if got := SniffMIMEType([]byte(data)); got != want {
+			t.Errorf("SniffMIMEType(%q) = %q, want %q", data, got, want)
+		}
+	}
+}
+
+func TestMatchMIMEType(t *testing.T) {
+	allowed := []string{"image/*", "application/pdf"}
+	for mimeType, want := range map[string]bool{
+		"image/png":       true,
+		"application/pdf": true,
+		"video/mp4":       false,
+		"imagex/png":      false,
+	} {
+		if got := matchMIMEType(mimeType, allowed); got != want {
+			t.Errorf("matchMIMEType(%q) = %v, want %v", mimeType, got, want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multimodal
+
+import (
+	"fmt"
+	"time"
+)
+
+// The keys of the video metadata in the Extra of a video part. The offsets are stored in seconds,
+// so that the Extra keeps plain values when the messages are serialized.
+const (
+	extraKeyVideoFPS         = "eino_ext_video_fps"
+	extraKeyVideoStartOffset = "eino_ext_video_start_offset"
+	extraKeyVideoEndOffset   = "eino_ext_video_end_offset"
+)
+
+// VideoMetadata tells the model how to sample a video input, independently of the provider.
+// Set it in the Extra of a video part with SetVideoMetadata, the model components map it to their own request fields,
+// e.g. the fps of ark or the VideoMetadata of gemini.
+type VideoMetadata struct {
+	// FPS is the number of frames sampled per second of video, 0 for the default of the provider.
+	FPS float64
+	// StartOffset is the start of the clip of the video sent to the model, 0 for the start of the video.
+	StartOffset time.Duration
+	// EndOffset is the end of the clip of the video sent to the model, 0 for the end of the video.
+	EndOffset time.Duration
+}
+
+// Clipped reports whether only a clip of the video is sent to the model.
+func (m *VideoMetadata) Clipped() bool {
+	return m != nil && (m.StartOffset > 0 || m.EndOffset > 0)
+}
+
+// Validate checks that the values are not negative and that the clip ends after it starts.
+func (m *VideoMetadata) Validate() error {
+	if m == nil {
+		return nil
+	}
+	if m.FPS < 0 {
+		return fmt.Errorf("invalid video metadata: fps must not be negative, got %v", m.FPS)
+	}
+	if m.StartOffset < 0 || m.EndOffset < 0 {
+		return fmt.Errorf("invalid video metadata: offsets must not be negative, got [%s, %s]", m.StartOffset, m.EndOffset)
+	}
+	if m.EndOffset > 0 && m.EndOffset <= m.StartOffset {
+		return fmt.Errorf("invalid video metadata: end offset %s must be after start offset %s", m.EndOffset, m.StartOffset)
+	}
+	return nil
+}
+
+// SetVideoMetadata stores the metadata in the Extra of a video part, e.g. schema.MessageInputVideo.Extra,
+// replacing the metadata set before. It returns the Extra, allocated if nil.
+func SetVideoMetadata(extra map[string]any, meta *VideoMetadata) map[string]any {
+	if extra == nil {
+		extra = make(map[string]any)
+	}
+	delete(extra, extraKeyVideoFPS)
+	delete(extra, extraKeyVideoStartOffset)
+	delete(extra, extraKeyVideoEndOffset)
+	if meta == nil {
+		return extra
+	}
+	if meta.FPS != 0 {
+		extra[extraKeyVideoFPS] = meta.FPS
+	}
+	if meta.StartOffset != 0 {
+		extra[extraKeyVideoStartOffset] = meta.StartOffset.Seconds()
+	}
+	if meta.EndOffset != 0 {
+		extra[extraKeyVideoEndOffset] = meta.EndOffset.Seconds()
+	}
+	return extra
+}
+
+// GetVideoMetadata returns the metadata stored in the Extra of a video part by SetVideoMetadata, nil if none.
+func GetVideoMetadata(extra map[string]any) *VideoMetadata {
+	fps, hasFPS := extra[extraKeyVideoFPS].(float64)
+	start, hasStart := extra[extraKeyVideoStartOffset].(float64)
+	end, hasEnd := extra[extraKeyVideoEndOffset].(float64)
+	if !hasFPS && !hasStart && !hasEnd {
+		return nil
+	}
+	return &VideoMetadata{
+		FPS:         fps,
+		StartOffset: secondsToDuration(start),
+		EndOffset:   secondsToDuration(end),
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
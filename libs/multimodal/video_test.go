@@ -0,0 +1,70 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package multimodal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVideoMetadata(t *testing.T) {
+	meta := &VideoMetadata{FPS: 2, StartOffset: 1500 * time.Millisecond, EndOffset: time.Minute}
+	extra := SetVideoMetadata(nil, meta)
+	if !reflect.DeepEqual(extra, map[string]any{extraKeyVideoFPS: 2.0, extraKeyVideoStartOffset: 1.5, extraKeyVideoEndOffset: 60.0}) {
+		t.Fatalf("unexpected extra: %v", extra)
+	}
+	if got := GetVideoMetadata(extra); !reflect.DeepEqual(got, meta) {
+		t.Fatalf("expected %+v, got %+v", meta, got)
+	}
+	if !meta.Clipped() {
+		t.Fatalf("expected clipped video")
+	}
+
+	// the metadata set before is replaced, the other keys are kept
+	extra["other"] = "kept"
+	extra = SetVideoMetadata(extra, &VideoMetadata{FPS: 1})
+	if got := GetVideoMetadata(extra); !reflect.DeepEqual(got, &VideoMetadata{FPS: 1}) || got.Clipped() {
+		t.Fatalf("unexpected metadata: %+v", got)
+	}
+	if extra["other"] != "kept" {
+		t.Fatalf("unexpected extra: %v", extra)
+	}
+	if got := GetVideoMetadata(SetVideoMetadata(extra, nil)); got != nil {
+		t.Fatalf("expected no metadata, got %+v", got)
+	}
+	if got := GetVideoMetadata(nil); got != nil {
+		t.Fatalf("expected no metadata, got %+v", got)
+	}
+
+	for _, invalid := range []*VideoMetadata{
+		{FPS: -1},
+		{StartOffset: -time.Second},
+		{StartOffset: time.Minute, EndOffset: time.Second},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Fatalf("expected %+v to be invalid", invalid)
+		}
+	}
+	var nilMeta *VideoMetadata
+	if err := nilMeta.Validate(); err != nil || nilMeta.Clipped() {
+		t.Fatalf("nil metadata must be valid and not clipped")
+	}
+	if err := (&VideoMetadata{StartOffset: time.Second}).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
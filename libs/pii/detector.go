@@ -0,0 +1,176 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package pii provides the redaction of the personally identifiable information (PII) sent to the model components:
+// the detectors find the PII in the text of the input messages, and the Redactor replaces it with placeholders
+// before the request is sent, restoring the original values in the response.
+package pii
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Built-in PII types.
+const (
+	TypeEmail      = "EMAIL"
+	TypePhone      = "PHONE"
+	TypeIDCard     = "ID_CARD"
+	TypeCreditCard = "CREDIT_CARD"
+	TypeIPv4       = "IPV4"
+)
+
+// Match is a PII found in a text, at the byte offsets [Start, End).
+type Match struct {
+	Start int
+	End   int
+	// Type is the type of the PII, e.g. TypeEmail, used in the placeholder replacing it.
+	// It is upper-cased in the placeholder, and the characters other than letters, digits and underscores
+	// are replaced with underscores, e.g. "home-address" becomes "HOME_ADDRESS".
+	Type string
+}
+
+// Detector finds the PII in a text.
+type Detector interface {
+	Detect(ctx context.Context, text string) ([]Match, error)
+}
+
+// DetectorFunc adapts a function to a Detector, e.g. to plug a named entity recognition (NER) model
+// detecting the names and addresses that regular expressions can not.
+type DetectorFunc func(ctx context.Context, text string) ([]Match, error)
+
+func (f DetectorFunc) Detect(ctx context.Context, text string) ([]Match, error) {
+	return f(ctx, text)
+}
+
+// RegexDetector detects the PII matching a regular expression.
+type RegexDetector struct {
+	// Type is the type of the detected PII.
+	Type string
+	// Pattern is the regular expression matching the PII.
+	Pattern *regexp.Regexp
+	// Validate, if set, filters the matches, e.g. with a checksum.
+	// Optional.
+	Validate func(s string) bool
+}
+
+// NewRegexDetector creates a RegexDetector of the PII of typ matching pattern.
+// It panics if pattern does not compile, like regexp.MustCompile.
+func NewRegexDetector(typ, pattern string) *RegexDetector {
+	return &RegexDetector{Type: typ, Pattern: regexp.MustCompile(pattern)}
+}
+
+func (d *RegexDetector) Detect(_ context.Context, text string) ([]Match, error) {
+	var matches []Match
+	for _, loc := range d.Pattern.FindAllStringIndex(text, -1) {
+		if d.Validate != nil && !d.Validate(text[loc[0]:loc[1]]) {
+			continue
+		}
+		matches = append(matches, Match{Start: loc[0], End: loc[1], Type: d.Type})
+	}
+	return matches, nil
+}
+
+var (
+	// EmailDetector detects email addresses.
+	EmailDetector = NewRegexDetector(TypeEmail, `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// PhoneDetector detects the mainland China mobile numbers, with an optional +86 prefix,
+	// and the international numbers in the E.164 format, e.g. +14155552671.
+	PhoneDetector = NewRegexDetector(TypePhone, `(?:\+86[- ]?1[3-9]\d{9}|\b1[3-9]\d{9}|\+[1-9]\d{7,14})\b`)
+	// IDCardDetector detects the 18-digit resident identity card numbers of mainland China.
+	IDCardDetector = &RegexDetector{
+		Type:     TypeIDCard,
+		Pattern:  regexp.MustCompile(`\b[1-9]\d{5}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]\b`),
+		Validate: validIDCard,
+	}
+	// CreditCardDetector detects the payment card numbers of 13 to 19 digits starting with 2 to 6, e.g. Visa,
+	// Mastercard, American Express and UnionPay, optionally grouped by spaces or dashes, passing the Luhn checksum.
+	CreditCardDetector = &RegexDetector{
+		Type:     TypeCreditCard,
+		Pattern:  regexp.MustCompile(`\b[2-6](?:[ -]?\d){12,18}\b`),
+		Validate: validLuhn,
+	}
+	// IPv4Detector detects IPv4 addresses.
+	IPv4Detector = NewRegexDetector(TypeIPv4, `\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`)
+)
+
+// DefaultDetectors returns the built-in detectors, used when Config.Detectors is empty.
+// The ID card and credit card detectors come first, so their numbers are not taken for phone numbers.
+func DefaultDetectors() []Detector {
+	return []Detector{IDCardDetector, CreditCardDetector, EmailDetector, PhoneDetector, IPv4Detector}
+}
+
+// detect runs the detectors on text, returning the matches ordered by offset.
+// On overlaps, the match of the earlier detector wins.
+func detect(ctx context.Context, detectors []Detector, text string) ([]Match, error) {
+	var found []Match
+	for _, d := range detectors {
+		matches, err := d.Detect(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if m.Start < 0 || m.End > len(text) || m.Start >= m.End || overlaps(found, m) {
+				continue
+			}
+			found = append(found, m)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Start < found[j].Start })
+	return found, nil
+}
+
+func overlaps(matches []Match, m Match) bool {
+	for _, o := range matches {
+		if m.Start < o.End && o.Start < m.End {
+			return true
+		}
+	}
+	return false
+}
+
+var idCardWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+
+// validIDCard checks the check digit of a resident identity card number, see GB 11643-1999.
+func validIDCard(s string) bool {
+	sum := 0
+	for i, w := range idCardWeights {
+		sum += int(s[i]-'0') * w
+	}
+	return "10X98765432"[sum%11] == strings.ToUpper(s[17:])[0]
+}
+
+// validLuhn checks the Luhn checksum of a card number.
+func validLuhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
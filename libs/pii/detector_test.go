@@ -0,0 +1,85 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pii
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultDetectors(t *testing.T) {
+	ctx := context.Background()
+	cases := map[string]string{
+		"mail alice.w@example.com please": TypeEmail,
+		"call 13812345678 now":            TypePhone,
+		"call +86 13812345678 now":        TypePhone,
+		"call +14155552671 now":           TypePhone,
+		"id 11010519491231002X ok":        TypeIDCard,
+		"card 4111 1111 1111 1111 ok":     TypeCreditCard,
+		"card 4111-1111-1111-1111 ok":     TypeCreditCard,
+		"host 192.168.0.1 down":           TypeIPv4,
+	}
+	for text, typ := range cases {
+		matches, err := detect(ctx, DefaultDetectors(), text)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(matches) != 1 || matches[0].Type != typ {
+			t.Fatalf("expected one %s in %q, got %+v", typ, text, matches)
+		}
+	}
+
+	negatives := []string{
+		"id 110105194912310021 has a wrong check digit",
+		"card 4111 1111 1111 1112 fails the checksum",
+		"version 1.2.3 and 12345",
+		"999.1.1.1",
+	}
+	for _, text := range negatives {
+		matches, err := detect(ctx, DefaultDetectors(), text)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(matches) != 0 {
+			t.Fatalf("expected no PII in %q, got %+v", text, matches)
+		}
+	}
+}
+
+func TestDetectOverlaps(t *testing.T) {
+	ctx := context.Background()
+	name := DetectorFunc(func(ctx context.Context, text string) ([]Match, error) {
+		return []Match{{Start: 0, End: 5, Type: "NAME"}, {Start: 8, End: 20, Type: "NAME"}, {Start: 3, End: 100, Type: "NAME"}}, nil
+	})
+	matches, err := detect(ctx, []Detector{EmailDetector, name}, "Alice, a@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Match{{Start: 0, End: 5, Type: "NAME"}, {Start: 7, End: 20, Type: TypeEmail}}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, matches)
+	}
+
+	failing := DetectorFunc(func(ctx context.Context, text string) ([]Match, error) {
+		return nil, errors.New("ner unavailable")
+	})
+	if _, err = detect(ctx, []Detector{failing}, "text"); err == nil {
+		t.Fatalf("expected error")
+	}
+}
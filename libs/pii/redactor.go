@@ -0,0 +1,501 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pii
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Mode is how the Redactor replaces the PII.
+type Mode string
+
+const (
+	// ModeTokenize replaces each PII value with a placeholder keyed by the HMAC of the value, e.g. "<EMAIL_3f2a9c1b>",
+	// the same value getting the same placeholder in all the requests of the Redactor, so the placeholders of the
+	// earlier turns of a conversation keep referring to the same values.
+	// The placeholders generated by the model are restored to the original values in the response.
+	ModeTokenize Mode = "tokenize"
+	// ModeRedact replaces the PII with its type, e.g. "[EMAIL]", irreversibly.
+	ModeRedact Mode = "redact"
+)
+
+// Interceptor processes the input messages of a chat model before they are sent,
+// and the output messages before they are returned, e.g. the Redactor.
+// Set it as the Interceptor of the ark, deepseek, gemini and qianfan models.
+type Interceptor interface {
+	// Intercept returns the messages to send instead of input, and the Restorer of the output messages.
+	Intercept(ctx context.Context, input []*schema.Message) ([]*schema.Message, Restorer, error)
+}
+
+// Restorer processes the output messages of a request intercepted by an Interceptor.
+type Restorer interface {
+	// Restore returns the message to return instead of msg.
+	Restore(msg *schema.Message) *schema.Message
+	// RestoreStream returns the stream to return instead of sr.
+	RestoreStream(sr *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message]
+}
+
+// Apply applies interceptor to the input messages of a chat model, see Interceptor.
+// If interceptor is nil, it returns input as is, with a Restorer returning the outputs as is.
+func Apply(ctx context.Context, interceptor Interceptor, input []*schema.Message) ([]*schema.Message, Restorer, error) {
+	if interceptor == nil {
+		return input, nopRestorer{}, nil
+	}
+	output, restorer, err := interceptor.Intercept(ctx, input)
+	if err != nil {
+		return nil, nil, fmt.Errorf("intercept input messages failed: %w", err)
+	}
+	if restorer == nil {
+		restorer = nopRestorer{}
+	}
+	return output, restorer, nil
+}
+
+type nopRestorer struct{}
+
+func (nopRestorer) Restore(msg *schema.Message) *schema.Message {
+	return msg
+}
+
+func (nopRestorer) RestoreStream(sr *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	return sr
+}
+
+// Config contains the configuration of the Redactor.
+type Config struct {
+	// Detectors find the PII in the text of the messages, the match of the earlier detector winning on overlaps.
+	// Optional. Default is DefaultDetectors().
+	Detectors []Detector
+	// Mode is how the PII is replaced.
+	// Optional. Default is ModeTokenize.
+	Mode Mode
+	// Key is the HMAC key of the placeholders of ModeTokenize. Set the same key on all the replicas
+	// to get the same placeholders across them, and keep it secret, as the placeholders of low-entropy values,
+	// e.g. the phone numbers, could otherwise be brute-forced.
+	// Optional. Default is a random key generated by NewRedactor.
+	Key []byte
+}
+
+// Redactor is an Interceptor replacing the PII of the text content, the text parts, the reasoning content and the tool call arguments
+// of the input messages with placeholders, and restoring the placeholders of the output messages in ModeTokenize,
+// including the string values of their Extra, e.g. the reasoning content kept there by the ark and deepseek models.
+// The callbacks of the model only see the placeholders.
+type Redactor struct {
+	detectors []Detector
+	mode      Mode
+	key       []byte
+}
+
+// NewRedactor creates a Redactor.
+func NewRedactor(config *Config) (*Redactor, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	r := &Redactor{detectors: config.Detectors, mode: config.Mode, key: config.Key}
+	if len(r.detectors) == 0 {
+		r.detectors = DefaultDetectors()
+	}
+	if len(r.key) == 0 {
+		r.key = make([]byte, 32)
+		if _, err := rand.Read(r.key); err != nil {
+			return nil, fmt.Errorf("generate placeholder key failed: %w", err)
+		}
+	}
+	switch r.mode {
+	case "":
+		r.mode = ModeTokenize
+	case ModeTokenize, ModeRedact:
+	default:
+		return nil, fmt.Errorf("unknown redaction mode: %s", r.mode)
+	}
+	return r, nil
+}
+
+// Intercept redacts the PII of the input messages, which are not modified.
+func (r *Redactor) Intercept(ctx context.Context, input []*schema.Message) ([]*schema.Message, Restorer, error) {
+	s := newSession(r)
+	output := make([]*schema.Message, len(input))
+	for i, msg := range input {
+		redacted, err := s.redactMessage(ctx, msg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("redact messages[%d] failed: %w", i, err)
+		}
+		output[i] = redacted
+	}
+	return output, s, nil
+}
+
+// placeholderHashLen is the number of the hex digits of the HMAC in a placeholder,
+// doubled for the rare values whose placeholder collides with another value of the request.
+const placeholderHashLen = 8
+
+var (
+	placeholderRegexp        = regexp.MustCompile(`<[A-Z0-9_]+_[0-9a-f]{8}(?:[0-9a-f]{8})?>`)
+	partialPlaceholderRegexp = regexp.MustCompile(`^<[A-Z0-9_]*[0-9a-f]*$`)
+)
+
+// token returns the placeholder of the value of typ, with n hex digits of its HMAC.
+func (r *Redactor) token(typ, value string, n int) string {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(typ + "\x00" + value))
+	return "<" + typ + "_" + hex.EncodeToString(mac.Sum(nil))[:n] + ">"
+}
+
+// session maps the placeholders of a request to the original values.
+type session struct {
+	*Redactor
+	placeholders map[string]string // type and value to placeholder
+	originals    map[string]string // placeholder to value
+	maxLen       int               // max length of the placeholders
+}
+
+func newSession(r *Redactor) *session {
+	return &session{
+		Redactor:     r,
+		placeholders: make(map[string]string),
+		originals:    make(map[string]string),
+	}
+}
+
+func (s *session) redactMessage(ctx context.Context, msg *schema.Message) (*schema.Message, error) {
+	if msg == nil {
+		return nil, nil
+	}
+	nm := *msg
+	var err error
+	if nm.Content, err = s.redact(ctx, msg.Content); err != nil {
+		return nil, err
+	}
+	if nm.ReasoningContent, err = s.redact(ctx, msg.ReasoningContent); err != nil {
+		return nil, err
+	}
+	if len(msg.MultiContent) > 0 {
+		nm.MultiContent = make([]schema.ChatMessagePart, len(msg.MultiContent))
+		for i, part := range msg.MultiContent {
+			if part.Text, err = s.redact(ctx, part.Text); err != nil {
+				return nil, err
+			}
+			nm.MultiContent[i] = part
+		}
+	}
+	if len(msg.UserInputMultiContent) > 0 {
+		nm.UserInputMultiContent = make([]schema.MessageInputPart, len(msg.UserInputMultiContent))
+		for i, part := range msg.UserInputMultiContent {
+			if part.Text, err = s.redact(ctx, part.Text); err != nil {
+				return nil, err
+			}
+			nm.UserInputMultiContent[i] = part
+		}
+	}
+	if len(msg.AssistantGenMultiContent) > 0 {
+		nm.AssistantGenMultiContent = make([]schema.MessageOutputPart, len(msg.AssistantGenMultiContent))
+		for i, part := range msg.AssistantGenMultiContent {
+			if part.Text, err = s.redact(ctx, part.Text); err != nil {
+				return nil, err
+			}
+			nm.AssistantGenMultiContent[i] = part
+		}
+	}
+	if len(msg.ToolCalls) > 0 {
+		nm.ToolCalls = make([]schema.ToolCall, len(msg.ToolCalls))
+		for i, tc := range msg.ToolCalls {
+			if tc.Function.Arguments, err = s.redact(ctx, tc.Function.Arguments); err != nil {
+				return nil, err
+			}
+			nm.ToolCalls[i] = tc
+		}
+	}
+	return &nm, nil
+}
+
+func (s *session) redact(ctx context.Context, text string) (string, error) {
+	if text == "" {
+		return text, nil
+	}
+	matches, err := detect(ctx, s.detectors, text)
+	if err != nil || len(matches) == 0 {
+		return text, err
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		sb.WriteString(text[last:m.Start])
+		sb.WriteString(s.placeholder(m.Type, text[m.Start:m.End]))
+		last = m.End
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), nil
+}
+
+// placeholderType returns typ upper-cased, with the characters other than letters, digits and underscores
+// replaced with underscores, so that the placeholders of any type are recognized by placeholderRegexp.
+func placeholderType(typ string) string {
+	if typ == "" {
+		return "PII"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, typ)
+}
+
+func (s *session) placeholder(typ, value string) string {
+	typ = placeholderType(typ)
+	if s.mode == ModeRedact {
+		return "[" + typ + "]"
+	}
+	key := typ + "\x00" + value
+	if p, ok := s.placeholders[key]; ok {
+		return p
+	}
+	p := s.token(typ, value, placeholderHashLen)
+	if v, ok := s.originals[p]; ok && v != value {
+		p = s.token(typ, value, 2*placeholderHashLen)
+	}
+	s.placeholders[key] = p
+	s.originals[p] = value
+	if len(p) > s.maxLen {
+		s.maxLen = len(p)
+	}
+	return p
+}
+
+// restore replaces the placeholders of text with the original values,
+// escaped as in a JSON string if inJSON, e.g. in the tool call arguments.
+func (s *session) restore(text string, inJSON bool) string {
+	if len(s.originals) == 0 || !strings.Contains(text, "<") {
+		return text
+	}
+	return placeholderRegexp.ReplaceAllStringFunc(text, func(p string) string {
+		value, ok := s.originals[p]
+		if !ok {
+			return p
+		}
+		if inJSON {
+			b, _ := json.Marshal(value)
+			return string(b[1 : len(b)-1])
+		}
+		return value
+	})
+}
+
+// restoreExtra returns a copy of extra with the placeholders of the string values restored, extra if none.
+func (s *session) restoreExtra(extra map[string]any) map[string]any {
+	var restored map[string]any
+	for k, v := range extra {
+		str, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if r := s.restore(str, false); r != str {
+			if restored == nil {
+				restored = make(map[string]any, len(extra))
+				for k, v := range extra {
+					restored[k] = v
+				}
+			}
+			restored[k] = r
+		}
+	}
+	if restored == nil {
+		return extra
+	}
+	return restored
+}
+
+// Restore restores the placeholders of the text content, the text parts, the reasoning content,
+// the tool call arguments and the string values of the Extra of msg.
+func (s *session) Restore(msg *schema.Message) *schema.Message {
+	if msg == nil || len(s.originals) == 0 {
+		return msg
+	}
+	nm := *msg
+	nm.Content = s.restore(msg.Content, false)
+	nm.ReasoningContent = s.restore(msg.ReasoningContent, false)
+	nm.Extra = s.restoreExtra(msg.Extra)
+	if len(msg.MultiContent) > 0 {
+		nm.MultiContent = make([]schema.ChatMessagePart, len(msg.MultiContent))
+		for i, part := range msg.MultiContent {
+			part.Text = s.restore(part.Text, false)
+			nm.MultiContent[i] = part
+		}
+	}
+	if len(msg.AssistantGenMultiContent) > 0 {
+		nm.AssistantGenMultiContent = make([]schema.MessageOutputPart, len(msg.AssistantGenMultiContent))
+		for i, part := range msg.AssistantGenMultiContent {
+			part.Text = s.restore(part.Text, false)
+			nm.AssistantGenMultiContent[i] = part
+		}
+	}
+	if len(msg.ToolCalls) > 0 {
+		nm.ToolCalls = make([]schema.ToolCall, len(msg.ToolCalls))
+		for i, tc := range msg.ToolCalls {
+			tc.Function.Arguments = s.restore(tc.Function.Arguments, true)
+			nm.ToolCalls[i] = tc
+		}
+	}
+	return &nm
+}
+
+// RestoreStream restores the placeholders of the chunks of sr. A placeholder may be split across chunks,
+// so the text which may start a placeholder is held back until the next chunk, or sent in a last chunk.
+func (s *session) RestoreStream(sr *schema.StreamReader[*schema.Message]) *schema.StreamReader[*schema.Message] {
+	if len(s.originals) == 0 {
+		return sr
+	}
+
+	out, sw := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			sr.Close()
+			sw.Close()
+		}()
+
+		sb := &streamBuffer{toolCallArgs: make(map[int]string), extra: make(map[string]string)}
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				if last := s.flush(sb); last != nil {
+					sw.Send(last, nil)
+				}
+				return
+			}
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if closed := sw.Send(s.restoreChunk(sb, chunk), nil); closed {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// streamBuffer holds the text of a stream which may start a placeholder.
+type streamBuffer struct {
+	content          string
+	reasoningContent string
+	toolCallArgs     map[int]string
+	extra            map[string]string
+}
+
+func (s *session) restoreChunk(sb *streamBuffer, chunk *schema.Message) *schema.Message {
+	if chunk == nil {
+		return nil
+	}
+	nm := *chunk
+	nm.Content = s.restorePartial(&sb.content, chunk.Content, false)
+	nm.ReasoningContent = s.restorePartial(&sb.reasoningContent, chunk.ReasoningContent, false)
+	if len(chunk.Extra) > 0 {
+		nm.Extra = make(map[string]any, len(chunk.Extra))
+		for k, v := range chunk.Extra {
+			if str, ok := v.(string); ok {
+				pending := sb.extra[k]
+				v = s.restorePartial(&pending, str, false)
+				sb.extra[k] = pending
+			}
+			nm.Extra[k] = v
+		}
+	}
+	if len(chunk.ToolCalls) > 0 {
+		nm.ToolCalls = make([]schema.ToolCall, len(chunk.ToolCalls))
+		for i, tc := range chunk.ToolCalls {
+			index := i
+			if tc.Index != nil {
+				index = *tc.Index
+			}
+			pending := sb.toolCallArgs[index]
+			tc.Function.Arguments = s.restorePartial(&pending, tc.Function.Arguments, true)
+			sb.toolCallArgs[index] = pending
+			nm.ToolCalls[i] = tc
+		}
+	}
+	return &nm
+}
+
+// restorePartial restores the placeholders of the pending text followed by delta,
+// holding back the end of the text which may start a placeholder in pending.
+func (s *session) restorePartial(pending *string, delta string, inJSON bool) string {
+	if *pending == "" && delta == "" {
+		return ""
+	}
+	text := *pending + delta
+	cut := len(text)
+	if i := strings.LastIndexByte(text, '<'); i >= 0 && len(text)-i < s.maxLen && partialPlaceholderRegexp.MatchString(text[i:]) {
+		cut = i
+	}
+	*pending = text[cut:]
+	return s.restore(text[:cut], inJSON)
+}
+
+// flush returns the last chunk with the text held back, nil if none.
+func (s *session) flush(sb *streamBuffer) *schema.Message {
+	var indexes []int
+	for index, args := range sb.toolCallArgs {
+		if args != "" {
+			indexes = append(indexes, index)
+		}
+	}
+	extra := make(map[string]any)
+	for k, v := range sb.extra {
+		if v != "" {
+			extra[k] = v
+		}
+	}
+	if sb.content == "" && sb.reasoningContent == "" && len(indexes) == 0 && len(extra) == 0 {
+		return nil
+	}
+
+	msg := &schema.Message{
+		Role:             schema.Assistant,
+		Content:          sb.content,
+		ReasoningContent: sb.reasoningContent,
+	}
+	if len(extra) > 0 {
+		msg.Extra = extra
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		index := index
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			Index:    &index,
+			Function: schema.FunctionCall{Arguments: sb.toolCallArgs[index]},
+		})
+	}
+	return msg
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pii
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestRedactor(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRedactor(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	emailA, emailB := r.token(TypeEmail, "a@example.com", 8), r.token(TypeEmail, "b@example.com", 8)
+	phone := r.token(TypePhone, "13812345678", 8)
+
+	input := []*schema.Message{
+		schema.SystemMessage("You are a helpful assistant."),
+		schema.UserMessage("Mail a@example.com and b@example.com, then a@example.com again."),
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "send", Arguments: `{"to":"a@example.com"}`}}}),
+		{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{{Type: schema.ChatMessagePartTypeText, Text: "my phone is 13812345678"}}},
+		{Role: schema.User, MultiContent: []schema.ChatMessagePart{{Type: schema.ChatMessagePartTypeText, Text: "or b@example.com"}}},
+	}
+	output, restorer, err := r.Intercept(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if output[1].Content != "Mail "+emailA+" and "+emailB+", then "+emailA+" again." {
+		t.Fatalf("unexpected content: %s", output[1].Content)
+	}
+	if output[2].ToolCalls[0].Function.Arguments != `{"to":"`+emailA+`"}` {
+		t.Fatalf("unexpected arguments: %s", output[2].ToolCalls[0].Function.Arguments)
+	}
+	if output[3].UserInputMultiContent[0].Text != "my phone is "+phone {
+		t.Fatalf("unexpected text: %s", output[3].UserInputMultiContent[0].Text)
+	}
+	if output[4].MultiContent[0].Text != "or "+emailB {
+		t.Fatalf("unexpected text: %s", output[4].MultiContent[0].Text)
+	}
+	if input[1].Content != "Mail a@example.com and b@example.com, then a@example.com again." ||
+		input[3].UserInputMultiContent[0].Text != "my phone is 13812345678" ||
+		input[4].MultiContent[0].Text != "or b@example.com" {
+		t.Fatalf("input messages must not be modified")
+	}
+
+	reply := schema.AssistantMessage("Sent to "+emailB+", not <EMAIL_0000abcd>.",
+		[]schema.ToolCall{{Function: schema.FunctionCall{Arguments: `{"to":"` + emailA + `"}`}}})
+	reply.ReasoningContent = "the user asked to mail " + emailB
+	reply.Extra = map[string]any{"reasoning_content": "mail " + emailA, "tokens": 3}
+	msg := restorer.Restore(reply)
+	if msg.Content != "Sent to b@example.com, not <EMAIL_0000abcd>." {
+		t.Fatalf("unexpected restored content: %s", msg.Content)
+	}
+	if msg.ToolCalls[0].Function.Arguments != `{"to":"a@example.com"}` {
+		t.Fatalf("unexpected restored arguments: %s", msg.ToolCalls[0].Function.Arguments)
+	}
+	if msg.ReasoningContent != "the user asked to mail b@example.com" {
+		t.Fatalf("unexpected restored reasoning content: %s", msg.ReasoningContent)
+	}
+	if msg.Extra["reasoning_content"] != "mail a@example.com" || msg.Extra["tokens"] != 3 {
+		t.Fatalf("unexpected restored extra: %v", msg.Extra)
+	}
+	if reply.Extra["reasoning_content"] != "mail "+emailA {
+		t.Fatalf("output messages must not be modified")
+	}
+
+	t.Run("stable placeholders", func(t *testing.T) {
+		output, _, err := r.Intercept(ctx, []*schema.Message{schema.UserMessage("b@example.com")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output[0].Content != emailB {
+			t.Fatalf("expected the placeholder of the earlier request, got %s", output[0].Content)
+		}
+
+		keyed, _ := NewRedactor(&Config{Key: []byte("secret")})
+		replica, _ := NewRedactor(&Config{Key: []byte("secret")})
+		if p := keyed.token(TypeEmail, "b@example.com", 8); p == emailB || p != replica.token(TypeEmail, "b@example.com", 8) {
+			t.Fatalf("expected the placeholders to depend on the key only, got %s", p)
+		}
+	})
+
+	t.Run("collision", func(t *testing.T) {
+		s := newSession(r)
+		s.originals[r.token(TypeEmail, "c@example.com", 8)] = "other@example.com"
+		if p := s.placeholder(TypeEmail, "c@example.com"); p != r.token(TypeEmail, "c@example.com", 16) {
+			t.Fatalf("expected a longer placeholder, got %s", p)
+		}
+		if msg := s.Restore(schema.AssistantMessage(r.token(TypeEmail, "c@example.com", 16), nil)); msg.Content != "c@example.com" {
+			t.Fatalf("unexpected restored content: %s", msg.Content)
+		}
+	})
+
+	t.Run("custom types", func(t *testing.T) {
+		r, _ := NewRedactor(&Config{Detectors: []Detector{NewRegexDetector("home-address", `\d+ Main St`)}})
+		output, restorer, err := r.Intercept(ctx, []*schema.Message{schema.UserMessage("I live at 42 Main St")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		p := r.token("HOME_ADDRESS", "42 Main St", 8)
+		if output[0].Content != "I live at "+p {
+			t.Fatalf("unexpected content: %s", output[0].Content)
+		}
+		if msg := restorer.Restore(schema.AssistantMessage("Shipping to "+p, nil)); msg.Content != "Shipping to 42 Main St" {
+			t.Fatalf("unexpected restored content: %s", msg.Content)
+		}
+		if typ := placeholderType("Name.2"); typ != "NAME_2" {
+			t.Fatalf("unexpected placeholder type: %s", typ)
+		}
+	})
+
+	t.Run("redact mode", func(t *testing.T) {
+		r, err := NewRedactor(&Config{Mode: ModeRedact, Detectors: []Detector{EmailDetector}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		output, restorer, err := r.Intercept(ctx, []*schema.Message{schema.UserMessage("to a@example.com, 13812345678")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output[0].Content != "to [EMAIL], 13812345678" {
+			t.Fatalf("unexpected content: %s", output[0].Content)
+		}
+		if msg := restorer.Restore(schema.AssistantMessage("[EMAIL]", nil)); msg.Content != "[EMAIL]" {
+			t.Fatalf("unexpected restored content: %s", msg.Content)
+		}
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		if _, err := NewRedactor(&Config{Mode: "hash"}); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("detector error", func(t *testing.T) {
+		r, _ := NewRedactor(&Config{Detectors: []Detector{DetectorFunc(func(ctx context.Context, text string) ([]Match, error) {
+			return nil, errors.New("ner unavailable")
+		})}})
+		if _, _, err := r.Intercept(ctx, []*schema.Message{schema.UserMessage("hi")}); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{schema.UserMessage("to a@example.com")}
+
+	output, restorer, err := Apply(ctx, nil, input)
+	if err != nil || output[0] != input[0] {
+		t.Fatalf("expected input as is, got %v, %v", output, err)
+	}
+	r, _ := NewRedactor(nil)
+	msg := schema.AssistantMessage(r.token(TypeEmail, "a@example.com", 8), nil)
+	if restorer.Restore(msg) != msg {
+		t.Fatalf("expected output as is")
+	}
+
+	output, restorer, err = Apply(ctx, r, input)
+	if err != nil || output[0].Content != "to "+msg.Content {
+		t.Fatalf("unexpected output: %v, %v", output, err)
+	}
+	if restorer.Restore(msg).Content != "a@example.com" {
+		t.Fatalf("unexpected restored content")
+	}
+}
+
+func TestRestoreStream(t *testing.T) {
+	ctx := context.Background()
+	r, _ := NewRedactor(&Config{Detectors: []Detector{DetectorFunc(func(ctx context.Context, text string) ([]Match, error) {
+		if text == "Bob" {
+			return []Match{{Start: 0, End: 3, Type: "NAME"}}, nil
+		}
+		return nil, nil
+	})}})
+	_, restorer, err := r.Intercept(ctx, []*schema.Message{schema.UserMessage(`Bob`)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := restorer.(*session)
+	// a value detected by a NER detector may need escaping in the tool call arguments
+	email := s.placeholder(TypeEmail, `"quoted"@example.com`)
+	name := r.token("NAME", "Bob", 8)
+
+	index := 0
+	chunks := []*schema.Message{
+		{Role: schema.Assistant, Content: "Hi " + name[:3]},
+		{Role: schema.Assistant, Content: name[3:] + ", a < b", Extra: map[string]any{"reasoning_content": "greet " + name[:7]}},
+		{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{Index: &index, ID: "1", Function: schema.FunctionCall{Name: "send", Arguments: `{"to":"` + email[:4]}}}},
+		{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{Index: &index, Function: schema.FunctionCall{Arguments: email[4:] + `"}`}}}},
+		{Role: schema.Assistant, Content: " <NAME_", Extra: map[string]any{"reasoning_content": name[7:]}},
+	}
+	out := restorer.RestoreStream(schema.StreamReaderFromArray(chunks))
+	defer out.Close()
+
+	var got []*schema.Message
+	for {
+		chunk, err := out.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, chunk)
+	}
+	msg, err := schema.ConcatMessages(got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.Content != "Hi Bob, a < b <NAME_" {
+		t.Fatalf("unexpected content: %q", msg.Content)
+	}
+	if msg.ToolCalls[0].Function.Arguments != `{"to":"\"quoted\"@example.com"}` {
+		t.Fatalf("unexpected arguments: %s", msg.ToolCalls[0].Function.Arguments)
+	}
+	if msg.Extra["reasoning_content"] != "greet Bob" {
+		t.Fatalf("unexpected extra: %v", msg.Extra)
+	}
+}
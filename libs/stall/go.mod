@@ -0,0 +1,3 @@
+module github.com/cloudwego/eino-ext/libs/stall
+
+go 1.18
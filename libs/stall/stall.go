@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package stall detects the streams of the model components stalling, i.e. not receiving the next event in time,
+// and cancels the upstream request so that the callers can fail over to another model.
+package stall
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Error is sent on the StreamReader when no event is received from the provider within the stall timeout.
+// The upstream request has been canceled by then, so callers can safely fail over to another model.
+type Error struct {
+	// Provider is the name of the provider of the stream, e.g. "ark".
+	Provider string
+	// Timeout is the stall window that was exceeded.
+	Timeout time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("stream stalled: no event received from %s within %s", e.Provider, e.Timeout)
+}
+
+// Watcher cancels the upstream request if it is armed for longer than the timeout.
+// It is armed while waiting for the next event and disarmed while the event is being handled,
+// so that a slow consumer is not mistaken for a stalled provider.
+// A nil *Watcher is valid and never fires.
+type Watcher struct {
+	provider string
+	timeout  time.Duration
+	timer    *time.Timer
+	stalled  int32
+}
+
+// NewWatcher derives a cancelable context from ctx for the request of the stream, and returns a watcher
+// which is already armed, and the function stopping the watcher and canceling the context.
+// If timeout is not positive, ctx is returned as is together with a nil watcher.
+func NewWatcher(ctx context.Context, provider string, timeout time.Duration) (context.Context, *Watcher, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, nil, func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watcher{provider: provider, timeout: timeout}
+	w.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&w.stalled, 1)
+		cancel()
+	})
+	return ctx, w, func() {
+		w.timer.Stop()
+		cancel()
+	}
+}
+
+// Arm starts waiting for the next event.
+func (w *Watcher) Arm() {
+	if w == nil {
+		return
+	}
+	w.timer.Reset(w.timeout)
+}
+
+// Disarm stops waiting, e.g. while the received event is being handled.
+func (w *Watcher) Disarm() {
+	if w == nil {
+		return
+	}
+	w.timer.Stop()
+}
+
+// WrapErr replaces err with an *Error if the watcher has fired,
+// since the error returned by the client is only the result of the cancellation.
+func (w *Watcher) WrapErr(err error) error {
+	if w == nil || atomic.LoadInt32(&w.stalled) == 0 {
+		return err
+	}
+	return &Error{Provider: w.provider, Timeout: w.timeout}
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stall
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		ctx := context.Background()
+		nctx, w, stop := NewWatcher(ctx, "test", 0)
+		defer stop()
+		if w != nil || nctx != ctx {
+			t.Fatalf("expected a nil watcher and the same context")
+		}
+		w.Arm()
+		w.Disarm()
+		err := errors.New("origin")
+		if got := w.WrapErr(err); got != err {
+			t.Errorf("WrapErr() = %v, expected %v", got, err)
+		}
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		ctx, w, stop := NewWatcher(context.Background(), "test", 10*time.Millisecond)
+		defer stop()
+
+		<-ctx.Done()
+		var stalledErr *Error
+		if !errors.As(w.WrapErr(ctx.Err()), &stalledErr) {
+			t.Fatalf("expected an *Error")
+		}
+		if stalledErr.Timeout != 10*time.Millisecond || stalledErr.Provider != "test" {
+			t.Errorf("unexpected error %+v", stalledErr)
+		}
+		if expected := "stream stalled: no event received from test within 10ms"; stalledErr.Error() != expected {
+			t.Errorf("Error() = %q, expected %q", stalledErr.Error(), expected)
+		}
+	})
+
+	t.Run("disarmed", func(t *testing.T) {
+		ctx, w, stop := NewWatcher(context.Background(), "test", 10*time.Millisecond)
+		defer stop()
+
+		w.Disarm()
+		time.Sleep(30 * time.Millisecond)
+		if ctx.Err() != nil {
+			t.Fatalf("expected the context not canceled")
+		}
+		err := errors.New("origin")
+		if got := w.WrapErr(err); got != err {
+			t.Errorf("WrapErr() = %v, expected %v", got, err)
+		}
+	})
+
+	t.Run("rearmed", func(t *testing.T) {
+		ctx, w, stop := NewWatcher(context.Background(), "test", 20*time.Millisecond)
+		defer stop()
+
+		for i := 0; i < 3; i++ {
+			time.Sleep(10 * time.Millisecond)
+			w.Arm()
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("expected the context not canceled while the events arrive in time")
+		}
+	})
+}
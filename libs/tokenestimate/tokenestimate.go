@@ -0,0 +1,110 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package tokenestimate estimates the input tokens of messages without the tokenizer of the model,
+// e.g. to decide when to compress the chat history or to fail an input too large for the context window.
+package tokenestimate
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Estimator estimates the number of input tokens of a message.
+// Use it to plug in the tokenizer of the target model for precise estimation.
+type Estimator func(msg *schema.Message) int
+
+const (
+	// ImageTokens, AudioTokens, VideoTokens and FileTokens are rough upper bounds of a single media input,
+	// the real cost depends on the resolution and duration.
+	ImageTokens = 1500
+	AudioTokens = 1500
+	VideoTokens = 10000
+	FileTokens  = 10000
+	// MessageOverheadTokens covers the role and format tokens of a message.
+	MessageOverheadTokens = 4
+)
+
+// Message is the default Estimator.
+// It is a heuristic: about one token per CJK character, one token per four bytes of other text,
+// and a fixed budget for every image, audio, video and file of the multi-content parts.
+func Message(msg *schema.Message) int {
+	if msg == nil {
+		return 0
+	}
+
+	n := MessageOverheadTokens + Text(msg.Content) + Text(msg.ReasoningContent)
+	for _, tc := range msg.ToolCalls {
+		n += Text(tc.Function.Name) + Text(tc.Function.Arguments)
+	}
+	for _, part := range msg.UserInputMultiContent {
+		n += partTokens(part.Type, part.Text)
+	}
+	for _, part := range msg.AssistantGenMultiContent {
+		n += partTokens(part.Type, part.Text)
+	}
+	for _, part := range msg.MultiContent {
+		n += partTokens(part.Type, part.Text)
+	}
+	return n
+}
+
+// Messages returns the total estimated tokens of msgs, using Message if estimator is nil.
+func Messages(msgs []*schema.Message, estimator Estimator) int {
+	if estimator == nil {
+		estimator = Message
+	}
+	n := 0
+	for _, msg := range msgs {
+		n += estimator(msg)
+	}
+	return n
+}
+
+// Text estimates the tokens of a text: one token per CJK character and one token per four bytes of other text.
+func Text(text string) int {
+	if text == "" {
+		return 0
+	}
+	cjk, otherBytes := 0, 0
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			cjk++
+		} else {
+			otherBytes += utf8.RuneLen(r)
+		}
+	}
+	return cjk + (otherBytes+3)/4
+}
+
+func partTokens(typ schema.ChatMessagePartType, text string) int {
+	switch typ {
+	case schema.ChatMessagePartTypeText:
+		return Text(text)
+	case schema.ChatMessagePartTypeImageURL:
+		return ImageTokens
+	case schema.ChatMessagePartTypeAudioURL:
+		return AudioTokens
+	case schema.ChatMessagePartTypeVideoURL:
+		return VideoTokens
+	case schema.ChatMessagePartTypeFileURL:
+		return FileTokens
+	default:
+		return 0
+	}
+}
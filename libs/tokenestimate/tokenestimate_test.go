@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tokenestimate
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestText(t *testing.T) {
+	for text, expected := range map[string]int{
+		"":         0,
+		"abcdefgh": 2,
+		"abc":      1,
+		"你好世界":     4,
+		"你好 ab":    3,
+	} {
+		if got := Text(text); got != expected {
+			t.Errorf("Text(%q) = %d, expected %d", text, got, expected)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	url := "https://example.com/a.png"
+	for name, c := range map[string]struct {
+		msg      *schema.Message
+		expected int
+	}{
+		"nil": {nil, 0},
+		"content": {
+			schema.UserMessage("hello"),
+			MessageOverheadTokens + 2,
+		},
+		"tool calls": {
+			schema.AssistantMessage("", []schema.ToolCall{{Function: schema.FunctionCall{Name: "get", Arguments: `{"a":1}`}}}),
+			MessageOverheadTokens + 1 + 2,
+		},
+		"user input multi content": {
+			&schema.Message{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "abcd"},
+				{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{
+					MessagePartCommon: schema.MessagePartCommon{URL: &url},
+				}},
+				{Type: schema.ChatMessagePartTypeVideoURL},
+			}},
+			MessageOverheadTokens + 1 + ImageTokens + VideoTokens,
+		},
+		"assistant gen multi content": {
+			&schema.Message{Role: schema.Assistant, AssistantGenMultiContent: []schema.MessageOutputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "abcd"},
+				{Type: schema.ChatMessagePartTypeAudioURL},
+			}},
+			MessageOverheadTokens + 1 + AudioTokens,
+		},
+		"multi content": {
+			&schema.Message{Role: schema.User, MultiContent: []schema.ChatMessagePart{
+				{Type: schema.ChatMessagePartTypeText, Text: "你好"},
+				{Type: schema.ChatMessagePartTypeFileURL},
+				{Type: schema.ChatMessagePartTypeImageURL},
+			}},
+			MessageOverheadTokens + 2 + FileTokens + ImageTokens,
+		},
+	} {
+		if got := Message(c.msg); got != c.expected {
+			t.Errorf("%s: Message() = %d, expected %d", name, got, c.expected)
+		}
+	}
+}
+
+func TestMessages(t *testing.T) {
+	msgs := []*schema.Message{schema.UserMessage("hello"), nil, schema.AssistantMessage("abcd", nil)}
+	if got := Messages(msgs, nil); got != 2*MessageOverheadTokens+2+1 {
+		t.Errorf("Messages() = %d", got)
+	}
+	if got := Messages(msgs, func(*schema.Message) int { return 1 }); got != 3 {
+		t.Errorf("Messages() with estimator = %d", got)
+	}
+}